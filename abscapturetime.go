@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	// AbsCaptureTimeURI is the URI of the abs-capture-time RTP header
+	// extension, which carries the NTP time a frame was captured at. Register
+	// it with MediaEngine.RegisterHeaderExtension to negotiate it.
+	AbsCaptureTimeURI = "http://www.webrtc.org/experiments/rtp-hdrext/abs-capture-time"
+
+	// AttributeAbsCaptureTime is the interceptor attribute holding the
+	// time.Time of the abs-capture-time extension of a RTP packet, it is set
+	// by TrackRemote.ReadRTP if the extension was negotiated and present.
+	AttributeAbsCaptureTime = "webrtc.absCaptureTime"
+)
+
+// absCaptureTimePayload returns the extension payload of captureTime.
+func absCaptureTimePayload(captureTime time.Time) ([]byte, error) {
+	return rtp.NewAbsCaptureTimeExtension(captureTime).Marshal()
+}
+
+// readAbsCaptureTime returns the capture time of the packet if it carries
+// the extension with id.
+func readAbsCaptureTime(header *rtp.Header, id uint8) (time.Time, bool) {
+	if id == 0 {
+		return time.Time{}, false
+	}
+
+	payload := header.GetExtension(id)
+	if payload == nil {
+		return time.Time{}, false
+	}
+
+	extension := rtp.AbsCaptureTimeExtension{}
+	if err := extension.Unmarshal(payload); err != nil {
+		return time.Time{}, false
+	}
+
+	return extension.CaptureTime(), true
+}
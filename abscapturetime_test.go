@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type headerTestWriter struct {
+	headers []rtp.Header
+}
+
+func (w *headerTestWriter) WriteRTP(header *rtp.Header, _ []byte) (int, error) {
+	w.headers = append(w.headers, header.Clone())
+
+	return 0, nil
+}
+
+func (w *headerTestWriter) Write([]byte) (int, error) {
+	return 0, nil
+}
+
+func TestAbsCaptureTime_WriteSample(t *testing.T) {
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8, ClockRate: 90000}, "video", "pion")
+	require.NoError(t, err)
+
+	bind := func(extensions []RTPHeaderExtensionParameter) *headerTestWriter {
+		writer := &headerTestWriter{}
+		_, bindErr := track.Bind(&baseTrackLocalContext{
+			params: RTPParameters{
+				HeaderExtensions: extensions,
+				Codecs:           []RTPCodecParameters{{RTPCodecCapability: track.Codec(), PayloadType: 96}},
+			},
+			ssrc:        1,
+			writeStream: writer,
+		})
+		require.NoError(t, bindErr)
+
+		return writer
+	}
+	withoutExtension := bind(nil)
+	withExtension := bind([]RTPHeaderExtensionParameter{{URI: AbsCaptureTimeURI, ID: 3}})
+	withOtherID := bind([]RTPHeaderExtensionParameter{{URI: AbsCaptureTimeURI, ID: 7}})
+
+	captureTime := time.Unix(1700000000, 0)
+	assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Second}))
+	assert.NoError(t, track.WriteSample(media.Sample{
+		Data: []byte{0x00}, Duration: time.Second, CaptureTime: captureTime,
+	}))
+
+	require.Len(t, withoutExtension.headers, 2)
+	assert.False(t, withoutExtension.headers[1].Extension)
+
+	for _, c := range []struct {
+		writer *headerTestWriter
+		id     uint8
+	}{{withExtension, 3}, {withOtherID, 7}} {
+		require.Len(t, c.writer.headers, 2)
+		_, ok := readAbsCaptureTime(&c.writer.headers[0], c.id)
+		assert.False(t, ok)
+
+		read, ok := readAbsCaptureTime(&c.writer.headers[1], c.id)
+		assert.True(t, ok)
+		assert.WithinDuration(t, captureTime, read, time.Millisecond)
+		assert.Len(t, c.writer.headers[1].Extensions, 1)
+	}
+}
@@ -7,8 +7,15 @@
 package webrtc
 
 import (
+	"github.com/pion/ice/v4"
 	"github.com/pion/interceptor"
 	"github.com/pion/logging"
+	"github.com/pion/randutil"
+)
+
+const (
+	multicastDNSHostNameLength = 32
+	multicastDNSHostNameRunes  = "abcdefghijklmnopqrstuvwxyz0123456789"
 )
 
 // API allows configuration of a PeerConnection
@@ -22,6 +29,14 @@ type API struct {
 	interceptorRegistry *interceptor.Registry
 
 	interceptor interceptor.Interceptor // Generated per PeerConnection
+
+	// Shared by all PeerConnections if SettingEngine.SetMulticastDNSHostNameReuse is enabled
+	multicastDNSHostName string
+
+	// Shared by all PeerConnections if SettingEngine.SetIncomingSSRCRoutines shares them
+	probeRoutines *probeRoutines
+
+	latencyMode LatencyMode // Configuration.LatencyMode of the PeerConnection
 }
 
 // NewAPI Creates a new API object for keeping semi-global settings to WebRTC objects
@@ -60,6 +75,21 @@ func NewAPI(options ...func(*API)) *API {
 		}
 	}
 
+	if routines := api.settingEngine.incomingSSRCRoutines; routines.shared {
+		api.probeRoutines = newProbeRoutines(routines.count)
+	}
+
+	candidates := api.settingEngine.candidates
+	if candidates.MulticastDNSHostNameReuse && candidates.MulticastDNSHostName == "" &&
+		candidates.MulticastDNSMode == ice.MulticastDNSModeQueryAndGather {
+		hostName, err := randutil.GenerateCryptoRandomString(multicastDNSHostNameLength, multicastDNSHostNameRunes)
+		if err != nil {
+			logger.Errorf("Failed to generate shared mDNS HostName %s", err)
+		} else {
+			api.multicastDNSHostName = hostName + ".local"
+		}
+	}
+
 	return api
 }
 
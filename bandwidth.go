@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/sdp/v3"
+)
+
+const (
+	sdpBandwidthTypeAS   = "AS"
+	sdpBandwidthTypeTIAS = "TIAS"
+)
+
+// SetReceiveBandwidth asks the remote peer to send at most bitrate bits per
+// second for the media of the transceiver. It is advertised with the b=TIAS
+// and b=AS lines of the media section of the next offer or answer, browsers
+// and SFUs honor them. Zero removes the limit.
+//
+// This is a Pion specific extension and is not part of the W3C API.
+func (t *RTPTransceiver) SetReceiveBandwidth(bitrate uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.receiveBandwidth = bitrate
+}
+
+// ReceiveBandwidth returns the bitrate set with SetReceiveBandwidth.
+func (t *RTPTransceiver) ReceiveBandwidth() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.receiveBandwidth
+}
+
+// RemoteBandwidth returns the bitrate in bits per second the remote peer
+// advertised for the media section of the transceiver in its last
+// description, with b=TIAS or b=AS, or 0 if it has no limit. The RTPSender
+// is paced to it if SettingEngine.EnableRemoteBandwidthEnforcement is set.
+//
+// This is a Pion specific extension and is not part of the W3C API.
+func (t *RTPTransceiver) RemoteBandwidth() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.remoteBandwidth
+}
+
+func (t *RTPTransceiver) setRemoteBandwidth(bitrate uint64) {
+	t.mu.Lock()
+	t.remoteBandwidth = bitrate
+	t.mu.Unlock()
+
+	if sender := t.Sender(); sender != nil {
+		sender.bandwidthLimit.setBitrate(int(bitrate)) //nolint:gosec // G115
+	}
+}
+
+// addBandwidth adds the b=AS and b=TIAS lines of bitrate to media, b=AS is in
+// kilobits per second and is kept for the peers that don't support TIAS.
+func addBandwidth(media *sdp.MediaDescription, bitrate uint64) {
+	if bitrate == 0 {
+		return
+	}
+
+	media.Bandwidth = append(media.Bandwidth,
+		sdp.Bandwidth{Type: sdpBandwidthTypeAS, Bandwidth: (bitrate + 999) / 1000},
+		sdp.Bandwidth{Type: sdpBandwidthTypeTIAS, Bandwidth: bitrate},
+	)
+}
+
+// getBandwidth returns the bitrate limit of a media section in bits per
+// second, from b=TIAS or else b=AS, or 0.
+func getBandwidth(media *sdp.MediaDescription) uint64 {
+	var bitrate uint64
+	for _, bandwidth := range media.Bandwidth {
+		if bandwidth.Experimental {
+			continue
+		}
+
+		switch bandwidth.Type {
+		case sdpBandwidthTypeTIAS:
+			return bandwidth.Bandwidth
+		case sdpBandwidthTypeAS:
+			bitrate = bandwidth.Bandwidth * 1000
+		}
+	}
+
+	return bitrate
+}
+
+// setRemoteBandwidths updates the remote bandwidth of the transceivers from
+// the media sections of a remote description.
+func setRemoteBandwidths(desc *sdp.SessionDescription, transceivers []*RTPTransceiver) {
+	for _, media := range desc.MediaDescriptions {
+		mid := getMidValue(media)
+		if mid == "" {
+			continue
+		}
+
+		for _, transceiver := range transceivers {
+			if transceiver.Mid() == mid {
+				transceiver.setRemoteBandwidth(getBandwidth(media))
+
+				break
+			}
+		}
+	}
+}
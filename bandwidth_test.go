@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBandwidth(t *testing.T) {
+	media := &sdp.MediaDescription{}
+	assert.Equal(t, uint64(0), getBandwidth(media))
+
+	media.Bandwidth = []sdp.Bandwidth{{Type: sdpBandwidthTypeAS, Bandwidth: 500}}
+	assert.Equal(t, uint64(500_000), getBandwidth(media))
+
+	// TIAS is preferred, experimental types are ignored
+	media.Bandwidth = append(media.Bandwidth,
+		sdp.Bandwidth{Type: sdpBandwidthTypeTIAS, Bandwidth: 450_000},
+		sdp.Bandwidth{Experimental: true, Type: "TIAS", Bandwidth: 1},
+	)
+	assert.Equal(t, uint64(450_000), getBandwidth(media))
+
+	media = &sdp.MediaDescription{}
+	addBandwidth(media, 0)
+	assert.Empty(t, media.Bandwidth)
+	addBandwidth(media, 450_500)
+	assert.Equal(t, []sdp.Bandwidth{
+		{Type: sdpBandwidthTypeAS, Bandwidth: 451},
+		{Type: sdpBandwidthTypeTIAS, Bandwidth: 450_500},
+	}, media.Bandwidth)
+}
+
+func TestRTPTransceiver_RemoteBandwidth(t *testing.T) {
+	offerPC, answerPC, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	sender, err := offerPC.AddTrack(track)
+	assert.NoError(t, err)
+
+	offer, err := offerPC.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, offerPC.SetLocalDescription(offer))
+	assert.NoError(t, answerPC.SetRemoteDescription(offer))
+
+	// The answerer asks for at most 500kbps
+	answerPC.GetTransceivers()[0].SetReceiveBandwidth(500_000)
+	assert.Equal(t, uint64(500_000), answerPC.GetTransceivers()[0].ReceiveBandwidth())
+	answer, err := answerPC.CreateAnswer(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, answer.SDP, "b=AS:500\r\n")
+	assert.Contains(t, answer.SDP, "b=TIAS:500000\r\n")
+	assert.NoError(t, answerPC.SetLocalDescription(answer))
+	assert.NoError(t, offerPC.SetRemoteDescription(answer))
+
+	transceiver := offerPC.GetTransceivers()[0]
+	assert.Equal(t, uint64(500_000), transceiver.RemoteBandwidth())
+	assert.Equal(t, 62_500.0, sender.bandwidthLimit.rate())
+	assert.Equal(t, uint64(0), answerPC.GetTransceivers()[0].RemoteBandwidth())
+
+	closePairNow(t, offerPC, answerPC)
+}
@@ -30,7 +30,8 @@ const (
 
 	// BundlePolicyMaxBundle indicates to gather ICE candidates for only
 	// one track. If the remote endpoint is not bundle-aware, negotiate only
-	// one media track.
+	// one media track. The initial offer marks the other media sections
+	// bundle-only, with a zero port.
 	BundlePolicyMaxBundle
 )
 
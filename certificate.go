@@ -9,6 +9,7 @@ package webrtc
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -35,28 +36,32 @@ type Certificate struct {
 // by DTLS for encrypting data sent over the wire. This method differs from
 // GenerateCertificate by allowing to specify a template x509.Certificate to
 // be used in order to define certificate parameters.
+//
+// The key may be any crypto.Signer with an RSA, ECDSA or Ed25519 public key.
+// This allows the private key to live outside of process memory, for example
+// in an HSM accessed via PKCS#11.
 func NewCertificate(key crypto.PrivateKey, tpl x509.Certificate) (*Certificate, error) {
-	var err error
-	var certDER []byte
-	switch sk := key.(type) {
-	case *rsa.PrivateKey:
-		pk := sk.Public()
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, &rtcerr.NotSupportedError{Err: ErrPrivateKeyType}
+	}
+
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
 		tpl.SignatureAlgorithm = x509.SHA256WithRSA
-		certDER, err = x509.CreateCertificate(rand.Reader, &tpl, &tpl, pk, sk)
-		if err != nil {
-			return nil, &rtcerr.UnknownError{Err: err}
-		}
-	case *ecdsa.PrivateKey:
-		pk := sk.Public()
+	case *ecdsa.PublicKey:
 		tpl.SignatureAlgorithm = x509.ECDSAWithSHA256
-		certDER, err = x509.CreateCertificate(rand.Reader, &tpl, &tpl, pk, sk)
-		if err != nil {
-			return nil, &rtcerr.UnknownError{Err: err}
-		}
+	case ed25519.PublicKey:
+		tpl.SignatureAlgorithm = x509.PureEd25519
 	default:
 		return nil, &rtcerr.NotSupportedError{Err: ErrPrivateKeyType}
 	}
 
+	certDER, err := x509.CreateCertificate(rand.Reader, &tpl, &tpl, signer.Public(), signer)
+	if err != nil {
+		return nil, &rtcerr.UnknownError{Err: err}
+	}
+
 	cert, err := x509.ParseCertificate(certDER)
 	if err != nil {
 		return nil, &rtcerr.UnknownError{Err: err}
@@ -93,6 +98,18 @@ func (c Certificate) Equals(cert Certificate) bool {
 		}
 
 		return false
+	case crypto.Signer:
+		// External signers (HSM, KMS) are compared by their public key
+		oSK, ok := cert.privateKey.(crypto.Signer)
+		if !ok {
+			return false
+		}
+		cPK, ok := cSK.Public().(interface{ Equal(crypto.PublicKey) bool })
+		if !ok || !cPK.Equal(oSK.Public()) {
+			return false
+		}
+
+		return c.x509Cert.Equal(cert.x509Cert)
 	default:
 		return false
 	}
@@ -160,6 +177,7 @@ func GenerateCertificate(secretKey crypto.PrivateKey) (*Certificate, error) {
 // CertificateFromX509 creates a new WebRTC Certificate from a given PrivateKey and Certificate
 //
 // This can be used if you want to share a certificate across multiple PeerConnections.
+// The privateKey may also be a crypto.Signer backed by external key storage.
 func CertificateFromX509(privateKey crypto.PrivateKey, certificate *x509.Certificate) Certificate {
 	return Certificate{privateKey, certificate, fmt.Sprintf("certificate-%d", time.Now().UnixNano())}
 }
@@ -190,7 +208,9 @@ func (c Certificate) collectStats(report *statsReportCollector) error {
 }
 
 // CertificateFromPEM creates a fresh certificate based on a string containing
-// pem blocks fort the private key and x509 certificate.
+// pem blocks fort the private key and x509 certificate, as returned by PEM.
+// Storing the PEM keeps the DTLS fingerprint of a PeerConnection the same
+// across restarts of the process.
 func CertificateFromPEM(pems string) (*Certificate, error) {
 	// decode & parse the certificate
 	block, more := pem.Decode([]byte(pems))
@@ -222,7 +242,16 @@ func CertificateFromPEM(pems string) (*Certificate, error) {
 
 // PEM returns the certificate encoded as two pem block: once for the X509
 // certificate and the other for the private key.
+//
+// Certificates backed by an external crypto.Signer can't be exported and
+// return ErrPrivateKeyNotExportable.
 func (c Certificate) PEM() (string, error) {
+	switch c.privateKey.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return "", ErrPrivateKeyNotExportable
+	}
+
 	// First write the X509 certificate
 	var builder strings.Builder
 	xcertBytes := make(
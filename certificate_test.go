@@ -7,13 +7,16 @@
 package webrtc
 
 import (
+	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"io"
 	"testing"
 	"time"
 
@@ -65,6 +68,44 @@ func TestGenerateCertificateECDSA(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestGenerateCertificateEd25519(t *testing.T) {
+	_, sk, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	skDER, err := x509.MarshalPKCS8PrivateKey(sk)
+	assert.Nil(t, err)
+
+	skPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: skDER,
+	})
+
+	cert, err := GenerateCertificate(sk)
+	assert.Nil(t, err)
+	assert.Equal(t, x509.PureEd25519, cert.x509Cert.SignatureAlgorithm)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.x509Cert.Raw,
+	})
+
+	_, err = tls.X509KeyPair(certPEM, skPEM)
+	assert.Nil(t, err)
+
+	// The PEM round trip keeps the key and fingerprint
+	certPEMs, err := cert.PEM()
+	assert.Nil(t, err)
+	loaded, err := CertificateFromPEM(certPEMs)
+	assert.Nil(t, err)
+	assert.True(t, cert.Equals(*loaded))
+
+	fingerprints, err := cert.GetFingerprints()
+	assert.Nil(t, err)
+	loadedFingerprints, err := loaded.GetFingerprints()
+	assert.Nil(t, err)
+	assert.Equal(t, fingerprints, loadedFingerprints)
+}
+
 func TestGenerateCertificateEqual(t *testing.T) {
 	sk1, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	assert.Nil(t, err)
@@ -136,3 +177,61 @@ func TestPEM(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, pem, pem2)
 }
+
+// externalSigner hides the concrete private key type, like an HSM backed signer would.
+type externalSigner struct {
+	signer crypto.Signer
+}
+
+func (e externalSigner) Public() crypto.PublicKey {
+	return e.signer.Public()
+}
+
+func (e externalSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return e.signer.Sign(rand, digest, opts)
+}
+
+func TestCertificateExternalSigner(t *testing.T) {
+	sk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	cert, err := GenerateCertificate(externalSigner{sk})
+	assert.NoError(t, err)
+	assert.Equal(t, x509.ECDSAWithSHA256, cert.x509Cert.SignatureAlgorithm)
+	assert.NoError(t, cert.x509Cert.CheckSignature(
+		cert.x509Cert.SignatureAlgorithm, cert.x509Cert.RawTBSCertificate, cert.x509Cert.Signature,
+	))
+
+	shared := CertificateFromX509(externalSigner{sk}, cert.x509Cert)
+	assert.True(t, cert.Equals(shared))
+
+	_, err = cert.PEM()
+	assert.ErrorIs(t, err, ErrPrivateKeyNotExportable)
+
+	_, err = cert.GetFingerprints()
+	assert.NoError(t, err)
+}
+
+func TestCertificateExternalSignerHandshake(t *testing.T) {
+	newSignerCertificate := func() Certificate {
+		sk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+
+		cert, err := GenerateCertificate(externalSigner{sk})
+		assert.NoError(t, err)
+
+		return *cert
+	}
+
+	offerer, err := NewPeerConnection(Configuration{Certificates: []Certificate{newSignerCertificate()}})
+	assert.NoError(t, err)
+
+	answerer, err := NewPeerConnection(Configuration{Certificates: []Certificate{newSignerCertificate()}})
+	assert.NoError(t, err)
+
+	peerConnectionConnected := untilConnectionState(PeerConnectionStateConnected, offerer, answerer)
+	assert.NoError(t, signalPair(offerer, answerer))
+	peerConnectionConnected.Wait()
+
+	closePairNow(t, offerer, answerer)
+}
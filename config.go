@@ -0,0 +1,341 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pion/ice/v4"
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// apiConfig is the configuration file read by NewAPIFromConfig.
+type apiConfig struct {
+	Codecs       []codecConfig `json:"codecs"`
+	Interceptors []string      `json:"interceptors"`
+	ICE          iceConfig     `json:"ice"`
+	Logging      loggingConfig `json:"logging"`
+}
+
+type codecConfig struct {
+	MimeType     string         `json:"mimeType"`
+	ClockRate    uint32         `json:"clockRate"`
+	Channels     uint16         `json:"channels"`
+	SDPFmtpLine  string         `json:"sdpFmtpLine"`
+	PayloadType  PayloadType    `json:"payloadType"`
+	RTCPFeedback []RTCPFeedback `json:"rtcpFeedback"`
+}
+
+type iceConfig struct {
+	Lite                 bool            `json:"lite"`
+	NetworkTypes         []string        `json:"networkTypes"`
+	PortMin              uint16          `json:"portMin"`
+	PortMax              uint16          `json:"portMax"`
+	NAT1To1IPs           []string        `json:"nat1To1IPs"`
+	NAT1To1CandidateType string          `json:"nat1To1CandidateType"`
+	IncludeLoopback      bool            `json:"includeLoopback"`
+	MulticastDNSMode     string          `json:"multicastDNSMode"`
+	DisconnectedTimeout  *configDuration `json:"disconnectedTimeout"`
+	FailedTimeout        *configDuration `json:"failedTimeout"`
+	KeepAliveInterval    *configDuration `json:"keepAliveInterval"`
+}
+
+type loggingConfig struct {
+	Level  string            `json:"level"`
+	Scopes map[string]string `json:"scopes"`
+}
+
+// configDuration is a time.Duration written as a string like "1.5s".
+type configDuration time.Duration
+
+func (d *configDuration) UnmarshalJSON(b []byte) error {
+	var val string
+	if err := json.Unmarshal(b, &val); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(val)
+	if err != nil {
+		return err
+	}
+	*d = configDuration(duration)
+
+	return nil
+}
+
+// NewAPIFromConfig creates a new API configured by a YAML or JSON document,
+// so services can tune Pion with their deployment configuration instead of
+// code. All keys are optional and unknown keys are an error:
+//
+//	codecs:                # replaces the default codecs
+//	  - mimeType: video/VP8
+//	    clockRate: 90000
+//	    payloadType: 96
+//	    rtcpFeedback: [{type: nack}, {type: nack, parameter: pli}]
+//	  - mimeType: audio/opus
+//	    clockRate: 48000
+//	    channels: 2
+//	    sdpFmtpLine: minptime=10;useinbandfec=1
+//	    payloadType: 111
+//	interceptors:          # replaces the default interceptors, [] for none
+//	  [nack, rtcpReports, twccSender]
+//	ice:
+//	  lite: false
+//	  networkTypes: [udp4, udp6]
+//	  portMin: 10000
+//	  portMax: 20000
+//	  nat1To1IPs: [203.0.113.1]
+//	  nat1To1CandidateType: host
+//	  includeLoopback: false
+//	  multicastDNSMode: disable    # disable, resolve or gather
+//	  disconnectedTimeout: 5s
+//	  failedTimeout: 25s
+//	  keepAliveInterval: 2s
+//	logging:
+//	  level: warn                  # disabled, error, warn, info, debug or trace
+//	  scopes: {ice: debug}
+//
+// The interceptors are default, nack, rtcpReports, twccSender,
+// twccHeaderExtensionSender, congestionControlFeedback and
+// simulcastExtensionHeaders. Without a logging level the levels are read
+// from the PION_LOG_* environment variables, as by NewAPI.
+func NewAPIFromConfig(r io.Reader) (*API, error) {
+	config, err := readAPIConfig(r)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaEngine := &MediaEngine{}
+	if err = config.registerCodecs(mediaEngine); err != nil {
+		return nil, err
+	}
+
+	interceptorRegistry := &interceptor.Registry{}
+	if err = config.registerInterceptors(mediaEngine, interceptorRegistry); err != nil {
+		return nil, err
+	}
+
+	settingEngine := SettingEngine{}
+	if err = config.ICE.apply(&settingEngine); err != nil {
+		return nil, err
+	}
+	if settingEngine.LoggerFactory, err = config.Logging.loggerFactory(); err != nil {
+		return nil, err
+	}
+
+	return NewAPI(
+		WithMediaEngine(mediaEngine),
+		WithInterceptorRegistry(interceptorRegistry),
+		WithSettingEngine(settingEngine),
+	), nil
+}
+
+// readAPIConfig reads a YAML document, JSON being a subset of YAML. It is
+// converted to JSON so the config only needs JSON tags and the types of the
+// package decode as they do from JSON.
+func readAPIConfig(r io.Reader) (*apiConfig, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	if err = yaml.Unmarshal(raw, &document); err != nil {
+		// nolint
+		return nil, fmt.Errorf("%w: %v", errAPIConfigMalformed, err)
+	}
+
+	jsonDocument, err := json.Marshal(document)
+	if err != nil {
+		// nolint
+		return nil, fmt.Errorf("%w: %v", errAPIConfigMalformed, err)
+	}
+
+	config := &apiConfig{}
+	decoder := json.NewDecoder(bytes.NewReader(jsonDocument))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(config); err != nil {
+		// nolint
+		return nil, fmt.Errorf("%w: %v", errAPIConfigMalformed, err)
+	}
+
+	return config, nil
+}
+
+func (c *apiConfig) registerCodecs(mediaEngine *MediaEngine) error {
+	if len(c.Codecs) == 0 {
+		return mediaEngine.RegisterDefaultCodecs()
+	}
+
+	for _, codec := range c.Codecs {
+		kind := NewRTPCodecType(strings.ToLower(strings.SplitN(codec.MimeType, "/", 2)[0]))
+		if kind == RTPCodecTypeUnknown || codec.ClockRate == 0 {
+			return fmt.Errorf("%w: %s", errAPIConfigInvalidCodec, codec.MimeType)
+		}
+
+		if err := mediaEngine.RegisterCodec(RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{
+				MimeType:     codec.MimeType,
+				ClockRate:    codec.ClockRate,
+				Channels:     codec.Channels,
+				SDPFmtpLine:  codec.SDPFmtpLine,
+				RTCPFeedback: codec.RTCPFeedback,
+			},
+			PayloadType: codec.PayloadType,
+		}, kind); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *apiConfig) registerInterceptors(mediaEngine *MediaEngine, interceptorRegistry *interceptor.Registry) error {
+	if c.Interceptors == nil {
+		return RegisterDefaultInterceptors(mediaEngine, interceptorRegistry)
+	}
+
+	for _, name := range c.Interceptors {
+		var err error
+		switch name {
+		case "default":
+			err = RegisterDefaultInterceptors(mediaEngine, interceptorRegistry)
+		case "nack":
+			err = ConfigureNack(mediaEngine, interceptorRegistry)
+		case "rtcpReports":
+			err = ConfigureRTCPReports(interceptorRegistry)
+		case "twccSender":
+			err = ConfigureTWCCSender(mediaEngine, interceptorRegistry)
+		case "twccHeaderExtensionSender":
+			err = ConfigureTWCCHeaderExtensionSender(mediaEngine, interceptorRegistry)
+		case "congestionControlFeedback":
+			err = ConfigureCongestionControlFeedback(mediaEngine, interceptorRegistry)
+		case "simulcastExtensionHeaders":
+			err = ConfigureSimulcastExtensionHeaders(mediaEngine)
+		default:
+			err = fmt.Errorf("%w: %s", errAPIConfigUnknownInterceptor, name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *iceConfig) apply(settingEngine *SettingEngine) error { //nolint:cyclop
+	settingEngine.SetLite(c.Lite)
+	settingEngine.SetIncludeLoopbackCandidate(c.IncludeLoopback)
+
+	if len(c.NetworkTypes) != 0 {
+		networkTypes := make([]NetworkType, 0, len(c.NetworkTypes))
+		for _, raw := range c.NetworkTypes {
+			networkType, err := NewNetworkType(raw)
+			if err != nil {
+				return err
+			}
+			networkTypes = append(networkTypes, networkType)
+		}
+		settingEngine.SetNetworkTypes(networkTypes)
+	}
+
+	if err := settingEngine.SetEphemeralUDPPortRange(c.PortMin, c.PortMax); err != nil {
+		return err
+	}
+
+	if len(c.NAT1To1IPs) != 0 {
+		candidateType := ICECandidateTypeHost
+		if c.NAT1To1CandidateType != "" {
+			var err error
+			if candidateType, err = NewICECandidateType(c.NAT1To1CandidateType); err != nil {
+				return err
+			}
+		}
+		settingEngine.SetNAT1To1IPs(c.NAT1To1IPs, candidateType)
+	}
+
+	switch NewICEMulticastDNSMode(c.MulticastDNSMode) {
+	case ICEMulticastDNSModeDisabled:
+		settingEngine.SetICEMulticastDNSMode(ice.MulticastDNSModeDisabled)
+	case ICEMulticastDNSModeQueryOnly:
+		settingEngine.SetICEMulticastDNSMode(ice.MulticastDNSModeQueryOnly)
+	case ICEMulticastDNSModeQueryAndGather:
+		settingEngine.SetICEMulticastDNSMode(ice.MulticastDNSModeQueryAndGather)
+	default:
+		if c.MulticastDNSMode != "" {
+			return fmt.Errorf("%w: multicastDNSMode %s", errAPIConfigMalformed, c.MulticastDNSMode)
+		}
+	}
+
+	if c.DisconnectedTimeout != nil {
+		timeout := time.Duration(*c.DisconnectedTimeout)
+		settingEngine.timeout.ICEDisconnectedTimeout = &timeout
+	}
+	if c.FailedTimeout != nil {
+		timeout := time.Duration(*c.FailedTimeout)
+		settingEngine.timeout.ICEFailedTimeout = &timeout
+	}
+	if c.KeepAliveInterval != nil {
+		interval := time.Duration(*c.KeepAliveInterval)
+		settingEngine.timeout.ICEKeepaliveInterval = &interval
+	}
+
+	return nil
+}
+
+// loggerFactory returns nil when the logging isn't configured, so NewAPI
+// creates its default LoggerFactory.
+func (c *loggingConfig) loggerFactory() (logging.LoggerFactory, error) {
+	if c.Level == "" && len(c.Scopes) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	loggerFactory := logging.NewDefaultLoggerFactory()
+	if c.Level != "" {
+		level, err := newConfigLogLevel(c.Level)
+		if err != nil {
+			return nil, err
+		}
+		loggerFactory.DefaultLogLevel = level
+	}
+
+	for scope, raw := range c.Scopes {
+		level, err := newConfigLogLevel(raw)
+		if err != nil {
+			return nil, err
+		}
+		loggerFactory.ScopeLevels[strings.ToLower(scope)] = level
+	}
+
+	return loggerFactory, nil
+}
+
+func newConfigLogLevel(raw string) (logging.LogLevel, error) {
+	switch strings.ToLower(raw) {
+	case "disabled":
+		return logging.LogLevelDisabled, nil
+	case "error":
+		return logging.LogLevelError, nil
+	case "warn":
+		return logging.LogLevelWarn, nil
+	case "info":
+		return logging.LogLevelInfo, nil
+	case "debug":
+		return logging.LogLevelDebug, nil
+	case "trace":
+		return logging.LogLevelTrace, nil
+	default:
+		return logging.LogLevelDisabled, fmt.Errorf("%w: %s", errAPIConfigUnknownLogLevel, raw)
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/ice/v4"
+	"github.com/pion/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAPIFromConfig(t *testing.T) {
+	api, err := NewAPIFromConfig(strings.NewReader(`
+codecs:
+  - mimeType: video/VP8
+    clockRate: 90000
+    payloadType: 96
+    rtcpFeedback: [{type: nack}, {type: nack, parameter: pli}]
+  - mimeType: audio/opus
+    clockRate: 48000
+    channels: 2
+    sdpFmtpLine: minptime=10;useinbandfec=1
+    payloadType: 111
+interceptors: [nack, rtcpReports]
+ice:
+  lite: true
+  networkTypes: [udp4]
+  portMin: 10000
+  portMax: 20000
+  nat1To1IPs: [203.0.113.1]
+  multicastDNSMode: disable
+  failedTimeout: 10s
+logging:
+  level: error
+  scopes: {ice: debug}
+`))
+	assert.NoError(t, err)
+
+	assert.Len(t, api.mediaEngine.videoCodecs, 1)
+	assert.Equal(t, MimeTypeVP8, api.mediaEngine.videoCodecs[0].MimeType)
+	assert.Equal(t, []RTCPFeedback{{Type: "nack"}, {Type: "nack", Parameter: "pli"}},
+		api.mediaEngine.videoCodecs[0].RTCPFeedback)
+	assert.Len(t, api.mediaEngine.audioCodecs, 1)
+	assert.Equal(t, "minptime=10;useinbandfec=1", api.mediaEngine.audioCodecs[0].SDPFmtpLine)
+
+	settingEngine := api.settingEngine
+	assert.True(t, settingEngine.candidates.ICELite)
+	assert.Equal(t, []NetworkType{NetworkTypeUDP4}, settingEngine.candidates.ICENetworkTypes)
+	assert.Equal(t, uint16(10000), settingEngine.ephemeralUDP.PortMin)
+	assert.Equal(t, uint16(20000), settingEngine.ephemeralUDP.PortMax)
+	assert.Equal(t, []string{"203.0.113.1"}, settingEngine.candidates.NAT1To1IPs)
+	assert.Equal(t, ICECandidateTypeHost, settingEngine.candidates.NAT1To1IPCandidateType)
+	assert.Equal(t, ice.MulticastDNSModeDisabled, settingEngine.candidates.MulticastDNSMode)
+	assert.Nil(t, settingEngine.timeout.ICEDisconnectedTimeout)
+	if assert.NotNil(t, settingEngine.timeout.ICEFailedTimeout) {
+		assert.Equal(t, 10*time.Second, *settingEngine.timeout.ICEFailedTimeout)
+	}
+
+	loggerFactory, ok := settingEngine.LoggerFactory.(*logging.DefaultLoggerFactory)
+	if assert.True(t, ok) {
+		assert.Equal(t, logging.LogLevelError, loggerFactory.DefaultLogLevel)
+		assert.Equal(t, logging.LogLevelDebug, loggerFactory.ScopeLevels["ice"])
+	}
+
+	peerConnection, err := api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.NoError(t, peerConnection.Close())
+}
+
+func TestNewAPIFromConfig_Defaults(t *testing.T) {
+	for _, config := range []string{"", "{}", `{"ice": {"lite": false}}`} {
+		api, err := NewAPIFromConfig(strings.NewReader(config))
+		assert.NoError(t, err)
+
+		defaultAPI := NewAPI()
+		assert.Equal(t, len(defaultAPI.mediaEngine.videoCodecs), len(api.mediaEngine.videoCodecs))
+		assert.Equal(t, len(defaultAPI.mediaEngine.audioCodecs), len(api.mediaEngine.audioCodecs))
+		assert.Nil(t, api.settingEngine.timeout.ICEFailedTimeout)
+	}
+}
+
+func TestNewAPIFromConfig_Errors(t *testing.T) {
+	for _, testCase := range []struct {
+		config string
+		err    error
+	}{
+		{"codecs: [", errAPIConfigMalformed},
+		{"unknown: true", errAPIConfigMalformed},
+		{"ice: {failedTimeout: 10}", errAPIConfigMalformed},
+		{"ice: {multicastDNSMode: sometimes}", errAPIConfigMalformed},
+		{"codecs: [{mimeType: VP8, clockRate: 90000}]", errAPIConfigInvalidCodec},
+		{"interceptors: [jitterBuffer]", errAPIConfigUnknownInterceptor},
+		{"logging: {level: loud}", errAPIConfigUnknownLogLevel},
+		{"ice: {networkTypes: [sctp]}", errNetworkTypeUnknown},
+		{"ice: {portMin: 2, portMax: 1}", ice.ErrPort},
+	} {
+		_, err := NewAPIFromConfig(strings.NewReader(testCase.config))
+		assert.ErrorIs(t, err, testCase.err, testCase.config)
+	}
+}
@@ -52,4 +52,52 @@ type Configuration struct {
 	// SDPSemantics controls the type of SDP offers accepted by and
 	// SDP answers generated by the PeerConnection.
 	SDPSemantics SDPSemantics `json:"sdpSemantics,omitempty"`
+
+	// FeatureFlags toggles experimental behaviors for this PeerConnection.
+	// Entries override the flags set via SettingEngine.SetFeatureFlags.
+	// This is a Pion specific extension and is not part of the W3C API.
+	FeatureFlags FeatureFlags `json:"featureFlags,omitempty"`
+
+	// ICEMulticastDNSMode overrides the mode set via
+	// SettingEngine.SetICEMulticastDNSMode for this PeerConnection.
+	// This is a Pion specific extension and is not part of the W3C API.
+	ICEMulticastDNSMode ICEMulticastDNSMode `json:"iceMulticastDNSMode,omitempty"`
+
+	// ICEMulticastDNSInterfaces are the names of the network interfaces mDNS
+	// queries and answers are sent on. pion/ice gathers host candidates on the
+	// same interfaces, so the other interfaces aren't used by this
+	// PeerConnection. This is a Pion specific extension and is not part of
+	// the W3C API.
+	ICEMulticastDNSInterfaces []string `json:"iceMulticastDNSInterfaces,omitempty"`
+
+	// DisabledInterceptors are the names of the interceptors of the API's
+	// interceptor.Registry that this PeerConnection is created without, see
+	// NewNamedInterceptorFactory. It is ignored by SetConfiguration.
+	// This is a Pion specific extension and is not part of the W3C API.
+	DisabledInterceptors []string `json:"disabledInterceptors,omitempty"`
+
+	// LatencyMode configures the jitter buffer of the remote tracks, and the
+	// NACK and ULPFEC interceptors of this PeerConnection. The NACK and ULPFEC
+	// interceptors of the API's interceptor.Registry are built with the
+	// options of the mode, the missing ones are added. RED and ULPFEC are
+	// registered with free payload types if the mode uses FEC. It can't be
+	// modified by SetConfiguration.
+	// This is a Pion specific extension and is not part of the W3C API.
+	LatencyMode LatencyMode `json:"latencyMode,omitempty"`
+}
+
+// hasICEMulticastDNSInterfaces returns true if the mDNS interfaces are the
+// given ones, in the same order.
+func (c Configuration) hasICEMulticastDNSInterfaces(interfaces []string) bool {
+	if len(c.ICEMulticastDNSInterfaces) != len(interfaces) {
+		return false
+	}
+
+	for i := range interfaces {
+		if c.ICEMulticastDNSInterfaces[i] != interfaces[i] {
+			return false
+		}
+	}
+
+	return true
 }
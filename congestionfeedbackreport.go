@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import "time"
+
+// CongestionFeedbackReport is the RFC 8888 congestion control feedback the
+// remote peer sent about a range of packets of an encoding.
+type CongestionFeedbackReport struct {
+	// SSRC of the encoding the report is about
+	SSRC SSRC
+
+	// RID of the encoding the report is about, empty if not simulcast
+	RID string
+
+	// Packets is the status of each packet of the range, in sequence number order
+	Packets []CongestionFeedbackPacket
+
+	// Received is how many packets of the range were received
+	Received int
+
+	// Lost is how many packets of the range weren't received
+	Lost int
+
+	// CongestionExperienced is how many packets arrived with ECNCE, the
+	// congestion signal L4S senders react to
+	CongestionExperienced int
+}
+
+// CongestionFeedbackPacket is the status of a single packet in a CongestionFeedbackReport.
+type CongestionFeedbackPacket struct {
+	SequenceNumber uint16
+	Received       bool
+
+	// ECN is the codepoint the packet arrived with
+	ECN ECN
+
+	// ArrivalTimeOffset is how long before the report was sent the packet
+	// arrived, negative if the remote peer couldn't represent it
+	ArrivalTimeOffset time.Duration
+}
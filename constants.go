@@ -15,7 +15,7 @@ const (
 	// mid and rid values.
 	simulcastProbeCount = 10
 
-	// simulcastMaxProbeRoutines is how many active routines can be used to probe
+	// simulcastMaxProbeRoutines is how many active routines can be used to probe by default
 	// If the total amount of incoming SSRCes exceeds this new requests will be ignored.
 	simulcastMaxProbeRoutines = 25
 
@@ -23,8 +23,22 @@ const (
 
 	sdpAttributeRid = "rid"
 
+	// maxMidLength is the longest mid that fits a one-byte RTP header extension, RFC 8285 Section 4.2.
+	maxMidLength = 16
+
 	sdpAttributeSimulcast = "simulcast"
 
+	sdpAttributeMaxMessageSize = "max-message-size"
+
+	// sctpDefaultMaxMessageSize is the largest message a SCTPTransport accepts
+	// unless configured otherwise, and the limit of a remote peer that doesn't
+	// advertise one, RFC 8841 Section 6.1.
+	sctpDefaultMaxMessageSize = 65536
+
+	// sdpAttributeBundleOnly marks media sections with a zero port that are
+	// only sent over the BUNDLE transport, RFC 8843.
+	sdpAttributeBundleOnly = "bundle-only"
+
 	rtpOutboundMTU = 1200
 
 	rtpPayloadTypeBitmask = 0x7F
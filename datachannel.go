@@ -66,6 +66,10 @@ type DataChannel struct {
 	// A reference to the associated api object used by this datachannel
 	api *API
 	log logging.LeveledLogger
+
+	latency dataChannelLatency
+
+	transcriptWriter DataChannelTranscriptWriter
 }
 
 // NewDataChannel creates a new DataChannel.
@@ -419,6 +423,7 @@ func (d *DataChannel) readLoop() {
 
 		msg := DataChannelMessage{Data: make([]byte, n), IsString: isString}
 		copy(msg.Data, buffer[:n])
+		d.transcribe(DataChannelTranscriptDirectionReceived, msg.Data, isString, time.Now())
 
 		// NB: Why was DataChannelMessage not passed as a pointer value?
 		d.onMessage(msg) // nolint:staticcheck
@@ -431,8 +436,16 @@ func (d *DataChannel) Send(data []byte) error {
 	if err != nil {
 		return err
 	}
+	if err = d.ensureMessageSize(len(data)); err != nil {
+		return err
+	}
 
+	sentAt := time.Now()
 	_, err = d.dataChannel.WriteDataChannel(data, false)
+	if err == nil {
+		d.recordSent(len(data), sentAt)
+		d.transcribe(DataChannelTranscriptDirectionSent, data, false, sentAt)
+	}
 
 	return err
 }
@@ -443,12 +456,33 @@ func (d *DataChannel) SendText(s string) error {
 	if err != nil {
 		return err
 	}
+	if err = d.ensureMessageSize(len(s)); err != nil {
+		return err
+	}
 
+	sentAt := time.Now()
 	_, err = d.dataChannel.WriteDataChannel([]byte(s), true)
+	if err == nil {
+		d.recordSent(len(s), sentAt)
+		d.transcribe(DataChannelTranscriptDirectionSent, []byte(s), true, sentAt)
+	}
 
 	return err
 }
 
+// recordSent measures how long the message waits to be acknowledged, if
+// SettingEngine.SetDataChannelLatencySampling is set.
+func (d *DataChannel) recordSent(size int, sentAt time.Time) {
+	interval := d.api.settingEngine.dataChannelLatencySampling
+	if interval == 0 {
+		return
+	}
+
+	if d.latency.sent(size, sentAt) {
+		go d.sampleLatency(interval)
+	}
+}
+
 func (d *DataChannel) ensureOpen() error {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
@@ -459,6 +493,20 @@ func (d *DataChannel) ensureOpen() error {
 	return nil
 }
 
+// ensureMessageSize returns a TypeError wrapping ErrDataChannelMessageTooLarge
+// if a message of size can't be sent, as the send method of the W3C API.
+func (d *DataChannel) ensureMessageSize(size int) error {
+	d.mu.RLock()
+	sctpTransport := d.sctpTransport
+	d.mu.RUnlock()
+
+	if sctpTransport != nil && float64(size) > sctpTransport.MaxMessageSize() {
+		return &rtcerr.TypeError{Err: ErrDataChannelMessageTooLarge}
+	}
+
+	return nil
+}
+
 // Detach allows you to detach the underlying datachannel.
 // This provides an idiomatic API to work with
 // (`io.ReadWriteCloser` with its `.Read()` and `.Write()` methods,
@@ -734,7 +782,9 @@ func (d *DataChannel) collectStats(collector *statsReportCollector) {
 		stats.BytesSent = d.dataChannel.BytesSent()
 		stats.MessagesReceived = d.dataChannel.MessagesReceived()
 		stats.BytesReceived = d.dataChannel.BytesReceived()
+		stats.BufferedAmount = d.dataChannel.BufferedAmount()
 	}
+	d.latency.collectStats(&stats)
 
 	collector.Collect(stats.ID, stats)
 }
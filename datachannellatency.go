@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+)
+
+type sentDataChannelMessage struct {
+	// end is the offset of the end of the message in the bytes written to the stream
+	end    uint64
+	sentAt time.Time
+}
+
+// dataChannelLatency measures how long the messages sent on a DataChannel
+// wait until the remote peer acknowledges them. The SCTP stream releases the
+// buffered amount of a message once a SACK acknowledges it, so the messages
+// whose end is below the bytes written minus the buffered amount are acked.
+type dataChannelLatency struct {
+	mu sync.Mutex
+
+	written  uint64
+	inFlight []sentDataChannelMessage
+	sampling bool
+
+	acknowledged uint32
+	totalDelay   time.Duration
+	maxDelay     time.Duration
+}
+
+// sent records a message of size bytes written to the stream, it returns
+// true if the caller has to start sampling the buffered amount.
+func (l *dataChannelLatency) sent(size int, now time.Time) bool {
+	// Empty messages are sent as a single byte
+	if size == 0 {
+		size = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.written += uint64(size)
+	l.inFlight = append(l.inFlight, sentDataChannelMessage{end: l.written, sentAt: now})
+
+	startSampling := !l.sampling
+	l.sampling = true
+
+	return startSampling
+}
+
+// update acknowledges the messages that left the buffer, it returns false
+// once no message is in flight and sampling stopped.
+func (l *dataChannelLatency) update(bufferedAmount uint64, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	released := l.written - bufferedAmount
+	if bufferedAmount > l.written {
+		released = 0
+	}
+
+	i := 0
+	for ; i < len(l.inFlight) && l.inFlight[i].end <= released; i++ {
+		delay := now.Sub(l.inFlight[i].sentAt)
+		l.acknowledged++
+		l.totalDelay += delay
+		if delay > l.maxDelay {
+			l.maxDelay = delay
+		}
+	}
+	l.inFlight = l.inFlight[i:]
+
+	l.sampling = len(l.inFlight) != 0
+
+	return l.sampling
+}
+
+// stop ends sampling, the messages in flight are never acknowledged.
+func (l *dataChannelLatency) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight = nil
+	l.sampling = false
+}
+
+func (l *dataChannelLatency) collectStats(stats *DataChannelStats) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats.MessagesAcknowledged = l.acknowledged
+	stats.TotalAcknowledgementDelay = l.totalDelay.Seconds()
+	stats.MaxAcknowledgementDelay = l.maxDelay.Seconds()
+}
+
+// sampleLatency polls the buffered amount until every message sent is acknowledged.
+func (d *DataChannel) sampleLatency(interval time.Duration) {
+	ticker := d.api.settingEngine.newTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.Ch() {
+		if d.ReadyState() != DataChannelStateOpen {
+			d.latency.stop()
+
+			return
+		}
+
+		if !d.latency.update(d.BufferedAmount(), time.Now()) {
+			return
+		}
+	}
+}
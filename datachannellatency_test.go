@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataChannelLatency(t *testing.T) {
+	latency := &dataChannelLatency{}
+	start := time.Now()
+
+	assert.True(t, latency.sent(10, start), "first message starts sampling")
+	assert.False(t, latency.sent(0, start.Add(time.Millisecond)), "sampling is running")
+	assert.False(t, latency.sent(5, start.Add(2*time.Millisecond)))
+
+	// Only the first message left the buffer
+	assert.True(t, latency.update(6, start.Add(10*time.Millisecond)))
+
+	// The empty message counts as a single byte
+	assert.True(t, latency.update(5, start.Add(20*time.Millisecond)))
+
+	assert.False(t, latency.update(0, start.Add(42*time.Millisecond)), "sampling stops once all are acked")
+	assert.True(t, latency.sent(1, start.Add(50*time.Millisecond)), "sampling restarts")
+	latency.stop()
+
+	stats := DataChannelStats{}
+	latency.collectStats(&stats)
+	assert.Equal(t, uint32(3), stats.MessagesAcknowledged)
+	assert.InDelta(t, 0.010+0.019+0.040, stats.TotalAcknowledgementDelay, 1e-9)
+	assert.InDelta(t, 0.040, stats.MaxAcknowledgementDelay, 1e-9)
+}
+
+func TestSetDataChannelLatencySampling(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetDataChannelLatencySampling(time.Millisecond)
+
+	offerPC, answerPC, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+	assert.NoError(t, err)
+
+	dc, err := offerPC.CreateDataChannel("latency", nil)
+	assert.NoError(t, err)
+
+	const messages = 10
+	dc.OnOpen(func() {
+		for i := 0; i < messages; i++ {
+			assert.NoError(t, dc.SendText("hello"))
+		}
+	})
+
+	assert.NoError(t, signalPair(offerPC, answerPC))
+
+	for {
+		stats, ok := offerPC.GetStats().GetDataChannelStats(dc)
+		assert.True(t, ok)
+		if stats.MessagesAcknowledged == messages {
+			assert.Greater(t, stats.TotalAcknowledgementDelay, float64(0))
+			assert.GreaterOrEqual(t, stats.TotalAcknowledgementDelay, stats.MaxAcknowledgementDelay)
+
+			break
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	closePairNow(t, offerPC, answerPC)
+}
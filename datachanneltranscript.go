@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// DataChannelTranscriptDirection tells if a DataChannel message was sent or received.
+type DataChannelTranscriptDirection int
+
+const (
+	// DataChannelTranscriptDirectionUnknown is the enum's zero-value.
+	DataChannelTranscriptDirectionUnknown DataChannelTranscriptDirection = iota
+
+	// DataChannelTranscriptDirectionSent is a message sent with Send or SendText.
+	DataChannelTranscriptDirectionSent
+
+	// DataChannelTranscriptDirectionReceived is a message passed to OnMessage.
+	DataChannelTranscriptDirectionReceived
+)
+
+func (d DataChannelTranscriptDirection) String() string {
+	switch d {
+	case DataChannelTranscriptDirectionSent:
+		return "sent"
+	case DataChannelTranscriptDirectionReceived:
+		return "received"
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// DataChannelTranscriptEntry describes a message of a DataChannel.
+type DataChannelTranscriptEntry struct {
+	Time      time.Time
+	Direction DataChannelTranscriptDirection
+	Label     string
+	ID        *uint16
+	IsString  bool
+	Size      int
+
+	// Data is the message, it is only set for writers that implement
+	// DataChannelTranscriptPayloadWriter and ask for it.
+	Data []byte
+}
+
+// DataChannelTranscriptWriter records the messages of a DataChannel, see
+// DataChannel.SetTranscriptWriter. It is only given the metadata of the
+// messages, unless it implements DataChannelTranscriptPayloadWriter.
+type DataChannelTranscriptWriter interface {
+	WriteTranscriptEntry(entry DataChannelTranscriptEntry) error
+}
+
+// DataChannelTranscriptPayloadWriter is a DataChannelTranscriptWriter that
+// can be given the messages themselves. As they may hold personal data,
+// writers opt in explicitly.
+type DataChannelTranscriptPayloadWriter interface {
+	DataChannelTranscriptWriter
+
+	// IncludePayloads returns true to have DataChannelTranscriptEntry.Data set.
+	IncludePayloads() bool
+}
+
+type jsonDataChannelTranscriptWriter struct {
+	mu              sync.Mutex
+	encoder         *json.Encoder
+	includePayloads bool
+}
+
+// NewJSONDataChannelTranscriptWriter creates a DataChannelTranscriptWriter
+// that writes an entry per line as JSON to w. The messages are written only
+// if includePayloads is true, text as "text" and binary as base64 "data".
+// It is safe to share between DataChannels.
+func NewJSONDataChannelTranscriptWriter(w io.Writer, includePayloads bool) DataChannelTranscriptPayloadWriter {
+	return &jsonDataChannelTranscriptWriter{encoder: json.NewEncoder(w), includePayloads: includePayloads}
+}
+
+func (w *jsonDataChannelTranscriptWriter) IncludePayloads() bool {
+	return w.includePayloads
+}
+
+func (w *jsonDataChannelTranscriptWriter) WriteTranscriptEntry(entry DataChannelTranscriptEntry) error {
+	line := struct {
+		Time      time.Time `json:"time"`
+		Direction string    `json:"direction"`
+		Label     string    `json:"label"`
+		ID        *uint16   `json:"id,omitempty"`
+		IsString  bool      `json:"isString"`
+		Size      int       `json:"size"`
+		Text      string    `json:"text,omitempty"`
+		Data      []byte    `json:"data,omitempty"`
+	}{
+		Time:      entry.Time,
+		Direction: entry.Direction.String(),
+		Label:     entry.Label,
+		ID:        entry.ID,
+		IsString:  entry.IsString,
+		Size:      entry.Size,
+	}
+	if entry.IsString {
+		line.Text = string(entry.Data)
+	} else {
+		line.Data = entry.Data
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.encoder.Encode(line)
+}
+
+// SetTranscriptWriter records the messages sent and received by the
+// DataChannel with writer, nil stops recording. Messages of a detached
+// DataChannel aren't recorded.
+func (d *DataChannel) SetTranscriptWriter(writer DataChannelTranscriptWriter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.transcriptWriter = writer
+}
+
+// transcribe passes a message to the transcript writer, if there is one.
+func (d *DataChannel) transcribe(direction DataChannelTranscriptDirection, data []byte, isString bool, at time.Time) {
+	d.mu.RLock()
+	writer := d.transcriptWriter
+	entry := DataChannelTranscriptEntry{
+		Time:      at,
+		Direction: direction,
+		Label:     d.label,
+		ID:        d.id,
+		IsString:  isString,
+		Size:      len(data),
+	}
+	d.mu.RUnlock()
+
+	if writer == nil {
+		return
+	}
+
+	if payloadWriter, ok := writer.(DataChannelTranscriptPayloadWriter); ok && payloadWriter.IncludePayloads() {
+		entry.Data = append([]byte{}, data...)
+	}
+
+	if err := writer.WriteTranscriptEntry(entry); err != nil {
+		d.log.Warnf("Failed to write DataChannel transcript: %v", err)
+	}
+}
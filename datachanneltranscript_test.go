@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTranscriptWriter struct {
+	mu      sync.Mutex
+	entries []DataChannelTranscriptEntry
+}
+
+func (w *testTranscriptWriter) WriteTranscriptEntry(entry DataChannelTranscriptEntry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.entries = append(w.entries, entry)
+
+	return nil
+}
+
+func TestDataChannelTranscriptDirection_String(t *testing.T) {
+	assert.Equal(t, ErrUnknownType.Error(), DataChannelTranscriptDirectionUnknown.String())
+	assert.Equal(t, "sent", DataChannelTranscriptDirectionSent.String())
+	assert.Equal(t, "received", DataChannelTranscriptDirectionReceived.String())
+}
+
+func TestJSONDataChannelTranscriptWriter(t *testing.T) {
+	id := uint16(1)
+	entry := DataChannelTranscriptEntry{
+		Time:      time.Unix(0, 0).UTC(),
+		Direction: DataChannelTranscriptDirectionSent,
+		Label:     "chat",
+		ID:        &id,
+		IsString:  true,
+		Size:      5,
+		Data:      []byte("hello"),
+	}
+
+	out := &bytes.Buffer{}
+	writer := NewJSONDataChannelTranscriptWriter(out, true)
+	assert.True(t, writer.IncludePayloads())
+	assert.NoError(t, writer.WriteTranscriptEntry(entry))
+
+	entry.IsString, entry.Data = false, []byte{0x01}
+	assert.NoError(t, writer.WriteTranscriptEntry(entry))
+
+	prefix := `{"time":"1970-01-01T00:00:00Z","direction":"sent","label":"chat","id":1,`
+	assert.Equal(t,
+		prefix+`"isString":true,"size":5,"text":"hello"}`+"\n"+
+			prefix+`"isString":false,"size":5,"data":"AQ=="}`+"\n",
+		out.String(),
+	)
+}
+
+func TestDataChannel_SetTranscriptWriter(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	offerPC, answerPC, err := newPair()
+	assert.NoError(t, err)
+
+	sent := &testTranscriptWriter{}
+	received := &bytes.Buffer{}
+	receivedWriter := NewJSONDataChannelTranscriptWriter(received, true)
+	done := make(chan struct{})
+
+	dc, err := offerPC.CreateDataChannel("transcript", nil)
+	assert.NoError(t, err)
+	dc.SetTranscriptWriter(sent)
+	dc.OnOpen(func() {
+		assert.NoError(t, dc.SendText("hello"))
+	})
+
+	answerPC.OnDataChannel(func(d *DataChannel) {
+		d.SetTranscriptWriter(receivedWriter)
+		d.OnMessage(func(DataChannelMessage) {
+			close(done)
+		})
+	})
+
+	assert.NoError(t, signalPair(offerPC, answerPC))
+	<-done
+
+	sent.mu.Lock()
+	if assert.Len(t, sent.entries, 1) {
+		entry := sent.entries[0]
+		assert.Equal(t, DataChannelTranscriptDirectionSent, entry.Direction)
+		assert.Equal(t, "transcript", entry.Label)
+		assert.True(t, entry.IsString)
+		assert.Equal(t, 5, entry.Size)
+		assert.Nil(t, entry.Data, "payloads are only given to writers that ask for them")
+	}
+	sent.mu.Unlock()
+
+	// The message is recorded before OnMessage is called
+	assert.Contains(t, received.String(), `"direction":"received","label":"transcript"`)
+	assert.Contains(t, received.String(), `"text":"hello"`)
+
+	closePairNow(t, offerPC, answerPC)
+}
@@ -7,6 +7,7 @@
 package webrtc
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -14,6 +15,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -37,12 +39,14 @@ import (
 type DTLSTransport struct {
 	lock sync.RWMutex
 
-	iceTransport          *ICETransport
-	certificates          []Certificate
-	remoteParameters      DTLSParameters
-	remoteCertificate     []byte
-	state                 DTLSTransportState
-	srtpProtectionProfile srtp.ProtectionProfile
+	iceTransport      *ICETransport
+	certificates      []Certificate
+	remoteParameters  DTLSParameters
+	remoteCertificate []byte
+	state             DTLSTransportState
+
+	// negotiatedSRTPProtectionProfile is the profile selected in the DTLS handshake
+	negotiatedSRTPProtectionProfile dtls.SRTPProtectionProfile
 
 	onStateChangeHandler   func(DTLSTransportState)
 	internalOnCloseHandler func()
@@ -53,6 +57,12 @@ type DTLSTransport struct {
 	srtpEndpoint, srtcpEndpoint *mux.Endpoint
 	simulcastStreams            []simulcastStreamPair
 	srtpReady                   chan struct{}
+	keyUsage                    *srtpKeyUsage
+	arrivals                    *packetMetadataTable
+	remoteBitrateCaps           remoteBitrateCaps
+	transportFeedback           transportFeedback
+	// rtcpCompound is set when the remote didn't signal reduced-size RTCP
+	rtcpCompound atomicBool
 
 	dtlsMatcher mux.MatchFunc
 
@@ -61,16 +71,19 @@ type DTLSTransport struct {
 }
 
 type simulcastStreamPair struct {
-	srtp  *srtp.ReadStreamSRTP
-	srtcp *srtp.ReadStreamSRTCP
+	srtp  SRTPReadStream
+	srtcp SRTPReadStream
 }
 
 // NewDTLSTransport creates a new DTLSTransport.
 // This constructor is part of the ORTC API. It is not
 // meant to be used together with the basic WebRTC API.
 func (api *API) NewDTLSTransport(transport *ICETransport, certificates []Certificate) (*DTLSTransport, error) {
+	limits := api.settingEngine.srtpKeyUsageLimits
 	trans := &DTLSTransport{
 		iceTransport: transport,
+		keyUsage:     newSRTPKeyUsage(limits.srtpPackets, limits.srtcpPackets),
+		arrivals:     newPacketMetadataTable(),
 		api:          api,
 		state:        DTLSTransportStateNew,
 		dtlsMatcher:  mux.MatchDTLS,
@@ -135,9 +148,91 @@ func (t *DTLSTransport) State() DTLSTransportState {
 	return t.state
 }
 
+// SRTPProtectionProfile returns the SRTP protection profile negotiated in the
+// DTLS handshake, or zero if the handshake hasn't completed yet. The offered
+// profiles are set with SettingEngine.SetSRTPProtectionProfiles.
+func (t *DTLSTransport) SRTPProtectionProfile() dtls.SRTPProtectionProfile {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.negotiatedSRTPProtectionProfile
+}
+
+// collectStats collects the TransportStats of the ICETransport completed with
+// the state and ciphers of DTLS.
+func (t *DTLSTransport) collectStats(collector *statsReportCollector) {
+	collector.Collecting()
+
+	stats := t.iceTransport.transportStats()
+
+	t.lock.RLock()
+	stats.DTLSState = t.state
+	stats.SRTPCipher = srtpProtectionProfileName(t.negotiatedSRTPProtectionProfile)
+	if t.conn != nil {
+		if state, ok := t.conn.ConnectionState(); ok {
+			stats.DTLSCipher = dtls.CipherSuiteName(state.CipherSuiteID)
+		}
+	}
+	if len(t.certificates) != 0 {
+		stats.LocalCertificateID = t.certificates[0].statsID
+	}
+	remoteCertificate := t.remoteCertificate
+	t.lock.RUnlock()
+
+	if len(remoteCertificate) != 0 {
+		if err := collectRemoteCertificateStats(collector, remoteCertificate); err != nil {
+			t.log.Warnf("Failed to collect remote certificate stats: %s", err)
+		} else {
+			stats.RemoteCertificateID = remoteCertificateStatsID
+		}
+	}
+
+	collector.Collect(stats.ID, stats)
+}
+
+// remoteCertificateStatsID is the ID of the CertificateStats of the
+// certificate presented by the remote peer during the DTLS handshake.
+const remoteCertificateStatsID = "remoteCertificate"
+
+func collectRemoteCertificateStats(collector *statsReportCollector, raw []byte) error {
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return err
+	}
+
+	return Certificate{x509Cert: certificate, statsID: remoteCertificateStatsID}.collectStats(collector)
+}
+
+// srtpProtectionProfileName returns the name of the profile in the IANA
+// DTLS-SRTP protection profile registry.
+func srtpProtectionProfileName(profile dtls.SRTPProtectionProfile) string {
+	switch profile {
+	case dtls.SRTP_AES128_CM_HMAC_SHA1_80:
+		return "SRTP_AES128_CM_HMAC_SHA1_80"
+	case dtls.SRTP_AES128_CM_HMAC_SHA1_32:
+		return "SRTP_AES128_CM_HMAC_SHA1_32"
+	case dtls.SRTP_NULL_HMAC_SHA1_80:
+		return "SRTP_NULL_HMAC_SHA1_80"
+	case dtls.SRTP_NULL_HMAC_SHA1_32:
+		return "SRTP_NULL_HMAC_SHA1_32"
+	case dtls.SRTP_AEAD_AES_128_GCM:
+		return "SRTP_AEAD_AES_128_GCM"
+	case dtls.SRTP_AEAD_AES_256_GCM:
+		return "SRTP_AEAD_AES_256_GCM"
+	default:
+		return ""
+	}
+}
+
 // WriteRTCP sends a user provided RTCP packet to the connected peer. If no peer is connected the
-// packet is discarded.
+// packet is discarded. Unless the remote signaled reduced-size RTCP (RFC 5506), feedback
+// sent without a report is preceded by an empty ReceiverReport.
 func (t *DTLSTransport) WriteRTCP(pkts []rtcp.Packet) (int, error) {
+	pkts = t.remoteBitrateCaps.apply(pkts)
+	if t.rtcpCompound.get() {
+		pkts = compoundRTCP(pkts)
+	}
+
 	raw, err := rtcp.Marshal(pkts)
 	if err != nil {
 		return 0, err
@@ -186,37 +281,9 @@ func (t *DTLSTransport) GetRemoteCertificate() []byte {
 }
 
 func (t *DTLSTransport) startSRTP() error {
-	srtpConfig := &srtp.Config{
-		Profile:       t.srtpProtectionProfile,
-		BufferFactory: t.api.settingEngine.BufferFactory,
-		LoggerFactory: t.api.settingEngine.LoggerFactory,
-	}
-	if t.api.settingEngine.replayProtection.SRTP != nil {
-		srtpConfig.RemoteOptions = append(
-			srtpConfig.RemoteOptions,
-			srtp.SRTPReplayProtection(*t.api.settingEngine.replayProtection.SRTP),
-		)
-	}
-
-	if t.api.settingEngine.disableSRTPReplayProtection {
-		srtpConfig.RemoteOptions = append(
-			srtpConfig.RemoteOptions,
-			srtp.SRTPNoReplayProtection(),
-		)
-	}
-
-	if t.api.settingEngine.replayProtection.SRTCP != nil {
-		srtpConfig.RemoteOptions = append(
-			srtpConfig.RemoteOptions,
-			srtp.SRTCPReplayProtection(*t.api.settingEngine.replayProtection.SRTCP),
-		)
-	}
-
-	if t.api.settingEngine.disableSRTCPReplayProtection {
-		srtpConfig.RemoteOptions = append(
-			srtpConfig.RemoteOptions,
-			srtp.SRTCPNoReplayProtection(),
-		)
+	profile, err := srtpProtectionProfile(t.negotiatedSRTPProtectionProfile)
+	if err != nil {
+		return err
 	}
 
 	connState, ok := t.conn.ConnectionState()
@@ -225,24 +292,62 @@ func (t *DTLSTransport) startSRTP() error {
 		return fmt.Errorf("%w: Failed to get DTLS ConnectionState", errDtlsKeyExtractionFailed)
 	}
 
-	err := srtpConfig.ExtractSessionKeysFromDTLS(&connState, t.role() == DTLSRoleClient)
+	keys := &srtp.Config{Profile: profile}
+	err = keys.ExtractSessionKeysFromDTLS(&connState, t.role() == DTLSRoleClient)
 	if err != nil {
 		// nolint
 		return fmt.Errorf("%w: %v", errDtlsKeyExtractionFailed, err)
 	}
 
-	srtpSession, err := srtp.NewSessionSRTP(t.srtpEndpoint, srtpConfig)
+	srtpConfig := SRTPConfig{
+		Profile:          t.negotiatedSRTPProtectionProfile,
+		LocalMasterKey:   keys.Keys.LocalMasterKey,
+		LocalMasterSalt:  keys.Keys.LocalMasterSalt,
+		RemoteMasterKey:  keys.Keys.RemoteMasterKey,
+		RemoteMasterSalt: keys.Keys.RemoteMasterSalt,
+		SRTPReplayProtection: SRTPReplayProtection{
+			Disabled: t.api.settingEngine.disableSRTPReplayProtection,
+		},
+		SRTCPReplayProtection: SRTPReplayProtection{
+			Disabled: t.api.settingEngine.disableSRTCPReplayProtection,
+		},
+	}
+	if t.api.settingEngine.replayProtection.SRTP != nil {
+		srtpConfig.SRTPReplayProtection.Window = *t.api.settingEngine.replayProtection.SRTP
+	}
+	if t.api.settingEngine.replayProtection.SRTCP != nil {
+		srtpConfig.SRTCPReplayProtection.Window = *t.api.settingEngine.replayProtection.SRTCP
+	}
+
+	provider := t.api.settingEngine.srtpProvider
+	if provider == nil {
+		provider = pionSRTPProvider{
+			bufferFactory: t.api.settingEngine.BufferFactory,
+			loggerFactory: t.api.settingEngine.LoggerFactory,
+		}
+	}
+
+	var srtpConn net.Conn = t.srtpEndpoint
+	if t.api.settingEngine.recordArrivals() {
+		srtpConn = &arrivalRecordingConn{t.srtpEndpoint, t.arrivals}
+	}
+
+	srtpSession, err := provider.NewSessionSRTP(srtpConn, srtpConfig)
 	if err != nil {
 		// nolint
 		return fmt.Errorf("%w: %v", errFailedToStartSRTP, err)
 	}
 
-	srtcpSession, err := srtp.NewSessionSRTCP(t.srtcpEndpoint, srtpConfig)
+	srtcpSession, err := provider.NewSessionSRTCP(t.srtcpEndpoint, srtpConfig)
 	if err != nil {
 		// nolint
 		return fmt.Errorf("%w: %v", errFailedToStartSRTCP, err)
 	}
 
+	if t.api.settingEngine.srtpKeyUsageLimits.enabled {
+		srtpSession = &keyUsageSRTPSession{srtpSession, t.keyUsage}
+		srtcpSession = &keyUsageSRTCPSession{srtcpSession, t.keyUsage}
+	}
 	t.srtpSession.Store(srtpSession)
 	t.srtcpSession.Store(srtcpSession)
 	close(t.srtpReady)
@@ -250,16 +355,16 @@ func (t *DTLSTransport) startSRTP() error {
 	return nil
 }
 
-func (t *DTLSTransport) getSRTPSession() (*srtp.SessionSRTP, error) {
-	if value, ok := t.srtpSession.Load().(*srtp.SessionSRTP); ok {
+func (t *DTLSTransport) getSRTPSession() (SRTPSession, error) {
+	if value, ok := t.srtpSession.Load().(SRTPSession); ok {
 		return value, nil
 	}
 
 	return nil, errDtlsTransportNotStarted
 }
 
-func (t *DTLSTransport) getSRTCPSession() (*srtp.SessionSRTCP, error) {
-	if value, ok := t.srtcpSession.Load().(*srtp.SessionSRTCP); ok {
+func (t *DTLSTransport) getSRTCPSession() (SRTCPSession, error) {
+	if value, ok := t.srtcpSession.Load().(SRTCPSession); ok {
 		return value, nil
 	}
 
@@ -293,6 +398,19 @@ func (t *DTLSTransport) role() DTLSRole {
 	return defaultDtlsRoleAnswer
 }
 
+// handshakeContext returns the context bounding the DTLS handshake, see
+// SettingEngine.SetDTLSConnectContextMaker and SetDTLSHandshakeTimeout.
+func (t *DTLSTransport) handshakeContext() (context.Context, func()) {
+	switch {
+	case t.api.settingEngine.dtls.connectContextMaker != nil:
+		return t.api.settingEngine.dtls.connectContextMaker()
+	case t.api.settingEngine.dtls.handshakeTimeout > 0:
+		return context.WithTimeout(context.Background(), t.api.settingEngine.dtls.handshakeTimeout)
+	default:
+		return context.Background(), func() {}
+	}
+}
+
 // Start DTLS transport negotiation with the parameters of the remote DTLS transport.
 func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:gocognit,cyclop
 	// Take lock and prepare connection, we must not hold the lock
@@ -363,6 +481,7 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 	dtlsConfig.ClientHelloMessageHook = t.api.settingEngine.dtls.clientHelloMessageHook
 	dtlsConfig.ServerHelloMessageHook = t.api.settingEngine.dtls.serverHelloMessageHook
 	dtlsConfig.CertificateRequestMessageHook = t.api.settingEngine.dtls.certificateRequestMessageHook
+	dtlsConfig.MTU = t.api.settingEngine.dtls.mtu
 
 	// Connect as DTLS Client/Server, function is blocking and we
 	// must not hold the DTLSTransport lock
@@ -373,12 +492,9 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 	}
 
 	if err == nil {
-		if t.api.settingEngine.dtls.connectContextMaker != nil {
-			handshakeCtx, _ := t.api.settingEngine.dtls.connectContextMaker()
-			err = dtlsConn.HandshakeContext(handshakeCtx)
-		} else {
-			err = dtlsConn.Handshake()
-		}
+		handshakeCtx, cancel := t.handshakeContext()
+		err = dtlsConn.HandshakeContext(handshakeCtx)
+		cancel()
 	}
 
 	// Re-take the lock, nothing beyond here is blocking
@@ -398,20 +514,12 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 		return ErrNoSRTPProtectionProfile
 	}
 
-	switch srtpProfile {
-	case dtls.SRTP_AEAD_AES_128_GCM:
-		t.srtpProtectionProfile = srtp.ProtectionProfileAeadAes128Gcm
-	case dtls.SRTP_AEAD_AES_256_GCM:
-		t.srtpProtectionProfile = srtp.ProtectionProfileAeadAes256Gcm
-	case dtls.SRTP_AES128_CM_HMAC_SHA1_80:
-		t.srtpProtectionProfile = srtp.ProtectionProfileAes128CmHmacSha1_80
-	case dtls.SRTP_NULL_HMAC_SHA1_80:
-		t.srtpProtectionProfile = srtp.ProtectionProfileNullHmacSha1_80
-	default:
+	if _, err = srtpProtectionProfile(srtpProfile); err != nil {
 		t.onStateChange(DTLSTransportStateFailed)
 
-		return ErrNoSRTPProtectionProfile
+		return err
 	}
+	t.negotiatedSRTPProtectionProfile = srtpProfile
 
 	// Check the fingerprint if a certificate was exchanged
 	connectionState, ok := dtlsConn.ConnectionState()
@@ -518,8 +626,8 @@ func (t *DTLSTransport) ensureICEConn() error {
 }
 
 func (t *DTLSTransport) storeSimulcastStream(
-	srtpReadStream *srtp.ReadStreamSRTP,
-	srtcpReadStream *srtp.ReadStreamSRTCP,
+	srtpReadStream SRTPReadStream,
+	srtcpReadStream SRTPReadStream,
 ) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -530,7 +638,7 @@ func (t *DTLSTransport) storeSimulcastStream(
 func (t *DTLSTransport) streamsForSSRC(
 	ssrc SSRC,
 	streamInfo interceptor.StreamInfo,
-) (*srtp.ReadStreamSRTP, interceptor.RTPReader, *srtp.ReadStreamSRTCP, interceptor.RTCPReader, error) {
+) (SRTPReadStream, interceptor.RTPReader, SRTPReadStream, interceptor.RTCPReader, error) {
 	srtpSession, err := t.getSRTPSession()
 	if err != nil {
 		return nil, nil, nil, nil, err
@@ -546,6 +654,9 @@ func (t *DTLSTransport) streamsForSSRC(
 		interceptor.RTPReaderFunc(
 			func(in []byte, a interceptor.Attributes) (n int, attributes interceptor.Attributes, err error) {
 				n, err = rtpReadStream.Read(in)
+				if err == nil {
+					a = setPacketMetadataAttributes(in[:n], a, t.arrivals, t.api.settingEngine.metadataPacketConn)
+				}
 
 				return n, a, err
 			},
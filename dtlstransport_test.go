@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pion/dtls/v3"
 	"github.com/pion/transport/v3/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -146,3 +147,38 @@ func TestPeerConnection_DTLSRoleSettingEngine(t *testing.T) {
 		runTest(DTLSRoleClient)
 	})
 }
+
+func TestPeerConnection_SRTPProtectionProfile(t *testing.T) {
+	report := test.CheckRoutines(t)
+	defer report()
+
+	offerSettingEngine := SettingEngine{}
+	offerSettingEngine.SetSRTPProtectionProfiles(dtls.SRTP_AEAD_AES_128_GCM)
+
+	offerPC, err := NewAPI(WithSettingEngine(offerSettingEngine)).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	answerPC, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, dtls.SRTPProtectionProfile(0), offerPC.SCTP().Transport().SRTPProtectionProfile())
+
+	_, err = offerPC.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	connectionComplete := untilConnectionState(PeerConnectionStateConnected, offerPC, answerPC)
+	assert.NoError(t, signalPair(offerPC, answerPC))
+	connectionComplete.Wait()
+
+	for _, pc := range []*PeerConnection{offerPC, answerPC} {
+		assert.Equal(t, dtls.SRTP_AEAD_AES_128_GCM, pc.SCTP().Transport().SRTPProtectionProfile())
+
+		stats, ok := pc.GetStats()["iceTransport"].(TransportStats)
+		assert.True(t, ok)
+		assert.Equal(t, "SRTP_AEAD_AES_128_GCM", stats.SRTPCipher)
+		assert.Equal(t, DTLSTransportStateConnected, stats.DTLSState)
+		assert.NotEmpty(t, stats.DTLSCipher)
+	}
+
+	closePairNow(t, offerPC, answerPC)
+}
@@ -37,6 +37,10 @@ var (
 	// chosen to generate a certificate is not supported.
 	ErrPrivateKeyType = errors.New("private key type not supported")
 
+	// ErrPrivateKeyNotExportable indicates that the private key of a certificate
+	// is held by an external signer and can't be serialized.
+	ErrPrivateKeyNotExportable = errors.New("private key is not exportable")
+
 	// ErrModifyingPeerIdentity indicates that an attempt to modify
 	// PeerIdentity was made after PeerConnection has been initialized.
 	ErrModifyingPeerIdentity = errors.New("peerIdentity cannot be modified")
@@ -57,6 +61,15 @@ var (
 	// ICECandidatePoolSize was made after PeerConnection has been initialized.
 	ErrModifyingICECandidatePoolSize = errors.New("ice candidate pool size cannot be modified")
 
+	// ErrModifyingICEMulticastDNS indicates that an attempt to modify
+	// ICEMulticastDNSMode or ICEMulticastDNSInterfaces was made after
+	// PeerConnection has been initialized.
+	ErrModifyingICEMulticastDNS = errors.New("ice multicast dns settings cannot be modified")
+
+	// ErrModifyingLatencyMode indicates that an attempt to modify LatencyMode
+	// was made after PeerConnection has been initialized.
+	ErrModifyingLatencyMode = errors.New("latency mode cannot be modified")
+
 	// ErrStringSizeLimit indicates that the character size limit of string is
 	// exceeded. The limit is hardcoded to 65535 according to specifications.
 	ErrStringSizeLimit = errors.New("data channel label exceeds size limit")
@@ -90,6 +103,10 @@ var (
 	// ErrIncorrectSignalingState indicates that the signaling state of PeerConnection is not correct.
 	ErrIncorrectSignalingState = errors.New("operation can not be run in current signaling state")
 
+	// ErrDataChannelMessageTooLarge indicates that a message passed to the Send
+	// methods of a DataChannel is larger than SCTPTransport.MaxMessageSize.
+	ErrDataChannelMessageTooLarge = errors.New("data channel message is larger than the max message size")
+
 	// ErrProtocolTooLarge indicates that value given for a DataChannelInit protocol is
 	// longer then 65535 bytes.
 	ErrProtocolTooLarge = errors.New("protocol is larger then 65535 bytes")
@@ -135,6 +152,10 @@ var (
 	// ErrNoSRTPProtectionProfile indicates that the DTLS handshake completed and no SRTP Protection Profile was chosen.
 	ErrNoSRTPProtectionProfile = errors.New("DTLS Handshake completed and no SRTP Protection Profile was chosen")
 
+	// ErrSRTPKeyUsageLimitReached indicates that the SRTP master key protected as many
+	// packets of an SSRC as allowed, no more packets of it are sent until DTLS is re-established.
+	ErrSRTPKeyUsageLimitReached = errors.New("SRTP master key usage limit reached")
+
 	// ErrFailedToGenerateCertificateFingerprint indicates that we failed to generate the fingerprint
 	// used for comparing certificates.
 	ErrFailedToGenerateCertificateFingerprint = errors.New("failed to generate certificate fingerprint")
@@ -142,6 +163,14 @@ var (
 	// ErrNoCodecsAvailable indicates that operation isn't possible because the MediaEngine has no codecs available.
 	ErrNoCodecsAvailable = errors.New("operation failed no codecs are available")
 
+	// ErrMidInUse indicates that the mid suggested in RTPTransceiverInit is
+	// already used by another transceiver of the PeerConnection.
+	ErrMidInUse = errors.New("mid is already in use")
+
+	// ErrMidTooLong indicates that the mid suggested in RTPTransceiverInit
+	// doesn't fit the 16 bytes of the mid RTP header extension.
+	ErrMidTooLong = errors.New("mid is longer than 16 bytes")
+
 	// ErrUnsupportedCodec indicates the remote peer doesn't support the requested codec.
 	ErrUnsupportedCodec = errors.New("unable to start track, codec is not supported by remote")
 
@@ -162,6 +191,9 @@ var (
 	// ErrNoPayloaderForCodec indicates that the requested codec does not have a payloader.
 	ErrNoPayloaderForCodec = errors.New("the requested codec does not have a payloader")
 
+	// ErrNoDepacketizerForCodec indicates that the negotiated codec does not have a depacketizer.
+	ErrNoDepacketizerForCodec = errors.New("the negotiated codec does not have a depacketizer")
+
 	// ErrRegisterHeaderExtensionInvalidDirection indicates that a extension was
 	// registered with a direction besides `sendonly` or `recvonly`.
 	ErrRegisterHeaderExtensionInvalidDirection = errors.New(
@@ -172,6 +204,26 @@ var (
 	// and the requested SSRC was ignored.
 	ErrSimulcastProbeOverflow = errors.New("simulcast probe limit has been reached, new SSRC has been discarded")
 
+	// ErrRemoteDescriptionTooLarge indicates SetRemoteDescription was called with a SessionDescription
+	// larger than SettingEngine.SetMaxRemoteDescriptionSize allows.
+	ErrRemoteDescriptionTooLarge = errors.New("remote description exceeds the maximum size")
+
+	// ErrTooManyMediaSections indicates SetRemoteDescription was called with a SessionDescription
+	// that has more media sections than SettingEngine.SetMaxMediaSections allows.
+	ErrTooManyMediaSections = errors.New("remote description exceeds the maximum number of media sections")
+
+	// ErrTooManyRemoteCandidates indicates a remote candidate was rejected because the
+	// PeerConnection reached SettingEngine.SetMaxRemoteCandidates.
+	ErrTooManyRemoteCandidates = errors.New("maximum number of remote candidates reached")
+
+	// ErrAddICECandidateRateLimited indicates AddICECandidate was called more often than
+	// SettingEngine.SetAddICECandidateRateLimit allows.
+	ErrAddICECandidateRateLimited = errors.New("AddICECandidate rate limit exceeded")
+
+	// ErrPayloadTypeConflict indicates a codec or a remote description uses a payload type
+	// pinned with MediaEngine.PinPayloadType for another codec.
+	ErrPayloadTypeConflict = errors.New("payload type is pinned to another codec")
+
 	errDetachNotEnabled                 = errors.New("enable detaching by calling webrtc.DetachDataChannels()")
 	errDetachBeforeOpened               = errors.New("datachannel not opened yet, try calling Detach from OnOpen")
 	errDtlsTransportNotStarted          = errors.New("the DTLS transport has not started yet")
@@ -181,6 +233,10 @@ var (
 	errInvalidDTLSStart                 = errors.New("attempted to start DTLSTransport that is not in new state")
 	errNoRemoteCertificate              = errors.New("peer didn't provide certificate via DTLS")
 	errIdentityProviderNotImplemented   = errors.New("identity provider is not implemented")
+	errIdentityAssertionInvalid         = errors.New("invalid identity assertion")
+	errIdentityFingerprintMismatch      = errors.New("identity assertion doesn't cover the fingerprint")
+	errIdentityMismatch                 = errors.New("identity assertion is for another identity")
+	errIdentityNotValidated             = errors.New("peer identity is set and the remote description has no identity")
 	errNoMatchingCertificateFingerprint = errors.New("remote certificate does not match any fingerprint")
 
 	errICEConnectionNotStarted        = errors.New("ICE connection not started")
@@ -196,6 +252,11 @@ var (
 
 	errNetworkTypeUnknown = errors.New("unknown network type")
 
+	errMediaEngineOpusNotRegistered  = errors.New("Opus must be registered before RED")
+	errMediaEngineNoFreePayloadType  = errors.New("no dynamic payload type is free")
+	errMediaEngineOpusNotFound       = errors.New("Opus is not registered")
+	errMediaEngineCodecNotRegistered = errors.New("codec is not registered")
+
 	errSDPDoesNotMatchOffer        = errors.New("new sdp does not match previous offer")
 	errSDPDoesNotMatchAnswer       = errors.New("new sdp does not match previous answer")
 	errPeerConnSDPTypeInvalidValue = errors.New(
@@ -234,20 +295,30 @@ var (
 	errRTPReceiverWithSSRCTrackStreamNotFound = errors.New("unable to find stream for Track with SSRC")
 	errRTPReceiverForRIDTrackStreamNotFound   = errors.New("no trackStreams found for RID")
 
-	errRTPSenderTrackNil             = errors.New("Track must not be nil")
-	errRTPSenderDTLSTransportNil     = errors.New("DTLSTransport must not be nil")
-	errRTPSenderSendAlreadyCalled    = errors.New("Send has already been called")
-	errRTPSenderStopped              = errors.New("Sender has already been stopped")
-	errRTPSenderTrackRemoved         = errors.New("Sender Track has been removed or replaced to nil")
-	errRTPSenderRidNil               = errors.New("Sender cannot add encoding as rid is empty")
-	errRTPSenderNoBaseEncoding       = errors.New("Sender cannot add encoding as there is no base track")
-	errRTPSenderBaseEncodingMismatch = errors.New("Sender cannot add encoding as provided track does not match base track")
-	errRTPSenderRIDCollision         = errors.New("Sender cannot encoding due to RID collision")
-	errRTPSenderNoTrackForRID        = errors.New("Sender does not have track for RID")
+	errTrackRemoteNoSSRC = errors.New("TrackRemote has no SSRC yet")
+
+	errSampleTooManyCSRC = errors.New("a Sample has at most 15 CSRCs")
+
+	errRTPSenderTrackNil              = errors.New("Track must not be nil")
+	errRTPSenderDTLSTransportNil      = errors.New("DTLSTransport must not be nil")
+	errRTPSenderSendAlreadyCalled     = errors.New("Send has already been called")
+	errRTPSenderStopped               = errors.New("Sender has already been stopped")
+	errRTPSenderTrackRemoved          = errors.New("Sender Track has been removed or replaced to nil")
+	errRTPSenderRidNil                = errors.New("Sender cannot add encoding as rid is empty")
+	errRTPSenderNoBaseEncoding        = errors.New("Sender cannot add encoding as there is no base track")
+	errRTPSenderBaseEncodingMismatch  = errors.New("Sender cannot add encoding as provided track does not match base track")
+	errRTPSenderRIDCollision          = errors.New("Sender cannot encoding due to RID collision")
+	errRTPSenderNoTrackForRID         = errors.New("Sender does not have track for RID")
+	errRTPSenderEncodingsModified     = errors.New("Sender encodings can only change Active, MaxBitrate and ScaleResolutionDownBy")
+	errRTPSenderScaleResolutionDownBy = errors.New("Sender ScaleResolutionDownBy must be at least 1.0")
+	errRTPSenderRIDNotInSendEncodings = errors.New("Sender cannot add encoding as rid is not in SendEncodings")
+	errInvalidScalabilityMode         = errors.New("invalid scalability mode")
+	errPacedQueueFull                 = errors.New("Sender pacing queue is full")
 
 	errRTPTransceiverCannotChangeMid        = errors.New("errRTPSenderTrackNil")
 	errRTPTransceiverSetSendingInvalidState = errors.New("invalid state change in RTPTransceiver.setSending")
 	errRTPTransceiverCodecUnsupported       = errors.New("unsupported codec type by this transceiver")
+	errRTPTransceiverCodecsOnlyResiliency   = errors.New("codec preferences only contain resiliency codecs")
 
 	errSCTPTransportDTLS = errors.New("DTLS not established")
 
@@ -272,9 +343,24 @@ var (
 	errICETransportNotInNew = errors.New("ICETransport can only be called in ICETransportStateNew")
 	errICETransportClosed   = errors.New("ICETransport closed")
 
+	errICECandidatePairNotFound          = errors.New("candidate pair not found")
+	errICECandidatePairCandidateNotFound = errors.New("candidate of candidate pair not found")
+
+	errICESOCKS5AuthMethod      = errors.New("SOCKS5 proxy doesn't accept the authentication method")
+	errICESOCKS5AuthFailed      = errors.New("SOCKS5 proxy authentication failed")
+	errICESOCKS5AssociateFailed = errors.New("SOCKS5 proxy refused UDP ASSOCIATE")
+	errICESOCKS5AddressType     = errors.New("unsupported SOCKS5 address type")
+
 	errCertificatePEMFormatError = errors.New("bad Certificate PEM format")
 
 	errRTPTooShort = errors.New("not long enough to be a RTP Packet")
 
 	errExcessiveRetries = errors.New("excessive retries in CreateOffer")
+
+	errFeatureFlagsMalformed = errors.New("malformed feature flags")
+
+	errAPIConfigMalformed          = errors.New("malformed API config")
+	errAPIConfigInvalidCodec       = errors.New("API config codec needs an audio or video mimeType and a clockRate")
+	errAPIConfigUnknownInterceptor = errors.New("unknown interceptor in API config")
+	errAPIConfigUnknownLogLevel    = errors.New("unknown log level in API config")
 )
@@ -66,26 +66,18 @@ func main() { //nolint:gocognit,cyclop,maintidx
 	// Create a Congestion Controller. This analyzes inbound and outbound data and provides
 	// suggestions on how much we should be sending.
 	//
-	// Passing `nil` means we use the default Estimation Algorithm which is Google Congestion Control.
+	// We use Google Congestion Control as the Estimation Algorithm.
 	// You can use the other ones that Pion provides, or write your own!
-	congestionController, err := cc.NewInterceptor(func() (cc.BandwidthEstimator, error) {
-		return gcc.NewSendSideBWE(gcc.SendSideBWEInitialBitrate(lowBitrate))
-	})
-	if err != nil {
-		panic(err)
-	}
-
-	estimatorChan := make(chan cc.BandwidthEstimator, 1)
-	congestionController.OnNewPeerConnection(func(id string, estimator cc.BandwidthEstimator) { //nolint: revive
-		estimatorChan <- estimator
-	})
-
-	interceptorRegistry.Add(congestionController)
-	if err = webrtc.ConfigureTWCCHeaderExtensionSender(mediaEngine, interceptorRegistry); err != nil {
+	if err := webrtc.ConfigureCongestionController(
+		mediaEngine, interceptorRegistry,
+		func() (cc.BandwidthEstimator, error) {
+			return gcc.NewSendSideBWE(gcc.SendSideBWEInitialBitrate(lowBitrate))
+		},
+	); err != nil {
 		panic(err)
 	}
 
-	if err = webrtc.RegisterDefaultInterceptors(mediaEngine, interceptorRegistry); err != nil {
+	if err := webrtc.RegisterDefaultInterceptors(mediaEngine, interceptorRegistry); err != nil {
 		panic(err)
 	}
 
@@ -108,8 +100,8 @@ func main() { //nolint:gocognit,cyclop,maintidx
 		}
 	}()
 
-	// Wait until our Bandwidth Estimator has been created
-	estimator := <-estimatorChan
+	// The Bandwidth Estimator was created with the PeerConnection
+	estimator := peerConnection.BandwidthEstimator()
 
 	// Create a video track
 	videoTrack, err := webrtc.NewTrackLocalStaticSample(
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// FeatureFlagEnabled is the value of a FeatureFlags entry that turns the feature on.
+	FeatureFlagEnabled = "Enabled"
+	// FeatureFlagDisabled is the value of a FeatureFlags entry that turns the feature off.
+	FeatureFlagDisabled = "Disabled"
+
+	// FeatureFlagL4S enables the experimental L4S support of a PeerConnection.
+	// RFC 8888 congestion control feedback is negotiated and generated, if it
+	// isn't configured already, and the RTP packets it sends are marked with
+	// ECNECT1 when the ICEUDPMux reads from the MetadataPacketConn of the
+	// SettingEngine. The flag is read when the PeerConnection is created.
+	FeatureFlagL4S = "WebRTC-L4S"
+)
+
+// FeatureFlags toggles experimental behaviors of a PeerConnection at runtime,
+// similar to field trials in libwebrtc. Each entry maps a flag name to its
+// value, a flag is enabled if its value is FeatureFlagEnabled or starts with
+// it ("Enabled,param:1" style values carry parameters).
+//
+// Flags configured via SettingEngine.SetFeatureFlags apply to every
+// PeerConnection of an API and are overridden by Configuration.FeatureFlags.
+type FeatureFlags map[string]string
+
+// ParseFeatureFlags parses flags in the libwebrtc field trial string format
+// of alternating names and values separated by '/',
+// e.g. "WebRTC-Foo/Enabled/WebRTC-Bar/Disabled/".
+func ParseFeatureFlags(trials string) (FeatureFlags, error) {
+	flags := FeatureFlags{}
+	if trials == "" {
+		return flags, nil
+	}
+
+	parts := strings.Split(strings.TrimSuffix(trials, "/"), "/")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("%w: %s", errFeatureFlagsMalformed, trials)
+	}
+
+	for i := 0; i < len(parts); i += 2 {
+		if parts[i] == "" {
+			return nil, fmt.Errorf("%w: empty flag name", errFeatureFlagsMalformed)
+		}
+		flags[parts[i]] = parts[i+1]
+	}
+
+	return flags, nil
+}
+
+// IsEnabled returns true if the flag is present and enabled.
+func (f FeatureFlags) IsEnabled(name string) bool {
+	return strings.HasPrefix(f[name], FeatureFlagEnabled)
+}
+
+// Value returns the raw value of the flag and whether it was set.
+func (f FeatureFlags) Value(name string) (string, bool) {
+	value, ok := f[name]
+
+	return value, ok
+}
+
+// String returns the flags in the field trial string format accepted by
+// ParseFeatureFlags. Flags are sorted by name.
+func (f FeatureFlags) String() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		builder.WriteString(name)
+		builder.WriteByte('/')
+		builder.WriteString(f[name])
+		builder.WriteByte('/')
+	}
+
+	return builder.String()
+}
+
+// merge returns a copy of f with the entries of overrides applied on top.
+func (f FeatureFlags) merge(overrides FeatureFlags) FeatureFlags {
+	merged := make(FeatureFlags, len(f)+len(overrides))
+	for name, value := range f {
+		merged[name] = value
+	}
+	for name, value := range overrides {
+		merged[name] = value
+	}
+
+	return merged
+}
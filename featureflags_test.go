@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFeatureFlags(t *testing.T) {
+	flags, err := ParseFeatureFlags("WebRTC-Foo/Enabled/WebRTC-Bar/Disabled/WebRTC-Baz/Enabled,limit:5/")
+	assert.NoError(t, err)
+	assert.True(t, flags.IsEnabled("WebRTC-Foo"))
+	assert.False(t, flags.IsEnabled("WebRTC-Bar"))
+	assert.True(t, flags.IsEnabled("WebRTC-Baz"))
+	assert.False(t, flags.IsEnabled("WebRTC-Unknown"))
+
+	value, ok := flags.Value("WebRTC-Baz")
+	assert.True(t, ok)
+	assert.Equal(t, "Enabled,limit:5", value)
+
+	assert.Equal(t, "WebRTC-Bar/Disabled/WebRTC-Baz/Enabled,limit:5/WebRTC-Foo/Enabled/", flags.String())
+
+	flags, err = ParseFeatureFlags("")
+	assert.NoError(t, err)
+	assert.Empty(t, flags)
+
+	_, err = ParseFeatureFlags("WebRTC-Foo/Enabled/WebRTC-Bar/")
+	assert.ErrorIs(t, err, errFeatureFlagsMalformed)
+
+	_, err = ParseFeatureFlags("/Enabled/")
+	assert.ErrorIs(t, err, errFeatureFlagsMalformed)
+}
+
+func TestFeatureFlagsMerge(t *testing.T) {
+	base := FeatureFlags{"a": FeatureFlagEnabled, "b": FeatureFlagEnabled}
+	merged := base.merge(FeatureFlags{"b": FeatureFlagDisabled, "c": FeatureFlagEnabled})
+
+	assert.Equal(t, FeatureFlags{"a": FeatureFlagEnabled, "b": FeatureFlagDisabled, "c": FeatureFlagEnabled}, merged)
+	assert.Equal(t, FeatureFlags{"a": FeatureFlagEnabled, "b": FeatureFlagEnabled}, base)
+
+	var empty FeatureFlags
+	assert.False(t, empty.merge(nil).IsEnabled("a"))
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"time"
+)
+
+// FrameSendReport describes how a media.Sample written to a
+// TrackLocalStaticSample was sent, see TrackLocalStaticSample.OnFrameSent.
+type FrameSendReport struct {
+	// Timestamp is the RTP timestamp of the packets of the sample.
+	Timestamp uint32
+
+	// Size is the length of the sample data in bytes.
+	Size int
+
+	// Packets is the number of RTP packets the sample was split into.
+	Packets int
+
+	// PayloadBytes is the sum of the RTP payload lengths of the packets.
+	PayloadBytes int
+
+	// WriteTime is when WriteSample was called.
+	WriteTime time.Time
+
+	// PacingDelay is the time from WriteTime until the first packet was
+	// written. The packets aren't paced, so it is the time spent packetizing
+	// the sample.
+	PacingDelay time.Duration
+
+	// SendDuration is the time from the first packet being written until the
+	// last write returned, for all the PeerConnections the track is bound to.
+	// It grows when the writes block on a congested transport.
+	SendDuration time.Duration
+
+	// BufferedAmount is the highest number of bytes waiting to be sent by the
+	// transports of the track when its first packet was written, see
+	// TrackLocalBufferedWriter.
+	BufferedAmount uint64
+
+	// Err is the error WriteSample returns.
+	Err error
+}
+
+// OnFrameSent sets a handler that is called with a FrameSendReport for every
+// media.Sample written with WriteSample, so encoding pipelines can correlate
+// the encoder output with the network. It is called from WriteSample before
+// it returns, so it must not block. Samples written before the track is bound
+// aren't reported.
+func (s *TrackLocalStaticSample) OnFrameSent(f func(FrameSendReport)) {
+	s.rtpTrack.mu.Lock()
+	defer s.rtpTrack.mu.Unlock()
+
+	s.onFrameSentHandler = f
+}
+
+// bufferedAmount returns the highest buffered amount of the transports of the bindings.
+func (s *TrackLocalStaticRTP) bufferedAmount() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var bufferedAmount uint64
+	for _, b := range s.bindings {
+		if bufferedWriter, ok := b.writeStream.(TrackLocalBufferedWriter); ok {
+			if amount := bufferedWriter.BufferedAmount(); amount > bufferedAmount {
+				bufferedAmount = amount
+			}
+		}
+	}
+
+	return bufferedAmount
+}
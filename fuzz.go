@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build fuzz && !js
+// +build fuzz,!js
+
+package webrtc
+
+import (
+	"encoding/binary"
+
+	"github.com/pion/datachannel"
+	"github.com/pion/logging"
+)
+
+// The Fuzz* functions below expose the parsers that handle data received from
+// the network to go-fuzz style fuzzers like OSS-Fuzz. They are only built with
+// the fuzz build tag. Each returns 1 if data was accepted by the parser, so
+// the fuzzer can prioritize it, and 0 otherwise. Seed corpora for each of
+// them live in testdata/fuzz/corpus.
+
+// Extension IDs FuzzRTPDemux looks up the MID, RID and repaired RID in.
+const (
+	FuzzMidExtensionID            = 1
+	FuzzStreamIDExtensionID       = 2
+	FuzzRepairStreamIDExtensionID = 3
+)
+
+const (
+	fuzzDataChannelStreamID = 1
+
+	dataChannelOpenMessageType    = 0x03
+	dataChannelOpenHeaderLength   = 12
+	dataChannelOpenChannelTypeIdx = 1
+	dataChannelOpenReliabilityIdx = 4
+	dataChannelOpenLabelLengthIdx = 8
+	dataChannelOpenProtoLengthIdx = 10
+)
+
+func newFuzzLogger() logging.LeveledLogger {
+	loggerFactory := logging.NewDefaultLoggerFactory()
+	loggerFactory.DefaultLogLevel = logging.LogLevelDisabled
+
+	return loggerFactory.NewLogger("fuzz")
+}
+
+// FuzzRemoteDescription parses data as the SDP of a remote offer and runs it
+// through the inspection SetRemoteDescription does before it applies it:
+// tracks, ICE and DTLS details, BUNDLE, directions, codecs and header
+// extensions.
+func FuzzRemoteDescription(data []byte) int {
+	log := newFuzzLogger()
+
+	desc := &SessionDescription{Type: SDPTypeOffer, SDP: string(data)}
+	parsed, err := desc.Unmarshal()
+	if err != nil {
+		return 0
+	}
+
+	_ = descriptionIsPlanB(desc, log)
+	_ = trackDetailsFromSDP(log, parsed)
+	_, _ = extractICEDetails(parsed, log)
+	_, _, _ = extractFingerprint(parsed)
+	_ = extractBundleID(parsed)
+	_ = haveDataChannel(desc)
+
+	for _, media := range parsed.MediaDescriptions {
+		_ = getMidValue(media)
+		_ = getPeerDirection(media)
+		_ = mediaSectionHasRTX(media)
+		_, _ = codecsFromMediaDescription(media)
+		_, _ = rtpExtensionsFromMediaDescription(media)
+	}
+
+	mediaEngine := &MediaEngine{}
+	if err = mediaEngine.RegisterDefaultCodecs(); err != nil {
+		panic(err)
+	}
+	_ = mediaEngine.updateFromRemoteDescription(*parsed)
+
+	return 1
+}
+
+// FuzzRTPDemux runs data through the demuxing decision made for the first
+// packet of an SSRC that was not signaled, with the MID, RID and repaired RID
+// header extensions negotiated as FuzzMidExtensionID,
+// FuzzStreamIDExtensionID and FuzzRepairStreamIDExtensionID.
+func FuzzRTPDemux(data []byte) int {
+	var mid, rid, rsid string
+	if _, _, err := handleUnknownRTPPacket(
+		data,
+		FuzzMidExtensionID,
+		FuzzStreamIDExtensionID,
+		FuzzRepairStreamIDExtensionID,
+		&mid, &rid, &rsid,
+	); err != nil {
+		return 0
+	}
+
+	return 1
+}
+
+// FuzzDataChannelOpen parses data as a DCEP message. DATA_CHANNEL_OPEN
+// messages are also mapped to the DataChannel a remotely opened channel is
+// accepted with.
+func FuzzDataChannelOpen(data []byte) int {
+	if datachannel.TryMarshalUnmarshal(data) == 0 {
+		return 0
+	}
+
+	config, ok := parseFuzzDataChannelOpen(data)
+	if !ok {
+		return 1
+	}
+
+	api := NewAPI()
+	if _, err := api.newDataChannel(
+		dataChannelParametersFromConfig(config, fuzzDataChannelStreamID),
+		nil,
+		newFuzzLogger(),
+	); err != nil {
+		return 0
+	}
+
+	return 1
+}
+
+// parseFuzzDataChannelOpen decodes a DATA_CHANNEL_OPEN message, RFC 8832 Section 5.1.
+func parseFuzzDataChannelOpen(data []byte) (*datachannel.Config, bool) {
+	if len(data) < dataChannelOpenHeaderLength || data[0] != dataChannelOpenMessageType {
+		return nil, false
+	}
+
+	labelLength := int(binary.BigEndian.Uint16(data[dataChannelOpenLabelLengthIdx:]))
+	protocolLength := int(binary.BigEndian.Uint16(data[dataChannelOpenProtoLengthIdx:]))
+	if len(data) < dataChannelOpenHeaderLength+labelLength+protocolLength {
+		return nil, false
+	}
+
+	label := data[dataChannelOpenHeaderLength : dataChannelOpenHeaderLength+labelLength]
+	protocol := data[dataChannelOpenHeaderLength+labelLength : dataChannelOpenHeaderLength+labelLength+protocolLength]
+
+	return &datachannel.Config{
+		ChannelType:          datachannel.ChannelType(data[dataChannelOpenChannelTypeIdx]),
+		ReliabilityParameter: binary.BigEndian.Uint32(data[dataChannelOpenReliabilityIdx:]),
+		Label:                string(label),
+		Protocol:             string(protocol),
+	}, true
+}
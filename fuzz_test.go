@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build fuzz && !js
+// +build fuzz,!js
+
+package webrtc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func addFuzzCorpus(f *testing.F, target string) {
+	f.Helper()
+
+	seeds, err := filepath.Glob(filepath.Join("testdata", "fuzz", "corpus", target, "*"))
+	assert.NoError(f, err)
+	assert.NotEmpty(f, seeds)
+
+	for _, seed := range seeds {
+		data, err := os.ReadFile(seed) //nolint:gosec
+		assert.NoError(f, err)
+		f.Add(data)
+	}
+}
+
+func Fuzz_RemoteDescription(f *testing.F) {
+	addFuzzCorpus(f, "remotedescription")
+	f.Fuzz(func(_ *testing.T, data []byte) {
+		FuzzRemoteDescription(data)
+	})
+}
+
+func Fuzz_RTPDemux(f *testing.F) {
+	addFuzzCorpus(f, "rtpdemux")
+	f.Fuzz(func(_ *testing.T, data []byte) {
+		FuzzRTPDemux(data)
+	})
+}
+
+func Fuzz_DataChannelOpen(f *testing.F) {
+	addFuzzCorpus(f, "datachannelopen")
+	f.Fuzz(func(_ *testing.T, data []byte) {
+		FuzzDataChannelOpen(data)
+	})
+}
+
+func TestFuzzCorpusAccepted(t *testing.T) {
+	for target, fuzz := range map[string]func([]byte) int{
+		"remotedescription": FuzzRemoteDescription,
+		"rtpdemux":          FuzzRTPDemux,
+		"datachannelopen":   FuzzDataChannelOpen,
+	} {
+		seeds, err := filepath.Glob(filepath.Join("testdata", "fuzz", "corpus", target, "*"))
+		assert.NoError(t, err)
+
+		for _, seed := range seeds {
+			data, err := os.ReadFile(seed) //nolint:gosec
+			assert.NoError(t, err)
+			assert.Equal(t, 1, fuzz(data), seed)
+		}
+	}
+}
@@ -4,21 +4,22 @@ go 1.20
 
 require (
 	github.com/pion/datachannel v1.5.10
-	github.com/pion/dtls/v3 v3.0.4
+	github.com/pion/dtls/v3 v3.0.6
 	github.com/pion/ice/v4 v4.0.6
 	github.com/pion/interceptor v0.1.37
 	github.com/pion/logging v0.2.3
 	github.com/pion/randutil v0.1.0
 	github.com/pion/rtcp v1.2.15
-	github.com/pion/rtp v1.8.11
+	github.com/pion/rtp v1.8.19
 	github.com/pion/sctp v1.8.35
 	github.com/pion/sdp/v3 v3.0.10
-	github.com/pion/srtp/v3 v3.0.4
+	github.com/pion/srtp/v3 v3.0.6
 	github.com/pion/stun/v3 v3.0.0
 	github.com/pion/transport/v3 v3.0.7
 	github.com/sclevine/agouti v3.0.0+incompatible
 	github.com/stretchr/testify v1.10.0
-	golang.org/x/net v0.33.0
+	golang.org/x/net v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -30,7 +31,6 @@ require (
 	github.com/pion/turn/v4 v4.0.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/wlynxg/anet v0.0.5 // indirect
-	golang.org/x/crypto v0.31.0 // indirect
-	golang.org/x/sys v0.28.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/crypto v0.32.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
 )
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
+)
+
+const (
+	// headerExtensionProfileOneByte and headerExtensionProfileTwoByte are the
+	// profiles of RFC 8285, the one-byte form has IDs 1 to 14 and payloads of
+	// at most 16 bytes.
+	headerExtensionProfileOneByte = 0xBEDE
+	headerExtensionProfileTwoByte = 0x1000
+	oneByteHeaderExtensionMaxID   = 14
+	oneByteHeaderExtensionMaxSize = 16
+)
+
+// WithSourceHeaderExtensions sets the header extensions negotiated by the
+// PeerConnection the packets written to the track are received from, usually
+// RTPReceiver.GetParameters().HeaderExtensions. The IDs of the extensions of
+// the written packets are translated to the ones negotiated by each
+// PeerConnection the track is sent to, and the extensions it didn't
+// negotiate are removed, so forwarded packets keep the transport-wide
+// sequence numbers, audio levels and other extensions. Without it the
+// extensions are sent as written.
+//
+// The stream identification extensions (mid, rid and repaired-rid) are
+// removed, as their values are the ones of the receiving PeerConnection.
+func WithSourceHeaderExtensions(extensions []RTPHeaderExtensionParameter) func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.sourceExtensions = headerExtensionURIs(extensions)
+	}
+}
+
+// SetSourceHeaderExtensions replaces the header extensions set with
+// WithSourceHeaderExtensions, when the source is renegotiated or replaced.
+// With nil the extensions are sent as written again.
+func (s *TrackLocalStaticRTP) SetSourceHeaderExtensions(extensions []RTPHeaderExtensionParameter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sourceExtensions = headerExtensionURIs(extensions)
+}
+
+// headerExtensionURIs returns the URIs of extensions by ID, or nil if
+// extensions is nil.
+func headerExtensionURIs(extensions []RTPHeaderExtensionParameter) map[uint8]string {
+	if extensions == nil {
+		return nil
+	}
+
+	uris := make(map[uint8]string, len(extensions))
+	for _, extension := range extensions {
+		uris[uint8(extension.ID)] = extension.URI //nolint:gosec // G115
+	}
+
+	return uris
+}
+
+// headerExtensionIDs returns the IDs of extensions by URI.
+func headerExtensionIDs(extensions []RTPHeaderExtensionParameter) map[string]uint8 {
+	ids := make(map[string]uint8, len(extensions))
+	for _, extension := range extensions {
+		ids[extension.URI] = uint8(extension.ID) //nolint:gosec // G115
+	}
+
+	return ids
+}
+
+// isStreamIDHeaderExtension returns true for the extensions identifying the
+// stream of a packet within its PeerConnection.
+func isStreamIDHeaderExtension(uri string) bool {
+	return uri == sdp.SDESMidURI || uri == sdp.SDESRTPStreamIDURI || uri == sdp.SDESRepairRTPStreamIDURI
+}
+
+// mapHeaderExtensions translates the extensions of header from the IDs of
+// source to the ones negotiated by the binding.
+func (b *trackBinding) mapHeaderExtensions(header *rtp.Header, source map[uint8]string) error {
+	if !header.Extension {
+		return nil
+	}
+
+	type mappedExtension struct {
+		id      uint8
+		payload []byte
+	}
+	mapped := []mappedExtension{}
+	twoByte := header.ExtensionProfile == headerExtensionProfileTwoByte
+	for _, sourceID := range header.GetExtensionIDs() {
+		uri, ok := source[sourceID]
+		if !ok || isStreamIDHeaderExtension(uri) {
+			continue
+		}
+		id := b.headerExtensionIDs[uri]
+		if id == 0 {
+			continue
+		}
+
+		payload := header.GetExtension(sourceID)
+		mapped = append(mapped, mappedExtension{id: id, payload: payload})
+		twoByte = twoByte || id > oneByteHeaderExtensionMaxID || len(payload) > oneByteHeaderExtensionMaxSize
+	}
+
+	header.Extension, header.ExtensionProfile, header.Extensions = false, 0, nil
+	if len(mapped) == 0 {
+		return nil
+	}
+
+	header.Extension, header.ExtensionProfile = true, headerExtensionProfileOneByte
+	if twoByte {
+		header.ExtensionProfile = headerExtensionProfileTwoByte
+	}
+	for _, extension := range mapped {
+		if err := header.SetExtension(extension.id, extension.payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackLocalStaticRTP_SourceHeaderExtensions(t *testing.T) {
+	const transportCCURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
+
+	track, err := NewTrackLocalStaticRTP(
+		RTPCodecCapability{MimeType: MimeTypeOpus, ClockRate: 48000}, "audio", "pion",
+		WithSourceHeaderExtensions([]RTPHeaderExtensionParameter{
+			{URI: sdp.AudioLevelURI, ID: 1},
+			{URI: sdp.SDESMidURI, ID: 4},
+			{URI: transportCCURI, ID: 5},
+			{URI: AbsCaptureTimeURI, ID: 7},
+		}),
+	)
+	require.NoError(t, err)
+
+	bind := func(extensions []RTPHeaderExtensionParameter) *headerTestWriter {
+		writer := &headerTestWriter{}
+		_, bindErr := track.Bind(&baseTrackLocalContext{
+			params: RTPParameters{
+				HeaderExtensions: extensions,
+				Codecs:           []RTPCodecParameters{{RTPCodecCapability: track.Codec(), PayloadType: 111}},
+			},
+			ssrc:        1,
+			writeStream: writer,
+		})
+		require.NoError(t, bindErr)
+
+		return writer
+	}
+	remapped := bind([]RTPHeaderExtensionParameter{
+		{URI: sdp.SDESMidURI, ID: 1},
+		{URI: transportCCURI, ID: 3},
+		{URI: sdp.AudioLevelURI, ID: 10},
+	})
+	twoByte := bind([]RTPHeaderExtensionParameter{{URI: sdp.AudioLevelURI, ID: 20}})
+	none := bind(nil)
+
+	packet := &rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: 1}, Payload: []byte{0x00}}
+	assert.NoError(t, packet.SetExtension(1, []byte{0x85}))
+	assert.NoError(t, packet.SetExtension(4, []byte("0")))
+	assert.NoError(t, packet.SetExtension(5, []byte{0x00, 0x01}))
+	assert.NoError(t, packet.SetExtension(7, []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}))
+	assert.NoError(t, track.WriteRTP(packet))
+
+	// The written packet is unchanged
+	assert.Equal(t, []uint8{1, 4, 5, 7}, packet.GetExtensionIDs())
+
+	require.Len(t, remapped.headers, 1)
+	header := remapped.headers[0]
+	assert.Equal(t, []uint8{10, 3}, header.GetExtensionIDs())
+	assert.Equal(t, []byte{0x85}, header.GetExtension(10))
+	assert.Equal(t, []byte{0x00, 0x01}, header.GetExtension(3))
+	assert.Equal(t, uint16(headerExtensionProfileOneByte), header.ExtensionProfile)
+
+	require.Len(t, twoByte.headers, 1)
+	assert.Equal(t, []byte{0x85}, twoByte.headers[0].GetExtension(20))
+	assert.Equal(t, uint16(headerExtensionProfileTwoByte), twoByte.headers[0].ExtensionProfile)
+
+	require.Len(t, none.headers, 1)
+	assert.False(t, none.headers[0].Extension)
+
+	// Without source extensions the packets are sent as written
+	track.SetSourceHeaderExtensions(nil)
+	assert.NoError(t, track.WriteRTP(packet))
+	assert.Equal(t, []uint8{1, 4, 5, 7}, none.headers[1].GetExtensionIDs())
+}
@@ -10,5 +10,10 @@ package webrtc
 // This constructor is part of the ORTC API. It is not
 // meant to be used together with the basic WebRTC API.
 func (api *API) NewICETransport(gatherer *ICEGatherer) *ICETransport {
-	return NewICETransport(gatherer, api.settingEngine.LoggerFactory)
+	transport := NewICETransport(gatherer, api.settingEngine.LoggerFactory)
+	if api.settingEngine.pacingBitrate > 0 {
+		transport.pacer = newPacer(api.settingEngine.pacingBitrate)
+	}
+
+	return transport
 }
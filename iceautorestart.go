@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+)
+
+// iceAutoRestart schedules ICE restarts while the ICE connection is
+// disconnected or failed, see SettingEngine.SetICEAutoRestart.
+type iceAutoRestart struct {
+	mu      sync.Mutex
+	timer   *time.Timer
+	backoff time.Duration
+	closed  bool
+}
+
+// handleICEConnectionStateChange arms the restart timer when the connection
+// is lost and disarms it once it is back.
+func (r *iceAutoRestart) handleICEConnectionStateChange(pc *PeerConnection, state ICEConnectionState) {
+	initialBackoff := pc.api.settingEngine.iceAutoRestart.initialBackoff
+	if initialBackoff == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch state {
+	case ICEConnectionStateDisconnected, ICEConnectionStateFailed:
+		if r.timer == nil && !r.closed {
+			if r.backoff == 0 {
+				r.backoff = initialBackoff
+			}
+			r.schedule(pc)
+		}
+	case ICEConnectionStateConnected, ICEConnectionStateCompleted:
+		r.stopLocked()
+		r.backoff = initialBackoff
+	default:
+	}
+}
+
+// schedule restarts ICE after the current backoff, and again with a
+// doubled backoff as long as the connection isn't back.
+func (r *iceAutoRestart) schedule(pc *PeerConnection) {
+	r.timer = time.AfterFunc(r.backoff, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if r.closed || pc.isClosed.get() {
+			return
+		}
+
+		switch pc.ICEConnectionState() {
+		case ICEConnectionStateConnected, ICEConnectionStateCompleted:
+			r.timer = nil
+
+			return
+		default:
+		}
+
+		pc.log.Infof("ICE connection lost, restarting ICE (backoff %s)", r.backoff)
+		pc.RestartICE()
+
+		r.backoff *= 2
+		if maxBackoff := pc.api.settingEngine.iceAutoRestart.maxBackoff; maxBackoff != 0 && r.backoff > maxBackoff {
+			r.backoff = maxBackoff
+		}
+		r.schedule(pc)
+	})
+}
+
+func (r *iceAutoRestart) stopLocked() {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+// close stops scheduling restarts, it is called when the PeerConnection is closed.
+func (r *iceAutoRestart) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	r.stopLocked()
+}
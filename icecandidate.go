@@ -5,6 +5,8 @@ package webrtc
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/pion/ice/v4"
 )
@@ -163,6 +165,33 @@ func (c *ICECandidate) setExtensions(ext []ice.CandidateExtension) {
 	c.extensions = extensions
 }
 
+// NetworkID returns the network-id extension of the candidate, it identifies
+// the network interface the candidate was gathered on.
+func (c ICECandidate) NetworkID() (uint16, bool) {
+	return c.uint16Extension("network-id")
+}
+
+// NetworkCost returns the network-cost extension of the candidate, higher
+// values are more costly networks such as cellular ones.
+func (c ICECandidate) NetworkCost() (uint16, bool) {
+	return c.uint16Extension("network-cost")
+}
+
+func (c ICECandidate) uint16Extension(key string) (uint16, bool) {
+	fields := strings.Split(c.extensions, " ")
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] != key {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[i+1], 10, 16)
+
+		return uint16(value), err == nil
+	}
+
+	return 0, false
+}
+
 func (c *ICECandidate) exportExtensions(cand ice.Candidate) error {
 	extensions := c.extensions
 	var ext ice.CandidateExtension
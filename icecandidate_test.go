@@ -354,3 +354,30 @@ func TestICECandidateExtensions_ToJSON(t *testing.T) {
 		assert.Equal(t, cand.extensions, iceBack.Extensions())
 	}
 }
+
+func TestICECandidate_NetworkExtensions(t *testing.T) {
+	candidate, err := ice.UnmarshalCandidate(
+		"2637185494 1 udp 2121932543 192.168.1.4 50723 typ host generation 0 network-id 3 network-cost 900",
+	)
+	assert.NoError(t, err)
+
+	iceCandidate, err := newICECandidateFromICE(candidate, "", 0)
+	assert.NoError(t, err)
+
+	networkID, ok := iceCandidate.NetworkID()
+	assert.True(t, ok)
+	assert.Equal(t, uint16(3), networkID)
+
+	networkCost, ok := iceCandidate.NetworkCost()
+	assert.True(t, ok)
+	assert.Equal(t, uint16(900), networkCost)
+
+	candidate, err = ice.UnmarshalCandidate("2637185494 1 udp 2121932543 192.168.1.4 50723 typ host")
+	assert.NoError(t, err)
+
+	iceCandidate, err = newICECandidateFromICE(candidate, "", 0)
+	assert.NoError(t, err)
+
+	_, ok = iceCandidate.NetworkCost()
+	assert.False(t, ok)
+}
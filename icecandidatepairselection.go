@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pion/ice/v4"
+	"github.com/pion/stun/v3"
+)
+
+// candidatePairSelection holds the pinned pair and the filter set on an
+// ICETransport. The agent can't be told to select a pair directly, they are
+// applied when a connectivity check is received on a pair.
+type candidatePairSelection struct {
+	mu sync.Mutex
+
+	pinned *ICECandidatePair
+	filter func(*ICECandidatePair) bool
+
+	// selectedExcluded is set if the filter rejects the selected pair
+	selectedExcluded bool
+}
+
+func (s *candidatePairSelection) setPinned(pair *ICECandidatePair) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pinned = nil
+	if pair != nil {
+		local, remote := *pair.Local, *pair.Remote
+		s.pinned = NewICECandidatePair(&local, &remote)
+	}
+}
+
+func (s *candidatePairSelection) setFilter(filter func(*ICECandidatePair) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.filter = filter
+}
+
+// selected is called when the agent selected a pair.
+func (s *candidatePairSelection) selected(pair *ICECandidatePair) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.selectedExcluded = s.filter != nil && !s.filter(pair)
+}
+
+// bindingRequestHandler returns the BindingRequestHandler of the agent, the
+// agent switches to the pair if it returns true. The handler set with
+// SettingEngine.SetICEBindingRequestHandler is still called, a pinned pair
+// or the filter take precedence over it.
+func (s *candidatePairSelection) bindingRequestHandler(
+	userHandler func(m *stun.Message, local, remote ice.Candidate, pair *ice.CandidatePair) bool,
+) func(m *stun.Message, local, remote ice.Candidate, pair *ice.CandidatePair) bool {
+	return func(m *stun.Message, local, remote ice.Candidate, pair *ice.CandidatePair) bool {
+		shouldSwitch := userHandler != nil && userHandler(m, local, remote, pair)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.pinned == nil && s.filter == nil {
+			return shouldSwitch
+		}
+
+		candidates, err := newICECandidatesFromICE([]ice.Candidate{local, remote}, "", 0)
+		if err != nil {
+			return false
+		}
+		candidatePair := NewICECandidatePair(&candidates[0], &candidates[1])
+
+		if s.pinned != nil {
+			return sameICECandidatePair(candidatePair, s.pinned)
+		}
+
+		if !s.filter(candidatePair) {
+			return false
+		}
+
+		// Move away from a selected pair the filter rejects
+		return shouldSwitch || s.selectedExcluded
+	}
+}
+
+// sameICECandidatePair reports whether a and b pair the same candidates. The
+// candidates are compared by value, the agent doesn't return candidates with
+// the same ID from all of its methods.
+func sameICECandidatePair(a, b *ICECandidatePair) bool {
+	return sameICECandidate(a.Local, b.Local) && sameICECandidate(a.Remote, b.Remote)
+}
+
+func sameICECandidate(a, b *ICECandidate) bool {
+	return a.Foundation == b.Foundation &&
+		a.Address == b.Address &&
+		a.Port == b.Port &&
+		a.Protocol == b.Protocol &&
+		a.Typ == b.Typ
+}
+
+// GetCandidatePairs returns the candidate pairs the ICE agent formed from the
+// local and remote candidates.
+func (t *ICETransport) GetCandidatePairs() ([]*ICECandidatePair, error) {
+	agent := t.gatherer.getAgent()
+	if agent == nil {
+		return nil, nil
+	}
+
+	// Candidates are only added to the agent between restarts, reading the
+	// pairs first makes sure both of their candidates are in the snapshot
+	pairsStats := agent.GetCandidatePairsStats()
+
+	localCandidates, err := agent.GetLocalCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	remoteCandidates, err := agent.GetRemoteCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := map[string]ICECandidate{}
+	for _, c := range append(localCandidates, remoteCandidates...) {
+		candidate, err := newICECandidateFromICE(c, "", 0)
+		if err != nil {
+			return nil, err
+		}
+		candidates[c.ID()] = candidate
+	}
+
+	// The agent leaves candidates it considers location tracking, like
+	// link-local addresses, out of its candidate lists but still pairs them
+	for _, stats := range append(agent.GetLocalCandidatesStats(), agent.GetRemoteCandidatesStats()...) {
+		if _, ok := candidates[stats.ID]; ok {
+			continue
+		}
+
+		candidate, err := newICECandidateFromICEStats(stats)
+		if err != nil {
+			return nil, err
+		}
+		candidates[stats.ID] = candidate
+	}
+
+	pairs := []*ICECandidatePair{}
+	for _, stats := range pairsStats {
+		local, ok := candidates[stats.LocalCandidateID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errICECandidatePairCandidateNotFound, stats.LocalCandidateID)
+		}
+
+		remote, ok := candidates[stats.RemoteCandidateID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errICECandidatePairCandidateNotFound, stats.RemoteCandidateID)
+		}
+
+		pairs = append(pairs, NewICECandidatePair(&local, &remote))
+	}
+
+	return pairs, nil
+}
+
+// newICECandidateFromICEStats builds the ICECandidate a candidate's stats
+// describe. The candidate is round tripped through package ice so it gets
+// the foundation the agent computed for it.
+func newICECandidateFromICEStats(stats ice.CandidateStats) (ICECandidate, error) {
+	typ, err := convertTypeFromICE(stats.CandidateType)
+	if err != nil {
+		return ICECandidate{}, err
+	}
+
+	protocol, err := NewICEProtocol(stats.NetworkType.NetworkShort())
+	if err != nil {
+		return ICECandidate{}, err
+	}
+
+	iceCandidate, err := ICECandidate{
+		statsID:   stats.ID,
+		Priority:  stats.Priority,
+		Address:   stats.IP,
+		Protocol:  protocol,
+		Port:      uint16(stats.Port), //nolint:gosec // G115
+		Typ:       typ,
+		Component: 1,
+	}.toICE()
+	if err != nil {
+		return ICECandidate{}, err
+	}
+
+	return newICECandidateFromICE(iceCandidate, "", 0)
+}
+
+// SetPinnedCandidatePair forces the selection of a pair returned by
+// GetCandidatePairs. The pair is selected on the next connectivity check
+// received on it, checks on other pairs don't switch away from it while it
+// is pinned. Pass nil to let the ICE agent select pairs again.
+func (t *ICETransport) SetPinnedCandidatePair(pair *ICECandidatePair) error {
+	if pair != nil {
+		pairs, err := t.GetCandidatePairs()
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, p := range pairs {
+			found = found || sameICECandidatePair(p, pair)
+		}
+		if !found {
+			return errICECandidatePairNotFound
+		}
+	}
+
+	t.gatherer.pairSelection.setPinned(pair)
+
+	return nil
+}
+
+// SetCandidatePairFilter excludes the pairs for which filter returns false,
+// for example relayed or TCP pairs. If the selected pair is excluded the
+// next allowed pair a connectivity check is received on is selected.
+// Pass nil to allow all pairs again.
+func (t *ICETransport) SetCandidatePairFilter(filter func(*ICECandidatePair) bool) {
+	t.gatherer.pairSelection.setFilter(filter)
+
+	if pair, err := t.GetSelectedCandidatePair(); err == nil && pair != nil {
+		t.gatherer.pairSelection.selected(pair)
+	}
+}
@@ -28,8 +28,13 @@ type ICEGatherer struct {
 	validatedServers []*stun.URI
 	gatherPolicy     ICETransportPolicy
 
+	multicastDNSMode       ICEMulticastDNSMode
+	multicastDNSInterfaces []string
+
 	agent *ice.Agent
 
+	pairSelection candidatePairSelection
+
 	onLocalCandidateHandler atomic.Value // func(candidate *ICECandidate)
 	onStateChangeHandler    atomic.Value // func(state ICEGathererState)
 
@@ -67,6 +72,9 @@ func (api *API) NewICEGatherer(opts ICEGatherOptions) (*ICEGatherer, error) {
 		log:              api.settingEngine.LoggerFactory.NewLogger("ice"),
 		sdpMid:           atomic.Value{},
 		sdpMLineIndex:    atomic.Uint32{},
+
+		multicastDNSMode:       opts.MulticastDNSMode,
+		multicastDNSInterfaces: opts.MulticastDNSInterfaces,
 	}, nil
 }
 
@@ -96,11 +104,32 @@ func (g *ICEGatherer) createAgent() error { //nolint:cyclop
 	}
 
 	mDNSMode := g.api.settingEngine.candidates.MulticastDNSMode
+	switch g.multicastDNSMode {
+	case ICEMulticastDNSModeDisabled:
+		mDNSMode = ice.MulticastDNSModeDisabled
+	case ICEMulticastDNSModeQueryOnly:
+		mDNSMode = ice.MulticastDNSModeQueryOnly
+	case ICEMulticastDNSModeQueryAndGather:
+		mDNSMode = ice.MulticastDNSModeQueryAndGather
+	default:
+	}
 	if mDNSMode != ice.MulticastDNSModeDisabled && mDNSMode != ice.MulticastDNSModeQueryAndGather {
 		// If enum is in state we don't recognized default to MulticastDNSModeQueryOnly
 		mDNSMode = ice.MulticastDNSModeQueryOnly
 	}
 
+	mDNSHostName := g.api.settingEngine.candidates.MulticastDNSHostName
+	if mDNSHostName == "" {
+		mDNSHostName = g.api.multicastDNSHostName
+	}
+
+	iceNet, err := g.api.settingEngine.getICENet()
+	if err != nil {
+		return err
+	}
+
+	localUfrag, localPwd := g.api.settingEngine.getICECredentials()
+
 	config := &ice.AgentConfig{
 		Lite:                   g.api.settingEngine.candidates.ICELite,
 		Urls:                   g.validatedServers,
@@ -116,22 +145,22 @@ func (g *ICEGatherer) createAgent() error { //nolint:cyclop
 		PrflxAcceptanceMinWait: g.api.settingEngine.timeout.ICEPrflxAcceptanceMinWait,
 		RelayAcceptanceMinWait: g.api.settingEngine.timeout.ICERelayAcceptanceMinWait,
 		STUNGatherTimeout:      g.api.settingEngine.timeout.ICESTUNGatherTimeout,
-		InterfaceFilter:        g.api.settingEngine.candidates.InterfaceFilter,
+		InterfaceFilter:        g.interfaceFilter(),
 		IPFilter:               g.api.settingEngine.candidates.IPFilter,
 		NAT1To1IPs:             g.api.settingEngine.candidates.NAT1To1IPs,
 		NAT1To1IPCandidateType: nat1To1CandiTyp,
 		IncludeLoopback:        g.api.settingEngine.candidates.IncludeLoopbackCandidate,
-		Net:                    g.api.settingEngine.net,
+		Net:                    iceNet,
 		MulticastDNSMode:       mDNSMode,
-		MulticastDNSHostName:   g.api.settingEngine.candidates.MulticastDNSHostName,
-		LocalUfrag:             g.api.settingEngine.candidates.UsernameFragment,
-		LocalPwd:               g.api.settingEngine.candidates.Password,
+		MulticastDNSHostName:   mDNSHostName,
+		LocalUfrag:             localUfrag,
+		LocalPwd:               localPwd,
 		TCPMux:                 g.api.settingEngine.iceTCPMux,
 		UDPMux:                 g.api.settingEngine.iceUDPMux,
 		ProxyDialer:            g.api.settingEngine.iceProxyDialer,
 		DisableActiveTCP:       g.api.settingEngine.iceDisableActiveTCP,
 		MaxBindingRequests:     g.api.settingEngine.iceMaxBindingRequests,
-		BindingRequestHandler:  g.api.settingEngine.iceBindingRequestHandler,
+		BindingRequestHandler:  g.pairSelection.bindingRequestHandler(g.api.settingEngine.iceBindingRequestHandler),
 	}
 
 	requestedNetworkTypes := g.api.settingEngine.candidates.ICENetworkTypes
@@ -192,6 +221,11 @@ func (g *ICEGatherer) Gather() error { //nolint:cyclop
 
 				return
 			}
+			if !g.keepCandidate(c) {
+				g.log.Debugf("Local candidate filtered: %s", c)
+
+				return
+			}
 			onLocalCandidateHandler(&c)
 		} else {
 			g.setState(ICEGathererStateComplete)
@@ -247,6 +281,25 @@ func (g *ICEGatherer) close(shouldGracefullyClose bool) error {
 	return nil
 }
 
+// interfaceFilter returns the InterfaceFilter of the SettingEngine, limited
+// to the mDNS interfaces if some were set.
+func (g *ICEGatherer) interfaceFilter() func(string) bool {
+	filter := g.api.settingEngine.candidates.InterfaceFilter
+	if len(g.multicastDNSInterfaces) == 0 {
+		return filter
+	}
+
+	return func(name string) bool {
+		for _, mDNSInterface := range g.multicastDNSInterfaces {
+			if name == mDNSInterface {
+				return filter == nil || filter(name)
+			}
+		}
+
+		return false
+	}
+}
+
 // GetLocalParameters returns the ICE parameters of the ICEGatherer.
 func (g *ICEGatherer) GetLocalParameters() (ICEParameters, error) {
 	if err := g.createAgent(); err != nil {
@@ -295,7 +348,26 @@ func (g *ICEGatherer) GetLocalCandidates() ([]ICECandidate, error) {
 
 	sdpMLineIndex := uint16(g.sdpMLineIndex.Load()) //nolint:gosec // G115
 
-	return newICECandidatesFromICE(iceCandidates, sdpMid, sdpMLineIndex)
+	candidates, err := newICECandidatesFromICE(iceCandidates, sdpMid, sdpMLineIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := candidates[:0]
+	for _, c := range candidates {
+		if g.keepCandidate(c) {
+			kept = append(kept, c)
+		}
+	}
+
+	return kept, nil
+}
+
+// keepCandidate returns false if the candidate filter of the SettingEngine drops the candidate.
+func (g *ICEGatherer) keepCandidate(c ICECandidate) bool {
+	filter := g.api.settingEngine.candidates.CandidateFilter
+
+	return filter == nil || filter(c)
 }
 
 // OnLocalCandidate sets an event handler which fires when a new local ICE candidate is available
@@ -102,6 +102,115 @@ func TestICEGather_mDNSCandidateGathering(t *testing.T) {
 	assert.NoError(t, gatherer.Close())
 }
 
+func TestICEGatherer_MulticastDNSOptions(t *testing.T) {
+	// Limit runtime in case of deadlocks
+	lim := test.TimeOut(time.Second * 20)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	t.Run("Mode overrides SettingEngine", func(t *testing.T) {
+		s := SettingEngine{}
+		s.SetICEMulticastDNSMode(ice.MulticastDNSModeDisabled)
+
+		gatherer, err := NewAPI(WithSettingEngine(s)).NewICEGatherer(ICEGatherOptions{
+			MulticastDNSMode: ICEMulticastDNSModeQueryAndGather,
+		})
+		assert.NoError(t, err)
+
+		gotMulticastDNSCandidate, resolveFunc := context.WithCancel(context.Background())
+		gatherer.OnLocalCandidate(func(c *ICECandidate) {
+			if c != nil && strings.HasSuffix(c.Address, ".local") {
+				resolveFunc()
+			}
+		})
+
+		assert.NoError(t, gatherer.Gather())
+
+		<-gotMulticastDNSCandidate.Done()
+		assert.NoError(t, gatherer.Close())
+	})
+
+	t.Run("Interfaces", func(t *testing.T) {
+		s := SettingEngine{}
+		s.SetInterfaceFilter(func(name string) bool { return name != "eth1" })
+
+		gatherer, err := NewAPI(WithSettingEngine(s)).NewICEGatherer(ICEGatherOptions{
+			MulticastDNSInterfaces: []string{"eth0", "eth1"},
+		})
+		assert.NoError(t, err)
+
+		filter := gatherer.interfaceFilter()
+		assert.True(t, filter("eth0"))
+		assert.False(t, filter("eth1"))
+		assert.False(t, filter("wlan0"))
+	})
+
+	t.Run("SetConfiguration", func(t *testing.T) {
+		pc, err := NewPeerConnection(Configuration{
+			ICEMulticastDNSMode:       ICEMulticastDNSModeDisabled,
+			ICEMulticastDNSInterfaces: []string{"eth0"},
+		})
+		assert.NoError(t, err)
+
+		assert.NoError(t, pc.SetConfiguration(Configuration{
+			ICEMulticastDNSMode:       ICEMulticastDNSModeDisabled,
+			ICEMulticastDNSInterfaces: []string{"eth0"},
+		}))
+
+		assert.ErrorIs(t, pc.SetConfiguration(Configuration{
+			ICEMulticastDNSMode: ICEMulticastDNSModeQueryAndGather,
+		}), ErrModifyingICEMulticastDNS)
+		assert.ErrorIs(t, pc.SetConfiguration(Configuration{
+			ICEMulticastDNSInterfaces: []string{"eth1"},
+		}), ErrModifyingICEMulticastDNS)
+
+		assert.NoError(t, pc.Close())
+	})
+}
+
+func TestICEGatherer_CandidateFilter(t *testing.T) {
+	// Limit runtime in case of deadlocks
+	lim := test.TimeOut(time.Second * 20)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	s := SettingEngine{}
+	s.SetNetworkTypes([]NetworkType{NetworkTypeUDP4, NetworkTypeTCP4})
+	s.SetICECandidateFilter(func(c ICECandidate) bool {
+		assert.Equal(t, ICECandidateTypeHost, c.Typ)
+
+		return c.Protocol == ICEProtocolTCP
+	})
+
+	gatherer, err := NewAPI(WithSettingEngine(s)).NewICEGatherer(ICEGatherOptions{})
+	assert.NoError(t, err)
+
+	gatherFinished, gatherFinishedFunc := context.WithCancel(context.Background())
+	gatherer.OnLocalCandidate(func(c *ICECandidate) {
+		if c == nil {
+			gatherFinishedFunc()
+
+			return
+		}
+		assert.Equal(t, ICEProtocolTCP, c.Protocol)
+	})
+
+	assert.NoError(t, gatherer.Gather())
+	<-gatherFinished.Done()
+
+	candidates, err := gatherer.GetLocalCandidates()
+	assert.NoError(t, err)
+	for _, c := range candidates {
+		assert.Equal(t, ICEProtocolTCP, c.Protocol)
+	}
+
+	assert.NoError(t, gatherer.Close())
+}
+
 func TestICEGatherer_AlreadyClosed(t *testing.T) {
 	// Limit runtime in case of deadlocks
 	lim := test.TimeOut(time.Second * 20)
@@ -7,4 +7,9 @@ package webrtc
 type ICEGatherOptions struct {
 	ICEServers      []ICEServer
 	ICEGatherPolicy ICETransportPolicy
+
+	// MulticastDNSMode and MulticastDNSInterfaces override the mDNS settings
+	// of the SettingEngine, see Configuration.ICEMulticastDNSMode.
+	MulticastDNSMode       ICEMulticastDNSMode
+	MulticastDNSInterfaces []string
 }
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"encoding/json"
+)
+
+// ICEMulticastDNSMode controls how a PeerConnection uses mDNS for host
+// candidates.
+type ICEMulticastDNSMode int
+
+const (
+	// ICEMulticastDNSModeUnknown is the enum's zero-value, the mode set
+	// with SettingEngine.SetICEMulticastDNSMode is used.
+	ICEMulticastDNSModeUnknown ICEMulticastDNSMode = iota
+
+	// ICEMulticastDNSModeDisabled doesn't resolve remote .local candidates
+	// and doesn't obfuscate local host candidates.
+	ICEMulticastDNSModeDisabled
+
+	// ICEMulticastDNSModeQueryOnly resolves remote .local candidates,
+	// local host candidates aren't obfuscated.
+	ICEMulticastDNSModeQueryOnly
+
+	// ICEMulticastDNSModeQueryAndGather resolves remote .local candidates
+	// and replaces the address of local host candidates with a .local name.
+	ICEMulticastDNSModeQueryAndGather
+)
+
+// This is done this way because of a linter.
+const (
+	iceMulticastDNSModeDisabledStr       = "disable"
+	iceMulticastDNSModeQueryOnlyStr      = "resolve"
+	iceMulticastDNSModeQueryAndGatherStr = "gather"
+)
+
+// NewICEMulticastDNSMode takes a string and converts it to ICEMulticastDNSMode.
+func NewICEMulticastDNSMode(raw string) ICEMulticastDNSMode {
+	switch raw {
+	case iceMulticastDNSModeDisabledStr:
+		return ICEMulticastDNSModeDisabled
+	case iceMulticastDNSModeQueryOnlyStr:
+		return ICEMulticastDNSModeQueryOnly
+	case iceMulticastDNSModeQueryAndGatherStr:
+		return ICEMulticastDNSModeQueryAndGather
+	default:
+		return ICEMulticastDNSModeUnknown
+	}
+}
+
+func (m ICEMulticastDNSMode) String() string {
+	switch m {
+	case ICEMulticastDNSModeDisabled:
+		return iceMulticastDNSModeDisabledStr
+	case ICEMulticastDNSModeQueryOnly:
+		return iceMulticastDNSModeQueryOnlyStr
+	case ICEMulticastDNSModeQueryAndGather:
+		return iceMulticastDNSModeQueryAndGatherStr
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// UnmarshalJSON parses the JSON-encoded data and stores the result.
+func (m *ICEMulticastDNSMode) UnmarshalJSON(b []byte) error {
+	var val string
+	if err := json.Unmarshal(b, &val); err != nil {
+		return err
+	}
+	*m = NewICEMulticastDNSMode(val)
+
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding.
+func (m ICEMulticastDNSMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewICEMulticastDNSMode(t *testing.T) {
+	testCases := []struct {
+		modeString   string
+		expectedMode ICEMulticastDNSMode
+	}{
+		{ErrUnknownType.Error(), ICEMulticastDNSModeUnknown},
+		{"disable", ICEMulticastDNSModeDisabled},
+		{"resolve", ICEMulticastDNSModeQueryOnly},
+		{"gather", ICEMulticastDNSModeQueryAndGather},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t,
+			testCase.expectedMode,
+			NewICEMulticastDNSMode(testCase.modeString),
+			"testCase: %d %v", i, testCase,
+		)
+	}
+}
+
+func TestICEMulticastDNSMode_String(t *testing.T) {
+	testCases := []struct {
+		mode           ICEMulticastDNSMode
+		expectedString string
+	}{
+		{ICEMulticastDNSModeUnknown, ErrUnknownType.Error()},
+		{ICEMulticastDNSModeDisabled, "disable"},
+		{ICEMulticastDNSModeQueryOnly, "resolve"},
+		{ICEMulticastDNSModeQueryAndGather, "gather"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t,
+			testCase.expectedString,
+			testCase.mode.String(),
+			"testCase: %d %v", i, testCase,
+		)
+	}
+}
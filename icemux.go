@@ -5,6 +5,7 @@ package webrtc
 
 import (
 	"net"
+	"time"
 
 	"github.com/pion/ice/v4"
 	"github.com/pion/logging"
@@ -20,6 +21,36 @@ func NewICETCPMux(logger logging.LeveledLogger, listener net.Listener, readBuffe
 	})
 }
 
+// ICETCPMuxOptions tunes the ice.TCPMux created by NewICETCPMuxWithOptions
+// for listeners shared by many PeerConnections.
+type ICETCPMuxOptions struct {
+	// ReadBufferSize is the number of packets buffered per connection
+	ReadBufferSize int
+	// WriteBufferSize is the size of the write buffer of a connection in bytes,
+	// zero writes directly to the connection
+	WriteBufferSize int
+	// FirstStunBindTimeout closes connections that didn't send a STUN binding
+	// request in time, they can't be routed to a PeerConnection
+	FirstStunBindTimeout time.Duration
+	// AliveDurationForConnFromStun closes connections whose ufrag didn't match
+	// a PeerConnection in time
+	AliveDurationForConnFromStun time.Duration
+}
+
+// NewICETCPMuxWithOptions creates a new instance of ice.TCPMuxDefault. All the
+// passive ICE TCP connections of the PeerConnections it is set on are accepted
+// on listener, they are routed by the ufrag of their first STUN binding request.
+func NewICETCPMuxWithOptions(logger logging.LeveledLogger, listener net.Listener, options ICETCPMuxOptions) ice.TCPMux {
+	return ice.NewTCPMuxDefault(ice.TCPMuxParams{
+		Listener:                     listener,
+		Logger:                       logger,
+		ReadBufferSize:               options.ReadBufferSize,
+		WriteBufferSize:              options.WriteBufferSize,
+		FirstStunBindTimeout:         options.FirstStunBindTimeout,
+		AliveDurationForConnFromStun: options.AliveDurationForConnFromStun,
+	})
+}
+
 // NewICEUDPMux creates a new instance of ice.UDPMuxDefault. It allows many PeerConnections to be served
 // by a single UDP Port.
 func NewICEUDPMux(logger logging.LeveledLogger, udpConn net.PacketConn) ice.UDPMux {
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+)
+
+// ICEServerProvider holds the ICE servers shared by all PeerConnections of
+// an API, see SettingEngine.SetICEServerProvider. PeerConnections whose
+// Configuration has no ICEServers use the servers of the provider at the
+// time they are created, so rotating TURN servers or credentials only needs
+// SetICEServers. PeerConnections that already exist keep their servers, use
+// OnChange to update them, for example with an ICE restart.
+type ICEServerProvider struct {
+	mu              sync.RWMutex
+	servers         []ICEServer
+	onChangeHandler func([]ICEServer)
+}
+
+// NewICEServerProvider creates a new ICEServerProvider with servers.
+func NewICEServerProvider(servers []ICEServer) (*ICEServerProvider, error) {
+	p := &ICEServerProvider{}
+	if err := p.SetICEServers(servers); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ICEServers returns the current ICE servers.
+func (p *ICEServerProvider) ICEServers() []ICEServer {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return Configuration{ICEServers: p.servers}.getICEServers()
+}
+
+// SetICEServers replaces the ICE servers given to new PeerConnections. The
+// servers are validated like in a Configuration, they are not replaced if
+// any is invalid.
+func (p *ICEServerProvider) SetICEServers(servers []ICEServer) error {
+	servers = Configuration{ICEServers: servers}.getICEServers()
+	for _, server := range servers {
+		if err := server.validate(); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	p.servers = servers
+	handler := p.onChangeHandler
+	p.mu.Unlock()
+
+	if handler != nil {
+		go handler(Configuration{ICEServers: servers}.getICEServers())
+	}
+
+	return nil
+}
+
+// OnChange sets an event handler which is called with the new ICE servers
+// every time they are set with SetICEServers.
+func (p *ICEServerProvider) OnChange(f func([]ICEServer)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onChangeHandler = f
+}
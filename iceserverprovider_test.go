@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestICEServerProvider(t *testing.T) {
+	stunServers := []ICEServer{{URLs: []string{"stun:stun.example.com:3478?transport=udp"}}}
+	provider, err := NewICEServerProvider(stunServers)
+	assert.NoError(t, err)
+	assert.Equal(t, []ICEServer{{URLs: []string{"stun:stun.example.com:3478"}}}, provider.ICEServers())
+
+	changed := make(chan []ICEServer, 1)
+	provider.OnChange(func(servers []ICEServer) { changed <- servers })
+
+	turnServers := []ICEServer{{
+		URLs:       []string{"turn:turn.example.com:3478"},
+		Username:   "user",
+		Credential: "pass",
+	}}
+	assert.NoError(t, provider.SetICEServers(turnServers))
+	assert.Equal(t, turnServers, <-changed)
+	assert.Equal(t, turnServers, provider.ICEServers())
+
+	// Invalid servers don't replace the current ones
+	err = provider.SetICEServers([]ICEServer{{URLs: []string{"turn:turn.example.com:3478"}}})
+	assert.ErrorIs(t, err, ErrNoTurnCredentials)
+	assert.Equal(t, turnServers, provider.ICEServers())
+
+	_, err = NewICEServerProvider([]ICEServer{{URLs: []string{"turn:turn.example.com:3478"}}})
+	assert.ErrorIs(t, err, ErrNoTurnCredentials)
+}
+
+func TestICEServerProvider_PeerConnection(t *testing.T) {
+	provider, err := NewICEServerProvider([]ICEServer{{URLs: []string{"stun:stun1.example.com:3478"}}})
+	assert.NoError(t, err)
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetICEServerProvider(provider)
+	api := NewAPI(WithSettingEngine(settingEngine))
+
+	first, err := api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.Equal(t, provider.ICEServers(), first.GetConfiguration().ICEServers)
+
+	assert.NoError(t, provider.SetICEServers([]ICEServer{{URLs: []string{"stun:stun2.example.com:3478"}}}))
+
+	// New PeerConnections use the new servers, the existing ones keep theirs
+	second, err := api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stun:stun2.example.com:3478"}, second.GetConfiguration().ICEServers[0].URLs)
+	assert.Equal(t, []string{"stun:stun1.example.com:3478"}, first.GetConfiguration().ICEServers[0].URLs)
+
+	// The servers of the Configuration take precedence
+	explicit := []ICEServer{{URLs: []string{"stun:stun3.example.com:3478"}}}
+	third, err := api.NewPeerConnection(Configuration{ICEServers: explicit})
+	assert.NoError(t, err)
+	assert.Equal(t, explicit, third.GetConfiguration().ICEServers)
+
+	assert.NoError(t, first.Close())
+	assert.NoError(t, second.Close())
+	assert.NoError(t, third.Close())
+}
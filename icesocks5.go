@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pion/transport/v3"
+	"golang.org/x/net/proxy"
+)
+
+// SOCKS5 protocol constants, see RFC 1928 and RFC 1929.
+const (
+	socks5Version             = 0x05
+	socks5AuthNone            = 0x00
+	socks5AuthPassword        = 0x02
+	socks5AuthNoAcceptable    = 0xff
+	socks5AuthPasswordVersion = 0x01
+	socks5CommandUDPAssociate = 0x03
+	socks5AddressIPv4         = 0x01
+	socks5AddressDomain       = 0x03
+	socks5AddressIPv6         = 0x04
+	socks5ReplySucceeded      = 0x00
+)
+
+// socks5HandshakeTimeout bounds connecting to the proxy and requesting a relay.
+const socks5HandshakeTimeout = 10 * time.Second
+
+// socks5Net is a transport.Net that sends UDP through the UDP ASSOCIATE
+// relay of a SOCKS5 proxy, everything else goes to the underlying Net.
+type socks5Net struct {
+	transport.Net
+
+	address string
+	auth    *proxy.Auth
+}
+
+func newSOCKS5Net(base transport.Net, address string, auth *proxy.Auth) *socks5Net {
+	return &socks5Net{Net: base, address: address, auth: auth}
+}
+
+// ListenPacket opens a UDP socket associated with the proxy for UDP networks.
+func (n *socks5Net) ListenPacket(network string, address string) (net.PacketConn, error) {
+	switch network {
+	case "udp", "udp4", "udp6":
+	default:
+		return n.Net.ListenPacket(network, address)
+	}
+
+	locAddr, err := n.Net.ResolveUDPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.ListenUDP(network, locAddr)
+}
+
+// ListenUDP opens a UDP socket associated with the proxy. Datagrams
+// written to it are relayed by the proxy, the remote sees the address of
+// the proxy.
+func (n *socks5Net) ListenUDP(network string, locAddr *net.UDPAddr) (transport.UDPConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), socks5HandshakeTimeout)
+	defer cancel()
+
+	control, err := n.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	relayAddr, err := socks5UDPAssociateWithDeadline(ctx, control, n.auth)
+	if err != nil {
+		_ = control.Close()
+
+		return nil, err
+	}
+
+	// The proxy can reply with an unspecified address if the relay listens
+	// on the address the control connection was accepted on.
+	if relayAddr.IP.IsUnspecified() {
+		if tcpAddr, ok := control.RemoteAddr().(*net.TCPAddr); ok {
+			relayAddr.IP = tcpAddr.IP
+		}
+	}
+
+	conn, err := n.Net.ListenUDP(network, locAddr)
+	if err != nil {
+		_ = control.Close()
+
+		return nil, err
+	}
+
+	return &socks5UDPConn{UDPConn: conn, control: control, relayAddr: relayAddr}, nil
+}
+
+// dial opens the control connection to the proxy, it is canceled with ctx if
+// the Net supports it.
+func (n *socks5Net) dial(ctx context.Context) (net.Conn, error) {
+	dialer := n.Net.CreateDialer(&net.Dialer{})
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, "tcp", n.address)
+	}
+
+	return dialer.Dial("tcp", n.address)
+}
+
+// socks5UDPAssociateWithDeadline is socks5UDPAssociate failing once the
+// deadline of ctx passes. The deadline is cleared again on success, the
+// control connection stays open while the relay is used.
+func socks5UDPAssociateWithDeadline(ctx context.Context, control net.Conn, auth *proxy.Auth) (*net.UDPAddr, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := control.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+
+	relayAddr, err := socks5UDPAssociate(control, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := control.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	return relayAddr, nil
+}
+
+// socks5UDPAssociate authenticates on the control connection and requests a
+// UDP relay, the address of the relay is returned.
+func socks5UDPAssociate(control net.Conn, auth *proxy.Auth) (*net.UDPAddr, error) {
+	method := byte(socks5AuthNone)
+	if auth != nil {
+		method = socks5AuthPassword
+	}
+
+	if _, err := control.Write([]byte{socks5Version, 1, method}); err != nil {
+		return nil, err
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(control, reply); err != nil {
+		return nil, err
+	}
+	if reply[0] != socks5Version || reply[1] == socks5AuthNoAcceptable || reply[1] != method {
+		return nil, errICESOCKS5AuthMethod
+	}
+
+	if method == socks5AuthPassword {
+		if len(auth.User) > 255 || len(auth.Password) > 255 {
+			return nil, errICESOCKS5AuthFailed
+		}
+
+		request := []byte{socks5AuthPasswordVersion, byte(len(auth.User))}
+		request = append(request, auth.User...)
+		request = append(request, byte(len(auth.Password)))
+		request = append(request, auth.Password...)
+		if _, err := control.Write(request); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.ReadFull(control, reply); err != nil {
+			return nil, err
+		}
+		if reply[1] != socks5ReplySucceeded {
+			return nil, errICESOCKS5AuthFailed
+		}
+	}
+
+	// The client doesn't know the address it will send from, so it is left
+	// unspecified as allowed by RFC 1928.
+	if _, err := control.Write([]byte{
+		socks5Version, socks5CommandUDPAssociate, 0x00, socks5AddressIPv4, 0, 0, 0, 0, 0, 0,
+	}); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(control, header); err != nil {
+		return nil, err
+	}
+	if header[0] != socks5Version || header[1] != socks5ReplySucceeded {
+		return nil, errICESOCKS5AssociateFailed
+	}
+
+	return socks5ReadAddress(control)
+}
+
+// socks5ReadAddress reads an ATYP, ADDR and PORT triplet.
+func socks5ReadAddress(r io.Reader) (*net.UDPAddr, error) {
+	addressType := make([]byte, 1)
+	if _, err := io.ReadFull(r, addressType); err != nil {
+		return nil, err
+	}
+
+	var host []byte
+	switch addressType[0] {
+	case socks5AddressIPv4:
+		host = make([]byte, net.IPv4len)
+	case socks5AddressIPv6:
+		host = make([]byte, net.IPv6len)
+	case socks5AddressDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(r, length); err != nil {
+			return nil, err
+		}
+		host = make([]byte, length[0])
+	default:
+		return nil, errICESOCKS5AddressType
+	}
+
+	if _, err := io.ReadFull(r, host); err != nil {
+		return nil, err
+	}
+
+	rawPort := make([]byte, 2)
+	if _, err := io.ReadFull(r, rawPort); err != nil {
+		return nil, err
+	}
+	port := int(binary.BigEndian.Uint16(rawPort))
+
+	if addressType[0] == socks5AddressDomain {
+		return net.ResolveUDPAddr("udp", net.JoinHostPort(string(host), strconv.Itoa(port)))
+	}
+
+	return &net.UDPAddr{IP: host, Port: port}, nil
+}
+
+// socks5UDPConn is a UDP socket whose datagrams are relayed by a SOCKS5
+// proxy. The relay is released when the control connection is closed.
+type socks5UDPConn struct {
+	transport.UDPConn
+
+	control   net.Conn
+	relayAddr *net.UDPAddr
+	closeOnce sync.Once
+
+	// readBuf holds the datagrams with their header, it is reused by reads
+	readMu  sync.Mutex
+	readBuf []byte
+}
+
+// WriteTo prepends the SOCKS5 UDP request header and sends p to the relay.
+func (c *socks5UDPConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return 0, errICESOCKS5AddressType
+	}
+
+	return c.WriteToUDP(p, udpAddr)
+}
+
+// WriteToUDP prepends the SOCKS5 UDP request header and sends p to the relay.
+func (c *socks5UDPConn) WriteToUDP(p []byte, addr *net.UDPAddr) (int, error) {
+	header := []byte{0x00, 0x00, 0x00}
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		header = append(header, socks5AddressIPv4)
+		header = append(header, ip4...)
+	} else {
+		header = append(header, socks5AddressIPv6)
+		header = append(header, addr.IP.To16()...)
+	}
+	header = binary.BigEndian.AppendUint16(header, uint16(addr.Port)) //nolint:gosec // G115
+
+	if _, err := c.UDPConn.WriteToUDP(append(header, p...), c.relayAddr); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// ReadFrom reads a datagram from the relay and strips its header, the
+// returned address is the one the proxy received the datagram from.
+func (c *socks5UDPConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	n, addr, err := c.ReadFromUDP(p)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return n, addr, nil
+}
+
+// ReadFromUDP reads a datagram from the relay and strips its header, the
+// returned address is the one the proxy received the datagram from.
+// Datagrams from other senders and fragments are dropped.
+func (c *socks5UDPConn) ReadFromUDP(p []byte) (int, *net.UDPAddr, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	if len(c.readBuf) < len(p)+socks5MaxUDPHeaderLength {
+		c.readBuf = make([]byte, len(p)+socks5MaxUDPHeaderLength)
+	}
+	buf := c.readBuf[:len(p)+socks5MaxUDPHeaderLength]
+	for {
+		n, from, err := c.UDPConn.ReadFromUDP(buf)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if !from.IP.Equal(c.relayAddr.IP) || from.Port != c.relayAddr.Port || n < 4 || buf[2] != 0x00 {
+			continue
+		}
+
+		reader := bytes.NewReader(buf[3:n])
+		addr, err := socks5ReadAddress(reader)
+		if err != nil {
+			continue
+		}
+
+		return copy(p, buf[n-reader.Len():n]), addr, nil
+	}
+}
+
+// Close releases the relay and closes the socket.
+func (c *socks5UDPConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.UDPConn.Close()
+		if closeErr := c.control.Close(); err == nil {
+			err = closeErr
+		}
+	})
+
+	return err
+}
+
+// socks5MaxUDPHeaderLength is the length of the UDP request header with the
+// longest domain name.
+const socks5MaxUDPHeaderLength = 3 + 1 + 1 + 255 + 2
@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/stdnet"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/proxy"
+)
+
+// socks5TestServer is a SOCKS5 proxy that only implements UDP ASSOCIATE for a
+// single client.
+type socks5TestServer struct {
+	listener net.Listener
+	relay    *net.UDPConn
+	auth     *proxy.Auth
+}
+
+func newSOCKS5TestServer(t *testing.T, auth *proxy.Auth) *socks5TestServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	relay, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(t, err)
+
+	server := &socks5TestServer{listener: listener, relay: relay, auth: auth}
+	go server.serve()
+
+	return server
+}
+
+func (s *socks5TestServer) close() {
+	_ = s.listener.Close()
+	_ = s.relay.Close()
+}
+
+func (s *socks5TestServer) serve() {
+	control, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = control.Close()
+	}()
+
+	greeting := make([]byte, 3)
+	if _, err = io.ReadFull(control, greeting); err != nil {
+		return
+	}
+	if _, err = control.Write([]byte{socks5Version, greeting[2]}); err != nil {
+		return
+	}
+
+	if greeting[2] == socks5AuthPassword {
+		request := make([]byte, 2+len(s.auth.User)+1+len(s.auth.Password))
+		if _, err = io.ReadFull(control, request); err != nil {
+			return
+		}
+
+		status := byte(socks5ReplySucceeded)
+		if string(request[2:2+len(s.auth.User)]) != s.auth.User {
+			status = 0x01
+		}
+		if _, err = control.Write([]byte{socks5AuthPasswordVersion, status}); err != nil {
+			return
+		}
+	}
+
+	request := make([]byte, 10)
+	if _, err = io.ReadFull(control, request); err != nil {
+		return
+	}
+
+	relayAddr, _ := s.relay.LocalAddr().(*net.UDPAddr)
+	reply := []byte{socks5Version, socks5ReplySucceeded, 0x00, socks5AddressIPv4}
+	reply = append(reply, relayAddr.IP.To4()...)
+	reply = append(reply, byte(relayAddr.Port>>8), byte(relayAddr.Port))
+	if _, err = control.Write(reply); err != nil {
+		return
+	}
+
+	go s.relayUDP()
+
+	// The relay lives as long as the control connection
+	_, _ = io.Copy(io.Discard, control)
+}
+
+func (s *socks5TestServer) relayUDP() {
+	var client *net.UDPAddr
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := s.relay.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		if client == nil || (from.IP.Equal(client.IP) && from.Port == client.Port) {
+			// From the client, unwrap and forward
+			client = from
+			to, err := socks5ReadAddress(bytes.NewReader(buf[3:n]))
+			if err != nil {
+				return
+			}
+			if _, err = s.relay.WriteToUDP(buf[10:n], to); err != nil {
+				return
+			}
+
+			continue
+		}
+
+		// From a remote, wrap and send to the client
+		packet := []byte{0x00, 0x00, 0x00, socks5AddressIPv4}
+		packet = append(packet, from.IP.To4()...)
+		packet = append(packet, byte(from.Port>>8), byte(from.Port))
+		if _, err = s.relay.WriteToUDP(append(packet, buf[:n]...), client); err != nil {
+			return
+		}
+	}
+}
+
+func TestSOCKS5Net(t *testing.T) {
+	for _, auth := range []*proxy.Auth{nil, {User: "pion", Password: "pion"}} {
+		server := newSOCKS5TestServer(t, auth)
+
+		echo, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		assert.NoError(t, err)
+		go func() {
+			buf := make([]byte, 1500)
+			for {
+				n, from, err := echo.ReadFromUDP(buf)
+				if err != nil {
+					return
+				}
+				_, _ = echo.WriteToUDP(buf[:n], from)
+			}
+		}()
+
+		base, err := stdnet.NewNet()
+		assert.NoError(t, err)
+
+		conn, err := newSOCKS5Net(base, server.listener.Addr().String(), auth).
+			ListenPacket("udp4", "127.0.0.1:0")
+		assert.NoError(t, err)
+
+		_, err = conn.WriteTo([]byte("ping"), echo.LocalAddr())
+		assert.NoError(t, err)
+
+		buf := make([]byte, 1500)
+		n, from, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "ping", string(buf[:n]))
+
+		// The address is the one the proxy received the datagram from
+		assert.Equal(t, echo.LocalAddr().String(), from.String())
+
+		assert.NoError(t, conn.Close())
+		assert.NoError(t, echo.Close())
+		server.close()
+	}
+}
+
+func TestSOCKS5Net_AssociateFailed(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, listener.Close())
+	}()
+
+	go func() {
+		control, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = control.Close()
+		}()
+
+		greeting := make([]byte, 3)
+		if _, err = io.ReadFull(control, greeting); err != nil {
+			return
+		}
+		_, _ = control.Write([]byte{socks5Version, socks5AuthNone})
+
+		request := make([]byte, 10)
+		if _, err = io.ReadFull(control, request); err != nil {
+			return
+		}
+		// Command not supported
+		_, _ = control.Write([]byte{socks5Version, 0x07, 0x00, socks5AddressIPv4, 0, 0, 0, 0, 0, 0})
+	}()
+
+	base, err := stdnet.NewNet()
+	assert.NoError(t, err)
+
+	_, err = newSOCKS5Net(base, listener.Addr().String(), nil).ListenUDP("udp4", nil)
+	assert.ErrorIs(t, err, errICESOCKS5AssociateFailed)
+}
+
+func TestSOCKS5UDPAssociate_Deadline(t *testing.T) {
+	control, proxyConn := net.Pipe()
+	defer func() {
+		_ = proxyConn.Close()
+	}()
+
+	// The proxy reads the greeting and never replies
+	go func() {
+		_, _ = io.Copy(io.Discard, proxyConn)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := socks5UDPAssociateWithDeadline(ctx, control, nil)
+	var netErr net.Error
+	assert.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+	assert.NoError(t, control.Close())
+}
@@ -35,6 +35,9 @@ type ICETransport struct {
 	gatherer *ICEGatherer
 	conn     *ice.Conn
 	mux      *mux.Mux
+	relay    relayAccounting
+	queue    sendQueue
+	pacer    *pacer
 
 	ctxCancel func()
 
@@ -118,13 +121,17 @@ func (t *ICETransport) Start(gatherer *ICEGatherer, params ICEParameters, role *
 		return err
 	}
 	if err := agent.OnSelectedCandidatePairChange(func(local, remote ice.Candidate) {
+		t.relay.relayed.set(local.Type() == ice.CandidateTypeRelay)
+
 		candidates, err := newICECandidatesFromICE([]ice.Candidate{local, remote}, "", 0)
 		if err != nil {
 			t.log.Warnf("%w: %s", errICECandiatesCoversionFailed, err)
 
 			return
 		}
-		t.onSelectedCandidatePairChange(NewICECandidatePair(&candidates[0], &candidates[1]))
+		pair := NewICECandidatePair(&candidates[0], &candidates[1])
+		t.gatherer.pairSelection.selected(pair)
+		t.onSelectedCandidatePairChange(pair)
 	}); err != nil {
 		return err
 	}
@@ -170,9 +177,10 @@ func (t *ICETransport) Start(gatherer *ICEGatherer, params ICEParameters, role *
 	}
 
 	t.conn = iceConn
+	t.relay.budget = t.gatherer.api.settingEngine.relayBudget
 
 	config := mux.Config{
-		Conn:          t.conn,
+		Conn:          &sendQueueConn{Conn: &relayAccountingConn{Conn: t.conn, accounting: &t.relay}, queue: &t.queue},
 		BufferSize:    int(t.gatherer.api.settingEngine.getReceiveMTU()), //nolint:gosec // G115
 		LoggerFactory: t.loggerFactory,
 	}
@@ -192,16 +200,63 @@ func (t *ICETransport) restart() error {
 		return fmt.Errorf("%w: unable to restart ICETransport", errICEAgentNotExist)
 	}
 
-	if err := agent.Restart(
-		t.gatherer.api.settingEngine.candidates.UsernameFragment,
-		t.gatherer.api.settingEngine.candidates.Password,
-	); err != nil {
+	if err := agent.Restart(t.gatherer.api.settingEngine.getICECredentials()); err != nil {
 		return err
 	}
 
 	return t.gatherer.Gather()
 }
 
+// rebind gathers new local candidates with the current ICE credentials and
+// keeps the remote candidates, so the remote agent can switch to the new
+// candidates without an ICE restart.
+func (t *ICETransport) rebind() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	agent := t.gatherer.getAgent()
+	if agent == nil {
+		return fmt.Errorf("%w: unable to rebind ICETransport", errICEAgentNotExist)
+	}
+
+	ufrag, pwd, err := agent.GetLocalUserCredentials()
+	if err != nil {
+		return err
+	}
+
+	remoteUfrag, remotePwd, err := agent.GetRemoteUserCredentials()
+	if err != nil {
+		return err
+	}
+
+	remoteCandidates, err := agent.GetRemoteCandidates()
+	if err != nil {
+		return err
+	}
+
+	if err = agent.Restart(ufrag, pwd); err != nil {
+		return err
+	}
+
+	// Restart forgets the remote credentials, they don't change without an ICE restart
+	if err = agent.SetRemoteCredentials(remoteUfrag, remotePwd); err != nil {
+		return err
+	}
+
+	for _, candidate := range remoteCandidates {
+		// Peer reflexive candidates are learned again from the connectivity checks
+		if candidate.Type() == ice.CandidateTypePeerReflexive {
+			continue
+		}
+
+		if err = agent.AddRemoteCandidate(candidate); err != nil {
+			return err
+		}
+	}
+
+	return t.gatherer.Gather()
+}
+
 // Stop irreversibly stops the ICETransport.
 func (t *ICETransport) Stop() error {
 	return t.stop(false /* shouldGracefullyClose */)
@@ -218,6 +273,10 @@ func (t *ICETransport) stop(shouldGracefullyClose bool) error {
 	t.lock.Lock()
 	t.setState(ICETransportStateClosed)
 
+	if t.pacer != nil {
+		t.pacer.close()
+	}
+
 	if t.ctxCancel != nil {
 		t.ctxCancel()
 	}
@@ -381,25 +440,32 @@ func (t *ICETransport) ensureGatherer() error {
 	return nil
 }
 
-func (t *ICETransport) collectStats(collector *statsReportCollector) {
+// transportStats returns the TransportStats of the ICE layer, DTLSTransport
+// completes them with the state of DTLS.
+func (t *ICETransport) transportStats() TransportStats {
 	t.lock.Lock()
 	conn := t.conn
+	role := t.role
 	t.lock.Unlock()
 
-	collector.Collecting()
-
 	stats := TransportStats{
 		Timestamp: statsTimestampFrom(time.Now()),
 		Type:      StatsTypeTransport,
 		ID:        "iceTransport",
+		ICERole:   role,
+		ICEState:  t.State(),
+	}
+	if pairStats, ok := t.gatherer.getSelectedCandidatePairStats(); ok {
+		stats.SelectedCandidatePairID = pairStats.ID
 	}
 
 	if conn != nil {
 		stats.BytesSent = conn.BytesSent()
 		stats.BytesReceived = conn.BytesReceived()
 	}
+	stats.RelayBytesSent, stats.RelayBytesReceived = t.relay.bytes()
 
-	collector.Collect(stats.ID, stats)
+	return stats
 }
 
 func (t *ICETransport) haveRemoteCredentialsChange(newUfrag, newPwd string) bool {
@@ -12,6 +12,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pion/ice/v4"
+	"github.com/pion/stun/v3"
 	"github.com/pion/transport/v3/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -141,3 +143,129 @@ func TestICETransport_GetLocalParameters(t *testing.T) {
 
 	closePairNow(t, offerer, answerer)
 }
+
+func TestICETransport_CandidatePairs(t *testing.T) {
+	offerer, answerer, err := newPair()
+	assert.NoError(t, err)
+
+	peerConnectionConnected := untilConnectionState(PeerConnectionStateConnected, offerer, answerer)
+	assert.NoError(t, signalPair(offerer, answerer))
+	peerConnectionConnected.Wait()
+
+	iceTransport := offerer.SCTP().Transport().ICETransport()
+	selectedPair, err := iceTransport.GetSelectedCandidatePair()
+	assert.NoError(t, err)
+
+	pairs, err := iceTransport.GetCandidatePairs()
+	assert.NoError(t, err)
+
+	found := false
+	for _, pair := range pairs {
+		found = found || sameICECandidatePair(pair, selectedPair)
+	}
+	assert.True(t, found)
+
+	assert.NoError(t, iceTransport.SetPinnedCandidatePair(selectedPair))
+	assert.ErrorIs(t, iceTransport.SetPinnedCandidatePair(NewICECandidatePair(
+		&ICECandidate{Address: "unknown"}, &ICECandidate{Address: "unknown"},
+	)), errICECandidatePairNotFound)
+	assert.NoError(t, iceTransport.SetPinnedCandidatePair(nil))
+
+	closePairNow(t, offerer, answerer)
+}
+
+func TestCandidatePairSelection(t *testing.T) {
+	newCandidate := func(address string) ice.Candidate {
+		candidate, err := ice.NewCandidateHost(&ice.CandidateHostConfig{
+			Network:   "udp",
+			Address:   address,
+			Port:      5000,
+			Component: 1,
+		})
+		assert.NoError(t, err)
+
+		return candidate
+	}
+	local, remoteA, remoteB := newCandidate("10.0.0.1"), newCandidate("10.0.0.2"), newCandidate("10.0.0.3")
+
+	userSwitches := false
+	selection := &candidatePairSelection{}
+	handler := selection.bindingRequestHandler(func(*stun.Message, ice.Candidate, ice.Candidate, *ice.CandidatePair) bool {
+		return userSwitches
+	})
+
+	// Without a pinned pair or a filter the user handler decides
+	assert.False(t, handler(nil, local, remoteA, nil))
+	userSwitches = true
+	assert.True(t, handler(nil, local, remoteA, nil))
+
+	// Only the pinned pair is switched to
+	candidates, err := newICECandidatesFromICE([]ice.Candidate{local, remoteB}, "", 0)
+	assert.NoError(t, err)
+	candidates[0].statsID, candidates[1].statsID = "other-local", "other-remote"
+	selection.setPinned(NewICECandidatePair(&candidates[0], &candidates[1]))
+	assert.False(t, handler(nil, local, remoteA, nil))
+	assert.True(t, handler(nil, local, remoteB, nil))
+	selection.setPinned(nil)
+
+	// Excluded pairs are never switched to, allowed pairs replace an excluded one
+	userSwitches = false
+	selection.setFilter(func(pair *ICECandidatePair) bool {
+		return pair.Remote.Address != "10.0.0.2"
+	})
+	assert.False(t, handler(nil, local, remoteA, nil))
+	assert.False(t, handler(nil, local, remoteB, nil))
+
+	candidates, err = newICECandidatesFromICE([]ice.Candidate{local, remoteA}, "", 0)
+	assert.NoError(t, err)
+	selection.selected(NewICECandidatePair(&candidates[0], &candidates[1]))
+	assert.False(t, handler(nil, local, remoteA, nil))
+	assert.True(t, handler(nil, local, remoteB, nil))
+}
+
+func TestPeerConnection_RebindICE(t *testing.T) {
+	report := test.CheckRoutines(t)
+	defer report()
+
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	dataChannel, err := pcOffer.CreateDataChannel("rebind", nil)
+	assert.NoError(t, err)
+	messages := make(chan string, 1)
+	dataChannel.OnMessage(func(msg DataChannelMessage) { messages <- string(msg.Data) })
+
+	answerDataChannels := make(chan *DataChannel, 1)
+	pcAnswer.OnDataChannel(func(d *DataChannel) {
+		d.OnOpen(func() { answerDataChannels <- d })
+	})
+
+	selectedPairs := make(chan *ICECandidatePair, 4)
+	pcOffer.SCTP().Transport().ICETransport().OnSelectedCandidatePairChange(func(pair *ICECandidatePair) {
+		selectedPairs <- pair
+	})
+
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	answerDataChannel := <-answerDataChannels
+	initialPair := <-selectedPairs
+
+	// The new candidates are trickled, no offer or answer is exchanged
+	pcOffer.OnICECandidate(func(candidate *ICECandidate) {
+		if candidate != nil {
+			assert.NoError(t, pcAnswer.AddICECandidate(candidate.ToJSON()))
+		}
+	})
+	assert.NoError(t, pcOffer.RebindICE())
+
+	rebindPair := <-selectedPairs
+	assert.NotEqual(t, initialPair.Local.Port, rebindPair.Local.Port)
+
+	assert.NoError(t, answerDataChannel.SendText("after rebind"))
+	assert.Equal(t, "after rebind", <-messages)
+
+	closePairNow(t, pcOffer, pcAnswer)
+	assert.ErrorIs(t, pcOffer.RebindICE(), ErrConnectionClosed)
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pion/sdp/v3"
+)
+
+const sdpAttributeIdentity = "identity"
+
+// IdentityProvider is the identity provider (IdP) an IdentityAssertion was
+// generated by, see https://www.rfc-editor.org/rfc/rfc8827#section-7.
+type IdentityProvider struct {
+	Domain   string `json:"domain"`
+	Protocol string `json:"protocol"`
+}
+
+// IdentityAssertion is the identity assertion signaled in an a=identity
+// attribute, it binds the DTLS fingerprints of a session description to an
+// identity.
+type IdentityAssertion struct {
+	IdP       IdentityProvider `json:"idp"`
+	Assertion string           `json:"assertion"`
+}
+
+// IdentityValidation is the result of validating an IdentityAssertion with
+// its IdP. Contents are the contents the assertion was generated for, they
+// hold the fingerprints the identity is bound to.
+type IdentityValidation struct {
+	Identity string
+	Contents string
+}
+
+// IdentityValidator validates an IdentityAssertion with its IdP, see
+// SettingEngine.SetIdentityValidator.
+type IdentityValidator func(assertion IdentityAssertion) (IdentityValidation, error)
+
+// identityContents are the contents of an assertion, RFC 8827 Section 7.
+type identityContents struct {
+	Fingerprint []identityFingerprint `json:"fingerprint"`
+}
+
+type identityFingerprint struct {
+	Algorithm string `json:"algorithm"`
+	Digest    string `json:"digest"`
+}
+
+// SetIdentityAssertion sets the assertion signaled in the a=identity attribute of
+// the local descriptions created from now on. The assertion must be generated by
+// the IdP for the contents returned by IdentityAssertionContents. Pass nil to stop
+// signaling an identity.
+func (pc *PeerConnection) SetIdentityAssertion(assertion *IdentityAssertion) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.identityAssertion = assertion
+}
+
+// IdentityAssertionContents returns the contents an IdP generates the assertion
+// of this PeerConnection for, the fingerprints of its certificates.
+func (pc *PeerConnection) IdentityAssertionContents() (string, error) {
+	contents := identityContents{}
+	for _, certificate := range pc.configuration.Certificates {
+		fingerprints, err := certificate.GetFingerprints()
+		if err != nil {
+			return "", err
+		}
+
+		for _, fingerprint := range fingerprints {
+			contents.Fingerprint = append(contents.Fingerprint, identityFingerprint{
+				Algorithm: fingerprint.Algorithm,
+				Digest:    strings.ToUpper(fingerprint.Value),
+			})
+		}
+	}
+
+	raw, err := json.Marshal(contents)
+
+	return string(raw), err
+}
+
+// PeerIdentity returns the identity of the remote peer, validated from the
+// a=identity attribute of the remote description. It is empty until an
+// assertion was validated.
+func (pc *PeerConnection) PeerIdentity() string {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	return pc.peerIdentity
+}
+
+// addIdentityAttribute signals the identity assertion in a local description,
+// the caller must hold pc.mu.
+func (pc *PeerConnection) addIdentityAttribute(desc *sdp.SessionDescription) error {
+	if pc.identityAssertion == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(pc.identityAssertion)
+	if err != nil {
+		return err
+	}
+	desc.Attributes = append(desc.Attributes, sdp.Attribute{
+		Key:   sdpAttributeIdentity,
+		Value: base64.StdEncoding.EncodeToString(raw),
+	})
+
+	return nil
+}
+
+// validateRemoteIdentity validates the a=identity attribute of a remote
+// description if an IdentityValidator is set. The fingerprints the assertion was
+// generated for must include all the fingerprints of the description, and
+// the identity must be Configuration.PeerIdentity if one is set.
+func (pc *PeerConnection) validateRemoteIdentity(desc *sdp.SessionDescription) error { //nolint:cyclop
+	validator := pc.api.settingEngine.identityValidator
+	if validator == nil {
+		return nil
+	}
+
+	value, hasIdentity := desc.Attribute(sdpAttributeIdentity)
+	if !hasIdentity {
+		if pc.configuration.PeerIdentity != "" {
+			return errIdentityNotValidated
+		}
+
+		return nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		// nolint
+		return fmt.Errorf("%w: %v", errIdentityAssertionInvalid, err)
+	}
+
+	var assertion IdentityAssertion
+	if err = json.Unmarshal(raw, &assertion); err != nil {
+		// nolint
+		return fmt.Errorf("%w: %v", errIdentityAssertionInvalid, err)
+	}
+
+	validation, err := validator(assertion)
+	if err != nil {
+		return err
+	}
+
+	var contents identityContents
+	if err = json.Unmarshal([]byte(validation.Contents), &contents); err != nil {
+		// nolint
+		return fmt.Errorf("%w: %v", errIdentityAssertionInvalid, err)
+	}
+
+	for _, fingerprint := range sdpFingerprints(desc) {
+		found := false
+		for _, f := range contents.Fingerprint {
+			found = found || (strings.EqualFold(f.Algorithm, fingerprint.Algorithm) &&
+				strings.EqualFold(f.Digest, fingerprint.Value))
+		}
+		if !found {
+			return fmt.Errorf("%w: %s %s", errIdentityFingerprintMismatch, fingerprint.Algorithm, fingerprint.Value)
+		}
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	switch {
+	case pc.configuration.PeerIdentity != "" && validation.Identity != pc.configuration.PeerIdentity,
+		pc.peerIdentity != "" && validation.Identity != pc.peerIdentity:
+		return fmt.Errorf("%w: %s", errIdentityMismatch, validation.Identity)
+	default:
+		pc.peerIdentity = validation.Identity
+	}
+
+	return nil
+}
+
+// sdpFingerprints returns the session and media level fingerprints of a description.
+func sdpFingerprints(desc *sdp.SessionDescription) []DTLSFingerprint {
+	fingerprints := []DTLSFingerprint{}
+	add := func(attributes []sdp.Attribute) {
+		for _, attr := range attributes {
+			if attr.Key != "fingerprint" {
+				continue
+			}
+
+			if parts := strings.Split(attr.Value, " "); len(parts) == 2 {
+				fingerprints = append(fingerprints, DTLSFingerprint{Algorithm: parts[0], Value: parts[1]})
+			}
+		}
+	}
+
+	add(desc.Attributes)
+	for _, media := range desc.MediaDescriptions {
+		add(media.Attributes)
+	}
+
+	return fingerprints
+}
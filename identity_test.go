@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerConnection_IdentityAssertion(t *testing.T) {
+	// The test IdP asserts "identity|contents"
+	validator := func(assertion IdentityAssertion) (IdentityValidation, error) {
+		assert.Equal(t, IdentityProvider{Domain: "idp.example.org", Protocol: "test"}, assertion.IdP)
+		parts := strings.SplitN(assertion.Assertion, "|", 2)
+
+		return IdentityValidation{Identity: parts[0], Contents: parts[1]}, nil
+	}
+
+	newOffer := func(identity string, contentsFrom *PeerConnection) SessionDescription {
+		pc, err := NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+		if contentsFrom == nil {
+			contentsFrom = pc
+		}
+
+		contents, err := contentsFrom.IdentityAssertionContents()
+		assert.NoError(t, err)
+		pc.SetIdentityAssertion(&IdentityAssertion{
+			IdP:       IdentityProvider{Domain: "idp.example.org", Protocol: "test"},
+			Assertion: identity + "|" + contents,
+		})
+
+		_, err = pc.CreateDataChannel("data", nil)
+		assert.NoError(t, err)
+
+		offer, err := pc.CreateOffer(nil)
+		assert.NoError(t, err)
+		assert.Contains(t, offer.SDP, "a=identity:")
+		assert.NoError(t, pc.Close())
+
+		return offer
+	}
+
+	newAnswerer := func(peerIdentity string) *PeerConnection {
+		settingEngine := SettingEngine{}
+		settingEngine.SetIdentityValidator(validator)
+
+		pc, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{
+			PeerIdentity: peerIdentity,
+		})
+		assert.NoError(t, err)
+
+		return pc
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		answerer := newAnswerer("alice@idp.example.org")
+		assert.NoError(t, answerer.SetRemoteDescription(newOffer("alice@idp.example.org", nil)))
+		assert.Equal(t, "alice@idp.example.org", answerer.PeerIdentity())
+		assert.NoError(t, answerer.Close())
+	})
+
+	t.Run("Other identity", func(t *testing.T) {
+		answerer := newAnswerer("alice@idp.example.org")
+		assert.ErrorIs(t, answerer.SetRemoteDescription(newOffer("bob@idp.example.org", nil)), errIdentityMismatch)
+		assert.Equal(t, "", answerer.PeerIdentity())
+		assert.NoError(t, answerer.Close())
+	})
+
+	t.Run("Other fingerprint", func(t *testing.T) {
+		other, err := NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+
+		answerer := newAnswerer("")
+		assert.ErrorIs(t,
+			answerer.SetRemoteDescription(newOffer("alice@idp.example.org", other)),
+			errIdentityFingerprintMismatch,
+		)
+		assert.NoError(t, answerer.Close())
+		assert.NoError(t, other.Close())
+	})
+
+	t.Run("No assertion", func(t *testing.T) {
+		offerer, err := NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+		_, err = offerer.CreateDataChannel("data", nil)
+		assert.NoError(t, err)
+		offer, err := offerer.CreateOffer(nil)
+		assert.NoError(t, err)
+
+		answerer := newAnswerer("alice@idp.example.org")
+		assert.ErrorIs(t, answerer.SetRemoteDescription(offer), errIdentityNotValidated)
+		assert.NoError(t, answerer.Close())
+		assert.NoError(t, offerer.Close())
+	})
+}
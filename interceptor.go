@@ -7,15 +7,20 @@
 package webrtc
 
 import (
+	"fmt"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/cc"
 	"github.com/pion/interceptor/pkg/nack"
 	"github.com/pion/interceptor/pkg/report"
 	"github.com/pion/interceptor/pkg/rfc8888"
 	"github.com/pion/interceptor/pkg/twcc"
 	"github.com/pion/rtp"
 	"github.com/pion/sdp/v3"
+	"github.com/pion/webrtc/v4/pkg/ulpfec"
 )
 
 // RegisterDefaultInterceptors will register some useful interceptors.
@@ -49,8 +54,29 @@ func ConfigureRTCPReports(interceptorRegistry *interceptor.Registry) error {
 		return err
 	}
 
-	interceptorRegistry.Add(reciver)
-	interceptorRegistry.Add(sender)
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameReceiverReports, reciver))
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameSenderReports, sender))
+
+	return nil
+}
+
+// ConfigureRTCPReportsWithTickerPool will setup everything necessary for generating
+// Sender and Receiver Reports. The Sender Report timers of all PeerConnections built
+// from the registry are served by the shared TickerPool instead of one timer each.
+// The Receiver Report interceptor can't be given a ticker and keeps its own timer.
+func ConfigureRTCPReportsWithTickerPool(interceptorRegistry *interceptor.Registry, pool *TickerPool) error {
+	reciver, err := report.NewReceiverInterceptor()
+	if err != nil {
+		return err
+	}
+
+	sender, err := report.NewSenderInterceptor(report.SenderTicker(pool.NewTicker))
+	if err != nil {
+		return err
+	}
+
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameReceiverReports, reciver))
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameSenderReports, sender))
 
 	return nil
 }
@@ -69,8 +95,8 @@ func ConfigureNack(mediaEngine *MediaEngine, interceptorRegistry *interceptor.Re
 
 	mediaEngine.RegisterFeedback(RTCPFeedback{Type: "nack"}, RTPCodecTypeVideo)
 	mediaEngine.RegisterFeedback(RTCPFeedback{Type: "nack", Parameter: "pli"}, RTPCodecTypeVideo)
-	interceptorRegistry.Add(responder)
-	interceptorRegistry.Add(generator)
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameNACKResponder, responder))
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameNACKGenerator, generator))
 
 	return nil
 }
@@ -95,7 +121,7 @@ func ConfigureTWCCHeaderExtensionSender(mediaEngine *MediaEngine, interceptorReg
 		return err
 	}
 
-	interceptorRegistry.Add(i)
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameTWCCHeaderExtensionSender, i))
 
 	return nil
 }
@@ -122,7 +148,7 @@ func ConfigureTWCCSender(mediaEngine *MediaEngine, interceptorRegistry *intercep
 		return err
 	}
 
-	interceptorRegistry.Add(generator)
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameTWCCSender, generator))
 
 	return nil
 }
@@ -136,11 +162,37 @@ func ConfigureCongestionControlFeedback(mediaEngine *MediaEngine, interceptorReg
 	if err != nil {
 		return err
 	}
-	interceptorRegistry.Add(generator)
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameCongestionControlFeedback, generator))
 
 	return nil
 }
 
+// ConfigureCongestionController adds a congestion controller interceptor
+// whose bandwidth estimators are created by newEstimator, like
+// gcc.NewSendSideBWE, and the TWCC header extension it estimates from. The
+// estimator of a PeerConnection is returned by PeerConnection.BandwidthEstimator.
+func ConfigureCongestionController(
+	mediaEngine *MediaEngine,
+	interceptorRegistry *interceptor.Registry,
+	newEstimator cc.BandwidthEstimatorFactory,
+	options ...cc.Option,
+) error {
+	congestionController, err := cc.NewInterceptor(newEstimator, options...)
+	if err != nil {
+		return err
+	}
+
+	congestionController.OnNewPeerConnection(func(id string, estimator cc.BandwidthEstimator) {
+		if value, ok := interceptorBuilds.Load(id); ok {
+			build, _ := value.(*interceptorBuild)
+			build.bandwidthEstimator = estimator
+		}
+	})
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameCongestionController, congestionController))
+
+	return ConfigureTWCCHeaderExtensionSender(mediaEngine, interceptorRegistry)
+}
+
 // ConfigureSimulcastExtensionHeaders enables the RTP Extension Headers needed for Simulcast.
 func ConfigureSimulcastExtensionHeaders(mediaEngine *MediaEngine) error {
 	if err := mediaEngine.RegisterHeaderExtension(
@@ -160,11 +212,133 @@ func ConfigureSimulcastExtensionHeaders(mediaEngine *MediaEngine) error {
 	)
 }
 
-type interceptorToTrackLocalWriter struct{ interceptor atomic.Value } // interceptor.RTPWriter }
+// ConfigureULPFEC registers the red and ulpfec video codecs with the provided
+// payload types and adds the interceptor that sends video inside RED followed
+// by ULPFEC packets, and recovers lost packets from the ULPFEC packets it receives.
+// The interceptor shifts the sequence numbers of outgoing packets, so this
+// must be called after the other interceptors (like NACK) were added.
+func ConfigureULPFEC(
+	redPayloadType, ulpfecPayloadType PayloadType,
+	mediaEngine *MediaEngine,
+	interceptorRegistry *interceptor.Registry,
+	options ...ulpfec.Option,
+) error {
+	if err := mediaEngine.RegisterCodec(RTPCodecParameters{
+		RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeVideoRED, ClockRate: 90000},
+		PayloadType:        redPayloadType,
+	}, RTPCodecTypeVideo); err != nil {
+		return err
+	}
+
+	if err := mediaEngine.RegisterCodec(RTPCodecParameters{
+		RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeULPFEC, ClockRate: 90000},
+		PayloadType:        ulpfecPayloadType,
+	}, RTPCodecTypeVideo); err != nil {
+		return err
+	}
+
+	generator, err := ulpfec.NewInterceptor(options...)
+	if err != nil {
+		return err
+	}
+
+	interceptorRegistry.Add(NewNamedInterceptorFactory(InterceptorNameULPFEC, generator))
+
+	return nil
+}
+
+// setULPFECStreamInfo passes the negotiated RED and ULPFEC payload types to the
+// ULPFEC interceptor, streams without both of them are left unprotected.
+func setULPFECStreamInfo(info *interceptor.StreamInfo, codecs []RTPCodecParameters) {
+	redPayloadType, ulpfecPayloadType := findULPFECPayloadTypes(codecs)
+	if redPayloadType == 0 {
+		return
+	}
+
+	info.PayloadTypeForwardErrorCorrection = uint8(ulpfecPayloadType)
+	info.Attributes.Set(ulpfec.AttributeREDPayloadType, uint8(redPayloadType))
+}
+
+type interceptorToTrackLocalWriter struct {
+	interceptor atomic.Value // interceptor.RTPWriter
+	encoding    atomic.Value // RTPEncodingParameters
+	queue       *sendQueue
+	metrics     *PerformanceMetrics
+	// pacer is the pacer of the transport, see SettingEngine.SetPacingBitrate.
+	// Packets that aren't queued are sent right away and charged to it.
+	pacer *pacer
+	// paced holds the packets until the pacers of the RTPSender let them
+	// through, it is unset if the writes aren't paced.
+	paced atomic.Value // *pacedQueue
+	// written is called after every packet written, if set.
+	written func()
+}
+
+// BufferedAmount implements TrackLocalBufferedWriter.
+func (i *interceptorToTrackLocalWriter) BufferedAmount() uint64 {
+	return i.queue.bufferedAmount() + i.pacedQueue().bufferedAmount()
+}
+
+func (i *interceptorToTrackLocalWriter) pacedQueue() *pacedQueue {
+	paced, _ := i.paced.Load().(*pacedQueue)
+
+	return paced
+}
+
+// svcLayerWriter is implemented by the TrackLocalWriter of RTPSender, tracks use
+// it to pass the SVC layer of a packet to the interceptors.
+type svcLayerWriter interface {
+	writeRTPWithLayer(header *rtp.Header, payload []byte, spatialID, temporalID uint8) (int, error)
+}
 
 func (i *interceptorToTrackLocalWriter) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
+	return i.writeRTPWithLayer(header, payload, 0, 0)
+}
+
+func (i *interceptorToTrackLocalWriter) writeRTPWithLayer(
+	header *rtp.Header, payload []byte, spatialID, temporalID uint8,
+) (int, error) {
+	attributes := interceptor.Attributes{}
+	if encoding, ok := i.encoding.Load().(RTPEncodingParameters); ok {
+		// Encoding has been paused by RTPSender.SetParameters
+		if encoding.Active != nil && !*encoding.Active {
+			return 0, nil
+		}
+
+		if encoding.MaxBitrate != 0 {
+			attributes[AttributeEncodingMaxBitrate] = encoding.MaxBitrate
+		}
+		if encoding.ScaleResolutionDownBy != 0 {
+			attributes[AttributeEncodingScaleResolutionDownBy] = encoding.ScaleResolutionDownBy
+		}
+		if encoding.ScalabilityMode != "" {
+			attributes[AttributeEncodingScalabilityMode] = encoding.ScalabilityMode
+			attributes[AttributeSpatialID] = spatialID
+			attributes[AttributeTemporalID] = temporalID
+		}
+	}
+
+	if paced := i.pacedQueue(); paced != nil {
+		return paced.push(header, payload, attributes)
+	}
+
+	i.pacer.consume(header.MarshalSize() + len(payload))
+
+	return i.write(header, payload, attributes)
+}
+
+func (i *interceptorToTrackLocalWriter) write(
+	header *rtp.Header, payload []byte, attributes interceptor.Attributes,
+) (int, error) {
 	if writer, ok := i.interceptor.Load().(interceptor.RTPWriter); ok && writer != nil {
-		return writer.Write(header, payload, interceptor.Attributes{})
+		if histogram := i.metrics.interceptorWriteDuration(); histogram != nil {
+			defer histogram.observeSince(time.Now())
+		}
+		if i.written != nil {
+			defer i.written()
+		}
+
+		return writer.Write(header, payload, attributes)
 	}
 
 	return 0, nil
@@ -214,3 +388,95 @@ func createStreamInfo(
 		RTCPFeedback:                      feedbacks,
 	}
 }
+
+// Names of the interceptors added by the Configure functions, as listed by
+// PeerConnection.Interceptors.
+const (
+	InterceptorNameNACKResponder             = "nackResponder"
+	InterceptorNameNACKGenerator             = "nackGenerator"
+	InterceptorNameReceiverReports           = "receiverReports"
+	InterceptorNameSenderReports             = "senderReports"
+	InterceptorNameTWCCHeaderExtensionSender = "twccHeaderExtensionSender"
+	InterceptorNameTWCCSender                = "twccSender"
+	InterceptorNameCongestionControlFeedback = "congestionControlFeedback"
+	InterceptorNameULPFEC                    = "ulpfec"
+	InterceptorNameCongestionController      = "congestionController"
+)
+
+// interceptorBuilds holds the interceptorBuild of the PeerConnections being
+// created, by the ID passed to interceptor.Registry.Build.
+var (
+	interceptorBuilds     sync.Map // map[string]*interceptorBuild
+	interceptorBuildCount uint64
+)
+
+// interceptorBuild records the named interceptors built for a PeerConnection.
+type interceptorBuild struct {
+	disabled           map[string]bool
+	overrides          map[string]interceptor.Factory
+	names              []string
+	bandwidthEstimator cc.BandwidthEstimator
+}
+
+// buildInterceptors builds the interceptors of the registry for a
+// PeerConnection, the named ones in disabled are left out and the ones in
+// overrides are built by the factory given for their name instead.
+func buildInterceptors(
+	registry *interceptor.Registry,
+	disabled []string,
+	overrides map[string]interceptor.Factory,
+) (interceptor.Interceptor, *interceptorBuild, error) {
+	build := &interceptorBuild{disabled: map[string]bool{}, overrides: overrides}
+	for _, name := range disabled {
+		build.disabled[name] = true
+	}
+
+	id := fmt.Sprintf("PeerConnection-%d", atomic.AddUint64(&interceptorBuildCount, 1))
+	interceptorBuilds.Store(id, build)
+	defer interceptorBuilds.Delete(id)
+
+	i, err := registry.Build(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return i, build, nil
+}
+
+type namedInterceptorFactory struct {
+	name    string
+	factory interceptor.Factory
+}
+
+// NewNamedInterceptorFactory names an interceptor.Factory, so the interceptors
+// it builds are listed by PeerConnection.Interceptors and can be left out of
+// a PeerConnection with Configuration.DisabledInterceptors. The interceptors
+// added by the Configure functions are named with the InterceptorName constants.
+func NewNamedInterceptorFactory(name string, factory interceptor.Factory) interceptor.Factory {
+	return &namedInterceptorFactory{name: name, factory: factory}
+}
+
+func (f *namedInterceptorFactory) NewInterceptor(id string) (interceptor.Interceptor, error) {
+	value, ok := interceptorBuilds.Load(id)
+	if !ok {
+		return f.factory.NewInterceptor(id)
+	}
+
+	build, _ := value.(*interceptorBuild)
+	if build.disabled[f.name] {
+		return &interceptor.NoOp{}, nil
+	}
+
+	factory := f.factory
+	if override, ok := build.overrides[f.name]; ok {
+		factory = override
+	}
+
+	i, err := factory.NewInterceptor(id)
+	if err != nil {
+		return nil, err
+	}
+	build.names = append(build.names, f.name)
+
+	return i, nil
+}
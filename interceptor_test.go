@@ -15,11 +15,14 @@ import (
 	"time"
 
 	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/cc"
+	"github.com/pion/interceptor/pkg/gcc"
 	mock_interceptor "github.com/pion/interceptor/pkg/mock"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/pion/transport/v3/test"
 	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/pion/webrtc/v4/pkg/ulpfec"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -245,6 +248,81 @@ func Test_InterceptorRegistry_Build(t *testing.T) {
 	closePairNow(t, peerConnectionA, peerConnectionB)
 }
 
+func TestPeerConnection_Interceptors(t *testing.T) {
+	customBuildCount := 0
+	newAPI := func() *API {
+		mediaEngine := &MediaEngine{}
+		assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+
+		ir := &interceptor.Registry{}
+		assert.NoError(t, RegisterDefaultInterceptors(mediaEngine, ir))
+		ir.Add(NewNamedInterceptorFactory("custom", &mock_interceptor.Factory{
+			NewInterceptorFn: func(_ string) (interceptor.Interceptor, error) {
+				customBuildCount++
+
+				return &interceptor.NoOp{}, nil
+			},
+		}))
+
+		return NewAPI(WithMediaEngine(mediaEngine), WithInterceptorRegistry(ir))
+	}
+
+	peerConnection, err := newAPI().NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		InterceptorNameNACKResponder,
+		InterceptorNameNACKGenerator,
+		InterceptorNameReceiverReports,
+		InterceptorNameSenderReports,
+		InterceptorNameTWCCSender,
+		"custom",
+	}, peerConnection.Interceptors())
+	assert.Equal(t, 1, customBuildCount)
+	assert.NoError(t, peerConnection.Close())
+
+	peerConnection, err = newAPI().NewPeerConnection(Configuration{
+		DisabledInterceptors: []string{InterceptorNameNACKGenerator, "custom"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		InterceptorNameNACKResponder,
+		InterceptorNameReceiverReports,
+		InterceptorNameSenderReports,
+		InterceptorNameTWCCSender,
+	}, peerConnection.Interceptors())
+	assert.Equal(t, 1, customBuildCount)
+	assert.NoError(t, peerConnection.Close())
+}
+
+func TestConfigureCongestionController(t *testing.T) {
+	mediaEngine := &MediaEngine{}
+	assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+
+	ir := &interceptor.Registry{}
+	assert.NoError(t, ConfigureCongestionController(mediaEngine, ir, func() (cc.BandwidthEstimator, error) {
+		return gcc.NewSendSideBWE(gcc.SendSideBWEInitialBitrate(500_000))
+	}))
+
+	peerConnectionA, peerConnectionB, err := NewAPI(
+		WithMediaEngine(mediaEngine), WithInterceptorRegistry(ir),
+	).newPair(Configuration{})
+	assert.NoError(t, err)
+
+	estimatorA, estimatorB := peerConnectionA.BandwidthEstimator(), peerConnectionB.BandwidthEstimator()
+	assert.NotNil(t, estimatorA)
+	assert.NotNil(t, estimatorB)
+	assert.NotSame(t, estimatorA, estimatorB)
+	assert.Equal(t, 500_000, estimatorA.GetTargetBitrate())
+	assert.Contains(t, peerConnectionA.Interceptors(), InterceptorNameCongestionController)
+
+	peerConnectionC, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.Nil(t, peerConnectionC.BandwidthEstimator())
+
+	closePairNow(t, peerConnectionA, peerConnectionB)
+	assert.NoError(t, peerConnectionC.Close())
+}
+
 func Test_Interceptor_ZeroSSRC(t *testing.T) {
 	to := test.TimeOut(time.Second * 20)
 	defer to.Stop()
@@ -447,3 +525,23 @@ func testInterceptorNack(t *testing.T, requestNack bool) { //nolint:cyclop
 		}
 	}
 }
+
+func TestConfigureULPFEC(t *testing.T) {
+	mediaEngine := &MediaEngine{}
+	assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	assert.NoError(t, ConfigureULPFEC(116, 117, mediaEngine, &interceptor.Registry{}))
+
+	red, fec := findULPFECPayloadTypes(mediaEngine.videoCodecs)
+	assert.Equal(t, PayloadType(116), red)
+	assert.Equal(t, PayloadType(117), fec)
+
+	info := createStreamInfo("", 1234, 0, 0, 96, 0, 0, mediaEngine.videoCodecs[0].RTPCodecCapability, nil)
+	setULPFECStreamInfo(info, mediaEngine.videoCodecs)
+	assert.Equal(t, uint8(117), info.PayloadTypeForwardErrorCorrection)
+	assert.Equal(t, uint8(116), info.Attributes.Get(ulpfec.AttributeREDPayloadType))
+
+	info = createStreamInfo("", 1234, 0, 0, 111, 0, 0, mediaEngine.audioCodecs[0].RTPCodecCapability, nil)
+	setULPFECStreamInfo(info, mediaEngine.audioCodecs)
+	assert.Equal(t, uint8(0), info.PayloadTypeForwardErrorCorrection)
+	assert.Nil(t, info.Attributes.Get(ulpfec.AttributeREDPayloadType))
+}
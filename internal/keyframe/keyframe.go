@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package keyframe detects the start of keyframes in RTP payloads
+package keyframe
+
+import (
+	"strings"
+
+	"github.com/pion/rtp/codecs"
+)
+
+const (
+	h264NALUTypeBitmask = 0x1F
+	h264NALUTypeIDR     = 5
+	h264NALUTypeSPS     = 7
+	h264NALUTypeSTAPA   = 24
+	h264NALUTypeFUA     = 28
+	h264FUStartBitmask  = 0x80
+
+	av1NewSequenceBitmask = 0x08
+)
+
+// IsStart returns true if payload is the first packet of a keyframe of the
+// video codec mimeType. Payloads of codecs without a known keyframe structure
+// always start one.
+func IsStart(mimeType string, payload []byte) bool {
+	switch {
+	case strings.EqualFold(mimeType, "video/vp8"):
+		vp8 := codecs.VP8Packet{}
+		if _, err := vp8.Unmarshal(payload); err != nil {
+			return false
+		}
+
+		// The P bit of the VP8 payload header is clear on keyframes
+		return vp8.S == 1 && vp8.PID == 0 && len(vp8.Payload) > 0 && vp8.Payload[0]&0x01 == 0
+	case strings.EqualFold(mimeType, "video/vp9"):
+		vp9 := codecs.VP9Packet{}
+		if _, err := vp9.Unmarshal(payload); err != nil {
+			return false
+		}
+
+		return vp9.B && !vp9.P
+	case strings.EqualFold(mimeType, "video/h264"):
+		return isH264Start(payload)
+	case strings.EqualFold(mimeType, "video/av1"):
+		// The N bit of the aggregation header starts a coded video sequence
+		return len(payload) > 0 && payload[0]&av1NewSequenceBitmask != 0
+	default:
+		return true
+	}
+}
+
+// isH264Start returns true if payload carries a SPS or the start of
+// an IDR slice.
+func isH264Start(payload []byte) bool {
+	if len(payload) == 0 {
+		return false
+	}
+
+	isKey := func(naluType byte) bool {
+		return naluType == h264NALUTypeIDR || naluType == h264NALUTypeSPS
+	}
+
+	switch naluType := payload[0] & h264NALUTypeBitmask; naluType {
+	case h264NALUTypeSTAPA:
+		for offset := 1; offset+2 < len(payload); {
+			size := int(payload[offset])<<8 | int(payload[offset+1])
+			if isKey(payload[offset+2] & h264NALUTypeBitmask) {
+				return true
+			}
+			offset += 2 + size
+		}
+
+		return false
+	case h264NALUTypeFUA:
+		return len(payload) > 1 && payload[1]&h264FUStartBitmask != 0 && isKey(payload[1]&h264NALUTypeBitmask)
+	default:
+		return isKey(naluType)
+	}
+}
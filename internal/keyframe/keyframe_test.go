@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package keyframe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStart(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		mimeType string
+		payload  []byte
+		keyframe bool
+	}{
+		{"VP8 Keyframe", "video/VP8", []byte{0x10, 0x00, 0x9d, 0x01}, true},
+		{"VP8 Interframe", "video/VP8", []byte{0x10, 0x01, 0x00, 0x00}, false},
+		{"VP8 Continuation", "video/VP8", []byte{0x00, 0x00, 0x9d, 0x01}, false},
+		{"VP9 Keyframe", "video/VP9", []byte{0x08, 0x00}, true},
+		{"VP9 Interframe", "video/VP9", []byte{0x48, 0x00}, false},
+		{"H264 IDR", "video/H264", []byte{0x65, 0x00}, true},
+		{"H264 Slice", "video/H264", []byte{0x41, 0x00}, false},
+		{"H264 STAP-A SPS", "video/H264", []byte{0x78, 0x00, 0x01, 0x67, 0x00, 0x01, 0x68}, true},
+		{"H264 FU-A IDR Start", "video/H264", []byte{0x7c, 0x85, 0x00}, true},
+		{"H264 FU-A IDR Middle", "video/H264", []byte{0x7c, 0x05, 0x00}, false},
+		{"AV1 New Sequence", "video/AV1", []byte{0x18, 0x00}, true},
+		{"AV1 Interframe", "video/AV1", []byte{0x10, 0x00}, false},
+		{"Empty", "video/H264", []byte{}, false},
+		{"Opus", "audio/opus", []byte{0x00}, true},
+	} {
+		assert.Equal(t, test.keyframe, IsStart(test.mimeType, test.payload), test.name)
+	}
+}
@@ -0,0 +1,272 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/nack"
+	"github.com/pion/webrtc/v4/pkg/ulpfec"
+)
+
+// LatencyMode is a profile that trades latency against robustness of the
+// received media. It configures the jitter buffer, NACK and FEC of a
+// PeerConnection together, see Configuration.LatencyMode.
+type LatencyMode byte
+
+const (
+	// LatencyModeUnknown is the enum's zero-value, the defaults of every
+	// setting are used.
+	LatencyModeUnknown LatencyMode = iota
+
+	// LatencyModeRealtime keeps the latency as low as possible. Incomplete
+	// samples are given up on quickly, losses are repaired with FEC rather
+	// than waiting for retransmissions, and paced video packets that wait
+	// too long to be sent are dropped.
+	LatencyModeRealtime
+
+	// LatencyModeBalanced uses the defaults of every setting.
+	LatencyModeBalanced
+
+	// LatencyModeQuality accepts more latency to receive complete media.
+	// Retransmissions are kept longer, the jitter buffer waits longer for
+	// them and FEC is added on top.
+	LatencyModeQuality
+)
+
+func (m LatencyMode) String() string {
+	switch m {
+	case LatencyModeRealtime:
+		return "realtime"
+	case LatencyModeBalanced:
+		return "balanced"
+	case LatencyModeQuality:
+		return "quality"
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// jitterBufferConfig returns the default JitterBufferConfig of the tracks of
+// the given kind.
+func (m LatencyMode) jitterBufferConfig(kind RTPCodecType) JitterBufferConfig {
+	switch m {
+	case LatencyModeRealtime:
+		if kind == RTPCodecTypeAudio {
+			return JitterBufferConfig{MaxLate: 4, MaxLatency: 60 * time.Millisecond}
+		}
+
+		return JitterBufferConfig{MaxLate: 64, MaxLatency: 100 * time.Millisecond}
+	case LatencyModeQuality:
+		if kind == RTPCodecTypeAudio {
+			return JitterBufferConfig{MaxLate: 64, MaxLatency: 500 * time.Millisecond}
+		}
+
+		return JitterBufferConfig{MaxLate: 1024, MaxLatency: time.Second}
+	default:
+		return JitterBufferConfig{}
+	}
+}
+
+// maxPacingDelay returns how long paced video packets may wait to be sent
+// before they are dropped, 0 if they aren't, see SettingEngine.SetPacingBitrate.
+func (m LatencyMode) maxPacingDelay() time.Duration {
+	if m == LatencyModeRealtime {
+		return 100 * time.Millisecond
+	}
+
+	return 0
+}
+
+// nackOptions returns the options of the NACK generator and responder.
+func (m LatencyMode) nackOptions() ([]nack.GeneratorOption, []nack.ResponderOption) {
+	switch m {
+	case LatencyModeRealtime:
+		// A retransmission is only useful if it arrives before the
+		// jitter buffer gives up, ask once and quickly.
+		return []nack.GeneratorOption{
+			nack.GeneratorInterval(20 * time.Millisecond),
+			nack.GeneratorMaxNacksPerPacket(1),
+		}, nil
+	case LatencyModeQuality:
+		return []nack.GeneratorOption{nack.GeneratorSize(4096)}, []nack.ResponderOption{nack.ResponderSize(4096)}
+	default:
+		return nil, nil
+	}
+}
+
+// fecOptions returns the options of the ULPFEC interceptor, FEC isn't
+// used if false is returned.
+func (m LatencyMode) fecOptions() ([]ulpfec.Option, bool) {
+	switch m {
+	case LatencyModeRealtime:
+		return []ulpfec.Option{ulpfec.NumMediaPackets(4)}, true
+	case LatencyModeQuality:
+		return []ulpfec.Option{ulpfec.NumMediaPackets(10)}, true
+	default:
+		return nil, false
+	}
+}
+
+// interceptorFactories returns the NACK and ULPFEC interceptor factories of
+// the mode by their InterceptorName, nil if the mode is unknown.
+func (m LatencyMode) interceptorFactories() (map[string]interceptor.Factory, error) {
+	if m == LatencyModeUnknown {
+		return nil, nil //nolint:nilnil
+	}
+
+	generatorOptions, responderOptions := m.nackOptions()
+
+	generator, err := nack.NewGeneratorInterceptor(generatorOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	responder, err := nack.NewResponderInterceptor(responderOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	factories := map[string]interceptor.Factory{
+		InterceptorNameNACKResponder: responder,
+		InterceptorNameNACKGenerator: generator,
+	}
+
+	if fecOptions, ok := m.fecOptions(); ok {
+		if factories[InterceptorNameULPFEC], err = ulpfec.NewInterceptor(fecOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	return factories, nil
+}
+
+// latencyModeInterceptorNames are the names of the interceptors configured
+// by a LatencyMode, in the order they are added.
+var latencyModeInterceptorNames = []string{ //nolint:gochecknoglobals
+	InterceptorNameNACKResponder,
+	InterceptorNameNACKGenerator,
+	InterceptorNameULPFEC,
+}
+
+// configureLatencyMode adds the interceptors of the LatencyMode that the
+// registry of the API doesn't have, the ones it has were built with the
+// options of the mode already. NACK and RED with ULPFEC are registered with
+// the MediaEngine, the payload types are picked among the free ones.
+func (pc *PeerConnection) configureLatencyMode(
+	i interceptor.Interceptor,
+	factories map[string]interceptor.Factory,
+) (interceptor.Interceptor, error) {
+	if len(factories) == 0 {
+		return i, nil
+	}
+
+	built, disabled := map[string]bool{}, map[string]bool{}
+	for _, name := range pc.interceptorNames {
+		built[name] = true
+	}
+	for _, name := range pc.configuration.DisabledInterceptors {
+		disabled[name] = true
+	}
+
+	interceptors := []interceptor.Interceptor{i}
+	for _, name := range latencyModeInterceptorNames {
+		factory, ok := factories[name]
+		if !ok || disabled[name] {
+			continue
+		}
+
+		switch name {
+		case InterceptorNameNACKGenerator:
+			pc.api.mediaEngine.RegisterFeedback(RTCPFeedback{Type: "nack"}, RTPCodecTypeVideo)
+			pc.api.mediaEngine.RegisterFeedback(RTCPFeedback{Type: "nack", Parameter: "pli"}, RTPCodecTypeVideo)
+		case InterceptorNameULPFEC:
+			if err := pc.api.mediaEngine.registerULPFEC(); err != nil {
+				return nil, err
+			}
+		}
+
+		if built[name] {
+			continue
+		}
+
+		added, err := factory.NewInterceptor("")
+		if err != nil {
+			return nil, err
+		}
+		interceptors = append(interceptors, added)
+		pc.interceptorNames = append(pc.interceptorNames, name)
+	}
+
+	if len(interceptors) == 1 {
+		return i, nil
+	}
+
+	return interceptor.NewChain(interceptors), nil
+}
+
+// registerULPFEC registers RED and ULPFEC for video with free payload types,
+// unless ULPFEC is registered already.
+func (m *MediaEngine) registerULPFEC() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hasRED := false
+	for _, codec := range m.videoCodecs {
+		switch {
+		case strings.EqualFold(codec.MimeType, MimeTypeULPFEC):
+			return nil
+		case strings.EqualFold(codec.MimeType, MimeTypeVideoRED):
+			hasRED = true
+		}
+	}
+
+	mimeTypes := []string{MimeTypeULPFEC}
+	if !hasRED {
+		mimeTypes = []string{MimeTypeVideoRED, MimeTypeULPFEC}
+	}
+
+	for _, mimeType := range mimeTypes {
+		payloadType, err := m.freePayloadType()
+		if err != nil {
+			return err
+		}
+
+		m.videoCodecs = m.addCodec(m.videoCodecs, RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeType: mimeType, ClockRate: 90000},
+			PayloadType:        payloadType,
+			statsID:            fmt.Sprintf("RTPCodec-%d", time.Now().UnixNano()),
+		})
+	}
+
+	return nil
+}
+
+// freePayloadType returns the lowest dynamic payload type that no registered
+// codec uses and that isn't pinned, for codecs registered on behalf of the
+// application. The caller must hold m.mu.
+func (m *MediaEngine) freePayloadType() (PayloadType, error) {
+	used := map[PayloadType]bool{}
+	for _, codec := range append(append([]RTPCodecParameters{}, m.audioCodecs...), m.videoCodecs...) {
+		used[codec.PayloadType] = true
+	}
+	for payloadType := range m.pinnedPayloadTypes {
+		used[payloadType] = true
+	}
+
+	// RFC 3551 Section 6, the dynamic range
+	for payloadType := PayloadType(96); payloadType <= 127; payloadType++ {
+		if !used[payloadType] {
+			return payloadType, nil
+		}
+	}
+
+	return 0, errMediaEngineNoFreePayloadType
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/interceptor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyMode_String(t *testing.T) {
+	testCases := []struct {
+		mode           LatencyMode
+		expectedString string
+	}{
+		{LatencyModeUnknown, ErrUnknownType.Error()},
+		{LatencyModeRealtime, "realtime"},
+		{LatencyModeBalanced, "balanced"},
+		{LatencyModeQuality, "quality"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t,
+			testCase.expectedString,
+			testCase.mode.String(),
+			"testCase: %d %v", i, testCase,
+		)
+	}
+}
+
+func TestLatencyMode_JitterBufferConfig(t *testing.T) {
+	assert.Equal(t, JitterBufferConfig{}, LatencyModeBalanced.jitterBufferConfig(RTPCodecTypeVideo))
+
+	realtime := LatencyModeRealtime.jitterBufferConfig(RTPCodecTypeVideo)
+	quality := LatencyModeQuality.jitterBufferConfig(RTPCodecTypeVideo)
+	assert.Less(t, realtime.MaxLate, quality.MaxLate)
+	assert.Less(t, realtime.MaxLatency, quality.MaxLatency)
+	assert.Less(t, LatencyModeRealtime.jitterBufferConfig(RTPCodecTypeAudio).MaxLatency, realtime.MaxLatency)
+}
+
+func TestPeerConnection_LatencyMode(t *testing.T) {
+	for _, test := range []struct {
+		mode LatencyMode
+		fec  bool
+	}{
+		{LatencyModeRealtime, true},
+		{LatencyModeBalanced, false},
+		{LatencyModeQuality, true},
+	} {
+		t.Run(test.mode.String(), func(t *testing.T) {
+			mediaEngine := &MediaEngine{}
+			assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+			registry := &interceptor.Registry{}
+			assert.NoError(t, RegisterDefaultInterceptors(mediaEngine, registry))
+			api := NewAPI(WithMediaEngine(mediaEngine), WithInterceptorRegistry(registry))
+
+			pc, err := api.NewPeerConnection(Configuration{LatencyMode: test.mode})
+			assert.NoError(t, err)
+
+			// The registered NACK interceptors are replaced, not added twice
+			interceptors := pc.Interceptors()
+			assert.Equal(t, 1, countString(interceptors, InterceptorNameNACKGenerator))
+			assert.Equal(t, 1, countString(interceptors, InterceptorNameNACKResponder))
+			assert.Equal(t, test.fec, countString(interceptors, InterceptorNameULPFEC) == 1)
+			assert.Equal(t, test.mode, pc.api.latencyMode)
+
+			// RED and ULPFEC get payload types no other codec uses
+			payloadTypes := map[PayloadType]bool{}
+			hasULPFEC := false
+			for _, codec := range pc.api.mediaEngine.getCodecsByKind(RTPCodecTypeVideo) {
+				assert.False(t, payloadTypes[codec.PayloadType], "payload type %d used twice", codec.PayloadType)
+				payloadTypes[codec.PayloadType] = true
+				hasULPFEC = hasULPFEC || codec.MimeType == MimeTypeULPFEC
+			}
+			assert.Equal(t, test.fec, hasULPFEC)
+
+			// The API's MediaEngine is left alone
+			for _, codec := range mediaEngine.getCodecsByKind(RTPCodecTypeVideo) {
+				assert.NotEqual(t, MimeTypeULPFEC, codec.MimeType)
+			}
+
+			other := LatencyModeRealtime
+			if test.mode == LatencyModeRealtime {
+				other = LatencyModeQuality
+			}
+			assert.ErrorIs(t, pc.SetConfiguration(Configuration{LatencyMode: other}), ErrModifyingLatencyMode)
+
+			assert.NoError(t, pc.Close())
+		})
+	}
+}
+
+func countString(values []string, value string) (count int) {
+	for _, v := range values {
+		if v == value {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestRTPReceiver_LatencyMode(t *testing.T) {
+	api := NewAPI()
+	api.latencyMode = LatencyModeRealtime
+
+	receiver := &RTPReceiver{kind: RTPCodecTypeVideo, api: api}
+	receiver.configureReceive(RTPReceiveParameters{Encodings: []RTPDecodingParameters{{
+		RTPCodingParameters: RTPCodingParameters{SSRC: 1},
+	}}})
+	assert.Equal(t, LatencyModeRealtime.jitterBufferConfig(RTPCodecTypeVideo), receiver.Track().jitterBufferConfig)
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/webrtc/v4/pkg/ulpfec"
+)
+
+// lossStatsWindow is how many sequence numbers behind the newest packet a
+// lost packet can still be repaired before it counts as concealed.
+const lossStatsWindow = 64
+
+// lossStats counts how the packets a TrackRemote hands to the application
+// were repaired, and the losses that weren't repaired and are left to the
+// decoder to conceal.
+type lossStats struct {
+	mu sync.Mutex
+
+	recoveredByFEC uint32
+	recoveredByRTX uint32
+	concealedGaps  uint32
+	concealed      uint32
+
+	started bool
+	newest  uint16
+	// received has bit i set if newest-i was received.
+	received uint64
+	// inGap is true if the last packet to leave the window was concealed.
+	inGap bool
+}
+
+func (l *lossStats) add(packet []byte, attributes interceptor.Attributes) {
+	if len(packet) < 4 {
+		return
+	}
+	sequenceNumber := binary.BigEndian.Uint16(packet[2:4])
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case attributes.Get(ulpfec.AttributeRecovered) == true:
+		l.recoveredByFEC++
+	case attributes.Get(AttributeRtxSequenceNumber) != nil:
+		l.recoveredByRTX++
+	}
+
+	if !l.started {
+		l.started, l.newest, l.received = true, sequenceNumber, ^uint64(0)
+
+		return
+	}
+
+	if diff := sequenceNumber - l.newest; diff != 0 && diff < 1<<15 {
+		l.advance(diff)
+		l.newest = sequenceNumber
+		l.received |= 1
+	} else if behind := l.newest - sequenceNumber; behind < lossStatsWindow {
+		l.received |= 1 << behind
+	}
+}
+
+// advance moves the window forward by n sequence numbers, the packets that
+// leave it without being received are concealed.
+func (l *lossStats) advance(n uint16) {
+	for ; n > 0; n-- {
+		missing := l.received&(1<<(lossStatsWindow-1)) == 0
+		if missing {
+			l.concealed++
+			if !l.inGap {
+				l.concealedGaps++
+			}
+		}
+		l.inGap = missing
+		l.received <<= 1
+	}
+}
+
+// collectStats sets the repair and concealment counters of stats.
+func (l *lossStats) collectStats(stats *InboundRTPStreamStats) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats.PacketsRecoveredByFEC = l.recoveredByFEC
+	stats.PacketsRecoveredByRTX = l.recoveredByRTX
+	stats.PacketsRepaired = l.recoveredByFEC + l.recoveredByRTX
+	stats.ConcealedGaps = l.concealedGaps
+	stats.PacketsConcealed = l.concealed
+}
+
+func (t *TrackRemote) inboundRTPStreamStatsID() string {
+	return fmt.Sprintf("InboundRTPStream-%d", t.SSRC())
+}
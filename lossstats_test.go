@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/webrtc/v4/pkg/ulpfec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLossStats(t *testing.T) {
+	packet := func(sequenceNumber uint16) []byte {
+		b := make([]byte, 12)
+		binary.BigEndian.PutUint16(b[2:4], sequenceNumber)
+
+		return b
+	}
+
+	stats := &lossStats{}
+	for _, sequenceNumber := range []uint16{65534, 65535, 2, 3} {
+		stats.add(packet(sequenceNumber), interceptor.Attributes{})
+	}
+	stats.add(packet(1), interceptor.Attributes{AttributeRtxSequenceNumber: uint16(1)})
+	stats.add(packet(5), interceptor.Attributes{ulpfec.AttributeRecovered: true})
+	stats.add(packet(6), interceptor.Attributes{})
+
+	inbound := InboundRTPStreamStats{}
+	stats.collectStats(&inbound)
+	assert.Equal(t, uint32(1), inbound.PacketsRecoveredByRTX)
+	assert.Equal(t, uint32(1), inbound.PacketsRecoveredByFEC)
+	assert.Equal(t, uint32(2), inbound.PacketsRepaired)
+
+	// Nothing is concealed while packets can still be repaired
+	assert.Equal(t, uint32(0), inbound.PacketsConcealed)
+
+	// 0 and 4 leave the window without being received
+	stats.add(packet(6+lossStatsWindow), interceptor.Attributes{})
+	stats.collectStats(&inbound)
+	assert.Equal(t, uint32(2), inbound.ConcealedGaps)
+	assert.Equal(t, uint32(2), inbound.PacketsConcealed)
+
+	// 7 to 69 are one gap
+	stats.add(packet(6+2*lossStatsWindow), interceptor.Attributes{})
+	stats.collectStats(&inbound)
+	assert.Equal(t, uint32(3), inbound.ConcealedGaps)
+	assert.Equal(t, uint32(2+lossStatsWindow-1), inbound.PacketsConcealed)
+}
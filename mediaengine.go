@@ -19,48 +19,16 @@ import (
 	"github.com/pion/webrtc/v4/internal/fmtp"
 )
 
-const (
-	// MimeTypeH264 H264 MIME type.
-	// Note: Matching should be case insensitive.
-	MimeTypeH264 = "video/H264"
-	// MimeTypeH265 H265 MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypeH265 = "video/H265"
-	// MimeTypeOpus Opus MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypeOpus = "audio/opus"
-	// MimeTypeVP8 VP8 MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypeVP8 = "video/VP8"
-	// MimeTypeVP9 VP9 MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypeVP9 = "video/VP9"
-	// MimeTypeAV1 AV1 MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypeAV1 = "video/AV1"
-	// MimeTypeG722 G722 MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypeG722 = "audio/G722"
-	// MimeTypePCMU PCMU MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypePCMU = "audio/PCMU"
-	// MimeTypePCMA PCMA MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypePCMA = "audio/PCMA"
-	// MimeTypeRTX RTX MIME type
-	// Note: Matching should be case insensitive.
-	MimeTypeRTX = "video/rtx"
-	// MimeTypeFlexFEC FEC MIME Type
-	// Note: Matching should be case insensitive.
-	MimeTypeFlexFEC = "video/flexfec"
-)
-
 type mediaEngineHeaderExtension struct {
 	uri              string
 	isAudio, isVideo bool
 
 	// If set only Transceivers of this direction are allowed
 	allowedDirections []RTPTransceiverDirection
+
+	// Set if the extension is only negotiated by the transceivers that add
+	// it with RTPTransceiver.AddHeaderExtension
+	isAudioPerTransceiver, isVideoPerTransceiver bool
 }
 
 // A MediaEngine defines the codecs supported by a PeerConnection, and the
@@ -75,6 +43,17 @@ type MediaEngine struct {
 	headerExtensions           []mediaEngineHeaderExtension
 	negotiatedHeaderExtensions map[int]mediaEngineHeaderExtension
 
+	codecMatcher CodecMatcher
+
+	// Set by SetOpusParameters
+	opusParameters *OpusParameters
+
+	// Payload types pinned with PinPayloadType, and the MimeType of their codec
+	pinnedPayloadTypes map[PayloadType]string
+
+	// Workarounds of the quirks enabled for the remote endpoint
+	quirks quirkWorkarounds
+
 	mu sync.RWMutex
 }
 
@@ -260,6 +239,10 @@ func (m *MediaEngine) RegisterCodec(codec RTPCodecParameters, typ RTPCodecType)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if err := m.checkPinnedPayloadType(codec); err != nil {
+		return err
+	}
+
 	codec.statsID = fmt.Sprintf("RTPCodec-%d", time.Now().UnixNano())
 	switch typ {
 	case RTPCodecTypeAudio:
@@ -273,6 +256,48 @@ func (m *MediaEngine) RegisterCodec(codec RTPCodecParameters, typ RTPCodecType)
 	return nil
 }
 
+// RegisterOpusRED registers RED (RFC 2198) carrying the Opus codec that is
+// already registered with the MediaEngine. RED is registered ahead of Opus so
+// remote peers prefer it when sending, received RED packets can be unwrapped
+// with pkg/media/red. Local tracks only send RED if created with WithRED.
+func (m *MediaEngine) RegisterOpusRED(payloadType PayloadType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var opus *RTPCodecParameters
+	for i := range m.audioCodecs {
+		if strings.EqualFold(m.audioCodecs[i].MimeType, MimeTypeOpus) {
+			opus = &m.audioCodecs[i]
+
+			break
+		}
+	}
+	if opus == nil {
+		return errMediaEngineOpusNotRegistered
+	}
+
+	for _, c := range m.audioCodecs {
+		if strings.EqualFold(c.MimeType, MimeTypeRED) {
+			return nil
+		}
+	}
+
+	opusPayloadType := strconv.Itoa(int(opus.PayloadType))
+	red := RTPCodecParameters{
+		RTPCodecCapability: RTPCodecCapability{
+			MimeType:    MimeTypeRED,
+			ClockRate:   opus.ClockRate,
+			Channels:    opus.Channels,
+			SDPFmtpLine: opusPayloadType + "/" + opusPayloadType,
+		},
+		PayloadType: payloadType,
+		statsID:     fmt.Sprintf("RTPCodec-%d", time.Now().UnixNano()),
+	}
+	m.audioCodecs = append([]RTPCodecParameters{red}, m.audioCodecs...)
+
+	return nil
+}
+
 // RegisterHeaderExtension adds a header extension to the MediaEngine
 // To determine the negotiated value use `GetHeaderExtensionID` after signaling is complete.
 //
@@ -313,8 +338,10 @@ func (m *MediaEngine) RegisterHeaderExtension(
 
 	if typ == RTPCodecTypeAudio {
 		m.headerExtensions[extensionIndex].isAudio = true
+		m.headerExtensions[extensionIndex].isAudioPerTransceiver = false
 	} else if typ == RTPCodecTypeVideo {
 		m.headerExtensions[extensionIndex].isVideo = true
+		m.headerExtensions[extensionIndex].isVideoPerTransceiver = false
 	}
 
 	m.headerExtensions[extensionIndex].uri = extension.URI
@@ -323,24 +350,106 @@ func (m *MediaEngine) RegisterHeaderExtension(
 	return nil
 }
 
+// registerTransceiverHeaderExtension adds a header extension that is only
+// negotiated by the transceivers that add it, unless it is registered with
+// RegisterHeaderExtension for typ.
+func (m *MediaEngine) registerTransceiverHeaderExtension(extension RTPHeaderExtensionCapability, typ RTPCodecType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.negotiatedHeaderExtensions == nil {
+		m.negotiatedHeaderExtensions = map[int]mediaEngineHeaderExtension{}
+	}
+
+	extensionIndex := -1
+	for i := range m.headerExtensions {
+		if extension.URI == m.headerExtensions[i].uri {
+			extensionIndex = i
+		}
+	}
+
+	if extensionIndex == -1 {
+		m.headerExtensions = append(m.headerExtensions, mediaEngineHeaderExtension{
+			uri:               extension.URI,
+			allowedDirections: []RTPTransceiverDirection{RTPTransceiverDirectionRecvonly, RTPTransceiverDirectionSendonly},
+		})
+		extensionIndex = len(m.headerExtensions) - 1
+	}
+
+	headerExtension := &m.headerExtensions[extensionIndex]
+	if typ == RTPCodecTypeAudio && !headerExtension.isAudio {
+		headerExtension.isAudio = true
+		headerExtension.isAudioPerTransceiver = true
+	} else if typ == RTPCodecTypeVideo && !headerExtension.isVideo {
+		headerExtension.isVideo = true
+		headerExtension.isVideoPerTransceiver = true
+	}
+}
+
+// isPerTransceiverHeaderExtension returns true if the header extension is
+// only negotiated by the transceivers of typ that add it.
+func (m *MediaEngine) isPerTransceiverHeaderExtension(uri string, typ RTPCodecType) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, headerExtension := range m.headerExtensions {
+		if headerExtension.uri == uri {
+			return typ == RTPCodecTypeAudio && headerExtension.isAudioPerTransceiver ||
+				typ == RTPCodecTypeVideo && headerExtension.isVideoPerTransceiver
+		}
+	}
+
+	return false
+}
+
 // RegisterFeedback adds feedback mechanism to already registered codecs.
+// Codecs that already have the feedback are left unchanged.
 func (m *MediaEngine) RegisterFeedback(feedback RTCPFeedback, typ RTPCodecType) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if typ == RTPCodecTypeVideo {
 		for i, v := range m.videoCodecs {
-			v.RTCPFeedback = append(v.RTCPFeedback, feedback)
+			v.RTCPFeedback = appendFeedback(v.RTCPFeedback, feedback)
 			m.videoCodecs[i] = v
 		}
 	} else if typ == RTPCodecTypeAudio {
 		for i, v := range m.audioCodecs {
-			v.RTCPFeedback = append(v.RTCPFeedback, feedback)
+			v.RTCPFeedback = appendFeedback(v.RTCPFeedback, feedback)
 			m.audioCodecs[i] = v
 		}
 	}
 }
 
+// appendFeedback appends feedback to feedbacks unless it is already present.
+func appendFeedback(feedbacks []RTCPFeedback, feedback RTCPFeedback) []RTCPFeedback {
+	for _, fb := range feedbacks {
+		if fb == feedback {
+			return feedbacks
+		}
+	}
+
+	return append(feedbacks, feedback)
+}
+
+// hasFeedback returns true if any codec has the feedback registered.
+func (m *MediaEngine) hasFeedback(feedback RTCPFeedback) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, codecs := range [][]RTPCodecParameters{m.videoCodecs, m.audioCodecs} {
+		for _, codec := range codecs {
+			for _, fb := range codec.RTCPFeedback {
+				if fb == feedback {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 // getHeaderExtensionID returns the negotiated ID for a header extension.
 // If the Header Extension isn't enabled ok will be false.
 func (m *MediaEngine) getHeaderExtensionID(extension RTPHeaderExtensionCapability) (
@@ -372,6 +481,17 @@ func (m *MediaEngine) copy() *MediaEngine {
 		videoCodecs:      append([]RTPCodecParameters{}, m.videoCodecs...),
 		audioCodecs:      append([]RTPCodecParameters{}, m.audioCodecs...),
 		headerExtensions: append([]mediaEngineHeaderExtension{}, m.headerExtensions...),
+		codecMatcher:     m.codecMatcher,
+	}
+	if len(m.pinnedPayloadTypes) > 0 {
+		cloned.pinnedPayloadTypes = map[PayloadType]string{}
+		for payloadType, mimeType := range m.pinnedPayloadTypes {
+			cloned.pinnedPayloadTypes[payloadType] = mimeType
+		}
+	}
+	if m.opusParameters != nil {
+		params := *m.opusParameters
+		cloned.opusParameters = &params
 	}
 	if len(m.headerExtensions) > 0 {
 		cloned.negotiatedHeaderExtensions = map[int]mediaEngineHeaderExtension{}
@@ -446,6 +566,38 @@ func (m *MediaEngine) collectStats(collector *statsReportCollector) {
 	statsLoop(m.audioCodecs)
 }
 
+// SetCodecMatcher replaces how codecs of a remote description are matched
+// against the codecs registered in the MediaEngine. Matching of a media section
+// follows these rules, only the comparison of two codecs is done by the matcher:
+//
+//   - Codecs are matched in the order of the media section, except codecs with
+//     an apt parameter (RTX) that are matched after the codec they reference.
+//   - A codec with an apt parameter only matches if the referenced codec matched,
+//     and it never matches better than the referenced codec.
+//   - If any codec matched exactly only exact matches are negotiated, otherwise
+//     the partial matches are.
+//
+// A nil matcher restores the default, which compares MimeType and fmtp parameters
+// and falls back to the MimeType. The matcher is called with the lock of the
+// MediaEngine held, so it must not call methods of the MediaEngine.
+func (m *MediaEngine) SetCodecMatcher(matcher CodecMatcher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.codecMatcher = matcher
+}
+
+func (m *MediaEngine) matchCodec(
+	remoteCodec RTPCodecParameters,
+	codecs []RTPCodecParameters,
+) (RTPCodecParameters, CodecMatchType) {
+	if m.codecMatcher != nil {
+		return m.codecMatcher(remoteCodec, codecs)
+	}
+
+	return codecParametersFuzzySearch(remoteCodec, codecs)
+}
+
 // Look up a codec and enable if it exists.
 //
 //nolint:cyclop
@@ -453,7 +605,7 @@ func (m *MediaEngine) matchRemoteCodec(
 	remoteCodec RTPCodecParameters,
 	typ RTPCodecType,
 	exactMatches, partialMatches []RTPCodecParameters,
-) (RTPCodecParameters, codecMatchType, error) {
+) (RTPCodecParameters, CodecMatchType, error) {
 	codecs := m.videoCodecs
 	if typ == RTPCodecTypeAudio {
 		codecs = m.audioCodecs
@@ -463,24 +615,24 @@ func (m *MediaEngine) matchRemoteCodec(
 	if apt, hasApt := remoteFmtp.Parameter("apt"); hasApt { //nolint:nestif
 		payloadType, err := strconv.ParseUint(apt, 10, 8)
 		if err != nil {
-			return RTPCodecParameters{}, codecMatchNone, err
+			return RTPCodecParameters{}, CodecMatchNone, err
 		}
 
-		aptMatch := codecMatchNone
+		aptMatch := CodecMatchNone
 		var aptCodec RTPCodecParameters
 		for _, codec := range exactMatches {
 			if codec.PayloadType == PayloadType(payloadType) {
-				aptMatch = codecMatchExact
+				aptMatch = CodecMatchExact
 				aptCodec = codec
 
 				break
 			}
 		}
 
-		if aptMatch == codecMatchNone {
+		if aptMatch == CodecMatchNone {
 			for _, codec := range partialMatches {
 				if codec.PayloadType == PayloadType(payloadType) {
-					aptMatch = codecMatchPartial
+					aptMatch = CodecMatchPartial
 					aptCodec = codec
 
 					break
@@ -488,13 +640,13 @@ func (m *MediaEngine) matchRemoteCodec(
 			}
 		}
 
-		if aptMatch == codecMatchNone {
-			return RTPCodecParameters{}, codecMatchNone, nil // not an error, we just ignore this codec we don't support
+		if aptMatch == CodecMatchNone {
+			return RTPCodecParameters{}, CodecMatchNone, nil // not an error, we just ignore this codec we don't support
 		}
 
 		// replace the apt value with the original codec's payload type
 		toMatchCodec := remoteCodec
-		if aptMatched, mt := codecParametersFuzzySearch(aptCodec, codecs); mt == aptMatch {
+		if aptMatched, mt := m.matchCodec(aptCodec, codecs); mt == aptMatch {
 			toMatchCodec.SDPFmtpLine = strings.Replace(
 				toMatchCodec.SDPFmtpLine,
 				fmt.Sprintf("apt=%d", payloadType),
@@ -504,19 +656,42 @@ func (m *MediaEngine) matchRemoteCodec(
 		}
 
 		// if apt's media codec is partial match, then apt codec must be partial match too.
-		localCodec, matchType := codecParametersFuzzySearch(toMatchCodec, codecs)
-		if matchType == codecMatchExact && aptMatch == codecMatchPartial {
-			matchType = codecMatchPartial
+		localCodec, matchType := m.matchCodec(toMatchCodec, codecs)
+		if matchType == CodecMatchExact && aptMatch == CodecMatchPartial {
+			matchType = CodecMatchPartial
 		}
 
 		return localCodec, matchType, nil
 	}
 
-	localCodec, matchType := codecParametersFuzzySearch(remoteCodec, codecs)
+	localCodec, matchType := m.matchCodec(remoteCodec, codecs)
 
 	return localCodec, matchType, nil
 }
 
+// aptResolved returns true if codecs[idx] doesn't reference another codec with
+// an apt parameter or if the referenced codec has already been matched.
+func aptResolved(codecs []RTPCodecParameters, idx int, matched []bool) bool {
+	apt, hasApt := fmtp.Parse(codecs[idx].MimeType, codecs[idx].SDPFmtpLine).Parameter("apt")
+	if !hasApt {
+		return true
+	}
+
+	payloadType, err := strconv.ParseUint(apt, 10, 8)
+	if err != nil {
+		// matchRemoteCodec reports the error
+		return true
+	}
+
+	for i, codec := range codecs {
+		if codec.PayloadType == PayloadType(payloadType) && i != idx {
+			return matched[i]
+		}
+	}
+
+	return true
+}
+
 // Update header extensions from a remote media section.
 func (m *MediaEngine) updateHeaderExtensionFromMediaSection(media *sdp.MediaDescription) error {
 	var typ RTPCodecType
@@ -542,6 +717,17 @@ func (m *MediaEngine) updateHeaderExtensionFromMediaSection(media *sdp.MediaDesc
 	return nil
 }
 
+// negotiateHeaderExtensions updates the header extensions from a remote media
+// section again, to negotiate the ones registered after the remote
+// description was set, like the ones added with
+// RTPTransceiver.AddHeaderExtension before answering.
+func (m *MediaEngine) negotiateHeaderExtensions(media *sdp.MediaDescription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.updateHeaderExtensionFromMediaSection(media)
+}
+
 // Look up a header extension and enable if it exists.
 func (m *MediaEngine) updateHeaderExtension(id int, extension string, typ RTPCodecType) error {
 	if m.negotiatedHeaderExtensions == nil {
@@ -615,22 +801,60 @@ func (m *MediaEngine) updateFromRemoteDescription(desc sdp.SessionDescription) e
 		if err != nil {
 			return err
 		}
+		codecs = m.quirks.apply(codecs)
 
 		exactMatches := make([]RTPCodecParameters, 0, len(codecs))
 		partialMatches := make([]RTPCodecParameters, 0, len(codecs))
 
-		for _, remoteCodec := range codecs {
-			localCodec, matchType, mErr := m.matchRemoteCodec(remoteCodec, typ, exactMatches, partialMatches)
-			if mErr != nil {
-				return mErr
-			}
+		// Codecs with an apt are matched once the codec they reference has been,
+		// so the order of the media section doesn't change the result.
+		matched := make([]bool, len(codecs))
+		matchTypes := make([]CodecMatchType, len(codecs))
+		localPayloadTypes := make([]PayloadType, len(codecs))
+		for progress := true; progress; {
+			progress = false
+			for i := range codecs {
+				if matched[i] || !aptResolved(codecs, i, matched) {
+					continue
+				}
+
+				if err = m.checkPinnedPayloadType(codecs[i]); err != nil {
+					return err
+				}
 
-			remoteCodec.RTCPFeedback = rtcpFeedbackIntersection(localCodec.RTCPFeedback, remoteCodec.RTCPFeedback)
+				localCodec, matchType, mErr := m.matchRemoteCodec(codecs[i], typ, exactMatches, partialMatches)
+				if mErr != nil {
+					return mErr
+				}
+				if m.isPinnedElsewhere(codecs[i]) {
+					matchType = CodecMatchNone
+				}
 
-			if matchType == codecMatchExact {
-				exactMatches = append(exactMatches, remoteCodec)
-			} else if matchType == codecMatchPartial {
-				partialMatches = append(partialMatches, remoteCodec)
+				codecs[i].RTCPFeedback = rtcpFeedbackIntersection(localCodec.RTCPFeedback, codecs[i].RTCPFeedback)
+				matched[i], matchTypes[i], progress = true, matchType, true
+				localPayloadTypes[i] = localCodec.PayloadType
+
+				if matchType == CodecMatchExact {
+					exactMatches = append(exactMatches, codecs[i])
+				} else if matchType == CodecMatchPartial {
+					partialMatches = append(partialMatches, codecs[i])
+				}
+			}
+		}
+
+		if err = m.checkPinnedCodecsNegotiated(codecs, matchTypes); err != nil {
+			return err
+		}
+		m.quirks.applyMirrorPayloadTypes(codecs, localPayloadTypes, matchTypes)
+		m.answerOpusParameters(codecs)
+
+		// Keep the order of the media section
+		exactMatches, partialMatches = exactMatches[:0], partialMatches[:0]
+		for i, matchType := range matchTypes {
+			if matchType == CodecMatchExact {
+				exactMatches = append(exactMatches, codecs[i])
+			} else if matchType == CodecMatchPartial {
+				partialMatches = append(partialMatches, codecs[i])
 			}
 		}
 
@@ -779,6 +1003,23 @@ func payloaderForCodec(codec RTPCodecCapability) (rtp.Payloader, error) {
 	}
 }
 
+func depacketizerForCodec(codec RTPCodecCapability) (rtp.Depacketizer, error) {
+	switch strings.ToLower(codec.MimeType) {
+	case strings.ToLower(MimeTypeH264):
+		return &codecs.H264Packet{}, nil
+	case strings.ToLower(MimeTypeOpus):
+		return &codecs.OpusPacket{}, nil
+	case strings.ToLower(MimeTypeVP8):
+		return &codecs.VP8Packet{}, nil
+	case strings.ToLower(MimeTypeVP9):
+		return &codecs.VP9Packet{}, nil
+	case strings.ToLower(MimeTypeAV1):
+		return &codecs.AV1Depacketizer{}, nil
+	default:
+		return nil, ErrNoDepacketizerForCodec
+	}
+}
+
 func (m *MediaEngine) isRTXEnabled(typ RTPCodecType, directions []RTPTransceiverDirection) bool {
 	for _, p := range m.getRTPParametersByKind(typ, directions).Codecs {
 		if p.MimeType == MimeTypeRTX {
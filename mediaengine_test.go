@@ -478,6 +478,80 @@ a=fmtp:97 apt=96
 		_, _, err := mediaEngine.getCodecByPayload(97)
 		assert.ErrorIs(t, err, ErrCodecNotFound)
 	})
+
+	t.Run("Matches rtx listed before its codec", func(t *testing.T) {
+		const rtxFirst = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=video 60323 UDP/TLS/RTP/SAVPF 97 95 96 94
+a=rtpmap:97 rtx/90000
+a=fmtp:97 apt=96
+a=rtpmap:95 rtx/90000
+a=fmtp:95 apt=94
+a=rtpmap:96 VP8/90000
+a=rtpmap:94 VP9/90000
+a=fmtp:94 profile-id=0
+`
+		mediaEngine := MediaEngine{}
+		assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+		assert.NoError(t, mediaEngine.updateFromRemoteDescription(mustParse(rtxFirst)))
+
+		vp8RTX, _, err := mediaEngine.getCodecByPayload(97)
+		assert.NoError(t, err)
+		assert.Equal(t, "apt=96", vp8RTX.SDPFmtpLine)
+
+		vp9RTX, _, err := mediaEngine.getCodecByPayload(95)
+		assert.NoError(t, err)
+		assert.Equal(t, "apt=94", vp9RTX.SDPFmtpLine)
+
+		// Order of the media section is kept
+		assert.Equal(t, PayloadType(97), mediaEngine.negotiatedVideoCodecs[0].PayloadType)
+		assert.Equal(t, PayloadType(94), mediaEngine.negotiatedVideoCodecs[3].PayloadType)
+	})
+
+	t.Run("Custom codec matcher", func(t *testing.T) {
+		const profileLevels = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=video 60323 UDP/TLS/RTP/SAVPF 96 97
+a=rtpmap:96 VP9/90000
+a=fmtp:96 profile-id=2
+a=rtpmap:97 rtx/90000
+a=fmtp:97 apt=96
+`
+		mediaEngine := MediaEngine{}
+		assert.NoError(t, mediaEngine.RegisterCodec(RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeVP9, 90000, 0, "profile-id=0", nil},
+			PayloadType:        98,
+		}, RTPCodecTypeVideo))
+		assert.NoError(t, mediaEngine.RegisterCodec(RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeRTX, 90000, 0, "apt=98", nil},
+			PayloadType:        99,
+		}, RTPCodecTypeVideo))
+
+		// Treat every codec with the same MimeType as an exact match
+		mediaEngine.SetCodecMatcher(func(
+			remote RTPCodecParameters, local []RTPCodecParameters,
+		) (RTPCodecParameters, CodecMatchType) {
+			for _, codec := range local {
+				if strings.EqualFold(codec.MimeType, remote.MimeType) {
+					return codec, CodecMatchExact
+				}
+			}
+
+			return RTPCodecParameters{}, CodecMatchNone
+		})
+
+		cloned := mediaEngine.copy()
+		assert.NoError(t, cloned.updateFromRemoteDescription(mustParse(profileLevels)))
+
+		_, _, err := cloned.getCodecByPayload(96)
+		assert.NoError(t, err)
+		_, _, err = cloned.getCodecByPayload(97)
+		assert.NoError(t, err)
+	})
 }
 
 func TestMediaEngineHeaderExtensionDirection(t *testing.T) {
@@ -592,6 +666,40 @@ func TestMediaEngineDoubleRegister(t *testing.T) {
 	assert.Equal(t, len(mediaEngine.audioCodecs), 1)
 }
 
+func TestMediaEngineRegisterFeedbackTwice(t *testing.T) {
+	mediaEngine := MediaEngine{}
+
+	assert.NoError(t, mediaEngine.RegisterCodec(
+		RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeVP8, 90000, 0, "", []RTCPFeedback{{Type: "nack"}}},
+			PayloadType:        96,
+		}, RTPCodecTypeVideo))
+
+	mediaEngine.RegisterFeedback(RTCPFeedback{Type: "nack"}, RTPCodecTypeVideo)
+	mediaEngine.RegisterFeedback(RTCPFeedback{Type: "nack", Parameter: "pli"}, RTPCodecTypeVideo)
+	mediaEngine.RegisterFeedback(RTCPFeedback{Type: "nack", Parameter: "pli"}, RTPCodecTypeVideo)
+
+	assert.Equal(t,
+		[]RTCPFeedback{{Type: "nack"}, {Type: "nack", Parameter: "pli"}},
+		mediaEngine.videoCodecs[0].RTCPFeedback,
+	)
+}
+
+func TestMediaEngineRegisterOpusRED(t *testing.T) {
+	mediaEngine := MediaEngine{}
+	assert.ErrorIs(t, mediaEngine.RegisterOpusRED(63), errMediaEngineOpusNotRegistered)
+
+	assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	assert.NoError(t, mediaEngine.RegisterOpusRED(63))
+	assert.NoError(t, mediaEngine.RegisterOpusRED(63))
+
+	// RED is preferred over the codec it carries
+	assert.Equal(t, MimeTypeRED, mediaEngine.audioCodecs[0].MimeType)
+	assert.Equal(t, PayloadType(63), mediaEngine.audioCodecs[0].PayloadType)
+	assert.Equal(t, "111/111", mediaEngine.audioCodecs[0].SDPFmtpLine)
+	assert.Equal(t, MimeTypeOpus, mediaEngine.audioCodecs[1].MimeType)
+}
+
 // The cloned MediaEngine instance should be able to update negotiated header extensions.
 func TestUpdateHeaderExtenstionToClonedMediaEngine(t *testing.T) {
 	src := MediaEngine{}
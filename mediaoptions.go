@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+)
+
+// WithMediaCodec registers codec with the MediaEngine of a PeerConnection
+// created with NewPeerConnectionWithMediaOptions. A codec of the same type
+// already registered with the payload type of codec is replaced.
+func WithMediaCodec(codec RTPCodecParameters, typ RTPCodecType) func(*MediaEngine) error {
+	return func(m *MediaEngine) error {
+		m.mu.Lock()
+		switch typ {
+		case RTPCodecTypeAudio:
+			m.audioCodecs = removeCodecs(m.audioCodecs, func(c RTPCodecParameters) bool {
+				return c.PayloadType == codec.PayloadType
+			})
+		case RTPCodecTypeVideo:
+			m.videoCodecs = removeCodecs(m.videoCodecs, func(c RTPCodecParameters) bool {
+				return c.PayloadType == codec.PayloadType
+			})
+		}
+		m.mu.Unlock()
+
+		return m.RegisterCodec(codec, typ)
+	}
+}
+
+// WithoutMediaCodec removes the codecs with mimeType, and their RTX, from the
+// MediaEngine of a PeerConnection created with
+// NewPeerConnectionWithMediaOptions.
+func WithoutMediaCodec(mimeType string) func(*MediaEngine) error {
+	return func(m *MediaEngine) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		isMimeType := func(c RTPCodecParameters) bool {
+			return strings.EqualFold(c.MimeType, mimeType)
+		}
+		m.audioCodecs = removeDanglingRTX(removeCodecs(m.audioCodecs, isMimeType))
+		m.videoCodecs = removeDanglingRTX(removeCodecs(m.videoCodecs, isMimeType))
+
+		return nil
+	}
+}
+
+// WithMediaHeaderExtension registers a header extension with the MediaEngine
+// of a PeerConnection created with NewPeerConnectionWithMediaOptions, see
+// MediaEngine.RegisterHeaderExtension.
+func WithMediaHeaderExtension(
+	extension RTPHeaderExtensionCapability, typ RTPCodecType, allowedDirections ...RTPTransceiverDirection,
+) func(*MediaEngine) error {
+	return func(m *MediaEngine) error {
+		return m.RegisterHeaderExtension(extension, typ, allowedDirections...)
+	}
+}
+
+// removeCodecs returns codecs without the ones remove returns true for.
+func removeCodecs(codecs []RTPCodecParameters, remove func(RTPCodecParameters) bool) []RTPCodecParameters {
+	filtered := make([]RTPCodecParameters, 0, len(codecs))
+	for _, codec := range codecs {
+		if !remove(codec) {
+			filtered = append(filtered, codec)
+		}
+	}
+
+	return filtered
+}
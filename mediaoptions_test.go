@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPI_NewPeerConnectionWithMediaOptions(t *testing.T) {
+	api := NewAPI()
+
+	offer := func(pc *PeerConnection) string {
+		_, err := pc.AddTransceiverFromKind(RTPCodecTypeVideo)
+		assert.NoError(t, err)
+		desc, err := pc.CreateOffer(nil)
+		assert.NoError(t, err)
+		assert.NoError(t, pc.Close())
+
+		return desc.SDP
+	}
+
+	pc, err := api.NewPeerConnectionWithMediaOptions(Configuration{},
+		WithoutMediaCodec(MimeTypeVP8),
+		WithMediaCodec(RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeH265, ClockRate: 90000},
+			PayloadType:        98,
+		}, RTPCodecTypeVideo),
+		WithMediaHeaderExtension(RTPHeaderExtensionCapability{URI: VideoOrientationURI}, RTPCodecTypeVideo),
+	)
+	assert.NoError(t, err)
+	sdp := offer(pc)
+	assert.NotRegexp(t, regexp.MustCompile(`(?m)^a=rtpmap:\d+ VP8/90000`), sdp)
+	// The RTX of VP8 is removed with it
+	assert.NotRegexp(t, regexp.MustCompile(`(?m)^a=fmtp:\d+ apt=96`), sdp)
+	// VP9 is replaced
+	assert.Regexp(t, regexp.MustCompile(`(?m)^a=rtpmap:98 H265/90000`), sdp)
+	assert.Contains(t, sdp, VideoOrientationURI)
+
+	// The other PeerConnections of the API are unchanged
+	pc, err = api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	sdp = offer(pc)
+	assert.Regexp(t, regexp.MustCompile(`(?m)^a=rtpmap:96 VP8/90000`), sdp)
+	assert.Regexp(t, regexp.MustCompile(`(?m)^a=rtpmap:98 VP9/90000`), sdp)
+	assert.NotContains(t, sdp, VideoOrientationURI)
+
+	_, err = api.NewPeerConnectionWithMediaOptions(Configuration{},
+		WithMediaCodec(RTPCodecParameters{PayloadType: 96}, RTPCodecType(0)))
+	assert.ErrorIs(t, err, ErrUnknownType)
+}
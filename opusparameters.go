@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pion/webrtc/v4/internal/fmtp"
+)
+
+// OpusParameters are the fmtp parameters of Opus, RFC 7587 Section 6.1. They
+// describe how the peer advertising them prefers to receive Opus, and what it
+// sends. The zero value of a field omits its parameter.
+type OpusParameters struct {
+	// Stereo is set if stereo is preferred when receiving (stereo=1).
+	Stereo bool
+	// SpropStereo is set if the sender is likely to send stereo (sprop-stereo=1).
+	SpropStereo bool
+	// UseInbandFEC is set if inband forward error correction is supported
+	// when receiving (useinbandfec=1).
+	UseInbandFEC bool
+	// UseDTX is set if discontinuous transmission is preferred when
+	// receiving (usedtx=1).
+	UseDTX bool
+	// MaxAverageBitrate is the maximum average bitrate in bits per second
+	// preferred when receiving (maxaveragebitrate).
+	MaxAverageBitrate uint32
+	// PTime is the preferred duration of the packets in milliseconds (ptime).
+	PTime uint32
+	// MinPTime is the minimum duration of the packets in milliseconds (minptime).
+	MinPTime uint32
+}
+
+// ParseOpusParameters parses the fmtp line of an Opus codec, unknown and
+// malformed parameters are ignored.
+func ParseOpusParameters(line string) OpusParameters {
+	parsed := fmtp.Parse(MimeTypeOpus, line)
+
+	flag := func(key string) bool {
+		value, ok := parsed.Parameter(key)
+
+		return ok && value == "1"
+	}
+	number := func(key string) uint32 {
+		value, ok := parsed.Parameter(key)
+		if !ok {
+			return 0
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return 0
+		}
+
+		return uint32(n)
+	}
+
+	return OpusParameters{
+		Stereo:            flag("stereo"),
+		SpropStereo:       flag("sprop-stereo"),
+		UseInbandFEC:      flag("useinbandfec"),
+		UseDTX:            flag("usedtx"),
+		MaxAverageBitrate: number("maxaveragebitrate"),
+		PTime:             number("ptime"),
+		MinPTime:          number("minptime"),
+	}
+}
+
+// SDPFmtpLine returns the fmtp line of the parameters, to be used as the
+// SDPFmtpLine of an Opus RTPCodecCapability.
+func (p OpusParameters) SDPFmtpLine() string {
+	parameters := []string{}
+	number := func(key string, value uint32) {
+		if value != 0 {
+			parameters = append(parameters, key+"="+strconv.FormatUint(uint64(value), 10))
+		}
+	}
+	flag := func(key string, value bool) {
+		if value {
+			parameters = append(parameters, key+"=1")
+		}
+	}
+
+	number("minptime", p.MinPTime)
+	number("ptime", p.PTime)
+	flag("useinbandfec", p.UseInbandFEC)
+	flag("usedtx", p.UseDTX)
+	flag("stereo", p.Stereo)
+	flag("sprop-stereo", p.SpropStereo)
+	number("maxaveragebitrate", p.MaxAverageBitrate)
+
+	return strings.Join(parameters, ";")
+}
+
+// SetOpusParameters sets the fmtp line of the registered Opus codecs to
+// params. They are also used in the answers to remote offers, instead of
+// echoing the Opus parameters of the offer, as the parameters of an answer
+// describe how the answerer prefers to receive.
+func (m *MediaEngine) SetOpusParameters(params OpusParameters) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	found := false
+	for i := range m.audioCodecs {
+		if strings.EqualFold(m.audioCodecs[i].MimeType, MimeTypeOpus) {
+			m.audioCodecs[i].SDPFmtpLine = params.SDPFmtpLine()
+			found = true
+		}
+	}
+	if !found {
+		return errMediaEngineOpusNotFound
+	}
+	m.opusParameters = &params
+
+	return nil
+}
+
+// answerOpusParameters replaces the fmtp line of the remote Opus codecs with
+// the parameters set with SetOpusParameters, if any.
+func (m *MediaEngine) answerOpusParameters(codecs []RTPCodecParameters) {
+	if m.opusParameters == nil {
+		return
+	}
+
+	for i := range codecs {
+		if strings.EqualFold(codecs[i].MimeType, MimeTypeOpus) {
+			codecs[i].SDPFmtpLine = m.opusParameters.SDPFmtpLine()
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpusParameters(t *testing.T) {
+	params := OpusParameters{
+		Stereo:            true,
+		SpropStereo:       true,
+		UseInbandFEC:      true,
+		UseDTX:            true,
+		MaxAverageBitrate: 128000,
+		PTime:             20,
+		MinPTime:          10,
+	}
+	line := params.SDPFmtpLine()
+	assert.Equal(t, "minptime=10;ptime=20;useinbandfec=1;usedtx=1;stereo=1;sprop-stereo=1;maxaveragebitrate=128000", line)
+	assert.Equal(t, params, ParseOpusParameters(line))
+
+	assert.Equal(t, "", OpusParameters{}.SDPFmtpLine())
+	assert.Equal(t, OpusParameters{UseInbandFEC: true},
+		ParseOpusParameters("maxplaybackrate=48000; useinbandfec=1; stereo=0"))
+	assert.Equal(t, OpusParameters{}, ParseOpusParameters("ptime=foo"))
+}
+
+func TestMediaEngine_SetOpusParameters(t *testing.T) {
+	mediaEngine := MediaEngine{}
+	assert.ErrorIs(t, mediaEngine.SetOpusParameters(OpusParameters{}), errMediaEngineOpusNotFound)
+
+	assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	params := OpusParameters{Stereo: true, UseInbandFEC: true}
+	assert.NoError(t, mediaEngine.SetOpusParameters(params))
+
+	const offer = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=audio 9 UDP/TLS/RTP/SAVPF 111
+a=rtpmap:111 opus/48000/2
+a=fmtp:111 minptime=10;useinbandfec=1;usedtx=1
+`
+	desc := sdp.SessionDescription{}
+	assert.NoError(t, desc.Unmarshal([]byte(offer)))
+	assert.NoError(t, mediaEngine.updateFromRemoteDescription(desc))
+
+	// The answer carries the local parameters rather than the offered ones
+	opus, _, err := mediaEngine.getCodecByPayload(111)
+	assert.NoError(t, err)
+	assert.Equal(t, params, ParseOpusParameters(opus.SDPFmtpLine))
+	assert.Equal(t, params, *mediaEngine.copy().opusParameters)
+}
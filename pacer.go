@@ -0,0 +1,331 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/cc"
+	"github.com/pion/rtp"
+)
+
+const (
+	// pacerBurst is how long the bitrate the pacer sends at once without waiting.
+	pacerBurst = 20 * time.Millisecond
+	// pacerMinBurstBytes lets a full packet through even at a low bitrate.
+	pacerMinBurstBytes = 1500
+	// pacerEstimatorFactor is how much faster than the target bitrate of the
+	// BandwidthEstimator the pacer sends, so the encoders' bursts drain quickly.
+	pacerEstimatorFactor = 2.5
+	// pacedQueueMaxBytes bounds the bytes a pacedQueue holds, writes fail
+	// once it is full instead of adding more latency.
+	pacedQueueMaxBytes = 1 << 20
+)
+
+// pacer spreads the RTP packets written to the RTPSenders of a PeerConnection
+// with a token bucket. Packets wait in a pacedQueue until the bucket has the
+// room for them, so the frames written at once by WriteSample are sent at the
+// pacing bitrate instead of in a burst.
+type pacer struct {
+	mu        sync.Mutex
+	bitrate   int
+	estimator cc.BandwidthEstimator
+	tokens    float64
+	last      time.Time
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newPacer(bitrate int) *pacer {
+	return &pacer{
+		bitrate: bitrate,
+		closed:  make(chan struct{}),
+	}
+}
+
+// setEstimator makes the pacing bitrate follow the target bitrate of estimator.
+func (p *pacer) setEstimator(estimator cc.BandwidthEstimator) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.estimator = estimator
+}
+
+// setBitrate changes the pacing bitrate, 0 stops pacing.
+func (p *pacer) setBitrate(bitrate int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.bitrate = bitrate
+}
+
+// unlimited returns true if the pacer lets packets through without waiting.
+func (p *pacer) unlimited() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.rate() <= 0
+}
+
+// rate returns the pacing bitrate in bytes per second.
+func (p *pacer) rate() float64 {
+	if p.estimator != nil {
+		if target := p.estimator.GetTargetBitrate(); target > 0 {
+			return float64(target) * pacerEstimatorFactor / 8
+		}
+	}
+
+	return float64(p.bitrate) / 8
+}
+
+// reserve takes size bytes from the bucket, and returns how long to wait
+// before sending them.
+func (p *pacer) reserve(size int, now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rate := p.rate()
+	if rate <= 0 {
+		return 0
+	}
+
+	burst := rate * pacerBurst.Seconds()
+	if burst < pacerMinBurstBytes {
+		burst = pacerMinBurstBytes
+	}
+
+	if p.last.IsZero() {
+		p.tokens = burst
+	} else if elapsed := now.Sub(p.last); elapsed > 0 {
+		p.tokens += rate * elapsed.Seconds()
+	}
+	if p.tokens > burst {
+		p.tokens = burst
+	}
+	p.last = now
+
+	p.tokens -= float64(size)
+	if p.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-p.tokens / rate * float64(time.Second))
+}
+
+// consume takes size bytes from the bucket without waiting, for the packets
+// that are sent ahead of the queued ones.
+func (p *pacer) consume(size int) {
+	if p == nil {
+		return
+	}
+
+	p.reserve(size, time.Now())
+}
+
+// wait blocks until size bytes may be sent, the pacer is closed or cancel is closed.
+func (p *pacer) wait(size int, cancel <-chan struct{}) {
+	delay := p.reserve(size, time.Now())
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-p.closed:
+	case <-cancel:
+	}
+}
+
+func (p *pacer) close() {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+}
+
+// pacedQueue holds the packets written to a RTPSender encoding until its
+// pacers let them through. The queue is drained by its own goroutine, so
+// writes don't wait for the pacers and a track shared with other
+// PeerConnections isn't held up by this one.
+type pacedQueue struct {
+	mu      sync.Mutex
+	packets []pacedPacket
+	bytes   int
+	running bool
+	pacers  []*pacer
+
+	// maxDelay drops the packets that waited longer in the queue, 0 keeps
+	// them until they are sent. See LatencyMode.
+	maxDelay time.Duration
+	write    func(*rtp.Header, []byte, interceptor.Attributes) (int, error)
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+type pacedPacket struct {
+	header     rtp.Header
+	payload    []byte
+	attributes interceptor.Attributes
+	queued     time.Time
+}
+
+func newPacedQueue(
+	write func(*rtp.Header, []byte, interceptor.Attributes) (int, error),
+	maxDelay time.Duration,
+	pacers ...*pacer,
+) *pacedQueue {
+	return &pacedQueue{
+		pacers:   pacers,
+		maxDelay: maxDelay,
+		write:    write,
+		closed:   make(chan struct{}),
+	}
+}
+
+// addPacers makes the packets wait for pacers too, starting with the next one sent.
+func (q *pacedQueue) addPacers(pacers ...*pacer) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pacers = append(q.pacers[:len(q.pacers):len(q.pacers)], pacers...)
+}
+
+// push queues a copy of the packet, the caller may reuse its buffers. While
+// none of the pacers limit the bitrate and the queue is empty the packet is
+// written right away instead.
+func (q *pacedQueue) push(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+	size := header.MarshalSize() + len(payload)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	select {
+	case <-q.closed:
+		return 0, io.ErrClosedPipe
+	default:
+	}
+
+	if !q.running && q.unlimited() {
+		// Packets pushed meanwhile are queued behind this one
+		q.running = true
+		q.mu.Unlock()
+		n, err := q.write(header, payload, attributes)
+		q.mu.Lock()
+		q.running = len(q.packets) != 0
+		if q.running {
+			go q.drain()
+		}
+
+		return n, err
+	}
+
+	if q.bytes+size > pacedQueueMaxBytes {
+		return 0, errPacedQueueFull
+	}
+
+	q.packets = append(q.packets, pacedPacket{
+		header:     header.Clone(),
+		payload:    append([]byte(nil), payload...),
+		attributes: attributes,
+		queued:     time.Now(),
+	})
+	q.bytes += size
+
+	if !q.running {
+		q.running = true
+		go q.drain()
+	}
+
+	return size, nil
+}
+
+func (q *pacedQueue) unlimited() bool {
+	for _, p := range q.pacers {
+		if !p.unlimited() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// drain sends the queued packets as the pacers let them through, it returns
+// once the queue is empty.
+func (q *pacedQueue) drain() {
+	for {
+		q.mu.Lock()
+		if len(q.packets) == 0 {
+			q.running = false
+			q.mu.Unlock()
+
+			return
+		}
+		packet := q.packets[0]
+		q.packets[0] = pacedPacket{}
+		q.packets = q.packets[1:]
+		pacers := q.pacers
+		q.mu.Unlock()
+
+		size := packet.header.MarshalSize() + len(packet.payload)
+		if q.maxDelay == 0 || time.Since(packet.queued) <= q.maxDelay {
+			for _, p := range pacers {
+				p.wait(size, q.closed)
+			}
+
+			select {
+			case <-q.closed:
+			default:
+				_, _ = q.write(&packet.header, packet.payload, packet.attributes)
+			}
+		}
+
+		q.mu.Lock()
+		q.bytes -= size
+		q.mu.Unlock()
+	}
+}
+
+// bufferedAmount returns the bytes of the packets waiting to be sent.
+func (q *pacedQueue) bufferedAmount() uint64 {
+	if q == nil {
+		return 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return uint64(q.bytes) //nolint:gosec // G115, never negative
+}
+
+// close drops the queued packets, writes fail afterwards.
+func (q *pacedQueue) close() {
+	if q == nil {
+		return
+	}
+
+	q.closeOnce.Do(func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		close(q.closed)
+		for _, packet := range q.packets {
+			q.bytes -= packet.header.MarshalSize() + len(packet.payload)
+		}
+		q.packets = nil
+	})
+}
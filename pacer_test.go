@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/gcc"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacer_Reserve(t *testing.T) {
+	// 1,200,000 bits per second is 150 bytes per millisecond, a burst of 3000 bytes
+	p := newPacer(1_200_000)
+	start := time.Unix(0, 0)
+
+	assert.Equal(t, time.Duration(0), p.reserve(1500, start))
+	assert.Equal(t, time.Duration(0), p.reserve(1500, start))
+	// The bucket is empty, the packets of the burst are spread
+	assert.Equal(t, 10*time.Millisecond, p.reserve(1500, start))
+	assert.Equal(t, 20*time.Millisecond, p.reserve(1500, start))
+
+	// The bucket refills while idle, up to the burst
+	assert.Equal(t, time.Duration(0), p.reserve(1500, start.Add(time.Second)))
+	assert.Equal(t, time.Duration(0), p.reserve(1500, start.Add(time.Second)))
+	assert.Greater(t, p.reserve(1500, start.Add(time.Second)), time.Duration(0))
+}
+
+func TestPacer_Estimator(t *testing.T) {
+	estimator, err := gcc.NewSendSideBWE(gcc.SendSideBWEInitialBitrate(480_000))
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, estimator.Close())
+	}()
+
+	// The estimator's 480,000 bits per second are paced at 150 bytes per millisecond
+	p := newPacer(8_000)
+	p.setEstimator(estimator)
+	start := time.Unix(0, 0)
+
+	assert.Equal(t, time.Duration(0), p.reserve(3000, start))
+	assert.Equal(t, 10*time.Millisecond, p.reserve(1500, start))
+}
+
+func TestPacer_Close(t *testing.T) {
+	p := newPacer(8_000)
+	p.wait(1500, nil)
+
+	done := make(chan struct{})
+	go func() {
+		p.wait(1500, nil)
+		close(done)
+	}()
+
+	p.close()
+	<-done
+}
+
+func TestPacedQueue(t *testing.T) {
+	// 12,000 bits per second is 1500 bytes per second, one packet of the burst
+	p := newPacer(12_000)
+	defer p.close()
+
+	written := make(chan uint16, 10)
+	queue := newPacedQueue(func(header *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+		written <- header.SequenceNumber
+
+		return 0, nil
+	}, 0, p)
+
+	header := &rtp.Header{Version: 2, SequenceNumber: 1}
+	payload := make([]byte, 1500-header.MarshalSize())
+
+	// Pushing doesn't wait for the pacer, the buffers may be reused right away
+	_, err := queue.push(header, payload, nil)
+	assert.NoError(t, err)
+	header.SequenceNumber = 2
+	_, err = queue.push(header, payload, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(1), <-written)
+	assert.Equal(t, uint64(1500), queue.bufferedAmount())
+
+	// The packet held back by the pacer is dropped on close
+	queue.close()
+	_, err = queue.push(header, payload, nil)
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.Eventually(t, func() bool { return queue.bufferedAmount() == 0 }, time.Second, time.Millisecond)
+	assert.Empty(t, written)
+
+	// Writes fail once the queue is full
+	full := newPacedQueue(func(*rtp.Header, []byte, interceptor.Attributes) (int, error) { return 0, nil }, 0, p)
+	defer full.close()
+	full.bytes = pacedQueueMaxBytes
+	_, err = full.push(header, payload, nil)
+	assert.ErrorIs(t, err, errPacedQueueFull)
+}
+
+func TestPacedQueue_MaxDelay(t *testing.T) {
+	// 120,000 bits per second sends a packet of 1500 bytes every 100 milliseconds
+	p := newPacer(120_000)
+	defer p.close()
+
+	written := make(chan uint16, 10)
+	queue := newPacedQueue(func(header *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+		written <- header.SequenceNumber
+
+		return 0, nil
+	}, 10*time.Millisecond, p)
+	defer queue.close()
+
+	header := &rtp.Header{Version: 2}
+	payload := make([]byte, 1500-header.MarshalSize())
+	for i := uint16(1); i <= 3; i++ {
+		header.SequenceNumber = i
+		_, err := queue.push(header, payload, nil)
+		assert.NoError(t, err)
+	}
+
+	// The third packet waited behind the second one for longer than the max delay
+	assert.Equal(t, uint16(1), <-written)
+	assert.Equal(t, uint16(2), <-written)
+	assert.Eventually(t, func() bool { return queue.bufferedAmount() == 0 }, time.Second, time.Millisecond)
+	assert.Empty(t, written)
+}
@@ -0,0 +1,306 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/webrtc/v4/internal/mux"
+)
+
+const (
+	// AttributeArrivalTime is the interceptor attribute holding the time.Time
+	// a RTP packet arrived. It is the kernel receive timestamp if the packet
+	// was read from a MetadataPacketConn that supports them, and otherwise,
+	// if enabled with SettingEngine.EnableArrivalTimes, the time the packet
+	// was read from the ICE transport, before decryption and buffering.
+	AttributeArrivalTime = "webrtc.arrivalTime"
+
+	// AttributeECN is the interceptor attribute holding the ECN codepoint a
+	// RTP packet arrived with. It is only set if the packet was read from a
+	// MetadataPacketConn that supports reading the ECN bits.
+	AttributeECN = "webrtc.ecn"
+)
+
+// ECN is the Explicit Congestion Notification codepoint of the IP header
+// of a packet, as defined in RFC 3168.
+type ECN uint8
+
+const (
+	// ECNNotECT indicates the packet was sent by a transport that isn't ECN capable.
+	ECNNotECT ECN = 0b00
+
+	// ECNECT1 indicates an ECN capable transport, L4S uses it to mark its traffic.
+	ECNECT1 ECN = 0b01
+
+	// ECNECT0 indicates an ECN capable transport.
+	ECNECT0 ECN = 0b10
+
+	// ECNCE indicates a router on the path experienced congestion.
+	ECNCE ECN = 0b11
+)
+
+func (e ECN) String() string {
+	switch e {
+	case ECNNotECT:
+		return "not-ect"
+	case ECNECT1:
+		return "ect1"
+	case ECNECT0:
+		return "ect0"
+	case ECNCE:
+		return "ce"
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// packetMetadataTableSize is how many packets a packetMetadataTable keeps
+// the metadata of until it is taken.
+const packetMetadataTableSize = 1024
+
+type packetMetadata struct {
+	arrivalTime time.Time
+	ecn         ECN
+	hasECN      bool
+}
+
+type packetMetadataEntry struct {
+	packetMetadata
+	slot int
+}
+
+// packetMetadataTable holds the metadata of received SRTP packets, until the
+// decrypted packet is read. The SSRC and sequence number of the RTP header
+// aren't encrypted and identify the packet on both sides.
+type packetMetadataTable struct {
+	mu      sync.Mutex
+	entries map[uint64]packetMetadataEntry
+	slots   [packetMetadataTableSize]uint64
+	next    int
+
+	// lastArrivals is when the last packet of every SSRC arrived
+	lastArrivals map[SSRC]time.Time
+}
+
+func newPacketMetadataTable() *packetMetadataTable {
+	return &packetMetadataTable{
+		entries:      map[uint64]packetMetadataEntry{},
+		lastArrivals: map[SSRC]time.Time{},
+	}
+}
+
+func packetMetadataKey(packet []byte) (uint64, bool) {
+	if len(packet) < 12 {
+		return 0, false
+	}
+
+	return uint64(binary.BigEndian.Uint32(packet[8:12]))<<16 | uint64(binary.BigEndian.Uint16(packet[2:4])), true
+}
+
+// add stores metadata for the RTP packet, the oldest entry is dropped once
+// the table is full.
+func (t *packetMetadataTable) add(packet []byte, metadata packetMetadata) {
+	key, ok := packetMetadataKey(packet)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if evicted, ok := t.entries[t.slots[t.next]]; ok && evicted.slot == t.next {
+		delete(t.entries, t.slots[t.next])
+	}
+	t.slots[t.next] = key
+	t.entries[key] = packetMetadataEntry{metadata, t.next}
+	t.next = (t.next + 1) % packetMetadataTableSize
+	t.lastArrivals[SSRC(key>>16)] = metadata.arrivalTime //nolint:gosec // G115
+}
+
+// lastArrival returns when the last packet of ssrc arrived.
+func (t *packetMetadataTable) lastArrival(ssrc SSRC) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	arrival, ok := t.lastArrivals[ssrc]
+
+	return arrival, ok
+}
+
+// forget drops the last arrival of ssrc, once its track ended.
+func (t *packetMetadataTable) forget(ssrc SSRC) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.lastArrivals, ssrc)
+}
+
+// take returns and removes the metadata of the RTP packet.
+func (t *packetMetadataTable) take(packet []byte) (packetMetadata, bool) {
+	key, ok := packetMetadataKey(packet)
+	if !ok {
+		return packetMetadata{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if ok {
+		delete(t.entries, key)
+	}
+
+	return entry.packetMetadata, ok
+}
+
+// arrivalRecordingConn records when every SRTP packet is read from the ICE
+// transport, it is only installed if SettingEngine.recordArrivals.
+type arrivalRecordingConn struct {
+	net.Conn
+	arrivals *packetMetadataTable
+}
+
+func (c *arrivalRecordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err == nil {
+		c.arrivals.add(b[:n], packetMetadata{arrivalTime: time.Now()})
+	}
+
+	return n, err
+}
+
+// setPacketMetadataAttributes sets AttributeArrivalTime and AttributeECN
+// on the attributes of a decrypted RTP packet.
+func setPacketMetadataAttributes(
+	packet []byte,
+	attributes interceptor.Attributes,
+	arrivals *packetMetadataTable,
+	metadataConn *MetadataPacketConn,
+) interceptor.Attributes {
+	metadata, ok := arrivals.take(packet)
+	if metadataConn != nil {
+		if socketMetadata, socketOK := metadataConn.metadata.take(packet); socketOK {
+			metadata, ok = socketMetadata, true
+		}
+	}
+	if !ok {
+		return attributes
+	}
+
+	if attributes == nil {
+		attributes = interceptor.Attributes{}
+	}
+	attributes.Set(AttributeArrivalTime, metadata.arrivalTime)
+	if metadata.hasECN {
+		attributes.Set(AttributeECN, metadata.ecn)
+	}
+
+	return attributes
+}
+
+// MetadataPacketConn is a net.PacketConn that records the kernel receive
+// timestamp and ECN codepoint of the SRTP packets it reads, where the
+// platform supports them. Wrap the socket of a UDPMux with it and pass it to
+// SettingEngine.SetMetadataPacketConn, the metadata is then attached to the
+// RTP packets as AttributeArrivalTime and AttributeECN. On Linux it also
+// marks the packets of PeerConnections with FeatureFlagL4S with ECNECT1.
+type MetadataPacketConn struct {
+	*net.UDPConn
+	metadata *packetMetadataTable
+
+	readMu sync.Mutex
+	oob    []byte
+
+	ect1Mu    sync.RWMutex
+	ect1SSRCs map[SSRC]int
+}
+
+// NewMetadataPacketConn enables receive timestamps and ECN reporting on conn.
+func NewMetadataPacketConn(conn *net.UDPConn) (*MetadataPacketConn, error) {
+	metadataConn := &MetadataPacketConn{
+		UDPConn:   conn,
+		metadata:  newPacketMetadataTable(),
+		oob:       make([]byte, 128),
+		ect1SSRCs: map[SSRC]int{},
+	}
+	if err := enablePacketMetadata(conn); err != nil {
+		return nil, err
+	}
+
+	return metadataConn, nil
+}
+
+// ReadFrom reads a packet from the connection, recording its metadata if it
+// is a SRTP packet.
+func (c *MetadataPacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	n, oobn, _, addr, err := c.UDPConn.ReadMsgUDP(b, c.oob)
+	if err != nil {
+		return n, addr, err
+	}
+
+	if mux.MatchSRTP(b[:n]) {
+		metadata := parsePacketMetadata(c.oob[:oobn])
+		if metadata.arrivalTime.IsZero() {
+			metadata.arrivalTime = time.Now()
+		}
+		c.metadata.add(b[:n], metadata)
+	}
+
+	return n, addr, nil
+}
+
+// WriteTo writes a packet to addr, marked with ECNECT1 if it is a SRTP
+// packet of a SSRC sent with L4S.
+func (c *MetadataPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok || !mux.MatchSRTP(b) || len(b) < 12 {
+		return c.UDPConn.WriteTo(b, addr)
+	}
+
+	c.ect1Mu.RLock()
+	_, marked := c.ect1SSRCs[SSRC(binary.BigEndian.Uint32(b[8:12]))]
+	c.ect1Mu.RUnlock()
+	if !marked {
+		return c.UDPConn.WriteTo(b, addr)
+	}
+
+	return writeWithECN(c.UDPConn, b, udpAddr, ECNECT1)
+}
+
+// markECT1 starts marking the packets of the SSRCs with ECNECT1.
+func (c *MetadataPacketConn) markECT1(ssrcs ...SSRC) {
+	c.ect1Mu.Lock()
+	defer c.ect1Mu.Unlock()
+
+	for _, ssrc := range ssrcs {
+		if ssrc != 0 {
+			c.ect1SSRCs[ssrc]++
+		}
+	}
+}
+
+// unmarkECT1 reverts markECT1.
+func (c *MetadataPacketConn) unmarkECT1(ssrcs ...SSRC) {
+	c.ect1Mu.Lock()
+	defer c.ect1Mu.Unlock()
+
+	for _, ssrc := range ssrcs {
+		if c.ect1SSRCs[ssrc] <= 1 {
+			delete(c.ect1SSRCs, ssrc)
+		} else {
+			c.ect1SSRCs[ssrc]--
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package webrtc
+
+import (
+	"net"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// enablePacketMetadata asks the kernel for the receive timestamp and the
+// traffic class of every packet. An option the socket doesn't support, like
+// IPV6_RECVTCLASS on an IPv4 socket, is ignored.
+func enablePacketMetadata(conn *net.UDPConn) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	return rawConn.Control(func(fd uintptr) {
+		_ = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_TIMESTAMPNS, 1)
+		_ = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_RECVTOS, 1)
+		_ = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_RECVTCLASS, 1)
+	})
+}
+
+func parsePacketMetadata(oob []byte) packetMetadata {
+	var metadata packetMetadata
+
+	messages, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return metadata
+	}
+
+	for _, message := range messages {
+		switch {
+		case message.Header.Level == syscall.SOL_SOCKET && message.Header.Type == syscall.SCM_TIMESTAMPNS:
+			if len(message.Data) >= int(unsafe.Sizeof(syscall.Timespec{})) {
+				timestamp := *(*syscall.Timespec)(unsafe.Pointer(&message.Data[0])) //nolint:gosec // G103
+				metadata.arrivalTime = time.Unix(timestamp.Unix())
+			}
+		case message.Header.Level == syscall.IPPROTO_IP && message.Header.Type == syscall.IP_TOS:
+			if len(message.Data) >= 1 {
+				metadata.ecn, metadata.hasECN = ECN(message.Data[0]&0b11), true
+			}
+		case message.Header.Level == syscall.IPPROTO_IPV6 && message.Header.Type == syscall.IPV6_TCLASS:
+			if len(message.Data) >= 4 {
+				trafficClass := *(*int32)(unsafe.Pointer(&message.Data[0]))  //nolint:gosec // G103
+				metadata.ecn, metadata.hasECN = ECN(trafficClass&0b11), true //nolint:gosec // G115
+			}
+		}
+	}
+
+	return metadata
+}
+
+// writeWithECN sends b with the ECN bits of the traffic class set to ecn.
+func writeWithECN(conn *net.UDPConn, b []byte, addr *net.UDPAddr, ecn ECN) (int, error) {
+	level, typ := syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS
+	if localAddr, ok := conn.LocalAddr().(*net.UDPAddr); ok && localAddr.IP.To4() != nil {
+		level, typ = syscall.IPPROTO_IP, syscall.IP_TOS
+	}
+
+	oob := make([]byte, syscall.CmsgSpace(4))
+	header := (*syscall.Cmsghdr)(unsafe.Pointer(&oob[0])) //nolint:gosec // G103
+	header.Level, header.Type = int32(level), int32(typ)
+	header.SetLen(syscall.CmsgLen(4))
+	*(*int32)(unsafe.Pointer(&oob[syscall.CmsgLen(0)])) = int32(ecn) //nolint:gosec // G103
+
+	n, _, err := conn.WriteMsgUDP(b, oob, addr)
+
+	return n, err
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package webrtc
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataPacketConn_ECN(t *testing.T) {
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(t, err)
+
+	conn, err := NewMetadataPacketConn(listener)
+	assert.NoError(t, err)
+
+	sender, err := net.DialUDP("udp4", nil, listener.LocalAddr().(*net.UDPAddr))
+	assert.NoError(t, err)
+
+	rawConn, err := sender.SyscallConn()
+	assert.NoError(t, err)
+	assert.NoError(t, rawConn.Control(func(fd uintptr) {
+		assert.NoError(t, syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TOS, int(ECNECT1)))
+	}))
+
+	_, err = sender.Write(rtpPacketForMetadata(1234, 1))
+	assert.NoError(t, err)
+
+	_, _, err = conn.ReadFrom(make([]byte, 1500))
+	assert.NoError(t, err)
+
+	metadata, ok := conn.metadata.take(rtpPacketForMetadata(1234, 1))
+	assert.True(t, ok)
+	assert.True(t, metadata.hasECN)
+	assert.Equal(t, ECNECT1, metadata.ecn)
+
+	assert.NoError(t, sender.Close())
+	assert.NoError(t, conn.Close())
+}
+
+func TestMetadataPacketConn_MarkECT1(t *testing.T) {
+	newConn := func() *MetadataPacketConn {
+		listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		assert.NoError(t, err)
+
+		conn, err := NewMetadataPacketConn(listener)
+		assert.NoError(t, err)
+
+		return conn
+	}
+	sender, receiver := newConn(), newConn()
+
+	receivedECN := func(packet []byte) ECN {
+		_, err := sender.WriteTo(packet, receiver.LocalAddr())
+		assert.NoError(t, err)
+
+		_, _, err = receiver.ReadFrom(make([]byte, 1500))
+		assert.NoError(t, err)
+
+		metadata, ok := receiver.metadata.take(packet)
+		assert.True(t, ok)
+
+		return metadata.ecn
+	}
+
+	sender.markECT1(1234)
+	assert.Equal(t, ECNECT1, receivedECN(rtpPacketForMetadata(1234, 1)))
+	assert.Equal(t, ECNNotECT, receivedECN(rtpPacketForMetadata(5678, 1)))
+
+	sender.unmarkECT1(1234)
+	assert.Equal(t, ECNNotECT, receivedECN(rtpPacketForMetadata(1234, 2)))
+
+	assert.NoError(t, sender.Close())
+	assert.NoError(t, receiver.Close())
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !linux && !js
+// +build !linux,!js
+
+package webrtc
+
+import "net"
+
+// enablePacketMetadata is a no-op, receive timestamps and ECN are only
+// supported on Linux.
+func enablePacketMetadata(*net.UDPConn) error {
+	return nil
+}
+
+func parsePacketMetadata([]byte) packetMetadata {
+	return packetMetadata{}
+}
+
+// writeWithECN sends b without marking it, ECN is only supported on Linux.
+func writeWithECN(conn *net.UDPConn, b []byte, addr *net.UDPAddr, _ ECN) (int, error) {
+	return conn.WriteToUDP(b, addr)
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/stretchr/testify/assert"
+)
+
+func rtpPacketForMetadata(ssrc uint32, sequenceNumber uint16) []byte {
+	packet := make([]byte, 20)
+	packet[0] = 0x80
+	packet[1] = 96
+	binary.BigEndian.PutUint16(packet[2:4], sequenceNumber)
+	binary.BigEndian.PutUint32(packet[8:12], ssrc)
+
+	return packet
+}
+
+func TestPacketMetadataTable(t *testing.T) {
+	table := newPacketMetadataTable()
+	arrival := time.Now()
+
+	table.add(rtpPacketForMetadata(1, 1), packetMetadata{arrivalTime: arrival, ecn: ECNCE, hasECN: true})
+	table.add([]byte{0x80}, packetMetadata{arrivalTime: arrival})
+
+	_, ok := table.take(rtpPacketForMetadata(2, 1))
+	assert.False(t, ok, "different SSRC")
+
+	metadata, ok := table.take(rtpPacketForMetadata(1, 1))
+	assert.True(t, ok)
+	assert.Equal(t, arrival, metadata.arrivalTime)
+	assert.Equal(t, ECNCE, metadata.ecn)
+
+	_, ok = table.take(rtpPacketForMetadata(1, 1))
+	assert.False(t, ok, "metadata is only taken once")
+
+	// The oldest packets are dropped once the table is full
+	for i := 0; i <= packetMetadataTableSize; i++ {
+		table.add(rtpPacketForMetadata(1, uint16(i)), packetMetadata{arrivalTime: arrival})
+	}
+	_, ok = table.take(rtpPacketForMetadata(1, 0))
+	assert.False(t, ok)
+	_, ok = table.take(rtpPacketForMetadata(1, packetMetadataTableSize))
+	assert.True(t, ok)
+	assert.LessOrEqual(t, len(table.entries), packetMetadataTableSize)
+}
+
+func TestPacketMetadataTable_LastArrival(t *testing.T) {
+	table := newPacketMetadataTable()
+	arrival := time.Now()
+
+	_, ok := table.lastArrival(1)
+	assert.False(t, ok)
+
+	table.add(rtpPacketForMetadata(1, 1), packetMetadata{arrivalTime: arrival})
+	table.add(rtpPacketForMetadata(1, 2), packetMetadata{arrivalTime: arrival.Add(time.Second)})
+	table.add(rtpPacketForMetadata(2, 1), packetMetadata{arrivalTime: arrival})
+
+	// The arrival outlives the metadata of the packet
+	_, ok = table.take(rtpPacketForMetadata(1, 2))
+	assert.True(t, ok)
+	lastArrival, ok := table.lastArrival(1)
+	assert.True(t, ok)
+	assert.Equal(t, arrival.Add(time.Second), lastArrival)
+
+	table.forget(1)
+	_, ok = table.lastArrival(1)
+	assert.False(t, ok)
+	_, ok = table.lastArrival(2)
+	assert.True(t, ok)
+}
+
+func TestSetPacketMetadataAttributes(t *testing.T) {
+	arrivals := newPacketMetadataTable()
+	packet := rtpPacketForMetadata(1234, 5)
+
+	assert.Nil(t, setPacketMetadataAttributes(packet, nil, arrivals, nil))
+
+	arrival := time.Now()
+	arrivals.add(packet, packetMetadata{arrivalTime: arrival})
+	attributes := setPacketMetadataAttributes(packet, nil, arrivals, nil)
+	assert.Equal(t, arrival, attributes.Get(AttributeArrivalTime))
+	assert.Nil(t, attributes.Get(AttributeECN))
+
+	// The metadata recorded by the socket takes precedence
+	metadataConn := &MetadataPacketConn{metadata: newPacketMetadataTable()}
+	kernelArrival := arrival.Add(-time.Millisecond)
+	arrivals.add(packet, packetMetadata{arrivalTime: arrival})
+	metadataConn.metadata.add(packet, packetMetadata{arrivalTime: kernelArrival, ecn: ECNECT1, hasECN: true})
+	attributes = setPacketMetadataAttributes(packet, interceptor.Attributes{}, arrivals, metadataConn)
+	assert.Equal(t, kernelArrival, attributes.Get(AttributeArrivalTime))
+	assert.Equal(t, ECNECT1, attributes.Get(AttributeECN))
+	assert.Empty(t, arrivals.entries)
+}
+
+func TestMetadataPacketConn(t *testing.T) {
+	listener, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	assert.NoError(t, err)
+
+	conn, err := NewMetadataPacketConn(listener)
+	assert.NoError(t, err)
+
+	sender, err := net.DialUDP("udp4", nil, listener.LocalAddr().(*net.UDPAddr))
+	assert.NoError(t, err)
+
+	before := time.Now()
+	for _, packet := range [][]byte{{0x16, 0xfe, 0xfd}, rtpPacketForMetadata(1234, 1)} {
+		_, err = sender.Write(packet)
+		assert.NoError(t, err)
+	}
+
+	buffer := make([]byte, 1500)
+	for i := 0; i < 2; i++ {
+		_, _, err = conn.ReadFrom(buffer)
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, conn.metadata.entries, 1, "only SRTP packets are recorded")
+	metadata, ok := conn.metadata.take(rtpPacketForMetadata(1234, 1))
+	assert.True(t, ok)
+	assert.WithinDuration(t, before, metadata.arrivalTime, time.Second)
+
+	assert.NoError(t, sender.Close())
+	assert.NoError(t, conn.Close())
+}
+
+func TestECN_String(t *testing.T) {
+	testCases := []struct {
+		ecn            ECN
+		expectedString string
+	}{
+		{ECNNotECT, "not-ect"},
+		{ECNECT1, "ect1"},
+		{ECNECT0, "ect0"},
+		{ECNCE, "ce"},
+		{ECN(4), ErrUnknownType.Error()},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t,
+			testCase.expectedString,
+			testCase.ecn.String(),
+			"testCase: %d %v", i, testCase,
+		)
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PinPayloadType pins the payload type of the codec registered with
+// mimeType and payloadType, so the RTP of the codec always carries it and
+// can be forwarded to external tools without rewriting:
+//
+//   - RegisterCodec fails with ErrPayloadTypeConflict if another codec is
+//     registered with payloadType.
+//   - SetRemoteDescription fails with ErrPayloadTypeConflict if the remote
+//     uses payloadType for another codec, or offers the codec only with other
+//     payload types. The payload types of the codec besides payloadType are
+//     not negotiated.
+func (m *MediaEngine) PinPayloadType(mimeType string, payloadType PayloadType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	registered := false
+	for _, codec := range append(append([]RTPCodecParameters{}, m.audioCodecs...), m.videoCodecs...) {
+		if codec.PayloadType != payloadType {
+			continue
+		}
+		if !strings.EqualFold(codec.MimeType, mimeType) {
+			return fmt.Errorf("%w: %d is registered for %s", ErrPayloadTypeConflict, payloadType, codec.MimeType)
+		}
+		registered = true
+	}
+	if !registered {
+		return fmt.Errorf("%w: %s with payload type %d", errMediaEngineCodecNotRegistered, mimeType, payloadType)
+	}
+
+	if m.pinnedPayloadTypes == nil {
+		m.pinnedPayloadTypes = map[PayloadType]string{}
+	}
+	m.pinnedPayloadTypes[payloadType] = mimeType
+
+	return nil
+}
+
+// checkPinnedPayloadType returns ErrPayloadTypeConflict if codec uses a
+// pinned payload type of another codec.
+func (m *MediaEngine) checkPinnedPayloadType(codec RTPCodecParameters) error {
+	if mimeType, ok := m.pinnedPayloadTypes[codec.PayloadType]; ok && !strings.EqualFold(mimeType, codec.MimeType) {
+		return fmt.Errorf("%w: %d is pinned to %s, not %s",
+			ErrPayloadTypeConflict, codec.PayloadType, mimeType, codec.MimeType)
+	}
+
+	return nil
+}
+
+// isPinnedElsewhere returns true if the codec has a pinned payload type that
+// isn't the one of codec.
+func (m *MediaEngine) isPinnedElsewhere(codec RTPCodecParameters) bool {
+	for payloadType, mimeType := range m.pinnedPayloadTypes {
+		if strings.EqualFold(mimeType, codec.MimeType) && payloadType != codec.PayloadType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkPinnedCodecsNegotiated returns ErrPayloadTypeConflict if the remote
+// codecs of a media section include a pinned codec, but it wasn't negotiated
+// with its pinned payload type.
+func (m *MediaEngine) checkPinnedCodecsNegotiated(remote []RTPCodecParameters, matchTypes []CodecMatchType) error {
+	for payloadType, mimeType := range m.pinnedPayloadTypes {
+		offered, negotiated := false, false
+		for i, codec := range remote {
+			if !strings.EqualFold(codec.MimeType, mimeType) {
+				continue
+			}
+			offered = true
+			if codec.PayloadType == payloadType && matchTypes[i] != CodecMatchNone {
+				negotiated = true
+			}
+		}
+
+		if offered && !negotiated {
+			return fmt.Errorf("%w: %s is pinned to %d, the remote uses other payload types",
+				ErrPayloadTypeConflict, mimeType, payloadType)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaEngine_PinPayloadType(t *testing.T) {
+	mustParse := func(raw string) sdp.SessionDescription {
+		s := sdp.SessionDescription{}
+		assert.NoError(t, s.Unmarshal([]byte(raw)))
+
+		return s
+	}
+	newMediaEngine := func() *MediaEngine {
+		mediaEngine := &MediaEngine{}
+		assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+		assert.NoError(t, mediaEngine.PinPayloadType(MimeTypeVP8, 96))
+		assert.NoError(t, mediaEngine.PinPayloadType(MimeTypeOpus, 111))
+
+		return mediaEngine
+	}
+
+	t.Run("Register", func(t *testing.T) {
+		mediaEngine := newMediaEngine()
+		assert.ErrorIs(t, mediaEngine.PinPayloadType(MimeTypeVP9, 96), ErrPayloadTypeConflict)
+		assert.ErrorIs(t, mediaEngine.PinPayloadType(MimeTypeVP9, 120), errMediaEngineCodecNotRegistered)
+		assert.ErrorIs(t, mediaEngine.RegisterCodec(RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeH265, ClockRate: 90000},
+			PayloadType:        96,
+		}, RTPCodecTypeVideo), ErrPayloadTypeConflict)
+		assert.NoError(t, mediaEngine.RegisterCodec(RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeH265, ClockRate: 90000},
+			PayloadType:        116,
+		}, RTPCodecTypeVideo))
+	})
+
+	t.Run("Other Payload Types Not Negotiated", func(t *testing.T) {
+		const offer = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=video 9 UDP/TLS/RTP/SAVPF 96 120 121
+a=rtpmap:96 VP8/90000
+a=rtpmap:120 VP8/90000
+a=rtpmap:121 rtx/90000
+a=fmtp:121 apt=120
+`
+		mediaEngine := newMediaEngine()
+		assert.NoError(t, mediaEngine.updateFromRemoteDescription(mustParse(offer)))
+
+		_, _, err := mediaEngine.getCodecByPayload(96)
+		assert.NoError(t, err)
+		_, _, err = mediaEngine.getCodecByPayload(120)
+		assert.Error(t, err)
+		_, _, err = mediaEngine.getCodecByPayload(121)
+		assert.Error(t, err)
+	})
+
+	t.Run("Remote Uses Other Payload Type", func(t *testing.T) {
+		const offer = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=audio 9 UDP/TLS/RTP/SAVPF 109
+a=rtpmap:109 opus/48000/2
+`
+		mediaEngine := newMediaEngine()
+		assert.ErrorIs(t, mediaEngine.updateFromRemoteDescription(mustParse(offer)), ErrPayloadTypeConflict)
+	})
+
+	t.Run("Remote Uses Pinned Payload Type", func(t *testing.T) {
+		const offer = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=video 9 UDP/TLS/RTP/SAVPF 96
+a=rtpmap:96 VP9/90000
+`
+		mediaEngine := newMediaEngine()
+		assert.ErrorIs(t, mediaEngine.updateFromRemoteDescription(mustParse(offer)), ErrPayloadTypeConflict)
+	})
+}
@@ -21,10 +21,12 @@ import (
 
 	"github.com/pion/ice/v4"
 	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/cc"
+	"github.com/pion/interceptor/pkg/rfc8888"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/sdp/v3"
-	"github.com/pion/srtp/v3"
+	"github.com/pion/webrtc/v4/internal/fmtp"
 	"github.com/pion/webrtc/v4/internal/util"
 	"github.com/pion/webrtc/v4/pkg/rtcerr"
 )
@@ -44,6 +46,7 @@ type PeerConnection struct {
 	ops *operations
 
 	configuration Configuration
+	featureFlags  atomic.Value // FeatureFlags
 
 	currentLocalDescription  *SessionDescription
 	pendingLocalDescription  *SessionDescription
@@ -55,6 +58,9 @@ type PeerConnection struct {
 
 	idpLoginURL *string
 
+	identityAssertion *IdentityAssertion
+	peerIdentity      string
+
 	isClosed                                *atomicBool
 	isGracefullyClosingOrClosed             bool
 	isCloseDone                             chan struct{}
@@ -62,9 +68,27 @@ type PeerConnection struct {
 	isNegotiationNeeded                     *atomicBool
 	updateNegotiationNeededFlagOnEmptyChain *atomicBool
 
+	// iceRestartPending is set by RestartICE until an offer restarting ICE is created
+	iceRestartPending atomicBool
+	iceAutoRestart    iceAutoRestart
+
+	rtpRouter rtpRouter
+
 	lastOffer  string
 	lastAnswer string
 
+	remoteSignalingLimiter remoteSignalingLimiter
+
+	// the remote description and transceiver state receivers were last
+	// configured from, used to only apply the media sections that changed
+	// on renegotiation
+	appliedRemoteDescription *sdp.SessionDescription
+	appliedTransceivers      map[string]appliedTransceiverState
+
+	// the transceivers created by the pending remote offer, removed if it
+	// is rolled back
+	remoteOfferTransceivers []*RTPTransceiver
+
 	// a value containing the last known greater mid value
 	// we internally generate mids as numbers. Needed since JSEP
 	// requires that when reusing a media section a new unique mid
@@ -74,12 +98,24 @@ type PeerConnection struct {
 	rtpTransceivers        []*RTPTransceiver
 	nonMediaBandwidthProbe atomic.Value // RTPReceiver
 
+	// holdDirections are the directions of the transceivers changed by Hold
+	holdDirections map[*RTPTransceiver]RTPTransceiverDirection
+
 	onSignalingStateChangeHandler     func(SignalingState)
 	onICEConnectionStateChangeHandler atomic.Value // func(ICEConnectionState)
 	onConnectionStateChangeHandler    atomic.Value // func(PeerConnectionState)
 	onTrackHandler                    func(*TrackRemote, *RTPReceiver)
+	onTrackEventHandler               func(TrackEvent)
+	onTrackAnnouncedHandler           func(*RTPTransceiver)
 	onDataChannelHandler              func(*DataChannel)
 	onNegotiationNeededHandler        atomic.Value // func()
+	onClosedHandler                   func()
+
+	// handlers tracks the goroutines running internal processing and user
+	// callbacks, so OnClosed can fire once all of them have returned
+	handlers        sync.WaitGroup
+	handlersMu      sync.Mutex
+	handlersStopped bool
 
 	iceGatherer   *ICEGatherer
 	iceTransport  *ICETransport
@@ -91,6 +127,8 @@ type PeerConnection struct {
 	log logging.LeveledLogger
 
 	interceptorRTCPWriter interceptor.RTCPWriter
+	interceptorNames      []string
+	bandwidthEstimator    cc.BandwidthEstimator
 }
 
 // NewPeerConnection creates a PeerConnection with the default codecs and interceptors.
@@ -110,6 +148,24 @@ func NewPeerConnection(configuration Configuration) (*PeerConnection, error) {
 // set the set of codecs and interceptors explicitly by using
 // [WithMediaEngine] and [WithInterceptorRegistry] when calling [NewAPI].
 func (api *API) NewPeerConnection(configuration Configuration) (*PeerConnection, error) {
+	return api.newPeerConnection(configuration, nil)
+}
+
+// NewPeerConnectionWithMediaOptions creates a new PeerConnection like
+// NewPeerConnection, with the mediaOptions applied to its copy of the
+// MediaEngine of the API. It adds or overrides codecs and header extensions
+// for one PeerConnection, so an API can serve peers with different needs.
+// The MediaEngine is copied even if SettingEngine.DisableMediaEngineCopy is
+// set.
+func (api *API) NewPeerConnectionWithMediaOptions(
+	configuration Configuration, mediaOptions ...func(*MediaEngine) error,
+) (*PeerConnection, error) {
+	return api.newPeerConnection(configuration, mediaOptions)
+}
+
+func (api *API) newPeerConnection(
+	configuration Configuration, mediaOptions []func(*MediaEngine) error,
+) (*PeerConnection, error) {
 	// https://w3c.github.io/webrtc-pc/#constructor (Step #2)
 	// Some variables defined explicitly despite their implicit zero values to
 	// allow better readability to understand what is happening.
@@ -142,26 +198,55 @@ func (api *API) NewPeerConnection(configuration Configuration) (*PeerConnection,
 	pc.iceConnectionState.Store(ICEConnectionStateNew)
 	pc.connectionState.Store(PeerConnectionStateNew)
 
-	i, err := api.interceptorRegistry.Build("")
+	latencyModeFactories, err := configuration.LatencyMode.interceptorFactories()
+	if err != nil {
+		return nil, err
+	}
+
+	i, build, err := buildInterceptors(
+		api.interceptorRegistry, configuration.DisabledInterceptors, latencyModeFactories,
+	)
 	if err != nil {
 		return nil, err
 	}
+	pc.interceptorNames = build.names
+	pc.bandwidthEstimator = build.bandwidthEstimator
 
 	pc.api = &API{
 		settingEngine: api.settingEngine,
 		interceptor:   i,
+		probeRoutines: api.probeRoutines,
+		latencyMode:   configuration.LatencyMode,
+	}
+	if pc.api.probeRoutines == nil {
+		pc.api.probeRoutines = newProbeRoutines(api.settingEngine.incomingSSRCRoutines.count)
 	}
 
-	if api.settingEngine.disableMediaEngineCopy {
+	if api.settingEngine.disableMediaEngineCopy && len(mediaOptions) == 0 {
 		pc.api.mediaEngine = api.mediaEngine
 	} else {
 		pc.api.mediaEngine = api.mediaEngine.copy()
 	}
+	for _, option := range mediaOptions {
+		if err = option(pc.api.mediaEngine); err != nil {
+			return nil, err
+		}
+	}
 
 	if err = pc.initConfiguration(configuration); err != nil {
 		return nil, err
 	}
 
+	if pc.FeatureFlags().IsEnabled(FeatureFlagL4S) {
+		if pc.api.interceptor, err = pc.configureL4S(pc.api.interceptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if pc.api.interceptor, err = pc.configureLatencyMode(pc.api.interceptor, latencyModeFactories); err != nil {
+		return nil, err
+	}
+
 	pc.iceGatherer, err = pc.createICEGatherer()
 	if err != nil {
 		return nil, err
@@ -241,8 +326,17 @@ func (pc *PeerConnection) initConfiguration(configuration Configuration) error {
 
 	pc.configuration.ICETransportPolicy = configuration.ICETransportPolicy
 	pc.configuration.SDPSemantics = configuration.SDPSemantics
+	pc.configuration.FeatureFlags = configuration.FeatureFlags
+	pc.featureFlags.Store(pc.api.settingEngine.featureFlags.merge(configuration.FeatureFlags))
+	pc.configuration.ICEMulticastDNSMode = configuration.ICEMulticastDNSMode
+	pc.configuration.ICEMulticastDNSInterfaces = configuration.ICEMulticastDNSInterfaces
+	pc.configuration.LatencyMode = configuration.LatencyMode
+	pc.configuration.DisabledInterceptors = configuration.DisabledInterceptors
 
 	sanitizedICEServers := configuration.getICEServers()
+	if len(sanitizedICEServers) == 0 && pc.api.settingEngine.iceServerProvider != nil {
+		sanitizedICEServers = pc.api.settingEngine.iceServerProvider.ICEServers()
+	}
 	if len(sanitizedICEServers) > 0 {
 		for _, server := range sanitizedICEServers {
 			if err := server.validate(); err != nil {
@@ -270,7 +364,7 @@ func (pc *PeerConnection) onSignalingStateChange(newState SignalingState) {
 
 	pc.log.Infof("signaling state changed to %s", newState)
 	if handler != nil {
-		go handler(newState)
+		pc.goHandler(func() { handler(newState) })
 	}
 }
 
@@ -356,7 +450,7 @@ func (pc *PeerConnection) checkNegotiationNeeded() bool { //nolint:gocognit,cycl
 	localDesc := pc.currentLocalDescription
 	remoteDesc := pc.currentRemoteDescription
 
-	if localDesc == nil {
+	if localDesc == nil || pc.iceRestartPending.get() {
 		return true
 	}
 
@@ -466,27 +560,145 @@ func (pc *PeerConnection) OnTrack(f func(*TrackRemote, *RTPReceiver)) {
 	pc.onTrackHandler = f
 }
 
+// TrackEvent describes a remote track that arrived, like the RTCTrackEvent
+// of the browser API.
+type TrackEvent struct {
+	Track       *TrackRemote
+	Receiver    *RTPReceiver
+	Transceiver *RTPTransceiver
+
+	// StreamIDs are the ids of the MediaStreams the track belongs to, from
+	// the a=msid lines of its media section.
+	StreamIDs []string
+}
+
+// OnTrackEvent sets an event handler which is called when remote track
+// arrives from a remote peer, like OnTrack. The TrackEvent also carries the
+// RTPTransceiver and the stream ids of the track, so they don't have to be
+// derived from the remote description. Both handlers are called if both are set.
+func (pc *PeerConnection) OnTrackEvent(f func(TrackEvent)) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.onTrackEventHandler = f
+}
+
+// OnTrackAnnounced sets an event handler which is called as soon as a remote
+// description declares a new inbound track, before any RTP is received.
+// It can be used to prepare for the media, OnTrack still fires once the
+// track arrives. The TrackRemote of the receiver has its ID and StreamID
+// set, the codec is only known after the first packet.
+func (pc *PeerConnection) OnTrackAnnounced(f func(*RTPTransceiver)) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.onTrackAnnouncedHandler = f
+}
+
+func (pc *PeerConnection) onTrackAnnounced(t *RTPTransceiver) {
+	pc.mu.RLock()
+	handler := pc.onTrackAnnouncedHandler
+	pc.mu.RUnlock()
+
+	if handler != nil && t != nil {
+		pc.goHandler(func() { handler(t) })
+	}
+}
+
 func (pc *PeerConnection) onTrack(t *TrackRemote, r *RTPReceiver) {
 	pc.mu.RLock()
 	handler := pc.onTrackHandler
+	eventHandler := pc.onTrackEventHandler
 	pc.mu.RUnlock()
 
 	pc.log.Debugf("got new track: %+v", t)
-	if t != nil {
-		if handler != nil {
-			go handler(t, r)
-		} else {
-			pc.log.Warnf("OnTrack unset, unable to handle incoming media streams")
+	if t == nil {
+		return
+	}
+
+	if handler != nil {
+		pc.goHandler(func() { handler(t, r) })
+	}
+	if eventHandler != nil {
+		event := TrackEvent{
+			Track:       t,
+			Receiver:    r,
+			Transceiver: r.RTPTransceiver(),
+			StreamIDs:   t.StreamIDs(),
 		}
+		pc.goHandler(func() { eventHandler(event) })
+	}
+	if handler == nil && eventHandler == nil {
+		pc.log.Warnf("OnTrack unset, unable to handle incoming media streams")
 	}
 }
 
+// OnClosed sets an event handler which is called exactly once after the
+// PeerConnection has been closed, all of its internal goroutines have
+// terminated and every callback it runs on its own goroutine (OnTrack,
+// OnTrackAnnounced, OnSignalingStateChange, OnConnectionStateChange) as
+// well as every DataChannel OnMessage callback has returned. Resources shared
+// with those callbacks can be released safely from this handler. It must be
+// set before Close is called.
+//
+// Because OnClosed waits on these callbacks, a callback that never returns,
+// or that blocks until OnClosed has fired, prevents OnClosed from ever being
+// called. Calling Close from a callback is safe.
+func (pc *PeerConnection) OnClosed(f func()) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.onClosedHandler = f
+}
+
+// goHandler runs f in a goroutine that is waited on before OnClosed fires.
+// f is dropped if the PeerConnection has already been closed.
+func (pc *PeerConnection) goHandler(f func()) {
+	pc.handlersMu.Lock()
+	defer pc.handlersMu.Unlock()
+	if pc.handlersStopped {
+		return
+	}
+
+	pc.handlers.Add(1)
+	go func() {
+		defer pc.handlers.Done()
+		f()
+	}()
+}
+
+// onClosed waits for all tracked goroutines and DataChannel read loops to
+// return and then fires the OnClosed handler.
+func (pc *PeerConnection) onClosed(handler func()) {
+	pc.handlers.Wait()
+
+	var readLoops []chan struct{}
+	pc.sctpTransport.lock.Lock()
+	for _, d := range pc.sctpTransport.dataChannels {
+		d.mu.RLock()
+		if d.readLoopActive != nil {
+			readLoops = append(readLoops, d.readLoopActive)
+		}
+		d.mu.RUnlock()
+	}
+	pc.sctpTransport.lock.Unlock()
+
+	for _, readLoop := range readLoops {
+		<-readLoop
+	}
+
+	handler()
+}
+
 // OnICEConnectionStateChange sets an event handler which is called
 // when an ICE connection state is changed.
 func (pc *PeerConnection) OnICEConnectionStateChange(f func(ICEConnectionState)) {
 	pc.onICEConnectionStateChangeHandler.Store(f)
 }
 
+// OnRelayBudgetExceeded sets an event handler which is called once the bytes
+// sent and received through a TURN relay reach SettingEngine.SetRelayBudget.
+func (pc *PeerConnection) OnRelayBudgetExceeded(f func()) {
+	pc.iceTransport.relay.onBudgetExceeded.Store(f)
+}
+
 func (pc *PeerConnection) onICEConnectionStateChange(cs ICEConnectionState) {
 	pc.iceConnectionState.Store(cs)
 	pc.log.Infof("ICE connection state changed: %s", cs)
@@ -505,7 +717,7 @@ func (pc *PeerConnection) onConnectionStateChange(cs PeerConnectionState) {
 	pc.connectionState.Store(cs)
 	pc.log.Infof("peer connection state changed: %s", cs)
 	if handler, ok := pc.onConnectionStateChangeHandler.Load().(func(PeerConnectionState)); ok && handler != nil {
-		go handler(cs)
+		pc.goHandler(func() { handler(cs) })
 	}
 }
 
@@ -563,9 +775,31 @@ func (pc *PeerConnection) SetConfiguration(configuration Configuration) error {
 		pc.configuration.ICECandidatePoolSize = configuration.ICECandidatePoolSize
 	}
 
+	// The latency mode configures the interceptors the PeerConnection was created with
+	if configuration.LatencyMode != LatencyModeUnknown &&
+		configuration.LatencyMode != pc.configuration.LatencyMode {
+		return &rtcerr.InvalidModificationError{Err: ErrModifyingLatencyMode}
+	}
+
 	// https://www.w3.org/TR/webrtc/#set-the-configuration (step #8)
 	pc.configuration.ICETransportPolicy = configuration.ICETransportPolicy
 
+	// Feature flags are a Pion extension and may change at any time
+	if configuration.FeatureFlags != nil {
+		pc.configuration.FeatureFlags = configuration.FeatureFlags
+		pc.featureFlags.Store(pc.api.settingEngine.featureFlags.merge(configuration.FeatureFlags))
+	}
+
+	// The mDNS settings are a Pion extension, they are used when the ICE agent is created
+	if configuration.ICEMulticastDNSMode != ICEMulticastDNSModeUnknown &&
+		configuration.ICEMulticastDNSMode != pc.configuration.ICEMulticastDNSMode {
+		return &rtcerr.InvalidModificationError{Err: ErrModifyingICEMulticastDNS}
+	}
+	if len(configuration.ICEMulticastDNSInterfaces) > 0 &&
+		!pc.configuration.hasICEMulticastDNSInterfaces(configuration.ICEMulticastDNSInterfaces) {
+		return &rtcerr.InvalidModificationError{Err: ErrModifyingICEMulticastDNS}
+	}
+
 	// https://www.w3.org/TR/webrtc/#set-the-configuration (step #11)
 	if len(configuration.ICEServers) > 0 {
 		// https://www.w3.org/TR/webrtc/#set-the-configuration (step #11.3)
@@ -580,6 +814,35 @@ func (pc *PeerConnection) SetConfiguration(configuration Configuration) error {
 	return nil
 }
 
+// configureL4S negotiates and generates RFC 8888 congestion control
+// feedback, unless the MediaEngine has it configured already.
+func (pc *PeerConnection) configureL4S(i interceptor.Interceptor) (interceptor.Interceptor, error) {
+	ccfb := RTCPFeedback{Type: TypeRTCPFBACK, Parameter: "ccfb"}
+	if pc.api.mediaEngine.hasFeedback(ccfb) {
+		return i, nil
+	}
+	for _, name := range pc.configuration.DisabledInterceptors {
+		if name == InterceptorNameCongestionControlFeedback {
+			return i, nil
+		}
+	}
+
+	pc.api.mediaEngine.RegisterFeedback(ccfb, RTPCodecTypeVideo)
+	pc.api.mediaEngine.RegisterFeedback(ccfb, RTPCodecTypeAudio)
+
+	generator, err := rfc8888.NewSenderInterceptor()
+	if err != nil {
+		return nil, err
+	}
+	feedback, err := generator.NewInterceptor("")
+	if err != nil {
+		return nil, err
+	}
+	pc.interceptorNames = append(pc.interceptorNames, InterceptorNameCongestionControlFeedback)
+
+	return interceptor.NewChain([]interceptor.Interceptor{i, feedback}), nil
+}
+
 // GetConfiguration returns a Configuration object representing the current
 // configuration of this PeerConnection object. The returned object is a
 // copy and direct mutation on it will not take affect until SetConfiguration
@@ -589,6 +852,30 @@ func (pc *PeerConnection) GetConfiguration() Configuration {
 	return pc.configuration
 }
 
+// FeatureFlags returns the experimental behaviors in effect for this
+// PeerConnection, the flags of the SettingEngine merged with the ones of
+// the Configuration. The returned value must not be modified.
+func (pc *PeerConnection) FeatureFlags() FeatureFlags {
+	flags, _ := pc.featureFlags.Load().(FeatureFlags)
+
+	return flags
+}
+
+// Interceptors returns the names of the interceptors bound to this
+// PeerConnection, in the order they were added to the interceptor.Registry.
+// Only the interceptors of named factories are listed, see
+// NewNamedInterceptorFactory.
+func (pc *PeerConnection) Interceptors() []string {
+	return append([]string{}, pc.interceptorNames...)
+}
+
+// BandwidthEstimator returns the estimator of the congestion controller
+// added with ConfigureCongestionController, or nil if there is none. Its
+// target bitrate is where to set the bitrate of the encoders.
+func (pc *PeerConnection) BandwidthEstimator() cc.BandwidthEstimator {
+	return pc.bandwidthEstimator
+}
+
 func (pc *PeerConnection) getStatsID() string {
 	pc.mu.RLock()
 	defer pc.mu.RUnlock()
@@ -613,6 +900,109 @@ func (pc *PeerConnection) hasLocalDescriptionChanged(desc *SessionDescription) b
 	return false
 }
 
+// RestartICE requests an ICE restart. The next offer created with CreateOffer
+// restarts ICE as if OfferOptions.ICERestart was set, and OnNegotiationNeeded
+// fires so the application can create and signal it.
+// https://www.w3.org/TR/webrtc/#dom-rtcpeerconnection-restartice
+func (pc *PeerConnection) RestartICE() {
+	if pc.isClosed.get() {
+		return
+	}
+
+	pc.iceRestartPending.set(true)
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.onNegotiationNeeded()
+}
+
+// RebindICE replaces the local sockets of an established PeerConnection, for
+// example after a socket error, without an ICE restart. New local candidates
+// are gathered with the current ICE credentials and emitted by
+// OnICECandidate, the known remote candidates are kept. Once the new
+// candidates are signaled with AddICECandidate, a remote peer that supports
+// trickle ICE switches to them without a new offer and answer, DTLS and SCTP
+// are kept. Use RestartICE if the remote peer doesn't support it.
+//
+// The ICE connection state goes back to checking until a new candidate pair
+// is selected. The candidates are gathered with the SettingEngine the
+// PeerConnection was created with, like its UDPMux.
+func (pc *PeerConnection) RebindICE() error {
+	if pc.isClosed.get() {
+		return &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
+	}
+
+	return pc.iceTransport.rebind()
+}
+
+// Hold puts the media of the PeerConnection on hold as described in RFC 6337
+// section 5.3: transceivers stop receiving, sendrecv ones become sendonly
+// and recvonly ones inactive. Media keeps being sent, so hold music can be
+// played. OnNegotiationNeeded fires so the application can create and signal
+// the offer. Calling Hold while on hold does nothing.
+func (pc *PeerConnection) Hold() error {
+	if pc.isClosed.get() {
+		return &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.holdDirections != nil {
+		return nil
+	}
+
+	pc.holdDirections = map[*RTPTransceiver]RTPTransceiverDirection{}
+	for _, transceiver := range pc.rtpTransceivers {
+		direction := transceiver.Direction()
+		switch direction {
+		case RTPTransceiverDirectionSendrecv:
+			transceiver.setDirection(RTPTransceiverDirectionSendonly)
+		case RTPTransceiverDirectionRecvonly:
+			transceiver.setDirection(RTPTransceiverDirectionInactive)
+		default:
+			continue
+		}
+		pc.holdDirections[transceiver] = direction
+	}
+	pc.onNegotiationNeeded()
+
+	return nil
+}
+
+// Resume takes the media of the PeerConnection off hold, the transceivers
+// changed by Hold get their direction back. OnNegotiationNeeded fires so the
+// application can create and signal the offer. Calling Resume while not on
+// hold does nothing.
+func (pc *PeerConnection) Resume() error {
+	if pc.isClosed.get() {
+		return &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.holdDirections == nil {
+		return nil
+	}
+
+	for transceiver, direction := range pc.holdDirections {
+		transceiver.setDirection(direction)
+	}
+	pc.holdDirections = nil
+	pc.onNegotiationNeeded()
+
+	return nil
+}
+
+// IsOnHold returns true between Hold and Resume.
+func (pc *PeerConnection) IsOnHold() bool {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	return pc.holdDirections != nil
+}
+
 // CreateOffer starts the PeerConnection and generates the localDescription
 // https://w3c.github.io/webrtc-pc/#dom-rtcpeerconnection-createoffer
 //
@@ -626,10 +1016,17 @@ func (pc *PeerConnection) CreateOffer(options *OfferOptions) (SessionDescription
 		return SessionDescription{}, &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
 	}
 
-	if options != nil && options.ICERestart {
+	iceRestart := options != nil && options.ICERestart
+	if pc.iceRestartPending.get() && pc.CurrentLocalDescription() != nil {
+		iceRestart = true
+	}
+
+	if iceRestart {
 		if err := pc.iceTransport.restart(); err != nil {
 			return SessionDescription{}, err
 		}
+		pc.iceRestartPending.set(false)
+		pc.resetAppliedRemoteDescription()
 	}
 
 	var (
@@ -677,6 +1074,19 @@ func (pc *PeerConnection) CreateOffer(options *OfferOptions) (SessionDescription
 					}
 				}
 			}
+			// mids suggested by the application go first, so the numeric mids
+			// assigned below don't take them
+			for _, t := range currentTransceivers {
+				if t.Mid() != "" || t.suggestedMid == "" || pc.midInUse(t.suggestedMid, t) {
+					continue
+				}
+				if err = t.SetMid(t.suggestedMid); err != nil {
+					return SessionDescription{}, err
+				}
+				if numericMid, errMid := strconv.Atoi(t.suggestedMid); errMid == nil && numericMid > pc.greaterMid {
+					pc.greaterMid = numericMid
+				}
+			}
 			for _, t := range currentTransceivers {
 				if mid := t.Mid(); mid != "" {
 					numericMid, errMid := strconv.Atoi(mid)
@@ -743,6 +1153,9 @@ func (pc *PeerConnection) createICEGatherer() (*ICEGatherer, error) {
 	g, err := pc.api.NewICEGatherer(ICEGatherOptions{
 		ICEServers:      pc.configuration.getICEServers(),
 		ICEGatherPolicy: pc.configuration.ICETransportPolicy,
+
+		MulticastDNSMode:       pc.configuration.ICEMulticastDNSMode,
+		MulticastDNSInterfaces: pc.configuration.ICEMulticastDNSInterfaces,
 	})
 	if err != nil {
 		return nil, err
@@ -803,6 +1216,9 @@ func (pc *PeerConnection) updateConnectionState(
 
 func (pc *PeerConnection) createICETransport() *ICETransport {
 	transport := pc.api.NewICETransport(pc.iceGatherer)
+	if transport.pacer != nil && pc.bandwidthEstimator != nil {
+		transport.pacer.setEstimator(pc.bandwidthEstimator)
+	}
 	transport.internalOnConnectionStateChangeHandler.Store(func(state ICETransportState) {
 		var cs ICEConnectionState
 		switch state {
@@ -827,6 +1243,7 @@ func (pc *PeerConnection) createICETransport() *ICETransport {
 		}
 		pc.onICEConnectionStateChange(cs)
 		pc.updateConnectionState(cs, pc.dtlsTransport.State())
+		pc.iceAutoRestart.handleICEConnectionStateChange(pc, cs)
 	})
 
 	return transport
@@ -1013,6 +1430,12 @@ func (pc *PeerConnection) SetLocalDescription(desc SessionDescription) error {
 		return &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
 	}
 
+	if desc.Type == SDPTypeRollback {
+		pc.resetAppliedRemoteDescription()
+
+		return pc.rollbackLocalDescription(desc)
+	}
+
 	haveLocalDescription := pc.currentLocalDescription != nil
 
 	// JSEP 5.4
@@ -1046,9 +1469,10 @@ func (pc *PeerConnection) SetLocalDescription(desc SessionDescription) error {
 		if err := pc.startRTPSenders(currentTransceivers); err != nil {
 			return err
 		}
-		pc.configureRTPReceivers(haveLocalDescription, remoteDesc, currentTransceivers)
+		unchanged := pc.unchangedRemoteMediaSections(remoteDesc, currentTransceivers)
+		pc.configureRTPReceivers(haveLocalDescription, remoteDesc, currentTransceivers, unchanged)
 		pc.ops.Enqueue(func() {
-			pc.startRTP(haveLocalDescription, remoteDesc, currentTransceivers)
+			pc.startRTP(haveLocalDescription, remoteDesc, currentTransceivers, unchanged)
 		})
 	}
 
@@ -1064,6 +1488,75 @@ func (pc *PeerConnection) SetLocalDescription(desc SessionDescription) error {
 	return nil
 }
 
+// rollbackLocalDescription discards the pending local offer. The mids it
+// assigned to transceivers are released, so the transceivers can be matched
+// to the media sections of a remote offer.
+func (pc *PeerConnection) rollbackLocalDescription(desc SessionDescription) error {
+	if err := pc.setDescription(&desc, stateChangeOpSetLocal); err != nil {
+		return err
+	}
+
+	var negotiatedMids map[string]bool
+	if current := pc.CurrentLocalDescription(); current != nil && current.parsed != nil {
+		negotiatedMids = map[string]bool{}
+		for _, media := range current.parsed.MediaDescriptions {
+			negotiatedMids[getMidValue(media)] = true
+		}
+	}
+
+	for _, transceiver := range pc.GetTransceivers() {
+		if mid := transceiver.Mid(); mid != "" && !negotiatedMids[mid] {
+			transceiver.mid.Store("")
+		}
+	}
+
+	return nil
+}
+
+// rollbackRemoteDescription discards the pending remote offer (JSEP 5.7).
+// The transceivers it created are removed, unless a track was added to them
+// since, and the mids it assigned to the others are released.
+func (pc *PeerConnection) rollbackRemoteDescription(desc SessionDescription) error {
+	if err := pc.setDescription(&desc, stateChangeOpSetRemote); err != nil {
+		return err
+	}
+
+	var negotiatedMids map[string]bool
+	if current := pc.CurrentRemoteDescription(); current != nil && current.parsed != nil {
+		negotiatedMids = map[string]bool{}
+		for _, media := range current.parsed.MediaDescriptions {
+			negotiatedMids[getMidValue(media)] = true
+		}
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	created := map[*RTPTransceiver]bool{}
+	for _, transceiver := range pc.remoteOfferTransceivers {
+		created[transceiver] = true
+	}
+	pc.remoteOfferTransceivers = nil
+
+	transceivers := make([]*RTPTransceiver, 0, len(pc.rtpTransceivers))
+	for _, transceiver := range pc.rtpTransceivers {
+		if mid := transceiver.Mid(); mid != "" && !negotiatedMids[mid] {
+			if created[transceiver] && transceiver.Sender() == nil {
+				if err := transceiver.Stop(); err != nil {
+					return err
+				}
+
+				continue
+			}
+			transceiver.mid.Store("")
+		}
+		transceivers = append(transceivers, transceiver)
+	}
+	pc.rtpTransceivers = transceivers
+
+	return nil
+}
+
 // LocalDescription returns PendingLocalDescription if it is not null and
 // otherwise it returns CurrentLocalDescription. This property is used to
 // determine if SetLocalDescription has already been called.
@@ -1084,15 +1577,34 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 		return &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
 	}
 
+	if desc.Type == SDPTypeRollback {
+		pc.resetAppliedRemoteDescription()
+
+		return pc.rollbackRemoteDescription(desc)
+	}
+
 	isRenegotiation := pc.currentRemoteDescription != nil
 
+	if err := pc.remoteSignalingLimiter.checkDescriptionSize(pc.api.settingEngine, &desc); err != nil {
+		return err
+	}
 	if _, err := desc.Unmarshal(); err != nil {
 		return err
 	}
+	if err := pc.remoteSignalingLimiter.checkDescription(pc.api.settingEngine, desc.parsed); err != nil {
+		return err
+	}
+	if err := pc.validateRemoteIdentity(desc.parsed); err != nil {
+		return err
+	}
 	if err := pc.setDescription(&desc, stateChangeOpSetRemote); err != nil {
 		return err
 	}
 
+	// Feedback is sent without reports only if the remote signals rtcp-rsize
+	pc.dtlsTransport.rtcpCompound.set(!isRTCPReducedSizeSet(desc.parsed))
+
+	pc.enableQuirks(desc)
 	if err := pc.api.mediaEngine.updateFromRemoteDescription(*desc.parsed); err != nil {
 		return err
 	}
@@ -1111,6 +1623,12 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 
 	weOffer := desc.Type == SDPTypeAnswer
 
+	if desc.Type == SDPTypeOffer {
+		pc.mu.Lock()
+		pc.remoteOfferTransceivers = nil
+		pc.mu.Unlock()
+	}
+
 	if !weOffer && !detectedPlanB { //nolint:nestif
 		for _, media := range pc.RemoteDescription().parsed.MediaDescriptions {
 			midValue := getMidValue(media)
@@ -1154,6 +1672,9 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 				transceiver = newRTPTransceiver(receiver, nil, localDirection, kind, pc.api)
 				pc.mu.Lock()
 				pc.addRTPTransceiver(transceiver)
+				if desc.Type == SDPTypeOffer {
+					pc.remoteOfferTransceivers = append(pc.remoteOfferTransceivers, transceiver)
+				}
 				pc.mu.Unlock()
 
 				// if transceiver is create by remote sdp, set prefer codec same as remote peer
@@ -1163,7 +1684,7 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 						if c, matchType := codecParametersFuzzySearch(
 							codec,
 							pc.api.mediaEngine.getCodecsByKind(kind),
-						); matchType == codecMatchExact {
+						); matchType == CodecMatchExact {
 							// if codec match exact, use payloadtype register to mediaengine
 							codec.PayloadType = c.PayloadType
 							filteredCodecs = append(filteredCodecs, codec)
@@ -1210,6 +1731,8 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 				return err
 			}
 		}
+		pc.resetAppliedRemoteDescription()
+		pc.remoteSignalingLimiter.restartICE(len(iceDetails.Candidates))
 
 		if err = pc.iceTransport.setRemoteCredentials(iceDetails.Ufrag, iceDetails.Password); err != nil {
 			return err
@@ -1223,6 +1746,7 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 	}
 
 	currentTransceivers := append([]*RTPTransceiver{}, pc.GetTransceivers()...)
+	setRemoteBandwidths(desc.parsed, currentTransceivers)
 
 	if isRenegotiation {
 		if weOffer {
@@ -1230,9 +1754,10 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 			if err = pc.startRTPSenders(currentTransceivers); err != nil {
 				return err
 			}
-			pc.configureRTPReceivers(true, &desc, currentTransceivers)
+			unchanged := pc.unchangedRemoteMediaSections(&desc, currentTransceivers)
+			pc.configureRTPReceivers(true, &desc, currentTransceivers, unchanged)
 			pc.ops.Enqueue(func() {
-				pc.startRTP(true, &desc, currentTransceivers)
+				pc.startRTP(true, &desc, currentTransceivers, unchanged)
 			})
 		}
 
@@ -1263,7 +1788,7 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 			return err
 		}
 
-		pc.configureRTPReceivers(false, &desc, currentTransceivers)
+		pc.configureRTPReceivers(false, &desc, currentTransceivers, pc.unchangedRemoteMediaSections(&desc, currentTransceivers))
 	}
 
 	pc.ops.Enqueue(func() {
@@ -1276,7 +1801,7 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 			fingerprintHash,
 		)
 		if weOffer {
-			pc.startRTP(false, &desc, currentTransceivers)
+			pc.startRTP(false, &desc, currentTransceivers, nil)
 		}
 	})
 
@@ -1292,6 +1817,7 @@ func (pc *PeerConnection) configureReceiver(incoming trackDetails, receiver *RTP
 		receiver.tracks[i].track.mu.Lock()
 		receiver.tracks[i].track.id = incoming.id
 		receiver.tracks[i].track.streamID = incoming.streamID
+		receiver.tracks[i].track.streamIDs = incoming.streamIDs
 		receiver.tracks[i].track.mu.Unlock()
 	}
 }
@@ -1313,7 +1839,9 @@ func (pc *PeerConnection) startReceiver(incoming trackDetails, receiver *RTPRece
 
 			return
 		}
-		go func(track *TrackRemote) {
+
+		track := track
+		pc.goHandler(func() {
 			b := make([]byte, pc.api.settingEngine.getReceiveMTU())
 			n, _, err := track.peek(b)
 			if err != nil {
@@ -1329,7 +1857,7 @@ func (pc *PeerConnection) startReceiver(incoming trackDetails, receiver *RTPRece
 			}
 
 			pc.onTrack(track, receiver)
-		}(track)
+		})
 	}
 }
 
@@ -1412,6 +1940,92 @@ func runIfNewReceiver(
 	return false
 }
 
+// appliedTransceiverState is the local state of a transceiver when the
+// receivers were configured for its media section.
+type appliedTransceiverState struct {
+	direction RTPTransceiverDirection
+	receiver  *RTPReceiver
+	sender    *RTPSender
+
+	trackID, streamID string
+}
+
+func newAppliedTransceiverState(transceiver *RTPTransceiver) appliedTransceiverState {
+	state := appliedTransceiverState{
+		direction: transceiver.Direction(),
+		receiver:  transceiver.Receiver(),
+		sender:    transceiver.Sender(),
+	}
+
+	if state.sender != nil {
+		if track := state.sender.Track(); track != nil {
+			state.trackID, state.streamID = track.ID(), track.StreamID()
+		}
+	}
+
+	return state
+}
+
+// unchangedRemoteMediaSections diffs remoteDesc and the transceivers against the
+// remote description and transceiver state receivers were last configured from,
+// and returns the mids of the media sections that didn't change on either side.
+// Renegotiation leaves their receivers and tracks untouched.
+func (pc *PeerConnection) unchangedRemoteMediaSections(
+	remoteDesc *SessionDescription,
+	currentTransceivers []*RTPTransceiver,
+) map[string]struct{} {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	unchanged := unchangedMediaSections(pc.appliedRemoteDescription, remoteDesc.parsed)
+
+	appliedTransceivers := make(map[string]appliedTransceiverState, len(currentTransceivers))
+	for _, transceiver := range currentTransceivers {
+		mid := transceiver.Mid()
+		if mid == "" {
+			continue
+		}
+
+		state := newAppliedTransceiverState(transceiver)
+		if previous, ok := pc.appliedTransceivers[mid]; !ok || previous != state {
+			delete(unchanged, mid)
+		}
+		appliedTransceivers[mid] = state
+	}
+
+	pc.appliedRemoteDescription = remoteDesc.parsed
+	pc.appliedTransceivers = appliedTransceivers
+
+	return unchanged
+}
+
+// resetAppliedRemoteDescription makes the next renegotiation apply all media
+// sections. It is called when the state the receivers were configured from
+// can't be trusted anymore, on rollback and ICE restart.
+func (pc *PeerConnection) resetAppliedRemoteDescription() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.appliedRemoteDescription = nil
+	pc.appliedTransceivers = nil
+}
+
+// filterUnchangedTracks removes the tracks of media sections that didn't change.
+func filterUnchangedTracks(incomingTracks []trackDetails, unchanged map[string]struct{}) []trackDetails {
+	if len(unchanged) == 0 {
+		return incomingTracks
+	}
+
+	filtered := make([]trackDetails, 0, len(incomingTracks))
+	for _, incomingTrack := range incomingTracks {
+		if _, ok := unchanged[incomingTrack.mid]; !ok {
+			filtered = append(filtered, incomingTrack)
+		}
+	}
+
+	return filtered
+}
+
 // configureRTPReceivers opens knows inbound SRTP streams from the RemoteDescription.
 //
 //nolint:gocognit,cyclop
@@ -1419,8 +2033,9 @@ func (pc *PeerConnection) configureRTPReceivers(
 	isRenegotiation bool,
 	remoteDesc *SessionDescription,
 	currentTransceivers []*RTPTransceiver,
+	unchanged map[string]struct{},
 ) {
-	incomingTracks := trackDetailsFromSDP(pc.log, remoteDesc.parsed)
+	incomingTracks := filterUnchangedTracks(trackDetailsFromSDP(pc.log, remoteDesc.parsed), unchanged)
 
 	if isRenegotiation { //nolint:nestif
 		for _, transceiver := range currentTransceivers {
@@ -1429,6 +2044,10 @@ func (pc *PeerConnection) configureRTPReceivers(
 				continue
 			}
 
+			if _, ok := unchanged[transceiver.Mid()]; ok {
+				continue
+			}
+
 			tracks := transceiver.Receiver().Tracks()
 			if len(tracks) == 0 {
 				continue
@@ -1445,6 +2064,7 @@ func (pc *PeerConnection) configureRTPReceivers(
 						if details := trackDetailsForRID(incomingTracks, mid, track.rid); details != nil {
 							track.id = details.id
 							track.streamID = details.streamID
+							track.streamIDs = details.streamIDs
 
 							return
 						}
@@ -1452,6 +2072,7 @@ func (pc *PeerConnection) configureRTPReceivers(
 						if details := trackDetailsForSSRC(incomingTracks, track.ssrc); details != nil {
 							track.id = details.id
 							track.streamID = details.streamID
+							track.streamIDs = details.streamIDs
 
 							return
 						}
@@ -1501,13 +2122,20 @@ func (pc *PeerConnection) configureRTPReceivers(
 	}
 
 	for _, incomingTrack := range filteredTracks {
-		_ = runIfNewReceiver(incomingTrack, localTransceivers, pc.configureReceiver)
+		_ = runIfNewReceiver(incomingTrack, localTransceivers, func(incoming trackDetails, receiver *RTPReceiver) {
+			pc.configureReceiver(incoming, receiver)
+			pc.onTrackAnnounced(receiver.RTPTransceiver())
+		})
 	}
 }
 
 // startRTPReceivers opens knows inbound SRTP streams from the RemoteDescription.
-func (pc *PeerConnection) startRTPReceivers(remoteDesc *SessionDescription, currentTransceivers []*RTPTransceiver) {
-	incomingTracks := trackDetailsFromSDP(pc.log, remoteDesc.parsed)
+func (pc *PeerConnection) startRTPReceivers(
+	remoteDesc *SessionDescription,
+	currentTransceivers []*RTPTransceiver,
+	unchanged map[string]struct{},
+) {
+	incomingTracks := filterUnchangedTracks(trackDetailsFromSDP(pc.log, remoteDesc.parsed), unchanged)
 	if len(incomingTracks) == 0 {
 		return
 	}
@@ -1556,6 +2184,10 @@ func (pc *PeerConnection) startRTPSenders(currentTransceivers []*RTPTransceiver)
 			if err != nil {
 				return err
 			}
+
+			if conn := pc.api.settingEngine.metadataPacketConn; conn != nil && pc.FeatureFlags().IsEnabled(FeatureFlagL4S) {
+				sender.markECT1(conn)
+			}
 		}
 	}
 
@@ -1563,10 +2195,10 @@ func (pc *PeerConnection) startRTPSenders(currentTransceivers []*RTPTransceiver)
 }
 
 // Start SCTP subsystem.
-func (pc *PeerConnection) startSCTP() {
+func (pc *PeerConnection) startSCTP(remoteMaxMessageSize uint32) {
 	// Start sctp
 	if err := pc.sctpTransport.Start(SCTPCapabilities{
-		MaxMessageSize: 0,
+		MaxMessageSize: remoteMaxMessageSize,
 	}); err != nil {
 		pc.log.Warnf("Failed to start SCTP: %s", err)
 		if err = pc.sctpTransport.Stop(); err != nil {
@@ -1579,6 +2211,7 @@ func (pc *PeerConnection) startSCTP() {
 
 //nolint:cyclop
 func (pc *PeerConnection) handleUndeclaredSSRC(
+	rtpStream io.Reader,
 	ssrc SSRC,
 	remoteDescription *SessionDescription,
 ) (handled bool, err error) {
@@ -1589,6 +2222,7 @@ func (pc *PeerConnection) handleUndeclaredSSRC(
 	onlyMediaSection := remoteDescription.parsed.MediaDescriptions[0]
 	streamID := ""
 	id := ""
+	var streamIDs []string
 	hasRidAttribute := false
 	hasSSRCAttribute := false
 
@@ -1598,6 +2232,7 @@ func (pc *PeerConnection) handleUndeclaredSSRC(
 			if split := strings.Split(a.Value, " "); len(split) == 2 {
 				streamID = split[0]
 				id = split[1]
+				streamIDs = appendMsidStreamID(streamIDs, split[0])
 			}
 		case sdp.AttrKeySSRC:
 			hasSSRCAttribute = true
@@ -1613,15 +2248,21 @@ func (pc *PeerConnection) handleUndeclaredSSRC(
 	}
 
 	incoming := trackDetails{
-		ssrcs:    []SSRC{ssrc},
-		kind:     RTPCodecTypeVideo,
-		streamID: streamID,
-		id:       id,
+		ssrcs:     []SSRC{ssrc},
+		kind:      RTPCodecTypeVideo,
+		streamID:  streamID,
+		streamIDs: streamIDs,
+		id:        id,
 	}
 	if onlyMediaSection.MediaName.Media == RTPCodecTypeAudio.String() {
 		incoming.kind = RTPCodecTypeAudio
 	}
 
+	handled, firstPacket, err := pc.handleUndeclaredRTX(rtpStream, ssrc, onlyMediaSection, incoming.kind)
+	if handled || err != nil {
+		return handled, err
+	}
+
 	t, err := pc.AddTransceiverFromKind(incoming.kind, RTPTransceiverInit{
 		Direction: RTPTransceiverDirectionSendrecv,
 	})
@@ -1631,11 +2272,111 @@ func (pc *PeerConnection) handleUndeclaredSSRC(
 	}
 
 	pc.configureReceiver(incoming, t.Receiver())
+
+	// The packet read to look for RTX is the first packet of the track
+	if track := t.Receiver().Track(); track != nil && firstPacket != nil {
+		track.mu.Lock()
+		track.peeked = firstPacket
+		track.mu.Unlock()
+	}
+
 	pc.startReceiver(incoming, t.Receiver())
 
 	return true, nil
 }
 
+// handleUndeclaredRTX binds an undeclared SSRC carrying RTX to the track it
+// repairs instead of surfacing it as a new track. It is only attempted if the
+// media section offers rtx. The first packet of the SSRC is read to tell if it
+// is RTX, and paired if the codec it repairs is the one of a track without
+// repair stream. Otherwise the packet is returned, it is the first packet of
+// the new track.
+func (pc *PeerConnection) handleUndeclaredRTX(
+	rtpStream io.Reader,
+	ssrc SSRC,
+	media *sdp.MediaDescription,
+	kind RTPCodecType,
+) (handled bool, firstPacket []byte, err error) {
+	if !mediaSectionHasRTX(media) {
+		return false, nil, nil
+	}
+
+	b := make([]byte, pc.api.settingEngine.getReceiveMTU())
+	i, err := rtpStream.Read(b)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if i < 4 {
+		return false, nil, errRTPTooShort
+	}
+	b = b[:i]
+
+	params, err := pc.api.mediaEngine.getRTPParametersByPayloadType(PayloadType(b[1] & 0x7f))
+	if err != nil || !strings.EqualFold(params.Codecs[0].MimeType, MimeTypeRTX) {
+		return false, b, nil //nolint:nilerr
+	}
+
+	apt, ok := fmtp.Parse(params.Codecs[0].MimeType, params.Codecs[0].SDPFmtpLine).Parameter("apt")
+	if !ok {
+		return false, b, nil
+	}
+
+	var receiver *RTPReceiver
+	for _, t := range pc.GetTransceivers() {
+		if r := t.Receiver(); r != nil && t.Kind() == kind {
+			if track := r.Track(); track != nil && track.SSRC() != 0 && !track.HasRTX() &&
+				strconv.Itoa(int(track.PayloadType())) == apt {
+				receiver = r
+
+				break
+			}
+		}
+	}
+	if receiver == nil {
+		return false, b, nil
+	}
+
+	streamInfo := createStreamInfo(
+		"",
+		ssrc,
+		0, 0,
+		params.Codecs[0].PayloadType,
+		0, 0,
+		params.Codecs[0].RTPCodecCapability,
+		params.HeaderExtensions,
+	)
+	readStream, interceptor, rtcpReadStream, rtcpInterceptor, err := pc.dtlsTransport.streamsForSSRC(ssrc, *streamInfo)
+	if err != nil {
+		return false, nil, err
+	}
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+
+	return true, nil, receiver.receiveForRtx(
+		ssrc, "", streamInfo, readStream, &bufferedRTPReader{packet: b, reader: interceptor}, rtcpReadStream, rtcpInterceptor,
+	)
+}
+
+// bufferedRTPReader returns packet from its first Read, it was read from the
+// stream before reader was bound to it.
+type bufferedRTPReader struct {
+	packet []byte
+	reader interceptor.RTPReader
+}
+
+func (r *bufferedRTPReader) Read(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+	if r.packet != nil {
+		n := copy(b, r.packet)
+		r.packet = nil
+
+		return n, a, nil
+	}
+
+	return r.reader.Read(b, a)
+}
+
 // Chrome sends probing traffic on SSRC 0. This reads the packets to ensure that we properly
 // generate TWCC reports for it. Since this isn't actually media we don't pass this to the user.
 func (pc *PeerConnection) handleNonMediaBandwidthProbe() {
@@ -1672,19 +2413,13 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 	}
 
 	// If a SSRC already exists in the RemoteDescription don't perform heuristics upon it
-	for _, track := range trackDetailsFromSDP(pc.log, remoteDescription.parsed) {
-		if track.repairSsrc != nil && ssrc == *track.repairSsrc {
-			return nil
-		}
-		for _, trackSsrc := range track.ssrcs {
-			if ssrc == trackSsrc {
-				return nil
-			}
-		}
+	routes := pc.rtpRouter.get(remoteDescription.parsed, pc.GetTransceivers, pc.log)
+	if routes.isDeclared(ssrc) {
+		return nil
 	}
 
 	// If the remote SDP was only one media section the ssrc doesn't have to be explicitly declared
-	if handled, err := pc.handleUndeclaredSSRC(ssrc, remoteDescription); handled || err != nil {
+	if handled, err := pc.handleUndeclaredSSRC(rtpStream, ssrc, remoteDescription); handled || err != nil {
 		return err
 	}
 
@@ -1723,6 +2458,9 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 		return err
 	}
 
+	// RTX streams without RSID repair the only track of the media section with their MID
+	isRTX := strings.EqualFold(params.Codecs[0].MimeType, MimeTypeRTX)
+
 	streamInfo := createStreamInfo(
 		"",
 		ssrc,
@@ -1732,6 +2470,9 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 		params.Codecs[0].RTPCodecCapability,
 		params.HeaderExtensions,
 	)
+	if strings.EqualFold(params.Codecs[0].MimeType, MimeTypeVideoRED) {
+		setULPFECStreamInfo(streamInfo, pc.api.mediaEngine.getCodecsByKind(RTPCodecTypeVideo))
+	}
 	readStream, interceptor, rtcpReadStream, rtcpInterceptor, err := pc.dtlsTransport.streamsForSSRC(ssrc, *streamInfo)
 	if err != nil {
 		return err
@@ -1740,7 +2481,7 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 	var mid, rid, rsid string
 	var paddingOnly bool
 	for readCount := 0; readCount <= simulcastProbeCount; readCount++ {
-		if mid == "" || (rid == "" && rsid == "") {
+		if mid == "" || (rid == "" && rsid == "" && !isRTX) {
 			// skip padding only packets for probing
 			if paddingOnly {
 				readCount--
@@ -1765,35 +2506,45 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 			continue
 		}
 
-		for _, t := range pc.GetTransceivers() {
-			receiver := t.Receiver()
-			if t.Mid() != mid || receiver == nil {
-				continue
-			}
+		t := routes.transceiverByMid(mid, pc.GetTransceivers)
+		if t == nil {
+			continue
+		}
+
+		receiver := t.Receiver()
+		if receiver == nil {
+			continue
+		}
 
-			if rsid != "" {
-				receiver.mu.Lock()
-				defer receiver.mu.Unlock()
+		if rsid != "" || isRTX {
+			receiver.mu.Lock()
+			defer receiver.mu.Unlock()
 
-				return receiver.receiveForRtx(SSRC(0), rsid, streamInfo, readStream, interceptor, rtcpReadStream, rtcpInterceptor)
+			rtxSSRC := SSRC(0)
+			if rsid == "" {
+				rtxSSRC = ssrc
 			}
 
-			track, err := receiver.receiveForRid(
-				rid,
-				params,
-				streamInfo,
-				readStream,
-				interceptor,
-				rtcpReadStream,
-				rtcpInterceptor,
+			return receiver.receiveForRtx(
+				rtxSSRC, rsid, streamInfo, readStream, interceptor, rtcpReadStream, rtcpInterceptor,
 			)
-			if err != nil {
-				return err
-			}
-			pc.onTrack(track, receiver)
+		}
 
-			return nil
+		track, err := receiver.receiveForRid(
+			rid,
+			params,
+			streamInfo,
+			readStream,
+			interceptor,
+			rtcpReadStream,
+			rtcpInterceptor,
+		)
+		if err != nil {
+			return err
 		}
+		pc.onTrack(track, receiver)
+
+		return nil
 	}
 
 	pc.api.interceptor.UnbindRemoteStream(streamInfo)
@@ -1803,12 +2554,11 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 
 // undeclaredMediaProcessor handles RTP/RTCP packets that don't match any a:ssrc lines.
 func (pc *PeerConnection) undeclaredMediaProcessor() {
-	go pc.undeclaredRTPMediaProcessor()
-	go pc.undeclaredRTCPMediaProcessor()
+	pc.goHandler(pc.undeclaredRTPMediaProcessor)
+	pc.goHandler(pc.undeclaredRTCPMediaProcessor)
 }
 
 func (pc *PeerConnection) undeclaredRTPMediaProcessor() { //nolint:cyclop
-	var simulcastRoutineCount uint64
 	for {
 		srtpSession, err := pc.dtlsTransport.getSRTPSession()
 		if err != nil {
@@ -1853,29 +2603,29 @@ func (pc *PeerConnection) undeclaredRTPMediaProcessor() { //nolint:cyclop
 		pc.dtlsTransport.storeSimulcastStream(srtpReadStream, srtcpReadStream)
 
 		if ssrc == 0 {
-			go pc.handleNonMediaBandwidthProbe()
+			pc.goHandler(pc.handleNonMediaBandwidthProbe)
 
 			continue
 		}
 
-		if atomic.AddUint64(&simulcastRoutineCount, 1) >= simulcastMaxProbeRoutines {
-			atomic.AddUint64(&simulcastRoutineCount, ^uint64(0))
+		if !pc.api.probeRoutines.acquire() {
 			pc.log.Warn(ErrSimulcastProbeOverflow.Error())
 
 			continue
 		}
 
-		go func(rtpStream io.Reader, ssrc SSRC) {
-			if err := pc.handleIncomingSSRC(rtpStream, ssrc); err != nil {
-				pc.log.Errorf(incomingUnhandledRTPSsrc, ssrc, err)
+		rtpStream, streamSSRC := srtpReadStream, SSRC(ssrc)
+		pc.goHandler(func() {
+			if err := pc.handleIncomingSSRC(rtpStream, streamSSRC); err != nil {
+				pc.log.Errorf(incomingUnhandledRTPSsrc, streamSSRC, err)
 			}
-			atomic.AddUint64(&simulcastRoutineCount, ^uint64(0))
-		}(srtpReadStream, SSRC(ssrc))
+			pc.api.probeRoutines.release()
+		})
 	}
 }
 
 func (pc *PeerConnection) undeclaredRTCPMediaProcessor() {
-	var unhandledStreams []*srtp.ReadStreamSRTCP
+	var unhandledStreams []SRTPReadStream
 	defer func() {
 		for _, s := range unhandledStreams {
 			_ = s.Close()
@@ -1926,6 +2676,10 @@ func (pc *PeerConnection) AddICECandidate(candidate ICECandidateInit) error {
 
 	var iceCandidate *ICECandidate
 	if candidateValue != "" {
+		if err := pc.remoteSignalingLimiter.checkCandidate(pc.api.settingEngine, time.Now()); err != nil {
+			return err
+		}
+
 		candidate, err := ice.UnmarshalCandidate(candidateValue)
 		if err != nil {
 			if errors.Is(err, ice.ErrUnknownCandidateTyp) || errors.Is(err, ice.ErrDetermineNetworkType) {
@@ -2091,10 +2845,11 @@ func (pc *PeerConnection) newTransceiverFromTrack(
 		return t, err
 	}
 
-	// Allow RTPTransceiverInit to override SSRC
-	if sender != nil && len(sender.trackEncodings) == 1 &&
-		len(init) == 1 && len(init[0].SendEncodings) == 1 && init[0].SendEncodings[0].SSRC != 0 {
-		sender.trackEncodings[0].ssrc = init[0].SendEncodings[0].SSRC
+	// Allow RTPTransceiverInit to override SSRCs and declare simulcast encodings
+	if sender != nil && len(init) == 1 && len(init[0].SendEncodings) != 0 {
+		if err = sender.setSendEncodings(init[0].SendEncodings); err != nil {
+			return t, err
+		}
 	}
 
 	return newRTPTransceiver(receiver, sender, direction, track.Kind(), pc.api), nil
@@ -2141,8 +2896,11 @@ func (pc *PeerConnection) AddTransceiverFromKind(
 		return nil, errPeerConnAddTransceiverFromKindSupport
 	}
 	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if err = pc.setSuggestedMid(t, init...); err != nil {
+		return nil, err
+	}
 	pc.addRTPTransceiver(t)
-	pc.mu.Unlock()
 
 	return t, nil
 }
@@ -2164,13 +2922,18 @@ func (pc *PeerConnection) AddTransceiverFromTrack(
 	}
 
 	t, err = pc.newTransceiverFromTrack(direction, track, init...)
-	if err == nil {
-		pc.mu.Lock()
-		pc.addRTPTransceiver(t)
-		pc.mu.Unlock()
+	if err != nil {
+		return nil, err
 	}
 
-	return
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if err = pc.setSuggestedMid(t, init...); err != nil {
+		return nil, err
+	}
+	pc.addRTPTransceiver(t)
+
+	return t, nil
 }
 
 // CreateDataChannel creates a new DataChannel object with the given label
@@ -2368,6 +3131,7 @@ func (pc *PeerConnection) close(shouldGracefullyClose bool) error { //nolint:cyc
 
 	// https://www.w3.org/TR/webrtc/#dom-rtcpeerconnection-close (step #3)
 	pc.signalingState.Set(SignalingStateClosed)
+	pc.iceAutoRestart.close()
 
 	// https://www.w3.org/TR/webrtc/#dom-rtcpeerconnection-close (step #4)
 	pc.mu.Lock()
@@ -2408,6 +3172,18 @@ func (pc *PeerConnection) close(shouldGracefullyClose bool) error { //nolint:cyc
 	// Interceptor closes at the end to prevent Bind from being called after interceptor is closed
 	closeErrs = append(closeErrs, pc.api.interceptor.Close()) //nolint:makezero // todo fix
 
+	pc.handlersMu.Lock()
+	pc.handlersStopped = true
+	pc.handlersMu.Unlock()
+
+	// Callbacks may call Close themselves, wait for them without blocking
+	pc.mu.RLock()
+	onClosedHandler := pc.onClosedHandler
+	pc.mu.RUnlock()
+	if onClosedHandler != nil {
+		go pc.onClosed(onClosedHandler)
+	}
+
 	return util.FlattenErrs(closeErrs)
 }
 
@@ -2419,6 +3195,55 @@ func (pc *PeerConnection) addRTPTransceiver(t *RTPTransceiver) {
 	pc.onNegotiationNeeded()
 }
 
+// setSuggestedMid sets the mid suggested in init on t after checking it is
+// unique. The caller must hold pc.mu.
+func (pc *PeerConnection) setSuggestedMid(t *RTPTransceiver, init ...RTPTransceiverInit) error {
+	if len(init) != 1 || init[0].Mid == "" {
+		return nil
+	}
+
+	mid := init[0].Mid
+	if len(mid) > maxMidLength {
+		return fmt.Errorf("%w: %s", ErrMidTooLong, mid)
+	}
+	if pc.midInUse(mid, nil) {
+		return fmt.Errorf("%w: %s", ErrMidInUse, mid)
+	}
+	t.suggestedMid = mid
+
+	return nil
+}
+
+// midInUse returns true if a transceiver other than except has or suggested
+// the mid, or if a media section of the local or remote description has it.
+// The caller must hold pc.mu.
+func (pc *PeerConnection) midInUse(mid string, except *RTPTransceiver) bool {
+	for _, t := range pc.rtpTransceivers {
+		if t == except {
+			continue
+		}
+		if transceiverMid := t.Mid(); transceiverMid == mid || (transceiverMid == "" && t.suggestedMid == mid) {
+			return true
+		}
+	}
+
+	for _, desc := range []*SessionDescription{
+		pc.currentLocalDescription, pc.pendingLocalDescription,
+		pc.currentRemoteDescription, pc.pendingRemoteDescription,
+	} {
+		if desc == nil || desc.parsed == nil {
+			continue
+		}
+		for _, media := range desc.parsed.MediaDescriptions {
+			if getMidValue(media) == mid {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // CurrentLocalDescription represents the local description that was
 // successfully negotiated the last time the PeerConnection transitioned
 // into the stable state plus any local candidates that have been generated
@@ -2506,6 +3331,10 @@ func (pc *PeerConnection) ConnectionState() PeerConnectionState {
 }
 
 // GetStats return data providing statistics about the overall connection.
+// The remote-inbound-rtp stats of a RTPSender and the remote-outbound-rtp
+// stats of a RTPReceiver are derived from the RTCP the remote peer sends, so
+// they, and the feedback counts of the outbound-rtp stats, are only available
+// while RTCP is read from them.
 func (pc *PeerConnection) GetStats() StatsReport {
 	var (
 		dataChannelsAccepted  uint32
@@ -2520,8 +3349,8 @@ func (pc *PeerConnection) GetStats() StatsReport {
 	if pc.iceGatherer != nil {
 		pc.iceGatherer.collectStats(statsCollector)
 	}
-	if pc.iceTransport != nil {
-		pc.iceTransport.collectStats(statsCollector)
+	if pc.dtlsTransport != nil {
+		pc.dtlsTransport.collectStats(statsCollector)
 	}
 
 	pc.sctpTransport.lock.Lock()
@@ -2553,6 +3382,15 @@ func (pc *PeerConnection) GetStats() StatsReport {
 
 	statsCollector.Collect(stats.ID, stats)
 
+	for _, transceiver := range pc.rtpTransceivers {
+		if receiver := transceiver.Receiver(); receiver != nil {
+			receiver.collectStats(statsCollector)
+		}
+		if sender := transceiver.Sender(); sender != nil {
+			sender.collectStats(statsCollector)
+		}
+	}
+
 	certificates := pc.configuration.Certificates
 	for _, certificate := range certificates {
 		if err := certificate.collectStats(statsCollector); err != nil {
@@ -2619,17 +3457,23 @@ func (pc *PeerConnection) startRTP(
 	isRenegotiation bool,
 	remoteDesc *SessionDescription,
 	currentTransceivers []*RTPTransceiver,
+	unchanged map[string]struct{},
 ) {
 	if !isRenegotiation {
 		pc.undeclaredMediaProcessor()
 	}
 
-	pc.startRTPReceivers(remoteDesc, currentTransceivers)
+	pc.startRTPReceivers(remoteDesc, currentTransceivers, unchanged)
 	if haveApplicationMediaSection(remoteDesc.parsed) {
-		pc.startSCTP()
+		pc.startSCTP(getMaxMessageSize(remoteDesc.parsed))
 	}
 }
 
+// dataMediaSection returns the application media section with mid.
+func (pc *PeerConnection) dataMediaSection(mid string) mediaSection {
+	return mediaSection{id: mid, data: true, maxMessageSize: pc.sctpTransport.GetCapabilities().MaxMessageSize}
+}
+
 // generateUnmatchedSDP generates an SDP that doesn't take remote state into account
 // This is used for the initial call for CreateOffer.
 //
@@ -2643,6 +3487,9 @@ func (pc *PeerConnection) generateUnmatchedSDP(
 		return nil, err
 	}
 	desc.Attributes = append(desc.Attributes, sdp.Attribute{Key: sdp.AttrKeyMsidSemantic, Value: "WMS*"})
+	if err = pc.addIdentityAttribute(desc); err != nil {
+		return nil, err
+	}
 
 	iceParams, err := pc.iceGatherer.GetLocalParameters()
 	if err != nil {
@@ -2684,7 +3531,7 @@ func (pc *PeerConnection) generateUnmatchedSDP(
 		}
 
 		if pc.sctpTransport.dataChannelsRequested != 0 {
-			mediaSections = append(mediaSections, mediaSection{id: "data", data: true})
+			mediaSections = append(mediaSections, pc.dataMediaSection("data"))
 		}
 	} else {
 		for _, t := range transceivers {
@@ -2695,7 +3542,7 @@ func (pc *PeerConnection) generateUnmatchedSDP(
 		}
 
 		if pc.sctpTransport.dataChannelsRequested != 0 {
-			mediaSections = append(mediaSections, mediaSection{id: strconv.Itoa(len(mediaSections)), data: true})
+			mediaSections = append(mediaSections, pc.dataMediaSection(dataMediaSectionID(mediaSections)))
 		}
 	}
 
@@ -2704,7 +3551,7 @@ func (pc *PeerConnection) generateUnmatchedSDP(
 		return nil, err
 	}
 
-	return populateSDP(
+	desc, err = populateSDP(
 		desc,
 		isPlanB,
 		dtlsFingerprints,
@@ -2719,6 +3566,15 @@ func (pc *PeerConnection) generateUnmatchedSDP(
 		pc.ICEGatheringState(),
 		nil,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if pc.configuration.BundlePolicy == BundlePolicyMaxBundle {
+		setBundleOnly(desc)
+	}
+
+	return desc, nil
 }
 
 // generateMatchedSDP generates a SDP and takes the remote state into account
@@ -2735,6 +3591,9 @@ func (pc *PeerConnection) generateMatchedSDP(
 		return nil, err
 	}
 	desc.Attributes = append(desc.Attributes, sdp.Attribute{Key: sdp.AttrKeyMsidSemantic, Value: "WMS*"})
+	if err = pc.addIdentityAttribute(desc); err != nil {
+		return nil, err
+	}
 
 	iceParams, err := pc.iceGatherer.GetLocalParameters()
 	if err != nil {
@@ -2768,7 +3627,7 @@ func (pc *PeerConnection) generateMatchedSDP(
 		}
 
 		if media.MediaName.Media == mediaSectionApplication {
-			mediaSections = append(mediaSections, mediaSection{id: midValue, data: true})
+			mediaSections = append(mediaSections, pc.dataMediaSection(midValue))
 			alreadyHaveApplicationMediaSection = true
 
 			continue
@@ -2828,6 +3687,9 @@ func (pc *PeerConnection) generateMatchedSDP(
 			}
 			mediaTransceivers := []*RTPTransceiver{transceiver}
 
+			if err := pc.api.mediaEngine.negotiateHeaderExtensions(media); err != nil {
+				return nil, err
+			}
 			extensions, _ := rtpExtensionsFromMediaDescription(media)
 			mediaSections = append(
 				mediaSections,
@@ -2850,9 +3712,9 @@ func (pc *PeerConnection) generateMatchedSDP(
 
 		if pc.sctpTransport.dataChannelsRequested != 0 && !alreadyHaveApplicationMediaSection {
 			if detectedPlanB {
-				mediaSections = append(mediaSections, mediaSection{id: "data", data: true})
+				mediaSections = append(mediaSections, pc.dataMediaSection("data"))
 			} else {
-				mediaSections = append(mediaSections, mediaSection{id: strconv.Itoa(len(mediaSections)), data: true})
+				mediaSections = append(mediaSections, pc.dataMediaSection(dataMediaSectionID(mediaSections)))
 			}
 		}
 	} else if remoteDescription != nil {
@@ -9,6 +9,7 @@ package webrtc
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -327,3 +328,75 @@ func TestPeerConnection_GracefulCloseConcurrent(t *testing.T) {
 		})
 	}
 }
+
+func TestPeerConnection_OnClosed(t *testing.T) {
+	// Limit runtime in case of deadlocks
+	lim := test.TimeOut(time.Second * 20)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messageHandlerReturned atomicBool
+	messageHandlerStarted := make(chan struct{})
+	answerDataChannelOpened := make(chan struct{})
+	pcAnswer.OnDataChannel(func(d *DataChannel) {
+		if d.Label() != "data" {
+			return
+		}
+		d.OnMessage(func(DataChannelMessage) {
+			close(messageHandlerStarted)
+			time.Sleep(100 * time.Millisecond)
+			messageHandlerReturned.set(true)
+		})
+		close(answerDataChannelOpened)
+	})
+
+	var stateHandlerReturned atomicBool
+	pcAnswer.OnConnectionStateChange(func(s PeerConnectionState) {
+		if s == PeerConnectionStateClosed {
+			time.Sleep(100 * time.Millisecond)
+			stateHandlerReturned.set(true)
+		}
+	})
+
+	var closedCount int32
+	onClosedFired := make(chan struct{})
+	pcAnswer.OnClosed(func() {
+		assert.True(t, messageHandlerReturned.get())
+		assert.True(t, stateHandlerReturned.get())
+		if atomic.AddInt32(&closedCount, 1) == 1 {
+			close(onClosedFired)
+		}
+	})
+
+	dcOffer, err := pcOffer.CreateDataChannel("data", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offerDataChannelOpened := make(chan struct{})
+	dcOffer.OnOpen(func() {
+		close(offerDataChannelOpened)
+	})
+
+	if err = signalPair(pcOffer, pcAnswer); err != nil {
+		t.Fatal(err)
+	}
+
+	<-offerDataChannelOpened
+	<-answerDataChannelOpened
+	assert.NoError(t, dcOffer.Send([]byte("hello")))
+	<-messageHandlerStarted
+
+	assert.NoError(t, pcAnswer.Close())
+	assert.NoError(t, pcAnswer.GracefulClose())
+	assert.NoError(t, pcOffer.Close())
+
+	<-onClosedFired
+	assert.Equal(t, int32(1), atomic.LoadInt32(&closedCount))
+}
@@ -24,7 +24,9 @@ import (
 
 	"github.com/pion/dtls/v3"
 	"github.com/pion/ice/v4"
+	"github.com/pion/interceptor"
 	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
 	"github.com/pion/transport/v3/test"
 	"github.com/pion/transport/v3/vnet"
 	"github.com/pion/webrtc/v4/internal/util"
@@ -906,6 +908,55 @@ func TestICERestart(t *testing.T) {
 	closePairNow(t, offerPC, answerPC)
 }
 
+func TestICEAutoRestart(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetICETimeouts(time.Second, 2*time.Second, 200*time.Millisecond)
+	settingEngine.SetICEAutoRestart(100*time.Millisecond, time.Second)
+	// Keep the PeerConnection open when the remote closes DTLS
+	settingEngine.DisableCloseByDTLS(true)
+
+	offerPC, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	answerPC, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	_, err = offerPC.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, offerPC, answerPC)
+	assert.NoError(t, signalPair(offerPC, answerPC))
+	connected.Wait()
+
+	firstOffer := offerPC.LocalDescription()
+	_, err = firstOffer.Unmarshal()
+	assert.NoError(t, err)
+	firstICEDetails, err := extractICEDetails(firstOffer.parsed, offerPC.log)
+	assert.NoError(t, err)
+
+	negotiationNeeded := make(chan struct{})
+	var once sync.Once
+	offerPC.OnNegotiationNeeded(func() {
+		once.Do(func() { close(negotiationNeeded) })
+	})
+
+	// The remote goes away, the restart is requested once ICE is disconnected
+	assert.NoError(t, answerPC.Close())
+	<-negotiationNeeded
+
+	offer, err := offerPC.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, offer.SDP, "a=ice-ufrag:"+firstICEDetails.Ufrag)
+
+	assert.NoError(t, offerPC.Close())
+}
+
 // Assert error handling when an Agent is restart.
 func TestICERestart_Error_Handling(t *testing.T) {
 	iceStates := make(chan ICEConnectionState, 100)
@@ -1323,6 +1374,41 @@ func TestPeerConnection_TransceiverDirection(t *testing.T) {
 	}
 }
 
+func TestPeerConnection_UnchangedRemoteMediaSections(t *testing.T) {
+	parsed := &sdp.SessionDescription{}
+	assert.NoError(t, parsed.UnmarshalString("v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n"+
+		"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\na=sendrecv\r\na=ssrc:2 cname:v\r\n"))
+	remoteDesc := &SessionDescription{parsed: parsed}
+
+	peerConnection, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	transceiver, err := peerConnection.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	assert.NoError(t, transceiver.SetMid("0"))
+	transceivers := peerConnection.GetTransceivers()
+
+	assert.Empty(t, peerConnection.unchangedRemoteMediaSections(remoteDesc, transceivers))
+	assert.Equal(t, map[string]struct{}{"0": {}}, peerConnection.unchangedRemoteMediaSections(remoteDesc, transceivers))
+
+	// Local direction changes are applied even if the remote section didn't change
+	transceiver.setDirection(RTPTransceiverDirectionSendonly)
+	assert.Empty(t, peerConnection.unchangedRemoteMediaSections(remoteDesc, transceivers))
+	assert.Equal(t, map[string]struct{}{"0": {}}, peerConnection.unchangedRemoteMediaSections(remoteDesc, transceivers))
+
+	// So are track changes
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	assert.NoError(t, transceiver.Sender().ReplaceTrack(track))
+	assert.Empty(t, peerConnection.unchangedRemoteMediaSections(remoteDesc, transceivers))
+
+	// Everything is applied again after a rollback or ICE restart
+	peerConnection.resetAppliedRemoteDescription()
+	assert.Empty(t, peerConnection.unchangedRemoteMediaSections(remoteDesc, transceivers))
+
+	assert.NoError(t, peerConnection.Close())
+}
+
 func TestPeerConnection_SessionID(t *testing.T) {
 	defer test.TimeOut(time.Second * 10).Stop()
 	defer test.CheckRoutines(t)()
@@ -1463,10 +1549,10 @@ a=fmtp:125 level-asymmetry-allowed=1;packetization-mode=0;profile-level-id=42e01
 		codecOfTr1 := pc.GetTransceivers()[0].getCodecs()[0]
 		codecs := pc.api.mediaEngine.getCodecsByKind(RTPCodecTypeVideo)
 		_, matchType := codecParametersFuzzySearch(codecOfTr1, codecs)
-		assert.Equal(t, codecMatchExact, matchType)
+		assert.Equal(t, CodecMatchExact, matchType)
 		codecOfTr2 := pc.GetTransceivers()[1].getCodecs()[0]
 		_, matchType = codecParametersFuzzySearch(codecOfTr2, codecs)
-		assert.Equal(t, codecMatchExact, matchType)
+		assert.Equal(t, CodecMatchExact, matchType)
 		assert.EqualValues(t, 94, codecOfTr2.PayloadType)
 		assert.NoError(t, pc.Close())
 	})
@@ -1524,10 +1610,10 @@ a=sendonly
 		codecOfTr1 := pc.GetTransceivers()[0].getCodecs()[0]
 		codecs := pc.api.mediaEngine.getCodecsByKind(RTPCodecTypeVideo)
 		_, matchType := codecParametersFuzzySearch(codecOfTr1, codecs)
-		assert.Equal(t, codecMatchExact, matchType)
+		assert.Equal(t, CodecMatchExact, matchType)
 		codecOfTr2 := pc.GetTransceivers()[1].getCodecs()[0]
 		_, matchType = codecParametersFuzzySearch(codecOfTr2, codecs)
-		assert.Equal(t, codecMatchExact, matchType)
+		assert.Equal(t, CodecMatchExact, matchType)
 		// h.264/profile-id=640032 should be remap to 106 as same as transceiver 1
 		assert.EqualValues(t, 106, codecOfTr2.PayloadType)
 		assert.NoError(t, pc.Close())
@@ -1594,6 +1680,46 @@ a=ssrc:1455629982 cname:{61fd3093-0326-4b12-8258-86bdc1fe677a}
 	assert.NoError(t, peerConnection.Close())
 }
 
+func TestPeerConnection_Hold(t *testing.T) {
+	offerPC, answerPC, err := newPair()
+	assert.NoError(t, err)
+
+	video, err := offerPC.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	audio, err := offerPC.AddTransceiverFromKind(
+		RTPCodecTypeAudio, RTPTransceiverInit{Direction: RTPTransceiverDirectionRecvonly},
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, signalPair(offerPC, answerPC))
+
+	assert.NoError(t, offerPC.Resume(), "resuming while not on hold does nothing")
+	assert.NoError(t, offerPC.Hold())
+	assert.True(t, offerPC.IsOnHold())
+	assert.Equal(t, RTPTransceiverDirectionSendonly, video.Direction())
+	assert.Equal(t, RTPTransceiverDirectionInactive, audio.Direction())
+
+	offer, err := offerPC.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, offer.SDP, "a=sendonly")
+	assert.Contains(t, offer.SDP, "a=inactive")
+
+	assert.NoError(t, signalPair(offerPC, answerPC))
+	assert.Contains(t, offerPC.CurrentRemoteDescription().SDP, "a=recvonly")
+
+	assert.NoError(t, offerPC.Resume())
+	assert.False(t, offerPC.IsOnHold())
+	assert.Equal(t, RTPTransceiverDirectionSendrecv, video.Direction())
+	assert.Equal(t, RTPTransceiverDirectionRecvonly, audio.Direction())
+
+	offer, err = offerPC.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, offer.SDP, "a=sendrecv")
+	assert.NotContains(t, offer.SDP, "a=inactive")
+
+	closePairNow(t, offerPC, answerPC)
+	assert.Error(t, offerPC.Hold())
+}
+
 func TestPeerConnectionState(t *testing.T) {
 	pc, err := NewPeerConnection(Configuration{})
 	assert.NoError(t, err)
@@ -1878,3 +2004,146 @@ func TestTranceiverMediaStreamIdentification(t *testing.T) {
 	assert.NoError(t, pcOfferer.Close())
 	assert.NoError(t, pcAnswerer.Close())
 }
+
+func TestPeerConnection_FeatureFlags(t *testing.T) {
+	settingEngine := SettingEngine{}
+	settingEngine.SetFeatureFlags(FeatureFlags{"a": FeatureFlagEnabled, "b": FeatureFlagEnabled})
+
+	pc, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{
+		FeatureFlags: FeatureFlags{"b": FeatureFlagDisabled},
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, pc.FeatureFlags().IsEnabled("a"))
+	assert.False(t, pc.FeatureFlags().IsEnabled("b"))
+
+	assert.NoError(t, pc.SetConfiguration(Configuration{
+		FeatureFlags: FeatureFlags{"c": FeatureFlagEnabled},
+	}))
+	assert.True(t, pc.FeatureFlags().IsEnabled("b"))
+	assert.True(t, pc.FeatureFlags().IsEnabled("c"))
+	assert.Equal(t, FeatureFlags{"c": FeatureFlagEnabled}, pc.GetConfiguration().FeatureFlags)
+
+	assert.NoError(t, pc.Close())
+}
+
+func TestPeerConnection_FeatureFlagL4S(t *testing.T) {
+	hasCCFB := func(pc *PeerConnection) bool {
+		_, err := pc.AddTransceiverFromKind(RTPCodecTypeVideo)
+		assert.NoError(t, err)
+
+		offer, err := pc.CreateOffer(nil)
+		assert.NoError(t, err)
+		assert.NoError(t, pc.Close())
+
+		return strings.Contains(offer.SDP, "a=rtcp-fb:96 ack ccfb")
+	}
+
+	pc, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.False(t, hasCCFB(pc))
+
+	pc, err = NewPeerConnection(Configuration{FeatureFlags: FeatureFlags{FeatureFlagL4S: FeatureFlagEnabled}})
+	assert.NoError(t, err)
+	assert.True(t, hasCCFB(pc))
+
+	// Feedback configured by the user isn't registered twice
+	mediaEngine := &MediaEngine{}
+	assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	interceptorRegistry := &interceptor.Registry{}
+	assert.NoError(t, ConfigureCongestionControlFeedback(mediaEngine, interceptorRegistry))
+
+	pc, err = NewAPI(WithMediaEngine(mediaEngine), WithInterceptorRegistry(interceptorRegistry)).NewPeerConnection(
+		Configuration{FeatureFlags: FeatureFlags{FeatureFlagL4S: FeatureFlagEnabled}},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, pc.api.mediaEngine.videoCodecs[0].RTCPFeedback, len(mediaEngine.videoCodecs[0].RTCPFeedback))
+	assert.NoError(t, pc.Close())
+}
+
+func TestPeerConnection_BundleOnly(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	offerPC, err := NewPeerConnection(Configuration{BundlePolicy: BundlePolicyMaxBundle})
+	assert.NoError(t, err)
+
+	answerPC, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	_, err = offerPC.AddTransceiverFromKind(RTPCodecTypeAudio)
+	assert.NoError(t, err)
+	_, err = offerPC.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, offerPC, answerPC)
+	assert.NoError(t, signalPair(offerPC, answerPC))
+	connected.Wait()
+
+	// The video and application sections are bundle-only, only the audio one has a port
+	offer := offerPC.LocalDescription().SDP
+	assert.Equal(t, 2, strings.Count(offer, "a="+sdpAttributeBundleOnly))
+	assert.Contains(t, offer, "m=audio 9 ")
+	assert.Contains(t, offer, "m=video 0 ")
+	assert.Contains(t, offer, "m=application 0 ")
+
+	answer := answerPC.LocalDescription().SDP
+	assert.NotContains(t, answer, "a="+sdpAttributeBundleOnly)
+	assert.Contains(t, answer, "m=video 9 ")
+	assert.Len(t, answerPC.GetTransceivers(), 2)
+
+	closePairNow(t, offerPC, answerPC)
+}
+
+func TestPeerConnection_Rollback(t *testing.T) {
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	rollback := SessionDescription{Type: SDPTypeRollback}
+	assert.Error(t, pcOffer.SetLocalDescription(rollback))
+	assert.Error(t, pcOffer.SetRemoteDescription(rollback))
+
+	transceiver, err := pcOffer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+
+	offer, err := pcOffer.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, pcOffer.SetLocalDescription(offer))
+	assert.Equal(t, SignalingStateHaveLocalOffer, pcOffer.SignalingState())
+	assert.NotEmpty(t, transceiver.Mid())
+
+	// The local offer is discarded and the mid it assigned released
+	assert.Error(t, pcOffer.SetRemoteDescription(rollback))
+	assert.NoError(t, pcOffer.SetLocalDescription(rollback))
+	assert.Equal(t, SignalingStateStable, pcOffer.SignalingState())
+	assert.Nil(t, pcOffer.PendingLocalDescription())
+	assert.Empty(t, transceiver.Mid())
+
+	// The remote offer is discarded, and the transceiver it created removed
+	assert.NoError(t, pcAnswer.SetRemoteDescription(offer))
+	assert.Equal(t, SignalingStateHaveRemoteOffer, pcAnswer.SignalingState())
+	assert.Len(t, pcAnswer.GetTransceivers(), 1)
+	assert.NoError(t, pcAnswer.SetRemoteDescription(rollback))
+	assert.Equal(t, SignalingStateStable, pcAnswer.SignalingState())
+	assert.Nil(t, pcAnswer.PendingRemoteDescription())
+	assert.Empty(t, pcAnswer.GetTransceivers())
+
+	// A transceiver the remote offer was matched to is kept, without its mid
+	answerTransceiver, err := pcAnswer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	assert.NoError(t, pcAnswer.SetRemoteDescription(offer))
+	assert.NotEmpty(t, answerTransceiver.Mid())
+	assert.NoError(t, pcAnswer.SetRemoteDescription(rollback))
+	assert.Equal(t, []*RTPTransceiver{answerTransceiver}, pcAnswer.GetTransceivers())
+	assert.Empty(t, answerTransceiver.Mid())
+
+	// Negotiation works after the rollbacks
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	assert.NotEmpty(t, transceiver.Mid())
+	assert.Equal(t, transceiver.Mid(), answerTransceiver.Mid())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
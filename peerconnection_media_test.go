@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pion/interceptor"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
@@ -532,6 +533,61 @@ func TestUndeclaredSSRC(t *testing.T) {
 	})
 }
 
+func TestHandleUndeclaredRTX(t *testing.T) {
+	pc, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	media := &sdp.MediaDescription{
+		MediaName: sdp.MediaName{Media: "video"},
+		Attributes: []sdp.Attribute{
+			{Key: "rtpmap", Value: "96 VP8/90000"},
+			{Key: "rtpmap", Value: "97 rtx/90000"},
+		},
+	}
+
+	marshal := func(payloadType uint8) []byte {
+		packet, err := (&rtp.Packet{
+			Header:  rtp.Header{Version: 2, PayloadType: payloadType, SSRC: 5000},
+			Payload: []byte{0x00, 0x01, 0x02},
+		}).Marshal()
+		assert.NoError(t, err)
+
+		return packet
+	}
+
+	// Media packets aren't consumed, they start the new track
+	packet := marshal(96)
+	handled, firstPacket, err := pc.handleUndeclaredRTX(bytes.NewReader(packet), 5000, media, RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	assert.False(t, handled)
+	assert.Equal(t, packet, firstPacket)
+
+	// RTX isn't paired without a track of the codec it repairs
+	packet = marshal(97)
+	handled, firstPacket, err = pc.handleUndeclaredRTX(bytes.NewReader(packet), 5000, media, RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	assert.False(t, handled)
+	assert.Equal(t, packet, firstPacket)
+
+	assert.NoError(t, pc.Close())
+}
+
+func TestBufferedRTPReader(t *testing.T) {
+	reader := &bufferedRTPReader{
+		packet: []byte{0x01},
+		reader: interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			return copy(b, []byte{0x02}), a, nil
+		}),
+	}
+
+	b := make([]byte, 1)
+	for _, expected := range []byte{0x01, 0x02, 0x02} {
+		n, _, err := reader.Read(b, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{expected}, b[:n])
+	}
+}
+
 func TestAddTransceiverFromTrackSendOnly(t *testing.T) {
 	lim := test.TimeOut(time.Second * 30)
 	defer lim.Stop()
@@ -1207,6 +1263,41 @@ func TestPeerConnection_CreateOffer_NoCodecs(t *testing.T) {
 	assert.NoError(t, pc.Close())
 }
 
+func TestPeerConnection_OnTrackAnnounced(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	_, err = pcOffer.AddTrack(track)
+	assert.NoError(t, err)
+
+	announced := make(chan *RTPTransceiver, 1)
+	pcAnswer.OnTrackAnnounced(func(transceiver *RTPTransceiver) {
+		announced <- transceiver
+	})
+	pcAnswer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+		assert.Fail(t, "OnTrack fired without RTP")
+	})
+
+	// No samples are written, the track is only known from the description
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	transceiver := <-announced
+	assert.Equal(t, RTPCodecTypeVideo, transceiver.Kind())
+	assert.Equal(t, "video", transceiver.Receiver().Track().ID())
+	assert.Equal(t, "pion", transceiver.Receiver().Track().StreamID())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
 // Assert that AddTrack is thread-safe.
 func TestPeerConnection_RaceReplaceTrack(t *testing.T) {
 	pc, err := NewPeerConnection(Configuration{})
@@ -1931,3 +2022,42 @@ func Test_PeerConnection_RTX_E2E(t *testing.T) { //nolint:cyclop
 	assert.NoError(t, wan.Stop())
 	closePairNow(t, pcOffer, pcAnswer)
 }
+
+func TestPeerConnection_OnTrackEvent(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion_stream")
+	assert.NoError(t, err)
+
+	_, err = pcOffer.AddTrack(track)
+	assert.NoError(t, err)
+
+	onTrackFired, onTrackFiredCancel := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(*TrackRemote, *RTPReceiver) {})
+
+	trackEventChan := make(chan TrackEvent, 1)
+	pcAnswer.OnTrackEvent(func(event TrackEvent) {
+		trackEventChan <- event
+		onTrackFiredCancel()
+	})
+
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+	event := <-trackEventChan
+	assert.Equal(t, "video", event.Track.ID())
+	assert.Equal(t, []string{"pion_stream"}, event.StreamIDs)
+	assert.Equal(t, []string{"pion_stream"}, event.Track.StreamIDs())
+	assert.NotNil(t, event.Transceiver)
+	assert.Equal(t, event.Receiver, event.Transceiver.Receiver())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
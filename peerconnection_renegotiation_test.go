@@ -1296,6 +1296,65 @@ func TestPeerConnection_Regegotiation_ReuseTransceiver(t *testing.T) {
 	closePairNow(t, pcOffer, pcAnswer)
 }
 
+func TestPeerConnection_Transceiver_SuggestedMid(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeVideo, RTPTransceiverInit{
+		Direction: RTPTransceiverDirectionSendrecv,
+		Mid:       "room1-video",
+	})
+	assert.NoError(t, err)
+
+	_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeAudio, RTPTransceiverInit{
+		Direction: RTPTransceiverDirectionRecvonly,
+		Mid:       "room1-video",
+	})
+	assert.ErrorIs(t, err, ErrMidInUse)
+
+	_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeAudio, RTPTransceiverInit{
+		Direction: RTPTransceiverDirectionRecvonly,
+		Mid:       "a-mid-longer-than-16-bytes",
+	})
+	assert.ErrorIs(t, err, ErrMidTooLong)
+
+	// A numeric suggestion moves the mids assigned to other transceivers
+	_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeAudio, RTPTransceiverInit{
+		Direction: RTPTransceiverDirectionRecvonly,
+		Mid:       "1",
+	})
+	assert.NoError(t, err)
+
+	unsuggested, err := pcOffer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+
+	_, err = pcOffer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	offer, err := pcOffer.CreateOffer(nil)
+	assert.NoError(t, err)
+
+	mids := []string{}
+	for _, media := range offer.parsed.MediaDescriptions {
+		mids = append(mids, getMidValue(media))
+	}
+	assert.Equal(t, []string{"room1-video", "1", "2", "3"}, mids)
+	assert.Equal(t, "2", unsuggested.Mid())
+
+	// The answerer uses the mids of the offer
+	assert.NoError(t, pcOffer.SetLocalDescription(offer))
+	assert.NoError(t, pcAnswer.SetRemoteDescription(offer))
+	assert.Equal(t, "room1-video", pcAnswer.GetTransceivers()[0].Mid())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
 func TestPeerConnection_Renegotiation_MidConflict(t *testing.T) {
 	lim := test.TimeOut(time.Second * 30)
 	defer lim.Stop()
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/interceptor"
+)
+
+// histogramBounds are the upper bounds of the buckets of a Histogram, from
+// 10µs to 1s. Slower observations are only counted in the total.
+var histogramBounds = []time.Duration{ //nolint:gochecknoglobals
+	10 * time.Microsecond,
+	25 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// Histogram counts durations in fixed buckets. It is safe for concurrent
+// use and cheap enough to be updated for every packet.
+type Histogram struct {
+	buckets [16]uint64 // len(histogramBounds)
+	count   uint64
+	sum     int64
+}
+
+// HistogramSnapshot is the state of a Histogram at a point in time.
+type HistogramSnapshot struct {
+	// Bounds are the upper bounds of the buckets.
+	Bounds []time.Duration
+	// Counts are the cumulative number of observations of each bucket, the
+	// observations that took at most the bound of the bucket.
+	Counts []uint64
+	// Count is the number of observations.
+	Count uint64
+	// Sum is the total of the observed durations.
+	Sum time.Duration
+}
+
+// observe adds a duration to the histogram, it does nothing if h is nil.
+func (h *Histogram) observe(duration time.Duration) {
+	if h == nil {
+		return
+	}
+
+	for i, bound := range histogramBounds {
+		if duration <= bound {
+			atomic.AddUint64(&h.buckets[i], 1)
+
+			break
+		}
+	}
+	atomic.AddInt64(&h.sum, int64(duration))
+	atomic.AddUint64(&h.count, 1)
+}
+
+// observeSince adds the time elapsed since start to the histogram.
+func (h *Histogram) observeSince(start time.Time) {
+	if h != nil {
+		h.observe(time.Since(start))
+	}
+}
+
+// Snapshot returns the current counts of the histogram. The counts of the
+// buckets are cumulative, as in the Prometheus exposition format.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Bounds: append([]time.Duration{}, histogramBounds...),
+		Counts: make([]uint64, len(histogramBounds)),
+	}
+	if h == nil {
+		return snapshot
+	}
+
+	total := uint64(0)
+	for i := range histogramBounds {
+		total += atomic.LoadUint64(&h.buckets[i])
+		snapshot.Counts[i] = total
+	}
+	snapshot.Count = atomic.LoadUint64(&h.count)
+	snapshot.Sum = time.Duration(atomic.LoadInt64(&h.sum))
+
+	return snapshot
+}
+
+// PerformanceMetrics are histograms of the time spent in the hot paths of
+// the PeerConnections of an API, so performance regressions are measurable.
+// Pass them to SettingEngine.SetPerformanceMetrics, pkg/metrics exports them.
+type PerformanceMetrics struct {
+	// RTPReadLatency is the time from the arrival of a RTP packet on the
+	// ICE transport until TrackRemote.Read returns it: the SRTP decryption,
+	// the interceptors and the time it waited to be read.
+	RTPReadLatency *Histogram
+	// InterceptorWriteDuration is the time a RTP packet written to a
+	// RTPSender takes to go through the interceptors, including SRTPWriteDuration.
+	InterceptorWriteDuration *Histogram
+	// SRTPWriteDuration is the time the SRTP encryption and send of a RTP
+	// packet takes.
+	SRTPWriteDuration *Histogram
+}
+
+// NewPerformanceMetrics creates PerformanceMetrics with empty histograms.
+func NewPerformanceMetrics() *PerformanceMetrics {
+	return &PerformanceMetrics{
+		RTPReadLatency:           &Histogram{},
+		InterceptorWriteDuration: &Histogram{},
+		SRTPWriteDuration:        &Histogram{},
+	}
+}
+
+// observeReadLatency adds the latency of a RTP packet read with attributes
+// if its arrival time is known.
+func (m *PerformanceMetrics) observeReadLatency(attributes interceptor.Attributes) {
+	if m == nil || attributes == nil {
+		return
+	}
+
+	if arrival, ok := attributes.Get(AttributeArrivalTime).(time.Time); ok {
+		m.RTPReadLatency.observeSince(arrival)
+	}
+}
+
+func (m *PerformanceMetrics) interceptorWriteDuration() *Histogram {
+	if m == nil {
+		return nil
+	}
+
+	return m.InterceptorWriteDuration
+}
+
+func (m *PerformanceMetrics) srtpWriteDuration() *Histogram {
+	if m == nil {
+		return nil
+	}
+
+	return m.SRTPWriteDuration
+}
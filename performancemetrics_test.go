@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	histogram := &Histogram{}
+	histogram.observe(5 * time.Microsecond)
+	histogram.observe(2 * time.Millisecond)
+	histogram.observe(2 * time.Millisecond)
+	histogram.observe(2 * time.Second)
+
+	snapshot := histogram.Snapshot()
+	assert.Equal(t, uint64(4), snapshot.Count)
+	assert.Equal(t, 4*time.Millisecond+2*time.Second+5*time.Microsecond, snapshot.Sum)
+	assert.Len(t, snapshot.Counts, len(snapshot.Bounds))
+
+	for i, bound := range snapshot.Bounds {
+		switch {
+		case bound < 2*time.Millisecond:
+			assert.Equal(t, uint64(1), snapshot.Counts[i], bound)
+		default:
+			// The 2s observation is beyond the last bucket
+			assert.Equal(t, uint64(3), snapshot.Counts[i], bound)
+		}
+	}
+
+	// A nil histogram is disabled
+	var disabled *Histogram
+	disabled.observe(time.Millisecond)
+	assert.Equal(t, uint64(0), disabled.Snapshot().Count)
+}
+
+func TestPerformanceMetrics_ReadLatency(t *testing.T) {
+	metrics := NewPerformanceMetrics()
+	metrics.observeReadLatency(nil)
+	metrics.observeReadLatency(interceptor.Attributes{})
+	assert.Equal(t, uint64(0), metrics.RTPReadLatency.Snapshot().Count)
+
+	attributes := interceptor.Attributes{}
+	attributes.Set(AttributeArrivalTime, time.Now())
+	metrics.observeReadLatency(attributes)
+	assert.Equal(t, uint64(1), metrics.RTPReadLatency.Snapshot().Count)
+
+	var disabled *PerformanceMetrics
+	disabled.observeReadLatency(attributes)
+	assert.Nil(t, disabled.interceptorWriteDuration())
+	assert.Nil(t, disabled.srtpWriteDuration())
+}
@@ -0,0 +1,350 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build e2e
+// +build e2e
+
+// Package interoptest runs Pion against headless browsers through WebDriver.
+// The browser loads a page with an RTCPeerConnection that is remote controlled
+// by the Harness, tests negotiate it with a Pion PeerConnection and assert on
+// the stats the browser reports for the received media.
+//
+// A chromedriver or geckodriver binary must be available in PATH. Like the
+// e2e tests, the package is only built with the e2e build tag, so the
+// WebDriver client isn't a dependency of regular builds.
+package interoptest
+
+import (
+	"context"
+	_ "embed" // harness.html
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/sclevine/agouti"
+)
+
+// Browser is a browser the Harness can drive.
+type Browser string
+
+const (
+	// BrowserChrome drives headless Chrome through chromedriver.
+	BrowserChrome Browser = "chrome"
+	// BrowserFirefox drives headless Firefox through geckodriver.
+	BrowserFirefox Browser = "firefox"
+)
+
+// BrowsersEnv is the environment variable Browsers reads, a comma separated
+// list like "chrome,firefox".
+const BrowsersEnv = "PION_INTEROPTEST_BROWSERS"
+
+const pollInterval = 100 * time.Millisecond
+
+var (
+	errUnknownBrowser = errors.New("interoptest: unknown browser")
+	errBrowser        = errors.New("interoptest: browser reported an error")
+)
+
+//go:embed harness.html
+var harnessPage []byte
+
+// Browsers returns the browsers listed in BrowsersEnv, Chrome if it is unset.
+func Browsers() []Browser {
+	value := os.Getenv(BrowsersEnv)
+	if value == "" {
+		return []Browser{BrowserChrome}
+	}
+
+	browsers := []Browser{}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			browsers = append(browsers, Browser(strings.ToLower(name)))
+		}
+	}
+
+	return browsers
+}
+
+func newDriver(browser Browser) (*agouti.WebDriver, error) {
+	switch browser {
+	case BrowserChrome:
+		return agouti.ChromeDriver(
+			agouti.ChromeOptions("args", []string{
+				"--headless",
+				"--disable-gpu",
+				"--no-sandbox",
+				"--autoplay-policy=no-user-gesture-required",
+			}),
+		), nil
+	case BrowserFirefox:
+		return agouti.GeckoDriver(
+			agouti.Desired(agouti.Capabilities{
+				"moz:firefoxOptions": map[string]interface{}{
+					"args": []string{"-headless"},
+					"prefs": map[string]interface{}{
+						"media.autoplay.default": 0,
+					},
+				},
+			}),
+		), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownBrowser, browser)
+	}
+}
+
+// Harness controls an RTCPeerConnection in a browser.
+type Harness struct {
+	browser Browser
+	driver  *agouti.WebDriver
+	page    *agouti.Page
+	server  *httptest.Server
+}
+
+// New starts the browser and loads the harness page.
+func New(browser Browser) (*Harness, error) {
+	driver, err := newDriver(browser)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = driver.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start WebDriver for %s: %w", browser, err)
+	}
+
+	harness := &Harness{
+		browser: browser,
+		driver:  driver,
+		server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write(harnessPage)
+		})),
+	}
+
+	if harness.page, err = driver.NewPage(); err != nil {
+		_ = harness.Close()
+
+		return nil, err
+	}
+
+	if err = harness.page.Navigate(harness.server.URL); err != nil {
+		_ = harness.Close()
+
+		return nil, err
+	}
+
+	return harness, nil
+}
+
+// Browser returns the browser the Harness is driving.
+func (h *Harness) Browser() Browser {
+	return h.browser
+}
+
+// OfferOptions selects what the browser offers.
+type OfferOptions struct {
+	// Audio and Video add a recvonly transceiver of the kind.
+	Audio, Video bool
+
+	// DataChannel adds a DataChannel that echoes messages back upper cased.
+	DataChannel bool
+}
+
+// Offer makes the browser create an offer and returns it once ICE gathering
+// has completed.
+func (h *Harness) Offer(ctx context.Context, options OfferOptions) (webrtc.SessionDescription, error) {
+	var offer webrtc.SessionDescription
+	if err := h.page.RunScript(
+		"createOffer(audio, video, dataChannel)",
+		map[string]interface{}{"audio": options.Audio, "video": options.Video, "dataChannel": options.DataChannel},
+		nil,
+	); err != nil {
+		return offer, err
+	}
+
+	err := h.result(ctx, "offer", &offer)
+
+	return offer, err
+}
+
+// SetAnswer applies the answer of Pion in the browser.
+func (h *Harness) SetAnswer(answer webrtc.SessionDescription) error {
+	answerJSON, err := json.Marshal(answer)
+	if err != nil {
+		return err
+	}
+
+	return h.page.RunScript(
+		"pc.setRemoteDescription(JSON.parse(answer)).catch(e => setResult('error', e.toString()))",
+		map[string]interface{}{"answer": string(answerJSON)},
+		nil,
+	)
+}
+
+// Negotiate exchanges an offer of the browser with an answer of pc.
+func (h *Harness) Negotiate(ctx context.Context, pc *webrtc.PeerConnection, options OfferOptions) error {
+	offer, err := h.Offer(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	if err = pc.SetRemoteDescription(offer); err != nil {
+		return err
+	}
+
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return err
+	}
+
+	gatheringComplete := webrtc.GatheringCompletePromise(pc)
+	if err = pc.SetLocalDescription(answer); err != nil {
+		return err
+	}
+
+	select {
+	case <-gatheringComplete:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return h.SetAnswer(*pc.LocalDescription())
+}
+
+// WaitConnectionState waits until the connectionState of the browser is state.
+func (h *Harness) WaitConnectionState(ctx context.Context, state webrtc.PeerConnectionState) error {
+	return h.poll(ctx, func() (bool, error) {
+		var current string
+		if err := h.page.RunScript("return pc.connectionState", nil, &current); err != nil {
+			return false, err
+		}
+
+		return current == state.String(), nil
+	})
+}
+
+// InboundRTPStats are the fields of an inbound-rtp stats object of the browser
+// the harness understands.
+type InboundRTPStats struct {
+	Kind            string `json:"kind"`
+	SSRC            uint32 `json:"ssrc"`
+	PacketsReceived uint32 `json:"packetsReceived"`
+	PacketsLost     int32  `json:"packetsLost"`
+	BytesReceived   uint64 `json:"bytesReceived"`
+	FramesReceived  uint32 `json:"framesReceived"`
+	FramesDecoded   uint32 `json:"framesDecoded"`
+	FrameWidth      uint32 `json:"frameWidth"`
+	FrameHeight     uint32 `json:"frameHeight"`
+	NACKCount       uint32 `json:"nackCount"`
+	PLICount        uint32 `json:"pliCount"`
+}
+
+// InboundRTPStats returns the inbound-rtp stats of the browser.
+func (h *Harness) InboundRTPStats(ctx context.Context) ([]InboundRTPStats, error) {
+	if err := h.page.RunScript("collectStats()", nil, nil); err != nil {
+		return nil, err
+	}
+
+	var stats []struct {
+		Type string `json:"type"`
+		InboundRTPStats
+	}
+	if err := h.result(ctx, "stats", &stats); err != nil {
+		return nil, err
+	}
+
+	inbound := []InboundRTPStats{}
+	for _, s := range stats {
+		if s.Type == "inbound-rtp" {
+			inbound = append(inbound, s.InboundRTPStats)
+		}
+	}
+
+	return inbound, nil
+}
+
+// WaitInboundRTP polls the inbound-rtp stats of the browser until done returns
+// true for a stream of the kind.
+func (h *Harness) WaitInboundRTP(
+	ctx context.Context,
+	kind webrtc.RTPCodecType,
+	done func(InboundRTPStats) bool,
+) (InboundRTPStats, error) {
+	var match InboundRTPStats
+	err := h.poll(ctx, func() (bool, error) {
+		stats, err := h.InboundRTPStats(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		for _, s := range stats {
+			if s.Kind == kind.String() && done(s) {
+				match = s
+
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+
+	return match, err
+}
+
+// Close closes the browser.
+func (h *Harness) Close() error {
+	h.server.Close()
+
+	return h.driver.Stop()
+}
+
+// result waits until the page has published the result key and decodes it into value.
+func (h *Harness) result(ctx context.Context, key string, value interface{}) error {
+	return h.poll(ctx, func() (bool, error) {
+		var published struct {
+			Value string `json:"value"`
+			Error string `json:"error"`
+		}
+		if err := h.page.RunScript(
+			`const published = {value: harnessResults[key] || "", error: harnessResults.error || ""}
+			delete harnessResults[key]
+			delete harnessResults.error
+			return published`,
+			map[string]interface{}{"key": key},
+			&published,
+		); err != nil {
+			return false, err
+		}
+
+		switch {
+		case published.Error != "":
+			return false, fmt.Errorf("%w: %s", errBrowser, published.Error)
+		case published.Value == "":
+			return false, nil
+		default:
+			return true, json.Unmarshal([]byte(published.Value), value)
+		}
+	})
+}
+
+func (h *Harness) poll(ctx context.Context, condition func() (bool, error)) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if done, err := condition(); done || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
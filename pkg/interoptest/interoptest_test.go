@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build e2e
+// +build e2e
+
+package interoptest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+var silentOpusFrame = []byte{0xf8, 0xff, 0xfe} // 20ms, 8kHz, mono
+
+func TestInterop_AudioAndDataChannel(t *testing.T) {
+	for _, browser := range Browsers() {
+		t.Run(string(browser), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			harness, err := New(browser)
+			if err != nil {
+				t.Fatalf("Failed to start %s: %v", browser, err)
+			}
+			defer func() {
+				assert.NoError(t, harness.Close())
+			}()
+
+			pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+			assert.NoError(t, err)
+			defer func() {
+				assert.NoError(t, pc.Close())
+			}()
+
+			track, err := webrtc.NewTrackLocalStaticSample(
+				webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus}, "audio", "pion",
+			)
+			assert.NoError(t, err)
+			_, err = pc.AddTrack(track)
+			assert.NoError(t, err)
+
+			echoed := make(chan string, 1)
+			pc.OnDataChannel(func(dataChannel *webrtc.DataChannel) {
+				dataChannel.OnOpen(func() {
+					assert.NoError(t, dataChannel.SendText("hello world"))
+				})
+				dataChannel.OnMessage(func(msg webrtc.DataChannelMessage) {
+					echoed <- string(msg.Data)
+				})
+			})
+
+			assert.NoError(t, harness.Negotiate(ctx, pc, OfferOptions{Audio: true, DataChannel: true}))
+			assert.NoError(t, harness.WaitConnectionState(ctx, webrtc.PeerConnectionStateConnected))
+
+			go func() {
+				ticker := time.NewTicker(20 * time.Millisecond)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if err := track.WriteSample(media.Sample{Data: silentOpusFrame, Duration: 20 * time.Millisecond}); err != nil {
+							return
+						}
+					}
+				}
+			}()
+
+			stats, err := harness.WaitInboundRTP(ctx, webrtc.RTPCodecTypeAudio, func(s InboundRTPStats) bool {
+				return s.PacketsReceived >= 50
+			})
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, stats.PacketsReceived, uint32(50))
+
+			select {
+			case msg := <-echoed:
+				assert.Equal(t, "HELLO WORLD", msg)
+			case <-ctx.Done():
+				t.Fatal("Timeout waiting for DataChannel echo")
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package jitterbuffer provides an adaptive playout delay jitter buffer for
+// RTP packets. Unlike a decoder's jitter buffer it only reorders packets and
+// releases them at their playout time, which suits recorders and forwarders
+// that want a steady, ordered stream without decoding it.
+package jitterbuffer
+
+import (
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	defaultMinDelay         = 20 * time.Millisecond
+	defaultMaxDelay         = 500 * time.Millisecond
+	defaultJitterMultiplier = 4
+
+	// jitterGain is the gain of the interarrival jitter estimate of RFC 3550.
+	jitterGain = 16
+	// delayDecay is how many packets it takes to halve the gap between the
+	// delay and a lower target, the delay grows at once but shrinks slowly.
+	delayDecay = 64
+)
+
+// Stats describes the state of a JitterBuffer.
+type Stats struct {
+	// Delay is the current playout delay added to the earliest arrival.
+	Delay time.Duration
+	// Jitter is the interarrival jitter estimate, as defined in RFC 3550.
+	Jitter time.Duration
+	// PacketsPlayed is the number of packets returned by Pop.
+	PacketsPlayed uint64
+	// PacketsLate is the number of packets that arrived after their playout
+	// time had passed, they are dropped.
+	PacketsLate uint64
+	// PacketsLost is the number of packets skipped because they hadn't
+	// arrived at their playout time.
+	PacketsLost uint64
+	// PacketsDuplicate is the number of packets dropped because they were
+	// already buffered.
+	PacketsDuplicate uint64
+}
+
+// JitterBuffer reorders RTP packets of a single SSRC and releases them at
+// their playout time. The playout time of a packet is the time it would have
+// arrived without queuing, derived from its RTP timestamp and the earliest
+// arrival seen, plus a delay that adapts to the jitter. A JitterBuffer is not
+// safe for concurrent use.
+type JitterBuffer struct {
+	clockRate        uint32
+	minDelay         time.Duration
+	maxDelay         time.Duration
+	jitterMultiplier float64
+
+	packets map[uint16]*rtp.Packet
+	started bool
+	nextSeq uint16
+
+	// The earliest arrival, in the RTP timestamp of baseTimestamp
+	baseArrival   time.Time
+	baseTimestamp uint32
+
+	lastTransit time.Duration
+	jitter      float64
+	delay       time.Duration
+
+	stats Stats
+}
+
+// An Option configures a JitterBuffer.
+type Option func(j *JitterBuffer)
+
+// WithMinDelay sets the lowest the adaptive playout delay gets, it defaults to 20ms.
+func WithMinDelay(minDelay time.Duration) Option {
+	return func(j *JitterBuffer) {
+		j.minDelay = minDelay
+	}
+}
+
+// WithMaxDelay sets the highest the adaptive playout delay gets, it defaults to 500ms.
+func WithMaxDelay(maxDelay time.Duration) Option {
+	return func(j *JitterBuffer) {
+		j.maxDelay = maxDelay
+	}
+}
+
+// WithJitterMultiplier sets how many times the jitter estimate the target
+// delay is, it defaults to 4.
+func WithJitterMultiplier(multiplier float64) Option {
+	return func(j *JitterBuffer) {
+		j.jitterMultiplier = multiplier
+	}
+}
+
+// New creates a JitterBuffer for a stream with the clockRate.
+func New(clockRate uint32, opts ...Option) *JitterBuffer {
+	j := &JitterBuffer{
+		clockRate:        clockRate,
+		minDelay:         defaultMinDelay,
+		maxDelay:         defaultMaxDelay,
+		jitterMultiplier: defaultJitterMultiplier,
+		packets:          map[uint16]*rtp.Packet{},
+	}
+	for _, o := range opts {
+		o(j)
+	}
+	if j.maxDelay < j.minDelay {
+		j.maxDelay = j.minDelay
+	}
+	j.delay = j.minDelay
+
+	return j
+}
+
+// mediaTime is the duration of the RTP timestamps from the base timestamp.
+func (j *JitterBuffer) mediaTime(timestamp uint32) time.Duration {
+	if j.clockRate == 0 {
+		return 0
+	}
+
+	ticks := int64(int32(timestamp - j.baseTimestamp)) //nolint:gosec // G115, the difference wraps
+
+	return time.Duration(ticks * int64(time.Second) / int64(j.clockRate))
+}
+
+func (j *JitterBuffer) clampDelay(delay time.Duration) time.Duration {
+	if delay < j.minDelay {
+		return j.minDelay
+	}
+	if delay > j.maxDelay {
+		return j.maxDelay
+	}
+
+	return delay
+}
+
+// Push adds a packet that arrived at arrival. Packets whose playout time
+// has already passed are counted as late and dropped.
+func (j *JitterBuffer) Push(packet *rtp.Packet, arrival time.Time) {
+	if !j.started {
+		j.started = true
+		j.nextSeq = packet.SequenceNumber
+		j.baseArrival = arrival
+		j.baseTimestamp = packet.Timestamp
+	}
+
+	if int16(packet.SequenceNumber-j.nextSeq) < 0 { //nolint:gosec // G115, the difference wraps
+		j.stats.PacketsLate++
+
+		return
+	}
+	if _, ok := j.packets[packet.SequenceNumber]; ok {
+		j.stats.PacketsDuplicate++
+
+		return
+	}
+	j.packets[packet.SequenceNumber] = packet
+
+	j.updateDelay(packet.Timestamp, arrival)
+}
+
+func (j *JitterBuffer) updateDelay(timestamp uint32, arrival time.Time) {
+	transit := arrival.Sub(j.baseArrival) - j.mediaTime(timestamp)
+	if j.stats.PacketsPlayed+uint64(len(j.packets)) > 1 {
+		d := transit - j.lastTransit
+		if d < 0 {
+			d = -d
+		}
+		j.jitter += (float64(d) - j.jitter) / jitterGain
+	}
+	j.lastTransit = transit
+
+	// The packet arrived earlier than any before, move the base to it so the
+	// queuing delay is measured from the fastest path and clock drift is followed.
+	if transit < 0 {
+		j.baseArrival = arrival
+		j.baseTimestamp = timestamp
+		j.lastTransit = 0
+		transit = 0
+	}
+
+	target := j.clampDelay(time.Duration(j.jitter * j.jitterMultiplier))
+	switch {
+	case transit > j.delay:
+		j.delay = j.clampDelay(transit)
+	case target > j.delay:
+		j.delay = target
+	default:
+		j.delay -= (j.delay - target) / delayDecay
+	}
+}
+
+// playoutTime returns when the packet with timestamp is due.
+func (j *JitterBuffer) playoutTime(timestamp uint32) time.Time {
+	return j.baseArrival.Add(j.mediaTime(timestamp) + j.delay)
+}
+
+// head returns the buffered packet that comes next in sequence.
+func (j *JitterBuffer) head() (uint16, *rtp.Packet, bool) {
+	if packet, ok := j.packets[j.nextSeq]; ok {
+		return j.nextSeq, packet, true
+	}
+
+	var (
+		headSeq  uint16
+		head     *rtp.Packet
+		distance uint16
+	)
+	for seq, packet := range j.packets {
+		if d := seq - j.nextSeq; head == nil || d < distance {
+			headSeq, head, distance = seq, packet, d
+		}
+	}
+
+	return headSeq, head, head != nil
+}
+
+// Pop returns the next packet in sequence if its playout time is not after
+// now, or nil. Missing packets are skipped once the packet following them is
+// due.
+func (j *JitterBuffer) Pop(now time.Time) *rtp.Packet {
+	seq, head, ok := j.head()
+	if !ok || j.playoutTime(head.Timestamp).After(now) {
+		return nil
+	}
+
+	delete(j.packets, seq)
+	j.stats.PacketsLost += uint64(seq - j.nextSeq)
+	j.stats.PacketsPlayed++
+	j.nextSeq = seq + 1
+
+	return head
+}
+
+// NextPlayout returns the playout time of the next packet, it returns false
+// if the buffer is empty.
+func (j *JitterBuffer) NextPlayout() (time.Time, bool) {
+	_, head, ok := j.head()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return j.playoutTime(head.Timestamp), true
+}
+
+// Delay returns the current playout delay.
+func (j *JitterBuffer) Delay() time.Duration {
+	return j.delay
+}
+
+// Stats returns the delay and packet counters of the JitterBuffer.
+func (j *JitterBuffer) Stats() Stats {
+	stats := j.stats
+	stats.Delay = j.delay
+	stats.Jitter = time.Duration(j.jitter)
+
+	return stats
+}
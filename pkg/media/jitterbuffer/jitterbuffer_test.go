@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package jitterbuffer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+// 20ms Opus packets
+const (
+	clockRate         = 48000
+	timestampInterval = 960
+	packetInterval    = 20 * time.Millisecond
+)
+
+func opusPacket(seq uint16) *rtp.Packet {
+	return &rtp.Packet{Header: rtp.Header{
+		SequenceNumber: seq,
+		Timestamp:      uint32(seq) * timestampInterval,
+	}}
+}
+
+func TestJitterBuffer_Reorder(t *testing.T) {
+	buffer := New(clockRate)
+	start := time.Unix(0, 0)
+
+	// Packet 1 is overtaken by packet 2
+	buffer.Push(opusPacket(0), start)
+	buffer.Push(opusPacket(2), start.Add(2*packetInterval))
+	buffer.Push(opusPacket(1), start.Add(2*packetInterval+time.Millisecond))
+
+	next, ok := buffer.NextPlayout()
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(buffer.Delay()), next)
+
+	assert.Nil(t, buffer.Pop(next.Add(-time.Millisecond)))
+	for seq := uint16(0); seq < 3; seq++ {
+		packet := buffer.Pop(start.Add(time.Second))
+		if assert.NotNil(t, packet) {
+			assert.Equal(t, seq, packet.SequenceNumber)
+		}
+	}
+	assert.Nil(t, buffer.Pop(start.Add(time.Second)))
+
+	_, ok = buffer.NextPlayout()
+	assert.False(t, ok)
+	assert.Equal(t, uint64(3), buffer.Stats().PacketsPlayed)
+}
+
+func TestJitterBuffer_LateAndLost(t *testing.T) {
+	buffer := New(clockRate, WithMinDelay(20*time.Millisecond), WithMaxDelay(20*time.Millisecond))
+	start := time.Unix(0, 0)
+
+	buffer.Push(opusPacket(0), start)
+	buffer.Push(opusPacket(2), start.Add(2*packetInterval))
+	buffer.Push(opusPacket(2), start.Add(2*packetInterval))
+
+	assert.Equal(t, uint16(0), buffer.Pop(start.Add(packetInterval)).SequenceNumber)
+	assert.Nil(t, buffer.Pop(start.Add(2*packetInterval)))
+	// Packet 1 never arrived, packet 2 is played at its time
+	assert.Equal(t, uint16(2), buffer.Pop(start.Add(3*packetInterval)).SequenceNumber)
+
+	buffer.Push(opusPacket(1), start.Add(3*packetInterval))
+
+	stats := buffer.Stats()
+	assert.Equal(t, uint64(2), stats.PacketsPlayed)
+	assert.Equal(t, uint64(1), stats.PacketsLost)
+	assert.Equal(t, uint64(1), stats.PacketsLate)
+	assert.Equal(t, uint64(1), stats.PacketsDuplicate)
+}
+
+func TestJitterBuffer_AdaptiveDelay(t *testing.T) {
+	buffer := New(clockRate, WithMinDelay(10*time.Millisecond), WithMaxDelay(200*time.Millisecond))
+	start := time.Unix(0, 0)
+	assert.Equal(t, 10*time.Millisecond, buffer.Delay())
+
+	// Every other packet is delayed by 40ms
+	seq, played := uint16(0), 0
+	for ; seq < 100; seq++ {
+		arrival := start.Add(time.Duration(seq) * packetInterval)
+		if seq%2 == 1 {
+			arrival = arrival.Add(40 * time.Millisecond)
+		}
+		buffer.Push(opusPacket(seq), arrival)
+		for packet := buffer.Pop(arrival); packet != nil; packet = buffer.Pop(arrival) {
+			played++
+		}
+	}
+	assert.GreaterOrEqual(t, buffer.Delay(), 40*time.Millisecond)
+	assert.Greater(t, buffer.Stats().Jitter, time.Duration(0))
+	assert.Equal(t, uint64(0), buffer.Stats().PacketsLate)
+
+	// The delay shrinks back once the network is steady
+	for ; seq < 1000; seq++ {
+		arrival := start.Add(time.Duration(seq) * packetInterval)
+		buffer.Push(opusPacket(seq), arrival)
+		for packet := buffer.Pop(arrival); packet != nil; packet = buffer.Pop(arrival) {
+			played++
+		}
+	}
+	assert.Less(t, buffer.Delay(), 20*time.Millisecond)
+	assert.Greater(t, played, 900)
+}
+
+func TestJitterBuffer_SequenceWrap(t *testing.T) {
+	buffer := New(clockRate)
+	start := time.Unix(0, 0)
+
+	for i, seq := range []uint16{65534, 65535, 0, 1} {
+		packet := opusPacket(seq)
+		packet.Timestamp = uint32(i * timestampInterval) //nolint:gosec // G115
+		buffer.Push(packet, start.Add(time.Duration(i)*packetInterval))
+	}
+
+	for _, seq := range []uint16{65534, 65535, 0, 1} {
+		packet := buffer.Pop(start.Add(time.Second))
+		if assert.NotNil(t, packet) {
+			assert.Equal(t, seq, packet.SequenceNumber)
+		}
+	}
+	assert.Equal(t, uint64(0), buffer.Stats().PacketsLost)
+}
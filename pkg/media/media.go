@@ -22,6 +22,77 @@ type Sample struct {
 	// RTP headers of RTP packets forming this Sample. (Optional)
 	// Useful for accessing RTP extensions associated to the Sample.
 	RTPHeaders []*rtp.Header
+
+	// Summary of the RTP packets forming this Sample. (Optional)
+	RTPMetadata *RTPMetadata
+
+	// SVC layer of the Sample when sending a scalable (VP9/AV1) stream. (Optional)
+	// Zero is the base layer, which is also correct for non scalable streams.
+	SpatialID  uint8
+	TemporalID uint8
+
+	// Time the Sample was captured at, in the clock of the capturing system. (Optional)
+	// It is sent in the abs-capture-time header extension if it was negotiated,
+	// and set by TrackRemote.ReadSample if the remote sent it.
+	CaptureTime time.Time
+
+	// Orientation of a video Sample. (Optional)
+	// It is sent in the video orientation (CVO) header extension of the last
+	// packet of the Sample if it was negotiated, and set by
+	// TrackRemote.ReadSample if the remote sent it.
+	Orientation *VideoOrientation
+
+	// Contributing sources of a Sample mixed from several sources, such as
+	// the output of an audio mixer. (Optional)
+	// They are sent in the CSRC list of the packets of the Sample, at most 15,
+	// and set by TrackRemote.ReadSample if the remote sent them.
+	CSRC []uint32
+}
+
+// VideoOrientation is the orientation of a video frame, as carried by the
+// coordination of video orientation (CVO) RTP header extension.
+type VideoOrientation struct {
+	// Rotation is how many degrees clockwise the frame has to be rotated to
+	// be displayed upright: 0, 90, 180 or 270.
+	Rotation uint16
+	// Flip is set if the frame has to be flipped horizontally, after it is
+	// rotated, to be displayed.
+	Flip bool
+	// BackCamera is set if the frame was captured by a back facing camera.
+	BackCamera bool
+}
+
+// RTPMetadata describes the RTP packets a Sample was built from.
+type RTPMetadata struct {
+	SSRC uint32
+
+	// Inclusive range of sequence numbers of the packets forming the Sample
+	FirstSequenceNumber uint16
+	LastSequenceNumber  uint16
+
+	// Marker bit of the last packet of the Sample
+	Marker bool
+
+	// Header extensions of all packets forming the Sample, in packet order
+	Extensions []RTPHeaderExtension
+}
+
+// RTPHeaderExtension is a single RTP header extension element.
+type RTPHeaderExtension struct {
+	ID      uint8
+	Payload []byte
+}
+
+// GetExtension returns the payload of the first header extension with the given id,
+// or nil if none of the packets carried it.
+func (m *RTPMetadata) GetExtension(id uint8) []byte {
+	for _, extension := range m.Extensions {
+		if extension.ID == id {
+			return extension.Payload
+		}
+	}
+
+	return nil
 }
 
 // Writer defines an interface to handle
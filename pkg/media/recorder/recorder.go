@@ -0,0 +1,333 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+// Package recorder records the remote tracks of a PeerConnection with the
+// writers of pkg/media. The application chooses the tracks to record and
+// their writers, the recorder reads the tracks, waits for keyframes, pauses
+// and resumes the recordings and reports their lifecycle:
+//
+//	rec, err := recorder.New(peerConnection, recorder.Config{
+//		Writer: func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) (media.Writer, error) {
+//			if track.Kind() != webrtc.RTPCodecTypeVideo {
+//				return nil, nil // Not recorded
+//			}
+//
+//			return ivfwriter.New(track.ID() + ".ivf", ivfwriter.WithCodec(track.Codec().MimeType))
+//		},
+//		WaitForKeyframe: true,
+//		OnEvent: func(event recorder.Event) {
+//			log.Printf("%s: %s", event.Track.ID(), event.Type)
+//		},
+//	})
+package recorder
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/internal/keyframe"
+	"github.com/pion/webrtc/v4/internal/util"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// keyframeRequestInterval is how often a keyframe is requested while a
+// recording waits for one.
+const keyframeRequestInterval = time.Second
+
+var errWriterNil = errors.New("recorder: Config.Writer is required")
+
+// WriterFactory creates the writer a remote track is recorded to. A nil
+// writer and error skip the track.
+type WriterFactory func(track *webrtc.TrackRemote, receiver *webrtc.RTPReceiver) (media.Writer, error)
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	// EventStarted is emitted when the first packet of a track is written.
+	EventStarted EventType = iota + 1
+	// EventPaused is emitted when the recording of a track is paused.
+	EventPaused
+	// EventResumed is emitted when the first packet of a track is written
+	// after it was paused.
+	EventResumed
+	// EventStopped is emitted when the recording of a track ended, because
+	// the track ended, the recorder was closed or the writer failed.
+	EventStopped
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventStarted:
+		return "started"
+	case EventPaused:
+		return "paused"
+	case EventResumed:
+		return "resumed"
+	case EventStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a change of the recording of a track.
+type Event struct {
+	Type  EventType
+	Track *webrtc.TrackRemote
+	// Err is the error that stopped the recording, if any.
+	Err error
+}
+
+// Config configures a Recorder.
+type Config struct {
+	// Writer creates the writers of the tracks to record.
+	Writer WriterFactory
+
+	// WaitForKeyframe makes the recordings of video tracks start, and resume,
+	// at a keyframe. Keyframes are requested with PLIs while waiting.
+	WaitForKeyframe bool
+
+	// OnEvent is invoked with the lifecycle events of the recordings.
+	OnEvent func(event Event)
+}
+
+// Recorder records the remote tracks of a PeerConnection. It sets the
+// OnTrack handler of the PeerConnection.
+type Recorder struct {
+	pc     *webrtc.PeerConnection
+	config Config
+
+	mu         sync.Mutex
+	paused     bool
+	closed     bool
+	recordings map[*webrtc.TrackRemote]*recording
+}
+
+type recording struct {
+	track  *webrtc.TrackRemote
+	writer media.Writer
+
+	// Guarded by Recorder.mu
+	started, paused, waitKeyframe, stopped bool
+	lastKeyframeRequest                    time.Time
+}
+
+// New creates a Recorder for the tracks of pc.
+func New(pc *webrtc.PeerConnection, config Config) (*Recorder, error) {
+	if config.Writer == nil {
+		return nil, errWriterNil
+	}
+
+	r := &Recorder{pc: pc, config: config, recordings: map[*webrtc.TrackRemote]*recording{}}
+	pc.OnTrack(r.handleTrack)
+
+	return r, nil
+}
+
+// Pause pauses the recordings, the packets are dropped until Resume.
+func (r *Recorder) Pause() {
+	r.mu.Lock()
+	if r.paused || r.closed {
+		r.mu.Unlock()
+
+		return
+	}
+	r.paused = true
+	events := []Event{}
+	for _, rec := range r.recordings {
+		if rec.started && !rec.stopped {
+			rec.paused = true
+			events = append(events, Event{Type: EventPaused, Track: rec.track})
+		}
+	}
+	r.mu.Unlock()
+
+	r.emit(events...)
+}
+
+// Resume resumes the recordings, at the next keyframe of the video tracks if
+// WaitForKeyframe is set.
+func (r *Recorder) Resume() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.paused = false
+	for _, rec := range r.recordings {
+		if rec.paused {
+			rec.waitKeyframe = r.waitsForKeyframe(rec.track)
+			rec.lastKeyframeRequest = time.Time{}
+		}
+	}
+}
+
+// Close stops the recordings and closes their writers. The tracks are still
+// read, and their packets dropped, until they end.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+
+		return nil
+	}
+	r.closed = true
+	recordings := make([]*recording, 0, len(r.recordings))
+	for _, rec := range r.recordings {
+		recordings = append(recordings, rec)
+	}
+	r.mu.Unlock()
+
+	var errs []error
+	for _, rec := range recordings {
+		if err := r.stop(rec, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return util.FlattenErrs(errs)
+}
+
+func (r *Recorder) emit(events ...Event) {
+	if r.config.OnEvent == nil {
+		return
+	}
+
+	for _, event := range events {
+		r.config.OnEvent(event)
+	}
+}
+
+func (r *Recorder) waitsForKeyframe(track *webrtc.TrackRemote) bool {
+	return r.config.WaitForKeyframe && track.Kind() == webrtc.RTPCodecTypeVideo
+}
+
+func (r *Recorder) handleTrack(track *webrtc.TrackRemote, receiver *webrtc.RTPReceiver) {
+	r.mu.Lock()
+	closed := r.closed
+	r.mu.Unlock()
+	if closed {
+		return
+	}
+
+	writer, err := r.config.Writer(track, receiver)
+	if err != nil {
+		r.emit(Event{Type: EventStopped, Track: track, Err: err})
+
+		return
+	}
+	if writer == nil {
+		return
+	}
+
+	rec := &recording{track: track, writer: writer, waitKeyframe: r.waitsForKeyframe(track)}
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+
+		_ = writer.Close()
+
+		return
+	}
+	r.recordings[track] = rec
+	r.mu.Unlock()
+
+	go r.record(rec)
+}
+
+// record writes the packets of the track until it ends.
+func (r *Recorder) record(rec *recording) {
+	for {
+		packet, _, err := rec.track.ReadRTP()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			_ = r.stop(rec, err)
+
+			return
+		}
+
+		if err = r.write(rec, packet); err != nil {
+			_ = r.stop(rec, err)
+		}
+	}
+}
+
+// write writes a packet of the track, unless the recording is paused, stopped
+// or waiting for a keyframe.
+func (r *Recorder) write(rec *recording, packet *rtp.Packet) error {
+	r.mu.Lock()
+	if rec.stopped || r.paused {
+		r.mu.Unlock()
+
+		return nil
+	}
+
+	if rec.waitKeyframe {
+		if !keyframe.IsStart(rec.track.Codec().MimeType, packet.Payload) {
+			requestKeyframe := time.Since(rec.lastKeyframeRequest) >= keyframeRequestInterval
+			if requestKeyframe {
+				rec.lastKeyframeRequest = time.Now()
+			}
+			r.mu.Unlock()
+
+			if requestKeyframe {
+				_ = r.pc.WriteRTCP([]rtcp.Packet{
+					&rtcp.PictureLossIndication{MediaSSRC: uint32(rec.track.SSRC())},
+				})
+			}
+
+			return nil
+		}
+		rec.waitKeyframe = false
+	}
+
+	var event *Event
+	switch {
+	case !rec.started:
+		rec.started = true
+		event = &Event{Type: EventStarted, Track: rec.track}
+	case rec.paused:
+		rec.paused = false
+		event = &Event{Type: EventResumed, Track: rec.track}
+	}
+
+	// The writer is written under the lock so it isn't closed meanwhile
+	err := rec.writer.WriteRTP(packet)
+	r.mu.Unlock()
+
+	if event != nil {
+		r.emit(*event)
+	}
+
+	return err
+}
+
+// stop closes the writer of the recording and emits EventStopped, once.
+func (r *Recorder) stop(rec *recording, cause error) error {
+	r.mu.Lock()
+	if rec.stopped {
+		r.mu.Unlock()
+
+		return nil
+	}
+	rec.stopped = true
+	delete(r.recordings, rec.track)
+	err := rec.writer.Close()
+	r.mu.Unlock()
+
+	if cause == nil {
+		cause = err
+	}
+	r.emit(Event{Type: EventStopped, Track: rec.track, Err: cause})
+
+	return err
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package recorder
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+type testWriter struct {
+	mu      sync.Mutex
+	packets int
+	closed  bool
+}
+
+func (w *testWriter) WriteRTP(*rtp.Packet) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.packets++
+
+	return nil
+}
+
+func (w *testWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+
+	return nil
+}
+
+func signal(t *testing.T, offerer, answerer *webrtc.PeerConnection) {
+	t.Helper()
+
+	offer, err := offerer.CreateOffer(nil)
+	assert.NoError(t, err)
+	offerGatheringComplete := webrtc.GatheringCompletePromise(offerer)
+	assert.NoError(t, offerer.SetLocalDescription(offer))
+	<-offerGatheringComplete
+	assert.NoError(t, answerer.SetRemoteDescription(*offerer.LocalDescription()))
+
+	answer, err := answerer.CreateAnswer(nil)
+	assert.NoError(t, err)
+	answerGatheringComplete := webrtc.GatheringCompletePromise(answerer)
+	assert.NoError(t, answerer.SetLocalDescription(answer))
+	<-answerGatheringComplete
+	assert.NoError(t, offerer.SetRemoteDescription(*answerer.LocalDescription()))
+}
+
+func TestNew_WriterNil(t *testing.T) {
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+
+	_, err = New(pc, Config{})
+	assert.ErrorIs(t, err, errWriterNil)
+	assert.NoError(t, pc.Close())
+}
+
+func TestRecorder(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	sender, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+	receiver, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+
+	audio, err := webrtc.NewTrackLocalStaticSample(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus}, "audio", "pion",
+	)
+	assert.NoError(t, err)
+	_, err = sender.AddTrack(audio)
+	assert.NoError(t, err)
+	video, err := webrtc.NewTrackLocalStaticSample(
+		webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8}, "video", "pion",
+	)
+	assert.NoError(t, err)
+	_, err = sender.AddTrack(video)
+	assert.NoError(t, err)
+
+	writer := &testWriter{}
+	events := make(chan Event, 10)
+	rec, err := New(receiver, Config{
+		Writer: func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) (media.Writer, error) {
+			if track.Kind() != webrtc.RTPCodecTypeAudio {
+				return nil, nil //nolint:nilnil
+			}
+
+			return writer, nil
+		},
+		WaitForKeyframe: true,
+		OnEvent: func(event Event) {
+			events <- event
+		},
+	})
+	assert.NoError(t, err)
+
+	signal(t, sender, receiver)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(20 * time.Millisecond):
+				assert.NoError(t, audio.WriteSample(media.Sample{Data: []byte{0x00}, Duration: 20 * time.Millisecond}))
+				assert.NoError(t, video.WriteSample(media.Sample{Data: []byte{0x00}, Duration: 20 * time.Millisecond}))
+			}
+		}
+	}()
+
+	event := <-events
+	assert.Equal(t, EventStarted, event.Type)
+	assert.Equal(t, "audio", event.Track.ID())
+
+	rec.Pause()
+	assert.Equal(t, EventPaused, (<-events).Type)
+	rec.Resume()
+	assert.Equal(t, EventResumed, (<-events).Type)
+
+	assert.NoError(t, rec.Close())
+	event = <-events
+	assert.Equal(t, EventStopped, event.Type)
+	assert.NoError(t, event.Err)
+
+	writer.mu.Lock()
+	assert.True(t, writer.closed)
+	assert.NotZero(t, writer.packets)
+	writer.mu.Unlock()
+
+	close(done)
+	assert.NoError(t, sender.Close())
+	assert.NoError(t, receiver.Close())
+}
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package red implements the RTP payload for redundant audio data (RED) defined in RFC 2198.
+// It is used to send each Opus frame a second time in the packets that follow it,
+// so that losses can be recovered without retransmissions.
+package red
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	redundantHeaderSize = 4
+	primaryHeaderSize   = 1
+
+	// MaxTimestampOffset is the largest timestamp offset a redundant block can have.
+	MaxTimestampOffset = 0x3FFF
+	// MaxBlockLength is the largest payload a redundant block can have.
+	MaxBlockLength = 0x3FF
+)
+
+var (
+	errShortPacket  = errors.New("red: packet is too short")
+	errInvalidBlock = errors.New("red: block length exceeds packet")
+)
+
+// Block is one encoding carried by a RED payload.
+type Block struct {
+	PayloadType uint8
+	// TimestampOffset is subtracted from the timestamp of the RTP packet to get
+	// the timestamp of the block. It is zero for the primary encoding.
+	TimestampOffset uint16
+	Payload         []byte
+}
+
+// Unmarshal parses a RED payload. Blocks are returned oldest first, the last
+// Block is the primary encoding. Payloads reference the input buffer.
+func Unmarshal(payload []byte) ([]Block, error) {
+	blocks := []Block{}
+	offset := 0
+	for {
+		if offset >= len(payload) {
+			return nil, errShortPacket
+		}
+
+		// Primary header, the final one
+		if payload[offset]&0x80 == 0 {
+			blocks = append(blocks, Block{PayloadType: payload[offset] & 0x7F})
+			offset += primaryHeaderSize
+
+			break
+		}
+
+		if offset+redundantHeaderSize > len(payload) {
+			return nil, errShortPacket
+		}
+
+		header := binary.BigEndian.Uint32(payload[offset:])
+		blocks = append(blocks, Block{
+			PayloadType:     uint8(header >> 24 & 0x7F),                //nolint:gosec // G115
+			TimestampOffset: uint16(header >> 10 & MaxTimestampOffset), //nolint:gosec // G115
+			Payload:         make([]byte, header&MaxBlockLength),
+		})
+		offset += redundantHeaderSize
+	}
+
+	for i := range blocks[:len(blocks)-1] {
+		length := len(blocks[i].Payload)
+		if offset+length > len(payload) {
+			return nil, errInvalidBlock
+		}
+
+		blocks[i].Payload = payload[offset : offset+length]
+		offset += length
+	}
+	blocks[len(blocks)-1].Payload = payload[offset:]
+
+	return blocks, nil
+}
+
+type historyEntry struct {
+	payload   []byte
+	timestamp uint32
+}
+
+// Encoder wraps payloads in RED, adding up to distance previous payloads as
+// redundant encodings. It is not safe for concurrent use.
+type Encoder struct {
+	distance int
+	history  []historyEntry
+}
+
+// NewEncoder creates an Encoder that repeats each payload in the next distance packets.
+func NewEncoder(distance int) *Encoder {
+	return &Encoder{distance: distance}
+}
+
+// Marshal returns the RED payload carrying payload as primary encoding, the
+// history is not modified. This allows building the payload for several payload
+// types before calling Push once.
+func (e *Encoder) Marshal(payloadType uint8, payload []byte, timestamp uint32) []byte {
+	redundant := make([]historyEntry, 0, len(e.history))
+	size := primaryHeaderSize + len(payload)
+	for _, entry := range e.history {
+		// Blocks that can't be described by the header are not repeated
+		timestampOffset := timestamp - entry.timestamp
+		if timestampOffset == 0 || timestampOffset > MaxTimestampOffset || len(entry.payload) > MaxBlockLength {
+			continue
+		}
+
+		redundant = append(redundant, entry)
+		size += redundantHeaderSize + len(entry.payload)
+	}
+
+	out := make([]byte, 0, size)
+	for _, entry := range redundant {
+		out = binary.BigEndian.AppendUint32(out, 1<<31|
+			uint32(payloadType&0x7F)<<24|
+			(timestamp-entry.timestamp)<<10|
+			uint32(len(entry.payload))) //nolint:gosec // G115
+	}
+	out = append(out, payloadType&0x7F)
+	for _, entry := range redundant {
+		out = append(out, entry.payload...)
+	}
+
+	return append(out, payload...)
+}
+
+// Push adds payload to the history, the oldest payload is dropped once the
+// history has distance entries.
+func (e *Encoder) Push(payload []byte, timestamp uint32) {
+	if e.distance <= 0 {
+		return
+	}
+
+	if len(e.history) == e.distance {
+		copy(e.history, e.history[1:])
+		e.history = e.history[:len(e.history)-1]
+	}
+
+	e.history = append(e.history, historyEntry{
+		payload:   append([]byte(nil), payload...),
+		timestamp: timestamp,
+	})
+}
+
+// Encode is Marshal followed by Push.
+func (e *Encoder) Encode(payloadType uint8, payload []byte, timestamp uint32) []byte {
+	out := e.Marshal(payloadType, payload, timestamp)
+	e.Push(payload, timestamp)
+
+	return out
+}
+
+// Decoder unwraps RED packets into packets of the primary codec. Redundant
+// encodings are only returned for packets that were lost. It is not safe for
+// concurrent use.
+type Decoder struct {
+	lastSequenceNumber uint16
+	started            bool
+}
+
+// Decode returns the packets carried by the RED packet, oldest first. Packets
+// recovered from redundant encodings assume the sender increments the sequence
+// number by one per packet, which is how RED is sent by browsers and Encoder.
+func (d *Decoder) Decode(packet *rtp.Packet) ([]*rtp.Packet, error) {
+	blocks, err := Unmarshal(packet.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	packets := make([]*rtp.Packet, 0, len(blocks))
+	redundantCount := len(blocks) - 1
+	for i, block := range blocks {
+		sequenceNumber := packet.SequenceNumber - uint16(redundantCount-i) //nolint:gosec // G115
+		if d.started && !isNewer(sequenceNumber, d.lastSequenceNumber) {
+			continue
+		}
+		if !d.started && i != redundantCount {
+			continue
+		}
+
+		header := packet.Header.Clone()
+		header.PayloadType = block.PayloadType
+		header.SequenceNumber = sequenceNumber
+		header.Timestamp = packet.Timestamp - uint32(block.TimestampOffset)
+		if i != redundantCount {
+			header.Marker = false
+		}
+
+		packets = append(packets, &rtp.Packet{Header: header, Payload: block.Payload})
+		d.lastSequenceNumber = sequenceNumber
+		d.started = true
+	}
+
+	return packets, nil
+}
+
+func isNewer(sequenceNumber, last uint16) bool {
+	return sequenceNumber != last && sequenceNumber-last < 0x8000
+}
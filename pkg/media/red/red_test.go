@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package red
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder(t *testing.T) {
+	encoder := NewEncoder(2)
+
+	// No history, only the primary header
+	assert.Equal(t, []byte{111, 0xAA}, encoder.Encode(111, []byte{0xAA}, 960))
+
+	payload := encoder.Encode(111, []byte{0xBB, 0xBB}, 1920)
+	assert.Equal(t, []byte{
+		0x80 | 111, 0x0F, 0x00, 0x01, // 960 timestamp offset, 1 byte
+		111,
+		0xAA,
+		0xBB, 0xBB,
+	}, payload)
+
+	encoder.Encode(111, []byte{0xCC}, 2880)
+	blocks, err := Unmarshal(encoder.Marshal(63, []byte{0xDD}, 3840))
+	assert.NoError(t, err)
+	assert.Equal(t, []Block{
+		{PayloadType: 63, TimestampOffset: 1920, Payload: []byte{0xBB, 0xBB}},
+		{PayloadType: 63, TimestampOffset: 960, Payload: []byte{0xCC}},
+		{PayloadType: 63, Payload: []byte{0xDD}},
+	}, blocks)
+
+	// Too far in the past to be described by the header
+	blocks, err = Unmarshal(encoder.Marshal(111, []byte{0xEE}, 2880+MaxTimestampOffset+1))
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 1)
+}
+
+func TestUnmarshal_Invalid(t *testing.T) {
+	_, err := Unmarshal(nil)
+	assert.ErrorIs(t, err, errShortPacket)
+
+	_, err = Unmarshal([]byte{0x80 | 111, 0x0F})
+	assert.ErrorIs(t, err, errShortPacket)
+
+	_, err = Unmarshal([]byte{0x80 | 111, 0x0F, 0x00, 0x05, 111, 0xAA})
+	assert.ErrorIs(t, err, errInvalidBlock)
+}
+
+func TestDecoder(t *testing.T) {
+	encoder := NewEncoder(2)
+	var packets []*rtp.Packet
+	for i := 0; i < 5; i++ {
+		timestamp := uint32(960 * i)
+		packets = append(packets, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    63,
+				SequenceNumber: uint16(100 + i),
+				Timestamp:      timestamp,
+				SSRC:           5000,
+			},
+			Payload: encoder.Encode(111, []byte{byte(i)}, timestamp),
+		})
+	}
+
+	decoder := &Decoder{}
+	decode := func(packet *rtp.Packet) (sequenceNumbers []uint16) {
+		decoded, err := decoder.Decode(packet)
+		assert.NoError(t, err)
+		for _, p := range decoded {
+			assert.Equal(t, uint8(111), p.PayloadType)
+			assert.Equal(t, []byte{byte(p.SequenceNumber - 100)}, p.Payload)
+			assert.Equal(t, uint32(960*(p.SequenceNumber-100)), p.Timestamp)
+			sequenceNumbers = append(sequenceNumbers, p.SequenceNumber)
+		}
+
+		return sequenceNumbers
+	}
+
+	assert.Equal(t, []uint16{100}, decode(packets[0]))
+	assert.Equal(t, []uint16{101}, decode(packets[1]))
+	// 102 and 103 are lost, both are recovered from 104
+	assert.Equal(t, []uint16{102, 103, 104}, decode(packets[4]))
+	// Late packets are not returned twice
+	assert.Empty(t, decode(packets[3]))
+}
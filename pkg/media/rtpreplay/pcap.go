@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtpreplay
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	pcapHeaderLen       = 24
+	pcapRecordHeaderLen = 16
+
+	pcapMagicMicroseconds = 0xa1b2c3d4
+	pcapMagicNanoseconds  = 0xa1b23c4d
+
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLinuxSLL = 113
+
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+	etherTypeVLAN = 0x8100
+
+	ipProtocolUDP = 17
+	udpHeaderLen  = 8
+)
+
+var (
+	errPCAPMalformed       = errors.New("rtpreplay: malformed pcap")
+	errPCAPUnknownLinkType = errors.New("rtpreplay: unsupported pcap link type")
+)
+
+type pcapSource struct {
+	reader      io.Reader
+	byteOrder   binary.ByteOrder
+	nanoseconds bool
+	linkType    uint32
+	port        uint16
+
+	started bool
+	first   time.Time
+}
+
+// NewPCAPSource creates a Source reading the UDP packets of a pcap file that
+// look like RTP, RTCP is skipped. If port isn't 0 only the packets to or from
+// that UDP port are read. The link types Ethernet, Linux cooked capture, raw
+// IP and BSD loopback are supported, pcapng files are not.
+func NewPCAPSource(r io.Reader, port uint16) (Source, error) {
+	header := make([]byte, pcapHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errPCAPMalformed
+	}
+
+	source := &pcapSource{reader: r, port: port}
+	switch {
+	case binary.LittleEndian.Uint32(header) == pcapMagicMicroseconds:
+		source.byteOrder = binary.LittleEndian
+	case binary.BigEndian.Uint32(header) == pcapMagicMicroseconds:
+		source.byteOrder = binary.BigEndian
+	case binary.LittleEndian.Uint32(header) == pcapMagicNanoseconds:
+		source.byteOrder, source.nanoseconds = binary.LittleEndian, true
+	case binary.BigEndian.Uint32(header) == pcapMagicNanoseconds:
+		source.byteOrder, source.nanoseconds = binary.BigEndian, true
+	default:
+		return nil, errPCAPMalformed
+	}
+
+	source.linkType = source.byteOrder.Uint32(header[20:])
+	switch source.linkType {
+	case linkTypeNull, linkTypeEthernet, linkTypeRaw, linkTypeLinuxSLL:
+	default:
+		return nil, errPCAPUnknownLinkType
+	}
+
+	return source, nil
+}
+
+func (s *pcapSource) Next() (Packet, error) {
+	for {
+		header := make([]byte, pcapRecordHeaderLen)
+		if _, err := io.ReadFull(s.reader, header); errors.Is(err, io.EOF) {
+			return Packet{}, io.EOF
+		} else if err != nil {
+			return Packet{}, errPCAPMalformed
+		}
+
+		data := make([]byte, s.byteOrder.Uint32(header[8:]))
+		if _, err := io.ReadFull(s.reader, data); err != nil {
+			return Packet{}, errPCAPMalformed
+		}
+
+		subseconds := time.Duration(s.byteOrder.Uint32(header[4:]))
+		if !s.nanoseconds {
+			subseconds *= time.Microsecond
+		}
+		capturedAt := time.Unix(int64(s.byteOrder.Uint32(header[0:])), int64(subseconds))
+
+		payload, ok := s.udpPayload(data)
+		if !ok || !isRTP(payload) {
+			continue
+		}
+
+		if !s.started {
+			s.started, s.first = true, capturedAt
+		}
+
+		return Packet{Offset: capturedAt.Sub(s.first), Data: payload}, nil
+	}
+}
+
+// udpPayload returns the payload of the frame if it is an UDP packet of the port.
+func (s *pcapSource) udpPayload(frame []byte) ([]byte, bool) {
+	var etherType uint16
+	switch s.linkType {
+	case linkTypeNull:
+		if len(frame) < 4 {
+			return nil, false
+		}
+		frame, etherType = frame[4:], ipEtherType(frame[4:])
+	case linkTypeEthernet:
+		if len(frame) < 14 {
+			return nil, false
+		}
+		etherType, frame = binary.BigEndian.Uint16(frame[12:]), frame[14:]
+		if etherType == etherTypeVLAN && len(frame) >= 4 {
+			etherType, frame = binary.BigEndian.Uint16(frame[2:]), frame[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(frame) < 16 {
+			return nil, false
+		}
+		etherType, frame = binary.BigEndian.Uint16(frame[14:]), frame[16:]
+	default:
+		etherType = ipEtherType(frame)
+	}
+
+	var udp []byte
+	switch etherType {
+	case etherTypeIPv4:
+		if len(frame) < 20 || frame[9] != ipProtocolUDP {
+			return nil, false
+		}
+		// Fragments aren't reassembled
+		if binary.BigEndian.Uint16(frame[6:])&0x3fff != 0 {
+			return nil, false
+		}
+		headerLen := int(frame[0]&0x0f) * 4
+		if len(frame) < headerLen {
+			return nil, false
+		}
+		udp = frame[headerLen:]
+	case etherTypeIPv6:
+		// Extension headers aren't followed
+		if len(frame) < 40 || frame[6] != ipProtocolUDP {
+			return nil, false
+		}
+		udp = frame[40:]
+	default:
+		return nil, false
+	}
+
+	if len(udp) < udpHeaderLen {
+		return nil, false
+	}
+	if s.port != 0 && binary.BigEndian.Uint16(udp[0:]) != s.port && binary.BigEndian.Uint16(udp[2:]) != s.port {
+		return nil, false
+	}
+
+	length := int(binary.BigEndian.Uint16(udp[4:]))
+	if length < udpHeaderLen || length > len(udp) {
+		return nil, false
+	}
+
+	return udp[udpHeaderLen:length], true
+}
+
+// ipEtherType returns the EtherType of a raw IP packet from its version.
+func ipEtherType(packet []byte) uint16 {
+	if len(packet) == 0 {
+		return 0
+	}
+
+	switch packet[0] >> 4 {
+	case 4:
+		return etherTypeIPv4
+	case 6:
+		return etherTypeIPv6
+	default:
+		return 0
+	}
+}
+
+// isRTP returns true if the payload is a RTP packet, RTCP packet types 200
+// to 204 conflict with the RTP payload types 72 to 76 and are skipped.
+func isRTP(payload []byte) bool {
+	if len(payload) < 12 || payload[0]>>6 != 2 {
+		return false
+	}
+	payloadType := payload[1] & 0x7f
+
+	return payloadType < 72 || payloadType > 76
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package rtpreplay writes the RTP packets of a capture to a track with their
+// original timing, to reproduce a recorded session against live code:
+//
+//	source, err := rtpreplay.NewRTPDumpSource(file)
+//	...
+//	err = rtpreplay.Replay(ctx, source, track, rtpreplay.WithSpeed(2))
+//
+// Captures are read from rtpdump files or from pcap files of UDP traffic.
+// The packets are written as they were captured, a TrackLocalStaticRTP
+// rewrites their SSRC and payload type to the negotiated ones.
+package rtpreplay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media/rtpdump"
+)
+
+var errInvalidSpeed = errors.New("rtpreplay: speed must not be negative")
+
+// Packet is a RTP packet of a capture.
+type Packet struct {
+	// Offset is the time since the start of the capture
+	Offset time.Duration
+	// Data is the RTP packet
+	Data []byte
+}
+
+// Source reads the RTP packets of a capture in order. Next returns io.EOF at
+// the end of the capture.
+type Source interface {
+	Next() (Packet, error)
+}
+
+type rtpDumpSource struct {
+	reader *rtpdump.Reader
+}
+
+// NewRTPDumpSource creates a Source reading an rtpdump file, its RTCP packets
+// are skipped.
+func NewRTPDumpSource(r io.Reader) (Source, error) {
+	reader, _, err := rtpdump.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rtpDumpSource{reader: reader}, nil
+}
+
+func (s *rtpDumpSource) Next() (Packet, error) {
+	for {
+		packet, err := s.reader.Next()
+		if err != nil {
+			return Packet{}, err
+		}
+		if !packet.IsRTCP {
+			return Packet{Offset: packet.Offset, Data: packet.Payload}, nil
+		}
+	}
+}
+
+// Writer is written the replayed packets, it is implemented by
+// webrtc.TrackLocalStaticRTP.
+type Writer interface {
+	WriteRTP(packet *rtp.Packet) error
+}
+
+type config struct {
+	speed  float64
+	ssrc   uint32
+	bySSRC bool
+}
+
+// An Option configures Replay.
+type Option func(c *config)
+
+// WithSpeed sets how much faster than captured the packets are written, it
+// defaults to 1. A speed of 0 writes them without waiting.
+func WithSpeed(speed float64) Option {
+	return func(c *config) {
+		c.speed = speed
+	}
+}
+
+// WithSSRC only replays the packets of the stream with ssrc, a capture of a
+// whole session usually holds many streams.
+func WithSSRC(ssrc uint32) Option {
+	return func(c *config) {
+		c.ssrc = ssrc
+		c.bySSRC = true
+	}
+}
+
+// Replay writes the packets of source to writer, each at its offset from the
+// first one. It returns once the source is exhausted, or ctx is done.
+// Packets that aren't valid RTP are skipped.
+func Replay(ctx context.Context, source Source, writer Writer, opts ...Option) error {
+	cfg := config{speed: 1}
+	for _, o := range opts {
+		o(&cfg)
+	}
+	if cfg.speed < 0 {
+		return errInvalidSpeed
+	}
+
+	var (
+		start       time.Time
+		firstOffset time.Duration
+		started     bool
+	)
+	for {
+		packet, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		rtpPacket := &rtp.Packet{}
+		if rtpPacket.Unmarshal(packet.Data) != nil {
+			continue
+		}
+		if cfg.bySSRC && rtpPacket.SSRC != cfg.ssrc {
+			continue
+		}
+
+		if !started {
+			start, firstOffset, started = time.Now(), packet.Offset, true
+		}
+		if cfg.speed != 0 {
+			due := start.Add(time.Duration(float64(packet.Offset-firstOffset) / cfg.speed))
+			if err := sleepUntil(ctx, due); err != nil {
+				return err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := writer.WriteRTP(rtpPacket); err != nil {
+			return err
+		}
+	}
+}
+
+func sleepUntil(ctx context.Context, due time.Time) error {
+	delay := time.Until(due)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
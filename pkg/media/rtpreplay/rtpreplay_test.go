@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package rtpreplay
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media/rtpdump"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testWriter struct {
+	packets []*rtp.Packet
+	times   []time.Time
+}
+
+func (w *testWriter) WriteRTP(packet *rtp.Packet) error {
+	w.packets = append(w.packets, packet)
+	w.times = append(w.times, time.Now())
+
+	return nil
+}
+
+func marshalRTP(t *testing.T, ssrc uint32, seq uint16) []byte {
+	t.Helper()
+
+	raw, err := (&rtp.Packet{
+		Header:  rtp.Header{Version: 2, PayloadType: 96, SSRC: ssrc, SequenceNumber: seq},
+		Payload: []byte{0x01},
+	}).Marshal()
+	require.NoError(t, err)
+
+	return raw
+}
+
+func rtpDumpCapture(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	writer, err := rtpdump.NewWriter(out, rtpdump.Header{Source: net.IPv4(127, 0, 0, 1), Port: 5000})
+	require.NoError(t, err)
+
+	for i, packet := range []rtpdump.Packet{
+		{Offset: 0, Payload: marshalRTP(t, 1, 0)},
+		{Offset: 0, Payload: []byte{0x80, 0xc8, 0x00, 0x00}, IsRTCP: true},
+		{Offset: 20 * time.Millisecond, Payload: marshalRTP(t, 2, 0)},
+		{Offset: 40 * time.Millisecond, Payload: marshalRTP(t, 1, 1)},
+	} {
+		require.NoError(t, writer.WritePacket(packet), i)
+	}
+
+	return out
+}
+
+func TestReplay_RTPDump(t *testing.T) {
+	source, err := NewRTPDumpSource(rtpDumpCapture(t))
+	require.NoError(t, err)
+
+	writer := &testWriter{}
+	assert.NoError(t, Replay(context.Background(), source, writer, WithSpeed(0)))
+	require.Len(t, writer.packets, 3)
+	assert.Equal(t, uint32(2), writer.packets[1].SSRC)
+
+	source, err = NewRTPDumpSource(rtpDumpCapture(t))
+	require.NoError(t, err)
+
+	// 40ms of capture replayed twice as fast
+	writer = &testWriter{}
+	assert.NoError(t, Replay(context.Background(), source, writer, WithSpeed(2), WithSSRC(1)))
+	require.Len(t, writer.packets, 2)
+	assert.Equal(t, uint16(1), writer.packets[1].SequenceNumber)
+	assert.GreaterOrEqual(t, writer.times[1].Sub(writer.times[0]), 20*time.Millisecond)
+
+	assert.ErrorIs(t, Replay(context.Background(), source, writer, WithSpeed(-1)), errInvalidSpeed)
+}
+
+func TestReplay_Canceled(t *testing.T) {
+	source, err := NewRTPDumpSource(rtpDumpCapture(t))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, Replay(ctx, source, &testWriter{}), context.Canceled)
+}
+
+// pcapCapture returns a little endian pcap of Ethernet frames holding the
+// UDP payloads, sent every 10ms from port 5000 to port 6000.
+func pcapCapture(payloads ...[]byte) *bytes.Buffer {
+	out := &bytes.Buffer{}
+	header := make([]byte, pcapHeaderLen)
+	binary.LittleEndian.PutUint32(header[0:], pcapMagicMicroseconds)
+	binary.LittleEndian.PutUint32(header[20:], linkTypeEthernet)
+	out.Write(header)
+
+	for i, payload := range payloads {
+		frame := make([]byte, 14+20+udpHeaderLen, 14+20+udpHeaderLen+len(payload))
+		binary.BigEndian.PutUint16(frame[12:], etherTypeIPv4)
+		frame[14] = 0x45
+		frame[14+9] = ipProtocolUDP
+		binary.BigEndian.PutUint16(frame[34:], 5000)
+		binary.BigEndian.PutUint16(frame[36:], 6000)
+		binary.BigEndian.PutUint16(frame[38:], uint16(udpHeaderLen+len(payload))) //nolint:gosec // G115
+		frame = append(frame, payload...)
+
+		record := make([]byte, pcapRecordHeaderLen)
+		binary.LittleEndian.PutUint32(record[0:], 1700000000)
+		binary.LittleEndian.PutUint32(record[4:], uint32(i*10000))     //nolint:gosec // G115
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(frame)))  //nolint:gosec // G115
+		binary.LittleEndian.PutUint32(record[12:], uint32(len(frame))) //nolint:gosec // G115
+		out.Write(record)
+		out.Write(frame)
+	}
+
+	return out
+}
+
+func TestPCAPSource(t *testing.T) {
+	capture := func() *bytes.Buffer {
+		return pcapCapture(marshalRTP(t, 1, 0), []byte{0x80, 0xc9, 0x00, 0x01, 0, 0, 0, 1}, marshalRTP(t, 1, 1))
+	}
+
+	source, err := NewPCAPSource(capture(), 6000)
+	require.NoError(t, err)
+
+	packet, err := source.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), packet.Offset)
+	assert.Equal(t, marshalRTP(t, 1, 0), packet.Data)
+
+	// The RTCP packet is skipped
+	packet, err = source.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Millisecond, packet.Offset)
+	assert.Equal(t, marshalRTP(t, 1, 1), packet.Data)
+
+	_, err = source.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	source, err = NewPCAPSource(capture(), 7000)
+	require.NoError(t, err)
+	_, err = source.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = NewPCAPSource(bytes.NewReader([]byte{0x01}), 0)
+	assert.ErrorIs(t, err, errPCAPMalformed)
+}
@@ -51,6 +51,21 @@ type SampleBuilder struct {
 
 	// return array of RTP headers as Sample.RTPHeaders
 	returnRTPHeaders bool
+
+	// return a summary of the RTP packets as Sample.RTPMetadata
+	returnRTPMetadata bool
+
+	// max wall clock time a packet may wait in the buffer before its sample
+	// is force-emitted or dropped
+	maxLatency time.Duration
+
+	// arrival times of the packets in buffer indexed by sequence number modulo
+	// its length, only allocated if maxLatency is set. The buffer holds at
+	// most maxLate packets once purged, so they don't overlap.
+	arrivals []time.Time
+
+	// returns the current time, overridable for testing
+	now func() time.Time
 }
 
 // New constructs a new SampleBuilder.
@@ -60,11 +75,15 @@ type SampleBuilder struct {
 // The depacketizer extracts media samples from RTP packets.
 // Several depacketizers are available in package github.com/pion/rtp/codecs.
 func New(maxLate uint16, depacketizer rtp.Depacketizer, sampleRate uint32, opts ...Option) *SampleBuilder {
-	s := &SampleBuilder{maxLate: maxLate, depacketizer: depacketizer, sampleRate: sampleRate}
+	s := &SampleBuilder{maxLate: maxLate, depacketizer: depacketizer, sampleRate: sampleRate, now: time.Now}
 	for _, o := range opts {
 		o(s)
 	}
 
+	if s.maxLatency > 0 {
+		s.arrivals = make([]time.Time, int(s.maxLate)+1)
+	}
+
 	return s
 }
 
@@ -103,6 +122,22 @@ func (s *SampleBuilder) tooOld(location sampleSequenceLocation) bool {
 	return timestampDistance(foundHead.Timestamp, foundTail.Timestamp) > s.maxLateTimestamp
 }
 
+// tooLate returns true if the oldest packet of location has been waiting
+// longer than maxLatency.
+func (s *SampleBuilder) tooLate(location sampleSequenceLocation) bool {
+	if s.maxLatency == 0 {
+		return false
+	}
+
+	for i := location.head; i != location.tail; i++ {
+		if s.buffer[i] != nil {
+			return s.now().Sub(s.arrivals[int(i)%len(s.arrivals)]) > s.maxLatency
+		}
+	}
+
+	return false
+}
+
 // fetchTimestamp returns the timestamp associated with a given sample location.
 func (s *SampleBuilder) fetchTimestamp(location sampleSequenceLocation) (timestamp uint32, hasData bool) {
 	if location.empty() {
@@ -155,7 +190,7 @@ func (s *SampleBuilder) purgeConsumedLocation(consume sampleSequenceLocation, fo
 func (s *SampleBuilder) purgeBuffers(flush bool) {
 	s.purgeConsumedBuffers()
 
-	for (s.tooOld(s.filled) || (s.filled.count() > s.maxLate) || flush) && s.filled.hasData() {
+	for (s.tooOld(s.filled) || s.tooLate(s.filled) || (s.filled.count() > s.maxLate) || flush) && s.filled.hasData() {
 		if s.active.empty() {
 			// refill the active based on the filled packets
 			s.active = s.filled
@@ -184,6 +219,9 @@ func (s *SampleBuilder) purgeBuffers(flush bool) {
 // this memory make sure to copy before calling Push.
 func (s *SampleBuilder) Push(packet *rtp.Packet) {
 	s.buffer[packet.SequenceNumber] = packet
+	if s.arrivals != nil {
+		s.arrivals[int(packet.SequenceNumber)%len(s.arrivals)] = s.now()
+	}
 
 	switch s.filled.compare(packet.SequenceNumber) {
 	case slCompareVoid:
@@ -291,6 +329,15 @@ func (s *SampleBuilder) buildSample(purgingBuffers bool) *media.Sample {
 	data := []byte{}
 	var metadata interface{}
 	var rtpHeaders []*rtp.Header
+	var rtpMetadata *media.RTPMetadata
+	if s.returnRTPMetadata {
+		rtpMetadata = &media.RTPMetadata{
+			SSRC:                s.buffer[consume.head].SSRC,
+			FirstSequenceNumber: consume.head,
+			LastSequenceNumber:  consume.tail - 1,
+			Marker:              s.buffer[consume.tail-1].Marker,
+		}
+	}
 	for i := consume.head; i != consume.tail; i++ {
 		payload, err := s.depacketizer.Unmarshal(s.buffer[i].Payload)
 		if err != nil {
@@ -303,6 +350,14 @@ func (s *SampleBuilder) buildSample(purgingBuffers bool) *media.Sample {
 			h := s.buffer[i].Header.Clone()
 			rtpHeaders = append(rtpHeaders, &h)
 		}
+		if rtpMetadata != nil {
+			for _, id := range s.buffer[i].GetExtensionIDs() {
+				rtpMetadata.Extensions = append(rtpMetadata.Extensions, media.RTPHeaderExtension{
+					ID:      id,
+					Payload: append([]byte{}, s.buffer[i].GetExtension(id)...),
+				})
+			}
+		}
 
 		data = append(data, payload...)
 	}
@@ -315,6 +370,7 @@ func (s *SampleBuilder) buildSample(purgingBuffers bool) *media.Sample {
 		PrevDroppedPackets: s.droppedPackets,
 		Metadata:           metadata,
 		RTPHeaders:         rtpHeaders,
+		RTPMetadata:        rtpMetadata,
 	}
 
 	s.droppedPackets = 0
@@ -334,6 +390,11 @@ func (s *SampleBuilder) buildSample(purgingBuffers bool) *media.Sample {
 // Pop compiles pushed RTP packets into media samples and then
 // returns the next valid sample (or nil if no sample is compiled).
 func (s *SampleBuilder) Pop() *media.Sample {
+	if s.maxLatency > 0 {
+		// release samples that have waited too long even if no new packets arrived
+		s.purgeBuffers(false)
+	}
+
 	_ = s.buildSample(false)
 	if s.prepared.empty() {
 		return nil
@@ -393,6 +454,26 @@ func WithMaxTimeDelay(maxLateDuration time.Duration) Option {
 	}
 }
 
+// WithMaxLatency bounds the wall clock time a packet may wait in the buffer.
+// Once the oldest packet has waited longer than maxLatency its sample is
+// emitted even if incomplete, or dropped if it can't be built. Unlike
+// WithMaxTimeDelay this is also enforced on Pop, so the builder never stalls
+// when packets stop arriving.
+func WithMaxLatency(maxLatency time.Duration) Option {
+	return func(o *SampleBuilder) {
+		o.maxLatency = maxLatency
+	}
+}
+
+// WithRTPMetadata enables to summarize the RTP packets forming a Sample in
+// Sample.RTPMetadata, exposing SSRC, sequence number range, marker bit and
+// header extensions without copying every RTP header.
+func WithRTPMetadata(enable bool) Option {
+	return func(o *SampleBuilder) {
+		o.returnRTPMetadata = enable
+	}
+}
+
 // WithRTPHeaders enables to collect RTP headers forming a Sample.
 // Useful for accessing RTP extensions associated to the Sample.
 func WithRTPHeaders(enable bool) Option {
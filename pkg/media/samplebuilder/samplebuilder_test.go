@@ -640,6 +640,80 @@ func TestSampleBuilder_Flush(t *testing.T) {
 	assert.Equal(t, expected, samples)
 }
 
+func TestSampleBuilder_MaxLatency(t *testing.T) {
+	now := time.Unix(0, 0)
+	fd := New(50, &fakeDepacketizer{
+		headChecker: true,
+		headBytes:   []byte{0x01},
+	}, 1, WithMaxLatency(100*time.Millisecond))
+	fd.now = func() time.Time { return now }
+	assert.Len(t, fd.arrivals, 51, "Arrivals are only kept for the packets the buffer may hold")
+
+	fd.Push(&rtp.Packet{
+		Header:  rtp.Header{SequenceNumber: 999, Timestamp: 0},
+		Payload: []byte{0x00},
+	}) // Invalid packet
+	// Gap preventing below packets to be processed
+	fd.Push(&rtp.Packet{
+		Header:  rtp.Header{SequenceNumber: 1001, Timestamp: 1, Marker: true},
+		Payload: []byte{0x01, 0x11},
+	}) // Valid packet
+	assert.Nil(t, fd.Pop(), "Sample must not be emitted before max latency")
+
+	now = now.Add(150 * time.Millisecond)
+	fd.Push(&rtp.Packet{
+		Header:  rtp.Header{SequenceNumber: 1011, Timestamp: 10, Marker: true},
+		Payload: []byte{0x01, 0x12},
+	}) // Valid packet
+
+	assert.Equal(t, &media.Sample{
+		Data: []byte{0x01, 0x11}, Duration: 9 * time.Second, PacketTimestamp: 1, PrevDroppedPackets: 2,
+	}, fd.Pop())
+	assert.Nil(t, fd.Pop(), "Recent packet must not be force-emitted")
+
+	// No packets arrive, Pop alone must release the pending sample
+	now = now.Add(150 * time.Millisecond)
+	assert.Equal(t, &media.Sample{
+		Data: []byte{0x01, 0x12}, Duration: 0, PacketTimestamp: 10, PrevDroppedPackets: 9,
+	}, fd.Pop())
+	assert.Nil(t, fd.Pop())
+}
+
+func TestSampleBuilder_RTPMetadata(t *testing.T) {
+	fd := New(50, &fakeDepacketizer{}, 1, WithRTPMetadata(true))
+
+	packets := []*rtp.Packet{
+		{Header: rtp.Header{SequenceNumber: 65535, Timestamp: 5, SSRC: 1234}, Payload: []byte{0x01}},
+		{Header: rtp.Header{SequenceNumber: 0, Timestamp: 5, SSRC: 1234, Marker: true}, Payload: []byte{0x02}},
+		{Header: rtp.Header{SequenceNumber: 1, Timestamp: 6, SSRC: 1234}, Payload: []byte{0x03}},
+	}
+	assert.NoError(t, packets[0].SetExtension(1, []byte{0xAA}))
+	assert.NoError(t, packets[1].SetExtension(1, []byte{0xBB}))
+	assert.NoError(t, packets[1].SetExtension(2, []byte{0xCC}))
+
+	for _, p := range packets {
+		fd.Push(p)
+	}
+
+	sample := fd.Pop()
+	assert.NotNil(t, sample)
+	assert.Equal(t, &media.RTPMetadata{
+		SSRC:                1234,
+		FirstSequenceNumber: 65535,
+		LastSequenceNumber:  0,
+		Marker:              true,
+		Extensions: []media.RTPHeaderExtension{
+			{ID: 1, Payload: []byte{0xAA}},
+			{ID: 1, Payload: []byte{0xBB}},
+			{ID: 2, Payload: []byte{0xCC}},
+		},
+	}, sample.RTPMetadata)
+	assert.Equal(t, []byte{0xAA}, sample.RTPMetadata.GetExtension(1))
+	assert.Equal(t, []byte{0xCC}, sample.RTPMetadata.GetExtension(2))
+	assert.Nil(t, sample.RTPMetadata.GetExtension(3))
+	assert.Nil(t, sample.RTPHeaders)
+}
+
 func BenchmarkSampleBuilderSequential(b *testing.B) {
 	fd := New(100, &fakeDepacketizer{}, 1)
 	b.ResetTimer()
@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package wavwriter implements WAV media container writer for PCM audio
+package wavwriter
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/pion/rtp"
+)
+
+// Format is the payload format of the RTP packets given to the WAVWriter.
+type Format int
+
+const (
+	// FormatPCMU is G.711 µ-law, expanded to 16-bit linear PCM.
+	FormatPCMU Format = iota + 1
+	// FormatPCMA is G.711 A-law, expanded to 16-bit linear PCM.
+	FormatPCMA
+	// FormatL16 is 16-bit big-endian linear PCM as defined in RFC 3551.
+	FormatL16
+)
+
+const (
+	wavHeaderSize      = 44
+	wavFormatPCM       = 1
+	wavBitsPerSample   = 16
+	wavBytesPerSample  = wavBitsPerSample / 8
+	riffChunkSizeIndex = 4
+	dataChunkSizeIndex = 40
+)
+
+var (
+	errFileNotOpened    = errors.New("file not opened")
+	errInvalidNilPacket = errors.New("invalid nil packet")
+	errUnknownFormat    = errors.New("unknown payload format")
+	errInvalidL16Length = errors.New("L16 payload length is not a multiple of the sample size")
+	errInvalidRate      = errors.New("sample rate must not be zero")
+	errInvalidChannels  = errors.New("channel count must not be zero")
+)
+
+// WAVWriter is used to take RTP packets and write them to a WAV file on disk.
+type WAVWriter struct {
+	stream       io.Writer
+	fd           *os.File
+	format       Format
+	sampleRate   uint32
+	channelCount uint16
+	dataSize     uint32
+}
+
+// New builds a new WAV writer.
+func New(fileName string, format Format, sampleRate uint32, channelCount uint16) (*WAVWriter, error) {
+	file, err := os.Create(fileName) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	writer, err := NewWith(file, format, sampleRate, channelCount)
+	if err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+	writer.fd = file
+
+	return writer, nil
+}
+
+// NewWith initialize a new WAV writer with an io.Writer output.
+//
+// The RIFF and data chunk sizes are only updated on Close if the output
+// implements io.WriteSeeker, otherwise they are left at their maximum value
+// which most decoders treat as 'read until end of stream'.
+func NewWith(out io.Writer, format Format, sampleRate uint32, channelCount uint16) (*WAVWriter, error) {
+	if out == nil {
+		return nil, errFileNotOpened
+	}
+
+	switch format {
+	case FormatPCMU, FormatPCMA, FormatL16:
+	default:
+		return nil, errUnknownFormat
+	}
+
+	if sampleRate == 0 {
+		return nil, errInvalidRate
+	}
+	if channelCount == 0 {
+		return nil, errInvalidChannels
+	}
+
+	writer := &WAVWriter{
+		stream:       out,
+		format:       format,
+		sampleRate:   sampleRate,
+		channelCount: channelCount,
+	}
+	if err := writer.writeHeader(); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+/*
+   ref: http://soundfile.sapp.org/doc/WaveFormat/
+
+   0         4         8         12        16        20        22        24
+   +---------+---------+---------+---------+---------+---------+---------+
+   |  RIFF   | ChunkSz |  WAVE   |  fmt    | 16      | PCM     | Chans   |
+   +---------+---------+---------+---------+---------+---------+---------+
+   24        28        32        34        36        40        44
+   | SmplRate| ByteRate| Align   | Bits    |  data   | DataSz  | samples...
+   +---------+---------+---------+---------+---------+---------+
+*/
+
+func (w *WAVWriter) writeHeader() error {
+	header := make([]byte, wavHeaderSize)
+	blockAlign := w.channelCount * wavBytesPerSample
+
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[riffChunkSizeIndex:], 0xFFFFFFFF) // Updated on Close
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)                              // fmt chunk size
+	binary.LittleEndian.PutUint16(header[20:], wavFormatPCM)                    // Audio format
+	binary.LittleEndian.PutUint16(header[22:], w.channelCount)                  // Channels
+	binary.LittleEndian.PutUint32(header[24:], w.sampleRate)                    // Sample rate
+	binary.LittleEndian.PutUint32(header[28:], w.sampleRate*uint32(blockAlign)) // Byte rate
+	binary.LittleEndian.PutUint16(header[32:], blockAlign)                      // Block align
+	binary.LittleEndian.PutUint16(header[34:], wavBitsPerSample)                // Bits per sample
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[dataChunkSizeIndex:], 0xFFFFFFFF) // Updated on Close
+
+	return w.writeToStream(header)
+}
+
+// WriteRTP adds a new packet and writes its samples as 16-bit little-endian PCM.
+func (w *WAVWriter) WriteRTP(packet *rtp.Packet) error {
+	if packet == nil {
+		return errInvalidNilPacket
+	}
+	if len(packet.Payload) == 0 {
+		return nil
+	}
+
+	var samples []byte
+	switch w.format {
+	case FormatPCMU:
+		samples = make([]byte, len(packet.Payload)*wavBytesPerSample)
+		for i, b := range packet.Payload {
+			binary.LittleEndian.PutUint16(samples[i*wavBytesPerSample:], uint16(DecodeULaw(b))) //nolint:gosec // G115
+		}
+	case FormatPCMA:
+		samples = make([]byte, len(packet.Payload)*wavBytesPerSample)
+		for i, b := range packet.Payload {
+			binary.LittleEndian.PutUint16(samples[i*wavBytesPerSample:], uint16(DecodeALaw(b))) //nolint:gosec // G115
+		}
+	case FormatL16:
+		if len(packet.Payload)%wavBytesPerSample != 0 {
+			return errInvalidL16Length
+		}
+		// L16 is sent in network byte order, WAV stores little-endian
+		samples = make([]byte, len(packet.Payload))
+		for i := 0; i < len(packet.Payload); i += wavBytesPerSample {
+			samples[i], samples[i+1] = packet.Payload[i+1], packet.Payload[i]
+		}
+	}
+
+	if err := w.writeToStream(samples); err != nil {
+		return err
+	}
+	w.dataSize += uint32(len(samples)) //nolint:gosec // G115
+
+	return nil
+}
+
+// Close stops the recording.
+func (w *WAVWriter) Close() error {
+	defer func() {
+		w.fd = nil
+		w.stream = nil
+	}()
+
+	// Returns no error has it may be convenient to call
+	// Close() multiple times
+	if w.stream == nil {
+		return nil
+	}
+
+	if seeker, ok := w.stream.(io.WriteSeeker); ok {
+		if err := w.updateChunkSizes(seeker); err != nil {
+			return err
+		}
+	}
+
+	if w.fd != nil {
+		return w.fd.Close()
+	}
+
+	// Close stream if we are operating on a stream
+	if closer, ok := w.stream.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (w *WAVWriter) updateChunkSizes(seeker io.WriteSeeker) error {
+	size := make([]byte, 4)
+
+	binary.LittleEndian.PutUint32(size, wavHeaderSize-8+w.dataSize)
+	if _, err := seeker.Seek(riffChunkSizeIndex, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := seeker.Write(size); err != nil {
+		return err
+	}
+
+	binary.LittleEndian.PutUint32(size, w.dataSize)
+	if _, err := seeker.Seek(dataChunkSizeIndex, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := seeker.Write(size); err != nil {
+		return err
+	}
+
+	_, err := seeker.Seek(0, io.SeekEnd)
+
+	return err
+}
+
+// Wraps writing to the stream and maintains state.
+func (w *WAVWriter) writeToStream(p []byte) error {
+	if w.stream == nil {
+		return errFileNotOpened
+	}
+
+	_, err := w.stream.Write(p)
+
+	return err
+}
+
+// DecodeULaw expands a G.711 µ-law sample to 16-bit linear PCM.
+func DecodeULaw(uLaw byte) int16 {
+	uLaw = ^uLaw
+	sample := ((int32(uLaw&0x0F) << 3) + 0x84) << ((uLaw & 0x70) >> 4)
+	if uLaw&0x80 != 0 {
+		return int16(0x84 - sample) //nolint:gosec // G115
+	}
+
+	return int16(sample - 0x84) //nolint:gosec // G115
+}
+
+// DecodeALaw expands a G.711 A-law sample to 16-bit linear PCM.
+func DecodeALaw(aLaw byte) int16 {
+	aLaw ^= 0x55
+	sample := int32(aLaw&0x0F) << 4
+	switch segment := (aLaw & 0x70) >> 4; segment {
+	case 0:
+		sample += 8
+	case 1:
+		sample += 0x108
+	default:
+		sample += 0x108
+		sample <<= segment - 1
+	}
+
+	if aLaw&0x80 != 0 {
+		return int16(sample) //nolint:gosec // G115
+	}
+
+	return int16(-sample) //nolint:gosec // G115
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package wavwriter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeG711(t *testing.T) {
+	assert.Equal(t, int16(0), DecodeULaw(0xFF))
+	assert.Equal(t, int16(-32124), DecodeULaw(0x00))
+	assert.Equal(t, int16(32124), DecodeULaw(0x80))
+
+	assert.Equal(t, int16(8), DecodeALaw(0xD5))
+	assert.Equal(t, int16(-8), DecodeALaw(0x55))
+	assert.Equal(t, int16(32256), DecodeALaw(0xAA))
+	assert.Equal(t, int16(-32256), DecodeALaw(0x2A))
+}
+
+func TestWAVWriter_InvalidArguments(t *testing.T) {
+	_, err := NewWith(nil, FormatPCMU, 8000, 1)
+	assert.ErrorIs(t, err, errFileNotOpened)
+
+	_, err = NewWith(&bytes.Buffer{}, Format(0), 8000, 1)
+	assert.ErrorIs(t, err, errUnknownFormat)
+
+	_, err = NewWith(&bytes.Buffer{}, FormatPCMU, 0, 1)
+	assert.ErrorIs(t, err, errInvalidRate)
+
+	_, err = NewWith(&bytes.Buffer{}, FormatPCMU, 8000, 0)
+	assert.ErrorIs(t, err, errInvalidChannels)
+
+	// New returns the error of NewWith instead of the one of closing the file
+	_, err = New(filepath.Join(t.TempDir(), "test.wav"), Format(0), 8000, 1)
+	assert.ErrorIs(t, err, errUnknownFormat)
+
+	writer, err := NewWith(&bytes.Buffer{}, FormatL16, 8000, 1)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, writer.WriteRTP(nil), errInvalidNilPacket)
+	assert.ErrorIs(t, writer.WriteRTP(&rtp.Packet{Payload: []byte{0x00}}), errInvalidL16Length)
+
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, writer.Close())
+	assert.ErrorIs(t, writer.WriteRTP(&rtp.Packet{Payload: []byte{0x00, 0x01}}), errFileNotOpened)
+}
+
+func TestWAVWriter_Stream(t *testing.T) {
+	for _, test := range []struct {
+		format   Format
+		payload  []byte
+		expected []byte
+	}{
+		{FormatPCMU, []byte{0xFF, 0x80}, []byte{0x00, 0x00, 0x7C, 0x7D}},
+		{FormatPCMA, []byte{0xD5, 0x55}, []byte{0x08, 0x00, 0xF8, 0xFF}},
+		{FormatL16, []byte{0x12, 0x34, 0xAB, 0xCD}, []byte{0x34, 0x12, 0xCD, 0xAB}},
+	} {
+		buffer := &bytes.Buffer{}
+		writer, err := NewWith(buffer, test.format, 8000, 1)
+		assert.NoError(t, err)
+
+		assert.NoError(t, writer.WriteRTP(&rtp.Packet{Payload: test.payload}))
+		assert.NoError(t, writer.Close())
+
+		out := buffer.Bytes()
+		assert.Equal(t, "RIFF", string(out[0:4]))
+		assert.Equal(t, "WAVE", string(out[8:12]))
+		assert.Equal(t, uint32(8000), binary.LittleEndian.Uint32(out[24:]))
+		assert.Equal(t, uint32(16000), binary.LittleEndian.Uint32(out[28:]))
+		assert.Equal(t, test.expected, out[wavHeaderSize:])
+	}
+}
+
+func TestWAVWriter_File(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "test.wav")
+
+	writer, err := New(fileName, FormatPCMU, 8000, 1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, writer.WriteRTP(&rtp.Packet{Payload: []byte{0xFF, 0xFF, 0xFF}}))
+	assert.NoError(t, writer.WriteRTP(&rtp.Packet{Payload: []byte{0xFF}}))
+	assert.NoError(t, writer.Close())
+
+	out, err := os.ReadFile(fileName) //nolint:gosec
+	assert.NoError(t, err)
+	assert.Len(t, out, wavHeaderSize+8)
+	assert.Equal(t, uint32(wavHeaderSize-8+8), binary.LittleEndian.Uint32(out[riffChunkSizeIndex:]))
+	assert.Equal(t, uint32(8), binary.LittleEndian.Uint32(out[dataChunkSizeIndex:]))
+}
@@ -0,0 +1,304 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package y4mreader implements YUV4MPEG2 (Y4M) raw video reader
+package y4mreader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	y4mFileHeaderSignature  = "YUV4MPEG2"
+	y4mFrameHeaderSignature = "FRAME"
+
+	// maxHeaderLength bounds the size of a header line to protect against
+	// malformed input without a terminating newline.
+	maxHeaderLength = 1024
+
+	// maxDimension bounds the width and height of a frame, and maxFrameSize
+	// the bytes of a frame, so a malformed header can't make the reader
+	// allocate unbounded memory.
+	maxDimension = 16384
+	maxFrameSize = 1 << 28
+)
+
+var (
+	errNilStream              = errors.New("stream is nil")
+	errIncompleteFileHeader   = errors.New("incomplete file header")
+	errIncompleteFrameHeader  = errors.New("incomplete frame header")
+	errIncompleteFrameData    = errors.New("incomplete frame data")
+	errSignatureMismatch      = errors.New("Y4M signature mismatch")
+	errFrameSignatureMismatch = errors.New("Y4M frame signature mismatch")
+	errHeaderTooLong          = errors.New("Y4M header line too long")
+	errInvalidDimensions      = errors.New("invalid frame dimensions")
+	errFrameTooLarge          = errors.New("frame size too large")
+	errInvalidFrameRate       = errors.New("invalid frame rate")
+	errInvalidParameter       = errors.New("invalid header parameter")
+	errUnsupportedColorSpace  = errors.New("unsupported color space")
+)
+
+// Y4MFileHeader contains the stream parameters from a Y4M file header
+// https://wiki.multimedia.cx/index.php/YUV4MPEG2
+type Y4MFileHeader struct {
+	Width                int
+	Height               int
+	FrameRateNumerator   int
+	FrameRateDenominator int
+	Interlacing          string
+	AspectNumerator      int
+	AspectDenominator    int
+	ColorSpace           string
+	// Comments contains the X (extension) parameters without the leading X
+	Comments []string
+}
+
+// Y4MFrameHeader contains the parameters of a single Y4M frame
+type Y4MFrameHeader struct {
+	// Parameters contains the optional FRAME parameters as they appear in the stream
+	Parameters []string
+	// Duration of the frame derived from the stream frame rate
+	Duration time.Duration
+	// Index is the zero based position of the frame in the stream
+	Index uint64
+}
+
+// Y4MReader is used to read Y4M files and return raw frames.
+type Y4MReader struct {
+	stream    *bufio.Reader
+	frameSize int
+	duration  time.Duration
+	frameIdx  uint64
+}
+
+// NewWith returns a new Y4M reader and Y4M file header
+// with an io.Reader input.
+func NewWith(stream io.Reader) (*Y4MReader, *Y4MFileHeader, error) {
+	if stream == nil {
+		return nil, nil, errNilStream
+	}
+
+	reader := &Y4MReader{
+		stream: bufio.NewReader(stream),
+	}
+
+	header, err := reader.parseFileHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	frameSize, err := frameSizeFor(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader.frameSize = frameSize
+	reader.duration = time.Duration(
+		int64(time.Second) * int64(header.FrameRateDenominator) / int64(header.FrameRateNumerator),
+	)
+
+	return reader, header, nil
+}
+
+// FrameSize returns the size in bytes of a single raw frame.
+func (r *Y4MReader) FrameSize() int {
+	return r.frameSize
+}
+
+// ParseNextFrame reads from stream and returns the raw frame data, header,
+// and an error if there is incomplete frame data.
+// Returns io.EOF when no more frames are available.
+func (r *Y4MReader) ParseNextFrame() ([]byte, *Y4MFrameHeader, error) {
+	line, err := readHeaderLine(r.stream)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, nil, errIncompleteFrameHeader
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	fields := strings.Split(line, " ")
+	if fields[0] != y4mFrameHeaderSignature {
+		return nil, nil, errFrameSignatureMismatch
+	}
+
+	header := &Y4MFrameHeader{
+		Parameters: fields[1:],
+		Duration:   r.duration,
+		Index:      r.frameIdx,
+	}
+
+	payload := make([]byte, r.frameSize)
+	if _, err = io.ReadFull(r.stream, payload); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return nil, nil, errIncompleteFrameData
+		}
+
+		return nil, nil, err
+	}
+
+	r.frameIdx++
+
+	return payload, header, nil
+}
+
+// parseFileHeader reads the first line of the stream and returns
+// Y4M file header. This is always called before ParseNextFrame().
+func (r *Y4MReader) parseFileHeader() (*Y4MFileHeader, error) {
+	line, err := readHeaderLine(r.stream)
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return nil, errIncompleteFileHeader
+	} else if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(line, " ")
+	if fields[0] != y4mFileHeaderSignature {
+		return nil, errSignatureMismatch
+	}
+
+	// Defaults as documented by the YUV4MPEG2 format
+	header := &Y4MFileHeader{
+		Interlacing:       "?",
+		AspectNumerator:   0,
+		AspectDenominator: 0,
+		ColorSpace:        "420jpeg",
+	}
+
+	for _, field := range fields[1:] {
+		if len(field) == 0 {
+			continue
+		}
+
+		value := field[1:]
+		switch field[0] {
+		case 'W':
+			if header.Width, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("%w: %s", errInvalidParameter, field)
+			}
+		case 'H':
+			if header.Height, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("%w: %s", errInvalidParameter, field)
+			}
+		case 'F':
+			if header.FrameRateNumerator, header.FrameRateDenominator, err = parseRatio(value); err != nil {
+				return nil, fmt.Errorf("%w: %s", errInvalidParameter, field)
+			}
+		case 'A':
+			if header.AspectNumerator, header.AspectDenominator, err = parseRatio(value); err != nil {
+				return nil, fmt.Errorf("%w: %s", errInvalidParameter, field)
+			}
+		case 'I':
+			header.Interlacing = value
+		case 'C':
+			header.ColorSpace = value
+		case 'X':
+			header.Comments = append(header.Comments, value)
+		default:
+			return nil, fmt.Errorf("%w: %s", errInvalidParameter, field)
+		}
+	}
+
+	if header.Width <= 0 || header.Height <= 0 || header.Width > maxDimension || header.Height > maxDimension {
+		return nil, errInvalidDimensions
+	}
+	if header.FrameRateNumerator <= 0 || header.FrameRateDenominator <= 0 {
+		return nil, errInvalidFrameRate
+	}
+
+	return header, nil
+}
+
+// frameSizeFor computes the number of bytes of a raw frame for the
+// color space declared in the header, at most maxFrameSize.
+func frameSizeFor(header *Y4MFileHeader) (int, error) {
+	width, height := int64(header.Width), int64(header.Height)
+	luma := width * height
+	chromaWidth := (width + 1) / 2
+	chromaHeight := (height + 1) / 2
+
+	// High bit depth color spaces use two bytes per sample
+	colorSpace, bytesPerSample := header.ColorSpace, int64(1)
+	if idx := strings.IndexByte(colorSpace, 'p'); idx != -1 && strings.HasPrefix(colorSpace, "4") {
+		bitDepth, err := strconv.Atoi(colorSpace[idx+1:])
+		if err == nil {
+			if bitDepth > 8 {
+				bytesPerSample = 2
+			}
+			colorSpace = colorSpace[:idx]
+		}
+	}
+
+	var samples int64
+	switch colorSpace {
+	case "420", "420jpeg", "420paldv", "420mpeg2":
+		samples = luma + 2*chromaWidth*chromaHeight
+	case "422":
+		samples = luma + 2*chromaWidth*height
+	case "444":
+		samples = 3 * luma
+	case "444alpha":
+		samples = 4 * luma
+	case "mono":
+		samples = luma
+	default:
+		return 0, fmt.Errorf("%w: %s", errUnsupportedColorSpace, header.ColorSpace)
+	}
+
+	if samples*bytesPerSample > maxFrameSize {
+		return 0, fmt.Errorf("%w: %dx%d %s", errFrameTooLarge, header.Width, header.Height, header.ColorSpace)
+	}
+
+	return int(samples * bytesPerSample), nil
+}
+
+func parseRatio(value string) (int, int, error) {
+	numerator, denominator, found := strings.Cut(value, ":")
+	if !found {
+		return 0, 0, errInvalidParameter
+	}
+
+	n, err := strconv.Atoi(numerator)
+	if err != nil {
+		return 0, 0, err
+	}
+	d, err := strconv.Atoi(denominator)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return n, d, nil
+}
+
+// readHeaderLine reads a newline terminated header and returns it without
+// the newline. io.EOF is returned only if no bytes were read.
+func readHeaderLine(stream *bufio.Reader) (string, error) {
+	var line bytes.Buffer
+	for {
+		b, err := stream.ReadByte()
+		if errors.Is(err, io.EOF) {
+			if line.Len() == 0 {
+				return "", io.EOF
+			}
+
+			return "", io.ErrUnexpectedEOF
+		} else if err != nil {
+			return "", err
+		}
+
+		if b == '\n' {
+			return line.String(), nil
+		}
+
+		if line.Len() >= maxHeaderLength {
+			return "", errHeaderTooLong
+		}
+		line.WriteByte(b)
+	}
+}
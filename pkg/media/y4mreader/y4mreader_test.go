@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package y4mreader
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildY4MContainer(header string, frames ...[]byte) *bytes.Buffer {
+	y4m := []byte(header + "\n")
+	for _, frame := range frames {
+		y4m = append(y4m, []byte("FRAME\n")...)
+		y4m = append(y4m, frame...)
+	}
+
+	return bytes.NewBuffer(y4m)
+}
+
+func TestY4MReader_ParseValidFileHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, header, err := NewWith(buildY4MContainer("YUV4MPEG2 W4 H2 F30000:1001 Ip A1:1 C420jpeg XYSCSS=420JPEG"))
+	assert.NoError(err)
+	assert.NotNil(reader)
+
+	assert.Equal(4, header.Width)
+	assert.Equal(2, header.Height)
+	assert.Equal(30000, header.FrameRateNumerator)
+	assert.Equal(1001, header.FrameRateDenominator)
+	assert.Equal("p", header.Interlacing)
+	assert.Equal(1, header.AspectNumerator)
+	assert.Equal(1, header.AspectDenominator)
+	assert.Equal("420jpeg", header.ColorSpace)
+	assert.Equal([]string{"YSCSS=420JPEG"}, header.Comments)
+	assert.Equal(12, reader.FrameSize())
+}
+
+func TestY4MReader_ParseValidFrames(t *testing.T) {
+	assert := assert.New(t)
+
+	frame1 := bytes.Repeat([]byte{0x01}, 12)
+	frame2 := bytes.Repeat([]byte{0x02}, 12)
+
+	reader, _, err := NewWith(buildY4MContainer("YUV4MPEG2 W4 H2 F25:1", frame1, frame2))
+	assert.NoError(err)
+
+	payload, header, err := reader.ParseNextFrame()
+	assert.NoError(err)
+	assert.Equal(frame1, payload)
+	assert.Equal(40*time.Millisecond, header.Duration)
+	assert.Equal(uint64(0), header.Index)
+
+	payload, header, err = reader.ParseNextFrame()
+	assert.NoError(err)
+	assert.Equal(frame2, payload)
+	assert.Equal(uint64(1), header.Index)
+
+	_, _, err = reader.ParseNextFrame()
+	assert.ErrorIs(err, io.EOF)
+}
+
+func TestY4MReader_FrameSize(t *testing.T) {
+	for _, test := range []struct {
+		colorSpace string
+		size       int
+	}{
+		{"420", 24},
+		{"422", 32},
+		{"444", 48},
+		{"444alpha", 64},
+		{"mono", 16},
+		{"420p10", 48},
+	} {
+		reader, _, err := NewWith(buildY4MContainer("YUV4MPEG2 W4 H4 F30:1 C" + test.colorSpace))
+		assert.NoError(t, err, test.colorSpace)
+		assert.Equal(t, test.size, reader.FrameSize(), test.colorSpace)
+	}
+}
+
+func TestY4MReader_ParseIncompleteFrame(t *testing.T) {
+	reader, _, err := NewWith(buildY4MContainer("YUV4MPEG2 W4 H2 F30:1", []byte{0x01, 0x02}))
+	assert.NoError(t, err)
+
+	_, _, err = reader.ParseNextFrame()
+	assert.ErrorIs(t, err, errIncompleteFrameData)
+}
+
+func TestY4MReader_InvalidHeader(t *testing.T) {
+	for name, test := range map[string]struct {
+		stream io.Reader
+		err    error
+	}{
+		"nil stream":       {nil, errNilStream},
+		"empty":            {bytes.NewBuffer(nil), errIncompleteFileHeader},
+		"bad signature":    {buildY4MContainer("YUV4MPEG W4 H2 F30:1"), errSignatureMismatch},
+		"missing size":     {buildY4MContainer("YUV4MPEG2 F30:1"), errInvalidDimensions},
+		"too wide":         {buildY4MContainer("YUV4MPEG2 W16385 H2 F30:1"), errInvalidDimensions},
+		"too large":        {buildY4MContainer("YUV4MPEG2 W16384 H16384 F30:1 C444alpha"), errFrameTooLarge},
+		"missing rate":     {buildY4MContainer("YUV4MPEG2 W4 H2"), errInvalidFrameRate},
+		"bad parameter":    {buildY4MContainer("YUV4MPEG2 W4 H2 F30"), errInvalidParameter},
+		"bad color space":  {buildY4MContainer("YUV4MPEG2 W4 H2 F30:1 Cfoo"), errUnsupportedColorSpace},
+		"no newline":       {bytes.NewBufferString("YUV4MPEG2 W4 H2 F30:1"), errIncompleteFileHeader},
+		"bad frame header": {bytes.NewBufferString("YUV4MPEG2 W4 H2 F30:1\nFRAMX\n"), nil},
+	} {
+		reader, _, err := NewWith(test.stream)
+		if test.err == nil {
+			assert.NoError(t, err, name)
+			_, _, err = reader.ParseNextFrame()
+			assert.ErrorIs(t, err, errFrameSignatureMismatch, name)
+
+			continue
+		}
+		assert.ErrorIs(t, err, test.err, name)
+	}
+}
@@ -0,0 +1,444 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+// Package metrics exports the statistics of PeerConnections as metrics. A
+// Collector serves them in the Prometheus text exposition format as a
+// http.Handler, and as JSON as an expvar.Var, so no metrics library is
+// required:
+//
+//	collector := metrics.NewCollector()
+//	http.Handle("/metrics", collector)
+//	expvar.Publish("webrtc", collector)
+//
+//	collector.Add(peerConnection)
+//	defer collector.Remove(peerConnection)
+//
+// The metrics are read with GetStats every time they are scraped and are
+// labeled with the ID of the PeerConnection stats object. The histograms of
+// webrtc.PerformanceMetrics are exported as well once set with
+// SetPerformanceMetrics.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+)
+
+// minBitrateInterval is how long a stream has to be observed before its
+// bitrate is computed again, shorter scrape intervals reuse the last value.
+const minBitrateInterval = time.Second
+
+type metricType string
+
+const (
+	metricTypeCounter   metricType = "counter"
+	metricTypeGauge     metricType = "gauge"
+	metricTypeHistogram metricType = "histogram"
+)
+
+type family struct {
+	name       string
+	help       string
+	metricType metricType
+}
+
+var (
+	familyPeerConnections = family{
+		"webrtc_peer_connections",
+		"Number of PeerConnections by connection state.",
+		metricTypeGauge,
+	}
+	familyCandidatePairRTT = family{
+		"webrtc_ice_candidate_pair_rtt_seconds",
+		"Latest round trip time of the selected ICE candidate pair.",
+		metricTypeGauge,
+	}
+	familyReceivedBytes = family{
+		"webrtc_rtp_received_bytes_total",
+		"RTP payload bytes received per track.",
+		metricTypeCounter,
+	}
+	familyReceivedPackets = family{
+		"webrtc_rtp_received_packets_total",
+		"RTP packets received per track.",
+		metricTypeCounter,
+	}
+	familyReceiveBitrate = family{
+		"webrtc_rtp_receive_bitrate_bps",
+		"RTP payload bitrate received per track, in bits per second.",
+		metricTypeGauge,
+	}
+	familySentBytes = family{
+		"webrtc_rtp_sent_bytes_total",
+		"RTP payload bytes sent per encoding.",
+		metricTypeCounter,
+	}
+	familySentPackets = family{
+		"webrtc_rtp_sent_packets_total",
+		"RTP packets sent per encoding.",
+		metricTypeCounter,
+	}
+	familySendBitrate = family{
+		"webrtc_rtp_send_bitrate_bps",
+		"RTP payload bitrate sent per encoding, in bits per second.",
+		metricTypeGauge,
+	}
+	familyNACKReceived = family{
+		"webrtc_rtp_nack_received_total",
+		"NACK packets received from the remote peer per encoding.",
+		metricTypeCounter,
+	}
+	familyPLIReceived = family{
+		"webrtc_rtp_pli_received_total",
+		"PLI packets received from the remote peer per encoding.",
+		metricTypeCounter,
+	}
+	familyDataChannelBufferedAmount = family{
+		"webrtc_data_channel_buffered_amount_bytes",
+		"Bytes queued to be sent on a DataChannel.",
+		metricTypeGauge,
+	}
+	familyRTPReadLatency = family{
+		"webrtc_rtp_read_latency_seconds",
+		"Time from the arrival of a RTP packet until it is read from its track.",
+		metricTypeHistogram,
+	}
+	familyInterceptorWriteDuration = family{
+		"webrtc_interceptor_write_duration_seconds",
+		"Time a RTP packet written to a sender takes to go through the interceptors.",
+		metricTypeHistogram,
+	}
+	familySRTPWriteDuration = family{
+		"webrtc_srtp_write_duration_seconds",
+		"Time the SRTP encryption and send of a RTP packet takes.",
+		metricTypeHistogram,
+	}
+
+	families = []family{
+		familyPeerConnections,
+		familyCandidatePairRTT,
+		familyReceivedBytes,
+		familyReceivedPackets,
+		familyReceiveBitrate,
+		familySentBytes,
+		familySentPackets,
+		familySendBitrate,
+		familyNACKReceived,
+		familyPLIReceived,
+		familyDataChannelBufferedAmount,
+		familyRTPReadLatency,
+		familyInterceptorWriteDuration,
+		familySRTPWriteDuration,
+	}
+
+	connectionStates = []webrtc.PeerConnectionState{
+		webrtc.PeerConnectionStateNew,
+		webrtc.PeerConnectionStateConnecting,
+		webrtc.PeerConnectionStateConnected,
+		webrtc.PeerConnectionStateDisconnected,
+		webrtc.PeerConnectionStateFailed,
+		webrtc.PeerConnectionStateClosed,
+	}
+)
+
+type label struct {
+	name, value string
+}
+
+type sample struct {
+	family family
+	labels []label
+	value  float64
+	// suffix is appended to the family name, for the series of histograms.
+	suffix string
+}
+
+type bitrateState struct {
+	bytes   uint64
+	at      time.Time
+	bitrate float64
+}
+
+// Collector reads the statistics of the PeerConnections added to it. It
+// implements http.Handler and expvar.Var.
+type Collector struct {
+	mu              sync.Mutex
+	peerConnections map[*webrtc.PeerConnection]struct{}
+	bitrates        map[string]*bitrateState
+	performance     *webrtc.PerformanceMetrics
+	now             func() time.Time
+}
+
+// NewCollector creates a Collector without PeerConnections.
+func NewCollector() *Collector {
+	return &Collector{
+		peerConnections: map[*webrtc.PeerConnection]struct{}{},
+		bitrates:        map[string]*bitrateState{},
+		now:             time.Now,
+	}
+}
+
+// Add starts exporting the metrics of peerConnection.
+func (c *Collector) Add(peerConnection *webrtc.PeerConnection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.peerConnections[peerConnection] = struct{}{}
+}
+
+// Remove stops exporting the metrics of peerConnection. Closed
+// PeerConnections keep being exported until they are removed.
+func (c *Collector) Remove(peerConnection *webrtc.PeerConnection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.peerConnections, peerConnection)
+}
+
+// SetPerformanceMetrics exports the histograms of performance, which should
+// be passed to SettingEngine.SetPerformanceMetrics of the API of the
+// PeerConnections.
+func (c *Collector) SetPerformanceMetrics(performance *webrtc.PerformanceMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.performance = performance
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = writeText(w, c.gather())
+}
+
+// String returns the metrics as a JSON object mapping the metric names to
+// their labels and values, as expected of an expvar.Var.
+func (c *Collector) String() string {
+	type jsonSample struct {
+		Labels map[string]string `json:"labels"`
+		Value  float64           `json:"value"`
+	}
+
+	metrics := map[string][]jsonSample{}
+	for _, s := range c.gather() {
+		labels := map[string]string{}
+		for _, l := range s.labels {
+			labels[l.name] = l.value
+		}
+		name := s.family.name + s.suffix
+		metrics[name] = append(metrics[name], jsonSample{labels, s.value})
+	}
+
+	out, err := json.Marshal(metrics)
+	if err != nil {
+		return "{}"
+	}
+
+	return string(out)
+}
+
+func (c *Collector) gather() []sample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	states := map[webrtc.PeerConnectionState]int{}
+	samples := []sample{}
+	seen := map[string]struct{}{}
+	for peerConnection := range c.peerConnections {
+		states[peerConnection.ConnectionState()]++
+		samples = c.appendReport(samples, peerConnection.GetStats(), now, seen)
+	}
+
+	// Forget the bitrates of the streams that are gone
+	for id := range c.bitrates {
+		if _, ok := seen[id]; !ok {
+			delete(c.bitrates, id)
+		}
+	}
+
+	for _, state := range connectionStates {
+		samples = append(samples, sample{
+			family: familyPeerConnections,
+			labels: []label{{"state", state.String()}},
+			value:  float64(states[state]),
+		})
+	}
+
+	if c.performance != nil {
+		samples = appendHistogram(samples, familyRTPReadLatency, c.performance.RTPReadLatency.Snapshot())
+		samples = appendHistogram(samples, familyInterceptorWriteDuration, c.performance.InterceptorWriteDuration.Snapshot())
+		samples = appendHistogram(samples, familySRTPWriteDuration, c.performance.SRTPWriteDuration.Snapshot())
+	}
+
+	return samples
+}
+
+// appendHistogram appends the bucket, sum and count series of a histogram.
+func appendHistogram(samples []sample, f family, snapshot webrtc.HistogramSnapshot) []sample {
+	for i, bound := range snapshot.Bounds {
+		samples = append(samples, sample{
+			family: f,
+			labels: []label{{"le", strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)}},
+			value:  float64(snapshot.Counts[i]),
+			suffix: "_bucket",
+		})
+	}
+
+	return append(samples,
+		sample{family: f, labels: []label{{"le", "+Inf"}}, value: float64(snapshot.Count), suffix: "_bucket"},
+		sample{family: f, value: snapshot.Sum.Seconds(), suffix: "_sum"},
+		sample{family: f, value: float64(snapshot.Count), suffix: "_count"},
+	)
+}
+
+// appendReport appends the samples of the stats of a PeerConnection, the
+// keys of the bitrates it updates are added to seen.
+func (c *Collector) appendReport( //nolint:cyclop
+	samples []sample,
+	report webrtc.StatsReport,
+	now time.Time,
+	seen map[string]struct{},
+) []sample {
+	peerConnectionID := ""
+	for _, stats := range report {
+		if s, ok := stats.(webrtc.PeerConnectionStats); ok {
+			peerConnectionID = s.ID
+		}
+	}
+
+	ids := make([]string, 0, len(report))
+	for id := range report {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		switch stats := report[id].(type) {
+		case webrtc.TransportStats:
+			pair, ok := report[stats.SelectedCandidatePairID].(webrtc.ICECandidatePairStats)
+			if !ok {
+				continue
+			}
+			samples = append(samples, sample{
+				family: familyCandidatePairRTT,
+				labels: []label{{"peer_connection", peerConnectionID}},
+				value:  pair.CurrentRoundTripTime,
+			})
+		case webrtc.InboundRTPStreamStats:
+			labels := []label{
+				{"peer_connection", peerConnectionID},
+				{"kind", stats.Kind},
+				{"ssrc", strconv.FormatUint(uint64(stats.SSRC), 10)},
+				{"track", stats.TrackID},
+			}
+			key := peerConnectionID + "/" + stats.ID
+			seen[key] = struct{}{}
+			samples = append(samples,
+				sample{family: familyReceivedBytes, labels: labels, value: float64(stats.BytesReceived)},
+				sample{family: familyReceivedPackets, labels: labels, value: float64(stats.PacketsReceived)},
+				sample{family: familyReceiveBitrate, labels: labels, value: c.bitrate(key, stats.BytesReceived, now)},
+			)
+		case webrtc.OutboundRTPStreamStats:
+			labels := []label{
+				{"peer_connection", peerConnectionID},
+				{"kind", stats.Kind},
+				{"ssrc", strconv.FormatUint(uint64(stats.SSRC), 10)},
+				{"rid", stats.Rid},
+			}
+			key := peerConnectionID + "/" + stats.ID
+			seen[key] = struct{}{}
+			samples = append(samples,
+				sample{family: familySentBytes, labels: labels, value: float64(stats.BytesSent)},
+				sample{family: familySentPackets, labels: labels, value: float64(stats.PacketsSent)},
+				sample{family: familySendBitrate, labels: labels, value: c.bitrate(key, stats.BytesSent, now)},
+				sample{family: familyNACKReceived, labels: labels, value: float64(stats.NACKCount)},
+				sample{family: familyPLIReceived, labels: labels, value: float64(stats.PLICount)},
+			)
+		case webrtc.DataChannelStats:
+			samples = append(samples, sample{
+				family: familyDataChannelBufferedAmount,
+				labels: []label{
+					{"peer_connection", peerConnectionID},
+					{"label", stats.Label},
+					{"id", strconv.FormatInt(int64(stats.DataChannelIdentifier), 10)},
+				},
+				value: float64(stats.BufferedAmount),
+			})
+		}
+	}
+
+	return samples
+}
+
+// bitrate returns the bitrate of the stream since it was last computed.
+func (c *Collector) bitrate(key string, bytes uint64, now time.Time) float64 {
+	state, ok := c.bitrates[key]
+	if !ok {
+		c.bitrates[key] = &bitrateState{bytes: bytes, at: now}
+
+		return 0
+	}
+
+	elapsed := now.Sub(state.at)
+	if elapsed < minBitrateInterval {
+		return state.bitrate
+	}
+
+	if bytes >= state.bytes {
+		state.bitrate = float64(bytes-state.bytes) * 8 / elapsed.Seconds()
+	}
+	state.bytes = bytes
+	state.at = now
+
+	return state.bitrate
+}
+
+// writeText writes the samples in the Prometheus text exposition format,
+// grouped by family.
+func writeText(w io.Writer, samples []sample) error {
+	var b strings.Builder
+	for _, f := range families {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.metricType)
+		for _, s := range samples {
+			if s.family.name != f.name {
+				continue
+			}
+
+			b.WriteString(f.name + s.suffix)
+			if len(s.labels) != 0 {
+				b.WriteByte('{')
+				for i, l := range s.labels {
+					if i != 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", l.name, escapeLabelValue(l.value))
+				}
+				b.WriteByte('}')
+			}
+			fmt.Fprintf(&b, " %s\n", strconv.FormatFloat(s.value, 'g', -1, 64))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
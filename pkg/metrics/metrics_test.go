@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package metrics
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector_Report(t *testing.T) {
+	collector := NewCollector()
+	now := time.Unix(0, 0)
+
+	report := func(bytesReceived, bytesSent uint64) webrtc.StatsReport {
+		return webrtc.StatsReport{
+			"PeerConnection-1": webrtc.PeerConnectionStats{ID: "PeerConnection-1"},
+			"iceTransport":     webrtc.TransportStats{ID: "iceTransport", SelectedCandidatePairID: "pair"},
+			"pair":             webrtc.ICECandidatePairStats{ID: "pair", CurrentRoundTripTime: 0.025},
+			"InboundRTPStream-1": webrtc.InboundRTPStreamStats{
+				ID: "InboundRTPStream-1", SSRC: 1, Kind: "video", TrackID: "video\"1",
+				PacketsReceived: 10, BytesReceived: bytesReceived,
+			},
+			"OutboundRTPStream-2": webrtc.OutboundRTPStreamStats{
+				ID: "OutboundRTPStream-2", SSRC: 2, Kind: "audio",
+				PacketsSent: 20, BytesSent: bytesSent, NACKCount: 3, PLICount: 4,
+			},
+			"DataChannel-1": webrtc.DataChannelStats{
+				ID: "DataChannel-1", Label: "chat", DataChannelIdentifier: 1, BufferedAmount: 512,
+			},
+		}
+	}
+
+	collector.appendReport(nil, report(1000, 2000), now, map[string]struct{}{})
+	samples := collector.appendReport(nil, report(2000, 6000), now.Add(2*time.Second), map[string]struct{}{})
+
+	var out strings.Builder
+	assert.NoError(t, writeText(&out, samples))
+	text := out.String()
+
+	for _, line := range []string{
+		"# TYPE webrtc_rtp_received_bytes_total counter",
+		`webrtc_ice_candidate_pair_rtt_seconds{peer_connection="PeerConnection-1"} 0.025`,
+		`webrtc_rtp_received_bytes_total{peer_connection="PeerConnection-1",kind="video",ssrc="1",track="video\"1"} 2000`,
+		`webrtc_rtp_receive_bitrate_bps{peer_connection="PeerConnection-1",kind="video",ssrc="1",track="video\"1"} 4000`,
+		`webrtc_rtp_sent_bytes_total{peer_connection="PeerConnection-1",kind="audio",ssrc="2",rid=""} 6000`,
+		`webrtc_rtp_send_bitrate_bps{peer_connection="PeerConnection-1",kind="audio",ssrc="2",rid=""} 16000`,
+		`webrtc_rtp_nack_received_total{peer_connection="PeerConnection-1",kind="audio",ssrc="2",rid=""} 3`,
+		`webrtc_rtp_pli_received_total{peer_connection="PeerConnection-1",kind="audio",ssrc="2",rid=""} 4`,
+		`webrtc_data_channel_buffered_amount_bytes{peer_connection="PeerConnection-1",label="chat",id="1"} 512`,
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+}
+
+func TestCollector_Bitrate(t *testing.T) {
+	collector := NewCollector()
+	now := time.Unix(0, 0)
+
+	assert.Equal(t, 0.0, collector.bitrate("stream", 0, now))
+	assert.Equal(t, 8000.0, collector.bitrate("stream", 1000, now.Add(time.Second)))
+	// Scrapes closer than minBitrateInterval reuse the last bitrate
+	assert.Equal(t, 8000.0, collector.bitrate("stream", 1100, now.Add(1500*time.Millisecond)))
+	assert.Equal(t, 1600.0, collector.bitrate("stream", 1200, now.Add(2*time.Second)))
+}
+
+func TestCollector_PerformanceMetrics(t *testing.T) {
+	collector := NewCollector()
+	performance := webrtc.NewPerformanceMetrics()
+	collector.SetPerformanceMetrics(performance)
+
+	var out strings.Builder
+	assert.NoError(t, writeText(&out, collector.gather()))
+	text := out.String()
+
+	for _, line := range []string{
+		"# TYPE webrtc_rtp_read_latency_seconds histogram",
+		`webrtc_rtp_read_latency_seconds_bucket{le="1e-05"} 0`,
+		`webrtc_rtp_read_latency_seconds_bucket{le="+Inf"} 0`,
+		"webrtc_rtp_read_latency_seconds_sum 0",
+		"webrtc_srtp_write_duration_seconds_count 0",
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+
+	var metrics map[string][]struct {
+		Labels map[string]string `json:"labels"`
+		Value  float64           `json:"value"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(collector.String()), &metrics))
+	assert.Len(t, metrics["webrtc_interceptor_write_duration_seconds_bucket"], 17)
+	assert.Len(t, metrics["webrtc_interceptor_write_duration_seconds_count"], 1)
+}
+
+func TestCollector_PeerConnections(t *testing.T) {
+	collector := NewCollector()
+
+	peerConnection, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+	collector.Add(peerConnection)
+
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, recorder.Body.String(), "webrtc_peer_connections{state=\"new\"} 1\n")
+	assert.Contains(t, recorder.Body.String(), "webrtc_peer_connections{state=\"closed\"} 0\n")
+
+	var metrics map[string][]struct {
+		Labels map[string]string `json:"labels"`
+		Value  float64           `json:"value"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(collector.String()), &metrics))
+	assert.Len(t, metrics["webrtc_peer_connections"], 6)
+
+	assert.NoError(t, peerConnection.Close())
+	collector.Remove(peerConnection)
+
+	recorder = httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, recorder.Body.String(), "webrtc_peer_connections{state=\"new\"} 0\n")
+}
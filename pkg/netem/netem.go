@@ -0,0 +1,479 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package netem provides an interceptor that impairs RTP streams like a bad
+// network would: packets are lost, duplicated, reordered, delayed and limited
+// to a bandwidth. It is meant for testing how applications cope with loss,
+// the conditions can be set per direction and per SSRC.
+package netem
+
+import (
+	"container/heap"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+var errInvalidProbability = errors.New("netem: probabilities must be between 0 and 1")
+
+// Conditions describes the impairments applied to a stream.
+type Conditions struct {
+	// Loss is the probability a packet is dropped.
+	Loss float64
+	// Duplicate is the probability a packet is delivered twice.
+	Duplicate float64
+	// Reorder is the probability a packet is held back and delivered after the next one.
+	Reorder float64
+	// Delay is added to every packet.
+	Delay time.Duration
+	// Jitter is the largest random delay added on top of Delay.
+	Jitter time.Duration
+	// Bandwidth limits the stream to this many bits per second, packets queue
+	// up behind each other once it is exceeded. Zero is unlimited.
+	Bandwidth int
+}
+
+func (c Conditions) validate() error {
+	for _, probability := range []float64{c.Loss, c.Duplicate, c.Reorder} {
+		if probability < 0 || probability > 1 {
+			return errInvalidProbability
+		}
+	}
+
+	return nil
+}
+
+// delayed returns true if packets can't be delivered right away.
+func (c Conditions) delayed() bool {
+	return c.Delay != 0 || c.Jitter != 0 || c.Bandwidth != 0
+}
+
+// Direction selects the streams Conditions apply to.
+type Direction int
+
+const (
+	// DirectionOutbound applies to the RTP packets sent.
+	DirectionOutbound Direction = 1 << iota
+	// DirectionInbound applies to the RTP packets received.
+	DirectionInbound
+	// DirectionBoth applies to the RTP packets sent and received.
+	DirectionBoth = DirectionOutbound | DirectionInbound
+)
+
+// Option configures the netem interceptor.
+type Option func(*InterceptorFactory) error
+
+// WithConditions sets the Conditions of all streams of the direction that
+// don't have Conditions for their SSRC.
+func WithConditions(direction Direction, conditions Conditions) Option {
+	return func(f *InterceptorFactory) error {
+		if err := conditions.validate(); err != nil {
+			return err
+		}
+
+		if direction&DirectionOutbound != 0 {
+			f.outbound = conditions
+		}
+		if direction&DirectionInbound != 0 {
+			f.inbound = conditions
+		}
+
+		return nil
+	}
+}
+
+// WithSSRCConditions sets the Conditions of the stream of the direction with the SSRC.
+func WithSSRCConditions(direction Direction, ssrc uint32, conditions Conditions) Option {
+	return func(f *InterceptorFactory) error {
+		if err := conditions.validate(); err != nil {
+			return err
+		}
+
+		if direction&DirectionOutbound != 0 {
+			f.outboundSSRC[ssrc] = conditions
+		}
+		if direction&DirectionInbound != 0 {
+			f.inboundSSRC[ssrc] = conditions
+		}
+
+		return nil
+	}
+}
+
+// WithSeed seeds the random decisions, so a test sees the same impairments on every run.
+func WithSeed(seed int64) Option {
+	return func(f *InterceptorFactory) error {
+		f.seed = seed
+
+		return nil
+	}
+}
+
+// InterceptorFactory is a interceptor.Factory for the netem Interceptor.
+type InterceptorFactory struct {
+	outbound, inbound         Conditions
+	outboundSSRC, inboundSSRC map[uint32]Conditions
+	seed                      int64
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	factory := &InterceptorFactory{
+		outboundSSRC: map[uint32]Conditions{},
+		inboundSSRC:  map[uint32]Conditions{},
+		seed:         time.Now().UnixNano(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(factory); err != nil {
+			return nil, err
+		}
+	}
+
+	return factory, nil
+}
+
+// NewInterceptor constructs a new Interceptor.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	return &Interceptor{factory: f}, nil
+}
+
+// Interceptor applies the Conditions of the InterceptorFactory to RTP packets.
+// RTCP is not impaired.
+type Interceptor struct {
+	interceptor.NoOp
+	factory *InterceptorFactory
+	closed  atomic.Bool
+}
+
+func (i *Interceptor) conditions(ssrc uint32, defaults Conditions, bySSRC map[uint32]Conditions) Conditions {
+	if conditions, ok := bySSRC[ssrc]; ok {
+		return conditions
+	}
+
+	return defaults
+}
+
+func (i *Interceptor) newRand(ssrc uint32) *rand.Rand {
+	return rand.New(rand.NewSource(i.factory.seed + int64(ssrc))) //nolint:gosec // G404, not used for security
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream.
+func (i *Interceptor) BindLocalStream(
+	info *interceptor.StreamInfo,
+	writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	conditions := i.conditions(info.SSRC, i.factory.outbound, i.factory.outboundSSRC)
+	if conditions == (Conditions{}) {
+		return writer
+	}
+
+	stream := &outboundStream{
+		interceptor: i,
+		writer:      writer,
+		conditions:  conditions,
+		impairments: impairments{rand: i.newRand(info.SSRC)},
+	}
+
+	return interceptor.RTPWriterFunc(stream.write)
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+func (i *Interceptor) BindRemoteStream(
+	info *interceptor.StreamInfo,
+	reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	conditions := i.conditions(info.SSRC, i.factory.inbound, i.factory.inboundSSRC)
+	if conditions == (Conditions{}) {
+		return reader
+	}
+
+	stream := &inboundStream{
+		reader:      reader,
+		conditions:  conditions,
+		impairments: impairments{rand: i.newRand(info.SSRC)},
+		notify:      make(chan struct{}, 1),
+	}
+
+	return interceptor.RTPReaderFunc(stream.read)
+}
+
+// Close stops delivering the packets that are still delayed.
+func (i *Interceptor) Close() error {
+	i.closed.Store(true)
+
+	return nil
+}
+
+// impairments makes the random decisions and schedules packets.
+type impairments struct {
+	rand *rand.Rand
+	// lastDelivery is when the previous packet is delivered, the bandwidth
+	// limit queues packets behind it.
+	lastDelivery time.Time
+}
+
+func (m *impairments) happens(probability float64) bool {
+	return probability != 0 && m.rand.Float64() < probability
+}
+
+// deliveryTime returns when a packet of size bytes that arrived at now is delivered.
+func (m *impairments) deliveryTime(conditions Conditions, now time.Time, size int) time.Time {
+	delivery := now
+	if conditions.Bandwidth != 0 {
+		if m.lastDelivery.After(delivery) {
+			delivery = m.lastDelivery
+		}
+		delivery = delivery.Add(time.Duration(size) * 8 * time.Second / time.Duration(conditions.Bandwidth))
+		m.lastDelivery = delivery
+	}
+
+	delivery = delivery.Add(conditions.Delay)
+	if conditions.Jitter > 0 {
+		delivery = delivery.Add(time.Duration(m.rand.Int63n(int64(conditions.Jitter))))
+	}
+
+	return delivery
+}
+
+type outboundPacket struct {
+	header     rtp.Header
+	payload    []byte
+	attributes interceptor.Attributes
+}
+
+type outboundStream struct {
+	mu sync.Mutex
+
+	interceptor *Interceptor
+	writer      interceptor.RTPWriter
+	conditions  Conditions
+	impairments impairments
+	held        *outboundPacket
+}
+
+func (s *outboundStream) write(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Lost packets are reported as written, like the network would
+	if s.impairments.happens(s.conditions.Loss) {
+		return header.MarshalSize() + len(payload), nil
+	}
+
+	packet := &outboundPacket{
+		header:     header.Clone(),
+		payload:    append([]byte{}, payload...),
+		attributes: attributes,
+	}
+
+	packets := []*outboundPacket{packet}
+	if s.impairments.happens(s.conditions.Duplicate) {
+		packets = append(packets, packet)
+	}
+
+	switch {
+	case s.held != nil:
+		packets = append(packets, s.held)
+		s.held = nil
+	case s.impairments.happens(s.conditions.Reorder):
+		s.held = packet
+
+		return header.MarshalSize() + len(payload), nil
+	}
+
+	for _, p := range packets {
+		if err := s.send(p); err != nil {
+			return 0, err
+		}
+	}
+
+	return header.MarshalSize() + len(payload), nil
+}
+
+func (s *outboundStream) send(packet *outboundPacket) error {
+	if !s.conditions.delayed() {
+		_, err := s.writer.Write(&packet.header, packet.payload, packet.attributes)
+
+		return err
+	}
+
+	now := time.Now()
+	size := packet.header.MarshalSize() + len(packet.payload)
+	delay := s.impairments.deliveryTime(s.conditions, now, size).Sub(now)
+	time.AfterFunc(delay, func() {
+		if s.interceptor.closed.Load() {
+			return
+		}
+
+		// Errors of delayed packets are lost like the packets of a real network
+		_, _ = s.writer.Write(&packet.header, packet.payload, packet.attributes)
+	})
+
+	return nil
+}
+
+type inboundPacket struct {
+	data       []byte
+	attributes interceptor.Attributes
+	delivery   time.Time
+	// index keeps the packets delivered at the same time in order
+	index uint64
+}
+
+// inboundQueue is a heap of the packets ordered by their delivery time.
+type inboundQueue []*inboundPacket
+
+func (q inboundQueue) Len() int { return len(q) }
+
+func (q inboundQueue) Less(i, j int) bool {
+	if q[i].delivery.Equal(q[j].delivery) {
+		return q[i].index < q[j].index
+	}
+
+	return q[i].delivery.Before(q[j].delivery)
+}
+
+func (q inboundQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *inboundQueue) Push(x any) {
+	*q = append(*q, x.(*inboundPacket)) //nolint:forcetypeassert
+}
+
+func (q *inboundQueue) Pop() any {
+	old := *q
+	packet := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return packet
+}
+
+// inboundStream impairs the packets read by a goroutine and queues them until
+// they are delivered, the reader of the stream is never held up by the packets
+// it doesn't get yet.
+type inboundStream struct {
+	reader      interceptor.RTPReader
+	conditions  Conditions
+	readLoopRun sync.Once
+	// notify is signaled when a packet is queued or the read loop ends
+	notify chan struct{}
+
+	// Only used by the read loop
+	impairments impairments
+	held        *inboundPacket
+
+	mu     sync.Mutex
+	queue  inboundQueue
+	queued uint64
+	err    error
+}
+
+func (s *inboundStream) read(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+	s.readLoopRun.Do(func() {
+		go s.readLoop(len(b))
+	})
+
+	for {
+		var wait time.Duration
+		s.mu.Lock()
+		switch {
+		case len(s.queue) != 0:
+			if wait = time.Until(s.queue[0].delivery); wait <= 0 {
+				packet := heap.Pop(&s.queue).(*inboundPacket) //nolint:forcetypeassert
+				s.mu.Unlock()
+
+				return copy(b, packet.data), packet.attributes, nil
+			}
+		case s.err != nil:
+			err := s.err
+			s.mu.Unlock()
+
+			return 0, nil, err
+		}
+		s.mu.Unlock()
+
+		if wait == 0 {
+			<-s.notify
+
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.notify:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// readLoop reads the packets of the stream, impairs them and queues them
+// until the stream returns an error.
+func (s *inboundStream) readLoop(bufferSize int) {
+	buf := make([]byte, bufferSize)
+	for {
+		n, attributes, err := s.reader.Read(buf, make(interceptor.Attributes))
+		if err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+			s.signal()
+
+			return
+		}
+
+		if s.impairments.happens(s.conditions.Loss) {
+			continue
+		}
+
+		packet := &inboundPacket{data: append([]byte{}, buf[:n]...), attributes: attributes}
+		packets := []*inboundPacket{packet}
+		if s.impairments.happens(s.conditions.Duplicate) {
+			duplicate := *packet
+			packets = append(packets, &duplicate)
+		}
+
+		switch {
+		case s.held != nil:
+			packets = append(packets, s.held)
+			s.held = nil
+		case s.impairments.happens(s.conditions.Reorder):
+			s.held = packet
+
+			continue
+		}
+
+		s.queuePackets(packets)
+	}
+}
+
+func (s *inboundStream) queuePackets(packets []*inboundPacket) {
+	now := time.Now()
+	for _, packet := range packets {
+		packet.delivery = now
+		if s.conditions.delayed() {
+			packet.delivery = s.impairments.deliveryTime(s.conditions, now, len(packet.data))
+		}
+	}
+
+	s.mu.Lock()
+	for _, packet := range packets {
+		packet.index = s.queued
+		s.queued++
+		heap.Push(&s.queue, packet)
+	}
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *inboundStream) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package netem
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestInterceptor(t *testing.T, opts ...Option) *Interceptor {
+	t.Helper()
+
+	factory, err := NewInterceptor(append(opts, WithSeed(1))...)
+	assert.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	return i.(*Interceptor) //nolint:forcetypeassert
+}
+
+func writeSequenceNumbers(t *testing.T, writer interceptor.RTPWriter, count int) {
+	t.Helper()
+
+	for sequenceNumber := 0; sequenceNumber < count; sequenceNumber++ {
+		_, err := writer.Write(&rtp.Header{SequenceNumber: uint16(sequenceNumber)}, []byte{0x00}, nil)
+		assert.NoError(t, err)
+	}
+}
+
+func TestInvalidProbability(t *testing.T) {
+	_, err := NewInterceptor(WithConditions(DirectionBoth, Conditions{Loss: 1.5}))
+	assert.ErrorIs(t, err, errInvalidProbability)
+
+	_, err = NewInterceptor(WithSSRCConditions(DirectionInbound, 1, Conditions{Reorder: -1}))
+	assert.ErrorIs(t, err, errInvalidProbability)
+}
+
+func TestOutbound(t *testing.T) {
+	var written []uint16
+	writer := interceptor.RTPWriterFunc(func(header *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+		written = append(written, header.SequenceNumber)
+
+		return 0, nil
+	})
+
+	t.Run("Passthrough", func(t *testing.T) {
+		written = nil
+		i := newTestInterceptor(t, WithConditions(DirectionInbound, Conditions{Loss: 1}))
+		writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, writer), 3)
+		assert.Equal(t, []uint16{0, 1, 2}, written)
+	})
+
+	t.Run("Loss", func(t *testing.T) {
+		written = nil
+		i := newTestInterceptor(t, WithConditions(DirectionOutbound, Conditions{Loss: 1}))
+		writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, writer), 3)
+		assert.Empty(t, written)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		written = nil
+		i := newTestInterceptor(t, WithConditions(DirectionOutbound, Conditions{Duplicate: 1}))
+		writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, writer), 2)
+		assert.Equal(t, []uint16{0, 0, 1, 1}, written)
+	})
+
+	t.Run("Reorder", func(t *testing.T) {
+		written = nil
+		i := newTestInterceptor(t, WithConditions(DirectionOutbound, Conditions{Reorder: 1}))
+		writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, writer), 4)
+		assert.Equal(t, []uint16{1, 0, 3, 2}, written)
+	})
+
+	t.Run("SSRC", func(t *testing.T) {
+		written = nil
+		i := newTestInterceptor(t,
+			WithConditions(DirectionOutbound, Conditions{Loss: 1}),
+			WithSSRCConditions(DirectionOutbound, 2, Conditions{}),
+		)
+		writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, writer), 2)
+		writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 2}, writer), 2)
+		assert.Equal(t, []uint16{0, 1}, written)
+	})
+}
+
+func TestOutboundDelay(t *testing.T) {
+	written := make(chan uint16, 2)
+	writer := interceptor.RTPWriterFunc(func(header *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+		written <- header.SequenceNumber
+
+		return 0, nil
+	})
+
+	i := newTestInterceptor(t, WithConditions(DirectionOutbound, Conditions{Delay: 20 * time.Millisecond}))
+	start := time.Now()
+	writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, writer), 1)
+	assert.Equal(t, uint16(0), <-written)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	assert.NoError(t, i.Close())
+	writeSequenceNumbers(t, i.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, writer), 1)
+	time.Sleep(40 * time.Millisecond)
+	assert.Empty(t, written)
+}
+
+func TestInbound(t *testing.T) {
+	newReader := func(count int) interceptor.RTPReader {
+		sequenceNumber := 0
+
+		return interceptor.RTPReaderFunc(func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+			if sequenceNumber == count {
+				return 0, nil, io.EOF
+			}
+
+			packet := &rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: uint16(sequenceNumber)}}
+			sequenceNumber++
+			raw, err := packet.Marshal()
+			if err != nil {
+				return 0, nil, err
+			}
+
+			return copy(b, raw), nil, nil
+		})
+	}
+
+	readSequenceNumbers := func(reader interceptor.RTPReader) (sequenceNumbers []uint16) {
+		buf := make([]byte, 1500)
+		for {
+			n, _, err := reader.Read(buf, nil)
+			if err != nil {
+				return sequenceNumbers
+			}
+
+			packet := &rtp.Packet{}
+			assert.NoError(t, packet.Unmarshal(buf[:n]))
+			sequenceNumbers = append(sequenceNumbers, packet.SequenceNumber)
+		}
+	}
+
+	for _, test := range []struct {
+		name       string
+		conditions Conditions
+		expected   []uint16
+	}{
+		{"Passthrough", Conditions{}, []uint16{0, 1, 2, 3}},
+		{"Loss", Conditions{Loss: 1}, nil},
+		{"Duplicate", Conditions{Duplicate: 1}, []uint16{0, 0, 1, 1, 2, 2, 3, 3}},
+		{"Reorder", Conditions{Reorder: 1}, []uint16{1, 0, 3, 2}},
+		{"Bandwidth", Conditions{Bandwidth: 1_000_000}, []uint16{0, 1, 2, 3}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			i := newTestInterceptor(t, WithConditions(DirectionInbound, test.conditions))
+			reader := i.BindRemoteStream(&interceptor.StreamInfo{SSRC: 1}, newReader(4))
+			assert.Equal(t, test.expected, readSequenceNumbers(reader))
+		})
+	}
+}
+
+func TestInboundDelay(t *testing.T) {
+	packets := make(chan []byte, 2)
+	reader := interceptor.RTPReaderFunc(func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+		raw, ok := <-packets
+		if !ok {
+			return 0, nil, io.EOF
+		}
+
+		return copy(b, raw), nil, nil
+	})
+
+	i := newTestInterceptor(t, WithConditions(DirectionInbound, Conditions{Delay: 20 * time.Millisecond}))
+	stream := i.BindRemoteStream(&interceptor.StreamInfo{SSRC: 1}, reader)
+
+	start := time.Now()
+	packets <- []byte{0x01}
+	buf := make([]byte, 1500)
+	n, _, err := stream.Read(buf, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01}, buf[:n])
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	// Packets that arrive while one is delayed are delayed from their own arrival
+	packets <- []byte{0x02}
+	time.Sleep(10 * time.Millisecond)
+	start = time.Now()
+	packets <- []byte{0x03}
+	for _, expected := range []byte{0x02, 0x03} {
+		n, _, err = stream.Read(buf, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{expected}, buf[:n])
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	close(packets)
+	_, _, err = stream.Read(buf, nil)
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+// Package perfectnegotiation implements the perfect negotiation pattern of
+// the W3C WebRTC specification. Both peers renegotiate whenever
+// OnNegotiationNeeded fires, and when their offers cross the impolite peer
+// ignores the remote offer while the polite peer rolls its own back and
+// answers. Applications only relay the descriptions and candidates:
+//
+//	negotiator, err := perfectnegotiation.New(peerConnection, perfectnegotiation.Config{
+//		Polite:          polite,
+//		SendDescription: func(desc webrtc.SessionDescription) error { return signal(desc) },
+//		SendCandidate:   func(candidate webrtc.ICECandidateInit) error { return signal(candidate) },
+//	})
+//
+//	// For every message from the remote peer
+//	err = negotiator.HandleDescription(desc)
+//	err = negotiator.HandleCandidate(candidate)
+//
+// https://www.w3.org/TR/webrtc/#perfect-negotiation-example
+package perfectnegotiation
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/pion/webrtc/v4"
+)
+
+var (
+	errSendDescriptionNil = errors.New("Config.SendDescription is required")
+	errNoPendingOffer     = errors.New("no pending local offer to roll back")
+)
+
+// Config configures a Negotiator.
+type Config struct {
+	// Polite is true for exactly one of the two peers, it gives way when
+	// both peers send an offer at the same time.
+	Polite bool
+
+	// SendDescription sends a local offer or answer to the remote peer.
+	SendDescription func(desc webrtc.SessionDescription) error
+
+	// SendCandidate sends a local ICE candidate to the remote peer. If nil,
+	// candidates aren't trickled: the descriptions are sent once gathering
+	// is complete and carry all of them.
+	SendCandidate func(candidate webrtc.ICECandidateInit) error
+
+	// OnError is invoked with the errors of the negotiations started by
+	// OnNegotiationNeeded, which have no caller to return them to.
+	OnError func(err error)
+}
+
+// Negotiator negotiates a PeerConnection with the perfect negotiation
+// pattern. It sets the OnNegotiationNeeded handler of the PeerConnection, and
+// the OnICECandidate handler if SendCandidate is set.
+type Negotiator struct {
+	pc     *webrtc.PeerConnection
+	config Config
+
+	// mu serializes the negotiations, so an offer can't be created while a
+	// remote description is applied.
+	mu          sync.Mutex
+	ignoreOffer bool
+}
+
+// New creates a Negotiator for pc.
+func New(pc *webrtc.PeerConnection, config Config) (*Negotiator, error) {
+	if config.SendDescription == nil {
+		return nil, errSendDescriptionNil
+	}
+
+	n := &Negotiator{pc: pc, config: config}
+	pc.OnNegotiationNeeded(func() {
+		// The handler runs in the operations chain of the PeerConnection
+		go n.negotiate()
+	})
+	if config.SendCandidate != nil {
+		pc.OnICECandidate(func(candidate *webrtc.ICECandidate) {
+			if candidate == nil {
+				return
+			}
+
+			// Wait for the description the candidate belongs to be sent
+			n.mu.Lock()
+			err := config.SendCandidate(candidate.ToJSON())
+			n.mu.Unlock()
+			if err != nil {
+				n.handleError(err)
+			}
+		})
+	}
+
+	return n, nil
+}
+
+func (n *Negotiator) handleError(err error) {
+	if n.config.OnError != nil {
+		n.config.OnError(err)
+	}
+}
+
+// negotiate sends an offer, unless a negotiation is already in progress. It
+// is started again once the PeerConnection is back to stable if needed.
+func (n *Negotiator) negotiate() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.pc.SignalingState() != webrtc.SignalingStateStable {
+		return
+	}
+
+	offer, err := n.pc.CreateOffer(nil)
+	if err != nil {
+		n.handleError(err)
+
+		return
+	}
+	if err = n.setLocalDescription(offer); err != nil {
+		n.handleError(err)
+	}
+}
+
+// setLocalDescription applies desc and sends it to the remote peer.
+func (n *Negotiator) setLocalDescription(desc webrtc.SessionDescription) error {
+	var gatherComplete <-chan struct{}
+	if n.config.SendCandidate == nil {
+		gatherComplete = webrtc.GatheringCompletePromise(n.pc)
+	}
+
+	if err := n.pc.SetLocalDescription(desc); err != nil {
+		return err
+	}
+	if gatherComplete != nil {
+		<-gatherComplete
+	}
+
+	return n.config.SendDescription(*n.pc.LocalDescription())
+}
+
+// HandleDescription applies a description received from the remote peer.
+// An offer colliding with a local one is ignored by the impolite peer, the
+// polite peer rolls its own offer back and answers.
+func (n *Negotiator) HandleDescription(desc webrtc.SessionDescription) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	offerCollision := desc.Type == webrtc.SDPTypeOffer && n.pc.SignalingState() != webrtc.SignalingStateStable
+	n.ignoreOffer = !n.config.Polite && offerCollision
+	if n.ignoreOffer {
+		return nil
+	}
+
+	if offerCollision {
+		pending := n.pc.PendingLocalDescription()
+		if pending == nil {
+			return errNoPendingOffer
+		}
+		if err := n.pc.SetLocalDescription(webrtc.SessionDescription{
+			Type: webrtc.SDPTypeRollback,
+			SDP:  pending.SDP,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := n.pc.SetRemoteDescription(desc); err != nil {
+		return err
+	}
+	if desc.Type != webrtc.SDPTypeOffer {
+		return nil
+	}
+
+	answer, err := n.pc.CreateAnswer(nil)
+	if err != nil {
+		return err
+	}
+
+	return n.setLocalDescription(answer)
+}
+
+// HandleCandidate adds an ICE candidate received from the remote peer. The
+// candidates of an ignored offer fail to be added, the error is dropped.
+func (n *Negotiator) HandleCandidate(candidate webrtc.ICECandidateInit) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := n.pc.AddICECandidate(candidate); err != nil && !n.ignoreOffer {
+		return err
+	}
+
+	return nil
+}
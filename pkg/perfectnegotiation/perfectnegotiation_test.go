@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package perfectnegotiation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type message struct {
+	desc      *webrtc.SessionDescription
+	candidate *webrtc.ICECandidateInit
+}
+
+// newPeer creates a PeerConnection whose Negotiator sends to out, and relays
+// the messages of in to it until done is closed.
+func newPeer(
+	t *testing.T, polite bool, in <-chan message, out chan<- message, done <-chan struct{},
+) *webrtc.PeerConnection {
+	t.Helper()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+
+	negotiator, err := New(pc, Config{
+		Polite: polite,
+		SendDescription: func(desc webrtc.SessionDescription) error {
+			out <- message{desc: &desc}
+
+			return nil
+		},
+		SendCandidate: func(candidate webrtc.ICECandidateInit) error {
+			out <- message{candidate: &candidate}
+
+			return nil
+		},
+		OnError: func(err error) {
+			assert.NoError(t, err)
+		},
+	})
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case msg := <-in:
+				if msg.desc != nil {
+					assert.NoError(t, negotiator.HandleDescription(*msg.desc))
+				} else {
+					assert.NoError(t, negotiator.HandleCandidate(*msg.candidate))
+				}
+			}
+		}
+	}()
+
+	return pc
+}
+
+func TestNew_SendDescriptionNil(t *testing.T) {
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+
+	_, err = New(pc, Config{})
+	assert.ErrorIs(t, err, errSendDescriptionNil)
+	assert.NoError(t, pc.Close())
+}
+
+func TestNegotiator_Glare(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	toPolite, toImpolite := make(chan message, 100), make(chan message, 100)
+	done := make(chan struct{})
+	politePC := newPeer(t, true, toPolite, toImpolite, done)
+	impolitePC := newPeer(t, false, toImpolite, toPolite, done)
+
+	politeReceived, impoliteReceived := make(chan string, 1), make(chan string, 1)
+	politePC.OnDataChannel(func(d *webrtc.DataChannel) {
+		politeReceived <- d.Label()
+	})
+	impolitePC.OnDataChannel(func(d *webrtc.DataChannel) {
+		impoliteReceived <- d.Label()
+	})
+
+	// Both peers negotiate at once, their offers collide
+	_, err := politePC.CreateDataChannel("polite", nil)
+	assert.NoError(t, err)
+	_, err = impolitePC.CreateDataChannel("impolite", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "impolite", <-politeReceived)
+	assert.Equal(t, "polite", <-impoliteReceived)
+
+	close(done)
+	assert.NoError(t, politePC.Close())
+	assert.NoError(t, impolitePC.Close())
+}
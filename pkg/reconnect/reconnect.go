@@ -0,0 +1,423 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+// Package reconnect provides the offering side of a PeerConnection that
+// reconnects on its own. When the connection is disconnected or failed it
+// restarts ICE with an exponential backoff, and once the ICE restarts are
+// exhausted, or if the remote peer closed it, it replaces the PeerConnection
+// with a new one, adding the tracks and creating the DataChannels again:
+//
+//	peerConnection, err := reconnect.New(api, webrtc.Configuration{}, reconnect.Options{
+//		Signal: func(offer webrtc.SessionDescription, replaced bool) (webrtc.SessionDescription, error) {
+//			return exchange(offer, replaced)
+//		},
+//	})
+//
+//	dataChannel, err := peerConnection.CreateDataChannel("data", nil)
+//	err = peerConnection.Connect()
+package reconnect
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pion/logging"
+	"github.com/pion/webrtc/v4"
+)
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+	defaultMaxICERestarts = 3
+	defaultAttemptTimeout = 10 * time.Second
+)
+
+var errSignalNil = errors.New("Options.Signal is required")
+
+// Options configure a PeerConnection.
+type Options struct {
+	// Signal sends an offer to the remote peer and returns its answer. The
+	// offers carry all the ICE candidates. replaced is true when the offer is
+	// from a PeerConnection replacing the failed one, the remote peer then
+	// answers it with a new PeerConnection as well.
+	Signal func(offer webrtc.SessionDescription, replaced bool) (webrtc.SessionDescription, error)
+
+	// InitialBackoff is the delay before the first attempt, it doubles after
+	// every failed attempt up to MaxBackoff. They default to 1s and 30s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// MaxICERestarts is the number of ICE restarts attempted before the
+	// PeerConnection is replaced, it defaults to 3. If negative, the
+	// PeerConnection is replaced at once.
+	MaxICERestarts int
+
+	// AttemptTimeout is how long an attempt waits for the connection, it
+	// defaults to 10s.
+	AttemptTimeout time.Duration
+
+	// LoggerFactory creates the logger of the PeerConnection, it defaults to
+	// the default logger factory of pion/logging.
+	LoggerFactory logging.LoggerFactory
+}
+
+type dataChannelInit struct {
+	label string
+	init  *webrtc.DataChannelInit
+}
+
+// PeerConnection wraps the current webrtc.PeerConnection and replaces it when
+// the connection can't be restored. OnReconnected is invoked once the
+// connection is back, the application then uses the webrtc.PeerConnection
+// and the DataChannels returned by PeerConnection and DataChannel, which may
+// be new.
+//
+// The PeerConnection sets the OnConnectionStateChange handler of the
+// webrtc.PeerConnection it wraps, use its own OnConnectionStateChange instead.
+type PeerConnection struct {
+	api           *webrtc.API
+	configuration webrtc.Configuration
+	options       Options
+	log           logging.LeveledLogger
+
+	mu                             sync.Mutex
+	pc                             *webrtc.PeerConnection
+	tracks                         []webrtc.TrackLocal
+	dataChannelInits               []dataChannelInit
+	dataChannels                   map[string]*webrtc.DataChannel
+	reconnecting                   bool
+	closed                         bool
+	onReconnectedHandler           func()
+	onConnectionStateChangeHandler func(webrtc.PeerConnectionState)
+
+	connected chan struct{}
+	done      chan struct{}
+}
+
+// New creates a PeerConnection with the provided configuration against the
+// received API object, or the default API if api is nil. Add the tracks and
+// DataChannels, then call Connect.
+func New(api *webrtc.API, configuration webrtc.Configuration, options Options) (*PeerConnection, error) {
+	if options.Signal == nil {
+		return nil, errSignalNil
+	}
+	if api == nil {
+		api = webrtc.NewAPI()
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = defaultInitialBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaultMaxBackoff
+	}
+	if options.MaxICERestarts == 0 {
+		options.MaxICERestarts = defaultMaxICERestarts
+	}
+	if options.AttemptTimeout <= 0 {
+		options.AttemptTimeout = defaultAttemptTimeout
+	}
+	if options.LoggerFactory == nil {
+		options.LoggerFactory = logging.NewDefaultLoggerFactory()
+	}
+
+	r := &PeerConnection{
+		api:           api,
+		configuration: configuration,
+		options:       options,
+		log:           options.LoggerFactory.NewLogger("reconnect"),
+		dataChannels:  map[string]*webrtc.DataChannel{},
+		connected:     make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+
+	pc, err := api.NewPeerConnection(configuration)
+	if err != nil {
+		return nil, err
+	}
+	r.watch(pc)
+	r.pc = pc
+
+	return r, nil
+}
+
+// PeerConnection returns the current webrtc.PeerConnection.
+func (r *PeerConnection) PeerConnection() *webrtc.PeerConnection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.pc
+}
+
+// DataChannel returns the current DataChannel created with label, or nil.
+func (r *PeerConnection) DataChannel(label string) *webrtc.DataChannel {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.dataChannels[label]
+}
+
+// AddTrack adds a track to the webrtc.PeerConnection, and to the ones
+// replacing it.
+func (r *PeerConnection) AddTrack(track webrtc.TrackLocal) (*webrtc.RTPSender, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sender, err := r.pc.AddTrack(track)
+	if err != nil {
+		return nil, err
+	}
+	r.tracks = append(r.tracks, track)
+
+	return sender, nil
+}
+
+// CreateDataChannel creates a DataChannel on the webrtc.PeerConnection, and
+// on the ones replacing it. A negotiated DataChannel must be created by the
+// remote peer again as well when it answers a replacing PeerConnection.
+func (r *PeerConnection) CreateDataChannel(label string, init *webrtc.DataChannelInit) (*webrtc.DataChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dataChannel, err := r.pc.CreateDataChannel(label, init)
+	if err != nil {
+		return nil, err
+	}
+	r.dataChannelInits = append(r.dataChannelInits, dataChannelInit{label: label, init: init})
+	r.dataChannels[label] = dataChannel
+
+	return dataChannel, nil
+}
+
+// OnReconnected sets an event handler which is invoked once the connection
+// is back after it was lost.
+func (r *PeerConnection) OnReconnected(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onReconnectedHandler = f
+}
+
+// OnConnectionStateChange sets an event handler which is invoked when the
+// PeerConnectionState of the current webrtc.PeerConnection changes.
+func (r *PeerConnection) OnConnectionStateChange(f func(webrtc.PeerConnectionState)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onConnectionStateChangeHandler = f
+}
+
+// Connect performs the initial offer and answer.
+func (r *PeerConnection) Connect() error {
+	return r.negotiate(r.PeerConnection(), nil, false)
+}
+
+// Close stops reconnecting and closes the current webrtc.PeerConnection.
+func (r *PeerConnection) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+
+		return nil
+	}
+	r.closed = true
+	close(r.done)
+	pc := r.pc
+	r.mu.Unlock()
+
+	return pc.Close()
+}
+
+func (r *PeerConnection) watch(pc *webrtc.PeerConnection) {
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		r.handleConnectionStateChange(pc, state)
+	})
+}
+
+func (r *PeerConnection) handleConnectionStateChange(pc *webrtc.PeerConnection, state webrtc.PeerConnectionState) {
+	r.mu.Lock()
+	if pc != r.pc {
+		r.mu.Unlock()
+
+		return
+	}
+	// The PeerConnection is closed by the remote peer closing DTLS
+	lost := state == webrtc.PeerConnectionStateDisconnected || state == webrtc.PeerConnectionStateFailed ||
+		state == webrtc.PeerConnectionStateClosed
+	start := lost && !r.reconnecting && !r.closed
+	if start {
+		r.reconnecting = true
+	}
+	handler := r.onConnectionStateChangeHandler
+	r.mu.Unlock()
+
+	if state == webrtc.PeerConnectionStateConnected {
+		select {
+		case r.connected <- struct{}{}:
+		default:
+		}
+	}
+	if handler != nil {
+		handler(state)
+	}
+	if start {
+		go r.reconnect()
+	}
+}
+
+// reconnect attempts to reconnect with an exponential backoff until it
+// succeeds or the PeerConnection is closed.
+func (r *PeerConnection) reconnect() {
+	backoff := r.options.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-r.done:
+			return
+		case <-time.After(backoff):
+		}
+
+		pc := r.PeerConnection()
+		if pc.ConnectionState() == webrtc.PeerConnectionStateConnected {
+			// The connection came back on its own
+			r.mu.Lock()
+			r.reconnecting = false
+			r.mu.Unlock()
+
+			return
+		}
+
+		select {
+		case <-r.connected:
+		default:
+		}
+
+		var err error
+		if attempt <= r.options.MaxICERestarts && pc.ConnectionState() != webrtc.PeerConnectionStateClosed {
+			r.log.Infof("connection lost, restarting ICE (attempt %d)", attempt)
+			err = r.negotiate(pc, &webrtc.OfferOptions{ICERestart: true}, false)
+		} else {
+			r.log.Infof("connection lost, replacing the PeerConnection (attempt %d)", attempt)
+			err = r.replace()
+		}
+
+		if err == nil && r.waitConnected() {
+			r.mu.Lock()
+			r.reconnecting = false
+			handler := r.onReconnectedHandler
+			r.mu.Unlock()
+
+			if handler != nil {
+				handler()
+			}
+
+			return
+		}
+		if err != nil {
+			r.log.Warnf("failed to reconnect: %v", err)
+		}
+
+		backoff *= 2
+		if backoff > r.options.MaxBackoff {
+			backoff = r.options.MaxBackoff
+		}
+	}
+}
+
+// waitConnected returns true if the current webrtc.PeerConnection connects
+// within the AttemptTimeout.
+func (r *PeerConnection) waitConnected() bool {
+	timer := time.NewTimer(r.options.AttemptTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-r.connected:
+		return true
+	case <-timer.C:
+	case <-r.done:
+	}
+
+	return false
+}
+
+// replace closes the current webrtc.PeerConnection and negotiates a new one
+// with the same tracks and DataChannels.
+func (r *PeerConnection) replace() error {
+	pc, err := r.api.NewPeerConnection(r.configuration)
+	if err != nil {
+		return err
+	}
+	r.watch(pc)
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+
+		return pc.Close()
+	}
+	old := r.pc
+	r.pc = pc
+	r.mu.Unlock()
+
+	if err = old.Close(); err != nil {
+		r.log.Warnf("failed to close the replaced PeerConnection: %v", err)
+	}
+
+	r.mu.Lock()
+	for _, track := range r.tracks {
+		if _, err = pc.AddTrack(track); err != nil {
+			r.mu.Unlock()
+
+			return err
+		}
+	}
+	for _, d := range r.dataChannelInits {
+		var dataChannel *webrtc.DataChannel
+		if dataChannel, err = pc.CreateDataChannel(d.label, d.init); err != nil {
+			r.mu.Unlock()
+
+			return err
+		}
+		r.dataChannels[d.label] = dataChannel
+	}
+	r.mu.Unlock()
+
+	return r.negotiate(pc, nil, true)
+}
+
+// negotiate creates an offer, waits for the ICE candidates and applies the
+// answer returned by Signal. If there is no answer the offer is rolled back,
+// so the next attempt starts from stable.
+func (r *PeerConnection) negotiate(pc *webrtc.PeerConnection, options *webrtc.OfferOptions, replaced bool) error {
+	offer, err := pc.CreateOffer(options)
+	if err != nil {
+		return err
+	}
+
+	gatherComplete := webrtc.GatheringCompletePromise(pc)
+	if err = pc.SetLocalDescription(offer); err != nil {
+		return err
+	}
+
+	select {
+	case <-gatherComplete:
+	case <-r.done:
+		return webrtc.ErrConnectionClosed
+	}
+
+	answer, err := r.options.Signal(*pc.LocalDescription(), replaced)
+	if err == nil {
+		err = pc.SetRemoteDescription(answer)
+	}
+	if err != nil {
+		if rollbackErr := pc.SetLocalDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeRollback}); rollbackErr != nil {
+			r.log.Warnf("failed to roll back the offer: %v", rollbackErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package reconnect
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_SignalNil(t *testing.T) {
+	_, err := New(nil, webrtc.Configuration{}, Options{})
+	assert.ErrorIs(t, err, errSignalNil)
+}
+
+func TestPeerConnection_Replace(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := webrtc.SettingEngine{}
+	settingEngine.SetICETimeouts(time.Second, 2*time.Second, 200*time.Millisecond)
+	api := webrtc.NewAPI(webrtc.WithSettingEngine(settingEngine))
+
+	negotiated := true
+	id := uint16(0)
+	dataChannelInit := &webrtc.DataChannelInit{Negotiated: &negotiated, ID: &id}
+
+	var (
+		mu       sync.Mutex
+		answerPC *webrtc.PeerConnection
+	)
+	received := make(chan string, 10)
+	signal := func(offer webrtc.SessionDescription, replaced bool) (webrtc.SessionDescription, error) {
+		pc, err := api.NewPeerConnection(webrtc.Configuration{})
+		if err != nil {
+			return webrtc.SessionDescription{}, err
+		}
+		dataChannel, err := pc.CreateDataChannel("data", dataChannelInit)
+		if err != nil {
+			return webrtc.SessionDescription{}, err
+		}
+		dataChannel.OnMessage(func(msg webrtc.DataChannelMessage) {
+			received <- string(msg.Data)
+		})
+
+		if err = pc.SetRemoteDescription(offer); err != nil {
+			return webrtc.SessionDescription{}, err
+		}
+		answer, err := pc.CreateAnswer(nil)
+		if err != nil {
+			return webrtc.SessionDescription{}, err
+		}
+		gatherComplete := webrtc.GatheringCompletePromise(pc)
+		if err = pc.SetLocalDescription(answer); err != nil {
+			return webrtc.SessionDescription{}, err
+		}
+		<-gatherComplete
+
+		mu.Lock()
+		answerPC = pc
+		mu.Unlock()
+
+		return *pc.LocalDescription(), nil
+	}
+
+	reconnecting, err := New(api, webrtc.Configuration{}, Options{
+		Signal:         signal,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxICERestarts: -1,
+		AttemptTimeout: 5 * time.Second,
+	})
+	assert.NoError(t, err)
+
+	firstDataChannel, err := reconnecting.CreateDataChannel("data", dataChannelInit)
+	assert.NoError(t, err)
+
+	connected := make(chan struct{}, 1)
+	reconnecting.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		if state == webrtc.PeerConnectionStateConnected {
+			select {
+			case connected <- struct{}{}:
+			default:
+			}
+		}
+	})
+	reconnected := make(chan struct{})
+	reconnecting.OnReconnected(func() {
+		close(reconnected)
+	})
+
+	firstPC := reconnecting.PeerConnection()
+	assert.NoError(t, reconnecting.Connect())
+	<-connected
+
+	// The remote peer goes away and comes back with a new PeerConnection
+	mu.Lock()
+	assert.NoError(t, answerPC.Close())
+	mu.Unlock()
+	<-reconnected
+
+	assert.NotEqual(t, firstPC, reconnecting.PeerConnection())
+	dataChannel := reconnecting.DataChannel("data")
+	assert.NotEqual(t, firstDataChannel, dataChannel)
+
+	opened := make(chan struct{})
+	var openOnce sync.Once
+	dataChannel.OnOpen(func() {
+		openOnce.Do(func() { close(opened) })
+	})
+	if dataChannel.ReadyState() == webrtc.DataChannelStateOpen {
+		openOnce.Do(func() { close(opened) })
+	}
+	<-opened
+	assert.NoError(t, dataChannel.SendText("hello"))
+	assert.Equal(t, "hello", <-received)
+
+	assert.NoError(t, reconnecting.Close())
+	mu.Lock()
+	assert.NoError(t, answerPC.Close())
+	mu.Unlock()
+}
+
+func TestPeerConnection_SignalFailed(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	errSignal := errors.New("signaling server unreachable")
+	reconnecting, err := New(nil, webrtc.Configuration{}, Options{
+		Signal: func(webrtc.SessionDescription, bool) (webrtc.SessionDescription, error) {
+			return webrtc.SessionDescription{}, errSignal
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = reconnecting.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	// The unanswered offer is rolled back
+	assert.ErrorIs(t, reconnecting.Connect(), errSignal)
+	assert.Equal(t, webrtc.SignalingStateStable, reconnecting.PeerConnection().SignalingState())
+	assert.Nil(t, reconnecting.PeerConnection().PendingLocalDescription())
+
+	assert.NoError(t, reconnecting.Close())
+}
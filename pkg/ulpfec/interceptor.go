@@ -0,0 +1,279 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package ulpfec
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media/red"
+)
+
+// AttributeREDPayloadType is the StreamInfo attribute holding the payload type
+// of the video RED codec, as uint8. Streams without it, or without
+// PayloadTypeForwardErrorCorrection, are passed through unchanged.
+const AttributeREDPayloadType = "ulpfec.redPayloadType"
+
+// AttributeRecovered is set to true in the Attributes returned with a media
+// packet that was lost and recovered from an ULPFEC packet.
+const AttributeRecovered = "ulpfec.recovered"
+
+const (
+	defaultNumMediaPackets = 5
+	receiveHistorySize     = 128
+)
+
+var errInvalidNumMediaPackets = errors.New("ulpfec: number of media packets must be between 1 and 16")
+
+// Option configures the ULPFEC interceptor.
+type Option func(*Interceptor) error
+
+// NumMediaPackets sets how many media packets each ULPFEC packet protects. The
+// overhead of FEC is 1/n, fewer packets protect against more loss. Defaults to 5.
+func NumMediaPackets(n int) Option {
+	return func(i *Interceptor) error {
+		if n < 1 || n > MaxProtectedPackets {
+			return errInvalidNumMediaPackets
+		}
+		i.numMediaPackets = n
+
+		return nil
+	}
+}
+
+// InterceptorFactory is a interceptor.Factory for the ULPFEC Interceptor.
+type InterceptorFactory struct {
+	opts []Option
+}
+
+// NewInterceptor returns a new InterceptorFactory.
+func NewInterceptor(opts ...Option) (*InterceptorFactory, error) {
+	return &InterceptorFactory{opts: opts}, nil
+}
+
+// NewInterceptor constructs a new Interceptor.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	i := &Interceptor{numMediaPackets: defaultNumMediaPackets}
+	for _, opt := range f.opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// Interceptor sends outgoing media inside RED followed by an ULPFEC packet
+// every few media packets, and unwraps incoming RED recovering lost media
+// packets from the ULPFEC packets. The sequence numbers of outgoing packets
+// are shifted to make room for the ULPFEC packets, it should be registered
+// after the interceptors that keep state per sequence number like NACK.
+type Interceptor struct {
+	interceptor.NoOp
+	numMediaPackets int
+}
+
+// BindLocalStream lets you modify any outgoing RTP packets. It is called once for per LocalStream.
+func (i *Interceptor) BindLocalStream(
+	info *interceptor.StreamInfo,
+	writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	redPayloadType, ok := info.Attributes.Get(AttributeREDPayloadType).(uint8)
+	if !ok || info.PayloadTypeForwardErrorCorrection == 0 {
+		return writer
+	}
+
+	stream := &localStream{
+		writer:          writer,
+		redPayloadType:  redPayloadType,
+		fecPayloadType:  info.PayloadTypeForwardErrorCorrection,
+		numMediaPackets: i.numMediaPackets,
+	}
+
+	return interceptor.RTPWriterFunc(stream.write)
+}
+
+// BindRemoteStream lets you modify any incoming RTP packets. It is called once for per RemoteStream.
+func (i *Interceptor) BindRemoteStream(
+	info *interceptor.StreamInfo,
+	reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	redPayloadType, ok := info.Attributes.Get(AttributeREDPayloadType).(uint8)
+	if !ok || info.PayloadTypeForwardErrorCorrection == 0 {
+		return reader
+	}
+
+	stream := &remoteStream{
+		reader:         reader,
+		redPayloadType: redPayloadType,
+		fecPayloadType: info.PayloadTypeForwardErrorCorrection,
+	}
+
+	return interceptor.RTPReaderFunc(stream.read)
+}
+
+type localStream struct {
+	mu sync.Mutex
+
+	writer                         interceptor.RTPWriter
+	redPayloadType, fecPayloadType uint8
+	numMediaPackets                int
+
+	// sequenceNumberOffset is the number of ULPFEC packets sent so far.
+	sequenceNumberOffset uint16
+	protected            [][]byte
+}
+
+func (s *localStream) write(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mediaHeader := header.Clone()
+	mediaHeader.SequenceNumber += s.sequenceNumberOffset
+
+	// Padding only packets carry nothing worth protecting
+	if header.Padding {
+		return s.writer.Write(&mediaHeader, payload, attributes)
+	}
+
+	media, err := (&rtp.Packet{Header: mediaHeader, Payload: payload}).Marshal()
+	if err != nil {
+		return 0, err
+	}
+	s.protected = append(s.protected, media)
+
+	redHeader := mediaHeader.Clone()
+	redHeader.PayloadType = s.redPayloadType
+	n, err := s.writer.Write(&redHeader, append([]byte{header.PayloadType}, payload...), attributes)
+	if err != nil || len(s.protected) < s.numMediaPackets {
+		return n, err
+	}
+
+	fec, err := Protect(s.protected)
+	s.protected = s.protected[:0]
+	if err != nil {
+		return n, err
+	}
+
+	s.sequenceNumberOffset++
+	fecHeader := rtp.Header{
+		Version:        2,
+		PayloadType:    s.redPayloadType,
+		SequenceNumber: mediaHeader.SequenceNumber + 1,
+		Timestamp:      mediaHeader.Timestamp,
+		SSRC:           mediaHeader.SSRC,
+	}
+	_, err = s.writer.Write(&fecHeader, append([]byte{s.fecPayloadType}, fec...), attributes)
+
+	return n, err
+}
+
+type receivedPacket struct {
+	sequenceNumber uint16
+	packet         []byte
+}
+
+type remoteStream struct {
+	mu sync.Mutex
+
+	reader                         interceptor.RTPReader
+	redPayloadType, fecPayloadType uint8
+
+	history   [receiveHistorySize]receivedPacket
+	recovered [][]byte
+}
+
+func (s *remoteStream) read(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if len(s.recovered) != 0 {
+			packet := s.recovered[0]
+			s.recovered = s.recovered[1:]
+
+			return s.copyPacket(b, packet, interceptor.Attributes{AttributeRecovered: true})
+		}
+
+		n, attributes, err := s.reader.Read(b, a)
+		if err != nil {
+			return n, attributes, err
+		}
+
+		packet := &rtp.Packet{}
+		if err = packet.Unmarshal(b[:n]); err != nil {
+			return 0, nil, err
+		}
+
+		if packet.PayloadType != s.redPayloadType {
+			s.store(packet.SequenceNumber, b[:n])
+
+			return n, attributes, nil
+		}
+
+		blocks, err := red.Unmarshal(packet.Payload)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		primary := blocks[len(blocks)-1]
+		if primary.PayloadType == s.fecPayloadType {
+			s.recover(primary.Payload, packet.SSRC)
+
+			continue
+		}
+
+		packet.PayloadType = primary.PayloadType
+		packet.Payload = primary.Payload
+		media, err := packet.Marshal()
+		if err != nil {
+			return 0, nil, err
+		}
+		s.store(packet.SequenceNumber, media)
+
+		return s.copyPacket(b, media, attributes)
+	}
+}
+
+func (s *remoteStream) copyPacket(
+	b, packet []byte,
+	attributes interceptor.Attributes,
+) (int, interceptor.Attributes, error) {
+	if len(b) < len(packet) {
+		return 0, nil, io.ErrShortBuffer
+	}
+
+	return copy(b, packet), attributes, nil
+}
+
+func (s *remoteStream) store(sequenceNumber uint16, packet []byte) {
+	s.history[sequenceNumber%receiveHistorySize] = receivedPacket{
+		sequenceNumber: sequenceNumber,
+		packet:         append([]byte{}, packet...),
+	}
+}
+
+func (s *remoteStream) lookup(sequenceNumber uint16) []byte {
+	if received := s.history[sequenceNumber%receiveHistorySize]; received.sequenceNumber == sequenceNumber {
+		return received.packet
+	}
+
+	return nil
+}
+
+// recover restores the packet protected by fec if it is the only one missing.
+// Losses ULPFEC can't repair are left to NACK.
+func (s *remoteStream) recover(fec []byte, ssrc uint32) {
+	packet, err := Recover(fec, ssrc, s.lookup)
+	if err != nil {
+		return
+	}
+
+	s.store(binary.BigEndian.Uint16(packet[2:]), packet)
+	s.recovered = append(s.recovered, packet)
+}
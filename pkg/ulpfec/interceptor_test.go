@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package ulpfec
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptor(t *testing.T) {
+	factory, err := NewInterceptor(NumMediaPackets(3))
+	assert.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	info := &interceptor.StreamInfo{
+		SSRC:                              0x1234,
+		PayloadType:                       96,
+		PayloadTypeForwardErrorCorrection: 117,
+		Attributes:                        interceptor.Attributes{},
+	}
+	info.Attributes.Set(AttributeREDPayloadType, uint8(116))
+
+	sent := [][]byte{}
+	writer := i.BindLocalStream(info, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+			packet, err := (&rtp.Packet{Header: *header, Payload: payload}).Marshal()
+			sent = append(sent, packet)
+
+			return len(packet), err
+		},
+	))
+
+	media := []*rtp.Packet{}
+	for seq := uint16(10); seq < 13; seq++ {
+		packet := &rtp.Packet{
+			Header: rtp.Header{
+				Version: 2, PayloadType: 96, SequenceNumber: seq, Timestamp: 3000, SSRC: 0x1234, Marker: seq == 12,
+			},
+			Payload: []byte{0xAA, byte(seq)},
+		}
+		media = append(media, packet)
+
+		_, err = writer.Write(&packet.Header, packet.Payload, interceptor.Attributes{})
+		assert.NoError(t, err)
+	}
+
+	// Three media packets in RED followed by the ULPFEC packet
+	assert.Len(t, sent, 4)
+	for i, raw := range sent {
+		packet := &rtp.Packet{}
+		assert.NoError(t, packet.Unmarshal(raw))
+		assert.Equal(t, uint8(116), packet.PayloadType)
+		assert.Equal(t, uint16(10+i), packet.SequenceNumber) //nolint:gosec // G115
+	}
+
+	// Media written after the ULPFEC packet is shifted by one
+	next := &rtp.Packet{
+		Header:  rtp.Header{Version: 2, PayloadType: 96, SequenceNumber: 13, Timestamp: 6000, SSRC: 0x1234},
+		Payload: []byte{0xBB},
+	}
+	_, err = writer.Write(&next.Header, next.Payload, interceptor.Attributes{})
+	assert.NoError(t, err)
+	assert.Len(t, sent, 5)
+
+	// Lose the second media packet on the way
+	received := [][]byte{sent[0], sent[2], sent[3], sent[4]}
+	reader := i.BindRemoteStream(info, interceptor.RTPReaderFunc(
+		func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+			if len(received) == 0 {
+				return 0, nil, io.EOF
+			}
+
+			n := copy(b, received[0])
+			received = received[1:]
+
+			return n, interceptor.Attributes{}, nil
+		},
+	))
+
+	sequenceNumbers := []uint16{}
+	buf := make([]byte, 1500)
+	for {
+		n, attributes, err := reader.Read(buf, interceptor.Attributes{})
+		if err != nil {
+			assert.ErrorIs(t, err, io.EOF)
+
+			break
+		}
+
+		packet := &rtp.Packet{}
+		assert.NoError(t, packet.Unmarshal(buf[:n]))
+		assert.Equal(t, uint8(96), packet.PayloadType)
+		assert.Equal(t, packet.SequenceNumber == 11, attributes.Get(AttributeRecovered) == true)
+		if packet.SequenceNumber < 13 {
+			assert.Equal(t, media[packet.SequenceNumber-10].Payload, packet.Payload)
+			assert.Equal(t, media[packet.SequenceNumber-10].Marker, packet.Marker)
+		}
+		sequenceNumbers = append(sequenceNumbers, packet.SequenceNumber)
+	}
+
+	assert.Equal(t, []uint16{10, 12, 11, 14}, sequenceNumbers)
+}
+
+func TestInterceptorPassthrough(t *testing.T) {
+	factory, err := NewInterceptor()
+	assert.NoError(t, err)
+
+	i, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	info := &interceptor.StreamInfo{SSRC: 0x1234, PayloadType: 111, Attributes: interceptor.Attributes{}}
+	written := 0
+	writer := i.BindLocalStream(info, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, _ []byte, _ interceptor.Attributes) (int, error) {
+			assert.Equal(t, uint8(111), header.PayloadType)
+			written++
+
+			return 0, nil
+		},
+	))
+
+	_, err = writer.Write(&rtp.Header{PayloadType: 111}, []byte{0x01}, interceptor.Attributes{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, written)
+
+	invalid, err := NewInterceptor(NumMediaPackets(17))
+	assert.NoError(t, err)
+	_, err = invalid.NewInterceptor("")
+	assert.ErrorIs(t, err, errInvalidNumMediaPackets)
+}
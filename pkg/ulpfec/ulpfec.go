@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package ulpfec implements the generic forward error correction defined in
+// RFC 5109 (ULPFEC), sent inside RED (RFC 2198) like browsers do when the red
+// and ulpfec video codecs are negotiated. An ULPFEC packet is the XOR of up to
+// 16 media packets, any one of them can be recovered from it and the others.
+package ulpfec
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	rtpHeaderLength   = 12
+	fecHeaderLength   = 10
+	levelHeaderLength = 4
+
+	// MaxProtectedPackets is the largest number of media packets one ULPFEC
+	// packet can protect with the short mask.
+	MaxProtectedPackets = 16
+)
+
+var (
+	errNoPackets         = errors.New("ulpfec: no packets to protect")
+	errTooManyPackets    = errors.New("ulpfec: packets span more than 16 sequence numbers")
+	errShortPacket       = errors.New("ulpfec: packet is too short")
+	errLongMask          = errors.New("ulpfec: long masks are not supported")
+	errNothingToRecover  = errors.New("ulpfec: no protected packet is missing")
+	errCannotRecover     = errors.New("ulpfec: more than one protected packet is missing")
+	errInvalidProtection = errors.New("ulpfec: recovered length exceeds protection length")
+)
+
+// Protect returns the ULPFEC payload protecting the marshaled RTP packets.
+// The sequence numbers of the packets must lie within 16 of the first one.
+func Protect(packets [][]byte) ([]byte, error) {
+	if len(packets) == 0 {
+		return nil, errNoPackets
+	}
+
+	var (
+		base             uint16
+		mask             uint16
+		protectionLength int
+	)
+	for i, packet := range packets {
+		if len(packet) < rtpHeaderLength {
+			return nil, errShortPacket
+		}
+
+		sequenceNumber := binary.BigEndian.Uint16(packet[2:])
+		if i == 0 {
+			base = sequenceNumber
+		}
+		offset := sequenceNumber - base
+		if offset >= MaxProtectedPackets {
+			return nil, errTooManyPackets
+		}
+		mask |= 0x8000 >> offset
+
+		if length := len(packet) - rtpHeaderLength; length > protectionLength {
+			protectionLength = length
+		}
+	}
+
+	fec := make([]byte, fecHeaderLength+levelHeaderLength+protectionLength)
+	for _, packet := range packets {
+		xorHeader(fec, packet)
+		xorBytes(fec[fecHeaderLength+levelHeaderLength:], packet[rtpHeaderLength:])
+	}
+
+	// E and L are zero, the short mask covers 16 packets.
+	fec[0] &= 0x3F
+	binary.BigEndian.PutUint16(fec[2:], base)
+	binary.BigEndian.PutUint16(fec[fecHeaderLength:], uint16(protectionLength)) //nolint:gosec // G115
+	binary.BigEndian.PutUint16(fec[fecHeaderLength+2:], mask)
+
+	return fec, nil
+}
+
+// ProtectedSequenceNumbers returns the sequence numbers of the media packets
+// the ULPFEC payload protects.
+func ProtectedSequenceNumbers(fec []byte) ([]uint16, error) {
+	base, mask, err := parseHeader(fec)
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceNumbers := []uint16{}
+	for offset := uint16(0); offset < MaxProtectedPackets; offset++ {
+		if mask&(0x8000>>offset) != 0 {
+			sequenceNumbers = append(sequenceNumbers, base+offset)
+		}
+	}
+
+	return sequenceNumbers, nil
+}
+
+// Recover returns the marshaled media packet protected by fec that is missing.
+// received looks up the other protected packets by sequence number, it returns
+// nil for packets that were lost. The recovered packet is given ssrc, which
+// ULPFEC doesn't protect.
+func Recover(fec []byte, ssrc uint32, received func(sequenceNumber uint16) []byte) ([]byte, error) {
+	sequenceNumbers, err := ProtectedSequenceNumbers(fec)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []uint16
+	for _, sequenceNumber := range sequenceNumbers {
+		if received(sequenceNumber) == nil {
+			missing = append(missing, sequenceNumber)
+		}
+	}
+
+	switch len(missing) {
+	case 0:
+		return nil, errNothingToRecover
+	case 1:
+	default:
+		return nil, errCannotRecover
+	}
+
+	protection := fec[fecHeaderLength+levelHeaderLength:]
+	if protectionLength := int(binary.BigEndian.Uint16(fec[fecHeaderLength:])); protectionLength < len(protection) {
+		protection = protection[:protectionLength]
+	}
+
+	recovery := make([]byte, fecHeaderLength)
+	copy(recovery, fec[:fecHeaderLength])
+	payload := make([]byte, len(protection))
+	copy(payload, protection)
+	for _, sequenceNumber := range sequenceNumbers {
+		if sequenceNumber == missing[0] {
+			continue
+		}
+
+		packet := received(sequenceNumber)
+		if len(packet) < rtpHeaderLength {
+			return nil, errShortPacket
+		}
+
+		xorHeader(recovery, packet)
+		xorBytes(payload, packet[rtpHeaderLength:])
+	}
+
+	length := int(binary.BigEndian.Uint16(recovery[8:]))
+	if length > len(payload) {
+		return nil, errInvalidProtection
+	}
+
+	packet := make([]byte, rtpHeaderLength+length)
+	packet[0] = 0x80 | recovery[0]&0x3F
+	packet[1] = recovery[1]
+	binary.BigEndian.PutUint16(packet[2:], missing[0])
+	copy(packet[4:8], recovery[4:8])
+	binary.BigEndian.PutUint32(packet[8:], ssrc)
+	copy(packet[rtpHeaderLength:], payload[:length])
+
+	return packet, nil
+}
+
+func parseHeader(fec []byte) (base, mask uint16, err error) {
+	if len(fec) < fecHeaderLength+levelHeaderLength {
+		return 0, 0, errShortPacket
+	}
+	if fec[0]&0x40 != 0 {
+		return 0, 0, errLongMask
+	}
+
+	return binary.BigEndian.Uint16(fec[2:]), binary.BigEndian.Uint16(fec[fecHeaderLength+2:]), nil
+}
+
+// xorHeader folds the recovery fields of packet into the FEC header: the P, X
+// and CC bits, the marker and payload type, the timestamp and the length of
+// everything following the fixed RTP header.
+func xorHeader(fec, packet []byte) {
+	fec[0] ^= packet[0] & 0x3F
+	fec[1] ^= packet[1]
+	xorBytes(fec[4:8], packet[4:8])
+
+	length := binary.BigEndian.Uint16(fec[8:]) ^ uint16(len(packet)-rtpHeaderLength) //nolint:gosec // G115
+	binary.BigEndian.PutUint16(fec[8:], length)
+}
+
+// xorBytes XORs src into dst, bytes beyond the end of dst are ignored.
+func xorBytes(dst, src []byte) {
+	if len(src) > len(dst) {
+		src = src[:len(dst)]
+	}
+
+	for i := range src {
+		dst[i] ^= src[i]
+	}
+}
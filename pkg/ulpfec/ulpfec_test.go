@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package ulpfec
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPacket(sequenceNumber uint16, marker bool, payload ...byte) []byte {
+	packet := make([]byte, rtpHeaderLength, rtpHeaderLength+len(payload))
+	packet[0] = 0x80
+	packet[1] = 96
+	if marker {
+		packet[1] |= 0x80
+	}
+	binary.BigEndian.PutUint16(packet[2:], sequenceNumber)
+	binary.BigEndian.PutUint32(packet[4:], 3000+uint32(sequenceNumber))
+	binary.BigEndian.PutUint32(packet[8:], 0x1234)
+
+	return append(packet, payload...)
+}
+
+func TestProtectRecover(t *testing.T) {
+	packets := [][]byte{
+		testPacket(65534, false, 0x01, 0x02, 0x03),
+		testPacket(65535, false, 0x04),
+		testPacket(0, true, 0x05, 0x06, 0x07, 0x08, 0x09),
+	}
+
+	fec, err := Protect(packets)
+	assert.NoError(t, err)
+
+	sequenceNumbers, err := ProtectedSequenceNumbers(fec)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{65534, 65535, 0}, sequenceNumbers)
+
+	for lost := range packets {
+		recovered, err := Recover(fec, 0x1234, func(sequenceNumber uint16) []byte {
+			for i, packet := range packets {
+				if i != lost && binary.BigEndian.Uint16(packet[2:]) == sequenceNumber {
+					return packet
+				}
+			}
+
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, packets[lost], recovered)
+	}
+}
+
+func TestRecoverErrors(t *testing.T) {
+	packets := [][]byte{testPacket(10, false, 0x01), testPacket(12, false, 0x02)}
+	fec, err := Protect(packets)
+	assert.NoError(t, err)
+
+	_, err = Recover(fec, 0x1234, func(sequenceNumber uint16) []byte {
+		return packets[(sequenceNumber-10)/2]
+	})
+	assert.ErrorIs(t, err, errNothingToRecover)
+
+	_, err = Recover(fec, 0x1234, func(uint16) []byte {
+		return nil
+	})
+	assert.ErrorIs(t, err, errCannotRecover)
+
+	_, err = Recover(fec[:fecHeaderLength], 0x1234, func(uint16) []byte {
+		return nil
+	})
+	assert.ErrorIs(t, err, errShortPacket)
+}
+
+func TestProtectErrors(t *testing.T) {
+	_, err := Protect(nil)
+	assert.ErrorIs(t, err, errNoPackets)
+
+	_, err = Protect([][]byte{testPacket(1, false), testPacket(17, false)})
+	assert.ErrorIs(t, err, errTooManyPackets)
+
+	_, err = Protect([][]byte{{0x80}})
+	assert.ErrorIs(t, err, errShortPacket)
+}
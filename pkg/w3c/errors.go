@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package w3c
+
+import (
+	"errors"
+
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
+)
+
+// Names of the exceptions thrown by the browser API.
+const (
+	InvalidStateError        = "InvalidStateError"
+	InvalidAccessError       = "InvalidAccessError"
+	NotSupportedError        = "NotSupportedError"
+	InvalidModificationError = "InvalidModificationError"
+	SyntaxError              = "SyntaxError"
+	OperationError           = "OperationError"
+	NotReadableError         = "NotReadableError"
+	UnknownError             = "UnknownError"
+
+	// TypeError and RangeError are ECMAScript errors rather than DOMExceptions,
+	// they are reported as DOMException with these names.
+	TypeError  = "TypeError"
+	RangeError = "RangeError"
+)
+
+// DOMException is the error returned by all methods of the package. Name is the
+// name of the exception a browser would throw, e.g. "InvalidStateError".
+type DOMException struct {
+	Name    string
+	Message string
+
+	err error
+}
+
+func (e *DOMException) Error() string {
+	return e.Name + ": " + e.Message
+}
+
+// Unwrap returns the original error of the webrtc package.
+func (e *DOMException) Unwrap() error {
+	return e.err
+}
+
+// IsDOMException returns true if err is a DOMException with the given name.
+func IsDOMException(err error, name string) bool {
+	var exception *DOMException
+
+	return errors.As(err, &exception) && exception.Name == name
+}
+
+// toDOMException converts errors of the webrtc package to the exception the
+// browser API throws in the same situation. Errors that aren't classified by
+// the webrtc package are reported as OperationError.
+func toDOMException(err error) error { //nolint:cyclop
+	if err == nil {
+		return nil
+	}
+
+	var (
+		exception                *DOMException
+		invalidStateError        *rtcerr.InvalidStateError
+		invalidAccessError       *rtcerr.InvalidAccessError
+		notSupportedError        *rtcerr.NotSupportedError
+		invalidModificationError *rtcerr.InvalidModificationError
+		syntaxError              *rtcerr.SyntaxError
+		typeError                *rtcerr.TypeError
+		operationError           *rtcerr.OperationError
+		notReadableError         *rtcerr.NotReadableError
+		rangeError               *rtcerr.RangeError
+		unknownError             *rtcerr.UnknownError
+	)
+
+	name, cause := OperationError, err
+	switch {
+	case errors.As(err, &exception):
+		return err
+	case errors.As(err, &invalidStateError):
+		name, cause = InvalidStateError, invalidStateError.Err
+	case errors.As(err, &invalidAccessError):
+		name, cause = InvalidAccessError, invalidAccessError.Err
+	case errors.As(err, &notSupportedError):
+		name, cause = NotSupportedError, notSupportedError.Err
+	case errors.As(err, &invalidModificationError):
+		name, cause = InvalidModificationError, invalidModificationError.Err
+	case errors.As(err, &syntaxError):
+		name, cause = SyntaxError, syntaxError.Err
+	case errors.As(err, &typeError):
+		name, cause = TypeError, typeError.Err
+	case errors.As(err, &operationError):
+		name, cause = OperationError, operationError.Err
+	case errors.As(err, &notReadableError):
+		name, cause = NotReadableError, notReadableError.Err
+	case errors.As(err, &rangeError):
+		name, cause = RangeError, rangeError.Err
+	case errors.As(err, &unknownError):
+		name, cause = UnknownError, unknownError.Err
+	}
+
+	message := err.Error()
+	if cause != nil {
+		message = cause.Error()
+	}
+
+	return &DOMException{Name: name, Message: message, err: err}
+}
@@ -0,0 +1,319 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package w3c
+
+import (
+	"sync"
+
+	"github.com/pion/webrtc/v4"
+)
+
+// RTCPeerConnection mirrors the RTCPeerConnection interface of the browser.
+// Like in the browser createOffer, createAnswer, setLocalDescription,
+// setRemoteDescription and addIceCandidate are queued on an operations chain
+// and run one after the other in the order they were called.
+type RTCPeerConnection struct {
+	pc *webrtc.PeerConnection
+
+	mu                  sync.Mutex
+	lastOperation       chan struct{}
+	restartIce          bool
+	onNegotiationNeeded func()
+}
+
+// NewRTCPeerConnection creates an RTCPeerConnection, like new RTCPeerConnection(configuration).
+func NewRTCPeerConnection(configuration webrtc.Configuration) (*RTCPeerConnection, error) {
+	return NewRTCPeerConnectionWithAPI(webrtc.NewAPI(), configuration)
+}
+
+// NewRTCPeerConnectionWithAPI creates an RTCPeerConnection from a customized API.
+func NewRTCPeerConnectionWithAPI(api *webrtc.API, configuration webrtc.Configuration) (*RTCPeerConnection, error) {
+	pc, err := api.NewPeerConnection(configuration)
+	if err != nil {
+		return nil, toDOMException(err)
+	}
+
+	return Wrap(pc), nil
+}
+
+// Wrap returns an RTCPeerConnection for an existing PeerConnection. The
+// PeerConnection should only be used through the wrapper afterwards, calls
+// made directly on it bypass the operations chain.
+func Wrap(pc *webrtc.PeerConnection) *RTCPeerConnection {
+	wrapped := &RTCPeerConnection{pc: pc}
+	pc.OnNegotiationNeeded(wrapped.fireNegotiationNeeded)
+
+	return wrapped
+}
+
+// PeerConnection returns the wrapped PeerConnection.
+func (c *RTCPeerConnection) PeerConnection() *webrtc.PeerConnection {
+	return c.pc
+}
+
+// chain queues op on the operations chain, promise is settled with its result.
+func (c *RTCPeerConnection) chain(promise *Promise, op func() error) {
+	c.mu.Lock()
+	previous := c.lastOperation
+	c.lastOperation = promise.done
+	c.mu.Unlock()
+
+	go func() {
+		if previous != nil {
+			<-previous
+		}
+
+		promise.settle(op())
+	}()
+}
+
+// CreateOffer mirrors createOffer(options). The offer restarts ICE if
+// options.ICERestart is set or RestartIce was called since the last offer.
+func (c *RTCPeerConnection) CreateOffer(options *webrtc.OfferOptions) *SessionDescriptionPromise {
+	promise := &SessionDescriptionPromise{Promise: *newPromise()}
+	c.chain(&promise.Promise, func() (err error) {
+		promise.description, err = c.createOffer(options)
+
+		return err
+	})
+
+	return promise
+}
+
+func (c *RTCPeerConnection) createOffer(options *webrtc.OfferOptions) (webrtc.SessionDescription, error) {
+	c.mu.Lock()
+	restartIce := c.restartIce
+	c.restartIce = false
+	c.mu.Unlock()
+
+	if restartIce {
+		restartOptions := webrtc.OfferOptions{ICERestart: true}
+		if options != nil {
+			restartOptions.OfferAnswerOptions = options.OfferAnswerOptions
+		}
+		options = &restartOptions
+	}
+
+	return c.pc.CreateOffer(options)
+}
+
+// CreateAnswer mirrors createAnswer(options).
+func (c *RTCPeerConnection) CreateAnswer(options *webrtc.AnswerOptions) *SessionDescriptionPromise {
+	promise := &SessionDescriptionPromise{Promise: *newPromise()}
+	c.chain(&promise.Promise, func() (err error) {
+		promise.description, err = c.pc.CreateAnswer(options)
+
+		return err
+	})
+
+	return promise
+}
+
+// SetLocalDescription mirrors setLocalDescription(description). Like in the
+// browser description is optional, if it is nil or has no SDP an offer or
+// answer matching the signaling state is created and applied.
+func (c *RTCPeerConnection) SetLocalDescription(description *webrtc.SessionDescription) *Promise {
+	promise := newPromise()
+	c.chain(promise, func() error {
+		if description != nil && description.SDP != "" {
+			return c.pc.SetLocalDescription(*description)
+		}
+
+		var (
+			implicit webrtc.SessionDescription
+			err      error
+		)
+		switch c.pc.SignalingState() {
+		case webrtc.SignalingStateHaveRemoteOffer, webrtc.SignalingStateHaveLocalPranswer:
+			implicit, err = c.pc.CreateAnswer(nil)
+		default:
+			implicit, err = c.createOffer(nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		return c.pc.SetLocalDescription(implicit)
+	})
+
+	return promise
+}
+
+// SetRemoteDescription mirrors setRemoteDescription(description).
+func (c *RTCPeerConnection) SetRemoteDescription(description webrtc.SessionDescription) *Promise {
+	promise := newPromise()
+	c.chain(promise, func() error {
+		return c.pc.SetRemoteDescription(description)
+	})
+
+	return promise
+}
+
+// AddIceCandidate mirrors addIceCandidate(candidate). A nil candidate signals
+// the end of remote candidates.
+func (c *RTCPeerConnection) AddIceCandidate(candidate *webrtc.ICECandidateInit) *Promise {
+	promise := newPromise()
+	c.chain(promise, func() error {
+		if candidate == nil {
+			return c.pc.AddICECandidate(webrtc.ICECandidateInit{})
+		}
+
+		return c.pc.AddICECandidate(*candidate)
+	})
+
+	return promise
+}
+
+// RestartIce mirrors restartIce(). The next offer restarts ICE and
+// negotiationneeded is fired.
+func (c *RTCPeerConnection) RestartIce() {
+	c.mu.Lock()
+	c.restartIce = true
+	c.mu.Unlock()
+
+	c.fireNegotiationNeeded()
+}
+
+// Close mirrors close().
+func (c *RTCPeerConnection) Close() error {
+	return toDOMException(c.pc.Close())
+}
+
+// GetConfiguration mirrors getConfiguration().
+func (c *RTCPeerConnection) GetConfiguration() webrtc.Configuration {
+	return c.pc.GetConfiguration()
+}
+
+// SetConfiguration mirrors setConfiguration(configuration).
+func (c *RTCPeerConnection) SetConfiguration(configuration webrtc.Configuration) error {
+	return toDOMException(c.pc.SetConfiguration(configuration))
+}
+
+// CreateDataChannel mirrors createDataChannel(label, options).
+func (c *RTCPeerConnection) CreateDataChannel(
+	label string,
+	options *webrtc.DataChannelInit,
+) (*webrtc.DataChannel, error) {
+	dataChannel, err := c.pc.CreateDataChannel(label, options)
+
+	return dataChannel, toDOMException(err)
+}
+
+// AddTransceiver mirrors addTransceiver(kind, init).
+func (c *RTCPeerConnection) AddTransceiver(
+	kind webrtc.RTPCodecType,
+	init ...webrtc.RTPTransceiverInit,
+) (*webrtc.RTPTransceiver, error) {
+	transceiver, err := c.pc.AddTransceiverFromKind(kind, init...)
+
+	return transceiver, toDOMException(err)
+}
+
+// GetTransceivers mirrors getTransceivers().
+func (c *RTCPeerConnection) GetTransceivers() []*webrtc.RTPTransceiver {
+	return c.pc.GetTransceivers()
+}
+
+// LocalDescription mirrors the localDescription attribute.
+func (c *RTCPeerConnection) LocalDescription() *webrtc.SessionDescription {
+	return c.pc.LocalDescription()
+}
+
+// RemoteDescription mirrors the remoteDescription attribute.
+func (c *RTCPeerConnection) RemoteDescription() *webrtc.SessionDescription {
+	return c.pc.RemoteDescription()
+}
+
+// CurrentLocalDescription mirrors the currentLocalDescription attribute.
+func (c *RTCPeerConnection) CurrentLocalDescription() *webrtc.SessionDescription {
+	return c.pc.CurrentLocalDescription()
+}
+
+// CurrentRemoteDescription mirrors the currentRemoteDescription attribute.
+func (c *RTCPeerConnection) CurrentRemoteDescription() *webrtc.SessionDescription {
+	return c.pc.CurrentRemoteDescription()
+}
+
+// PendingLocalDescription mirrors the pendingLocalDescription attribute.
+func (c *RTCPeerConnection) PendingLocalDescription() *webrtc.SessionDescription {
+	return c.pc.PendingLocalDescription()
+}
+
+// PendingRemoteDescription mirrors the pendingRemoteDescription attribute.
+func (c *RTCPeerConnection) PendingRemoteDescription() *webrtc.SessionDescription {
+	return c.pc.PendingRemoteDescription()
+}
+
+// SignalingState mirrors the signalingState attribute.
+func (c *RTCPeerConnection) SignalingState() webrtc.SignalingState {
+	return c.pc.SignalingState()
+}
+
+// ConnectionState mirrors the connectionState attribute.
+func (c *RTCPeerConnection) ConnectionState() webrtc.PeerConnectionState {
+	return c.pc.ConnectionState()
+}
+
+// IceConnectionState mirrors the iceConnectionState attribute.
+func (c *RTCPeerConnection) IceConnectionState() webrtc.ICEConnectionState {
+	return c.pc.ICEConnectionState()
+}
+
+// IceGatheringState mirrors the iceGatheringState attribute.
+func (c *RTCPeerConnection) IceGatheringState() webrtc.ICEGatheringState {
+	return c.pc.ICEGatheringState()
+}
+
+// OnIceCandidate sets the onicecandidate event handler. Like event.candidate
+// in the browser, the candidate is nil once gathering is complete.
+func (c *RTCPeerConnection) OnIceCandidate(f func(candidate *webrtc.ICECandidateInit)) {
+	c.pc.OnICECandidate(func(candidate *webrtc.ICECandidate) {
+		if candidate == nil {
+			f(nil)
+
+			return
+		}
+
+		init := candidate.ToJSON()
+		f(&init)
+	})
+}
+
+// OnNegotiationNeeded sets the onnegotiationneeded event handler.
+func (c *RTCPeerConnection) OnNegotiationNeeded(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onNegotiationNeeded = f
+}
+
+func (c *RTCPeerConnection) fireNegotiationNeeded() {
+	c.mu.Lock()
+	handler := c.onNegotiationNeeded
+	c.mu.Unlock()
+
+	if handler != nil {
+		go handler()
+	}
+}
+
+// OnSignalingStateChange sets the onsignalingstatechange event handler.
+func (c *RTCPeerConnection) OnSignalingStateChange(f func(webrtc.SignalingState)) {
+	c.pc.OnSignalingStateChange(f)
+}
+
+// OnConnectionStateChange sets the onconnectionstatechange event handler.
+func (c *RTCPeerConnection) OnConnectionStateChange(f func(webrtc.PeerConnectionState)) {
+	c.pc.OnConnectionStateChange(f)
+}
+
+// OnIceConnectionStateChange sets the oniceconnectionstatechange event handler.
+func (c *RTCPeerConnection) OnIceConnectionStateChange(f func(webrtc.ICEConnectionState)) {
+	c.pc.OnICEConnectionStateChange(f)
+}
+
+// OnDataChannel sets the ondatachannel event handler.
+func (c *RTCPeerConnection) OnDataChannel(f func(*webrtc.DataChannel)) {
+	c.pc.OnDataChannel(f)
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build js && wasm
+// +build js,wasm
+
+package w3c
+
+import "github.com/pion/webrtc/v4"
+
+// OnIceGatheringStateChange sets the onicegatheringstatechange event handler.
+func (c *RTCPeerConnection) OnIceGatheringStateChange(f func(webrtc.ICEGatheringState)) {
+	c.pc.OnICEGatheringStateChange(func() {
+		f(c.pc.ICEGatheringState())
+	})
+}
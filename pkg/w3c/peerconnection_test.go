@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package w3c
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTCPeerConnection_ImplicitDescriptions(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	offerer, err := NewRTCPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+
+	answerer, err := NewRTCPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+
+	candidates := make(chan *webrtc.ICECandidateInit, 32)
+	offerer.OnIceCandidate(func(candidate *webrtc.ICECandidateInit) {
+		candidates <- candidate
+	})
+
+	opened := make(chan struct{})
+	answerer.OnDataChannel(func(dataChannel *webrtc.DataChannel) {
+		dataChannel.OnOpen(func() {
+			close(opened)
+		})
+	})
+
+	_, err = offerer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, offerer.SetLocalDescription(nil).Await())
+	assert.Equal(t, webrtc.SignalingStateHaveLocalOffer, offerer.SignalingState())
+
+	// Queued operations run in order, the answer is created after the offer is applied
+	setRemote := answerer.SetRemoteDescription(*offerer.LocalDescription())
+	setLocal := answerer.SetLocalDescription(nil)
+	assert.NoError(t, setRemote.Await())
+	assert.NoError(t, setLocal.Await())
+	assert.Equal(t, webrtc.SDPTypeAnswer, answerer.LocalDescription().Type)
+
+	assert.NoError(t, offerer.SetRemoteDescription(*answerer.LocalDescription()).Await())
+
+	for candidate := range candidates {
+		assert.NoError(t, answerer.AddIceCandidate(candidate).Await())
+		if candidate == nil {
+			break
+		}
+	}
+
+	<-opened
+
+	assert.NoError(t, offerer.Close())
+	assert.NoError(t, answerer.Close())
+}
+
+func TestRTCPeerConnection_Exceptions(t *testing.T) {
+	pc, err := NewRTCPeerConnection(webrtc.Configuration{})
+	assert.NoError(t, err)
+
+	// No remote description to answer
+	_, err = pc.CreateAnswer(nil).Await()
+	assert.True(t, IsDOMException(err, InvalidStateError), err)
+
+	err = pc.AddIceCandidate(&webrtc.ICECandidateInit{Candidate: "candidate:invalid"}).Await()
+	assert.True(t, IsDOMException(err, InvalidStateError), err)
+
+	assert.NoError(t, pc.Close())
+
+	_, err = pc.CreateOffer(nil).Await()
+	assert.True(t, IsDOMException(err, InvalidStateError), err)
+}
+
+func TestToDOMException(t *testing.T) {
+	errTest := errors.New("test")
+
+	assert.NoError(t, toDOMException(nil))
+
+	err := toDOMException(&rtcerr.InvalidModificationError{Err: errTest})
+	assert.True(t, IsDOMException(err, InvalidModificationError))
+	assert.Equal(t, "InvalidModificationError: test", err.Error())
+	assert.ErrorIs(t, err, errTest)
+
+	err = toDOMException(errTest)
+	assert.True(t, IsDOMException(err, OperationError))
+	assert.Same(t, err, toDOMException(err))
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+// Package w3c provides thin wrappers around the webrtc package that mirror the
+// method names and semantics of the browser API. Methods returning a Promise in
+// JavaScript return a Promise here, errors are DOMExceptions carrying the name
+// a browser would throw. This eases porting JavaScript signaling logic and
+// allows running the same conformance tests against the native and wasm builds.
+package w3c
+
+import (
+	"context"
+
+	"github.com/pion/webrtc/v4"
+)
+
+// Promise is the result of an asynchronous operation without a value.
+type Promise struct {
+	done chan struct{}
+	err  error
+}
+
+func newPromise() *Promise {
+	return &Promise{done: make(chan struct{})}
+}
+
+func (p *Promise) settle(err error) {
+	p.err = toDOMException(err)
+	close(p.done)
+}
+
+// Done returns a channel that is closed once the Promise is settled.
+func (p *Promise) Done() <-chan struct{} {
+	return p.done
+}
+
+// Await blocks until the Promise is settled and returns the rejection reason.
+func (p *Promise) Await() error {
+	<-p.done
+
+	return p.err
+}
+
+// AwaitContext is like Await, but gives up waiting when ctx is done.
+func (p *Promise) AwaitContext(ctx context.Context) error {
+	select {
+	case <-p.done:
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SessionDescriptionPromise is the result of createOffer and createAnswer.
+type SessionDescriptionPromise struct {
+	Promise
+	description webrtc.SessionDescription
+}
+
+// Await blocks until the Promise is settled and returns the description or
+// the rejection reason.
+func (p *SessionDescriptionPromise) Await() (webrtc.SessionDescription, error) {
+	<-p.done
+
+	return p.description, p.err
+}
+
+// AwaitContext is like Await, but gives up waiting when ctx is done.
+func (p *SessionDescriptionPromise) AwaitContext(ctx context.Context) (webrtc.SessionDescription, error) {
+	if err := p.Promise.AwaitContext(ctx); err != nil {
+		return webrtc.SessionDescription{}, err
+	}
+
+	return p.description, nil
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import "sync/atomic"
+
+// probeRoutines limits the goroutines that read the first packets of the
+// undeclared incoming SSRCs to dispatch them to their tracks. It belongs to
+// a PeerConnection, or to an API if SettingEngine.SetIncomingSSRCRoutines
+// shares it.
+type probeRoutines struct {
+	count uint64
+	max   uint64
+}
+
+func newProbeRoutines(maxRoutines int) *probeRoutines {
+	if maxRoutines <= 0 {
+		maxRoutines = simulcastMaxProbeRoutines
+	}
+
+	return &probeRoutines{max: uint64(maxRoutines)}
+}
+
+// acquire reserves a routine, it returns false if all are in use.
+func (p *probeRoutines) acquire() bool {
+	if atomic.AddUint64(&p.count, 1) > p.max {
+		atomic.AddUint64(&p.count, ^uint64(0))
+
+		return false
+	}
+
+	return true
+}
+
+func (p *probeRoutines) release() {
+	atomic.AddUint64(&p.count, ^uint64(0))
+}
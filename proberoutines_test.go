@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeRoutines(t *testing.T) {
+	routines := newProbeRoutines(2)
+	assert.True(t, routines.acquire())
+	assert.True(t, routines.acquire())
+	assert.False(t, routines.acquire())
+
+	routines.release()
+	assert.True(t, routines.acquire())
+
+	assert.Equal(t, uint64(simulcastMaxProbeRoutines), newProbeRoutines(0).max)
+}
+
+func TestSettingEngine_SetIncomingSSRCRoutines(t *testing.T) {
+	settingEngine := SettingEngine{}
+	api := NewAPI(WithSettingEngine(settingEngine))
+	pcA, err := api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	pcB, err := api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.NotSame(t, pcA.api.probeRoutines, pcB.api.probeRoutines)
+	assert.NoError(t, pcA.Close())
+	assert.NoError(t, pcB.Close())
+
+	settingEngine.SetIncomingSSRCRoutines(4, true)
+	api = NewAPI(WithSettingEngine(settingEngine))
+	pcA, err = api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	pcB, err = api.NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	assert.Same(t, pcA.api.probeRoutines, pcB.api.probeRoutines)
+	assert.Equal(t, uint64(4), pcA.api.probeRoutines.max)
+	assert.NoError(t, pcA.Close())
+	assert.NoError(t, pcB.Close())
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pion/webrtc/v4/internal/fmtp"
+)
+
+// Quirk is a workaround for remote endpoints known to be broken. The quirks
+// are registered with SettingEngine.AddQuirk, and enabled for a
+// PeerConnection once it receives a remote description they match.
+type Quirk struct {
+	// Name identifies the quirk, see PeerConnection.Quirks.
+	Name string
+
+	// Match returns true if the remote description is from an endpoint that
+	// needs the quirk, see MatchSDP.
+	Match func(desc SessionDescription) bool
+
+	// MirrorPayloadTypes negotiates the codecs with the payload types of the
+	// local MediaEngine instead of the ones of the remote description, for
+	// endpoints that send and expect the payload types Pion signaled rather
+	// than their own.
+	MirrorPayloadTypes bool
+
+	// DisableRTX doesn't negotiate RTX, for endpoints that offer it but fail
+	// to handle it.
+	DisableRTX bool
+
+	// H264PacketizationMode replaces the packetization-mode of the H264
+	// codecs of the remote descriptions if not empty, for endpoints that
+	// signal a mode they don't use.
+	H264PacketizationMode string
+}
+
+// MatchSDP returns a Quirk.Match function that matches the remote
+// descriptions whose SDP matches pattern, such as the o=, s= or a=tool lines
+// identifying a product.
+func MatchSDP(pattern *regexp.Regexp) func(desc SessionDescription) bool {
+	return func(desc SessionDescription) bool {
+		return pattern.MatchString(desc.SDP)
+	}
+}
+
+// AddQuirk registers a workaround that the PeerConnections enable when the
+// remote description matches it. With DisableMediaEngineCopy the quirks
+// enabled by a PeerConnection apply to all the PeerConnections of the API.
+func (e *SettingEngine) AddQuirk(quirk Quirk) {
+	e.quirks = append(e.quirks, quirk)
+}
+
+// quirkWorkarounds are the workarounds enabled for a PeerConnection.
+type quirkWorkarounds struct {
+	names                 []string
+	mirrorPayloadTypes    bool
+	disableRTX            bool
+	h264PacketizationMode string
+}
+
+// Quirks returns the names of the quirks enabled for the PeerConnection.
+//
+// This is a Pion specific extension and is not part of the W3C API.
+func (pc *PeerConnection) Quirks() []string {
+	pc.api.mediaEngine.mu.RLock()
+	defer pc.api.mediaEngine.mu.RUnlock()
+
+	return append([]string{}, pc.api.mediaEngine.quirks.names...)
+}
+
+// enableQuirks enables the quirks of the SettingEngine matching the remote
+// description, they stay enabled for the lifetime of the PeerConnection.
+func (pc *PeerConnection) enableQuirks(desc SessionDescription) {
+	m := pc.api.mediaEngine
+	for _, quirk := range pc.api.settingEngine.quirks {
+		if quirk.Match == nil || !quirk.Match(desc) {
+			continue
+		}
+
+		m.mu.Lock()
+		enabled := false
+		for _, name := range m.quirks.names {
+			enabled = enabled || name == quirk.Name
+		}
+		if !enabled {
+			m.quirks.names = append(m.quirks.names, quirk.Name)
+			m.quirks.mirrorPayloadTypes = m.quirks.mirrorPayloadTypes || quirk.MirrorPayloadTypes
+			m.quirks.disableRTX = m.quirks.disableRTX || quirk.DisableRTX
+			if quirk.H264PacketizationMode != "" {
+				m.quirks.h264PacketizationMode = quirk.H264PacketizationMode
+			}
+		}
+		m.mu.Unlock()
+
+		if !enabled {
+			pc.log.Infof("enabling quirk %s for the remote endpoint", quirk.Name)
+		}
+	}
+}
+
+// apply applies the workarounds to the codecs of a remote media section before
+// they are matched.
+func (q *quirkWorkarounds) apply(codecs []RTPCodecParameters) []RTPCodecParameters {
+	if q.disableRTX {
+		codecs = removeCodecs(codecs, func(c RTPCodecParameters) bool {
+			return strings.EqualFold(c.MimeType, MimeTypeRTX)
+		})
+	}
+
+	if q.h264PacketizationMode != "" {
+		for i := range codecs {
+			if strings.EqualFold(codecs[i].MimeType, MimeTypeH264) {
+				codecs[i].SDPFmtpLine = setFmtpParameter(
+					codecs[i].SDPFmtpLine, "packetization-mode", q.h264PacketizationMode,
+				)
+			}
+		}
+	}
+
+	return codecs
+}
+
+// applyMirrorPayloadTypes replaces the payload types of the matched remote codecs
+// with the ones of the local codecs they matched, and the apt of their RTX.
+func (q *quirkWorkarounds) applyMirrorPayloadTypes(
+	codecs []RTPCodecParameters, localPayloadTypes []PayloadType, matchTypes []CodecMatchType,
+) {
+	if !q.mirrorPayloadTypes {
+		return
+	}
+
+	mirrored := map[string]string{}
+	for i := range codecs {
+		if matchTypes[i] != CodecMatchNone {
+			mirrored[strconv.Itoa(int(codecs[i].PayloadType))] = strconv.Itoa(int(localPayloadTypes[i]))
+		}
+	}
+
+	for i := range codecs {
+		if matchTypes[i] == CodecMatchNone {
+			continue
+		}
+
+		codecs[i].PayloadType = localPayloadTypes[i]
+		apt, hasApt := fmtp.Parse(codecs[i].MimeType, codecs[i].SDPFmtpLine).Parameter("apt")
+		if local, ok := mirrored[apt]; hasApt && ok {
+			codecs[i].SDPFmtpLine = setFmtpParameter(codecs[i].SDPFmtpLine, "apt", local)
+		}
+	}
+}
+
+// setFmtpParameter sets the value of key in a fmtp line, adding it if absent.
+func setFmtpParameter(line, key, value string) string {
+	parameters := []string{}
+	found := false
+	for _, parameter := range strings.Split(line, ";") {
+		parameter = strings.TrimSpace(parameter)
+		if parameter == "" {
+			continue
+		}
+
+		if name, _, _ := strings.Cut(parameter, "="); strings.EqualFold(name, key) {
+			parameter = key + "=" + value
+			found = true
+		}
+		parameters = append(parameters, parameter)
+	}
+	if !found {
+		parameters = append(parameters, key+"="+value)
+	}
+
+	return strings.Join(parameters, ";")
+}
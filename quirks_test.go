@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFmtpParameter(t *testing.T) {
+	assert.Equal(t, "packetization-mode=1", setFmtpParameter("", "packetization-mode", "1"))
+	assert.Equal(t,
+		"level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f",
+		setFmtpParameter(
+			"level-asymmetry-allowed=1; packetization-mode=0; profile-level-id=42e01f", "packetization-mode", "1",
+		),
+	)
+	assert.Equal(t, "apt=96;foo=bar", setFmtpParameter("apt=96", "foo", "bar"))
+}
+
+func TestMediaEngine_Quirks(t *testing.T) {
+	const offer = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+m=video 9 UDP/TLS/RTP/SAVPF 120 121 122
+a=rtpmap:120 VP8/90000
+a=rtpmap:121 rtx/90000
+a=fmtp:121 apt=120
+a=rtpmap:122 H264/90000
+a=fmtp:122 level-asymmetry-allowed=1;profile-level-id=42e01f
+`
+	negotiate := func(quirks quirkWorkarounds) *MediaEngine {
+		mediaEngine := &MediaEngine{}
+		assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+		mediaEngine.quirks = quirks
+
+		desc := sdp.SessionDescription{}
+		assert.NoError(t, desc.Unmarshal([]byte(offer)))
+		assert.NoError(t, mediaEngine.updateFromRemoteDescription(desc))
+
+		return mediaEngine
+	}
+
+	// H264 without packetization-mode doesn't match any local codec
+	mediaEngine := negotiate(quirkWorkarounds{})
+	_, _, err := mediaEngine.getCodecByPayload(121)
+	assert.NoError(t, err)
+	_, _, err = mediaEngine.getCodecByPayload(122)
+	assert.Error(t, err)
+
+	mediaEngine = negotiate(quirkWorkarounds{disableRTX: true, h264PacketizationMode: "1"})
+	_, _, err = mediaEngine.getCodecByPayload(121)
+	assert.Error(t, err)
+	h264, _, err := mediaEngine.getCodecByPayload(122)
+	assert.NoError(t, err)
+	assert.Contains(t, h264.SDPFmtpLine, "packetization-mode=1")
+
+	mediaEngine = negotiate(quirkWorkarounds{mirrorPayloadTypes: true})
+	vp8, _, err := mediaEngine.getCodecByPayload(96)
+	assert.NoError(t, err)
+	assert.Equal(t, MimeTypeVP8, vp8.MimeType)
+	rtx, _, err := mediaEngine.getCodecByPayload(97)
+	assert.NoError(t, err)
+	assert.Equal(t, "apt=96", rtx.SDPFmtpLine)
+}
+
+func TestPeerConnection_Quirks(t *testing.T) {
+	settingEngine := SettingEngine{}
+	settingEngine.AddQuirk(Quirk{
+		Name:       "no-rtx",
+		Match:      MatchSDP(regexp.MustCompile(`(?m)^s=-`)),
+		DisableRTX: true,
+	})
+	settingEngine.AddQuirk(Quirk{
+		Name:  "never",
+		Match: MatchSDP(regexp.MustCompile(`(?m)^a=tool:never`)),
+	})
+
+	offerer, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	answerer, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	_, err = offerer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	offer, err := offerer.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, offer.SDP, "rtx/90000")
+
+	assert.NoError(t, answerer.SetRemoteDescription(offer))
+	assert.Equal(t, []string{"no-rtx"}, answerer.Quirks())
+
+	answer, err := answerer.CreateAnswer(nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, answer.SDP, "rtx/90000")
+
+	closePairNow(t, offerer, answerer)
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+// relayAccounting counts the bytes an ICETransport sends and receives while
+// its selected candidate pair uses a local relay candidate, those are the
+// bytes going through the TURN server.
+type relayAccounting struct {
+	bytesSent     uint64
+	bytesReceived uint64
+
+	relayed  atomicBool
+	exceeded atomicBool
+
+	// budget is the number of relayed bytes, sent and received, after which
+	// onBudgetExceeded fires. Zero disables it.
+	budget           uint64
+	onBudgetExceeded atomic.Value // func()
+}
+
+func (r *relayAccounting) add(sent, received int) {
+	if !r.relayed.get() || (sent <= 0 && received <= 0) {
+		return
+	}
+
+	total := atomic.AddUint64(&r.bytesSent, uint64(sent)) + //nolint:gosec // G115, checked above
+		atomic.AddUint64(&r.bytesReceived, uint64(received)) //nolint:gosec // G115, checked above
+	if r.budget == 0 || total < r.budget || r.exceeded.swap(true) {
+		return
+	}
+
+	if handler, ok := r.onBudgetExceeded.Load().(func()); ok && handler != nil {
+		go handler()
+	}
+}
+
+func (r *relayAccounting) bytes() (sent, received uint64) {
+	return atomic.LoadUint64(&r.bytesSent), atomic.LoadUint64(&r.bytesReceived)
+}
+
+// relayAccountingConn counts the bytes passing through the ice.Conn.
+type relayAccountingConn struct {
+	net.Conn
+	accounting *relayAccounting
+}
+
+func (c *relayAccountingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.accounting.add(0, n)
+
+	return n, err
+}
+
+func (c *relayAccountingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.accounting.add(n, 0)
+
+	return n, err
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelayAccounting(t *testing.T) {
+	accounting := &relayAccounting{budget: 10}
+
+	var exceeded int32
+	accounting.onBudgetExceeded.Store(func() {
+		atomic.AddInt32(&exceeded, 1)
+	})
+
+	local, remote := net.Pipe()
+	conn := &relayAccountingConn{Conn: local, accounting: accounting}
+	defer func() {
+		assert.NoError(t, conn.Close())
+		assert.NoError(t, remote.Close())
+	}()
+
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := remote.Read(buf)
+			if err != nil {
+				return
+			}
+			if _, err = remote.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+	}()
+
+	echo := func(payload []byte) {
+		_, err := conn.Write(payload)
+		assert.NoError(t, err)
+
+		_, err = conn.Read(make([]byte, 16))
+		assert.NoError(t, err)
+	}
+
+	// Not counted while the selected pair isn't relayed
+	echo([]byte{1, 2, 3})
+	sent, received := accounting.bytes()
+	assert.Equal(t, uint64(0), sent)
+	assert.Equal(t, uint64(0), received)
+
+	accounting.relayed.set(true)
+	echo([]byte{1, 2, 3})
+	sent, received = accounting.bytes()
+	assert.Equal(t, uint64(3), sent)
+	assert.Equal(t, uint64(3), received)
+
+	// The budget is exceeded once
+	echo([]byte{1, 2, 3})
+	echo([]byte{1, 2, 3})
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&exceeded) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&exceeded))
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// remoteBitrateCapInterval is how often the REMB of a capped track is repeated,
+// so the remote sender keeps the cap after its estimate recovers.
+const remoteBitrateCapInterval = time.Second
+
+// remoteBitrateCaps holds the bitrate caps of the remote senders of a
+// DTLSTransport, keyed by SSRC.
+type remoteBitrateCaps struct {
+	mu   sync.RWMutex
+	caps map[SSRC]uint64
+}
+
+// set caps the bitrate of ssrc, zero removes the cap.
+func (c *remoteBitrateCaps) set(ssrc SSRC, bitrate uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if bitrate == 0 {
+		delete(c.caps, ssrc)
+
+		return
+	}
+
+	if c.caps == nil {
+		c.caps = map[SSRC]uint64{}
+	}
+	c.caps[ssrc] = bitrate
+}
+
+func (c *remoteBitrateCaps) get(ssrc SSRC) (uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bitrate, ok := c.caps[ssrc]
+
+	return bitrate, ok
+}
+
+// apply lowers the REMB messages in pkts to the caps. A REMB covering capped
+// SSRCs is split, the capped SSRCs get a REMB of their own so the bitrate of
+// the other SSRCs is unchanged.
+func (c *remoteBitrateCaps) apply(pkts []rtcp.Packet) []rtcp.Packet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.caps) == 0 {
+		return pkts
+	}
+
+	out := make([]rtcp.Packet, 0, len(pkts))
+	for _, pkt := range pkts {
+		remb, ok := pkt.(*rtcp.ReceiverEstimatedMaximumBitrate)
+		if !ok {
+			out = append(out, pkt)
+
+			continue
+		}
+
+		var uncapped []uint32
+		var capped []rtcp.Packet
+		for _, ssrc := range remb.SSRCs {
+			bitrate, isCapped := c.caps[SSRC(ssrc)]
+			if !isCapped {
+				uncapped = append(uncapped, ssrc)
+
+				continue
+			}
+
+			cappedBitrate := remb.Bitrate
+			if float32(bitrate) < cappedBitrate {
+				cappedBitrate = float32(bitrate)
+			}
+			capped = append(capped, &rtcp.ReceiverEstimatedMaximumBitrate{
+				SenderSSRC: remb.SenderSSRC,
+				Bitrate:    cappedBitrate,
+				SSRCs:      []uint32{ssrc},
+			})
+		}
+
+		if len(uncapped) != 0 {
+			out = append(out, &rtcp.ReceiverEstimatedMaximumBitrate{
+				SenderSSRC: remb.SenderSSRC,
+				Bitrate:    remb.Bitrate,
+				SSRCs:      uncapped,
+			})
+		}
+		out = append(out, capped...)
+	}
+
+	return out
+}
+
+// SetMaxBitrate asks the remote sender of the track to send at most bitrate
+// bits per second, without affecting the other tracks of the PeerConnection.
+// A REMB message for the SSRC of the track alone is sent every second, and
+// the REMB messages written by interceptors or WriteRTCP that cover it are
+// lowered to the cap. The remote sender has to honor REMB, browsers do so even
+// when they estimate the bandwidth with transport-wide congestion control.
+// Zero removes the cap.
+func (t *TrackRemote) SetMaxBitrate(bitrate uint64) error {
+	ssrc := t.SSRC()
+	if ssrc == 0 {
+		return errTrackRemoteNoSSRC
+	}
+
+	transport := t.receiver.Transport()
+	transport.remoteBitrateCaps.set(ssrc, bitrate)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case bitrate == 0 && t.bitrateCapStop != nil:
+		close(t.bitrateCapStop)
+		t.bitrateCapStop = nil
+	case bitrate != 0 && t.bitrateCapStop == nil:
+		t.bitrateCapStop = make(chan struct{})
+		go t.sendBitrateCap(transport, ssrc, t.bitrateCapStop)
+	}
+
+	return nil
+}
+
+// sendBitrateCap sends the REMB of the cap until it is removed or the
+// receiver is stopped.
+func (t *TrackRemote) sendBitrateCap(transport *DTLSTransport, ssrc SSRC, stop chan struct{}) {
+	ticker := t.receiver.api.settingEngine.newTicker(remoteBitrateCapInterval)
+	defer ticker.Stop()
+
+	for {
+		if bitrate, ok := transport.remoteBitrateCaps.get(ssrc); ok {
+			if _, err := transport.WriteRTCP([]rtcp.Packet{&rtcp.ReceiverEstimatedMaximumBitrate{
+				Bitrate: float32(bitrate),
+				SSRCs:   []uint32{uint32(ssrc)},
+			}}); err != nil {
+				transport.log.Warnf("Failed to send REMB for SSRC %d: %v", ssrc, err)
+			}
+		}
+
+		select {
+		case <-ticker.Ch():
+		case <-stop:
+			return
+		case <-t.receiver.closed:
+			transport.remoteBitrateCaps.set(ssrc, 0)
+
+			return
+		}
+	}
+}
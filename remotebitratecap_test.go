@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteBitrateCaps_Apply(t *testing.T) {
+	var caps remoteBitrateCaps
+
+	remb := &rtcp.ReceiverEstimatedMaximumBitrate{SenderSSRC: 1, Bitrate: 2_000_000, SSRCs: []uint32{10, 20, 30}}
+	pli := &rtcp.PictureLossIndication{MediaSSRC: 20}
+	assert.Equal(t, []rtcp.Packet{remb, pli}, caps.apply([]rtcp.Packet{remb, pli}))
+
+	caps.set(20, 500_000)
+	caps.set(30, 5_000_000)
+	assert.Equal(t, []rtcp.Packet{
+		&rtcp.ReceiverEstimatedMaximumBitrate{SenderSSRC: 1, Bitrate: 2_000_000, SSRCs: []uint32{10}},
+		&rtcp.ReceiverEstimatedMaximumBitrate{SenderSSRC: 1, Bitrate: 500_000, SSRCs: []uint32{20}},
+		&rtcp.ReceiverEstimatedMaximumBitrate{SenderSSRC: 1, Bitrate: 2_000_000, SSRCs: []uint32{30}},
+		pli,
+	}, caps.apply([]rtcp.Packet{remb, pli}))
+
+	caps.set(20, 0)
+	caps.set(30, 0)
+	assert.Equal(t, []rtcp.Packet{remb}, caps.apply([]rtcp.Packet{remb}))
+}
+
+func TestTrackRemote_SetMaxBitrate(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	offerPC, answerPC, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	sender, err := offerPC.AddTrack(track)
+	assert.NoError(t, err)
+
+	answerPC.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		assert.NoError(t, trackRemote.SetMaxBitrate(300_000))
+	})
+
+	assert.NoError(t, signalPair(offerPC, answerPC))
+
+	capReceived, capReceivedCancel := context.WithCancel(context.Background())
+	go func() {
+		for {
+			pkts, _, readErr := sender.ReadRTCP()
+			if readErr != nil {
+				return
+			}
+
+			for _, pkt := range pkts {
+				remb, ok := pkt.(*rtcp.ReceiverEstimatedMaximumBitrate)
+				if ok && remb.Bitrate == 300_000 &&
+					len(remb.SSRCs) == 1 && SSRC(remb.SSRCs[0]) == sender.GetParameters().Encodings[0].SSRC {
+					capReceivedCancel()
+				}
+			}
+		}
+	}()
+
+	func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-capReceived.Done():
+				return
+			case <-ticker.C:
+				assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0xAA}, Duration: time.Second}))
+			}
+		}
+	}()
+
+	closePairNow(t, offerPC, answerPC)
+}
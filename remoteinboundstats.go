@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// remoteInboundStats accumulates what the remote peer reports over RTCP
+// about the reception of an encoding sent by a RTPSender.
+type remoteInboundStats struct {
+	mu sync.Mutex
+
+	received bool
+
+	fractionLost    float64
+	packetsLost     int32
+	jitter          time.Duration
+	roundTripTime   time.Duration
+	totalRTT        time.Duration
+	rttMeasurements uint64
+
+	firCount  uint32
+	pliCount  uint32
+	nackCount uint32
+}
+
+func (s *remoteInboundStats) addReport(quality RemoteQualityReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.received = true
+	s.fractionLost = quality.FractionLost
+	s.packetsLost = int32(quality.TotalLost) //nolint:gosec // G115, TotalLost is 24 bits
+	s.jitter = quality.Jitter
+
+	if quality.RoundTripTime != 0 {
+		s.roundTripTime = quality.RoundTripTime
+		s.totalRTT += quality.RoundTripTime
+		s.rttMeasurements++
+	}
+}
+
+// addFeedback counts the feedback messages about ssrc in pkt.
+func (s *remoteInboundStats) addFeedback(pkt rtcp.Packet, ssrc SSRC) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch pkt := pkt.(type) {
+	case *rtcp.PictureLossIndication:
+		if SSRC(pkt.MediaSSRC) == ssrc {
+			s.pliCount++
+		}
+	case *rtcp.FullIntraRequest:
+		for _, entry := range pkt.FIR {
+			if SSRC(entry.SSRC) == ssrc {
+				s.firCount++
+			}
+		}
+	case *rtcp.TransportLayerNack:
+		if SSRC(pkt.MediaSSRC) == ssrc {
+			s.nackCount++
+		}
+	}
+}
+
+// collectStats sets the counters of stats, it returns false if the remote
+// peer hasn't sent a report yet.
+func (s *remoteInboundStats) collectStats(stats *RemoteInboundRTPStreamStats) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.received {
+		return false
+	}
+
+	stats.FractionLost = s.fractionLost
+	stats.PacketsLost = s.packetsLost
+	stats.Jitter = s.jitter.Seconds()
+	stats.RoundTripTime = s.roundTripTime.Seconds()
+	stats.TotalRoundTripTime = s.totalRTT.Seconds()
+	stats.RoundTripTimeMeasurements = s.rttMeasurements
+	stats.FIRCount = s.firCount
+	stats.PLICount = s.pliCount
+	stats.NACKCount = s.nackCount
+
+	return true
+}
+
+func remoteInboundRTPStreamStatsID(ssrc SSRC) string {
+	return fmt.Sprintf("RemoteInboundRTPStream-%d", ssrc)
+}
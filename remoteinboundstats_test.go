@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteInboundStats(t *testing.T) {
+	stats := &remoteInboundStats{}
+
+	remoteInbound := RemoteInboundRTPStreamStats{}
+	assert.False(t, stats.collectStats(&remoteInbound), "no report received yet")
+
+	stats.addReport(RemoteQualityReport{
+		FractionLost:  0.25,
+		TotalLost:     10,
+		Jitter:        20 * time.Millisecond,
+		RoundTripTime: 100 * time.Millisecond,
+	})
+	stats.addReport(RemoteQualityReport{FractionLost: 0.5, TotalLost: 12, Jitter: 10 * time.Millisecond})
+	stats.addReport(RemoteQualityReport{TotalLost: 12, RoundTripTime: 300 * time.Millisecond})
+
+	stats.addFeedback(&rtcp.PictureLossIndication{MediaSSRC: 1234}, 1234)
+	stats.addFeedback(&rtcp.PictureLossIndication{MediaSSRC: 4321}, 1234)
+	stats.addFeedback(&rtcp.FullIntraRequest{FIR: []rtcp.FIREntry{{SSRC: 1234}}}, 1234)
+	stats.addFeedback(&rtcp.TransportLayerNack{MediaSSRC: 1234}, 1234)
+	stats.addFeedback(&rtcp.TransportLayerNack{MediaSSRC: 1234}, 1234)
+
+	assert.True(t, stats.collectStats(&remoteInbound))
+	assert.Equal(t, float64(0), remoteInbound.FractionLost)
+	assert.Equal(t, int32(12), remoteInbound.PacketsLost)
+	assert.Equal(t, float64(0), remoteInbound.Jitter)
+	assert.InDelta(t, 0.3, remoteInbound.RoundTripTime, 1e-9)
+	assert.InDelta(t, 0.4, remoteInbound.TotalRoundTripTime, 1e-9)
+	assert.Equal(t, uint64(2), remoteInbound.RoundTripTimeMeasurements)
+	assert.Equal(t, uint32(1), remoteInbound.PLICount)
+	assert.Equal(t, uint32(1), remoteInbound.FIRCount)
+	assert.Equal(t, uint32(2), remoteInbound.NACKCount)
+}
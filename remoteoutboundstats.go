@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+)
+
+const (
+	// clockOffsetGain is the gain of the smoothing of the clock offset estimate.
+	clockOffsetGain = 8
+	// minClockSkewInterval is how long the clock offset has to be observed
+	// before the clock skew is estimated from its drift.
+	minClockSkewInterval = 10 * time.Second
+)
+
+// remoteOutboundStats accumulates what the remote peer reports over RTCP
+// about an encoding it sends to a TrackRemote.
+type remoteOutboundStats struct {
+	mu sync.Mutex
+
+	reportsSent     uint64
+	packetsSent     uint32
+	bytesSent       uint64
+	remoteTimestamp time.Time
+	roundTripTime   time.Duration
+	totalRTT        time.Duration
+	rttMeasurements uint64
+
+	hasClockOffset     bool
+	clockOffset        time.Duration
+	firstClockOffset   time.Duration
+	firstClockOffsetAt time.Time
+	clockSkew          float64
+}
+
+// add updates the stats with the Sender Reports and the DLRR blocks of the
+// Extended Reports of ssrc in pkts. iceRTT is the round trip time of the ICE
+// transport, used to estimate the clock offset if the remote peer doesn't
+// send DLRR blocks.
+func (s *remoteOutboundStats) add(pkts []rtcp.Packet, ssrc SSRC, now time.Time, iceRTT time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pkt := range pkts {
+		switch pkt := pkt.(type) {
+		case *rtcp.SenderReport:
+			if SSRC(pkt.SSRC) != ssrc {
+				continue
+			}
+
+			s.reportsSent++
+			s.packetsSent = pkt.PacketCount
+			s.bytesSent = uint64(pkt.OctetCount)
+			s.remoteTimestamp = ntpTime(pkt.NTPTime)
+
+			rtt := s.roundTripTime
+			if rtt == 0 {
+				rtt = iceRTT
+			}
+			s.addClockOffset(s.remoteTimestamp, now, rtt)
+		case *rtcp.ExtendedReport:
+			if SSRC(pkt.SenderSSRC) != ssrc {
+				continue
+			}
+
+			for _, block := range pkt.Reports {
+				if dlrr, ok := block.(*rtcp.DLRRReportBlock); ok {
+					s.addDLRR(dlrr, now)
+				}
+			}
+		}
+	}
+}
+
+// addDLRR measures the round trip time as defined in RFC 3611 4.5.
+func (s *remoteOutboundStats) addDLRR(block *rtcp.DLRRReportBlock, now time.Time) {
+	for _, report := range block.Reports {
+		if report.LastRR == 0 || report.DLRR == 0 {
+			continue
+		}
+
+		rtt := middleNTP(now) - report.LastRR - report.DLRR
+		if int32(rtt) <= 0 { //nolint:gosec // G115
+			continue
+		}
+
+		s.roundTripTime = time.Duration(float64(rtt) / 65536 * float64(time.Second))
+		s.totalRTT += s.roundTripTime
+		s.rttMeasurements++
+	}
+}
+
+// addClockOffset updates the estimate of the offset of the remote clock with
+// a Sender Report sent at remoteTime that arrived at arrival. The report is
+// assumed to have been sent half a round trip before it arrived. The skew is
+// the drift of the offset since the first estimate.
+func (s *remoteOutboundStats) addClockOffset(remoteTime, arrival time.Time, rtt time.Duration) {
+	offset := remoteTime.Sub(arrival.Add(-rtt / 2))
+	if !s.hasClockOffset {
+		s.hasClockOffset = true
+		s.clockOffset, s.firstClockOffset, s.firstClockOffsetAt = offset, offset, arrival
+
+		return
+	}
+
+	s.clockOffset += (offset - s.clockOffset) / clockOffsetGain
+	if elapsed := arrival.Sub(s.firstClockOffsetAt); elapsed >= minClockSkewInterval {
+		s.clockSkew = float64(s.clockOffset-s.firstClockOffset) / float64(elapsed) * 1e6
+	}
+}
+
+// collectStats sets the counters of stats, it returns false if the remote
+// peer hasn't sent a Sender Report yet.
+func (s *remoteOutboundStats) collectStats(stats *RemoteOutboundRTPStreamStats) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reportsSent == 0 {
+		return false
+	}
+
+	stats.ReportsSent = s.reportsSent
+	stats.PacketsSent = s.packetsSent
+	stats.BytesSent = s.bytesSent
+	stats.RemoteTimestamp = statsTimestampFrom(s.remoteTimestamp)
+	stats.RoundTripTime = s.roundTripTime.Seconds()
+	stats.TotalRoundTripTime = s.totalRTT.Seconds()
+	stats.RoundTripTimeMeasurements = s.rttMeasurements
+	stats.EstimatedClockOffset = s.clockOffset.Seconds()
+	stats.EstimatedClockSkew = s.clockSkew
+
+	return true
+}
+
+// readRemoteOutbound wraps reader to update the remote-outbound-rtp stats of
+// track with the RTCP read from it, and to end the track on a RTCP BYE.
+func readRemoteOutbound(track *TrackRemote, reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(in []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attributes, err := reader.Read(in, a)
+		if err != nil {
+			return n, attributes, err
+		}
+
+		if pkts, unmarshalErr := rtcp.Unmarshal(in[:n]); unmarshalErr == nil {
+			track.mu.RLock()
+			ssrc, receiver := track.ssrc, track.receiver
+			track.mu.RUnlock()
+
+			track.remoteOutbound.add(pkts, ssrc, time.Now(), receiver.iceRoundTripTime())
+			track.handleGoodbye(pkts, ssrc)
+		}
+
+		return n, attributes, err
+	})
+}
+
+// iceRoundTripTime returns the round trip time of the selected ICE candidate
+// pair of the receiver, or 0 if it isn't known.
+func (r *RTPReceiver) iceRoundTripTime() time.Duration {
+	if r == nil || r.transport == nil || r.transport.iceTransport == nil {
+		return 0
+	}
+
+	stats, ok := r.transport.iceTransport.GetSelectedCandidatePairStats()
+	if !ok {
+		return 0
+	}
+
+	return time.Duration(stats.CurrentRoundTripTime * float64(time.Second))
+}
+
+func (t *TrackRemote) remoteOutboundRTPStreamStatsID() string {
+	return fmt.Sprintf("RemoteOutboundRTPStream-%d", t.SSRC())
+}
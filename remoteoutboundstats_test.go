@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteOutboundStats(t *testing.T) {
+	stats := &remoteOutboundStats{}
+	now := time.Now()
+
+	remoteOutbound := RemoteOutboundRTPStreamStats{}
+	assert.False(t, stats.collectStats(&remoteOutbound), "no Sender Report received yet")
+
+	remoteTime := time.Unix(1700000000, 0)
+	pkts := []rtcp.Packet{
+		&rtcp.SenderReport{SSRC: 4321, NTPTime: 1, PacketCount: 1, OctetCount: 1},
+		&rtcp.SenderReport{
+			SSRC:        1234,
+			NTPTime:     uint64(remoteTime.Unix()+ntpEpochOffset) << 32,
+			PacketCount: 100,
+			OctetCount:  120000,
+		},
+	}
+	stats.add(pkts, 1234, now, 0)
+	stats.add(pkts, 1234, now, 0)
+
+	stats.addDLRR(&rtcp.DLRRReportBlock{Reports: []rtcp.DLRRReport{
+		{SSRC: 1, LastRR: middleNTP(now.Add(-100 * time.Millisecond)), DLRR: 65536 / 20},
+		{SSRC: 1, LastRR: 0, DLRR: 65536 / 20},
+	}}, now)
+
+	assert.True(t, stats.collectStats(&remoteOutbound))
+	assert.Equal(t, uint64(2), remoteOutbound.ReportsSent)
+	assert.Equal(t, uint32(100), remoteOutbound.PacketsSent)
+	assert.Equal(t, uint64(120000), remoteOutbound.BytesSent)
+	assert.Equal(t, statsTimestampFrom(remoteTime), remoteOutbound.RemoteTimestamp)
+	assert.InDelta(t, 0.05, remoteOutbound.RoundTripTime, 0.001)
+	assert.InDelta(t, 0.05, remoteOutbound.TotalRoundTripTime, 0.001)
+	assert.Equal(t, uint64(1), remoteOutbound.RoundTripTimeMeasurements)
+}
+
+func TestRemoteOutboundStats_ClockOffset(t *testing.T) {
+	stats := &remoteOutboundStats{}
+	start := time.Unix(1700000000, 0)
+	rtt := 40 * time.Millisecond
+
+	// The remote clock is 3s ahead and runs 100ppm fast, its reports take 20ms to arrive
+	for i := 0; i <= 60; i++ {
+		elapsed := time.Duration(i) * time.Second
+		remoteTime := start.Add(3*time.Second + elapsed + elapsed/10000)
+		stats.add([]rtcp.Packet{&rtcp.SenderReport{
+			SSRC:    1234,
+			NTPTime: uint64(remoteTime.Unix()+ntpEpochOffset)<<32 | uint64(remoteTime.Nanosecond())<<32/1e9,
+		}}, 1234, start.Add(elapsed+rtt/2), rtt)
+	}
+
+	remoteOutbound := RemoteOutboundRTPStreamStats{}
+	assert.True(t, stats.collectStats(&remoteOutbound))
+	assert.InDelta(t, 3.006, remoteOutbound.EstimatedClockOffset, 0.002)
+	assert.InDelta(t, 100, remoteOutbound.EstimatedClockSkew, 15)
+}
+
+func TestNTPTime(t *testing.T) {
+	ntp := uint64(1700000000+ntpEpochOffset)<<32 | 1<<31
+	assert.Equal(t, time.Unix(1700000000, 500000000), ntpTime(ntp))
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import "time"
+
+// RemoteQualityReport is the reception quality of an encoding as reported
+// by the remote peer in a RTCP Receiver Report, or in transport-wide
+// congestion control feedback if TransportCC is set.
+type RemoteQualityReport struct {
+	// SSRC of the encoding the report is about
+	SSRC SSRC
+
+	// RID of the encoding the report is about, empty if not simulcast
+	RID string
+
+	// FractionLost is the fraction of packets lost since the previous report, between 0 and 1.
+	// For transport-wide feedback it is the fraction of the packets covered by the feedback.
+	FractionLost float64
+
+	// TotalLost is the cumulative number of packets lost
+	TotalLost uint32
+
+	// Jitter is the interarrival jitter, zero if the clock rate of the codec is unknown
+	Jitter time.Duration
+
+	// RoundTripTime is computed from the LSR and DLSR fields of the report,
+	// zero if the remote peer hasn't received a Sender Report yet
+	RoundTripTime time.Duration
+
+	// QueuingDelay is how much longer than the fastest packet sent on the
+	// transport the packets covered by transport-wide feedback took to
+	// arrive on average. It is only set if TransportCC is.
+	QueuingDelay time.Duration
+
+	// TransportCC is true if the report was computed from transport-wide
+	// congestion control feedback, then only FractionLost and QueuingDelay
+	// are set.
+	TransportCC bool
+}
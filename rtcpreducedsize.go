@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/rtcp"
+	"github.com/pion/sdp/v3"
+)
+
+// isRTCPReducedSizeSet returns true if all the audio and video sections of
+// the description signal rtcp-rsize (RFC 5506), so RTCP feedback may be sent
+// without a report. Pion always signals it.
+func isRTCPReducedSizeSet(desc *sdp.SessionDescription) bool {
+	for _, media := range desc.MediaDescriptions {
+		kind := NewRTPCodecType(media.MediaName.Media)
+		if kind == RTPCodecTypeUnknown || isMediaSectionRejected(media) {
+			continue
+		}
+
+		if _, ok := media.Attribute(sdp.AttrKeyRTCPRsize); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compoundRTCP makes the packets a compound RTCP packet as required by RFC
+// 3550 when reduced-size RTCP wasn't negotiated, feedback sent on its own is
+// preceded by an empty ReceiverReport.
+func compoundRTCP(pkts []rtcp.Packet) []rtcp.Packet {
+	if len(pkts) == 0 {
+		return pkts
+	}
+
+	switch pkts[0].(type) {
+	case *rtcp.SenderReport, *rtcp.ReceiverReport:
+		return pkts
+	}
+
+	return append([]rtcp.Packet{&rtcp.ReceiverReport{SSRC: rtcpSenderSSRC(pkts[0])}}, pkts...)
+}
+
+// rtcpSenderSSRC returns the SSRC of the sender of a feedback packet.
+func rtcpSenderSSRC(pkt rtcp.Packet) uint32 {
+	switch pkt := pkt.(type) {
+	case *rtcp.PictureLossIndication:
+		return pkt.SenderSSRC
+	case *rtcp.FullIntraRequest:
+		return pkt.SenderSSRC
+	case *rtcp.SliceLossIndication:
+		return pkt.SenderSSRC
+	case *rtcp.TransportLayerNack:
+		return pkt.SenderSSRC
+	case *rtcp.TransportLayerCC:
+		return pkt.SenderSSRC
+	case *rtcp.RapidResynchronizationRequest:
+		return pkt.SenderSSRC
+	case *rtcp.ReceiverEstimatedMaximumBitrate:
+		return pkt.SenderSSRC
+	case *rtcp.CCFeedbackReport:
+		return pkt.SenderSSRC
+	default:
+		return 0
+	}
+}
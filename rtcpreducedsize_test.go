@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRTCPReducedSizeSet(t *testing.T) {
+	media := func(kind string, port int, attributes ...sdp.Attribute) *sdp.MediaDescription {
+		return &sdp.MediaDescription{
+			MediaName:  sdp.MediaName{Media: kind, Port: sdp.RangedPort{Value: port}},
+			Attributes: attributes,
+		}
+	}
+	rsize := sdp.NewPropertyAttribute(sdp.AttrKeyRTCPRsize)
+
+	assert.True(t, isRTCPReducedSizeSet(&sdp.SessionDescription{}))
+	assert.True(t, isRTCPReducedSizeSet(&sdp.SessionDescription{MediaDescriptions: []*sdp.MediaDescription{
+		media("audio", 9, rsize), media("video", 9, rsize), media("application", 9),
+	}}))
+	// Rejected sections don't count
+	assert.True(t, isRTCPReducedSizeSet(&sdp.SessionDescription{MediaDescriptions: []*sdp.MediaDescription{
+		media("audio", 9, rsize), media("video", 0),
+	}}))
+	assert.False(t, isRTCPReducedSizeSet(&sdp.SessionDescription{MediaDescriptions: []*sdp.MediaDescription{
+		media("audio", 9, rsize), media("video", 9),
+	}}))
+}
+
+func TestCompoundRTCP(t *testing.T) {
+	pli := &rtcp.PictureLossIndication{SenderSSRC: 1, MediaSSRC: 2}
+	assert.Equal(t, []rtcp.Packet{&rtcp.ReceiverReport{SSRC: 1}, pli}, compoundRTCP([]rtcp.Packet{pli}))
+
+	report := &rtcp.ReceiverReport{SSRC: 3}
+	assert.Equal(t, []rtcp.Packet{report, pli}, compoundRTCP([]rtcp.Packet{report, pli}))
+
+	assert.Empty(t, compoundRTCP(nil))
+}
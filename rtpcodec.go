@@ -4,12 +4,57 @@
 package webrtc
 
 import (
-	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/pion/webrtc/v4/internal/fmtp"
 )
 
+const (
+	// MimeTypeH264 H264 MIME type.
+	// Note: Matching should be case insensitive.
+	MimeTypeH264 = "video/H264"
+	// MimeTypeH265 H265 MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeH265 = "video/H265"
+	// MimeTypeOpus Opus MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeOpus = "audio/opus"
+	// MimeTypeVP8 VP8 MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeVP8 = "video/VP8"
+	// MimeTypeVP9 VP9 MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeVP9 = "video/VP9"
+	// MimeTypeAV1 AV1 MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeAV1 = "video/AV1"
+	// MimeTypeG722 G722 MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeG722 = "audio/G722"
+	// MimeTypePCMU PCMU MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypePCMU = "audio/PCMU"
+	// MimeTypePCMA PCMA MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypePCMA = "audio/PCMA"
+	// MimeTypeRTX RTX MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeRTX = "video/rtx"
+	// MimeTypeFlexFEC FEC MIME Type
+	// Note: Matching should be case insensitive.
+	MimeTypeFlexFEC = "video/flexfec"
+	// MimeTypeRED RED (redundant audio data) MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeRED = "audio/red"
+	// MimeTypeVideoRED RED MIME type for video, it carries the media and ULPFEC packets
+	// Note: Matching should be case insensitive.
+	MimeTypeVideoRED = "video/red"
+	// MimeTypeULPFEC ULPFEC MIME type
+	// Note: Matching should be case insensitive.
+	MimeTypeULPFEC = "video/ulpfec"
+)
+
 // RTPCodecType determines the type of a codec.
 type RTPCodecType int
 
@@ -93,46 +138,64 @@ type RTPParameters struct {
 	Codecs           []RTPCodecParameters
 }
 
-type codecMatchType int
+// CodecMatchType describes how well a remote codec matches a local one.
+type CodecMatchType int
 
 const (
-	codecMatchNone    codecMatchType = 0
-	codecMatchPartial codecMatchType = 1
-	codecMatchExact   codecMatchType = 2
+	// CodecMatchNone means the codecs don't match.
+	CodecMatchNone CodecMatchType = 0
+	// CodecMatchPartial means the MimeType matches but the fmtp parameters don't.
+	CodecMatchPartial CodecMatchType = 1
+	// CodecMatchExact means the MimeType and the fmtp parameters match.
+	CodecMatchExact CodecMatchType = 2
 )
 
+// CodecMatcher looks up a codec offered by the remote peer in the local codecs
+// of the same kind registered in the MediaEngine. It returns the local codec and
+// how well it matched. For codecs with an apt parameter (RTX) the apt has already
+// been rewritten to the payload type of the local codec it applies to.
+//
+// The default matcher compares the MimeType and fmtp parameters and falls back to
+// the MimeType only, see MediaEngine.SetCodecMatcher for the rules around it.
+type CodecMatcher func(remote RTPCodecParameters, local []RTPCodecParameters) (RTPCodecParameters, CodecMatchType)
+
 // Do a fuzzy find for a codec in the list of codecs
 // Used for lookup up a codec in an existing list to find a match
-// Returns codecMatchExact, codecMatchPartial, or codecMatchNone.
+// Returns CodecMatchExact, CodecMatchPartial, or CodecMatchNone.
 func codecParametersFuzzySearch(
 	needle RTPCodecParameters,
 	haystack []RTPCodecParameters,
-) (RTPCodecParameters, codecMatchType) {
+) (RTPCodecParameters, CodecMatchType) {
 	needleFmtp := fmtp.Parse(needle.RTPCodecCapability.MimeType, needle.RTPCodecCapability.SDPFmtpLine)
 
 	// First attempt to match on MimeType + SDPFmtpLine
 	for _, c := range haystack {
 		cfmtp := fmtp.Parse(c.RTPCodecCapability.MimeType, c.RTPCodecCapability.SDPFmtpLine)
 		if needleFmtp.Match(cfmtp) {
-			return c, codecMatchExact
+			return c, CodecMatchExact
 		}
 	}
 
 	// Fallback to just MimeType
 	for _, c := range haystack {
 		if strings.EqualFold(c.RTPCodecCapability.MimeType, needle.RTPCodecCapability.MimeType) {
-			return c, codecMatchPartial
+			return c, CodecMatchPartial
 		}
 	}
 
-	return RTPCodecParameters{}, codecMatchNone
+	return RTPCodecParameters{}, CodecMatchNone
 }
 
 // Given a CodecParameters find the RTX CodecParameters if one exists.
 func findRTXPayloadType(needle PayloadType, haystack []RTPCodecParameters) PayloadType {
-	aptStr := fmt.Sprintf("apt=%d", needle)
+	aptStr := strconv.Itoa(int(needle))
 	for _, c := range haystack {
-		if aptStr == c.SDPFmtpLine {
+		if !strings.EqualFold(c.MimeType, MimeTypeRTX) {
+			continue
+		}
+
+		// fmtp may carry more parameters than apt, e.g. "apt=96;rtx-time=3000"
+		if apt, ok := fmtp.Parse(c.MimeType, c.SDPFmtpLine).Parameter("apt"); ok && apt == aptStr {
 			return c.PayloadType
 		}
 	}
@@ -140,6 +203,90 @@ func findRTXPayloadType(needle PayloadType, haystack []RTPCodecParameters) Paylo
 	return PayloadType(0)
 }
 
+// findREDPayloadType returns the payload type of the RED codec that carries
+// needle for all of its encodings, e.g. "111/111".
+func findREDPayloadType(needle PayloadType, haystack []RTPCodecParameters) PayloadType {
+	payloadType := strconv.Itoa(int(needle))
+	for _, c := range haystack {
+		if !strings.EqualFold(c.MimeType, MimeTypeRED) || c.SDPFmtpLine == "" {
+			continue
+		}
+
+		carriesNeedle := true
+		for _, encoding := range strings.Split(c.SDPFmtpLine, "/") {
+			if strings.TrimSpace(encoding) != payloadType {
+				carriesNeedle = false
+
+				break
+			}
+		}
+
+		if carriesNeedle {
+			return c.PayloadType
+		}
+	}
+
+	return PayloadType(0)
+}
+
+// findULPFECPayloadTypes returns the payload types of the video RED and ULPFEC
+// codecs, or zero if haystack doesn't have both of them.
+func findULPFECPayloadTypes(haystack []RTPCodecParameters) (red, ulpfec PayloadType) {
+	for _, c := range haystack {
+		switch {
+		case strings.EqualFold(c.MimeType, MimeTypeVideoRED):
+			red = c.PayloadType
+		case strings.EqualFold(c.MimeType, MimeTypeULPFEC):
+			ulpfec = c.PayloadType
+		}
+	}
+
+	if red == 0 || ulpfec == 0 {
+		return 0, 0
+	}
+
+	return red, ulpfec
+}
+
+// isResiliencyCodec returns true for codecs that only protect other codecs
+// (RTX, RED and FEC) and can't carry media on their own.
+func isResiliencyCodec(codec RTPCodecParameters) bool {
+	_, subtype, _ := strings.Cut(codec.MimeType, "/")
+	switch strings.ToLower(subtype) {
+	case "rtx", "red", "ulpfec", "flexfec", "flexfec-03":
+		return true
+	default:
+		return false
+	}
+}
+
+// removeDanglingRTX removes the RTX codecs whose associated payload type
+// is not in codecs anymore.
+func removeDanglingRTX(codecs []RTPCodecParameters) []RTPCodecParameters {
+	filtered := make([]RTPCodecParameters, 0, len(codecs))
+	for _, codec := range codecs {
+		if strings.EqualFold(codec.MimeType, MimeTypeRTX) {
+			apt, hasApt := fmtp.Parse(codec.MimeType, codec.SDPFmtpLine).Parameter("apt")
+			if hasApt && !payloadTypeInCodecs(apt, codecs) {
+				continue
+			}
+		}
+		filtered = append(filtered, codec)
+	}
+
+	return filtered
+}
+
+func payloadTypeInCodecs(payloadType string, codecs []RTPCodecParameters) bool {
+	for _, codec := range codecs {
+		if strconv.Itoa(int(codec.PayloadType)) == payloadType {
+			return true
+		}
+	}
+
+	return false
+}
+
 func rtcpFeedbackIntersection(a, b []RTCPFeedback) (out []RTCPFeedback) {
 	for _, aFeedback := range a {
 		for _, bFeeback := range b {
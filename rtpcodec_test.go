@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindRTXPayloadType(t *testing.T) {
+	codecs := []RTPCodecParameters{
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeVP8, 90000, 0, "", nil}, PayloadType: 96},
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeRTX, 90000, 0, "apt=96", nil}, PayloadType: 97},
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeVP9, 90000, 0, "profile-id=0", nil}, PayloadType: 98},
+		{RTPCodecCapability: RTPCodecCapability{"video/RTX", 90000, 0, "apt=98;rtx-time=3000", nil}, PayloadType: 99},
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeH264, 90000, 0, "", nil}, PayloadType: 102},
+		{RTPCodecCapability: RTPCodecCapability{"video/red", 90000, 0, "apt=102", nil}, PayloadType: 103},
+	}
+
+	assert.Equal(t, PayloadType(97), findRTXPayloadType(96, codecs))
+	assert.Equal(t, PayloadType(99), findRTXPayloadType(98, codecs))
+	assert.Equal(t, PayloadType(0), findRTXPayloadType(102, codecs))
+	assert.Equal(t, PayloadType(0), findRTXPayloadType(9, codecs))
+}
+
+func TestFindREDPayloadType(t *testing.T) {
+	codecs := []RTPCodecParameters{
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeRED, 48000, 2, "111/111", nil}, PayloadType: 63},
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeOpus, 48000, 2, "", nil}, PayloadType: 111},
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeRED, 48000, 2, "111/9", nil}, PayloadType: 64},
+	}
+
+	assert.Equal(t, PayloadType(63), findREDPayloadType(111, codecs))
+	assert.Equal(t, PayloadType(0), findREDPayloadType(9, codecs))
+}
+
+func TestFindULPFECPayloadTypes(t *testing.T) {
+	codecs := []RTPCodecParameters{
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeVP8, 90000, 0, "", nil}, PayloadType: 96},
+		{RTPCodecCapability: RTPCodecCapability{MimeTypeVideoRED, 90000, 0, "", nil}, PayloadType: 116},
+		{RTPCodecCapability: RTPCodecCapability{"video/ULPFEC", 90000, 0, "", nil}, PayloadType: 117},
+	}
+
+	red, ulpfec := findULPFECPayloadTypes(codecs)
+	assert.Equal(t, PayloadType(116), red)
+	assert.Equal(t, PayloadType(117), ulpfec)
+
+	red, ulpfec = findULPFECPayloadTypes(codecs[:2])
+	assert.Equal(t, PayloadType(0), red)
+	assert.Equal(t, PayloadType(0), ulpfec)
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
+)
+
+const (
+	// CSRCAudioLevelURI is the URI of the RFC 6465 header extension carrying
+	// the audio levels of the contributing sources of a mixed RTP packet.
+	CSRCAudioLevelURI = "urn:ietf:params:rtp-hdrext:csrc-audio-level"
+
+	// contributingSourceLifetime is how long a source is reported after the
+	// last packet it contributed to.
+	contributingSourceLifetime = 10 * time.Second
+
+	// maxCSRC is the most CSRCs a RTP packet carries, RFC 3550 Section 5.1.
+	maxCSRC = 15
+
+	// audioLevelSilence is the -dBov level of silence, RFC 6464 Section 3.
+	audioLevelSilence = 127
+)
+
+// RTPContributingSource describes a source of the RTP packets received by a
+// RTPReceiver, as of the most recent packet it contributed to.
+// https://www.w3.org/TR/webrtc/#dom-rtcrtpcontributingsource
+type RTPContributingSource struct {
+	// Timestamp is the time the most recent packet of the source was read.
+	Timestamp time.Time
+	// Source is the SSRC or CSRC of the source.
+	Source uint32
+	// AudioLevel is the audio level of the source in the most recent packet,
+	// between 0 for silence and 1 for 0 dBov, read from the
+	// urn:ietf:params:rtp-hdrext:ssrc-audio-level or CSRCAudioLevelURI header
+	// extensions. It is nil if the packet didn't carry it.
+	AudioLevel *float64
+	// RTPTimestamp is the RTP timestamp of the most recent packet.
+	RTPTimestamp uint32
+}
+
+// RTPSynchronizationSource describes a synchronization source (SSRC) of the
+// RTP packets received by a RTPReceiver.
+// https://www.w3.org/TR/webrtc/#dom-rtcrtpsynchronizationsource
+type RTPSynchronizationSource struct {
+	RTPContributingSource
+}
+
+// contributingSources are the sources of the packets read from a RTPReceiver
+// in the last 10 seconds.
+type contributingSources struct {
+	mu    sync.Mutex
+	ssrcs map[uint32]RTPContributingSource
+	csrcs map[uint32]RTPContributingSource
+}
+
+// audioLevel converts a -dBov level to the linear audio level of the W3C API.
+func audioLevel(level uint8) *float64 {
+	linear := 0.0
+	if level < audioLevelSilence {
+		linear = math.Pow(10, -float64(level)/20)
+	}
+
+	return &linear
+}
+
+// add records the sources of the packet with header, ssrcLevelID and
+// csrcLevelID are the negotiated IDs of the audio level extensions or 0.
+func (c *contributingSources) add(header *rtp.Header, ssrcLevelID, csrcLevelID uint8, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ssrcs == nil {
+		c.ssrcs = map[uint32]RTPContributingSource{}
+		c.csrcs = map[uint32]RTPContributingSource{}
+	}
+
+	source := RTPContributingSource{Timestamp: now, Source: header.SSRC, RTPTimestamp: header.Timestamp}
+	if payload := header.GetExtension(ssrcLevelID); ssrcLevelID != 0 && len(payload) > 0 {
+		source.AudioLevel = audioLevel(payload[0] & 0x7f)
+	}
+	c.set(c.ssrcs, source)
+
+	var csrcLevels []byte
+	if csrcLevelID != 0 {
+		csrcLevels = header.GetExtension(csrcLevelID)
+	}
+	for i, csrc := range header.CSRC {
+		source = RTPContributingSource{Timestamp: now, Source: csrc, RTPTimestamp: header.Timestamp}
+		if i < len(csrcLevels) {
+			source.AudioLevel = audioLevel(csrcLevels[i] & 0x7f)
+		}
+		c.set(c.csrcs, source)
+	}
+}
+
+// set stores source, the expired sources are removed when a new one appears
+// so the map doesn't grow with the churn of the CSRCs.
+func (c *contributingSources) set(sources map[uint32]RTPContributingSource, source RTPContributingSource) {
+	if _, ok := sources[source.Source]; !ok {
+		for key, s := range sources {
+			if source.Timestamp.Sub(s.Timestamp) > contributingSourceLifetime {
+				delete(sources, key)
+			}
+		}
+	}
+	sources[source.Source] = source
+}
+
+// get returns the sources seen in the last 10 seconds, the most recent first.
+func (c *contributingSources) get(sources map[uint32]RTPContributingSource, now time.Time) []RTPContributingSource {
+	out := []RTPContributingSource{}
+	for _, s := range sources {
+		if now.Sub(s.Timestamp) <= contributingSourceLifetime {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Timestamp.After(out[j].Timestamp)
+	})
+
+	return out
+}
+
+// GetSynchronizationSources returns the SSRCs of the packets read from the
+// RTPReceiver in the last 10 seconds, the most recent first.
+// https://www.w3.org/TR/webrtc/#dom-rtcrtpreceiver-getsynchronizationsources
+func (r *RTPReceiver) GetSynchronizationSources() []RTPSynchronizationSource {
+	r.sources.mu.Lock()
+	defer r.sources.mu.Unlock()
+
+	sources := r.sources.get(r.sources.ssrcs, time.Now())
+	out := make([]RTPSynchronizationSource, 0, len(sources))
+	for _, s := range sources {
+		out = append(out, RTPSynchronizationSource{RTPContributingSource: s})
+	}
+
+	return out
+}
+
+// GetContributingSources returns the CSRCs of the packets read from the
+// RTPReceiver in the last 10 seconds, the most recent first. A mixer lists
+// the sources it mixed as CSRCs, with their audio levels if it supports
+// CSRCAudioLevelURI, which allows indicating the talkers.
+// https://www.w3.org/TR/webrtc/#dom-rtcrtpreceiver-getcontributingsources
+func (r *RTPReceiver) GetContributingSources() []RTPContributingSource {
+	r.sources.mu.Lock()
+	defer r.sources.mu.Unlock()
+
+	return r.sources.get(r.sources.csrcs, time.Now())
+}
+
+// addContributingSources records the sources of a packet read from the track.
+func (t *TrackRemote) addContributingSources(receiver *RTPReceiver, packet []byte, now time.Time) {
+	header := rtp.Header{}
+	if _, err := header.Unmarshal(packet); err != nil {
+		return
+	}
+
+	t.mu.RLock()
+	ssrcLevelID := headerExtensionID(t.params.HeaderExtensions, sdp.AudioLevelURI)
+	csrcLevelID := headerExtensionID(t.params.HeaderExtensions, CSRCAudioLevelURI)
+	t.mu.RUnlock()
+
+	receiver.sources.add(&header, ssrcLevelID, csrcLevelID, now)
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioLevel(t *testing.T) {
+	assert.Equal(t, 1.0, *audioLevel(0))
+	assert.InDelta(t, 0.1, *audioLevel(20), 1e-9)
+	assert.Equal(t, 0.0, *audioLevel(audioLevelSilence))
+}
+
+func TestContributingSources(t *testing.T) {
+	sources := contributingSources{}
+	start := time.Unix(0, 0)
+
+	header := &rtp.Header{Timestamp: 960, SSRC: 1, CSRC: []uint32{10, 11}}
+	assert.NoError(t, header.SetExtension(1, []byte{0x80 | 20}))
+	assert.NoError(t, header.SetExtension(2, []byte{0, audioLevelSilence}))
+	sources.add(header, 1, 2, start)
+
+	ssrcs := sources.get(sources.ssrcs, start)
+	if assert.Len(t, ssrcs, 1) {
+		assert.Equal(t, uint32(1), ssrcs[0].Source)
+		assert.Equal(t, uint32(960), ssrcs[0].RTPTimestamp)
+		assert.Equal(t, start, ssrcs[0].Timestamp)
+		assert.InDelta(t, 0.1, *ssrcs[0].AudioLevel, 1e-9)
+	}
+
+	csrcs := sources.get(sources.csrcs, start)
+	if assert.Len(t, csrcs, 2) {
+		levels := map[uint32]float64{}
+		for _, csrc := range csrcs {
+			levels[csrc.Source] = *csrc.AudioLevel
+		}
+		assert.Equal(t, map[uint32]float64{10: 1, 11: 0}, levels)
+	}
+
+	// Without the extensions the audio levels are unknown, the most recent source comes first
+	sources.add(&rtp.Header{Timestamp: 1920, SSRC: 2, CSRC: []uint32{10}}, 0, 0, start.Add(time.Second))
+	ssrcs = sources.get(sources.ssrcs, start.Add(time.Second))
+	if assert.Len(t, ssrcs, 2) {
+		assert.Equal(t, uint32(2), ssrcs[0].Source)
+		assert.Nil(t, ssrcs[0].AudioLevel)
+	}
+
+	// The sources are reported for 10 seconds
+	csrcs = sources.get(sources.csrcs, start.Add(10*time.Second+time.Millisecond))
+	if assert.Len(t, csrcs, 1) {
+		assert.Equal(t, uint32(10), csrcs[0].Source)
+	}
+
+	// Expired sources are removed once a new one appears
+	sources.add(&rtp.Header{SSRC: 3}, 0, 0, start.Add(time.Minute))
+	assert.Len(t, sources.ssrcs, 1)
+}
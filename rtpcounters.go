@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// rtpCounters counts the RTP packets and bytes of a stream.
+type rtpCounters struct {
+	mu sync.Mutex
+
+	packets      uint32
+	headerBytes  uint64
+	payloadBytes uint64
+	last         time.Time
+}
+
+// addSizes counts a packet, headerBytes includes the padding.
+func (c *rtpCounters) addSizes(headerBytes, payloadBytes int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.packets++
+	c.headerBytes += uint64(headerBytes)   //nolint:gosec // G115
+	c.payloadBytes += uint64(payloadBytes) //nolint:gosec // G115
+	c.last = now
+}
+
+// add counts a marshaled RTP packet, packets too short to be RTP are ignored.
+func (c *rtpCounters) add(packet []byte, now time.Time) {
+	headerBytes, ok := rtpHeaderAndPaddingSize(packet)
+	if !ok {
+		return
+	}
+
+	c.addSizes(headerBytes, len(packet)-headerBytes, now)
+}
+
+func (c *rtpCounters) snapshot() (packets uint32, headerBytes, payloadBytes uint64, last time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.packets, c.headerBytes, c.payloadBytes, c.last
+}
+
+// rtpHeaderAndPaddingSize returns the size of the header, CSRCs, header
+// extension and padding of a RTP packet, without unmarshaling it.
+func rtpHeaderAndPaddingSize(packet []byte) (int, bool) {
+	const fixedHeaderSize = 12
+	if len(packet) < fixedHeaderSize {
+		return 0, false
+	}
+
+	size := fixedHeaderSize + int(packet[0]&0x0F)*4
+	if packet[0]&0x10 != 0 {
+		if len(packet) < size+4 {
+			return 0, false
+		}
+		size += 4 + int(binary.BigEndian.Uint16(packet[size+2:size+4]))*4
+	}
+	if packet[0]&0x20 != 0 && len(packet) > size {
+		size += int(packet[len(packet)-1])
+	}
+	if size > len(packet) {
+		return 0, false
+	}
+
+	return size, true
+}
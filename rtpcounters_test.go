@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTPCounters(t *testing.T) {
+	header := rtp.Header{Version: 2, SSRC: 1, CSRC: []uint32{2}}
+	assert.NoError(t, header.SetExtension(1, []byte{0x01, 0x02}))
+	packet := &rtp.Packet{Header: header, Payload: make([]byte, 100)}
+	raw, err := packet.Marshal()
+	assert.NoError(t, err)
+
+	headerBytes, ok := rtpHeaderAndPaddingSize(raw)
+	assert.True(t, ok)
+	assert.Equal(t, header.MarshalSize(), headerBytes)
+
+	padded := &rtp.Packet{Header: rtp.Header{Version: 2, Padding: true, PaddingSize: 3}, Payload: []byte{0x01}}
+	raw2, err := padded.Marshal()
+	assert.NoError(t, err)
+	headerBytes, ok = rtpHeaderAndPaddingSize(raw2)
+	assert.True(t, ok)
+	assert.Equal(t, 12+3, headerBytes)
+
+	_, ok = rtpHeaderAndPaddingSize([]byte{0x80, 0x00})
+	assert.False(t, ok)
+
+	now := time.Now()
+	var counters rtpCounters
+	counters.add(raw, now)
+	counters.add(raw2, now)
+	counters.add([]byte{0x80}, now)
+
+	packets, headerTotal, payloadTotal, last := counters.snapshot()
+	assert.Equal(t, uint32(2), packets)
+	assert.Equal(t, uint64(header.MarshalSize()+15), headerTotal)
+	assert.Equal(t, uint64(101), payloadTotal)
+	assert.Equal(t, now, last)
+}
@@ -8,4 +8,27 @@ package webrtc
 // http://draft.ortc.org/#dom-rtcrtpencodingparameters
 type RTPEncodingParameters struct {
 	RTPCodingParameters
+
+	// Active indicates that the encoding is being sent. The default value of
+	// nil means active. Setting it to false with RTPSender.SetParameters stops
+	// sending RTP for the encoding without renegotiation.
+	Active *bool `json:"active,omitempty"`
+
+	// MaxBitrate is the maximum bitrate in bits per second the encoding is
+	// sent at, zero means unlimited. The RTP packets of a video encoding are
+	// paced to it, audio is sent as it is written. Pion doesn't encode media
+	// itself, the value is made available to interceptors and to the
+	// application too.
+	MaxBitrate uint64 `json:"maxBitrate,omitempty"`
+
+	// ScaleResolutionDownBy is the factor video resolution of the encoding is
+	// scaled down by, zero means unset. Pion doesn't encode video, so it is
+	// informational: it is passed to interceptors and returned by
+	// RTPSender.GetParameters for the application's encoder.
+	ScaleResolutionDownBy float64 `json:"scaleResolutionDownBy,omitempty"`
+
+	// ScalabilityMode is the SVC mode of the encoding as defined in
+	// https://www.w3.org/TR/webrtc-svc/, e.g. "L1T3" or "L3T3_KEY". Only
+	// applies to VP9 and AV1, empty means the encoding is not scalable.
+	ScalabilityMode string `json:"scalabilityMode,omitempty"`
 }
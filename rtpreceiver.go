@@ -15,7 +15,6 @@ import (
 
 	"github.com/pion/interceptor"
 	"github.com/pion/rtcp"
-	"github.com/pion/srtp/v3"
 	"github.com/pion/webrtc/v4/internal/util"
 )
 
@@ -26,17 +25,17 @@ type trackStreams struct {
 
 	streamInfo, repairStreamInfo *interceptor.StreamInfo
 
-	rtpReadStream  *srtp.ReadStreamSRTP
+	rtpReadStream  SRTPReadStream
 	rtpInterceptor interceptor.RTPReader
 
-	rtcpReadStream  *srtp.ReadStreamSRTCP
+	rtcpReadStream  SRTPReadStream
 	rtcpInterceptor interceptor.RTCPReader
 
-	repairReadStream    *srtp.ReadStreamSRTP
+	repairReadStream    SRTPReadStream
 	repairInterceptor   interceptor.RTPReader
 	repairStreamChannel chan rtxPacketWithAttributes
 
-	repairRtcpReadStream  *srtp.ReadStreamSRTCP
+	repairRtcpReadStream  SRTPReadStream
 	repairRtcpInterceptor interceptor.RTCPReader
 }
 
@@ -70,6 +69,8 @@ type RTPReceiver struct {
 	api *API
 
 	rtxPool sync.Pool
+
+	sources contributingSources
 }
 
 // NewRTPReceiver constructs a new RTPReceiver.
@@ -179,6 +180,7 @@ func (r *RTPReceiver) configureReceive(parameters RTPReceiveParameters) {
 				r,
 			),
 		}
+		t.track.jitterBufferConfig = r.api.latencyMode.jitterBufferConfig(r.kind)
 
 		r.tracks = append(r.tracks, t)
 	}
@@ -226,12 +228,14 @@ func (r *RTPReceiver) startReceive(parameters RTPReceiveParameters) error { //no
 			codec,
 			globalParams.HeaderExtensions,
 		)
+		setULPFECStreamInfo(streams.streamInfo, globalParams.Codecs)
 		var err error
 
 		//nolint:lll // # TODO refactor
 		if streams.rtpReadStream, streams.rtpInterceptor, streams.rtcpReadStream, streams.rtcpInterceptor, err = r.transport.streamsForSSRC(parameters.Encodings[i].SSRC, *streams.streamInfo); err != nil {
 			return err
 		}
+		streams.rtcpInterceptor = readRemoteOutbound(streams.track, streams.rtcpInterceptor)
 
 		if rtxSsrc := parameters.Encodings[i].RTX.SSRC; rtxSsrc != 0 {
 			streamInfo := createStreamInfo("", rtxSsrc, 0, 0, 0, 0, 0, codec, globalParams.HeaderExtensions)
@@ -341,6 +345,61 @@ func (r *RTPReceiver) haveReceived() bool {
 	}
 }
 
+// collectStats reports an InboundRTPStreamStats for every track receiving packets.
+func (r *RTPReceiver) collectStats(collector *statsReportCollector) {
+	if !r.haveReceived() {
+		return
+	}
+
+	mid := ""
+	if tr := r.RTPTransceiver(); tr != nil {
+		mid = tr.Mid()
+	}
+
+	for _, track := range r.Tracks() {
+		ssrc := track.SSRC()
+		if ssrc == 0 {
+			continue
+		}
+
+		collector.Collecting()
+		stats := InboundRTPStreamStats{
+			Mid:       mid,
+			Timestamp: statsTimestampNow(),
+			Type:      StatsTypeInboundRTP,
+			ID:        track.inboundRTPStreamStatsID(),
+			SSRC:      ssrc,
+			Kind:      track.Kind().String(),
+			TrackID:   track.ID(),
+		}
+		track.lossStats.collectStats(&stats)
+
+		packets, headerBytes, payloadBytes, last := track.counters.snapshot()
+		stats.PacketsReceived = packets
+		stats.HeaderBytesReceived = headerBytes
+		stats.BytesReceived = payloadBytes
+		if !last.IsZero() {
+			stats.LastPacketReceivedTimestamp = statsTimestampFrom(last)
+		}
+
+		collector.Collect(stats.ID, stats)
+
+		remoteOutbound := RemoteOutboundRTPStreamStats{
+			Timestamp:   statsTimestampNow(),
+			Type:        StatsTypeRemoteOutboundRTP,
+			ID:          track.remoteOutboundRTPStreamStatsID(),
+			SSRC:        ssrc,
+			Kind:        track.Kind().String(),
+			TransportID: "iceTransport",
+			LocalID:     stats.ID,
+		}
+		if track.remoteOutbound.collectStats(&remoteOutbound) {
+			collector.Collecting()
+			collector.Collect(remoteOutbound.ID, remoteOutbound)
+		}
+	}
+}
+
 // Stop irreversibly stops the RTPReceiver.
 func (r *RTPReceiver) Stop() error { //nolint:cyclop
 	r.mu.Lock()
@@ -389,6 +448,13 @@ func (r *RTPReceiver) Stop() error { //nolint:cyclop
 
 	close(r.closed)
 
+	for i := range r.tracks {
+		if r.transport != nil {
+			r.transport.arrivals.forget(r.tracks[i].track.SSRC())
+		}
+		r.tracks[i].track.end(TrackEndReasonStopped)
+	}
+
 	return err
 }
 
@@ -418,9 +484,9 @@ func (r *RTPReceiver) receiveForRid(
 	rid string,
 	params RTPParameters,
 	streamInfo *interceptor.StreamInfo,
-	rtpReadStream *srtp.ReadStreamSRTP,
+	rtpReadStream SRTPReadStream,
 	rtpInterceptor interceptor.RTPReader,
-	rtcpReadStream *srtp.ReadStreamSRTCP,
+	rtcpReadStream SRTPReadStream,
 	rtcpInterceptor interceptor.RTCPReader,
 ) (*TrackRemote, error) {
 	r.mu.Lock()
@@ -439,7 +505,7 @@ func (r *RTPReceiver) receiveForRid(
 			r.tracks[i].rtpReadStream = rtpReadStream
 			r.tracks[i].rtpInterceptor = rtpInterceptor
 			r.tracks[i].rtcpReadStream = rtcpReadStream
-			r.tracks[i].rtcpInterceptor = rtcpInterceptor
+			r.tracks[i].rtcpInterceptor = readRemoteOutbound(r.tracks[i].track, rtcpInterceptor)
 
 			return r.tracks[i].track, nil
 		}
@@ -455,14 +521,17 @@ func (r *RTPReceiver) receiveForRtx(
 	ssrc SSRC,
 	rsid string,
 	streamInfo *interceptor.StreamInfo,
-	rtpReadStream *srtp.ReadStreamSRTP,
+	rtpReadStream SRTPReadStream,
 	rtpInterceptor interceptor.RTPReader,
-	rtcpReadStream *srtp.ReadStreamSRTCP,
+	rtcpReadStream SRTPReadStream,
 	rtcpInterceptor interceptor.RTCPReader,
 ) error {
 	var track *trackStreams
 	if ssrc != 0 && len(r.tracks) == 1 {
 		track = &r.tracks[0]
+		if track.track.RtxSSRC() == 0 {
+			track.track.setRtxSSRC(ssrc)
+		}
 	} else {
 		for i := range r.tracks {
 			if r.tracks[i].track.RID() == rsid {
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync/atomic"
+
+	"github.com/pion/logging"
+	"github.com/pion/sdp/v3"
+)
+
+// rtpRoutes is a snapshot of how incoming SSRCs are routed for one remote
+// description. It is never modified once stored, so it can be read without
+// holding the PeerConnection lock.
+type rtpRoutes struct {
+	remoteDescription *sdp.SessionDescription
+
+	// declaredSSRCs are the SSRCs (and repair SSRCs) the remote description
+	// signals, they are handled by the receivers directly
+	declaredSSRCs map[SSRC]struct{}
+
+	// transceivers are the transceivers by mid when the snapshot was taken
+	transceivers map[string]*RTPTransceiver
+}
+
+// rtpRouter routes SSRCs that are not known yet to the transceivers with
+// the MID and RID header extensions. The snapshot is rebuilt when the remote
+// description changes, lookups missing it fall back to scanning the
+// transceivers.
+type rtpRouter struct {
+	routes atomic.Value // *rtpRoutes
+}
+
+// get returns the routes for the remote description, building them if the
+// remote description changed since the last call.
+func (r *rtpRouter) get(
+	remoteDescription *sdp.SessionDescription,
+	transceivers func() []*RTPTransceiver,
+	log logging.LeveledLogger,
+) *rtpRoutes {
+	if routes, ok := r.routes.Load().(*rtpRoutes); ok && routes.remoteDescription == remoteDescription {
+		return routes
+	}
+
+	routes := &rtpRoutes{
+		remoteDescription: remoteDescription,
+		declaredSSRCs:     map[SSRC]struct{}{},
+		transceivers:      map[string]*RTPTransceiver{},
+	}
+
+	for _, track := range trackDetailsFromSDP(log, remoteDescription) {
+		if track.repairSsrc != nil {
+			routes.declaredSSRCs[*track.repairSsrc] = struct{}{}
+		}
+		for _, ssrc := range track.ssrcs {
+			routes.declaredSSRCs[ssrc] = struct{}{}
+		}
+	}
+
+	for _, t := range transceivers() {
+		if mid := t.Mid(); mid != "" {
+			routes.transceivers[mid] = t
+		}
+	}
+
+	r.routes.Store(routes)
+
+	return routes
+}
+
+func (r *rtpRoutes) isDeclared(ssrc SSRC) bool {
+	_, ok := r.declaredSSRCs[ssrc]
+
+	return ok
+}
+
+// transceiverByMid returns the transceiver with the mid. The snapshot is
+// checked first, transceivers that got their mid after it was taken are
+// found by scanning all of them.
+func (r *rtpRoutes) transceiverByMid(mid string, transceivers func() []*RTPTransceiver) *RTPTransceiver {
+	if t, ok := r.transceivers[mid]; ok && t.Mid() == mid {
+		return t
+	}
+
+	for _, t := range transceivers() {
+		if t.Mid() == mid {
+			return t
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/logging"
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTPRouter(t *testing.T) {
+	const remoteSDP = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+a=group:BUNDLE 0 1
+m=video 9 UDP/TLS/RTP/SAVPF 96 97
+c=IN IP4 0.0.0.0
+a=mid:0
+a=sendonly
+a=rtpmap:96 VP8/90000
+a=rtpmap:97 rtx/90000
+a=fmtp:97 apt=96
+a=ssrc-group:FID 1000 1001
+a=ssrc:1000 msid:stream track
+a=ssrc:1001 msid:stream track
+m=video 9 UDP/TLS/RTP/SAVPF 96
+c=IN IP4 0.0.0.0
+a=mid:1
+a=sendonly
+a=rtpmap:96 VP8/90000
+a=rid:a send
+a=simulcast:send a
+`
+
+	parsed := &sdp.SessionDescription{}
+	assert.NoError(t, parsed.UnmarshalString(remoteSDP))
+
+	first, second := &RTPTransceiver{}, &RTPTransceiver{}
+	assert.NoError(t, first.SetMid("0"))
+	transceivers := []*RTPTransceiver{first, second}
+	getTransceivers := func() []*RTPTransceiver { return transceivers }
+
+	log := logging.NewDefaultLoggerFactory().NewLogger("test")
+	router := &rtpRouter{}
+	routes := router.get(parsed, getTransceivers, log)
+
+	assert.True(t, routes.isDeclared(1000))
+	assert.True(t, routes.isDeclared(1001))
+	assert.False(t, routes.isDeclared(2000))
+
+	// The routes are only rebuilt for a new remote description
+	assert.Same(t, routes, router.get(parsed, getTransceivers, log))
+
+	assert.Same(t, first, routes.transceiverByMid("0", getTransceivers))
+
+	// Transceivers that got their mid later are still found
+	assert.Nil(t, routes.transceiverByMid("1", getTransceivers))
+	assert.NoError(t, second.SetMid("1"))
+	assert.Same(t, second, routes.transceiverByMid("1", getTransceivers))
+}
@@ -7,6 +7,7 @@
 package webrtc
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
 	"sync"
@@ -16,13 +17,39 @@ import (
 	"github.com/pion/randutil"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v4/internal/util"
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
+)
+
+const (
+	// AttributeEncodingMaxBitrate is the interceptor.Attributes key holding the
+	// MaxBitrate (uint64) of the encoding an outbound RTP packet belongs to.
+	// It is only set if a MaxBitrate was configured with RTPSender.SetParameters.
+	AttributeEncodingMaxBitrate = "webrtc.encoding.maxBitrate"
+
+	// AttributeEncodingScaleResolutionDownBy is the interceptor.Attributes key holding the
+	// ScaleResolutionDownBy (float64) of the encoding an outbound RTP packet belongs to.
+	// It is only set if a ScaleResolutionDownBy was configured with RTPSender.SetParameters.
+	AttributeEncodingScaleResolutionDownBy = "webrtc.encoding.scaleResolutionDownBy"
+
+	// AttributeEncodingScalabilityMode is the interceptor.Attributes key holding the
+	// ScalabilityMode (string) of the encoding an outbound RTP packet belongs to.
+	// It is only set if a ScalabilityMode was configured for the encoding.
+	AttributeEncodingScalabilityMode = "webrtc.encoding.scalabilityMode"
+
+	// AttributeSpatialID and AttributeTemporalID are the interceptor.Attributes keys
+	// holding the SVC layer (uint8) of an outbound RTP packet, as given by the
+	// media.Sample it was packetized from. They are only set for scalable encodings.
+	AttributeSpatialID  = "webrtc.svc.spatialID"
+	AttributeTemporalID = "webrtc.svc.temporalID"
 )
 
 type trackEncoding struct {
 	track TrackLocal
 
-	srtpStream *srtpWriterFuture
+	srtpStream  *srtpWriterFuture
+	writeStream *interceptorToTrackLocalWriter
 
 	rtcpInterceptor interceptor.RTCPReader
 	streamInfo      interceptor.StreamInfo
@@ -30,12 +57,49 @@ type trackEncoding struct {
 	context *baseTrackLocalContext
 
 	ssrc, ssrcRTX, ssrcFEC SSRC
+
+	active                bool
+	maxBitrate            uint64
+	scaleResolutionDownBy float64
+	scalabilityMode       string
+
+	// maxBitrateLimit paces the video of the encoding to its MaxBitrate, it
+	// is created once a MaxBitrate is set.
+	maxBitrateLimit *pacer
+
+	remoteInbound remoteInboundStats
+	counters      rtpCounters
+}
+
+func (t *trackEncoding) encodingParameters() RTPEncodingParameters {
+	var rid string
+	if t.track != nil {
+		rid = t.track.RID()
+	}
+
+	active := t.active
+
+	return RTPEncodingParameters{
+		RTPCodingParameters: RTPCodingParameters{
+			RID:  rid,
+			SSRC: t.ssrc,
+			RTX:  RTPRtxParameters{SSRC: t.ssrcRTX},
+			FEC:  RTPFecParameters{SSRC: t.ssrcFEC},
+		},
+		Active:                &active,
+		MaxBitrate:            t.maxBitrate,
+		ScaleResolutionDownBy: t.scaleResolutionDownBy,
+		ScalabilityMode:       t.scalabilityMode,
+	}
 }
 
 // RTPSender allows an application to control how a given Track is encoded and transmitted to a remote peer.
 type RTPSender struct {
 	trackEncodings []*trackEncoding
 
+	// Encodings requested via RTPTransceiverInit.SendEncodings
+	sendEncodings []RTPEncodingParameters
+
 	transport *DTLSTransport
 
 	payloadType PayloadType
@@ -53,6 +117,16 @@ type RTPSender struct {
 
 	rtpTransceiver *RTPTransceiver
 
+	onRemoteQualityHandler      func(RemoteQualityReport)
+	onCongestionFeedbackHandler func(CongestionFeedbackReport)
+	ect1Conn                    *MetadataPacketConn
+	backpressure                senderBackpressure
+	bandwidthLimit              *pacer
+
+	// events runs the OnRemoteQuality and OnCongestionFeedback handlers in
+	// order, off the goroutine reading RTCP
+	events *operations
+
 	mu                     sync.RWMutex
 	sendCalled, stopCalled chan struct{}
 }
@@ -71,12 +145,14 @@ func (api *API) NewRTPSender(track TrackLocal, transport *DTLSTransport) (*RTPSe
 	}
 
 	r := &RTPSender{
-		transport:  transport,
-		api:        api,
-		sendCalled: make(chan struct{}),
-		stopCalled: make(chan struct{}),
-		id:         id,
-		kind:       track.Kind(),
+		transport:      transport,
+		api:            api,
+		sendCalled:     make(chan struct{}),
+		stopCalled:     make(chan struct{}),
+		bandwidthLimit: newPacer(0),
+		events:         newOperations(&atomicBool{}, nil),
+		id:             id,
+		kind:           track.Kind(),
 	}
 
 	r.addEncoding(track)
@@ -97,6 +173,20 @@ func (r *RTPSender) setNegotiated() {
 	r.negotiated = true
 }
 
+// getRTPParameters returns the send parameters of the MediaEngine for kind,
+// with the header extensions negotiated by the transceiver. r.mu must be held.
+func (r *RTPSender) getRTPParameters(kind RTPCodecType) RTPParameters {
+	parameters := r.api.mediaEngine.getRTPParametersByKind(
+		kind,
+		[]RTPTransceiverDirection{RTPTransceiverDirectionSendonly},
+	)
+	if r.rtpTransceiver != nil {
+		parameters.HeaderExtensions = r.rtpTransceiver.filterHeaderExtensions(parameters.HeaderExtensions)
+	}
+
+	return parameters
+}
+
 func (r *RTPSender) setRTPTransceiver(rtpTransceiver *RTPTransceiver) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -120,26 +210,13 @@ func (r *RTPSender) GetParameters() RTPSendParameters {
 
 	var encodings []RTPEncodingParameters
 	for _, trackEncoding := range r.trackEncodings {
-		var rid string
-		if trackEncoding.track != nil {
-			rid = trackEncoding.track.RID()
-		}
-		encodings = append(encodings, RTPEncodingParameters{
-			RTPCodingParameters: RTPCodingParameters{
-				RID:         rid,
-				SSRC:        trackEncoding.ssrc,
-				RTX:         RTPRtxParameters{SSRC: trackEncoding.ssrcRTX},
-				FEC:         RTPFecParameters{SSRC: trackEncoding.ssrcFEC},
-				PayloadType: r.payloadType,
-			},
-		})
+		encoding := trackEncoding.encodingParameters()
+		encoding.PayloadType = r.payloadType
+		encodings = append(encodings, encoding)
 	}
 	sendParameters := RTPSendParameters{
-		RTPParameters: r.api.mediaEngine.getRTPParametersByKind(
-			r.kind,
-			[]RTPTransceiverDirection{RTPTransceiverDirectionSendonly},
-		),
-		Encodings: encodings,
+		RTPParameters: r.getRTPParameters(r.kind),
+		Encodings:     encodings,
 	}
 	if r.rtpTransceiver != nil {
 		sendParameters.Codecs = r.rtpTransceiver.getCodecs()
@@ -150,6 +227,57 @@ func (r *RTPSender) GetParameters() RTPSendParameters {
 	return sendParameters
 }
 
+// SetParameters updates the encodings of the sender without renegotiation.
+// The parameters should be obtained from GetParameters, only the Active,
+// MaxBitrate, ScaleResolutionDownBy and ScalabilityMode fields of the encodings
+// may be changed.
+// Inactive encodings stop sending RTP until they are activated again.
+func (r *RTPSender) SetParameters(parameters RTPSendParameters) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hasStopped() {
+		return &rtcerr.InvalidStateError{Err: errRTPSenderStopped}
+	}
+
+	if len(parameters.Encodings) != len(r.trackEncodings) {
+		return &rtcerr.InvalidModificationError{Err: errRTPSenderEncodingsModified}
+	}
+
+	for idx, encoding := range parameters.Encodings {
+		current := r.trackEncodings[idx].encodingParameters()
+		if encoding.RID != current.RID || encoding.SSRC != current.SSRC ||
+			encoding.RTX != current.RTX || encoding.FEC != current.FEC {
+			return &rtcerr.InvalidModificationError{Err: errRTPSenderEncodingsModified}
+		}
+
+		if encoding.ScaleResolutionDownBy != 0 && encoding.ScaleResolutionDownBy < 1 {
+			return &rtcerr.RangeError{Err: errRTPSenderScaleResolutionDownBy}
+		}
+
+		if encoding.ScalabilityMode != "" {
+			if _, _, err := parseScalabilityMode(encoding.ScalabilityMode); err != nil {
+				return &rtcerr.OperationError{Err: err}
+			}
+		}
+	}
+
+	for idx, encoding := range parameters.Encodings {
+		trackEncoding := r.trackEncodings[idx]
+		trackEncoding.active = encoding.Active == nil || *encoding.Active
+		trackEncoding.maxBitrate = encoding.MaxBitrate
+		trackEncoding.scaleResolutionDownBy = encoding.ScaleResolutionDownBy
+		trackEncoding.scalabilityMode = encoding.ScalabilityMode
+
+		if trackEncoding.writeStream != nil {
+			trackEncoding.writeStream.encoding.Store(trackEncoding.encodingParameters())
+			r.limitMaxBitrate(trackEncoding)
+		}
+	}
+
+	return nil
+}
+
 // AddEncoding adds an encoding to RTPSender. Used by simulcast senders.
 func (r *RTPSender) AddEncoding(track TrackLocal) error { //nolint:cyclop
 	r.mu.Lock()
@@ -193,15 +321,108 @@ func (r *RTPSender) AddEncoding(track TrackLocal) error { //nolint:cyclop
 		}
 	}
 
+	if len(r.sendEncodings) > 1 && r.findSendEncoding(track.RID()) == nil {
+		return fmt.Errorf("%w: %s", errRTPSenderRIDNotInSendEncodings, track.RID())
+	}
+
 	r.addEncoding(track)
 
 	return nil
 }
 
+// setSendEncodings applies the encodings of RTPTransceiverInit. The first track
+// must be the first encoding, the remaining ones are added with AddEncoding.
+func (r *RTPSender) setSendEncodings(encodings []RTPEncodingParameters) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(encodings) > 1 {
+		for i := range encodings {
+			if encodings[i].RID == "" {
+				return errRTPSenderRidNil
+			}
+
+			for j := range encodings[:i] {
+				if encodings[i].RID == encodings[j].RID {
+					return errRTPSenderRIDCollision
+				}
+			}
+		}
+
+		if r.trackEncodings[0].track == nil || r.trackEncodings[0].track.RID() != encodings[0].RID {
+			return errRTPSenderBaseEncodingMismatch
+		}
+	}
+
+	for i := range encodings {
+		if encodings[i].ScalabilityMode == "" {
+			continue
+		}
+
+		if _, _, err := parseScalabilityMode(encodings[i].ScalabilityMode); err != nil {
+			return err
+		}
+	}
+
+	r.sendEncodings = encodings
+	for _, trackEncoding := range r.trackEncodings {
+		r.applySendEncoding(trackEncoding)
+	}
+
+	return nil
+}
+
+func (r *RTPSender) findSendEncoding(rid string) *RTPEncodingParameters {
+	for i := range r.sendEncodings {
+		if r.sendEncodings[i].RID == rid {
+			return &r.sendEncodings[i]
+		}
+	}
+
+	return nil
+}
+
+// applySendEncoding overrides the SSRCs and parameters of trackEncoding with the
+// ones requested for its RID in RTPTransceiverInit.SendEncodings. A single
+// encoding always describes the first track, whatever the RIDs are.
+func (r *RTPSender) applySendEncoding(trackEncoding *trackEncoding) {
+	var encoding *RTPEncodingParameters
+	switch {
+	case len(r.sendEncodings) == 1:
+		if len(r.trackEncodings) == 0 || r.trackEncodings[0] == trackEncoding {
+			encoding = &r.sendEncodings[0]
+		}
+	case trackEncoding.track != nil:
+		encoding = r.findSendEncoding(trackEncoding.track.RID())
+	default:
+		encoding = r.findSendEncoding("")
+	}
+	if encoding == nil {
+		return
+	}
+
+	if encoding.SSRC != 0 {
+		trackEncoding.ssrc = encoding.SSRC
+	}
+	if encoding.RTX.SSRC != 0 && trackEncoding.ssrcRTX != 0 {
+		trackEncoding.ssrcRTX = encoding.RTX.SSRC
+	}
+	if encoding.FEC.SSRC != 0 && trackEncoding.ssrcFEC != 0 {
+		trackEncoding.ssrcFEC = encoding.FEC.SSRC
+	}
+	if encoding.Active != nil {
+		trackEncoding.active = *encoding.Active
+	}
+	trackEncoding.maxBitrate = encoding.MaxBitrate
+	trackEncoding.scaleResolutionDownBy = encoding.ScaleResolutionDownBy
+	trackEncoding.scalabilityMode = encoding.ScalabilityMode
+}
+
 func (r *RTPSender) addEncoding(track TrackLocal) {
 	trackEncoding := &trackEncoding{
-		track: track,
-		ssrc:  SSRC(util.RandUint32()),
+		track:  track,
+		ssrc:   SSRC(util.RandUint32()),
+		active: true,
 	}
 
 	if r.api.mediaEngine.isRTXEnabled(r.kind, []RTPTransceiverDirection{RTPTransceiverDirectionSendonly}) {
@@ -212,6 +433,7 @@ func (r *RTPSender) addEncoding(track TrackLocal) {
 		trackEncoding.ssrcFEC = SSRC(util.RandUint32())
 	}
 
+	r.applySendEncoding(trackEncoding)
 	r.trackEncodings = append(r.trackEncodings, trackEncoding)
 }
 
@@ -264,10 +486,7 @@ func (r *RTPSender) ReplaceTrack(track TrackLocal) error { //nolint:cyclop
 		return nil
 	}
 
-	params := r.api.mediaEngine.getRTPParametersByKind(
-		track.Kind(),
-		[]RTPTransceiverDirection{RTPTransceiverDirectionSendonly},
-	)
+	params := r.getRTPParameters(track.Kind())
 
 	// If we reach this point in the routine, there is only 1 track encoding
 	codec, err := track.Bind(&baseTrackLocalContext{
@@ -313,17 +532,30 @@ func (r *RTPSender) Send(parameters RTPSendParameters) error {
 	for idx := range r.trackEncodings {
 		trackEncoding := r.trackEncodings[idx]
 		srtpStream := &srtpWriterFuture{ssrc: parameters.Encodings[idx].SSRC, rtpSender: r}
-		writeStream := &interceptorToTrackLocalWriter{}
-		rtpParameters := r.api.mediaEngine.getRTPParametersByKind(
-			trackEncoding.track.Kind(),
-			[]RTPTransceiverDirection{RTPTransceiverDirectionSendonly},
-		)
+		writeStream := &interceptorToTrackLocalWriter{
+			metrics: r.api.settingEngine.performanceMetrics,
+			written: r.checkBufferedAmount,
+		}
+		if r.api.settingEngine.enforceRemoteBandwidth {
+			r.pace(writeStream, r.bandwidthLimit)
+		}
+		if r.transport != nil && r.transport.iceTransport != nil {
+			writeStream.queue = &r.transport.iceTransport.queue
+			if pacer := r.transport.iceTransport.pacer; pacer != nil {
+				writeStream.pacer = pacer
+				r.pace(writeStream, pacer)
+			}
+		}
+		rtpParameters := r.getRTPParameters(trackEncoding.track.Kind())
 
 		trackEncoding.srtpStream = srtpStream
+		trackEncoding.writeStream = writeStream
 		trackEncoding.ssrc = parameters.Encodings[idx].SSRC
 		trackEncoding.ssrcRTX = parameters.Encodings[idx].RTX.SSRC
 		trackEncoding.ssrcFEC = parameters.Encodings[idx].FEC.SSRC
-		trackEncoding.rtcpInterceptor = r.api.interceptor.BindRTCPReader(
+		writeStream.encoding.Store(trackEncoding.encodingParameters())
+		r.limitMaxBitrate(trackEncoding)
+		rtcpInterceptor := r.api.interceptor.BindRTCPReader(
 			interceptor.RTCPReaderFunc(
 				func(in []byte, a interceptor.Attributes) (n int, attributes interceptor.Attributes, err error) {
 					n, err = trackEncoding.srtpStream.Read(in)
@@ -332,6 +564,25 @@ func (r *RTPSender) Send(parameters RTPSendParameters) error {
 				},
 			),
 		)
+		trackEncoding.rtcpInterceptor = interceptor.RTCPReaderFunc(
+			func(in []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+				n, attributes, err := rtcpInterceptor.Read(in, a)
+				if err != nil {
+					return n, attributes, err
+				}
+
+				if attributes == nil {
+					attributes = make(interceptor.Attributes)
+				}
+				// The packets are cached in the attributes, interceptors and
+				// ReadRTCP don't unmarshal them again
+				if pkts, unmarshalErr := attributes.GetRTCPPackets(in[:n]); unmarshalErr == nil {
+					r.handleRemoteQuality(trackEncoding, pkts)
+				}
+
+				return n, attributes, err
+			},
+		)
 		trackEncoding.context = &baseTrackLocalContext{
 			id:              r.id,
 			params:          rtpParameters,
@@ -348,22 +599,52 @@ func (r *RTPSender) Send(parameters RTPSendParameters) error {
 		}
 		trackEncoding.context.params.Codecs = []RTPCodecParameters{codec}
 
+		// NACKed packets are retransmitted on the RTX SSRC, unless no RTX codec
+		// was negotiated for the codec in which case they are resent as is.
+		ssrcRTX := parameters.Encodings[idx].RTX.SSRC
+		payloadTypeRTX := findRTXPayloadType(codec.PayloadType, rtpParameters.Codecs)
+		if payloadTypeRTX == 0 {
+			ssrcRTX = 0
+		}
+
 		trackEncoding.streamInfo = *createStreamInfo(
 			r.id,
 			parameters.Encodings[idx].SSRC,
-			parameters.Encodings[idx].RTX.SSRC,
+			ssrcRTX,
 			parameters.Encodings[idx].FEC.SSRC,
 			codec.PayloadType,
-			findRTXPayloadType(codec.PayloadType, rtpParameters.Codecs),
+			payloadTypeRTX,
 			0,
 			codec.RTPCodecCapability,
 			parameters.HeaderExtensions,
 		)
+		setULPFECStreamInfo(&trackEncoding.streamInfo, rtpParameters.Codecs)
+
+		feedback, transportCCID := r.bindTransportFeedback(trackEncoding, parameters.HeaderExtensions)
 
 		rtpInterceptor := r.api.interceptor.BindLocalStream(
 			&trackEncoding.streamInfo,
 			interceptor.RTPWriterFunc(func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
-				return srtpStream.WriteRTP(header, payload)
+				n, err := srtpStream.WriteRTP(header, payload)
+				if err != nil {
+					return n, err
+				}
+
+				now := time.Now()
+				if feedback != nil {
+					if ext := header.GetExtension(transportCCID); len(ext) == 2 {
+						feedback.sentPacket(trackEncoding.ssrc, binary.BigEndian.Uint16(ext), now)
+					}
+				}
+				if SSRC(header.SSRC) == trackEncoding.ssrc {
+					padding := 0
+					if header.Padding && len(payload) > 0 && int(payload[len(payload)-1]) <= len(payload) {
+						padding = int(payload[len(payload)-1])
+					}
+					trackEncoding.counters.addSizes(header.MarshalSize()+padding, len(payload)-padding, now)
+				}
+
+				return n, err
 			}),
 		)
 
@@ -375,6 +656,34 @@ func (r *RTPSender) Send(parameters RTPSendParameters) error {
 	return nil
 }
 
+// pace queues the video written to writeStream until pacers let it through,
+// audio isn't queued.
+func (r *RTPSender) pace(writeStream *interceptorToTrackLocalWriter, pacers ...*pacer) {
+	if r.kind == RTPCodecTypeAudio {
+		return
+	}
+
+	if paced := writeStream.pacedQueue(); paced != nil {
+		paced.addPacers(pacers...)
+
+		return
+	}
+
+	writeStream.paced.Store(newPacedQueue(writeStream.write, r.api.latencyMode.maxPacingDelay(), pacers...))
+}
+
+// limitMaxBitrate paces the video of the encoding to its MaxBitrate.
+func (r *RTPSender) limitMaxBitrate(trackEncoding *trackEncoding) {
+	switch {
+	case r.kind == RTPCodecTypeAudio:
+	case trackEncoding.maxBitrateLimit != nil:
+		trackEncoding.maxBitrateLimit.setBitrate(int(trackEncoding.maxBitrate)) //nolint:gosec // G115
+	case trackEncoding.maxBitrate != 0:
+		trackEncoding.maxBitrateLimit = newPacer(int(trackEncoding.maxBitrate)) //nolint:gosec // G115
+		r.pace(trackEncoding.writeStream, trackEncoding.maxBitrateLimit)
+	}
+}
+
 // Stop irreversibly stops the RTPSender.
 func (r *RTPSender) Stop() error {
 	r.mu.Lock()
@@ -386,7 +695,19 @@ func (r *RTPSender) Stop() error {
 	}
 
 	close(r.stopCalled)
+	for _, trackEncoding := range r.trackEncodings {
+		if trackEncoding.writeStream != nil {
+			trackEncoding.writeStream.pacedQueue().close()
+		}
+		if trackEncoding.maxBitrateLimit != nil {
+			trackEncoding.maxBitrateLimit.close()
+		}
+	}
 	r.mu.Unlock()
+	r.stopBackpressure()
+	if r.bandwidthLimit != nil {
+		r.bandwidthLimit.close()
+	}
 
 	if !r.hasSent() {
 		return nil
@@ -396,6 +717,21 @@ func (r *RTPSender) Stop() error {
 		return err
 	}
 
+	r.mu.Lock()
+	if r.transport != nil {
+		for _, trackEncoding := range r.trackEncodings {
+			r.transport.transportFeedback.onFeedback(trackEncoding.ssrc, nil)
+			r.transport.keyUsage.remove(trackEncoding.ssrc, trackEncoding.ssrcRTX, trackEncoding.ssrcFEC)
+		}
+	}
+	if r.ect1Conn != nil {
+		for _, trackEncoding := range r.trackEncodings {
+			r.ect1Conn.unmarkECT1(trackEncoding.ssrc, trackEncoding.ssrcRTX, trackEncoding.ssrcFEC)
+		}
+		r.ect1Conn = nil
+	}
+	r.mu.Unlock()
+
 	errs := []error{}
 	for _, trackEncoding := range r.trackEncodings {
 		r.api.interceptor.UnbindLocalStream(&trackEncoding.streamInfo)
@@ -407,6 +743,312 @@ func (r *RTPSender) Stop() error {
 	return util.FlattenErrs(errs)
 }
 
+// OnRemoteQuality sets an event handler which is invoked for every RTCP
+// Receiver Report block the remote peer sends about one of the encodings, and
+// for every transport-wide congestion control feedback report covering
+// packets of the encodings if the transport-wide sequence number header
+// extension was negotiated. It allows adapting what is sent without running a
+// congestion controller.
+// Like interceptors, it is only invoked while RTCP is read from the RTPSender.
+// The handler runs on its own goroutine in the order the reports are read, it
+// doesn't hold up reading RTCP.
+func (r *RTPSender) OnRemoteQuality(f func(RemoteQualityReport)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onRemoteQualityHandler = f
+}
+
+func (r *RTPSender) handleRemoteQuality(trackEncoding *trackEncoding, pkts []rtcp.Packet) {
+	r.mu.RLock()
+	handler := r.onRemoteQualityHandler
+	feedbackHandler := r.onCongestionFeedbackHandler
+	ssrc := trackEncoding.ssrc
+	var rid string
+	if trackEncoding.track != nil {
+		rid = trackEncoding.track.RID()
+	}
+	var clockRate uint32
+	if trackEncoding.context != nil && len(trackEncoding.context.params.Codecs) != 0 {
+		clockRate = trackEncoding.context.params.Codecs[0].ClockRate
+	}
+	r.mu.RUnlock()
+
+	now := time.Now()
+	for _, pkt := range pkts {
+		var reports []rtcp.ReceptionReport
+		switch pkt := pkt.(type) {
+		case *rtcp.ReceiverReport:
+			reports = pkt.Reports
+		case *rtcp.SenderReport:
+			reports = pkt.Reports
+		case *rtcp.TransportLayerCC:
+			// The feedback is about the packets of every encoding sent on
+			// the transport, their RTPSenders are notified
+			if r.transport != nil {
+				r.transport.transportFeedback.handle(pkt)
+			}
+
+			continue
+		case *rtcp.CCFeedbackReport:
+			if feedbackHandler != nil {
+				for _, block := range pkt.ReportBlocks {
+					if SSRC(block.MediaSSRC) == ssrc {
+						report := newCongestionFeedbackReport(block, rid)
+						r.events.Enqueue(func() { feedbackHandler(report) })
+					}
+				}
+			}
+
+			continue
+		default:
+			trackEncoding.remoteInbound.addFeedback(pkt, ssrc)
+
+			continue
+		}
+
+		for _, report := range reports {
+			if SSRC(report.SSRC) != ssrc {
+				continue
+			}
+
+			quality := newRemoteQualityReport(report, rid, clockRate, now)
+			trackEncoding.remoteInbound.addReport(quality)
+			if handler != nil {
+				r.events.Enqueue(func() { handler(quality) })
+			}
+		}
+	}
+}
+
+// bindTransportFeedback makes the transport-wide congestion control feedback
+// about the packets of trackEncoding invoke the OnRemoteQuality handler. It
+// returns nil if the transport-wide sequence number extension wasn't negotiated.
+func (r *RTPSender) bindTransportFeedback(
+	trackEncoding *trackEncoding,
+	headerExtensions []RTPHeaderExtensionParameter,
+) (*transportFeedback, uint8) {
+	if r.transport == nil {
+		return nil, 0
+	}
+
+	for _, ext := range headerExtensions {
+		if ext.URI != sdp.TransportCCURI {
+			continue
+		}
+
+		r.transport.transportFeedback.onFeedback(trackEncoding.ssrc, func(fractionLost float64, queuingDelay time.Duration) {
+			r.mu.RLock()
+			handler := r.onRemoteQualityHandler
+			var rid string
+			if trackEncoding.track != nil {
+				rid = trackEncoding.track.RID()
+			}
+			r.mu.RUnlock()
+
+			if handler != nil {
+				quality := RemoteQualityReport{
+					SSRC:         trackEncoding.ssrc,
+					RID:          rid,
+					FractionLost: fractionLost,
+					QueuingDelay: queuingDelay,
+					TransportCC:  true,
+				}
+				r.events.Enqueue(func() { handler(quality) })
+			}
+		})
+
+		return &r.transport.transportFeedback, uint8(ext.ID) //nolint:gosec // G115, IDs are 1-255
+	}
+
+	return nil, 0
+}
+
+func newRemoteQualityReport(
+	report rtcp.ReceptionReport,
+	rid string,
+	clockRate uint32,
+	now time.Time,
+) RemoteQualityReport {
+	quality := RemoteQualityReport{
+		SSRC:         SSRC(report.SSRC),
+		RID:          rid,
+		FractionLost: float64(report.FractionLost) / 256,
+		TotalLost:    report.TotalLost,
+	}
+
+	if clockRate != 0 {
+		quality.Jitter = time.Duration(float64(report.Jitter) / float64(clockRate) * float64(time.Second))
+	}
+
+	// RFC 3550 6.4.1, LSR and DLSR are in units of 1/65536 seconds
+	if report.LastSenderReport != 0 {
+		rtt := middleNTP(now) - report.LastSenderReport - report.Delay
+		if int32(rtt) > 0 { //nolint:gosec // G115
+			quality.RoundTripTime = time.Duration(float64(rtt) / 65536 * float64(time.Second))
+		}
+	}
+
+	return quality
+}
+
+// OnCongestionFeedback sets an event handler which is invoked for every
+// RFC 8888 congestion control feedback report block the remote peer sends
+// about one of the encodings, see FeatureFlagL4S. Like interceptors, it is
+// only invoked while RTCP is read from the RTPSender. The handler runs on its
+// own goroutine in the order the reports are read, it doesn't hold up reading
+// RTCP.
+func (r *RTPSender) OnCongestionFeedback(f func(CongestionFeedbackReport)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onCongestionFeedbackHandler = f
+}
+
+func newCongestionFeedbackReport(block rtcp.CCFeedbackReportBlock, rid string) CongestionFeedbackReport {
+	report := CongestionFeedbackReport{
+		SSRC:    SSRC(block.MediaSSRC),
+		RID:     rid,
+		Packets: make([]CongestionFeedbackPacket, 0, len(block.MetricBlocks)),
+	}
+
+	for i, metric := range block.MetricBlocks {
+		packet := CongestionFeedbackPacket{
+			SequenceNumber: block.BeginSequence + uint16(i), //nolint:gosec // G115
+			Received:       metric.Received,
+		}
+
+		if metric.Received {
+			report.Received++
+			packet.ECN = ECN(metric.ECN)
+			if packet.ECN == ECNCE {
+				report.CongestionExperienced++
+			}
+
+			// RFC 8888 3.1, the offset is in units of 1/1024 seconds and
+			// 0x1FFF means it is too large or unavailable
+			if metric.ArrivalTimeOffset >= 0x1FFF {
+				packet.ArrivalTimeOffset = -1
+			} else {
+				packet.ArrivalTimeOffset = time.Duration(metric.ArrivalTimeOffset) * time.Second / 1024
+			}
+		} else {
+			report.Lost++
+		}
+
+		report.Packets = append(report.Packets, packet)
+	}
+
+	return report
+}
+
+// markECT1 marks the packets of every encoding with ECNECT1 once written to conn.
+func (r *RTPSender) markECT1(conn *MetadataPacketConn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ect1Conn != nil {
+		return
+	}
+	r.ect1Conn = conn
+	for _, trackEncoding := range r.trackEncodings {
+		conn.markECT1(trackEncoding.ssrc, trackEncoding.ssrcRTX, trackEncoding.ssrcFEC)
+	}
+}
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and the Unix epoch (1970).
+const ntpEpochOffset = 2208988800
+
+// middleNTP returns the middle 32 bits of the NTP timestamp of t.
+func middleNTP(t time.Time) uint32 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)                   //nolint:gosec // G115
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second) //nolint:gosec // G115
+
+	return uint32((seconds<<32 | fraction) >> 16) //nolint:gosec // G115
+}
+
+// ntpTime converts a 64 bit NTP timestamp to a time.Time.
+func ntpTime(ntp uint64) time.Time {
+	seconds := int64(ntp>>32) - ntpEpochOffset                           //nolint:gosec // G115
+	nanoseconds := int64((ntp & 0xFFFFFFFF) * uint64(time.Second) >> 32) //nolint:gosec // G115
+
+	return time.Unix(seconds, nanoseconds)
+}
+
+func (r *RTPSender) collectStats(collector *statsReportCollector) {
+	if !r.hasSent() {
+		return
+	}
+
+	r.mu.RLock()
+	transceiver := r.rtpTransceiver
+	r.mu.RUnlock()
+
+	mid := ""
+	if transceiver != nil {
+		mid = transceiver.Mid()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, trackEncoding := range r.trackEncodings {
+		if trackEncoding.ssrc == 0 {
+			continue
+		}
+
+		remoteInbound := RemoteInboundRTPStreamStats{
+			Timestamp:   statsTimestampNow(),
+			Type:        StatsTypeRemoteInboundRTP,
+			ID:          remoteInboundRTPStreamStatsID(trackEncoding.ssrc),
+			SSRC:        trackEncoding.ssrc,
+			Kind:        r.kind.String(),
+			TransportID: "iceTransport",
+			LocalID:     outboundRTPStreamStatsID(trackEncoding.ssrc),
+		}
+		hasRemoteInbound := trackEncoding.remoteInbound.collectStats(&remoteInbound)
+
+		packets, headerBytes, payloadBytes, last := trackEncoding.counters.snapshot()
+		outbound := OutboundRTPStreamStats{
+			Mid:             mid,
+			Timestamp:       statsTimestampNow(),
+			Type:            StatsTypeOutboundRTP,
+			ID:              outboundRTPStreamStatsID(trackEncoding.ssrc),
+			SSRC:            trackEncoding.ssrc,
+			Kind:            r.kind.String(),
+			TransportID:     "iceTransport",
+			PacketsSent:     packets,
+			HeaderBytesSent: headerBytes,
+			BytesSent:       payloadBytes,
+			FIRCount:        remoteInbound.FIRCount,
+			PLICount:        remoteInbound.PLICount,
+			NACKCount:       remoteInbound.NACKCount,
+			Active:          trackEncoding.active,
+		}
+		if trackEncoding.track != nil {
+			outbound.Rid = trackEncoding.track.RID()
+		}
+		if !last.IsZero() {
+			outbound.LastPacketSentTimestamp = statsTimestampFrom(last)
+		}
+		if hasRemoteInbound {
+			outbound.RemoteID = remoteInbound.ID
+		}
+
+		collector.Collecting()
+		collector.Collect(outbound.ID, outbound)
+
+		if hasRemoteInbound {
+			collector.Collecting()
+			collector.Collect(remoteInbound.ID, remoteInbound)
+		}
+	}
+}
+
+func outboundRTPStreamStatsID(ssrc SSRC) string {
+	return fmt.Sprintf("OutboundRTPStream-%d", ssrc)
+}
+
 // Read reads incoming RTCP for this RTPSender.
 func (r *RTPSender) Read(b []byte) (n int, a interceptor.Attributes, err error) {
 	select {
@@ -425,7 +1067,7 @@ func (r *RTPSender) ReadRTCP() ([]rtcp.Packet, interceptor.Attributes, error) {
 		return nil, nil, err
 	}
 
-	pkts, err := rtcp.Unmarshal(b[:i])
+	pkts, err := attributes.GetRTCPPackets(b[:i])
 	if err != nil {
 		return nil, nil, err
 	}
@@ -457,7 +1099,7 @@ func (r *RTPSender) ReadSimulcastRTCP(rid string) ([]rtcp.Packet, interceptor.At
 		return nil, nil, err
 	}
 
-	pkts, err := rtcp.Unmarshal(b[:i])
+	pkts, err := attributes.GetRTCPPackets(b[:i])
 
 	return pkts, attributes, err
 }
@@ -15,6 +15,9 @@ import (
 	"time"
 
 	"github.com/pion/interceptor"
+	mock_interceptor "github.com/pion/interceptor/pkg/mock"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
 	"github.com/pion/transport/v3/test"
 	"github.com/pion/webrtc/v4/pkg/media"
 	"github.com/stretchr/testify/assert"
@@ -541,3 +544,287 @@ func (s *TrackLocalCheckRTCPReaderOnBind) Bind(ctx TrackLocalContext) (RTPCodecP
 
 	return p, err
 }
+
+func Test_RTPSender_SetParameters(t *testing.T) {
+	var written uint64
+	var lastAttributes atomic.Value
+
+	ir := &interceptor.Registry{}
+	ir.Add(&mock_interceptor.Factory{
+		NewInterceptorFn: func(_ string) (interceptor.Interceptor, error) {
+			return &mock_interceptor.Interceptor{
+				BindLocalStreamFn: func(_ *interceptor.StreamInfo, _ interceptor.RTPWriter) interceptor.RTPWriter {
+					return interceptor.RTPWriterFunc(
+						func(_ *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+							atomic.AddUint64(&written, 1)
+							lastAttributes.Store(attributes)
+
+							return len(payload), nil
+						},
+					)
+				},
+			}, nil
+		},
+	})
+
+	peerConnection, err := NewAPI(WithInterceptorRegistry(ir)).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	rtpSender, err := peerConnection.AddTrack(track)
+	assert.NoError(t, err)
+
+	parameters := rtpSender.GetParameters()
+	assert.True(t, *parameters.Encodings[0].Active)
+	assert.NoError(t, rtpSender.Send(parameters))
+
+	assert.NoError(t, track.WriteRTP(&rtp.Packet{Payload: []byte{0x00}}))
+	assert.Equal(t, uint64(1), atomic.LoadUint64(&written))
+	// Without a MaxBitrate the encoding isn't paced
+	assert.Nil(t, rtpSender.trackEncodings[0].maxBitrateLimit)
+
+	t.Run("Modify immutable", func(t *testing.T) {
+		modified := rtpSender.GetParameters()
+		modified.Encodings[0].SSRC++
+		assert.ErrorIs(t, rtpSender.SetParameters(modified), errRTPSenderEncodingsModified)
+
+		modified = rtpSender.GetParameters()
+		modified.Encodings = append(modified.Encodings, modified.Encodings[0])
+		assert.ErrorIs(t, rtpSender.SetParameters(modified), errRTPSenderEncodingsModified)
+
+		modified = rtpSender.GetParameters()
+		modified.Encodings[0].ScaleResolutionDownBy = 0.5
+		assert.ErrorIs(t, rtpSender.SetParameters(modified), errRTPSenderScaleResolutionDownBy)
+	})
+
+	t.Run("MaxBitrate", func(t *testing.T) {
+		modified := rtpSender.GetParameters()
+		modified.Encodings[0].MaxBitrate = 500_000
+		modified.Encodings[0].ScaleResolutionDownBy = 2
+		assert.NoError(t, rtpSender.SetParameters(modified))
+
+		assert.Equal(t, uint64(500_000), rtpSender.GetParameters().Encodings[0].MaxBitrate)
+		assert.Equal(t, 2.0, rtpSender.GetParameters().Encodings[0].ScaleResolutionDownBy)
+		assert.Equal(t, 500_000/8.0, rtpSender.trackEncodings[0].maxBitrateLimit.rate())
+
+		// The packets are paced to MaxBitrate, so they are written asynchronously
+		writtenBefore := atomic.LoadUint64(&written)
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{Payload: []byte{0x00}}))
+		assert.Eventually(t, func() bool {
+			return atomic.LoadUint64(&written) == writtenBefore+1
+		}, time.Second, time.Millisecond)
+		attributes, ok := lastAttributes.Load().(interceptor.Attributes)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(500_000), attributes.Get(AttributeEncodingMaxBitrate))
+		assert.Equal(t, 2.0, attributes.Get(AttributeEncodingScaleResolutionDownBy))
+
+		modified.Encodings[0].MaxBitrate = 0
+		assert.NoError(t, rtpSender.SetParameters(modified))
+	})
+
+	t.Run("ScalabilityMode", func(t *testing.T) {
+		modified := rtpSender.GetParameters()
+		modified.Encodings[0].ScalabilityMode = "L4T1"
+		assert.ErrorIs(t, rtpSender.SetParameters(modified), errInvalidScalabilityMode)
+
+		modified.Encodings[0].ScalabilityMode = "L2T3"
+		assert.NoError(t, rtpSender.SetParameters(modified))
+		assert.Equal(t, "L2T3", rtpSender.GetParameters().Encodings[0].ScalabilityMode)
+
+		sample := &media.Sample{SpatialID: 1, TemporalID: 2}
+		assert.NoError(t, track.writeSampleRTP(&rtp.Packet{Payload: []byte{0x00}}, sample))
+		attributes, ok := lastAttributes.Load().(interceptor.Attributes)
+		assert.True(t, ok)
+		assert.Equal(t, "L2T3", attributes.Get(AttributeEncodingScalabilityMode))
+		assert.Equal(t, uint8(1), attributes.Get(AttributeSpatialID))
+		assert.Equal(t, uint8(2), attributes.Get(AttributeTemporalID))
+	})
+
+	t.Run("Pause", func(t *testing.T) {
+		modified := rtpSender.GetParameters()
+		active := false
+		modified.Encodings[0].Active = &active
+		assert.NoError(t, rtpSender.SetParameters(modified))
+
+		writtenBefore := atomic.LoadUint64(&written)
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{Payload: []byte{0x00}}))
+		assert.Equal(t, writtenBefore, atomic.LoadUint64(&written))
+
+		active = true
+		assert.NoError(t, rtpSender.SetParameters(modified))
+
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{Payload: []byte{0x00}}))
+		assert.Equal(t, writtenBefore+1, atomic.LoadUint64(&written))
+	})
+
+	assert.NoError(t, peerConnection.Close())
+}
+
+func Test_RTPSender_RemoteQualityReport(t *testing.T) {
+	now := time.Now()
+	report := newRemoteQualityReport(rtcp.ReceptionReport{
+		SSRC:             1234,
+		FractionLost:     64,
+		TotalLost:        10,
+		Jitter:           900,
+		LastSenderReport: middleNTP(now.Add(-100 * time.Millisecond)),
+		Delay:            65536 / 20, // 50ms
+	}, "f", 90000, now)
+
+	assert.Equal(t, SSRC(1234), report.SSRC)
+	assert.Equal(t, "f", report.RID)
+	assert.Equal(t, 0.25, report.FractionLost)
+	assert.Equal(t, uint32(10), report.TotalLost)
+	assert.Equal(t, 10*time.Millisecond, report.Jitter)
+	assert.InDelta(t, float64(50*time.Millisecond), float64(report.RoundTripTime), float64(time.Millisecond))
+
+	// No Sender Report received by the remote yet
+	report = newRemoteQualityReport(rtcp.ReceptionReport{SSRC: 1234}, "", 0, now)
+	assert.Zero(t, report.RoundTripTime)
+	assert.Zero(t, report.Jitter)
+}
+
+func Test_RTPSender_CongestionFeedbackReport(t *testing.T) {
+	report := newCongestionFeedbackReport(rtcp.CCFeedbackReportBlock{
+		MediaSSRC:     1234,
+		BeginSequence: 65535,
+		MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+			{Received: true, ECN: rtcp.ECNECT1, ArrivalTimeOffset: 512},
+			{Received: false},
+			{Received: true, ECN: rtcp.ECNCE, ArrivalTimeOffset: 0x1FFF},
+		},
+	}, "f")
+
+	assert.Equal(t, SSRC(1234), report.SSRC)
+	assert.Equal(t, "f", report.RID)
+	assert.Equal(t, 2, report.Received)
+	assert.Equal(t, 1, report.Lost)
+	assert.Equal(t, 1, report.CongestionExperienced)
+	assert.Equal(t, []CongestionFeedbackPacket{
+		{SequenceNumber: 65535, Received: true, ECN: ECNECT1, ArrivalTimeOffset: 500 * time.Millisecond},
+		{SequenceNumber: 0},
+		{SequenceNumber: 1, Received: true, ECN: ECNCE, ArrivalTimeOffset: -1},
+	}, report.Packets)
+}
+
+func Test_RTPSender_OnRemoteQuality(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	sender, receiver, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	rtpSender, err := sender.AddTrack(track)
+	assert.NoError(t, err)
+
+	ssrc := rtpSender.GetParameters().Encodings[0].SSRC
+	reportReceived, reportReceivedCancel := context.WithCancel(context.Background())
+	rtpSender.OnRemoteQuality(func(quality RemoteQualityReport) {
+		assert.Equal(t, ssrc, quality.SSRC)
+		assert.Equal(t, 0.5, quality.FractionLost)
+		reportReceivedCancel()
+	})
+
+	go func() {
+		for {
+			if _, _, readErr := rtpSender.ReadRTCP(); readErr != nil {
+				return
+			}
+		}
+	}()
+
+	assert.NoError(t, signalPair(sender, receiver))
+
+	func() {
+		for range time.Tick(time.Millisecond * 20) {
+			select {
+			case <-reportReceived.Done():
+				return
+			default:
+				// Fails until DTLS is connected
+				_ = receiver.WriteRTCP([]rtcp.Packet{&rtcp.ReceiverReport{
+					Reports: []rtcp.ReceptionReport{{SSRC: uint32(ssrc), FractionLost: 128}},
+				}})
+			}
+		}
+	}()
+
+	closePairNow(t, sender, receiver)
+}
+
+func Test_RTPSender_OnRemoteQuality_DoesNotBlockRead(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	sender, receiver, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	rtpSender, err := sender.AddTrack(track)
+	assert.NoError(t, err)
+
+	handlerCalled := make(chan struct{})
+	release := make(chan struct{})
+	var calls int32
+	rtpSender.OnRemoteQuality(func(RemoteQualityReport) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(handlerCalled)
+		}
+		<-release
+	})
+
+	var reads int32
+	go func() {
+		for {
+			if _, _, readErr := rtpSender.ReadRTCP(); readErr != nil {
+				return
+			}
+			atomic.AddInt32(&reads, 1)
+		}
+	}()
+
+	assert.NoError(t, signalPair(sender, receiver))
+
+	ssrc := rtpSender.GetParameters().Encodings[0].SSRC
+	writeReport := func() {
+		_ = receiver.WriteRTCP([]rtcp.Packet{&rtcp.ReceiverReport{
+			Reports: []rtcp.ReceptionReport{{SSRC: uint32(ssrc)}},
+		}})
+	}
+
+	func() {
+		for range time.Tick(time.Millisecond * 20) {
+			select {
+			case <-handlerCalled:
+				return
+			default:
+				// Fails until DTLS is connected
+				writeReport()
+			}
+		}
+	}()
+
+	// RTCP is still read while the handler is blocked
+	readsBefore := atomic.LoadInt32(&reads)
+	assert.Eventually(t, func() bool {
+		writeReport()
+
+		return atomic.LoadInt32(&reads) > readsBefore
+	}, time.Second*5, time.Millisecond*20)
+
+	close(release)
+	closePairNow(t, sender, receiver)
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+)
+
+// backpressurePollInterval is how often the buffered amount of a RTPSender
+// is checked while it is above its low threshold.
+const backpressurePollInterval = 5 * time.Millisecond
+
+// senderBackpressure invokes the OnBufferedAmountLow handler of a RTPSender.
+type senderBackpressure struct {
+	mu        sync.Mutex
+	threshold uint64
+	handler   func()
+	above     bool
+	timer     *time.Timer
+	stopped   bool
+}
+
+// BufferedAmount returns the number of bytes that wait to be sent: the
+// packets written to the RTPSender held back by its pacers, see
+// SettingEngine.SetPacingBitrate, and the packets of all the RTPSenders
+// sharing its transport blocked on the socket. Applications producing media
+// faster than it is sent can skip frames while it is high instead of adding
+// latency.
+//
+// This is a Pion specific extension and is not part of the W3C API.
+func (r *RTPSender) BufferedAmount() uint64 {
+	var bufferedAmount uint64
+	if r.transport != nil && r.transport.iceTransport != nil {
+		bufferedAmount = r.transport.iceTransport.queue.bufferedAmount()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, trackEncoding := range r.trackEncodings {
+		if trackEncoding.writeStream != nil {
+			bufferedAmount += trackEncoding.writeStream.pacedQueue().bufferedAmount()
+		}
+	}
+
+	return bufferedAmount
+}
+
+// BufferedAmountLowThreshold returns the threshold of BufferedAmount at
+// which the OnBufferedAmountLow handler is invoked.
+func (r *RTPSender) BufferedAmountLowThreshold() uint64 {
+	r.backpressure.mu.Lock()
+	defer r.backpressure.mu.Unlock()
+
+	return r.backpressure.threshold
+}
+
+// SetBufferedAmountLowThreshold sets the threshold of BufferedAmount at
+// which the OnBufferedAmountLow handler is invoked, it defaults to 0.
+func (r *RTPSender) SetBufferedAmountLowThreshold(th uint64) {
+	r.backpressure.mu.Lock()
+	defer r.backpressure.mu.Unlock()
+
+	r.backpressure.threshold = th
+}
+
+// OnBufferedAmountLow sets an event handler which is invoked when the
+// BufferedAmount, having been above the BufferedAmountLowThreshold after a
+// write, becomes lower than or equal to it. The handler is invoked in its
+// own goroutine, so it may write to the track.
+//
+// This is a Pion specific extension and is not part of the W3C API.
+func (r *RTPSender) OnBufferedAmountLow(f func()) {
+	r.backpressure.mu.Lock()
+	defer r.backpressure.mu.Unlock()
+
+	r.backpressure.handler = f
+}
+
+// checkBufferedAmount is called after every write. While the BufferedAmount
+// is above the threshold it is checked again as the pacer and the socket
+// drain, and the handler is invoked once it falls to the threshold.
+func (r *RTPSender) checkBufferedAmount() {
+	backpressure := &r.backpressure
+	backpressure.mu.Lock()
+	defer backpressure.mu.Unlock()
+
+	if backpressure.handler == nil || backpressure.stopped {
+		return
+	}
+
+	if r.BufferedAmount() > backpressure.threshold {
+		backpressure.above = true
+		if backpressure.timer == nil {
+			backpressure.timer = time.AfterFunc(backpressurePollInterval, r.checkBufferedAmount)
+		} else {
+			backpressure.timer.Reset(backpressurePollInterval)
+		}
+
+		return
+	}
+
+	if backpressure.above {
+		backpressure.above = false
+		go backpressure.handler()
+	}
+}
+
+// stopBackpressure stops checking the BufferedAmount once the RTPSender is stopped.
+func (r *RTPSender) stopBackpressure() {
+	r.backpressure.mu.Lock()
+	defer r.backpressure.mu.Unlock()
+
+	r.backpressure.stopped = true
+	if r.backpressure.timer != nil {
+		r.backpressure.timer.Stop()
+	}
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTPSender_OnBufferedAmountLow(t *testing.T) {
+	pacer := newPacer(1_200_000)
+	defer pacer.close()
+
+	queue := newPacedQueue(func(*rtp.Header, []byte, interceptor.Attributes) (int, error) {
+		return 0, nil
+	}, 0, pacer)
+	defer queue.close()
+
+	writeStream := &interceptorToTrackLocalWriter{}
+	writeStream.paced.Store(queue)
+	sender := &RTPSender{
+		transport:      &DTLSTransport{iceTransport: &ICETransport{}},
+		trackEncodings: []*trackEncoding{{writeStream: writeStream}},
+	}
+	sender.SetBufferedAmountLowThreshold(1000)
+	assert.Equal(t, uint64(1000), sender.BufferedAmountLowThreshold())
+
+	low := make(chan struct{}, 1)
+	sender.OnBufferedAmountLow(func() {
+		low <- struct{}{}
+	})
+
+	// Below the threshold the handler isn't invoked
+	sender.checkBufferedAmount()
+	select {
+	case <-low:
+		assert.Fail(t, "OnBufferedAmountLow invoked below the threshold")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The burst of the pacer is 3000 bytes, 3000 bytes wait for it and drain in 20ms
+	header := &rtp.Header{Version: 2}
+	payload := make([]byte, 1500-header.MarshalSize())
+	for i := 0; i < 4; i++ {
+		_, err := queue.push(header, payload, nil)
+		assert.NoError(t, err)
+	}
+	assert.Greater(t, sender.BufferedAmount(), uint64(1000))
+	assert.Greater(t, sender.trackEncodings[0].writeStream.BufferedAmount(), uint64(1000))
+	sender.checkBufferedAmount()
+
+	select {
+	case <-low:
+		assert.LessOrEqual(t, sender.BufferedAmount(), uint64(1000))
+	case <-time.After(time.Second):
+		assert.Fail(t, "OnBufferedAmountLow not invoked")
+	}
+
+	sender.stopBackpressure()
+}
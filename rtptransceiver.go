@@ -24,8 +24,21 @@ type RTPTransceiver struct {
 
 	codecs []RTPCodecParameters // User provided codecs via SetCodecPreferences
 
+	// User provided header extensions via AddHeaderExtension and
+	// RemoveHeaderExtension, mapped to whether they are negotiated
+	headerExtensions map[string]bool
+
 	kind RTPCodecType
 
+	// suggestedMid is the mid from RTPTransceiverInit, used when the
+	// transceiver is first offered.
+	suggestedMid string
+
+	// receiveBandwidth is advertised in the media section, remoteBandwidth is
+	// the one of the remote description, see SetReceiveBandwidth.
+	receiveBandwidth uint64
+	remoteBandwidth  uint64
+
 	api *API
 	mu  sync.RWMutex
 }
@@ -52,12 +65,21 @@ func (t *RTPTransceiver) SetCodecPreferences(codecs []RTPCodecParameters) error
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	onlyResiliency := len(codecs) != 0
 	for _, codec := range codecs {
 		if _, matchType := codecParametersFuzzySearch(
 			codec, t.api.mediaEngine.getCodecsByKind(t.kind),
-		); matchType == codecMatchNone {
+		); matchType == CodecMatchNone {
 			return fmt.Errorf("%w %s", errRTPTransceiverCodecUnsupported, codec.MimeType)
 		}
+
+		if !isResiliencyCodec(codec) {
+			onlyResiliency = false
+		}
+	}
+
+	if onlyResiliency {
+		return errRTPTransceiverCodecsOnlyResiliency
 	}
 
 	t.codecs = codecs
@@ -77,7 +99,7 @@ func (t *RTPTransceiver) getCodecs() []RTPCodecParameters {
 
 	filteredCodecs := []RTPCodecParameters{}
 	for _, codec := range t.codecs {
-		if c, matchType := codecParametersFuzzySearch(codec, mediaEngineCodecs); matchType != codecMatchNone {
+		if c, matchType := codecParametersFuzzySearch(codec, mediaEngineCodecs); matchType != CodecMatchNone {
 			if codec.PayloadType == 0 {
 				codec.PayloadType = c.PayloadType
 			}
@@ -86,7 +108,65 @@ func (t *RTPTransceiver) getCodecs() []RTPCodecParameters {
 		}
 	}
 
-	return filteredCodecs
+	return removeDanglingRTX(filteredCodecs)
+}
+
+// AddHeaderExtension negotiates the header extension on the media section of
+// this transceiver, in addition to the ones registered with the MediaEngine.
+// Other transceivers don't negotiate it unless they add it too. When
+// answering it is only negotiated if the remote offered it. Changes are
+// applied by the next CreateOffer or CreateAnswer.
+func (t *RTPTransceiver) AddHeaderExtension(extension RTPHeaderExtensionCapability) error {
+	if _, err := parseExtMapURI(extension.URI); err != nil {
+		return err
+	}
+
+	t.api.mediaEngine.registerTransceiverHeaderExtension(extension, t.kind)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.headerExtensions == nil {
+		t.headerExtensions = map[string]bool{}
+	}
+	t.headerExtensions[extension.URI] = true
+
+	return nil
+}
+
+// RemoveHeaderExtension stops negotiating the header extension on the media
+// section of this transceiver, even if it is registered with the MediaEngine.
+// Changes are applied by the next CreateOffer or CreateAnswer.
+func (t *RTPTransceiver) RemoveHeaderExtension(extension RTPHeaderExtensionCapability) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.headerExtensions == nil {
+		t.headerExtensions = map[string]bool{}
+	}
+	t.headerExtensions[extension.URI] = false
+}
+
+// filterHeaderExtensions returns the header extensions negotiated by this
+// transceiver out of the ones of the MediaEngine.
+func (t *RTPTransceiver) filterHeaderExtensions(
+	headerExtensions []RTPHeaderExtensionParameter,
+) []RTPHeaderExtensionParameter {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	filtered := make([]RTPHeaderExtensionParameter, 0, len(headerExtensions))
+	for _, headerExtension := range headerExtensions {
+		enabled, ok := t.headerExtensions[headerExtension.URI]
+		if !ok {
+			enabled = !t.api.mediaEngine.isPerTransceiverHeaderExtension(headerExtension.URI, t.kind)
+		}
+		if enabled {
+			filtered = append(filtered, headerExtension)
+		}
+	}
+
+	return filtered
 }
 
 // Sender returns the RTPTransceiver's RTPSender if it has one.
@@ -108,6 +188,7 @@ func (t *RTPTransceiver) SetSender(s *RTPSender, track TrackLocal) error {
 func (t *RTPTransceiver) setSender(s *RTPSender) {
 	if s != nil {
 		s.setRTPTransceiver(t)
+		s.bandwidthLimit.setBitrate(int(t.RemoteBandwidth())) //nolint:gosec // G115
 	}
 
 	if prevSender := t.Sender(); prevSender != nil {
@@ -40,3 +40,31 @@ func (r *RTPTransceiver) Receiver() *RTPReceiver {
 
 	return &RTPReceiver{underlying: underlying}
 }
+
+// SetCodecPreferences sets preferred list of supported codecs
+// if codecs is empty or nil we reset to default from MediaEngine.
+func (r *RTPTransceiver) SetCodecPreferences(codecs []RTPCodecParameters) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = recoveryToError(e)
+		}
+	}()
+
+	codecValues := make([]interface{}, 0, len(codecs))
+	for _, codec := range codecs {
+		codecValue := map[string]interface{}{
+			"mimeType":  codec.MimeType,
+			"clockRate": codec.ClockRate,
+		}
+		if codec.Channels != 0 {
+			codecValue["channels"] = codec.Channels
+		}
+		if codec.SDPFmtpLine != "" {
+			codecValue["sdpFmtpLine"] = codec.SDPFmtpLine
+		}
+		codecValues = append(codecValues, codecValue)
+	}
+	r.underlying.Call("setCodecPreferences", codecValues)
+
+	return nil
+}
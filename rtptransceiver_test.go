@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pion/interceptor"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,6 +80,33 @@ func Test_RTPTransceiver_SetCodecPreferences(t *testing.T) {
 		assert.NoError(t, tr.SetCodecPreferences(testCase))
 	}
 
+	assert.ErrorIs(t, tr.SetCodecPreferences([]RTPCodecParameters{
+		{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeRTX, 90000, 0, "apt=96", nil},
+			PayloadType:        97,
+		},
+	}), errRTPTransceiverCodecsOnlyResiliency)
+
+	// RTX for a codec that isn't preferred is removed
+	assert.NoError(t, tr.SetCodecPreferences([]RTPCodecParameters{
+		{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeVP8, 90000, 0, "", nil},
+			PayloadType:        96,
+		},
+		{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeRTX, 90000, 0, "apt=96", nil},
+			PayloadType:        97,
+		},
+		{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeRTX, 90000, 0, "apt=98", nil},
+			PayloadType:        99,
+		},
+	}))
+	codecs := tr.getCodecs()
+	assert.Len(t, codecs, 2)
+	assert.Equal(t, PayloadType(96), codecs[0].PayloadType)
+	assert.Equal(t, PayloadType(97), codecs[1].PayloadType)
+
 	assert.NoError(t, tr.SetCodecPreferences(nil))
 	assert.NotEqual(t, 0, len(tr.getCodecs()))
 
@@ -135,3 +163,84 @@ func Test_RTPTransceiver_SetCodecPreferences_PayloadType(t *testing.T) {
 
 	closePairNow(t, offerPC, answerPC)
 }
+
+func Test_RTPTransceiver_HeaderExtensions(t *testing.T) {
+	const (
+		globalExtension       = "urn:example:global"
+		transceiverExtension  = "urn:example:transceiver"
+		unsupportedExtension  = "urn:example:unsupported"
+		invalidExtensionURI   = "%invalid"
+		firstMid, secondMid   = "0", "1"
+		expectedMediaSections = 2
+	)
+
+	newAPI := func() *API {
+		mediaEngine := &MediaEngine{}
+		assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+		assert.NoError(t, mediaEngine.RegisterHeaderExtension(
+			RTPHeaderExtensionCapability{globalExtension}, RTPCodecTypeVideo,
+		))
+
+		return NewAPI(WithMediaEngine(mediaEngine), WithInterceptorRegistry(&interceptor.Registry{}))
+	}
+
+	extensionsByMid := func(description *SessionDescription) map[string][]string {
+		parsed, err := description.Unmarshal()
+		assert.NoError(t, err)
+		assert.Len(t, parsed.MediaDescriptions, expectedMediaSections)
+
+		extensions := map[string][]string{}
+		for _, media := range parsed.MediaDescriptions {
+			mid, _ := media.Attribute("mid")
+			for _, attribute := range media.Attributes {
+				if attribute.Key == "extmap" {
+					extensions[mid] = append(extensions[mid], strings.Fields(attribute.Value)[1])
+				}
+			}
+		}
+
+		return extensions
+	}
+
+	offerer, err := newAPI().NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+	answerer, err := newAPI().NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	first, err := offerer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	_, err = offerer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+
+	assert.Error(t, first.AddHeaderExtension(RTPHeaderExtensionCapability{invalidExtensionURI}))
+	assert.NoError(t, first.AddHeaderExtension(RTPHeaderExtensionCapability{transceiverExtension}))
+	first.RemoveHeaderExtension(RTPHeaderExtensionCapability{globalExtension})
+
+	offer, err := offerer.CreateOffer(nil)
+	assert.NoError(t, err)
+	offerExtensions := extensionsByMid(&offer)
+	assert.Equal(t, []string{transceiverExtension}, offerExtensions[firstMid])
+	assert.Equal(t, []string{globalExtension}, offerExtensions[secondMid])
+
+	assert.NoError(t, offerer.SetLocalDescription(offer))
+	assert.NoError(t, answerer.SetRemoteDescription(offer))
+
+	// The answer only has the extensions offered in each media section, that
+	// the transceiver accepts
+	transceivers := answerer.GetTransceivers()
+	assert.Len(t, transceivers, expectedMediaSections)
+	for _, transceiver := range transceivers {
+		assert.NoError(t, transceiver.AddHeaderExtension(RTPHeaderExtensionCapability{transceiverExtension}))
+		assert.NoError(t, transceiver.AddHeaderExtension(RTPHeaderExtensionCapability{unsupportedExtension}))
+	}
+	transceivers[1].RemoveHeaderExtension(RTPHeaderExtensionCapability{globalExtension})
+
+	answer, err := answerer.CreateAnswer(nil)
+	assert.NoError(t, err)
+	answerExtensions := extensionsByMid(&answer)
+	assert.Equal(t, []string{transceiverExtension}, answerExtensions[firstMid])
+	assert.Empty(t, answerExtensions[secondMid])
+
+	assert.NoError(t, offerer.Close())
+	assert.NoError(t, answerer.Close())
+}
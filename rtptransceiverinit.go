@@ -9,4 +9,10 @@ type RTPTransceiverInit struct {
 	Direction     RTPTransceiverDirection
 	SendEncodings []RTPEncodingParameters
 	// Streams       []*Track
+
+	// Mid is suggested as the mid of the transceiver when it is first offered,
+	// it must be unique within the PeerConnection and at most 16 bytes long.
+	// A transceiver matched to a media section of a remote offer takes the mid
+	// of the remote media section instead. Ignored in WASM.
+	Mid string
 }
@@ -77,3 +77,88 @@ func Test_RTPTransceiverInit_SSRC(t *testing.T) {
 		closePairNow(t, offerer, answerer)
 	})
 }
+
+func Test_RTPTransceiverInit_SimulcastSendEncodings(t *testing.T) {
+	peerConnection, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	newLayer := func(rid string) *TrackLocalStaticSample {
+		track, err := NewTrackLocalStaticSample(
+			RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithRTPStreamID(rid),
+		)
+		assert.NoError(t, err)
+
+		return track
+	}
+
+	sendEncodings := []RTPEncodingParameters{
+		{RTPCodingParameters: RTPCodingParameters{RID: "q", SSRC: 1000}},
+		{RTPCodingParameters: RTPCodingParameters{RID: "h", SSRC: 2000}, MaxBitrate: 500_000},
+		{RTPCodingParameters: RTPCodingParameters{RID: "f", SSRC: 3000}},
+	}
+
+	_, err = peerConnection.AddTransceiverFromTrack(newLayer("h"), RTPTransceiverInit{
+		Direction:     RTPTransceiverDirectionSendonly,
+		SendEncodings: sendEncodings,
+	})
+	assert.ErrorIs(t, err, errRTPSenderBaseEncodingMismatch)
+
+	transceiver, err := peerConnection.AddTransceiverFromTrack(newLayer("q"), RTPTransceiverInit{
+		Direction:     RTPTransceiverDirectionSendonly,
+		SendEncodings: sendEncodings,
+	})
+	assert.NoError(t, err)
+
+	sender := transceiver.Sender()
+	assert.NoError(t, sender.AddEncoding(newLayer("h")))
+	assert.NoError(t, sender.AddEncoding(newLayer("f")))
+	assert.ErrorIs(t, sender.AddEncoding(newLayer("x")), errRTPSenderRIDNotInSendEncodings)
+
+	encodings := sender.GetParameters().Encodings
+	assert.Len(t, encodings, 3)
+	for i, encoding := range encodings {
+		assert.Equal(t, sendEncodings[i].RID, encoding.RID)
+		assert.Equal(t, sendEncodings[i].SSRC, encoding.SSRC)
+		assert.Equal(t, sendEncodings[i].MaxBitrate, encoding.MaxBitrate)
+		assert.True(t, *encoding.Active)
+	}
+
+	offer, err := peerConnection.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, offer.SDP, "a=simulcast:send q;h;f")
+	assert.Contains(t, offer.SDP, "a=ssrc:2000 ")
+
+	assert.NoError(t, peerConnection.Close())
+}
+
+func Test_RTPTransceiverInit_SingleSendEncoding(t *testing.T) {
+	peerConnection, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	// A single encoding applies to the track even when the RIDs don't match
+	for _, rid := range []string{"", "q"} {
+		track, err := NewTrackLocalStaticSample(
+			RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithRTPStreamID(rid),
+		)
+		assert.NoError(t, err)
+
+		transceiver, err := peerConnection.AddTransceiverFromTrack(track, RTPTransceiverInit{
+			Direction: RTPTransceiverDirectionSendonly,
+			SendEncodings: []RTPEncodingParameters{
+				{RTPCodingParameters: RTPCodingParameters{
+					RID:  "f",
+					SSRC: 5000,
+					RTX:  RTPRtxParameters{SSRC: 5001},
+				}},
+			},
+		})
+		assert.NoError(t, err)
+
+		encodings := transceiver.Sender().GetParameters().Encodings
+		assert.Len(t, encodings, 1)
+		assert.Equal(t, SSRC(5000), encodings[0].SSRC)
+		assert.Equal(t, SSRC(5001), encodings[0].RTX.SSRC)
+	}
+
+	assert.NoError(t, peerConnection.Close())
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"time"
+
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// sampleExtensionsHistory is how many RTP timestamps ReadSample remembers the
+// header extensions of, enough for the samples pending in the SampleBuilder.
+const sampleExtensionsHistory = 32
+
+// headerExtensionID returns the negotiated ID of the header extension uri,
+// or 0 if it wasn't negotiated.
+func headerExtensionID(extensions []RTPHeaderExtensionParameter, uri string) uint8 {
+	for _, extension := range extensions {
+		if extension.URI == uri {
+			return uint8(extension.ID) //nolint:gosec // G115
+		}
+	}
+
+	return 0
+}
+
+// sampleExtensions are the header extension values of the packets of a RTP
+// timestamp that ReadSample sets on the sample built from them.
+type sampleExtensions struct {
+	captureTime time.Time
+	orientation *media.VideoOrientation
+	csrc        []uint32
+}
+
+// sampleExtensionsRing remembers the sampleExtensions of the last RTP
+// timestamps read.
+type sampleExtensionsRing struct {
+	timestamps [sampleExtensionsHistory]uint32
+	extensions [sampleExtensionsHistory]sampleExtensions
+	used       [sampleExtensionsHistory]bool
+	next       int
+}
+
+// entry returns the sampleExtensions of timestamp to be updated, the oldest
+// entry is replaced if timestamp has none yet.
+func (r *sampleExtensionsRing) entry(timestamp uint32) *sampleExtensions {
+	for i := range r.timestamps {
+		if r.used[i] && r.timestamps[i] == timestamp {
+			return &r.extensions[i]
+		}
+	}
+
+	i := r.next
+	r.timestamps[i], r.extensions[i], r.used[i] = timestamp, sampleExtensions{}, true
+	r.next = (r.next + 1) % sampleExtensionsHistory
+
+	return &r.extensions[i]
+}
+
+func (r *sampleExtensionsRing) get(timestamp uint32) sampleExtensions {
+	for i := range r.timestamps {
+		if r.used[i] && r.timestamps[i] == timestamp {
+			return r.extensions[i]
+		}
+	}
+
+	return sampleExtensions{}
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleExtensionsRing(t *testing.T) {
+	ring := sampleExtensionsRing{}
+	start := time.Unix(1700000000, 0)
+
+	for i := 0; i < sampleExtensionsHistory+1; i++ {
+		timestamp := uint32(i * 3000) //nolint:gosec // G115
+		ring.entry(timestamp).captureTime = start.Add(time.Duration(i) * time.Second)
+		// The other packets of the frame don't take more room
+		ring.entry(timestamp).captureTime = start.Add(time.Duration(i) * time.Second)
+	}
+
+	assert.True(t, ring.get(0).captureTime.IsZero())
+	assert.Equal(t, start.Add(time.Second), ring.get(3000).captureTime)
+	assert.Equal(t, start.Add(sampleExtensionsHistory*time.Second), ring.get(sampleExtensionsHistory*3000).captureTime)
+	assert.True(t, ring.get(1).captureTime.IsZero())
+
+	// Extensions of a timestamp are kept together
+	ring.entry(3000).orientation = &media.VideoOrientation{Rotation: 90}
+	ring.entry(3000).csrc = []uint32{2, 3}
+	extensions := ring.get(3000)
+	assert.Equal(t, start.Add(time.Second), extensions.captureTime)
+	assert.Equal(t, uint16(90), extensions.orientation.Rotation)
+	assert.Equal(t, []uint32{2, 3}, extensions.csrc)
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// L = spatial layers with inter-layer prediction, S = simulcast like spatial
+// layers without it, T = temporal layers. https://www.w3.org/TR/webrtc-svc/#scalabilitymodes*
+var scalabilityModeRegex = regexp.MustCompile(`^([LS])([1-3])T([1-3])(h|_KEY|_KEY_SHIFT)?$`)
+
+// parseScalabilityMode returns the number of spatial and temporal layers of a
+// scalability mode.
+func parseScalabilityMode(mode string) (spatialLayers, temporalLayers int, err error) {
+	match := scalabilityModeRegex.FindStringSubmatch(mode)
+	if match == nil {
+		return 0, 0, fmt.Errorf("%w: %s", errInvalidScalabilityMode, mode)
+	}
+
+	// Suffixes and S modes require multiple spatial layers, _KEY modes are L only
+	kind, suffix := match[1], match[4]
+	if ((suffix != "" || kind == "S") && match[2] == "1") || (suffix != "" && suffix != "h" && kind != "L") {
+		return 0, 0, fmt.Errorf("%w: %s", errInvalidScalabilityMode, mode)
+	}
+
+	spatialLayers, _ = strconv.Atoi(match[2])
+	temporalLayers, _ = strconv.Atoi(match[3])
+
+	return spatialLayers, temporalLayers, nil
+}
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScalabilityMode(t *testing.T) {
+	for _, test := range []struct {
+		mode                          string
+		spatialLayers, temporalLayers int
+	}{
+		{"L1T1", 1, 1},
+		{"L1T3", 1, 3},
+		{"L2T2h", 2, 2},
+		{"L3T3_KEY", 3, 3},
+		{"L2T3_KEY_SHIFT", 2, 3},
+		{"S2T1", 2, 1},
+		{"S3T3h", 3, 3},
+	} {
+		spatialLayers, temporalLayers, err := parseScalabilityMode(test.mode)
+		assert.NoError(t, err, test.mode)
+		assert.Equal(t, test.spatialLayers, spatialLayers, test.mode)
+		assert.Equal(t, test.temporalLayers, temporalLayers, test.mode)
+	}
+
+	for _, mode := range []string{"", "L0T1", "L4T1", "L1T4", "L1T2h", "L1T1_KEY", "S1T2", "S2T2_KEY", "l1t1"} {
+		_, _, err := parseScalabilityMode(mode)
+		assert.ErrorIs(t, err, errInvalidScalabilityMode, mode)
+	}
+}
@@ -38,6 +38,9 @@ type SCTPTransport struct {
 	// DataChannel's send() method.
 	maxMessageSize float64
 
+	// remoteCapabilities are the SCTPCapabilities passed to Start
+	remoteCapabilities SCTPCapabilities
+
 	// MaxChannels represents the maximum amount of DataChannel's that can
 	// be used simultaneously.
 	maxChannels *uint16
@@ -74,7 +77,7 @@ func (api *API) NewSCTPTransport(dtls *DTLSTransport) *SCTPTransport {
 		dataChannelIDsUsed: make(map[uint16]struct{}),
 	}
 
-	res.updateMessageSize()
+	res.updateMessageSize(sctpDefaultMaxMessageSize)
 	res.updateMaxChannels()
 
 	return res
@@ -88,22 +91,59 @@ func (r *SCTPTransport) Transport() *DTLSTransport {
 	return r.dtlsTransport
 }
 
-// GetCapabilities returns the SCTPCapabilities of the SCTPTransport.
+// GetCapabilities returns the SCTPCapabilities of the SCTPTransport. Its
+// MaxMessageSize is the largest message it accepts, which a PeerConnection
+// advertises with a=max-message-size, see SettingEngine.SetSCTPMaxMessageSize.
 func (r *SCTPTransport) GetCapabilities() SCTPCapabilities {
 	return SCTPCapabilities{
-		MaxMessageSize: 0,
+		MaxMessageSize: r.localMaxMessageSize(),
 	}
 }
 
+// GetRemoteCapabilities returns the SCTPCapabilities of the remote peer passed
+// to Start. A PeerConnection reads its MaxMessageSize from the
+// a=max-message-size of the remote description, zero means the remote peer
+// accepts messages of any size.
+func (r *SCTPTransport) GetRemoteCapabilities() SCTPCapabilities {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.remoteCapabilities
+}
+
+// MaxMessageSize returns the largest message that can be passed to the Send
+// methods of the DataChannels, the smaller of the local and remote limits.
+// It is +Inf if neither has a limit, and the local limit until Start is called.
+func (r *SCTPTransport) MaxMessageSize() float64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.maxMessageSize
+}
+
+func (r *SCTPTransport) localMaxMessageSize() uint32 {
+	if size := r.api.settingEngine.sctp.maxMessageSize; size != 0 {
+		return size
+	}
+
+	return sctpDefaultMaxMessageSize
+}
+
 // Start the SCTPTransport. Since both local and remote parties must mutually
 // create an SCTPTransport, SCTP SO (Simultaneous Open) is used to establish
-// a connection over SCTP.
-func (r *SCTPTransport) Start(_ SCTPCapabilities) error {
+// a connection over SCTP. The MaxMessageSize of remoteCaps limits the
+// messages sent, zero for no limit.
+func (r *SCTPTransport) Start(remoteCaps SCTPCapabilities) error {
 	if r.isStarted {
 		return nil
 	}
 	r.isStarted = true
 
+	r.lock.Lock()
+	r.remoteCapabilities = remoteCaps
+	r.lock.Unlock()
+	r.updateMessageSize(remoteCaps.MaxMessageSize)
+
 	dtlsTransport := r.Transport()
 	if dtlsTransport == nil || dtlsTransport.conn == nil {
 		return errSCTPTransportDTLS
@@ -114,6 +154,10 @@ func (r *SCTPTransport) Start(_ SCTPCapabilities) error {
 		EnableZeroChecksum:   r.api.settingEngine.sctp.enableZeroChecksum,
 		LoggerFactory:        r.api.settingEngine.LoggerFactory,
 		RTOMax:               float64(r.api.settingEngine.sctp.rtoMax) / float64(time.Millisecond),
+		MaxMessageSize:       r.sendMaxMessageSize(),
+		MinCwnd:              r.api.settingEngine.sctp.minCwnd,
+		FastRtxWnd:           r.api.settingEngine.sctp.fastRtxWnd,
+		CwndCAStep:           r.api.settingEngine.sctp.cwndCAStep,
 		BlockWrite:           r.api.settingEngine.detach.DataChannels && r.api.settingEngine.dataChannelBlockWrite,
 	})
 	if err != nil {
@@ -201,43 +245,10 @@ ACCEPT:
 			}
 		}
 
-		var (
-			maxRetransmits    *uint16
-			maxPacketLifeTime *uint16
+		rtcDC, err := r.api.newDataChannel(
+			dataChannelParametersFromConfig(&dc.Config, dc.StreamIdentifier()),
+			r, r.api.settingEngine.LoggerFactory.NewLogger("ortc"),
 		)
-		val := uint16(dc.Config.ReliabilityParameter) //nolint:gosec //G115
-		ordered := true
-
-		switch dc.Config.ChannelType {
-		case datachannel.ChannelTypeReliable:
-			ordered = true
-		case datachannel.ChannelTypeReliableUnordered:
-			ordered = false
-		case datachannel.ChannelTypePartialReliableRexmit:
-			ordered = true
-			maxRetransmits = &val
-		case datachannel.ChannelTypePartialReliableRexmitUnordered:
-			ordered = false
-			maxRetransmits = &val
-		case datachannel.ChannelTypePartialReliableTimed:
-			ordered = true
-			maxPacketLifeTime = &val
-		case datachannel.ChannelTypePartialReliableTimedUnordered:
-			ordered = false
-			maxPacketLifeTime = &val
-		default:
-		}
-
-		sid := dc.StreamIdentifier()
-		rtcDC, err := r.api.newDataChannel(&DataChannelParameters{
-			ID:                &sid,
-			Label:             dc.Config.Label,
-			Protocol:          dc.Config.Protocol,
-			Negotiated:        dc.Config.Negotiated,
-			Ordered:           ordered,
-			MaxPacketLifeTime: maxPacketLifeTime,
-			MaxRetransmits:    maxRetransmits,
-		}, r, r.api.settingEngine.LoggerFactory.NewLogger("ortc"))
 		if err != nil {
 			// This data channel is invalid. Close it and log an error.
 			if err1 := dc.Close(); err1 != nil {
@@ -263,6 +274,47 @@ ACCEPT:
 	}
 }
 
+// dataChannelParametersFromConfig maps the DCEP open message of a remotely
+// opened channel to the parameters of the DataChannel accepting it.
+func dataChannelParametersFromConfig(config *datachannel.Config, sid uint16) *DataChannelParameters {
+	var (
+		maxRetransmits    *uint16
+		maxPacketLifeTime *uint16
+	)
+	val := uint16(config.ReliabilityParameter) //nolint:gosec //G115
+	ordered := true
+
+	switch config.ChannelType {
+	case datachannel.ChannelTypeReliable:
+		ordered = true
+	case datachannel.ChannelTypeReliableUnordered:
+		ordered = false
+	case datachannel.ChannelTypePartialReliableRexmit:
+		ordered = true
+		maxRetransmits = &val
+	case datachannel.ChannelTypePartialReliableRexmitUnordered:
+		ordered = false
+		maxRetransmits = &val
+	case datachannel.ChannelTypePartialReliableTimed:
+		ordered = true
+		maxPacketLifeTime = &val
+	case datachannel.ChannelTypePartialReliableTimedUnordered:
+		ordered = false
+		maxPacketLifeTime = &val
+	default:
+	}
+
+	return &DataChannelParameters{
+		ID:                &sid,
+		Label:             config.Label,
+		Protocol:          config.Protocol,
+		Negotiated:        config.Negotiated,
+		Ordered:           ordered,
+		MaxPacketLifeTime: maxPacketLifeTime,
+		MaxRetransmits:    maxRetransmits,
+	}
+}
+
 // OnError sets an event handler which is invoked when the SCTP Association errors.
 func (r *SCTPTransport) OnError(f func(err error)) {
 	r.lock.Lock()
@@ -344,14 +396,26 @@ func (r *SCTPTransport) onDataChannel(dc *DataChannel) (done chan struct{}) {
 	return
 }
 
-func (r *SCTPTransport) updateMessageSize() {
+// updateMessageSize computes the maxMessageSize from the limit of the remote
+// peer. The local limit is also the largest message Pion sends, pion/webrtc#758.
+func (r *SCTPTransport) updateMessageSize(remoteMaxMessageSize uint32) {
+	canSendSize := r.localMaxMessageSize()
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	var remoteMaxMessageSize float64 = 65536 // pion/webrtc#758
-	var canSendSize float64 = 65536          // pion/webrtc#758
+	r.maxMessageSize = r.calcMessageSize(float64(remoteMaxMessageSize), float64(canSendSize))
+}
 
-	r.maxMessageSize = r.calcMessageSize(remoteMaxMessageSize, canSendSize)
+// sendMaxMessageSize returns the maxMessageSize as the limit of the SCTP
+// association.
+func (r *SCTPTransport) sendMaxMessageSize() uint32 {
+	maxMessageSize := r.MaxMessageSize()
+	if maxMessageSize >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+
+	return uint32(maxMessageSize)
 }
 
 func (r *SCTPTransport) calcMessageSize(remoteMaxMessageSize, canSendSize float64) float64 {
@@ -399,13 +463,16 @@ func (r *SCTPTransport) State() SCTPTransportState {
 	return r.state
 }
 
-func (r *SCTPTransport) collectStats(collector *statsReportCollector) {
-	collector.Collecting()
-
+// GetStats returns the statistics of the SCTP association, as included in
+// PeerConnection.GetStats, so the throughput of DataChannels can be diagnosed
+// without reading the stats of the whole PeerConnection. The values are zero
+// until the association is established.
+func (r *SCTPTransport) GetStats() SCTPTransportStats {
 	stats := SCTPTransportStats{
-		Timestamp: statsTimestampFrom(time.Now()),
-		Type:      StatsTypeSCTPTransport,
-		ID:        "sctpTransport",
+		Timestamp:   statsTimestampFrom(time.Now()),
+		Type:        StatsTypeSCTPTransport,
+		ID:          "sctpTransport",
+		TransportID: "iceTransport",
 	}
 
 	association := r.association()
@@ -418,6 +485,13 @@ func (r *SCTPTransport) collectStats(collector *statsReportCollector) {
 		stats.MTU = association.MTU()
 	}
 
+	return stats
+}
+
+func (r *SCTPTransport) collectStats(collector *statsReportCollector) {
+	collector.Collecting()
+
+	stats := r.GetStats()
 	collector.Collect(stats.ID, stats)
 }
 
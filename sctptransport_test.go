@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
 	"github.com/stretchr/testify/require"
 )
 
@@ -261,3 +262,87 @@ func TestSCTPTransportOutOfBandNegotiatedDataChannelDetach(t *testing.T) { //nol
 		}
 	}
 }
+
+func TestSCTPTransport_GetStats(t *testing.T) {
+	offerPC, answerPC, err := newPair()
+	require.NoError(t, err)
+	defer closePairNow(t, offerPC, answerPC)
+
+	stats := offerPC.SCTP().GetStats()
+	require.Equal(t, "sctpTransport", stats.ID)
+	require.Equal(t, StatsTypeSCTPTransport, stats.Type)
+	require.Zero(t, stats.CongestionWindow)
+
+	received := make(chan struct{})
+	answerPC.OnDataChannel(func(dc *DataChannel) {
+		dc.OnMessage(func(DataChannelMessage) {
+			close(received)
+		})
+	})
+
+	dc, err := offerPC.CreateDataChannel(expectedLabel, nil)
+	require.NoError(t, err)
+	dc.OnOpen(func() {
+		if sendErr := dc.SendText("hello"); sendErr != nil {
+			t.Error("failed to send message", sendErr)
+		}
+	})
+
+	require.NoError(t, signalPair(offerPC, answerPC))
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+
+	stats = offerPC.SCTP().GetStats()
+	require.NotZero(t, stats.BytesSent)
+	require.NotZero(t, stats.CongestionWindow)
+	require.NotZero(t, stats.MTU)
+	require.Equal(t, stats.BytesSent, getSctpTransportStats(t, offerPC.GetStats()).BytesSent)
+}
+
+func TestSCTPTransport_MaxMessageSize(t *testing.T) {
+	settingEngine := SettingEngine{}
+	settingEngine.SetSCTPMaxMessageSize(256 * 1024)
+	offerPC, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	answerPC, err := NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	defer closePairNow(t, offerPC, answerPC)
+
+	require.Equal(t, uint32(256*1024), offerPC.SCTP().GetCapabilities().MaxMessageSize)
+	require.Equal(t, uint32(sctpDefaultMaxMessageSize), answerPC.SCTP().GetCapabilities().MaxMessageSize)
+
+	opened := make(chan *DataChannel, 1)
+	dc, err := offerPC.CreateDataChannel(expectedLabel, nil)
+	require.NoError(t, err)
+	dc.OnOpen(func() {
+		opened <- dc
+	})
+
+	offer, err := offerPC.CreateOffer(nil)
+	require.NoError(t, err)
+	require.Contains(t, offer.SDP, "a=max-message-size:262144\r\n")
+	require.NoError(t, signalPair(offerPC, answerPC))
+
+	select {
+	case <-opened:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+
+	require.Equal(t, uint32(sctpDefaultMaxMessageSize), offerPC.SCTP().GetRemoteCapabilities().MaxMessageSize)
+	require.Equal(t, uint32(256*1024), answerPC.SCTP().GetRemoteCapabilities().MaxMessageSize)
+	require.Equal(t, float64(sctpDefaultMaxMessageSize), offerPC.SCTP().MaxMessageSize())
+	require.Equal(t, float64(sctpDefaultMaxMessageSize), answerPC.SCTP().MaxMessageSize())
+
+	require.NoError(t, dc.Send(make([]byte, sctpDefaultMaxMessageSize)))
+
+	err = dc.Send(make([]byte, sctpDefaultMaxMessageSize+1))
+	require.ErrorIs(t, err, ErrDataChannelMessageTooLarge)
+	var typeErr *rtcerr.TypeError
+	require.ErrorAs(t, err, &typeErr)
+	require.ErrorIs(t, dc.SendText(string(make([]byte, sctpDefaultMaxMessageSize+1))), ErrDataChannelMessageTooLarge)
+}
@@ -8,7 +8,6 @@ package webrtc
 
 import (
 	"errors"
-	"fmt"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -20,18 +19,83 @@ import (
 	"github.com/pion/sdp/v3"
 )
 
+// extMapURIs holds the parsed URIs of the header extensions known to Pion.
+// The same handful of extensions is added to every media section of every
+// local description, parsing them each time is a large share of the cost of
+// big descriptions. Only known URIs are cached so the table can't be grown by
+// remote or application supplied extensions. The values are cached, so every
+// caller gets its own copy to modify.
+var extMapURIs = func() map[string]url.URL { //nolint:gochecknoglobals
+	uris := map[string]url.URL{}
+	for _, uri := range []string{
+		sdp.ABSSendTimeURI,
+		sdp.TransportCCURI,
+		sdp.SDESMidURI,
+		sdp.SDESRTPStreamIDURI,
+		sdp.SDESRepairRTPStreamIDURI,
+		sdp.AudioLevelURI,
+		CSRCAudioLevelURI,
+		VideoOrientationURI,
+		AbsCaptureTimeURI,
+	} {
+		if parsed, err := url.Parse(uri); err == nil {
+			uris[uri] = *parsed
+		}
+	}
+
+	return uris
+}()
+
+func parseExtMapURI(uri string) (*url.URL, error) {
+	if cached, ok := extMapURIs[uri]; ok {
+		return &cached, nil
+	}
+
+	return url.Parse(uri)
+}
+
 // trackDetails represents any media source that can be represented in a SDP
 // This isn't keyed by SSRC because it also needs to support rid based sources.
 type trackDetails struct {
 	mid        string
 	kind       RTPCodecType
 	streamID   string
+	streamIDs  []string
 	id         string
 	ssrcs      []SSRC
 	repairSsrc *SSRC
 	rids       []string
 }
 
+// appendMsidStreamID adds the stream id of a a=msid line, "-" declares a
+// track that isn't part of any stream.
+func appendMsidStreamID(streamIDs []string, streamID string) []string {
+	if streamID == "-" {
+		return streamIDs
+	}
+	for _, existing := range streamIDs {
+		if existing == streamID {
+			return streamIDs
+		}
+	}
+
+	return append(streamIDs, streamID)
+}
+
+// trackStreamIDs returns the stream ids of the a=msid lines of a media
+// section, or the stream id of the a=ssrc msid attribute of Plan B.
+func trackStreamIDs(msidStreamIDs []string, streamID string) []string {
+	if len(msidStreamIDs) == 0 {
+		if streamID == "" {
+			return nil
+		}
+
+		return appendMsidStreamID(nil, streamID)
+	}
+
+	return append([]string{}, msidStreamIDs...)
+}
+
 func trackDetailsForSSRC(trackDetails []trackDetails, ssrc SSRC) *trackDetails {
 	for i := range trackDetails {
 		for j := range trackDetails[i].ssrcs {
@@ -95,6 +159,7 @@ func trackDetailsFromSDP(
 		// Plan B can have multiple tracks in a single media section
 		streamID := ""
 		trackID := ""
+		msidStreamIDs := []string{}
 
 		// If media section is recvonly or inactive skip
 		if _, ok := media.Attribute(sdp.AttrKeyRecvOnly); ok {
@@ -157,6 +222,7 @@ func trackDetailsFromSDP(
 				if len(split) == 2 {
 					streamID = split[0]
 					trackID = split[1]
+					msidStreamIDs = appendMsidStreamID(msidStreamIDs, split[0])
 				}
 
 			case sdp.AttrKeySSRC:
@@ -191,6 +257,7 @@ func trackDetailsFromSDP(
 				trackDetails.mid = midValue
 				trackDetails.kind = codecType
 				trackDetails.streamID = streamID
+				trackDetails.streamIDs = trackStreamIDs(msidStreamIDs, streamID)
 				trackDetails.id = trackID
 				trackDetails.ssrcs = []SSRC{SSRC(ssrc)}
 
@@ -207,13 +274,22 @@ func trackDetailsFromSDP(
 			}
 		}
 
+		// The a=msid lines apply to every track of the media section, even
+		// if they follow the a=ssrc lines
+		if len(msidStreamIDs) != 0 {
+			for i := range tracksInMediaSection {
+				tracksInMediaSection[i].streamIDs = trackStreamIDs(msidStreamIDs, streamID)
+			}
+		}
+
 		if rids := getRids(media); len(rids) != 0 && trackID != "" && streamID != "" {
 			simulcastTrack := trackDetails{
-				mid:      midValue,
-				kind:     codecType,
-				streamID: streamID,
-				id:       trackID,
-				rids:     []string{},
+				mid:       midValue,
+				kind:      codecType,
+				streamID:  streamID,
+				streamIDs: trackStreamIDs(msidStreamIDs, streamID),
+				id:        trackID,
+				rids:      []string{},
 			}
 			for _, rid := range rids {
 				simulcastTrack.rids = append(simulcastTrack.rids, rid.id)
@@ -333,6 +409,7 @@ func addDataMediaSection(
 	shouldAddCandidates bool,
 	dtlsFingerprints []DTLSFingerprint,
 	midValue string,
+	maxMessageSize uint32,
 	iceParams ICEParameters,
 	candidates []ICECandidate,
 	dtlsRole sdp.ConnectionRole,
@@ -359,6 +436,10 @@ func addDataMediaSection(
 		WithPropertyAttribute("sctp-port:5000").
 		WithICECredentials(iceParams.UsernameFragment, iceParams.Password)
 
+	if maxMessageSize != 0 {
+		media = media.WithValueAttribute(sdpAttributeMaxMessageSize, strconv.FormatUint(uint64(maxMessageSize), 10))
+	}
+
 	for _, f := range dtlsFingerprints {
 		media = media.WithFingerprint(f.Algorithm, strings.ToUpper(f.Value))
 	}
@@ -428,10 +509,10 @@ func addSenderSDP(
 		sendParameters := sender.GetParameters()
 		for _, encoding := range sendParameters.Encodings {
 			if encoding.RTX.SSRC != 0 {
-				media = media.WithValueAttribute("ssrc-group", fmt.Sprintf("FID %d %d", encoding.SSRC, encoding.RTX.SSRC))
+				media = media.WithValueAttribute("ssrc-group", ssrcGroup("FID", encoding.SSRC, encoding.RTX.SSRC))
 			}
 			if encoding.FEC.SSRC != 0 {
-				media = media.WithValueAttribute("ssrc-group", fmt.Sprintf("FEC-FR %d %d", encoding.SSRC, encoding.FEC.SSRC))
+				media = media.WithValueAttribute("ssrc-group", ssrcGroup("FEC-FR", encoding.SSRC, encoding.FEC.SSRC))
 			}
 
 			media = media.WithMediaSource(
@@ -480,6 +561,28 @@ func addSenderSDP(
 	}
 }
 
+func ssrcGroup(semantics string, ssrcs ...SSRC) string {
+	buf := make([]byte, 0, len(semantics)+len(ssrcs)*11)
+	buf = append(buf, semantics...)
+	for _, ssrc := range ssrcs {
+		buf = append(buf, ' ')
+		buf = strconv.AppendUint(buf, uint64(ssrc), 10)
+	}
+
+	return string(buf)
+}
+
+func rtcpFeedbackValue(payloadType PayloadType, feedback RTCPFeedback) string {
+	buf := make([]byte, 0, 5+len(feedback.Type)+len(feedback.Parameter))
+	buf = strconv.AppendUint(buf, uint64(payloadType), 10)
+	buf = append(buf, ' ')
+	buf = append(buf, feedback.Type...)
+	buf = append(buf, ' ')
+	buf = append(buf, feedback.Parameter...)
+
+	return string(buf)
+}
+
 //nolint:cyclop
 func addTransceiverSDP(
 	descr *sdp.SessionDescription,
@@ -514,7 +617,7 @@ func addTransceiverSDP(
 		media.WithCodec(uint8(codec.PayloadType), name, codec.ClockRate, codec.Channels, codec.SDPFmtpLine)
 
 		for _, feedback := range codec.RTPCodecCapability.RTCPFeedback {
-			media.WithValueAttribute("rtcp-fb", fmt.Sprintf("%d %s %s", codec.PayloadType, feedback.Type, feedback.Parameter))
+			media.WithValueAttribute("rtcp-fb", rtcpFeedbackValue(codec.PayloadType, feedback))
 		}
 	}
 	if len(codecs) == 0 {
@@ -558,13 +661,13 @@ func addTransceiverSDP(
 	}
 
 	parameters := mediaEngine.getRTPParametersByKind(transceiver.kind, directions)
-	for _, rtpExtension := range parameters.HeaderExtensions {
+	for _, rtpExtension := range transceiver.filterHeaderExtensions(parameters.HeaderExtensions) {
 		if mediaSection.matchExtensions != nil {
 			if _, enabled := mediaSection.matchExtensions[rtpExtension.URI]; !enabled {
 				continue
 			}
 		}
-		extURL, err := url.Parse(rtpExtension.URI)
+		extURL, err := parseExtMapURI(rtpExtension.URI)
 		if err != nil {
 			return false, err
 		}
@@ -587,6 +690,7 @@ func addTransceiverSDP(
 	}
 
 	addSenderSDP(mediaSection, isPlanB, media)
+	addBandwidth(media, transceiver.ReceiveBandwidth())
 
 	media = media.WithPropertyAttribute(transceiver.Direction().String())
 
@@ -617,6 +721,28 @@ type mediaSection struct {
 	data            bool
 	matchExtensions map[string]int
 	rids            []*simulcastRid
+	// maxMessageSize of a data section is advertised with a=max-message-size
+	maxMessageSize uint32
+}
+
+// dataMediaSectionID returns the mid of a new application media section, the
+// index it is added at unless a transceiver already has that mid.
+func dataMediaSectionID(mediaSections []mediaSection) string {
+	for index := len(mediaSections); ; index++ {
+		id := strconv.Itoa(index)
+		inUse := false
+		for _, section := range mediaSections {
+			if section.id == id {
+				inUse = true
+
+				break
+			}
+		}
+
+		if !inUse {
+			return id
+		}
+	}
 }
 
 func bundleMatchFromRemote(matchBundleGroup *string) func(mid string) bool {
@@ -659,19 +785,32 @@ func populateSDP(
 	var err error
 	mediaDtlsFingerprints := []DTLSFingerprint{}
 
+	// Uppercase once instead of for every media section
+	upperFingerprints := make([]DTLSFingerprint, len(dtlsFingerprints))
+	for i, fingerprint := range dtlsFingerprints {
+		upperFingerprints[i] = DTLSFingerprint{Algorithm: fingerprint.Algorithm, Value: strings.ToUpper(fingerprint.Value)}
+	}
+	dtlsFingerprints = upperFingerprints
+
 	if mediaDescriptionFingerprint {
 		mediaDtlsFingerprints = dtlsFingerprints
 	}
 
-	bundleValue := "BUNDLE"
+	var bundleValue strings.Builder
+	bundleValue.WriteString("BUNDLE")
 	bundleCount := 0
 
 	bundleMatch := bundleMatchFromRemote(matchBundleGroup)
 	appendBundle := func(midValue string) {
-		bundleValue += " " + midValue
+		bundleValue.WriteByte(' ')
+		bundleValue.WriteString(midValue)
 		bundleCount++
 	}
 
+	if descr.MediaDescriptions == nil {
+		descr.MediaDescriptions = make([]*sdp.MediaDescription, 0, len(mediaSections))
+	}
+
 	for i, section := range mediaSections {
 		if section.data && len(section.transceivers) != 0 {
 			return nil, errSDPMediaSectionMediaDataChanInvalid
@@ -687,6 +826,7 @@ func populateSDP(
 				shouldAddCandidates,
 				mediaDtlsFingerprints,
 				section.id,
+				section.maxMessageSize,
 				iceParams,
 				candidates,
 				connectionRole,
@@ -738,7 +878,7 @@ func populateSDP(
 	}
 
 	if bundleCount > 0 {
-		descr = descr.WithValueAttribute(sdp.AttrKeyGroup, bundleValue)
+		descr = descr.WithValueAttribute(sdp.AttrKeyGroup, bundleValue.String())
 	}
 
 	return descr, nil
@@ -754,6 +894,100 @@ func getMidValue(media *sdp.MediaDescription) string {
 	return ""
 }
 
+// mediaSectionHasRTX returns true if the media section offers the rtx codec.
+func mediaSectionHasRTX(media *sdp.MediaDescription) bool {
+	for _, attr := range media.Attributes {
+		if attr.Key != "rtpmap" {
+			continue
+		}
+
+		// a=rtpmap:97 rtx/90000
+		if _, encoding, ok := strings.Cut(attr.Value, " "); ok && strings.HasPrefix(strings.ToLower(encoding), "rtx/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unchangedMediaSections returns the mids of the media sections in current that
+// are identical to the media section with the same mid in previous. Candidates
+// are ignored, trickling them doesn't change what has been negotiated.
+func unchangedMediaSections(previous, current *sdp.SessionDescription) map[string]struct{} {
+	unchanged := map[string]struct{}{}
+	if previous == nil || current == nil {
+		return unchanged
+	}
+
+	previousByMid := make(map[string]*sdp.MediaDescription, len(previous.MediaDescriptions))
+	for _, media := range previous.MediaDescriptions {
+		if mid := getMidValue(media); mid != "" {
+			previousByMid[mid] = media
+		}
+	}
+
+	for _, media := range current.MediaDescriptions {
+		mid := getMidValue(media)
+		if previousMedia, ok := previousByMid[mid]; ok && mediaSectionsEqual(previousMedia, media) {
+			unchanged[mid] = struct{}{}
+		}
+	}
+
+	return unchanged
+}
+
+func mediaSectionsEqual(a, b *sdp.MediaDescription) bool { //nolint:cyclop
+	if a.MediaName.Media != b.MediaName.Media || a.MediaName.Port.Value != b.MediaName.Port.Value ||
+		!stringSlicesEqual(a.MediaName.Protos, b.MediaName.Protos) ||
+		!stringSlicesEqual(a.MediaName.Formats, b.MediaName.Formats) ||
+		len(a.Bandwidth) != len(b.Bandwidth) {
+		return false
+	}
+
+	for i := range a.Bandwidth {
+		if a.Bandwidth[i] != b.Bandwidth[i] {
+			return false
+		}
+	}
+
+	isNegotiated := func(attr sdp.Attribute) bool {
+		return attr.Key != "candidate" && attr.Key != "end-of-candidates"
+	}
+
+	i, j := 0, 0
+	for {
+		for i < len(a.Attributes) && !isNegotiated(a.Attributes[i]) {
+			i++
+		}
+		for j < len(b.Attributes) && !isNegotiated(b.Attributes[j]) {
+			j++
+		}
+
+		switch {
+		case i == len(a.Attributes) || j == len(b.Attributes):
+			return i == len(a.Attributes) && j == len(b.Attributes)
+		case a.Attributes[i] != b.Attributes[j]:
+			return false
+		}
+		i++
+		j++
+	}
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
 // SessionDescription contains a MediaSection with Multiple SSRCs, it is Plan-B.
 func descriptionIsPlanB(desc *SessionDescription, log logging.LeveledLogger) bool {
 	if desc == nil || desc.parsed == nil {
@@ -801,6 +1035,41 @@ func getPeerDirection(media *sdp.MediaDescription) RTPTransceiverDirection {
 	return RTPTransceiverDirectionUnknown
 }
 
+// setBundleOnly marks the media sections of the BUNDLE group, but the first,
+// bundle-only with a zero port (RFC 8843 Section 6) as browsers do in the
+// initial offer with the max-bundle policy. A remote peer that doesn't
+// support BUNDLE rejects them instead of negotiating separate transports.
+func setBundleOnly(desc *sdp.SessionDescription) {
+	groupAttribute, _ := desc.Attribute(sdp.AttrKeyGroup)
+	bundleIDs := strings.Fields(groupAttribute)
+	if len(bundleIDs) < 3 || bundleIDs[0] != "BUNDLE" {
+		return
+	}
+
+	bundleOnly := map[string]struct{}{}
+	for _, mid := range bundleIDs[2:] {
+		bundleOnly[mid] = struct{}{}
+	}
+
+	for _, media := range desc.MediaDescriptions {
+		if _, ok := bundleOnly[getMidValue(media)]; ok {
+			media.MediaName.Port = sdp.RangedPort{Value: 0}
+			media.WithPropertyAttribute(sdpAttributeBundleOnly)
+		}
+	}
+}
+
+// isMediaSectionRejected returns true if the media section has a zero port
+// without being bundle-only.
+func isMediaSectionRejected(media *sdp.MediaDescription) bool {
+	if media.MediaName.Port.Value != 0 {
+		return false
+	}
+	_, bundleOnly := media.Attribute(sdpAttributeBundleOnly)
+
+	return !bundleOnly
+}
+
 func extractBundleID(desc *sdp.SessionDescription) string {
 	groupAttribute, _ := desc.Attribute(sdp.AttrKeyGroup)
 
@@ -1001,6 +1270,27 @@ func haveApplicationMediaSection(desc *sdp.SessionDescription) bool {
 	return false
 }
 
+// getMaxMessageSize returns the a=max-message-size of the application media
+// section of desc, or the default of a peer that doesn't advertise one. Zero
+// means the remote peer accepts messages of any size.
+func getMaxMessageSize(desc *sdp.SessionDescription) uint32 {
+	for _, mediaDescr := range desc.MediaDescriptions {
+		if mediaDescr.MediaName.Media != mediaSectionApplication {
+			continue
+		}
+
+		if raw, ok := mediaDescr.Attribute(sdpAttributeMaxMessageSize); ok {
+			if size, err := strconv.ParseUint(raw, 10, 32); err == nil {
+				return uint32(size)
+			}
+		}
+
+		break
+	}
+
+	return sctpDefaultMaxMessageSize
+}
+
 func getByMid(searchMid string, desc *SessionDescription) *sdp.MediaDescription {
 	for _, m := range desc.parsed.MediaDescriptions {
 		if mid, ok := m.Attribute(sdp.AttrKeyMID); ok && mid == searchMid {
@@ -10,6 +10,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -515,6 +516,52 @@ func TestTrackDetailsFromSDP(t *testing.T) {
 		assert.Equal(t, SSRC(4000), *tracks[0].repairSsrc)
 		assert.Equal(t, SSRC(6000), *tracks[1].repairSsrc)
 	})
+
+	t.Run("multiple msid", func(t *testing.T) {
+		descr := &sdp.SessionDescription{
+			MediaDescriptions: []*sdp.MediaDescription{
+				{
+					MediaName: sdp.MediaName{
+						Media: "video",
+					},
+					Attributes: []sdp.Attribute{
+						{Key: "mid", Value: "0"},
+						{Key: "sendonly"},
+						{Key: "ssrc", Value: "1000"},
+						{Key: "msid", Value: "stream_a video_trk_id"},
+						{Key: "msid", Value: "stream_b video_trk_id"},
+					},
+				},
+				{
+					MediaName: sdp.MediaName{
+						Media: "audio",
+					},
+					Attributes: []sdp.Attribute{
+						{Key: "mid", Value: "1"},
+						{Key: "sendonly"},
+						{Key: "msid", Value: "- audio_trk_id"},
+						{Key: "ssrc", Value: "2000"},
+					},
+				},
+				{
+					MediaName: sdp.MediaName{
+						Media: "video",
+					},
+					Attributes: []sdp.Attribute{
+						{Key: "mid", Value: "2"},
+						{Key: "sendonly"},
+						{Key: "ssrc", Value: "3000 msid:planb_stream planb_trk_id"},
+					},
+				},
+			},
+		}
+
+		tracks := trackDetailsFromSDP(nil, descr)
+		assert.Equal(t, 3, len(tracks))
+		assert.Equal(t, []string{"stream_a", "stream_b"}, trackDetailsForSSRC(tracks, 1000).streamIDs)
+		assert.Empty(t, trackDetailsForSSRC(tracks, 2000).streamIDs)
+		assert.Equal(t, []string{"planb_stream"}, trackDetailsForSSRC(tracks, 3000).streamIDs)
+	})
 }
 
 func TestHaveApplicationMediaSection(t *testing.T) {
@@ -1287,3 +1334,158 @@ a=sendrecv
 		})
 	}
 }
+
+func TestSDPAttributeValues(t *testing.T) {
+	assert.Equal(t, "FID 1234 5678", ssrcGroup("FID", 1234, 5678))
+	assert.Equal(t, "FEC-FR 1 4294967295", ssrcGroup("FEC-FR", 1, 4294967295))
+	assert.Equal(t, "96 nack pli", rtcpFeedbackValue(96, RTCPFeedback{Type: "nack", Parameter: "pli"}))
+	assert.Equal(t, "96 nack ", rtcpFeedbackValue(96, RTCPFeedback{Type: "nack"}))
+
+	first, err := parseExtMapURI(sdp.SDESMidURI)
+	assert.NoError(t, err)
+	second, err := parseExtMapURI(sdp.SDESMidURI)
+	assert.NoError(t, err)
+	assert.Equal(t, sdp.SDESMidURI, first.String())
+	assert.Equal(t, first, second)
+
+	// Callers don't share the cached URL
+	first.Path = "modified"
+	third, err := parseExtMapURI(sdp.SDESMidURI)
+	assert.NoError(t, err)
+	assert.Equal(t, sdp.SDESMidURI, third.String())
+	assert.NotSame(t, second, third)
+
+	// Unknown URIs are parsed but not cached
+	unknown, err := parseExtMapURI("urn:example:unknown")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:example:unknown", unknown.String())
+	_, cached := extMapURIs["urn:example:unknown"]
+	assert.False(t, cached)
+}
+
+func BenchmarkCreateOffer16(b *testing.B)  { benchmarkCreateOffer(b, 16) }
+func BenchmarkCreateOffer128(b *testing.B) { benchmarkCreateOffer(b, 128) }
+
+func benchmarkCreateOffer(b *testing.B, numTracks int) {
+	b.Helper()
+
+	peerConnection, err := NewPeerConnection(Configuration{})
+	assert.NoError(b, err)
+
+	for i := 0; i < numTracks; i++ {
+		track, err := NewTrackLocalStaticSample(
+			RTPCodecCapability{MimeType: MimeTypeVP8}, "video-"+strconv.Itoa(i), "stream",
+		)
+		assert.NoError(b, err)
+
+		_, err = peerConnection.AddTrack(track)
+		assert.NoError(b, err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := peerConnection.CreateOffer(nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.StopTimer()
+	assert.NoError(b, peerConnection.Close())
+}
+
+func TestUnchangedMediaSections(t *testing.T) {
+	parse := func(raw string) *sdp.SessionDescription {
+		parsed := &sdp.SessionDescription{}
+		assert.NoError(t, parsed.UnmarshalString(raw))
+
+		return parsed
+	}
+
+	const header = "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n"
+	const audio = "m=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=mid:0\r\na=sendrecv\r\na=ssrc:1 cname:a\r\n"
+	const video = "m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:1\r\na=sendrecv\r\na=ssrc:2 cname:v\r\n"
+
+	previous := parse(header + audio + video)
+
+	assert.Empty(t, unchangedMediaSections(nil, previous))
+	assert.Equal(t, map[string]struct{}{"0": {}, "1": {}}, unchangedMediaSections(previous, previous))
+
+	// Candidates don't count as a change
+	withCandidates := parse(header +
+		"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=mid:0\r\na=sendrecv\r\n" +
+		"a=candidate:1 1 udp 2130706431 10.0.0.1 5000 typ host\r\na=end-of-candidates\r\na=ssrc:1 cname:a\r\n" +
+		video)
+	assert.Equal(t, map[string]struct{}{"0": {}, "1": {}}, unchangedMediaSections(previous, withCandidates))
+
+	// Direction and SSRC changes are detected, new sections are never unchanged
+	changed := parse(header +
+		"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\na=mid:0\r\na=recvonly\r\na=ssrc:1 cname:a\r\n" +
+		"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:1\r\na=sendrecv\r\na=ssrc:3 cname:v\r\n" +
+		"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:2\r\na=sendrecv\r\n")
+	assert.Empty(t, unchangedMediaSections(previous, changed))
+}
+
+func TestMediaSectionHasRTX(t *testing.T) {
+	media := &sdp.MediaDescription{}
+	media = media.WithValueAttribute("rtpmap", "96 VP8/90000")
+	assert.False(t, mediaSectionHasRTX(media))
+
+	media = media.WithValueAttribute("rtpmap", "97 RTX/90000").WithValueAttribute("fmtp", "97 apt=96")
+	assert.True(t, mediaSectionHasRTX(media))
+}
+
+func TestGetMaxMessageSize(t *testing.T) {
+	application := func(attributes ...sdp.Attribute) *sdp.MediaDescription {
+		return &sdp.MediaDescription{
+			MediaName:  sdp.MediaName{Media: mediaSectionApplication},
+			Attributes: attributes,
+		}
+	}
+
+	assert.Equal(t, uint32(sctpDefaultMaxMessageSize), getMaxMessageSize(&sdp.SessionDescription{}))
+	assert.Equal(t, uint32(sctpDefaultMaxMessageSize), getMaxMessageSize(
+		(&sdp.SessionDescription{}).WithMedia(application()),
+	))
+	assert.Equal(t, uint32(1024), getMaxMessageSize((&sdp.SessionDescription{}).WithMedia(
+		application(sdp.NewAttribute(sdpAttributeMaxMessageSize, "1024")),
+	)))
+	assert.Equal(t, uint32(0), getMaxMessageSize((&sdp.SessionDescription{}).WithMedia(
+		application(sdp.NewAttribute(sdpAttributeMaxMessageSize, "0")),
+	)))
+	assert.Equal(t, uint32(sctpDefaultMaxMessageSize), getMaxMessageSize((&sdp.SessionDescription{}).WithMedia(
+		application(sdp.NewAttribute(sdpAttributeMaxMessageSize, "invalid")),
+	)))
+}
+
+func TestSetBundleOnly(t *testing.T) {
+	media := func(mid string) *sdp.MediaDescription {
+		return (&sdp.MediaDescription{
+			MediaName: sdp.MediaName{Media: "audio", Port: sdp.RangedPort{Value: 9}},
+		}).WithValueAttribute(sdp.AttrKeyMID, mid)
+	}
+
+	desc := (&sdp.SessionDescription{}).
+		WithMedia(media("0")).
+		WithMedia(media("1")).
+		WithMedia(media("2")).
+		WithValueAttribute(sdp.AttrKeyGroup, "BUNDLE 0 1")
+	setBundleOnly(desc)
+
+	// The first media section carries the transport, the unbundled one is untouched
+	assert.Equal(t, 9, desc.MediaDescriptions[0].MediaName.Port.Value)
+	assert.False(t, isMediaSectionRejected(desc.MediaDescriptions[0]))
+	_, bundleOnly := desc.MediaDescriptions[0].Attribute(sdpAttributeBundleOnly)
+	assert.False(t, bundleOnly)
+
+	assert.Equal(t, 0, desc.MediaDescriptions[1].MediaName.Port.Value)
+	assert.False(t, isMediaSectionRejected(desc.MediaDescriptions[1]))
+	_, bundleOnly = desc.MediaDescriptions[1].Attribute(sdpAttributeBundleOnly)
+	assert.True(t, bundleOnly)
+
+	assert.Equal(t, 9, desc.MediaDescriptions[2].MediaName.Port.Value)
+
+	desc.MediaDescriptions[2].MediaName.Port = sdp.RangedPort{Value: 0}
+	assert.True(t, isMediaSectionRejected(desc.MediaDescriptions[2]))
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/internal/keyframe"
+)
+
+// TrackLocalBufferedWriter is implemented by the TrackLocalWriter an RTPSender
+// binds a TrackLocal with. Writers can use it to back off when the transport is
+// congested instead of adding latency.
+type TrackLocalBufferedWriter interface {
+	TrackLocalWriter
+
+	// BufferedAmount is the number of bytes waiting to be sent: the packets
+	// written to the RTPSender held back by its pacers, see
+	// SettingEngine.SetPacingBitrate, and the writes of all RTPSenders,
+	// retransmissions and RTCP of the transport blocked on the network.
+	BufferedAmount() uint64
+}
+
+// sendQueue counts the bytes of the writes to the transport that haven't
+// returned yet. Writes block when the socket buffer is full.
+type sendQueue struct {
+	bytes int64
+}
+
+func (q *sendQueue) bufferedAmount() uint64 {
+	if q == nil {
+		return 0
+	}
+
+	return uint64(atomic.LoadInt64(&q.bytes)) //nolint:gosec // G115, never negative
+}
+
+// sendQueueConn counts the bytes waiting in Write.
+type sendQueueConn struct {
+	net.Conn
+	queue *sendQueue
+}
+
+func (c *sendQueueConn) Write(b []byte) (int, error) {
+	atomic.AddInt64(&c.queue.bytes, int64(len(b)))
+	defer atomic.AddInt64(&c.queue.bytes, -int64(len(b)))
+
+	return c.Conn.Write(b)
+}
+
+// frameDropper drops the video frames written to a binding while more than
+// threshold bytes wait to be sent. The decision is made at the first packet
+// of a frame. The frames following a dropped one can't be decoded, so once
+// dropping starts everything is dropped until a keyframe is written after
+// the queue drained. The sequence numbers are kept, the remote sees the
+// dropped packets as lost and requests a keyframe with PLI.
+type frameDropper struct {
+	mu sync.Mutex
+
+	threshold uint64
+	mimeType  string
+
+	started   bool
+	timestamp uint32
+	dropping  bool
+}
+
+// drop returns true if the packet is dropped.
+func (f *frameDropper) drop(packet *rtp.Packet, writer TrackLocalWriter) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.started || packet.Timestamp != f.timestamp {
+		f.started, f.timestamp = true, packet.Timestamp
+
+		congested := false
+		if bufferedWriter, ok := writer.(TrackLocalBufferedWriter); ok {
+			congested = bufferedWriter.BufferedAmount() > f.threshold
+		}
+
+		switch {
+		case congested:
+			f.dropping = true
+		case f.dropping:
+			f.dropping = !keyframe.IsStart(f.mimeType, packet.Payload)
+		}
+	}
+
+	return f.dropping
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+type bufferedTestWriter struct {
+	bufferedAmount uint64
+	written        []uint16
+}
+
+func (w *bufferedTestWriter) WriteRTP(header *rtp.Header, _ []byte) (int, error) {
+	w.written = append(w.written, header.SequenceNumber)
+
+	return 0, nil
+}
+
+func (w *bufferedTestWriter) Write([]byte) (int, error) {
+	return 0, nil
+}
+
+func (w *bufferedTestWriter) BufferedAmount() uint64 {
+	return atomic.LoadUint64(&w.bufferedAmount)
+}
+
+func TestSendQueueConn(t *testing.T) {
+	local, remote := net.Pipe()
+	defer func() {
+		assert.NoError(t, local.Close())
+		assert.NoError(t, remote.Close())
+	}()
+
+	queue := &sendQueue{}
+	conn := &sendQueueConn{Conn: local, queue: queue}
+
+	written := make(chan struct{})
+	go func() {
+		_, err := conn.Write(make([]byte, 10))
+		assert.NoError(t, err)
+		close(written)
+	}()
+
+	// The write blocks until the remote reads it
+	assert.Eventually(t, func() bool { return queue.bufferedAmount() == 10 }, time.Second, time.Millisecond)
+
+	_, err := remote.Read(make([]byte, 10))
+	assert.NoError(t, err)
+	<-written
+	assert.Equal(t, uint64(0), queue.bufferedAmount())
+}
+
+func TestTrackLocalStaticRTP_FrameDrop(t *testing.T) {
+	track, err := NewTrackLocalStaticRTP(
+		RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithFrameDropThreshold(1000),
+	)
+	assert.NoError(t, err)
+
+	writer := &bufferedTestWriter{}
+	_, err = track.Bind(&baseTrackLocalContext{
+		id:          "binding",
+		params:      RTPParameters{Codecs: []RTPCodecParameters{{RTPCodecCapability: track.Codec(), PayloadType: 96}}},
+		ssrc:        1,
+		writeStream: writer,
+	})
+	assert.NoError(t, err)
+
+	keyframe, interframe := []byte{0x10, 0x00, 0x9d, 0x01}, []byte{0x10, 0x01, 0x00, 0x00}
+	write := func(sequenceNumber uint16, timestamp uint32, payload []byte) {
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber, Timestamp: timestamp},
+			Payload: payload,
+		}))
+	}
+
+	write(10, 1, interframe)
+	// The queue filling up in the middle of a frame doesn't cut it
+	atomic.StoreUint64(&writer.bufferedAmount, 2000)
+	write(11, 1, interframe)
+
+	// The next frame is dropped whole
+	write(12, 2, interframe)
+	write(13, 2, interframe)
+
+	// Once the queue drained frames are still dropped until a keyframe
+	atomic.StoreUint64(&writer.bufferedAmount, 0)
+	write(14, 3, interframe)
+	write(15, 4, keyframe)
+	write(16, 4, interframe)
+
+	// The sequence numbers are kept, the receiver sees the gap
+	assert.Equal(t, []uint16{10, 11, 15, 16}, writer.written)
+}
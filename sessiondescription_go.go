@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+
+	"github.com/pion/sdp/v3"
+)
+
+// Codecs returns the codecs the media sections of the given kind offer, in
+// the order of preference of the description. Codecs shared by bundled
+// media sections are only returned once, rejected media sections are
+// skipped. It can be used on a remote description to decide which encoder
+// or file to use before adding tracks.
+func (sd *SessionDescription) Codecs(kind RTPCodecType) ([]RTPCodecParameters, error) {
+	mediaSections, err := sd.mediaSectionsOfKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	codecs := []RTPCodecParameters{}
+	for _, media := range mediaSections {
+		mediaCodecs, err := codecsFromMediaDescription(media)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, codec := range mediaCodecs {
+			if findCodecByPayload(codecs, codec.PayloadType) == nil {
+				codecs = append(codecs, codec)
+			}
+		}
+	}
+
+	return codecs, nil
+}
+
+// HasCodec returns true if a media section of the codec's kind offers a
+// codec with the given MimeType, the comparison is case-insensitive.
+func (sd *SessionDescription) HasCodec(mimeType string) (bool, error) {
+	kind := RTPCodecTypeVideo
+	if strings.HasPrefix(strings.ToLower(mimeType), RTPCodecTypeAudio.String()+"/") {
+		kind = RTPCodecTypeAudio
+	}
+
+	codecs, err := sd.Codecs(kind)
+	if err != nil {
+		return false, err
+	}
+
+	for _, codec := range codecs {
+		if strings.EqualFold(codec.MimeType, mimeType) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// HeaderExtensions returns the RTP header extensions the media sections of
+// the given kind offer, every URI is only returned once.
+func (sd *SessionDescription) HeaderExtensions(kind RTPCodecType) ([]RTPHeaderExtensionParameter, error) {
+	mediaSections, err := sd.mediaSectionsOfKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	extensions := []RTPHeaderExtensionParameter{}
+	seen := map[string]struct{}{}
+	for _, media := range mediaSections {
+		for _, attr := range media.Attributes {
+			if attr.Key != sdp.AttrKeyExtMap {
+				continue
+			}
+
+			extMap := sdp.ExtMap{}
+			if err := extMap.Unmarshal(attr.String()); err != nil {
+				return nil, err
+			}
+
+			uri := extMap.URI.String()
+			if _, ok := seen[uri]; ok {
+				continue
+			}
+			seen[uri] = struct{}{}
+
+			extensions = append(extensions, RTPHeaderExtensionParameter{URI: uri, ID: extMap.Value})
+		}
+	}
+
+	return extensions, nil
+}
+
+// mediaSectionsOfKind returns the media sections of the given kind that
+// weren't rejected, bundle-only sections have a zero port too.
+func (sd *SessionDescription) mediaSectionsOfKind(kind RTPCodecType) ([]*sdp.MediaDescription, error) {
+	parsed := sd.parsed
+	if parsed == nil {
+		parsed = &sdp.SessionDescription{}
+		if err := parsed.UnmarshalString(sd.SDP); err != nil {
+			return nil, err
+		}
+	}
+
+	mediaSections := []*sdp.MediaDescription{}
+	for _, media := range parsed.MediaDescriptions {
+		if media.MediaName.Media != kind.String() {
+			continue
+		}
+
+		if isMediaSectionRejected(media) {
+			continue
+		}
+		mediaSections = append(mediaSections, media)
+	}
+
+	return mediaSections, nil
+}
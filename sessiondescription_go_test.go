@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionDescription_Codecs(t *testing.T) {
+	const remoteSDP = `v=0
+o=- 4596489990601351948 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+a=group:BUNDLE 0 1 2
+m=video 9 UDP/TLS/RTP/SAVPF 96 97 102
+c=IN IP4 0.0.0.0
+a=mid:0
+a=extmap:4 urn:ietf:params:rtp-hdrext:sdes:mid
+a=rtpmap:96 VP8/90000
+a=rtcp-fb:96 nack
+a=rtpmap:97 rtx/90000
+a=fmtp:97 apt=96
+a=rtpmap:102 H264/90000
+a=fmtp:102 level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f
+m=video 0 UDP/TLS/RTP/SAVPF 96 98
+c=IN IP4 0.0.0.0
+a=mid:1
+a=bundle-only
+a=extmap:4 urn:ietf:params:rtp-hdrext:sdes:mid
+a=extmap:5 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time
+a=rtpmap:96 VP8/90000
+a=rtpmap:98 VP9/90000
+m=audio 0 UDP/TLS/RTP/SAVPF 111
+c=IN IP4 0.0.0.0
+a=mid:2
+a=rtpmap:111 opus/48000/2
+`
+
+	desc := SessionDescription{Type: SDPTypeOffer, SDP: remoteSDP}
+
+	codecs, err := desc.Codecs(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+
+	mimeTypes := []string{}
+	for _, codec := range codecs {
+		mimeTypes = append(mimeTypes, codec.MimeType)
+	}
+	assert.Equal(t, []string{"video/VP8", "video/rtx", "video/H264", "video/VP9"}, mimeTypes)
+	assert.Equal(t, "apt=96", codecs[1].SDPFmtpLine)
+	assert.Equal(t, []RTCPFeedback{{Type: "nack"}}, codecs[0].RTCPFeedback)
+
+	hasH264, err := desc.HasCodec(MimeTypeH264)
+	assert.NoError(t, err)
+	assert.True(t, hasH264)
+
+	// The audio section is rejected
+	audioCodecs, err := desc.Codecs(RTPCodecTypeAudio)
+	assert.NoError(t, err)
+	assert.Empty(t, audioCodecs)
+
+	hasOpus, err := desc.HasCodec(MimeTypeOpus)
+	assert.NoError(t, err)
+	assert.False(t, hasOpus)
+
+	extensions, err := desc.HeaderExtensions(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+	assert.Equal(t, []RTPHeaderExtensionParameter{
+		{URI: "urn:ietf:params:rtp-hdrext:sdes:mid", ID: 4},
+		{URI: "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time", ID: 5},
+	}, extensions)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/pion/stun/v3"
 	"github.com/pion/transport/v3"
 	"github.com/pion/transport/v3/packetio"
+	"github.com/pion/transport/v3/stdnet"
 	"golang.org/x/net/proxy"
 )
 
@@ -46,17 +47,20 @@ type SettingEngine struct {
 		ICESTUNGatherTimeout      *time.Duration
 	}
 	candidates struct {
-		ICELite                  bool
-		ICENetworkTypes          []NetworkType
-		InterfaceFilter          func(string) (keep bool)
-		IPFilter                 func(net.IP) (keep bool)
-		NAT1To1IPs               []string
-		NAT1To1IPCandidateType   ICECandidateType
-		MulticastDNSMode         ice.MulticastDNSMode
-		MulticastDNSHostName     string
-		UsernameFragment         string
-		Password                 string
-		IncludeLoopbackCandidate bool
+		ICELite                   bool
+		ICENetworkTypes           []NetworkType
+		InterfaceFilter           func(string) (keep bool)
+		IPFilter                  func(net.IP) (keep bool)
+		NAT1To1IPs                []string
+		NAT1To1IPCandidateType    ICECandidateType
+		MulticastDNSMode          ice.MulticastDNSMode
+		MulticastDNSHostName      string
+		MulticastDNSHostNameReuse bool
+		UsernameFragment          string
+		Password                  string
+		CredentialsGenerator      func() (usernameFragment, password string)
+		CandidateFilter           func(ICECandidate) (keep bool)
+		IncludeLoopbackCandidate  bool
 	}
 	replayProtection struct {
 		DTLS  *uint
@@ -78,15 +82,28 @@ type SettingEngine struct {
 		clientHelloMessageHook        func(handshake.MessageClientHello) handshake.Message
 		serverHelloMessageHook        func(handshake.MessageServerHello) handshake.Message
 		certificateRequestMessageHook func(handshake.MessageCertificateRequest) handshake.Message
+		handshakeTimeout              time.Duration
+		mtu                           int
+	}
+	signalingLimits struct {
+		maxRemoteDescriptionSize int
+		maxMediaSections         int
+		maxRemoteCandidates      int
+		maxCandidatesPerSecond   int
 	}
 	sctp struct {
 		maxReceiveBufferSize uint32
 		enableZeroChecksum   bool
 		rtoMax               time.Duration
+		minCwnd              uint32
+		fastRtxWnd           uint32
+		cwndCAStep           uint32
+		maxMessageSize       uint32
 	}
 	sdpMediaLevelFingerprints                 bool
 	answeringDTLSRole                         DTLSRole
 	disableCertificateFingerprintVerification bool
+	tickerPool                                *TickerPool
 	disableSRTPReplayProtection               bool
 	disableSRTCPReplayProtection              bool
 	net                                       transport.Net
@@ -95,15 +112,44 @@ type SettingEngine struct {
 	iceTCPMux                                 ice.TCPMux
 	iceUDPMux                                 ice.UDPMux
 	iceProxyDialer                            proxy.Dialer
+	iceSOCKS5Proxy                            *iceSOCKS5Proxy
 	iceDisableActiveTCP                       bool
 	iceBindingRequestHandler                  func(m *stun.Message, local, remote ice.Candidate, pair *ice.CandidatePair) bool //nolint:lll
 	disableMediaEngineCopy                    bool
 	srtpProtectionProfiles                    []dtls.SRTPProtectionProfile
+	srtpProvider                              SRTPProvider
 	receiveMTU                                uint
 	iceMaxBindingRequests                     *uint16
 	fireOnTrackBeforeFirstRTP                 bool
 	disableCloseByDTLS                        bool
 	dataChannelBlockWrite                     bool
+	featureFlags                              FeatureFlags
+	relayBudget                               uint64
+	iceAutoRestart                            struct {
+		initialBackoff time.Duration
+		maxBackoff     time.Duration
+	}
+	srtpKeyUsageLimits struct {
+		enabled      bool
+		srtpPackets  uint64
+		srtcpPackets uint64
+	}
+	identityValidator          IdentityValidator
+	metadataPacketConn         *MetadataPacketConn
+	dataChannelLatencySampling time.Duration
+	arrivalTimes               bool
+	trackRemoteTimeouts        struct {
+		mute, end time.Duration
+	}
+	iceServerProvider    *ICEServerProvider
+	pacingBitrate        int
+	performanceMetrics   *PerformanceMetrics
+	incomingSSRCRoutines struct {
+		count  int
+		shared bool
+	}
+	enforceRemoteBandwidth bool
+	quirks                 []Quirk
 }
 
 // getReceiveMTU returns the configured MTU. If SettingEngine's MTU is configured to 0 it returns the default.
@@ -128,8 +174,103 @@ func (e *SettingEngine) EnableDataChannelBlockWrite(nonblockWrite bool) {
 	e.dataChannelBlockWrite = nonblockWrite
 }
 
+// SetDataChannelLatencySampling enables measuring how long the messages sent
+// with DataChannel.Send and SendText wait until the remote peer acknowledges
+// them, reported in the DataChannelStats. While messages are in flight the
+// buffered amount of the channel is polled every interval, which bounds the
+// precision of the measurement. The SCTP stack doesn't expose when a message
+// is first transmitted, only when it is acknowledged. Writes to detached
+// channels aren't measured. Leave this 0 to disable the measurement.
+func (e *SettingEngine) SetDataChannelLatencySampling(interval time.Duration) {
+	e.dataChannelLatencySampling = interval
+}
+
+// SetICEServerProvider sets the ICEServerProvider consulted by the new
+// PeerConnections of the API whose Configuration has no ICEServers.
+func (e *SettingEngine) SetICEServerProvider(provider *ICEServerProvider) {
+	e.iceServerProvider = provider
+}
+
+// SetPacingBitrate enables pacing the RTP packets written to the RTPSenders of
+// a PeerConnection at bitrate bits per second, so the packets of a frame
+// written with WriteSample aren't sent in a burst. Video packets are queued
+// per RTPSender and sent by their own goroutine, writes don't wait for them.
+// With LatencyModeRealtime the packets that wait too long are dropped. Audio
+// packets aren't queued, they are sent as they are written and count
+// against the bitrate ahead of the video. If the PeerConnection has a
+// BandwidthEstimator, see ConfigureCongestionController, the pacing bitrate
+// follows its target bitrate instead. Leave this 0 to send the packets as
+// they are written.
+func (e *SettingEngine) SetPacingBitrate(bitrate int) {
+	e.pacingBitrate = bitrate
+}
+
+// EnableRemoteBandwidthEnforcement paces the RTP packets written to each
+// RTPSender to the bandwidth the remote peer advertised for its media section
+// with b=TIAS or b=AS, see RTPTransceiver.RemoteBandwidth, so publishers
+// respect the caps of an SFU even if their encoder overshoots. Video packets
+// are queued like with SetPacingBitrate, audio isn't paced. It is disabled by
+// default.
+func (e *SettingEngine) EnableRemoteBandwidthEnforcement(isEnabled bool) {
+	e.enforceRemoteBandwidth = isEnabled
+}
+
+// SetIncomingSSRCRoutines sets how many goroutines at most read the first
+// packets of the incoming SSRCs that aren't declared in the SDP, such as
+// simulcast streams, to dispatch them to their tracks. SSRCs arriving while
+// all are busy are discarded. By default each PeerConnection has 25, if
+// shared is true the PeerConnections of the API share count goroutines
+// instead, which bounds the load of many PeerConnections on small devices.
+//
+// The packets of a transport are always demultiplexed by a single goroutine
+// for RTP and one for RTCP, so they are dispatched in order.
+func (e *SettingEngine) SetIncomingSSRCRoutines(count int, shared bool) {
+	e.incomingSSRCRoutines.count = count
+	e.incomingSSRCRoutines.shared = shared
+}
+
+// SetPerformanceMetrics enables timing the RTP read and write paths of the
+// PeerConnections of the API with the histograms of metrics. They are
+// disabled by default.
+func (e *SettingEngine) SetPerformanceMetrics(metrics *PerformanceMetrics) {
+	e.performanceMetrics = metrics
+}
+
+// SetTrackRemoteTimeouts sets how long no RTP may arrive for a TrackRemote
+// before it is muted, see TrackRemote.OnMute, and before it ends with
+// TrackEndReasonTimeout, see TrackRemote.OnEnded. Both are disabled by
+// default, setting one records when every SRTP packet arrives. Set a timeout
+// to 0 to disable it.
+func (e *SettingEngine) SetTrackRemoteTimeouts(mute, end time.Duration) {
+	e.trackRemoteTimeouts.mute = mute
+	e.trackRemoteTimeouts.end = end
+}
+
+func (e *SettingEngine) getTrackRemoteTimeouts() (mute, end time.Duration) {
+	return e.trackRemoteTimeouts.mute, e.trackRemoteTimeouts.end
+}
+
+// EnableArrivalTimes sets AttributeArrivalTime on the received RTP packets
+// to the time they were read from the ICE transport, for the PeerConnections
+// that don't read from a MetadataPacketConn. It is disabled by default.
+func (e *SettingEngine) EnableArrivalTimes(isEnabled bool) {
+	e.arrivalTimes = isEnabled
+}
+
+// recordArrivals returns whether the arrival of every SRTP packet is
+// recorded, for AttributeArrivalTime, the read latency of the
+// PerformanceMetrics or the timeouts of the TrackRemotes.
+func (e *SettingEngine) recordArrivals() bool {
+	mute, end := e.getTrackRemoteTimeouts()
+
+	return e.arrivalTimes || e.performanceMetrics != nil || mute != 0 || end != 0
+}
+
 // SetSRTPProtectionProfiles allows the user to override the default SRTP Protection Profiles
 // The default srtp protection profiles are provided by the function `defaultSrtpProtectionProfiles`.
+// Only the given profiles are offered, so passing a single one forces it and the DTLS
+// handshake fails if the remote doesn't support it. The profile that was negotiated is
+// returned by DTLSTransport.SRTPProtectionProfile.
 func (e *SettingEngine) SetSRTPProtectionProfiles(profiles ...dtls.SRTPProtectionProfile) {
 	e.srtpProtectionProfiles = profiles
 }
@@ -216,6 +357,18 @@ func (e *SettingEngine) SetInterfaceFilter(filter func(string) (keep bool)) {
 	e.candidates.InterfaceFilter = filter
 }
 
+// SetICECandidateFilter sets a function deciding for every local ICE candidate
+// if it is signaled, it receives the whole candidate: type, protocol, related
+// address and the network-id and network-cost extensions. This can be used to
+// drop e.g. the server reflexive candidates of a cellular network or relayed
+// candidates of a VPN with one hook. Filtered candidates aren't passed to
+// OnICECandidate and aren't in local descriptions, pion/ice still sends
+// connectivity checks from them, see ICETransport.SetCandidatePairFilter to
+// never select them.
+func (e *SettingEngine) SetICECandidateFilter(filter func(ICECandidate) (keep bool)) {
+	e.candidates.CandidateFilter = filter
+}
+
 // SetIPFilter sets the filtering functions when gathering ICE candidates
 // This can be used to exclude certain ip from ICE. Which may be
 // useful if you know a certain ip will never succeed, or if you wish to reduce
@@ -302,6 +455,17 @@ func (e *SettingEngine) SetMulticastDNSHostName(hostName string) {
 	e.candidates.MulticastDNSHostName = hostName
 }
 
+// SetMulticastDNSHostNameReuse makes all PeerConnections of an API share one
+// randomly generated mDNS HostName instead of generating one per PeerConnection.
+// Servers creating many PeerConnections per second otherwise announce and
+// answer queries for a new HostName every time.
+//
+// It only has an effect with MulticastDNSModeQueryAndGather and when no static
+// HostName was set with SetMulticastDNSHostName.
+func (e *SettingEngine) SetMulticastDNSHostNameReuse(reuse bool) {
+	e.candidates.MulticastDNSHostNameReuse = reuse
+}
+
 // SetICECredentials sets a staic uFrag/uPwd to be used by pion/ice
 //
 // This is useful if you want to do signalless WebRTC session,
@@ -311,6 +475,29 @@ func (e *SettingEngine) SetICECredentials(usernameFragment, password string) {
 	e.candidates.Password = password
 }
 
+// SetICECredentialsGenerator sets a function returning the uFrag/uPwd of every
+// ICE agent created by the API, and of every ICE restart. It takes precedence
+// over SetICECredentials.
+//
+// This allows a load balanced SFU to encode the destination of a session in
+// its uFrag and route the STUN binding requests of a client by uFrag before
+// the PeerConnection exists on the server handling it. An empty uFrag or
+// uPwd is generated randomly by pion/ice.
+func (e *SettingEngine) SetICECredentialsGenerator(generator func() (usernameFragment, password string)) {
+	e.candidates.CredentialsGenerator = generator
+}
+
+// SetIdentityValidator sets the function validating the a=identity assertions
+// of remote descriptions with their IdP, see
+// https://www.rfc-editor.org/rfc/rfc8827#section-7. SetRemoteDescription fails
+// if the validator fails, if the assertion doesn't cover all the fingerprints
+// of the description or if it isn't for Configuration.PeerIdentity. When
+// Configuration.PeerIdentity is set, descriptions without assertion fail too.
+// The validated identity is returned by PeerConnection.PeerIdentity.
+func (e *SettingEngine) SetIdentityValidator(validator IdentityValidator) {
+	e.identityValidator = validator
+}
+
 // DisableCertificateFingerprintVerification disables fingerprint verification after DTLS Handshake has finished.
 func (e *SettingEngine) DisableCertificateFingerprintVerification(isDisabled bool) {
 	e.disableCertificateFingerprintVerification = isDisabled
@@ -343,6 +530,29 @@ func (e *SettingEngine) DisableSRTCPReplayProtection(isDisabled bool) {
 	e.disableSRTCPReplayProtection = isDisabled
 }
 
+// EnableSRTPKeyUsageTracking counts the SRTP and SRTCP packets each SSRC sends
+// with the same SRTP master key, see DTLSTransport.OnSRTPKeyUsage. It is
+// disabled by default, counting costs a little on every packet sent.
+func (e *SettingEngine) EnableSRTPKeyUsageTracking(isEnabled bool) {
+	e.srtpKeyUsageLimits.enabled = isEnabled
+}
+
+// SetSRTPKeyUsageLimits sets the number of SRTP and SRTCP packets each SSRC
+// sends with the same SRTP master key, DTLSTransport.OnSRTPKeyUsage is notified
+// when they are approached. Zero keeps the limit of the packet index. The
+// limits only apply once EnableSRTPKeyUsageTracking is set.
+func (e *SettingEngine) SetSRTPKeyUsageLimits(srtpPackets, srtcpPackets uint64) {
+	e.srtpKeyUsageLimits.srtpPackets = srtpPackets
+	e.srtpKeyUsageLimits.srtcpPackets = srtcpPackets
+}
+
+// SetSRTPProvider sets the SRTPProvider creating the SRTP and SRTCP sessions
+// instead of pion/srtp. The replay protection settings are passed to it in the
+// SRTPConfig. Pass nil to use pion/srtp again.
+func (e *SettingEngine) SetSRTPProvider(provider SRTPProvider) {
+	e.srtpProvider = provider
+}
+
 // SetSDPMediaLevelFingerprints configures the logic for DTLS Fingerprint insertion
 // If true, fingerprints will be inserted in the sdp at the fingerprint
 // level, instead of the session level. This helps with compatibility with
@@ -352,7 +562,8 @@ func (e *SettingEngine) SetSDPMediaLevelFingerprints(sdpMediaLevelFingerprints b
 }
 
 // SetICETCPMux enables ICE-TCP when set to a non-nil value. Make sure that
-// NetworkTypeTCP4 or NetworkTypeTCP6 is enabled as well.
+// NetworkTypeTCP4 or NetworkTypeTCP6 is enabled as well. The TCPMux can be
+// shared by all the PeerConnections, they are then reached on one TCP port.
 func (e *SettingEngine) SetICETCPMux(tcpMux ice.TCPMux) {
 	e.iceTCPMux = tcpMux
 }
@@ -364,11 +575,72 @@ func (e *SettingEngine) SetICEUDPMux(udpMux ice.UDPMux) {
 	e.iceUDPMux = udpMux
 }
 
+// SetMetadataPacketConn sets the MetadataPacketConn the ICEUDPMux reads from,
+// so the kernel receive timestamps and ECN codepoints it records are attached
+// to the RTP packets as AttributeArrivalTime and AttributeECN.
+func (e *SettingEngine) SetMetadataPacketConn(conn *MetadataPacketConn) {
+	e.metadataPacketConn = conn
+}
+
 // SetICEProxyDialer sets the proxy dialer interface based on golang.org/x/net/proxy.
+// It is only used for TURN over TCP and TLS, see SetICESOCKS5Proxy to proxy UDP too.
 func (e *SettingEngine) SetICEProxyDialer(d proxy.Dialer) {
 	e.iceProxyDialer = d
 }
 
+type iceSOCKS5Proxy struct {
+	address string
+	auth    *proxy.Auth
+}
+
+// SetICESOCKS5Proxy sends all ICE traffic through the SOCKS5 proxy at address.
+// UDP is relayed with UDP ASSOCIATE (RFC 1928), TURN over TCP and TLS is
+// dialed through the proxy with CONNECT, replacing the dialer set with
+// SetICEProxyDialer. auth can be nil if the proxy doesn't require a username
+// and password.
+//
+// Host candidates still carry the local addresses, the remote will see the
+// address of the proxy. Server reflexive and relay candidates are the ones
+// that work through the proxy. MulticastDNS should be disabled.
+func (e *SettingEngine) SetICESOCKS5Proxy(address string, auth *proxy.Auth) error {
+	dialer, err := proxy.SOCKS5("tcp", address, auth, proxy.Direct)
+	if err != nil {
+		return err
+	}
+
+	e.iceProxyDialer = dialer
+	e.iceSOCKS5Proxy = &iceSOCKS5Proxy{address: address, auth: auth}
+
+	return nil
+}
+
+// getICECredentials returns the uFrag/uPwd of a new ICE agent or ICE restart.
+func (e *SettingEngine) getICECredentials() (string, string) {
+	if e.candidates.CredentialsGenerator != nil {
+		return e.candidates.CredentialsGenerator()
+	}
+
+	return e.candidates.UsernameFragment, e.candidates.Password
+}
+
+// getICENet returns the Net passed to pion/ice, UDP is sent through the SOCKS5
+// proxy if one is configured.
+func (e *SettingEngine) getICENet() (transport.Net, error) {
+	if e.iceSOCKS5Proxy == nil {
+		return e.net, nil
+	}
+
+	base := e.net
+	if base == nil {
+		var err error
+		if base, err = stdnet.NewNet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return newSOCKS5Net(base, e.iceSOCKS5Proxy.address, e.iceSOCKS5Proxy.auth), nil
+}
+
 // SetICEMaxBindingRequests sets the maximum amount of binding requests
 // that can be sent on a candidate before it is considered invalid.
 func (e *SettingEngine) SetICEMaxBindingRequests(d uint16) {
@@ -376,6 +648,11 @@ func (e *SettingEngine) SetICEMaxBindingRequests(d uint16) {
 }
 
 // DisableActiveTCP disables using active TCP for ICE. Active TCP is enabled by default.
+//
+// Active TCP candidates are gathered when NetworkTypeTCP4 or NetworkTypeTCP6 is
+// enabled, they dial the passive TCP candidates of the remote. This lets a
+// PeerConnection behind a firewall blocking UDP connect to a browser or SFU
+// listening with ICE-TCP without a TURN server. SSLTCP framing isn't supported.
 func (e *SettingEngine) DisableActiveTCP(isDisabled bool) {
 	e.iceDisableActiveTCP = isDisabled
 }
@@ -428,6 +705,23 @@ func (e *SettingEngine) SetDTLSConnectContextMaker(connectContextMaker func() (c
 	e.dtls.connectContextMaker = connectContextMaker
 }
 
+// SetDTLSHandshakeTimeout sets how long the DTLS handshake may take before
+// the DTLSTransport fails. By default the handshake is only bounded by the
+// ICE connection. With SetDTLSRetransmissionInterval it lets handshakes
+// complete on high latency links, such as satellite ones, by waiting longer
+// for each flight instead of retransmitting it early. It is ignored if a
+// context maker is set with SetDTLSConnectContextMaker.
+func (e *SettingEngine) SetDTLSHandshakeTimeout(timeout time.Duration) {
+	e.dtls.handshakeTimeout = timeout
+}
+
+// SetDTLSMTU sets the size the DTLS handshake messages are fragmented to,
+// it defaults to 1200 bytes. Lower it for links with a small MTU, where the
+// certificate flights would otherwise be dropped.
+func (e *SettingEngine) SetDTLSMTU(mtu int) {
+	e.dtls.mtu = mtu
+}
+
 // SetDTLSExtendedMasterSecret sets the extended master secret type for DTLS.
 func (e *SettingEngine) SetDTLSExtendedMasterSecret(extendedMasterSecret dtls.ExtendedMasterSecretType) {
 	e.dtls.extendedMasterSecret = extendedMasterSecret
@@ -499,6 +793,35 @@ func (e *SettingEngine) SetSCTPRTOMax(rtoMax time.Duration) {
 	e.sctp.rtoMax = rtoMax
 }
 
+// SetSCTPMaxMessageSize sets the largest DataChannel message the SCTPTransport
+// accepts, it is advertised to the remote peer with a=max-message-size and
+// also bounds the messages sent. Leave this 0 for the default of 65536 bytes.
+func (e *SettingEngine) SetSCTPMaxMessageSize(maxMessageSize uint32) {
+	e.sctp.maxMessageSize = maxMessageSize
+}
+
+// SetSCTPMinCwnd sets the minimum congestion window of SCTP in bytes, the
+// congestion window never shrinks below it. Raising it keeps DataChannels
+// sending at a steady rate after losses on long fat networks.
+// Leave this 0 for the default of the SCTP congestion control.
+func (e *SettingEngine) SetSCTPMinCwnd(minCwnd uint32) {
+	e.sctp.minCwnd = minCwnd
+}
+
+// SetSCTPFastRtxWnd sets the congestion window of SCTP in bytes during a fast
+// retransmission. Leave this 0 for the default of the SCTP congestion control.
+func (e *SettingEngine) SetSCTPFastRtxWnd(fastRtxWnd uint32) {
+	e.sctp.fastRtxWnd = fastRtxWnd
+}
+
+// SetSCTPCwndCAStep sets how many bytes the congestion window of SCTP grows by
+// per round trip during congestion avoidance, a larger step recovers the
+// throughput of high bandwidth-delay product paths faster.
+// Leave this 0 for the default of the SCTP congestion control.
+func (e *SettingEngine) SetSCTPCwndCAStep(cwndCAStep uint32) {
+	e.sctp.cwndCAStep = cwndCAStep
+}
+
 // SetICEBindingRequestHandler sets a callback that is fired on a STUN BindingRequest
 // This allows users to do things like
 // - Log incoming Binding Requests for debugging
@@ -519,9 +842,64 @@ func (e *SettingEngine) SetFireOnTrackBeforeFirstRTP(fireOnTrackBeforeFirstRTP b
 	e.fireOnTrackBeforeFirstRTP = fireOnTrackBeforeFirstRTP
 }
 
+// SetICEAutoRestart makes PeerConnections restart ICE on their own when the
+// ICE connection is disconnected or failed. The restart is requested with
+// PeerConnection.RestartICE after initialBackoff, so the application only
+// has to create and signal an offer from OnNegotiationNeeded. If the
+// connection isn't back the backoff doubles up to maxBackoff for the next
+// attempt, it is reset once the connection is back. A zero initialBackoff
+// disables the restarts, which is the default.
+func (e *SettingEngine) SetICEAutoRestart(initialBackoff, maxBackoff time.Duration) {
+	e.iceAutoRestart.initialBackoff = initialBackoff
+	e.iceAutoRestart.maxBackoff = maxBackoff
+}
+
 // DisableCloseByDTLS sets if the connection should be closed when dtls transport is closed.
 // Setting this to true will keep the connection open when dtls transport is closed
 // and relies on the ice failed state to detect the connection is interrupted.
 func (e *SettingEngine) DisableCloseByDTLS(isEnabled bool) {
 	e.disableCloseByDTLS = isEnabled
 }
+
+// SetFeatureFlags sets the experimental behaviors enabled for every PeerConnection
+// created by this API. A PeerConnection may override them with Configuration.FeatureFlags.
+func (e *SettingEngine) SetFeatureFlags(flags FeatureFlags) {
+	e.featureFlags = flags
+}
+
+// SetMaxRemoteDescriptionSize sets the largest SDP, in bytes, SetRemoteDescription
+// accepts. Larger descriptions are rejected with ErrRemoteDescriptionTooLarge
+// before they are parsed. Leave this 0 for no limit.
+func (e *SettingEngine) SetMaxRemoteDescriptionSize(size int) {
+	e.signalingLimits.maxRemoteDescriptionSize = size
+}
+
+// SetMaxMediaSections sets how many media sections a remote description may have.
+// Descriptions with more are rejected with ErrTooManyMediaSections. Leave this 0 for no limit.
+func (e *SettingEngine) SetMaxMediaSections(count int) {
+	e.signalingLimits.maxMediaSections = count
+}
+
+// SetMaxRemoteCandidates sets how many remote candidates a PeerConnection accepts,
+// counting the candidates of the remote description and those added with
+// AddICECandidate since it was set. Once it is reached SetRemoteDescription and
+// AddICECandidate fail with ErrTooManyRemoteCandidates. Leave this 0 for no limit.
+func (e *SettingEngine) SetMaxRemoteCandidates(count int) {
+	e.signalingLimits.maxRemoteCandidates = count
+}
+
+// SetAddICECandidateRateLimit sets how many candidates AddICECandidate accepts
+// per second, calls above the rate fail with ErrAddICECandidateRateLimited.
+// Leave this 0 for no limit.
+func (e *SettingEngine) SetAddICECandidateRateLimit(candidatesPerSecond int) {
+	e.signalingLimits.maxCandidatesPerSecond = candidatesPerSecond
+}
+
+// SetRelayBudget sets how many bytes a PeerConnection may send and receive
+// through a TURN relay before PeerConnection.OnRelayBudgetExceeded fires. The
+// bytes are counted while the selected candidate pair uses a local relay
+// candidate, they are also reported as RelayBytesSent and RelayBytesReceived
+// in the TransportStats. Leave this 0 to disable the callback.
+func (e *SettingEngine) SetRelayBudget(bytes uint64) {
+	e.relayBudget = bytes
+}
@@ -8,7 +8,9 @@ package webrtc
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -158,6 +160,123 @@ func TestSettingEngine_SetICETCP(t *testing.T) {
 	assert.Equal(t, tcpMux, settingEngine.iceTCPMux)
 }
 
+func TestSettingEngine_ActiveTCP(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IP{127, 0, 0, 1}})
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	tcpMux := NewICETCPMux(nil, listener, 8)
+
+	defer func() {
+		_ = tcpMux.Close()
+	}()
+
+	newTCPOnlyAPI := func(tcpMux ice.TCPMux) *API {
+		settingEngine := SettingEngine{}
+		settingEngine.SetNetworkTypes([]NetworkType{NetworkTypeTCP4})
+		settingEngine.SetIncludeLoopbackCandidate(true)
+		settingEngine.SetIPFilter(func(ip net.IP) bool { return ip.IsLoopback() })
+		if tcpMux != nil {
+			settingEngine.SetICETCPMux(tcpMux)
+		}
+
+		return NewAPI(WithSettingEngine(settingEngine))
+	}
+
+	// The offerer can't be reached, it dials the passive candidate of the answerer
+	offerer, err := newTCPOnlyAPI(nil).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	answerer, err := newTCPOnlyAPI(tcpMux).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	_, err = offerer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, offerer, answerer)
+	assert.NoError(t, signalPair(offerer, answerer))
+	connected.Wait()
+
+	pair, err := offerer.SCTP().Transport().ICETransport().GetSelectedCandidatePair()
+	assert.NoError(t, err)
+	assert.Equal(t, ICEProtocolTCP, pair.Local.Protocol)
+	assert.Equal(t, "active", pair.Local.TCPType)
+
+	closePairNow(t, offerer, answerer)
+}
+
+func TestSettingEngine_SharedICETCPMux(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IP{127, 0, 0, 1}})
+	assert.NoError(t, err)
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	tcpMux := NewICETCPMuxWithOptions(nil, listener, ICETCPMuxOptions{
+		ReadBufferSize:       8,
+		FirstStunBindTimeout: 5 * time.Second,
+	})
+
+	defer func() {
+		_ = tcpMux.Close()
+	}()
+
+	newTCPOnlyAPI := func(tcpMux ice.TCPMux) *API {
+		settingEngine := SettingEngine{}
+		settingEngine.SetNetworkTypes([]NetworkType{NetworkTypeTCP4})
+		settingEngine.SetIncludeLoopbackCandidate(true)
+		settingEngine.SetIPFilter(func(ip net.IP) bool { return ip.IsLoopback() })
+		if tcpMux != nil {
+			settingEngine.SetICETCPMux(tcpMux)
+		}
+
+		return NewAPI(WithSettingEngine(settingEngine))
+	}
+
+	listenerAddr, ok := listener.Addr().(*net.TCPAddr)
+	assert.True(t, ok)
+
+	answererAPI := newTCPOnlyAPI(tcpMux)
+	for i := 0; i < 3; i++ {
+		offerer, err := newTCPOnlyAPI(nil).NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+
+		answerer, err := answererAPI.NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+
+		_, err = offerer.CreateDataChannel("data", nil)
+		assert.NoError(t, err)
+
+		connected := untilConnectionState(PeerConnectionStateConnected, offerer, answerer)
+		assert.NoError(t, signalPair(offerer, answerer))
+		connected.Wait()
+
+		// Every answerer is reached on the port of the shared listener
+		pair, err := answerer.SCTP().Transport().ICETransport().GetSelectedCandidatePair()
+		assert.NoError(t, err)
+		assert.Equal(t, "passive", pair.Local.TCPType)
+		assert.Equal(t, listenerAddr.Port, int(pair.Local.Port))
+
+		closePairNow(t, offerer, answerer)
+	}
+}
+
 func TestSettingEngine_SetDisableMediaEngineCopy(t *testing.T) {
 	t.Run("Copy", func(t *testing.T) {
 		mediaEngine := &MediaEngine{}
@@ -273,6 +392,26 @@ func TestSetDTLSHandShakeTimeout(*testing.T) {
 	})
 }
 
+func TestSetDTLSHandshakeTimeoutAndMTU(t *testing.T) {
+	s := SettingEngine{}
+	transport := &DTLSTransport{api: NewAPI(WithSettingEngine(s))}
+	ctx, cancel := transport.handshakeContext()
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+	cancel()
+
+	s.SetDTLSHandshakeTimeout(2 * time.Minute)
+	s.SetDTLSMTU(1000)
+	assert.Equal(t, 1000, s.dtls.mtu)
+
+	transport = &DTLSTransport{api: NewAPI(WithSettingEngine(s))}
+	ctx, cancel = transport.handshakeContext()
+	deadline, hasDeadline := ctx.Deadline()
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), deadline, time.Second)
+	cancel()
+}
+
 func TestSetSCTPMaxReceiverBufferSize(t *testing.T) {
 	s := SettingEngine{}
 	assert.Equal(t, uint32(0), s.sctp.maxReceiveBufferSize)
@@ -291,6 +430,20 @@ func TestSetSCTPRTOMax(t *testing.T) {
 	assert.Equal(t, expSize, s.sctp.rtoMax)
 }
 
+func TestSetSCTPCongestionControl(t *testing.T) {
+	s := SettingEngine{}
+	assert.Equal(t, uint32(0), s.sctp.minCwnd)
+	assert.Equal(t, uint32(0), s.sctp.fastRtxWnd)
+	assert.Equal(t, uint32(0), s.sctp.cwndCAStep)
+
+	s.SetSCTPMinCwnd(64 * 1024)
+	s.SetSCTPFastRtxWnd(32 * 1024)
+	s.SetSCTPCwndCAStep(4 * 1024)
+	assert.Equal(t, uint32(64*1024), s.sctp.minCwnd)
+	assert.Equal(t, uint32(32*1024), s.sctp.fastRtxWnd)
+	assert.Equal(t, uint32(4*1024), s.sctp.cwndCAStep)
+}
+
 func TestSetICEBindingRequestHandler(t *testing.T) {
 	seenICEControlled, seenICEControlledCancel := context.WithCancel(context.Background())
 	seenICEControlling, seenICEControllingCancel := context.WithCancel(context.Background())
@@ -482,3 +635,75 @@ func TestEnableDataChannelBlockWrite(t *testing.T) {
 	assert.ErrorIs(t, err, context.DeadlineExceeded)
 	closePairNow(t, offer, answer)
 }
+
+func TestSetMulticastDNSHostNameReuse(t *testing.T) {
+	settingEngine := SettingEngine{}
+	settingEngine.SetMulticastDNSHostNameReuse(true)
+	assert.Empty(t, NewAPI(WithSettingEngine(settingEngine)).multicastDNSHostName)
+
+	settingEngine.SetICEMulticastDNSMode(ice.MulticastDNSModeQueryAndGather)
+	api := NewAPI(WithSettingEngine(settingEngine))
+	assert.True(t, strings.HasSuffix(api.multicastDNSHostName, ".local"))
+	assert.NotEqual(t, api.multicastDNSHostName, NewAPI(WithSettingEngine(settingEngine)).multicastDNSHostName)
+
+	settingEngine.SetMulticastDNSHostName("static.local")
+	assert.Empty(t, NewAPI(WithSettingEngine(settingEngine)).multicastDNSHostName)
+}
+
+func TestSetICECredentials(t *testing.T) {
+	t.Run("Static", func(t *testing.T) {
+		settingEngine := SettingEngine{}
+		settingEngine.SetICECredentials("staticufrag", "staticpasswordstaticpassword")
+
+		pc, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+
+		params, err := pc.iceGatherer.GetLocalParameters()
+		assert.NoError(t, err)
+		assert.Equal(t, "staticufrag", params.UsernameFragment)
+		assert.Equal(t, "staticpasswordstaticpassword", params.Password)
+
+		assert.NoError(t, pc.Close())
+	})
+
+	t.Run("Generator", func(t *testing.T) {
+		generated := 0
+
+		settingEngine := SettingEngine{}
+		settingEngine.SetICECredentials("staticufrag", "staticpasswordstaticpassword")
+		settingEngine.SetICECredentialsGenerator(func() (string, string) {
+			generated++
+
+			return fmt.Sprintf("backend1-%d", generated), "generatedpasswordgeneratedpassword"
+		})
+		api := NewAPI(WithSettingEngine(settingEngine))
+
+		for i := 1; i <= 2; i++ {
+			pc, err := api.NewPeerConnection(Configuration{})
+			assert.NoError(t, err)
+
+			params, err := pc.iceGatherer.GetLocalParameters()
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("backend1-%d", i), params.UsernameFragment)
+			assert.Equal(t, "generatedpasswordgeneratedpassword", params.Password)
+
+			assert.NoError(t, pc.Close())
+		}
+	})
+}
+
+func TestRecordArrivals(t *testing.T) {
+	settingEngine := SettingEngine{}
+	assert.False(t, settingEngine.recordArrivals())
+
+	settingEngine.SetTrackRemoteTimeouts(time.Second, 0)
+	assert.True(t, settingEngine.recordArrivals())
+
+	settingEngine.SetTrackRemoteTimeouts(0, 0)
+	settingEngine.SetPerformanceMetrics(NewPerformanceMetrics())
+	assert.True(t, settingEngine.recordArrivals())
+
+	settingEngine.SetPerformanceMetrics(nil)
+	settingEngine.EnableArrivalTimes(true)
+	assert.True(t, settingEngine.recordArrivals())
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/sdp/v3"
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
+)
+
+// remoteSignalingLimiter enforces the limits of the SettingEngine on the
+// remote descriptions and candidates a PeerConnection accepts.
+type remoteSignalingLimiter struct {
+	mu sync.Mutex
+
+	candidates       int
+	windowStart      time.Time
+	windowCandidates int
+}
+
+// checkDescriptionSize is run before the description is parsed.
+func (l *remoteSignalingLimiter) checkDescriptionSize(settingEngine *SettingEngine, desc *SessionDescription) error {
+	if limit := settingEngine.signalingLimits.maxRemoteDescriptionSize; limit != 0 && len(desc.SDP) > limit {
+		return &rtcerr.OperationError{Err: ErrRemoteDescriptionTooLarge}
+	}
+
+	return nil
+}
+
+// checkDescription counts the media sections and candidates of a parsed
+// description. A renegotiation repeats the candidates already known, so they
+// only raise the count, it is reset by restartICE.
+func (l *remoteSignalingLimiter) checkDescription(settingEngine *SettingEngine, desc *sdp.SessionDescription) error {
+	limits := settingEngine.signalingLimits
+	if limits.maxMediaSections != 0 && len(desc.MediaDescriptions) > limits.maxMediaSections {
+		return &rtcerr.OperationError{Err: ErrTooManyMediaSections}
+	}
+
+	candidates := 0
+	for _, media := range desc.MediaDescriptions {
+		for _, attr := range media.Attributes {
+			if attr.IsICECandidate() {
+				candidates++
+			}
+		}
+	}
+
+	if limits.maxRemoteCandidates != 0 && candidates > limits.maxRemoteCandidates {
+		return &rtcerr.OperationError{Err: ErrTooManyRemoteCandidates}
+	}
+
+	l.mu.Lock()
+	if candidates > l.candidates {
+		l.candidates = candidates
+	}
+	l.mu.Unlock()
+
+	return nil
+}
+
+// restartICE resets the candidate count to the candidates of the description
+// that restarted ICE, the previous remote candidates are discarded.
+func (l *remoteSignalingLimiter) restartICE(candidates int) {
+	l.mu.Lock()
+	l.candidates = candidates
+	l.mu.Unlock()
+}
+
+// checkCandidate is run for every candidate added with AddICECandidate.
+func (l *remoteSignalingLimiter) checkCandidate(settingEngine *SettingEngine, now time.Time) error {
+	limits := settingEngine.signalingLimits
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limits.maxCandidatesPerSecond != 0 {
+		if now.Sub(l.windowStart) >= time.Second {
+			l.windowStart = now
+			l.windowCandidates = 0
+		}
+
+		if l.windowCandidates >= limits.maxCandidatesPerSecond {
+			return &rtcerr.OperationError{Err: ErrAddICECandidateRateLimited}
+		}
+		l.windowCandidates++
+	}
+
+	if limits.maxRemoteCandidates != 0 && l.candidates >= limits.maxRemoteCandidates {
+		return &rtcerr.OperationError{Err: ErrTooManyRemoteCandidates}
+	}
+	l.candidates++
+
+	return nil
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSignalingLimiter(t *testing.T) {
+	t.Run("Description", func(t *testing.T) {
+		settingEngine := &SettingEngine{}
+		settingEngine.SetMaxRemoteDescriptionSize(10)
+		settingEngine.SetMaxMediaSections(1)
+		settingEngine.SetMaxRemoteCandidates(1)
+
+		limiter := &remoteSignalingLimiter{}
+		assert.NoError(t, limiter.checkDescriptionSize(settingEngine, &SessionDescription{SDP: "v=0"}))
+		assert.ErrorIs(t, limiter.checkDescriptionSize(
+			settingEngine, &SessionDescription{SDP: strings.Repeat("a", 11)},
+		), ErrRemoteDescriptionTooLarge)
+
+		candidate := sdp.Attribute{Key: "candidate", Value: "1 1 udp 1 127.0.0.1 5000 typ host"}
+		assert.NoError(t, limiter.checkDescription(settingEngine, &sdp.SessionDescription{
+			MediaDescriptions: []*sdp.MediaDescription{{Attributes: []sdp.Attribute{candidate}}},
+		}))
+		assert.ErrorIs(t, limiter.checkDescription(settingEngine, &sdp.SessionDescription{
+			MediaDescriptions: []*sdp.MediaDescription{{}, {}},
+		}), ErrTooManyMediaSections)
+		assert.ErrorIs(t, limiter.checkDescription(settingEngine, &sdp.SessionDescription{
+			MediaDescriptions: []*sdp.MediaDescription{{Attributes: []sdp.Attribute{candidate, candidate}}},
+		}), ErrTooManyRemoteCandidates)
+
+		// The candidate of the description counts against AddICECandidate
+		assert.ErrorIs(t, limiter.checkCandidate(settingEngine, time.Now()), ErrTooManyRemoteCandidates)
+	})
+
+	t.Run("Renegotiation", func(t *testing.T) {
+		settingEngine := &SettingEngine{}
+		settingEngine.SetMaxRemoteCandidates(2)
+
+		limiter := &remoteSignalingLimiter{}
+		assert.NoError(t, limiter.checkDescription(settingEngine, &sdp.SessionDescription{}))
+		assert.NoError(t, limiter.checkCandidate(settingEngine, time.Now()))
+		assert.NoError(t, limiter.checkCandidate(settingEngine, time.Now()))
+
+		// A renegotiation doesn't forget the trickled candidates
+		assert.NoError(t, limiter.checkDescription(settingEngine, &sdp.SessionDescription{}))
+		assert.ErrorIs(t, limiter.checkCandidate(settingEngine, time.Now()), ErrTooManyRemoteCandidates)
+
+		// An ICE restart does
+		limiter.restartICE(0)
+		assert.NoError(t, limiter.checkCandidate(settingEngine, time.Now()))
+	})
+
+	t.Run("Rate", func(t *testing.T) {
+		settingEngine := &SettingEngine{}
+		settingEngine.SetAddICECandidateRateLimit(2)
+
+		limiter := &remoteSignalingLimiter{}
+		now := time.Now()
+		assert.NoError(t, limiter.checkCandidate(settingEngine, now))
+		assert.NoError(t, limiter.checkCandidate(settingEngine, now.Add(100*time.Millisecond)))
+		assert.ErrorIs(t, limiter.checkCandidate(
+			settingEngine, now.Add(200*time.Millisecond),
+		), ErrAddICECandidateRateLimited)
+		assert.NoError(t, limiter.checkCandidate(settingEngine, now.Add(time.Second)))
+	})
+}
+
+func TestPeerConnection_SignalingLimits(t *testing.T) {
+	offerer, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	_, err = offerer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	offer, err := offerer.CreateOffer(nil)
+	assert.NoError(t, err)
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetMaxRemoteDescriptionSize(len(offer.SDP) - 1)
+	settingEngine.SetAddICECandidateRateLimit(1)
+
+	answerer, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, answerer.SetRemoteDescription(offer), ErrRemoteDescriptionTooLarge)
+
+	settingEngine.SetMaxRemoteDescriptionSize(0)
+	answerer.api.settingEngine = &settingEngine
+	assert.NoError(t, answerer.SetRemoteDescription(offer))
+
+	candidate := ICECandidateInit{Candidate: "candidate:1 1 udp 2130706431 127.0.0.1 5000 typ host"}
+	assert.NoError(t, answerer.AddICECandidate(candidate))
+	assert.ErrorIs(t, answerer.AddICECandidate(candidate), ErrAddICECandidateRateLimited)
+
+	// End of candidates isn't limited
+	assert.NoError(t, answerer.AddICECandidate(ICECandidateInit{}))
+
+	closePairNow(t, offerer, answerer)
+}
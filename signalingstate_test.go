@@ -137,6 +137,30 @@ func TestSignalingState_Transitions(t *testing.T) {
 			SDPTypePranswer,
 			&rtcerr.InvalidModificationError{},
 		},
+		{
+			"have-local-offer->SetLocal(rollback)->stable",
+			SignalingStateHaveLocalOffer,
+			SignalingStateStable,
+			stateChangeOpSetLocal,
+			SDPTypeRollback,
+			nil,
+		},
+		{
+			"have-remote-offer->SetRemote(rollback)->stable",
+			SignalingStateHaveRemoteOffer,
+			SignalingStateStable,
+			stateChangeOpSetRemote,
+			SDPTypeRollback,
+			nil,
+		},
+		{
+			"(invalid) have-local-offer->SetRemote(rollback)->stable",
+			SignalingStateHaveLocalOffer,
+			SignalingStateStable,
+			stateChangeOpSetRemote,
+			SDPTypeRollback,
+			&rtcerr.InvalidModificationError{},
+		},
 		{
 			"(invalid) stable->SetRemote(rollback)->have-local-offer",
 			SignalingStateStable,
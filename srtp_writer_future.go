@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/pion/rtp"
-	"github.com/pion/srtp/v3"
 )
 
 // srtpWriterFuture blocks Read/Write calls until
@@ -21,8 +20,8 @@ import (
 type srtpWriterFuture struct {
 	ssrc           SSRC
 	rtpSender      *RTPSender
-	rtcpReadStream atomic.Value // *srtp.ReadStreamSRTCP
-	rtpWriteStream atomic.Value // *srtp.WriteStreamSRTP
+	rtcpReadStream atomic.Value // SRTPReadStream
+	rtpWriteStream atomic.Value // SRTPWriteStream
 	mu             sync.Mutex
 	closed         bool
 }
@@ -86,7 +85,7 @@ func (s *srtpWriterFuture) Close() error {
 	}
 	s.closed = true
 
-	if value, ok := s.rtcpReadStream.Load().(*srtp.ReadStreamSRTCP); ok {
+	if value, ok := s.rtcpReadStream.Load().(SRTPReadStream); ok {
 		return value.Close()
 	}
 
@@ -94,7 +93,7 @@ func (s *srtpWriterFuture) Close() error {
 }
 
 func (s *srtpWriterFuture) Read(b []byte) (n int, err error) {
-	if value, ok := s.rtcpReadStream.Load().(*srtp.ReadStreamSRTCP); ok {
+	if value, ok := s.rtcpReadStream.Load().(SRTPReadStream); ok {
 		return value.Read(b)
 	}
 
@@ -106,7 +105,7 @@ func (s *srtpWriterFuture) Read(b []byte) (n int, err error) {
 }
 
 func (s *srtpWriterFuture) SetReadDeadline(t time.Time) error {
-	if value, ok := s.rtcpReadStream.Load().(*srtp.ReadStreamSRTCP); ok {
+	if value, ok := s.rtcpReadStream.Load().(SRTPReadStream); ok {
 		return value.SetReadDeadline(t)
 	}
 
@@ -118,7 +117,11 @@ func (s *srtpWriterFuture) SetReadDeadline(t time.Time) error {
 }
 
 func (s *srtpWriterFuture) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
-	if value, ok := s.rtpWriteStream.Load().(*srtp.WriteStreamSRTP); ok {
+	if value, ok := s.rtpWriteStream.Load().(SRTPWriteStream); ok {
+		if histogram := s.rtpSender.api.settingEngine.performanceMetrics.srtpWriteDuration(); histogram != nil {
+			defer histogram.observeSince(time.Now())
+		}
+
 		return value.WriteRTP(header, payload)
 	}
 
@@ -130,7 +133,7 @@ func (s *srtpWriterFuture) WriteRTP(header *rtp.Header, payload []byte) (int, er
 }
 
 func (s *srtpWriterFuture) Write(b []byte) (int, error) {
-	if value, ok := s.rtpWriteStream.Load().(*srtp.WriteStreamSRTP); ok {
+	if value, ok := s.rtpWriteStream.Load().(SRTPWriteStream); ok {
 		return value.Write(b)
 	}
 
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	// The SRTP and SRTCP packet indexes are 48 and 31 bits, an AEAD key
+	// must not protect two packets with the same index (RFC 7714).
+	defaultSRTPKeyUsageLimit  = uint64(1) << 48
+	defaultSRTCPKeyUsageLimit = uint64(1) << 31
+)
+
+// SRTPKeyUsage is the number of packets an SSRC sent with the SRTP master key
+// of a DTLSTransport, see DTLSTransport.OnSRTPKeyUsage. The packet indexes
+// are per SSRC, so are the limits.
+type SRTPKeyUsage struct {
+	SSRC         SSRC
+	SRTPPackets  uint64
+	SRTCPPackets uint64
+
+	// LimitReached is false when a limit is approached and true once it
+	// was reached, sending fails with ErrSRTPKeyUsageLimitReached from then on.
+	LimitReached bool
+}
+
+// srtpKeyUsage counts the packets protected by the sessions of a DTLSTransport
+// for each SSRC.
+type srtpKeyUsage struct {
+	srtpLimit, srtcpLimit uint64
+
+	mu      sync.RWMutex
+	ssrcs   map[SSRC]*srtpKeyUsageCounter
+	handler func(SRTPKeyUsage)
+}
+
+// srtpKeyUsageCounter is updated without holding the lock of srtpKeyUsage,
+// the streams of the SSRCs don't contend.
+type srtpKeyUsageCounter struct {
+	srtpPackets, srtcpPackets atomic.Uint64
+	approaching, reached      atomic.Bool
+}
+
+func (c *srtpKeyUsageCounter) usage(ssrc SSRC) SRTPKeyUsage {
+	return SRTPKeyUsage{
+		SSRC:         ssrc,
+		SRTPPackets:  c.srtpPackets.Load(),
+		SRTCPPackets: c.srtcpPackets.Load(),
+		LimitReached: c.reached.Load(),
+	}
+}
+
+func newSRTPKeyUsage(srtpLimit, srtcpLimit uint64) *srtpKeyUsage {
+	if srtpLimit == 0 {
+		srtpLimit = defaultSRTPKeyUsageLimit
+	}
+	if srtcpLimit == 0 {
+		srtcpLimit = defaultSRTCPKeyUsageLimit
+	}
+
+	return &srtpKeyUsage{
+		srtpLimit:  srtpLimit,
+		srtcpLimit: srtcpLimit,
+		ssrcs:      map[SSRC]*srtpKeyUsageCounter{},
+	}
+}
+
+func (u *srtpKeyUsage) onUsage(f func(SRTPKeyUsage)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.handler = f
+}
+
+func (u *srtpKeyUsage) get(ssrc SSRC) SRTPKeyUsage {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if counter, ok := u.ssrcs[ssrc]; ok {
+		return counter.usage(ssrc)
+	}
+
+	return SRTPKeyUsage{SSRC: ssrc}
+}
+
+// remove forgets the packets of the SSRCs once their streams are closed.
+func (u *srtpKeyUsage) remove(ssrcs ...SSRC) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, ssrc := range ssrcs {
+		delete(u.ssrcs, ssrc)
+	}
+}
+
+func (u *srtpKeyUsage) counter(ssrc SSRC) *srtpKeyUsageCounter {
+	u.mu.RLock()
+	counter, ok := u.ssrcs[ssrc]
+	u.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if counter, ok = u.ssrcs[ssrc]; !ok {
+		counter = &srtpKeyUsageCounter{}
+		u.ssrcs[ssrc] = counter
+	}
+
+	return counter
+}
+
+// protect accounts a packet of ssrc about to be protected, it fails once the
+// limit of the SSRC is reached.
+func (u *srtpKeyUsage) protect(ssrc SSRC, isRTCP bool) error {
+	counter := u.counter(ssrc)
+
+	packets, limit := &counter.srtpPackets, u.srtpLimit
+	if isRTCP {
+		packets, limit = &counter.srtcpPackets, u.srtcpLimit
+	}
+
+	var count uint64
+	for {
+		count = packets.Load()
+		if count >= limit {
+			return ErrSRTPKeyUsageLimitReached
+		}
+		if packets.CompareAndSwap(count, count+1) {
+			count++
+
+			break
+		}
+	}
+
+	// Notify when a quarter of the packets is left to give time to re-key
+	notify := false
+	switch {
+	case count == limit:
+		counter.approaching.Store(true)
+		counter.reached.Store(true)
+		notify = true
+	case count >= limit-limit/4:
+		notify = counter.approaching.CompareAndSwap(false, true)
+	}
+	if !notify {
+		return nil
+	}
+
+	u.mu.RLock()
+	handler := u.handler
+	u.mu.RUnlock()
+	if handler != nil {
+		go handler(counter.usage(ssrc))
+	}
+
+	return nil
+}
+
+// keyUsageSRTPSession counts the RTP packets written to an SRTPSession.
+type keyUsageSRTPSession struct {
+	SRTPSession
+	usage *srtpKeyUsage
+}
+
+func (s *keyUsageSRTPSession) OpenWriteStream() (SRTPWriteStream, error) {
+	stream, err := s.SRTPSession.OpenWriteStream()
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyUsageSRTPWriteStream{stream, s.usage}, nil
+}
+
+type keyUsageSRTPWriteStream struct {
+	SRTPWriteStream
+	usage *srtpKeyUsage
+}
+
+func (s *keyUsageSRTPWriteStream) Write(b []byte) (int, error) {
+	// Too short packets are left to the SRTP session to reject
+	if len(b) >= 12 {
+		if err := s.usage.protect(SSRC(binary.BigEndian.Uint32(b[8:12])), false); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.SRTPWriteStream.Write(b)
+}
+
+func (s *keyUsageSRTPWriteStream) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
+	if err := s.usage.protect(SSRC(header.SSRC), false); err != nil {
+		return 0, err
+	}
+
+	return s.SRTPWriteStream.WriteRTP(header, payload)
+}
+
+// keyUsageSRTCPSession counts the RTCP packets written to an SRTCPSession.
+type keyUsageSRTCPSession struct {
+	SRTCPSession
+	usage *srtpKeyUsage
+}
+
+func (s *keyUsageSRTCPSession) OpenWriteStream() (SRTCPWriteStream, error) {
+	stream, err := s.SRTCPSession.OpenWriteStream()
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyUsageSRTCPWriteStream{stream, s.usage}, nil
+}
+
+type keyUsageSRTCPWriteStream struct {
+	SRTCPWriteStream
+	usage *srtpKeyUsage
+}
+
+func (s *keyUsageSRTCPWriteStream) Write(b []byte) (int, error) {
+	// The SRTCP index belongs to the sender SSRC of the first packet
+	if len(b) >= 8 {
+		if err := s.usage.protect(SSRC(binary.BigEndian.Uint32(b[4:8])), true); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.SRTCPWriteStream.Write(b)
+}
+
+// OnSRTPKeyUsage sets a handler that is notified when the packets an SSRC sent
+// with the SRTP master key approach the limit of the protection profile, and
+// again when the limit is reached. The packets are only counted once enabled
+// with SettingEngine.EnableSRTPKeyUsageTracking, the limits can be lowered with
+// SettingEngine.SetSRTPKeyUsageLimits.
+//
+// The notification doesn't re-key: pion/dtls doesn't support renegotiation
+// and an ICE restart keeps the DTLS association. The application re-keys by
+// establishing a new DTLS association, for example with a new PeerConnection.
+func (t *DTLSTransport) OnSRTPKeyUsage(f func(SRTPKeyUsage)) {
+	t.keyUsage.onUsage(f)
+}
+
+// GetSRTPKeyUsage returns the number of packets ssrc sent with the SRTP master
+// key, see SettingEngine.EnableSRTPKeyUsageTracking.
+func (t *DTLSTransport) GetSRTPKeyUsage(ssrc SSRC) SRTPKeyUsage {
+	return t.keyUsage.get(ssrc)
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type nopSRTPWriteStream struct{}
+
+func (nopSRTPWriteStream) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (nopSRTPWriteStream) WriteRTP(_ *rtp.Header, payload []byte) (int, error) {
+	return len(payload), nil
+}
+
+func TestSRTPKeyUsage(t *testing.T) {
+	usage := newSRTPKeyUsage(8, 0)
+	assert.Equal(t, defaultSRTCPKeyUsageLimit, usage.srtcpLimit)
+
+	events := make(chan SRTPKeyUsage, 2)
+	usage.onUsage(func(u SRTPKeyUsage) {
+		events <- u
+	})
+
+	rtpStream := &keyUsageSRTPWriteStream{nopSRTPWriteStream{}, usage}
+	rtcpStream := &keyUsageSRTCPWriteStream{nopSRTPWriteStream{}, usage}
+
+	// Raw packets are accounted to the SSRC in their header
+	rtpPacket, err := (&rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 1}}).Marshal()
+	assert.NoError(t, err)
+	rtcpPacket := []byte{0x80, 0xc9, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01}
+
+	for i := 0; i < 5; i++ {
+		_, err = rtpStream.WriteRTP(&rtp.Header{SSRC: 1}, []byte{0x00})
+		assert.NoError(t, err)
+	}
+	_, err = rtcpStream.Write(rtcpPacket)
+	assert.NoError(t, err)
+
+	// Other SSRCs have limits of their own
+	for i := 0; i < 7; i++ {
+		_, err = rtpStream.WriteRTP(&rtp.Header{SSRC: 2}, []byte{0x00})
+		assert.NoError(t, err)
+	}
+	event := <-events
+	assert.Equal(t, SRTPKeyUsage{SSRC: 2, SRTPPackets: 6}, event)
+
+	select {
+	case <-events:
+		assert.Fail(t, "limit not approached yet")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A quarter of the packets is left
+	_, err = rtpStream.Write(rtpPacket)
+	assert.NoError(t, err)
+	event = <-events
+	assert.Equal(t, SSRC(1), event.SSRC)
+	assert.False(t, event.LimitReached)
+
+	for i := 0; i < 2; i++ {
+		_, err = rtpStream.Write(rtpPacket)
+		assert.NoError(t, err)
+	}
+	event = <-events
+	assert.True(t, event.LimitReached)
+	assert.Equal(t, uint64(8), event.SRTPPackets)
+	assert.Equal(t, uint64(1), event.SRTCPPackets)
+
+	_, err = rtpStream.WriteRTP(&rtp.Header{SSRC: 1}, []byte{0x00})
+	assert.ErrorIs(t, err, ErrSRTPKeyUsageLimitReached)
+	assert.Equal(t, SRTPKeyUsage{SSRC: 1, SRTPPackets: 8, SRTCPPackets: 1, LimitReached: true}, usage.get(1))
+
+	_, err = rtpStream.WriteRTP(&rtp.Header{SSRC: 2}, []byte{0x00})
+	assert.NoError(t, err)
+	assert.Equal(t, SRTPKeyUsage{SSRC: 2, SRTPPackets: 8, LimitReached: true}, <-events)
+
+	// The packets of closed streams are forgotten
+	usage.remove(1)
+	assert.Equal(t, SRTPKeyUsage{SSRC: 1}, usage.get(1))
+	assert.Equal(t, uint64(8), usage.get(2).SRTPPackets)
+}
+
+func TestSRTPKeyUsageTracking(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	for _, enabled := range []bool{false, true} {
+		settingEngine := SettingEngine{}
+		settingEngine.EnableSRTPKeyUsageTracking(enabled)
+
+		offerer, answerer, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+		assert.NoError(t, err)
+
+		track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+		assert.NoError(t, err)
+
+		sender, err := offerer.AddTrack(track)
+		assert.NoError(t, err)
+		ssrc := sender.GetParameters().Encodings[0].SSRC
+
+		onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+		answerer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+			onTrackFiredFunc()
+		})
+
+		assert.NoError(t, signalPair(offerer, answerer))
+		sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+		// Packets are only counted when enabled
+		transport := sender.Transport()
+		assert.Equal(t, enabled, transport.GetSRTPKeyUsage(ssrc).SRTPPackets != 0)
+
+		assert.NoError(t, offerer.RemoveTrack(sender))
+		assert.Zero(t, transport.GetSRTPKeyUsage(ssrc).SRTPPackets)
+
+		closePairNow(t, offerer, answerer)
+	}
+}
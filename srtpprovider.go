@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/pion/dtls/v3"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+	"github.com/pion/srtp/v3"
+	"github.com/pion/transport/v3/packetio"
+)
+
+// SRTPReadStream is the stream of decrypted SRTP or SRTCP packets of one SSRC.
+type SRTPReadStream interface {
+	Read(b []byte) (int, error)
+	SetReadDeadline(t time.Time) error
+	Close() error
+}
+
+// SRTPWriteStream encrypts and sends RTP packets.
+type SRTPWriteStream interface {
+	Write(b []byte) (int, error)
+	WriteRTP(header *rtp.Header, payload []byte) (int, error)
+}
+
+// SRTCPWriteStream encrypts and sends RTCP packets.
+type SRTCPWriteStream interface {
+	Write(b []byte) (int, error)
+}
+
+// SRTPSession protects the RTP sent and received over a DTLSTransport.
+type SRTPSession interface {
+	// OpenWriteStream returns the stream used to send RTP.
+	OpenWriteStream() (SRTPWriteStream, error)
+	// OpenReadStream returns the stream of the RTP received from the SSRC.
+	OpenReadStream(ssrc uint32) (SRTPReadStream, error)
+	// AcceptStream returns the streams of SSRCs no stream was opened for.
+	AcceptStream() (SRTPReadStream, uint32, error)
+	Close() error
+}
+
+// SRTCPSession protects the RTCP sent and received over a DTLSTransport.
+type SRTCPSession interface {
+	// OpenWriteStream returns the stream used to send RTCP.
+	OpenWriteStream() (SRTCPWriteStream, error)
+	// OpenReadStream returns the stream of the RTCP received from the SSRC.
+	OpenReadStream(ssrc uint32) (SRTPReadStream, error)
+	// AcceptStream returns the streams of SSRCs no stream was opened for.
+	AcceptStream() (SRTPReadStream, uint32, error)
+	Close() error
+}
+
+// SRTPReplayProtection configures the replay protection of the SRTP or SRTCP
+// packets received.
+type SRTPReplayProtection struct {
+	// Disabled accepts replayed packets.
+	Disabled bool
+	// Window is the size of the replay protection window, zero uses the
+	// default of the SRTPProvider.
+	Window uint
+}
+
+// SRTPConfig holds what an SRTPProvider needs to protect the packets of a
+// DTLSTransport.
+type SRTPConfig struct {
+	// Profile is the protection profile selected in the DTLS handshake.
+	Profile dtls.SRTPProtectionProfile
+
+	// The keys and salts are extracted from DTLS, the local ones protect the
+	// packets sent and the remote ones the packets received.
+	LocalMasterKey   []byte
+	LocalMasterSalt  []byte
+	RemoteMasterKey  []byte
+	RemoteMasterSalt []byte
+
+	SRTPReplayProtection  SRTPReplayProtection
+	SRTCPReplayProtection SRTPReplayProtection
+}
+
+// SRTPProvider creates the SRTP and SRTCP sessions of a DTLSTransport once the
+// DTLS handshake is done. It allows replacing pion/srtp, for example with
+// hardware offloaded or FIPS validated crypto, see SettingEngine.SetSRTPProvider.
+//
+// conn carries the encrypted packets.
+type SRTPProvider interface {
+	NewSessionSRTP(conn net.Conn, config SRTPConfig) (SRTPSession, error)
+	NewSessionSRTCP(conn net.Conn, config SRTPConfig) (SRTCPSession, error)
+}
+
+// pionSRTPProvider is the SRTPProvider using pion/srtp.
+type pionSRTPProvider struct {
+	bufferFactory func(packetType packetio.BufferPacketType, ssrc uint32) io.ReadWriteCloser
+	loggerFactory logging.LoggerFactory
+}
+
+func (p pionSRTPProvider) NewSessionSRTP(conn net.Conn, config SRTPConfig) (SRTPSession, error) {
+	srtpConfig, err := p.srtpConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := srtp.NewSessionSRTP(conn, srtpConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pionSRTPSession{session}, nil
+}
+
+func (p pionSRTPProvider) NewSessionSRTCP(conn net.Conn, config SRTPConfig) (SRTCPSession, error) {
+	srtpConfig, err := p.srtpConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := srtp.NewSessionSRTCP(conn, srtpConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pionSRTCPSession{session}, nil
+}
+
+func (p pionSRTPProvider) srtpConfig(config SRTPConfig) (*srtp.Config, error) {
+	profile, err := srtpProtectionProfile(config.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	srtpConfig := &srtp.Config{
+		Profile: profile,
+		Keys: srtp.SessionKeys{
+			LocalMasterKey:   config.LocalMasterKey,
+			LocalMasterSalt:  config.LocalMasterSalt,
+			RemoteMasterKey:  config.RemoteMasterKey,
+			RemoteMasterSalt: config.RemoteMasterSalt,
+		},
+		BufferFactory: p.bufferFactory,
+		LoggerFactory: p.loggerFactory,
+	}
+
+	switch {
+	case config.SRTPReplayProtection.Disabled:
+		srtpConfig.RemoteOptions = append(srtpConfig.RemoteOptions, srtp.SRTPNoReplayProtection())
+	case config.SRTPReplayProtection.Window != 0:
+		srtpConfig.RemoteOptions = append(
+			srtpConfig.RemoteOptions,
+			srtp.SRTPReplayProtection(config.SRTPReplayProtection.Window),
+		)
+	}
+
+	switch {
+	case config.SRTCPReplayProtection.Disabled:
+		srtpConfig.RemoteOptions = append(srtpConfig.RemoteOptions, srtp.SRTCPNoReplayProtection())
+	case config.SRTCPReplayProtection.Window != 0:
+		srtpConfig.RemoteOptions = append(
+			srtpConfig.RemoteOptions,
+			srtp.SRTCPReplayProtection(config.SRTCPReplayProtection.Window),
+		)
+	}
+
+	return srtpConfig, nil
+}
+
+// srtpProtectionProfile returns the pion/srtp profile of the DTLS one.
+func srtpProtectionProfile(profile dtls.SRTPProtectionProfile) (srtp.ProtectionProfile, error) {
+	switch profile {
+	case dtls.SRTP_AEAD_AES_128_GCM:
+		return srtp.ProtectionProfileAeadAes128Gcm, nil
+	case dtls.SRTP_AEAD_AES_256_GCM:
+		return srtp.ProtectionProfileAeadAes256Gcm, nil
+	case dtls.SRTP_AES128_CM_HMAC_SHA1_80:
+		return srtp.ProtectionProfileAes128CmHmacSha1_80, nil
+	case dtls.SRTP_NULL_HMAC_SHA1_80:
+		return srtp.ProtectionProfileNullHmacSha1_80, nil
+	default:
+		return 0, ErrNoSRTPProtectionProfile
+	}
+}
+
+type pionSRTPSession struct {
+	session *srtp.SessionSRTP
+}
+
+func (s *pionSRTPSession) OpenWriteStream() (SRTPWriteStream, error) {
+	stream, err := s.session.OpenWriteStream()
+	if err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+func (s *pionSRTPSession) OpenReadStream(ssrc uint32) (SRTPReadStream, error) {
+	stream, err := s.session.OpenReadStream(ssrc)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+func (s *pionSRTPSession) AcceptStream() (SRTPReadStream, uint32, error) {
+	stream, ssrc, err := s.session.AcceptStream()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return stream, ssrc, nil
+}
+
+func (s *pionSRTPSession) Close() error {
+	return s.session.Close()
+}
+
+type pionSRTCPSession struct {
+	session *srtp.SessionSRTCP
+}
+
+func (s *pionSRTCPSession) OpenWriteStream() (SRTCPWriteStream, error) {
+	stream, err := s.session.OpenWriteStream()
+	if err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+func (s *pionSRTCPSession) OpenReadStream(ssrc uint32) (SRTPReadStream, error) {
+	stream, err := s.session.OpenReadStream(ssrc)
+	if err != nil {
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+func (s *pionSRTCPSession) AcceptStream() (SRTPReadStream, uint32, error) {
+	stream, ssrc, err := s.session.AcceptStream()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return stream, ssrc, nil
+}
+
+func (s *pionSRTCPSession) Close() error {
+	return s.session.Close()
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSRTPProvider struct {
+	pionSRTPProvider
+	srtpSessions, srtcpSessions int32
+	config                      atomic.Value // SRTPConfig
+}
+
+func (p *countingSRTPProvider) NewSessionSRTP(conn net.Conn, config SRTPConfig) (SRTPSession, error) {
+	atomic.AddInt32(&p.srtpSessions, 1)
+	p.config.Store(config)
+
+	return p.pionSRTPProvider.NewSessionSRTP(conn, config)
+}
+
+func (p *countingSRTPProvider) NewSessionSRTCP(conn net.Conn, config SRTPConfig) (SRTCPSession, error) {
+	atomic.AddInt32(&p.srtcpSessions, 1)
+
+	return p.pionSRTPProvider.NewSessionSRTCP(conn, config)
+}
+
+func TestSetSRTPProvider(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	provider := &countingSRTPProvider{}
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetSRTPProvider(provider)
+	settingEngine.SetSRTPReplayProtectionWindow(128)
+	settingEngine.DisableSRTCPReplayProtection(true)
+
+	offerer, answerer, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	_, err = offerer.AddTrack(track)
+	assert.NoError(t, err)
+
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	answerer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+		onTrackFiredFunc()
+	})
+
+	assert.NoError(t, signalPair(offerer, answerer))
+
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+	// Both PeerConnections use the provider of the API
+	assert.Equal(t, int32(2), atomic.LoadInt32(&provider.srtpSessions))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&provider.srtcpSessions))
+
+	config, ok := provider.config.Load().(SRTPConfig)
+	assert.True(t, ok)
+	assert.NotZero(t, config.Profile)
+	assert.NotEmpty(t, config.LocalMasterKey)
+	assert.NotEmpty(t, config.RemoteMasterSalt)
+	assert.Equal(t, SRTPReplayProtection{Window: 128}, config.SRTPReplayProtection)
+	assert.Equal(t, SRTPReplayProtection{Disabled: true}, config.SRTCPReplayProtection)
+
+	closePairNow(t, offerer, answerer)
+}
@@ -172,6 +172,55 @@ func statsTimestampNow() StatsTimestamp {
 // StatsReport collects Stats objects indexed by their ID.
 type StatsReport map[string]Stats
 
+// MarshalJSON encodes the report in the shape browser getStats() reports are
+// serialized to by tools like webrtc-internals, an object mapping the ID of
+// each stats object to its members. Like browsers do for members that aren't
+// set, members that are an empty string or null are left out.
+func (r StatsReport) MarshalJSON() ([]byte, error) {
+	report := make(map[string]map[string]json.RawMessage, len(r))
+	for id, stats := range r {
+		b, err := json.Marshal(stats)
+		if err != nil {
+			return nil, err
+		}
+
+		members := map[string]json.RawMessage{}
+		if err = json.Unmarshal(b, &members); err != nil {
+			return nil, err
+		}
+		for name, value := range members {
+			if string(value) == `""` || string(value) == "null" {
+				delete(members, name)
+			}
+		}
+
+		report[id] = members
+	}
+
+	return json.Marshal(report)
+}
+
+// UnmarshalJSON decodes a report encoded by MarshalJSON, stats objects of a
+// type UnmarshalStatsJSON doesn't know are an error.
+func (r *StatsReport) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("unmarshal stats report: %w", err)
+	}
+
+	report := make(StatsReport, len(raw))
+	for id, rawStats := range raw {
+		stats, err := UnmarshalStatsJSON(rawStats)
+		if err != nil {
+			return err
+		}
+		report[id] = stats
+	}
+	*r = report
+
+	return nil
+}
+
 type statsReportCollector struct {
 	collectingGroup sync.WaitGroup
 	report          StatsReport
@@ -448,6 +497,21 @@ type InboundRTPStreamStats struct {
 	// and only counted for RTP packets that have no further chance of repair.
 	PacketsRepaired uint32 `json:"packetsRepaired"`
 
+	// PacketsRecoveredByFEC is the number of lost RTP packets recovered from
+	// ULPFEC packets, see ConfigureULPFEC. It is a subset of PacketsRepaired.
+	PacketsRecoveredByFEC uint32 `json:"packetsRecoveredByFec,omitempty"`
+
+	// PacketsRecoveredByRTX is the number of RTP packets received as a
+	// retransmission on the RTX stream. It is a subset of PacketsRepaired.
+	PacketsRecoveredByRTX uint32 `json:"packetsRecoveredByRtx,omitempty"`
+
+	// ConcealedGaps is the number of runs of consecutive RTP packets that were
+	// lost and not repaired in time, leaving the decoder to conceal them.
+	ConcealedGaps uint32 `json:"concealedGaps,omitempty"`
+
+	// PacketsConcealed is the total number of RTP packets in ConcealedGaps.
+	PacketsConcealed uint32 `json:"packetsConcealed,omitempty"`
+
 	// BurstPacketsLost is the cumulative number of RTP packets lost during loss bursts.
 	BurstPacketsLost uint32 `json:"burstPacketsLost"`
 
@@ -1104,6 +1168,19 @@ type RemoteOutboundRTPStreamStats struct {
 	// received for this SSRC that contain a DLRR report block that can derive a valid round trip time
 	// according to [RFC3611]. This counter will not increment if the RoundTripTime can not be calculated.
 	RoundTripTimeMeasurements uint64 `json:"roundTripTimeMeasurements"`
+
+	// EstimatedClockOffset is the estimated offset in seconds of the clock of the remote
+	// endpoint from the local clock, estimated from the RTCP Sender Reports and the round
+	// trip time. Subtract it from a remote NTP time, such as RemoteTimestamp or the capture
+	// time of abs-capture-time, to convert it to the local clock.
+	// This is a Pion specific extension and is not part of the W3C API.
+	EstimatedClockOffset float64 `json:"estimatedClockOffset,omitempty"`
+
+	// EstimatedClockSkew is the estimated rate at which the clock of the remote endpoint
+	// drifts from the local clock, in parts per million. It is zero until the RTCP Sender
+	// Reports have been observed for ten seconds.
+	// This is a Pion specific extension and is not part of the W3C API.
+	EstimatedClockSkew float64 `json:"estimatedClockSkew,omitempty"`
 }
 
 func (s RemoteOutboundRTPStreamStats) statsMarker() {}
@@ -1451,6 +1528,27 @@ type DataChannelStats struct {
 	// BytesReceived represents the total number of bytes received on this
 	// datachannel not including headers or padding.
 	BytesReceived uint64 `json:"bytesReceived"`
+
+	// MessagesAcknowledged is the number of messages sent with Send or SendText
+	// the remote peer acknowledged with a SCTP SACK, or that were abandoned
+	// because of the reliability parameters of the channel. It is only
+	// measured if SettingEngine.SetDataChannelLatencySampling is set.
+	MessagesAcknowledged uint32 `json:"messagesAcknowledged,omitempty"`
+
+	// TotalAcknowledgementDelay is the sum of the time in seconds from the
+	// Send of each acknowledged message to its acknowledgement, the time
+	// queued in the SCTP send buffer and the network round trip. Dividing it
+	// by MessagesAcknowledged gives the average delay, which exposes the
+	// head-of-line blocking of ordered channels.
+	TotalAcknowledgementDelay float64 `json:"totalAcknowledgementDelay,omitempty"`
+
+	// MaxAcknowledgementDelay is the longest delay in seconds from the Send
+	// of a message to its acknowledgement.
+	MaxAcknowledgementDelay float64 `json:"maxAcknowledgementDelay,omitempty"`
+
+	// BufferedAmount is the "bufferedAmount" value of the DataChannel object,
+	// the number of bytes queued to be sent.
+	BufferedAmount uint64 `json:"bufferedAmount,omitempty"`
 }
 
 func (s DataChannelStats) statsMarker() {}
@@ -1958,6 +2056,14 @@ type TransportStats struct {
 	// not including headers or padding.
 	BytesReceived uint64 `json:"bytesReceived"`
 
+	// RelayBytesSent is the part of BytesSent that was sent while the selected
+	// candidate pair used a local relay candidate, so went through a TURN server.
+	RelayBytesSent uint64 `json:"relayBytesSent,omitempty"`
+
+	// RelayBytesReceived is the part of BytesReceived that was received while the
+	// selected candidate pair used a local relay candidate.
+	RelayBytesReceived uint64 `json:"relayBytesReceived,omitempty"`
+
 	// RTCPTransportStatsID is the ID of the transport that gives stats for the RTCP
 	// component If RTP and RTCP are not multiplexed and this record has only
 	// the RTP component stats.
@@ -101,3 +101,19 @@ func (r StatsReport) GetCodecStats(c *RTPCodecParameters) (CodecStats, bool) {
 
 	return codecStats, true
 }
+
+// GetInboundRTPStreamStats is a helper method to return the associated stats for a given TrackRemote.
+func (r StatsReport) GetInboundRTPStreamStats(t *TrackRemote) (InboundRTPStreamStats, bool) {
+	statsID := t.inboundRTPStreamStatsID()
+	stats, ok := r[statsID]
+	if !ok {
+		return InboundRTPStreamStats{}, false
+	}
+
+	inboundRTPStreamStats, ok := stats.(InboundRTPStreamStats)
+	if !ok {
+		return InboundRTPStreamStats{}, false
+	}
+
+	return inboundRTPStreamStats, true
+}
@@ -7,6 +7,7 @@
 package webrtc
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -115,6 +116,10 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 		Jitter:                         8,
 		PacketsDiscarded:               9,
 		PacketsRepaired:                10,
+		PacketsRecoveredByFEC:          50,
+		PacketsRecoveredByRTX:          51,
+		ConcealedGaps:                  52,
+		PacketsConcealed:               53,
 		BurstPacketsLost:               11,
 		BurstPacketsDiscarded:          12,
 		BurstLossCount:                 13,
@@ -188,6 +193,10 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "jitter": 8,
   "packetsDiscarded": 9,
   "packetsRepaired": 10,
+  "packetsRecoveredByFec": 50,
+  "packetsRecoveredByRtx": 51,
+  "concealedGaps": 52,
+  "packetsConcealed": 53,
   "burstPacketsLost": 11,
   "burstPacketsDiscarded": 12,
   "burstLossCount": 13,
@@ -429,6 +438,8 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 		RoundTripTime:             10,
 		TotalRoundTripTime:        11,
 		RoundTripTimeMeasurements: 12,
+		EstimatedClockOffset:      13,
+		EstimatedClockSkew:        14,
 	}
 	remoteOutboundRTPStreamStatsJSON := `
 {
@@ -454,7 +465,9 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "reportsSent": 9,
   "roundTripTime": 10,
   "totalRoundTripTime": 11,
-  "roundTripTimeMeasurements": 12
+  "roundTripTimeMeasurements": 12,
+  "estimatedClockOffset": 13,
+  "estimatedClockSkew": 14
 }
 `
 	csrcStats := RTPContributingSourceStats{
@@ -580,18 +593,22 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 }
 `
 	dataChannelStats := DataChannelStats{
-		Timestamp:             1688978831527.718,
-		Type:                  StatsTypeDataChannel,
-		ID:                    "D1",
-		Label:                 "display",
-		Protocol:              "protocol",
-		DataChannelIdentifier: 1,
-		TransportID:           "T1",
-		State:                 DataChannelStateOpen,
-		MessagesSent:          1,
-		BytesSent:             16,
-		MessagesReceived:      2,
-		BytesReceived:         20,
+		Timestamp:                 1688978831527.718,
+		Type:                      StatsTypeDataChannel,
+		ID:                        "D1",
+		Label:                     "display",
+		Protocol:                  "protocol",
+		DataChannelIdentifier:     1,
+		TransportID:               "T1",
+		State:                     DataChannelStateOpen,
+		MessagesSent:              1,
+		BytesSent:                 16,
+		MessagesReceived:          2,
+		BytesReceived:             20,
+		MessagesAcknowledged:      1,
+		TotalAcknowledgementDelay: 0.05,
+		MaxAcknowledgementDelay:   0.05,
+		BufferedAmount:            8,
 	}
 	dataChannelStatsJSON := `
 {
@@ -606,7 +623,11 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "messagesSent": 1,
   "bytesSent": 16,
   "messagesReceived": 2,
-  "bytesReceived": 20
+  "bytesReceived": 20,
+  "messagesAcknowledged": 1,
+  "totalAcknowledgementDelay": 0.05,
+  "maxAcknowledgementDelay": 0.05,
+  "bufferedAmount": 8
 }
 `
 	streamStats := MediaStreamStats{
@@ -819,6 +840,8 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 		PacketsReceived:         8,
 		BytesSent:               6517,
 		BytesReceived:           1159,
+		RelayBytesSent:          512,
+		RelayBytesReceived:      256,
 		RTCPTransportStatsID:    "T01",
 		ICERole:                 ICERoleControlling,
 		DTLSState:               DTLSTransportStateConnected,
@@ -841,6 +864,8 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "packetsReceived": 8,
   "bytesSent": 6517,
   "bytesReceived": 1159,
+  "relayBytesSent": 512,
+  "relayBytesReceived": 256,
   "rtcpTransportStatsId": "T01",
   "iceRole": "controlling",
   "dtlsState": "connected",
@@ -1140,6 +1165,41 @@ func TestStatsMarshal(t *testing.T) {
 	}
 }
 
+func TestStatsReport_JSON(t *testing.T) {
+	report := StatsReport{}
+	for i, sample := range getStatsSamples() {
+		report[fmt.Sprintf("%d", i)] = sample.stats
+	}
+	report["empty"] = CertificateStats{Timestamp: 1688978831527.718, Type: StatsTypeCertificate, ID: "empty"}
+
+	b, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var members map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &members))
+	assert.Len(t, members, len(report))
+	for id, stats := range members {
+		assert.Contains(t, stats, "id", id)
+		assert.Contains(t, stats, "type", id)
+		assert.Contains(t, stats, "timestamp", id)
+		for name, value := range stats {
+			assert.NotEqual(t, "", value, "%s.%s", id, name)
+			assert.NotNil(t, value, "%s.%s", id, name)
+		}
+	}
+	assert.Equal(t, map[string]interface{}{
+		"id":        "empty",
+		"type":      "certificate",
+		"timestamp": 1688978831527.718,
+	}, members["empty"])
+
+	var decoded StatsReport
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, report, decoded)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"a":{"type":"unknown"}}`), &decoded), ErrUnknownType)
+}
+
 func TestStatsUnmarshal(t *testing.T) {
 	for _, test := range getStatsSamples() {
 		t.Run(test.name+"_unmarshal", func(t *testing.T) {
@@ -1420,6 +1480,16 @@ func TestPeerConnection_GetStats(t *testing.T) {
 	assert.NotEmpty(t, findLocalCandidateStats(reportPCOffer))
 	assert.NotEmpty(t, findRemoteCandidateStats(reportPCOffer))
 	assert.NotEmpty(t, findCandidatePairStats(t, reportPCOffer))
+	outboundStatsCount := 0
+	for _, stats := range reportPCOffer {
+		if outboundStats, ok := stats.(OutboundRTPStreamStats); ok {
+			outboundStatsCount++
+			assert.Equal(t, "video", outboundStats.Kind)
+			assert.NotEmpty(t, outboundStats.Mid)
+			assert.Equal(t, fmt.Sprintf("OutboundRTPStream-%d", outboundStats.SSRC), outboundStats.ID)
+		}
+	}
+	assert.Equal(t, 1, outboundStatsCount)
 
 	connStatsAnswer = getConnectionStats(t, reportPCAnswer, answerPC)
 	assert.Equal(t, uint32(1), connStatsAnswer.DataChannelsOpened)
@@ -1476,6 +1546,18 @@ func TestPeerConnection_GetStats(t *testing.T) {
 	offerICETransportStats := getTransportStats(t, reportPCOffer, "iceTransport")
 	assert.GreaterOrEqual(t, offerICETransportStats.BytesSent, answerICETransportStats.BytesReceived)
 	assert.GreaterOrEqual(t, answerICETransportStats.BytesSent, offerICETransportStats.BytesReceived)
+	assert.Equal(t, ICETransportStateConnected, offerICETransportStats.ICEState)
+	assert.Equal(t, ICERoleControlling, offerICETransportStats.ICERole)
+	assert.Equal(t, ICERoleControlled, answerICETransportStats.ICERole)
+	_, ok := reportPCOffer[offerICETransportStats.SelectedCandidatePairID]
+	assert.True(t, ok, "selected candidate pair is in the report")
+	assert.Equal(t, offerPC.configuration.Certificates[0].statsID, offerICETransportStats.LocalCertificateID)
+	remoteCertificateStats, ok := reportPCOffer[offerICETransportStats.RemoteCertificateID].(CertificateStats)
+	assert.True(t, ok, "remote certificate is in the report")
+	assert.Equal(t,
+		base64.RawURLEncoding.EncodeToString(answerPC.configuration.Certificates[0].x509Cert.Raw),
+		remoteCertificateStats.Base64Certificate,
+	)
 
 	answerSCTPTransportStats := getSctpTransportStats(t, reportPCAnswer)
 	offerSCTPTransportStats := getSctpTransportStats(t, reportPCOffer)
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
+)
+
+// Subscribe adds count recvonly transceivers of each kind, so the remote
+// peer can send that many more tracks. Inactive transceivers without a
+// sender, like the ones Unsubscribe stops, are reused before new ones are
+// created. Negotiation is needed once for all of them.
+func (pc *PeerConnection) Subscribe(kinds []RTPCodecType, count int) ([]*RTPTransceiver, error) {
+	if pc.isClosed.get() {
+		return nil, &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	subscribed := []*RTPTransceiver{}
+	for _, kind := range kinds {
+		for i := 0; i < count; i++ {
+			receiver, err := pc.api.NewRTPReceiver(kind, pc.dtlsTransport)
+			if err != nil {
+				return subscribed, err
+			}
+
+			if t := pc.unusedTransceiver(kind); t != nil {
+				if prevReceiver := t.Receiver(); prevReceiver != nil {
+					_ = prevReceiver.Stop()
+				}
+				t.setReceiver(receiver)
+				t.setDirection(RTPTransceiverDirectionRecvonly)
+				subscribed = append(subscribed, t)
+
+				continue
+			}
+
+			t := newRTPTransceiver(receiver, nil, RTPTransceiverDirectionRecvonly, kind, pc.api)
+			pc.rtpTransceivers = append(pc.rtpTransceivers, t)
+			subscribed = append(subscribed, t)
+		}
+	}
+
+	if len(subscribed) != 0 {
+		pc.onNegotiationNeeded()
+	}
+
+	return subscribed, nil
+}
+
+// Unsubscribe stops up to count recvonly transceivers of each kind, the most
+// recently subscribed first. Their media sections are kept inactive and are
+// reused by the next Subscribe. Negotiation is needed once for all of them.
+func (pc *PeerConnection) Unsubscribe(kinds []RTPCodecType, count int) error {
+	if pc.isClosed.get() {
+		return &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	stopped := false
+	for _, kind := range kinds {
+		remaining := count
+		for i := len(pc.rtpTransceivers) - 1; i >= 0 && remaining > 0; i-- {
+			t := pc.rtpTransceivers[i]
+			if t.kind != kind || t.Sender() != nil || t.Direction() != RTPTransceiverDirectionRecvonly {
+				continue
+			}
+
+			if err := t.Stop(); err != nil {
+				return err
+			}
+			stopped = true
+			remaining--
+		}
+	}
+
+	if stopped {
+		pc.onNegotiationNeeded()
+	}
+
+	return nil
+}
+
+// unusedTransceiver returns an inactive transceiver of the kind that doesn't
+// send, the caller must hold pc.mu.
+func (pc *PeerConnection) unusedTransceiver(kind RTPCodecType) *RTPTransceiver {
+	for _, t := range pc.rtpTransceivers {
+		if t.kind == kind && t.Sender() == nil && t.Direction() == RTPTransceiverDirectionInactive {
+			return t
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerConnection_Subscribe(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pc, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	negotiationNeeded := make(chan struct{}, 10)
+	pc.OnNegotiationNeeded(func() {
+		negotiationNeeded <- struct{}{}
+	})
+
+	transceivers, err := pc.Subscribe([]RTPCodecType{RTPCodecTypeVideo, RTPCodecTypeAudio}, 2)
+	assert.NoError(t, err)
+	assert.Len(t, transceivers, 4)
+	for _, transceiver := range transceivers {
+		assert.Equal(t, RTPTransceiverDirectionRecvonly, transceiver.Direction())
+	}
+
+	// One negotiation for all the transceivers
+	<-negotiationNeeded
+	offer, err := pc.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, pc.SetLocalDescription(offer))
+	assert.Equal(t, 4, strings.Count(offer.SDP, "a=recvonly"))
+	assert.Len(t, negotiationNeeded, 0)
+
+	assert.NoError(t, pc.Unsubscribe([]RTPCodecType{RTPCodecTypeVideo}, 1))
+	assert.Equal(t, RTPTransceiverDirectionRecvonly, transceivers[0].Direction())
+	assert.Equal(t, RTPTransceiverDirectionInactive, transceivers[1].Direction())
+
+	// The stopped transceiver is reused
+	resubscribed, err := pc.Subscribe([]RTPCodecType{RTPCodecTypeVideo}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*RTPTransceiver{transceivers[1]}, resubscribed)
+	assert.Equal(t, RTPTransceiverDirectionRecvonly, transceivers[1].Direction())
+	assert.Len(t, pc.GetTransceivers(), 4)
+
+	assert.NoError(t, pc.Close())
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor/pkg/report"
+)
+
+// TickerPool shares a single timer between all tickers of the same interval.
+// Interceptors of every PeerConnection of an API normally create their own
+// time.Ticker, with thousands of PeerConnections per process the timers and
+// the goroutines servicing them dominate. A TickerPool runs one goroutine per
+// distinct interval and fans the ticks out to all subscribers.
+//
+// The pool serves the Sender Report timers with
+// ConfigureRTCPReportsWithTickerPool, and the periodic timers of the
+// PeerConnections with SettingEngine.SetTickerPool.
+//
+// Like time.Ticker, ticks are dropped for subscribers that are not ready to
+// receive them.
+type TickerPool struct {
+	mu     sync.Mutex
+	groups map[time.Duration]*tickerGroup
+}
+
+type tickerGroup struct {
+	subscribers map[*pooledTicker]struct{}
+	done        chan struct{}
+}
+
+type pooledTicker struct {
+	pool     *TickerPool
+	interval time.Duration
+	ch       chan time.Time
+}
+
+// NewTickerPool creates a new TickerPool.
+func NewTickerPool() *TickerPool {
+	return &TickerPool{groups: map[time.Duration]*tickerGroup{}}
+}
+
+// NewTicker returns a ticker firing every interval that is backed by the
+// shared timer of the pool. It can be used as a report.TickerFactory.
+func (p *TickerPool) NewTicker(interval time.Duration) report.Ticker {
+	ticker := &pooledTicker{
+		pool:     p,
+		interval: interval,
+		ch:       make(chan time.Time, 1),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	group, ok := p.groups[interval]
+	if !ok {
+		group = &tickerGroup{
+			subscribers: map[*pooledTicker]struct{}{},
+			done:        make(chan struct{}),
+		}
+		p.groups[interval] = group
+		go p.run(interval, group)
+	}
+	group.subscribers[ticker] = struct{}{}
+
+	return ticker
+}
+
+func (p *TickerPool) run(interval time.Duration, group *tickerGroup) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-group.done:
+			return
+		case now := <-ticker.C:
+			p.mu.Lock()
+			for subscriber := range group.subscribers {
+				select {
+				case subscriber.ch <- now:
+				default:
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *TickerPool) stop(ticker *pooledTicker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	group, ok := p.groups[ticker.interval]
+	if !ok {
+		return
+	}
+
+	delete(group.subscribers, ticker)
+	if len(group.subscribers) == 0 {
+		close(group.done)
+		delete(p.groups, ticker.interval)
+	}
+}
+
+// SetTickerPool makes the periodic timers the PeerConnections of the API run
+// themselves share the timers of pool.
+func (e *SettingEngine) SetTickerPool(pool *TickerPool) {
+	e.tickerPool = pool
+}
+
+// newTicker returns a ticker from the TickerPool if one is set, or a
+// time.Ticker otherwise.
+func (e *SettingEngine) newTicker(interval time.Duration) report.Ticker {
+	if e.tickerPool != nil {
+		return e.tickerPool.NewTicker(interval)
+	}
+
+	return &timeTicker{time.NewTicker(interval)}
+}
+
+type timeTicker struct {
+	*time.Ticker
+}
+
+func (t *timeTicker) Ch() <-chan time.Time {
+	return t.C
+}
+
+// Ch returns the channel the ticks are delivered on.
+func (t *pooledTicker) Ch() <-chan time.Time {
+	return t.ch
+}
+
+// Stop turns off the ticker. Like time.Ticker, the channel is not closed.
+func (t *pooledTicker) Stop() {
+	t.pool.stop(t)
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickerPool(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pool := NewTickerPool()
+
+	tickerA := pool.NewTicker(10 * time.Millisecond)
+	tickerB := pool.NewTicker(10 * time.Millisecond)
+	tickerC := pool.NewTicker(20 * time.Millisecond)
+
+	pool.mu.Lock()
+	assert.Len(t, pool.groups, 2)
+	pool.mu.Unlock()
+
+	tickA := <-tickerA.Ch()
+	tickB := <-tickerB.Ch()
+	assert.Equal(t, tickA, tickB)
+	<-tickerC.Ch()
+
+	tickerA.Stop()
+	tickerA.Stop()
+	<-tickerB.Ch()
+
+	tickerB.Stop()
+	tickerC.Stop()
+
+	pool.mu.Lock()
+	assert.Empty(t, pool.groups)
+	pool.mu.Unlock()
+}
+
+func TestConfigureRTCPReportsWithTickerPool(t *testing.T) {
+	registry := &interceptor.Registry{}
+	assert.NoError(t, ConfigureRTCPReportsWithTickerPool(registry, NewTickerPool()))
+
+	i, err := registry.Build("")
+	assert.NoError(t, err)
+	assert.NoError(t, i.Close())
+}
+
+func TestSettingEngine_SetTickerPool(t *testing.T) {
+	settingEngine := SettingEngine{}
+
+	ticker := settingEngine.newTicker(10 * time.Millisecond)
+	_, isPooled := ticker.(*pooledTicker)
+	assert.False(t, isPooled)
+	<-ticker.Ch()
+	ticker.Stop()
+
+	pool := NewTickerPool()
+	settingEngine.SetTickerPool(pool)
+
+	ticker = settingEngine.newTicker(10 * time.Millisecond)
+	_, isPooled = ticker.(*pooledTicker)
+	assert.True(t, isPooled)
+	<-ticker.Ch()
+	ticker.Stop()
+
+	pool.mu.Lock()
+	assert.Empty(t, pool.groups)
+	pool.mu.Unlock()
+}
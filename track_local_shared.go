@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/internal/util"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// SharedTrackLocal accepts Samples like TrackLocalStaticSample and sends them to
+// all the tracks created with NewTrack. Every PeerConnection can be given its
+// own track, with its own ID and StreamID, while the Samples are packetized
+// only once. The SSRC and PayloadType of the packets are rewritten for every
+// binding.
+type SharedTrackLocal struct {
+	mu sync.RWMutex
+
+	codec   RTPCodecCapability
+	options []func(*TrackLocalStaticRTP)
+
+	packetizer rtp.Packetizer
+	sequencer  rtp.Sequencer
+	clockRate  float64
+
+	// tracks are the tracks with at least one binding.
+	tracks []*TrackLocalStaticRTP
+}
+
+// NewSharedTrackLocal returns a SharedTrackLocal. The options are applied to
+// every track created with NewTrack.
+func NewSharedTrackLocal(c RTPCodecCapability, options ...func(*TrackLocalStaticRTP)) *SharedTrackLocal {
+	return &SharedTrackLocal{
+		codec:   c,
+		options: options,
+	}
+}
+
+// NewTrack returns a TrackLocal that sends the Samples written to the
+// SharedTrackLocal once it is bound.
+func (s *SharedTrackLocal) NewTrack(id, streamID string) (TrackLocal, error) {
+	rtpTrack, err := NewTrackLocalStaticRTP(s.codec, id, streamID, s.options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sharedTrack{shared: s, rtpTrack: rtpTrack}, nil
+}
+
+// Codec gets the Codec of the tracks.
+func (s *SharedTrackLocal) Codec() RTPCodecCapability {
+	return s.codec
+}
+
+// WriteSample packetizes a Sample and writes the packets to all bound tracks.
+// If one PeerConnection fails the packets will still be sent to
+// all PeerConnections. The error message will contain the ID of the failed
+// PeerConnections so you can remove them.
+func (s *SharedTrackLocal) WriteSample(sample media.Sample) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.packetizer == nil {
+		return nil
+	}
+
+	packets := packetizeSample(s.packetizer, s.sequencer, s.clockRate, sample)
+
+	writeErrs := []error{}
+	for _, p := range packets {
+		for _, track := range s.tracks {
+			if err := track.writeSampleRTP(p, &sample); err != nil {
+				writeErrs = append(writeErrs, err)
+			}
+		}
+	}
+
+	return util.FlattenErrs(writeErrs)
+}
+
+func (s *SharedTrackLocal) bind(rtpTrack *TrackLocalStaticRTP, codec RTPCodecParameters) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The packetizer is created for the first binding, the clock rate of the
+	// codec is the same for all of them
+	if s.packetizer == nil {
+		packetizer, sequencer, err := newSamplePacketizer(rtpTrack.payloader, rtpTrack.sequencer, codec)
+		if err != nil {
+			return err
+		}
+		s.packetizer, s.sequencer = packetizer, sequencer
+		s.clockRate = float64(codec.ClockRate)
+	}
+
+	for _, track := range s.tracks {
+		if track == rtpTrack {
+			return nil
+		}
+	}
+	s.tracks = append(s.tracks, rtpTrack)
+
+	return nil
+}
+
+func (s *SharedTrackLocal) unbind(rtpTrack *TrackLocalStaticRTP) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rtpTrack.mu.RLock()
+	bound := len(rtpTrack.bindings) != 0
+	rtpTrack.mu.RUnlock()
+	if bound {
+		return
+	}
+
+	for i, track := range s.tracks {
+		if track == rtpTrack {
+			s.tracks = append(s.tracks[:i], s.tracks[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// sharedTrack is a TrackLocal created by SharedTrackLocal.NewTrack.
+type sharedTrack struct {
+	shared   *SharedTrackLocal
+	rtpTrack *TrackLocalStaticRTP
+}
+
+// Bind is called by the PeerConnection after negotiation is complete.
+func (t *sharedTrack) Bind(trackContext TrackLocalContext) (RTPCodecParameters, error) {
+	codec, err := t.rtpTrack.Bind(trackContext)
+	if err != nil {
+		return codec, err
+	}
+
+	if err = t.shared.bind(t.rtpTrack, codec); err != nil {
+		_ = t.rtpTrack.Unbind(trackContext)
+
+		return codec, err
+	}
+
+	return codec, nil
+}
+
+// Unbind implements the teardown logic when the track is no longer needed.
+func (t *sharedTrack) Unbind(trackContext TrackLocalContext) error {
+	if err := t.rtpTrack.Unbind(trackContext); err != nil {
+		return err
+	}
+	t.shared.unbind(t.rtpTrack)
+
+	return nil
+}
+
+// ID is the unique identifier for this Track.
+func (t *sharedTrack) ID() string { return t.rtpTrack.ID() }
+
+// StreamID is the group this track belongs too.
+func (t *sharedTrack) StreamID() string { return t.rtpTrack.StreamID() }
+
+// RID is the RTP stream identifier.
+func (t *sharedTrack) RID() string { return t.rtpTrack.RID() }
+
+// Kind controls if this TrackLocal is audio or video.
+func (t *sharedTrack) Kind() RTPCodecType { return t.rtpTrack.Kind() }
+
+// Codec gets the Codec of the track.
+func (t *sharedTrack) Codec() RTPCodecCapability { return t.rtpTrack.Codec() }
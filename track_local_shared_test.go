@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SharedTrackLocal(t *testing.T) {
+	defer test.TimeOut(time.Second * 30).Stop()
+	defer test.CheckRoutines(t)()
+
+	var payloaders int32
+	shared := NewSharedTrackLocal(
+		RTPCodecCapability{MimeType: MimeTypeVP8},
+		WithPayloader(func(RTPCodecCapability) (rtp.Payloader, error) {
+			atomic.AddInt32(&payloaders, 1)
+
+			return &codecs.VP8Payloader{}, nil
+		}),
+	)
+
+	var received sync.WaitGroup
+	pairs := [][2]*PeerConnection{}
+	for _, id := range []string{"viewer1", "viewer2"} {
+		id := id
+		offerer, answerer, err := newPair()
+		assert.NoError(t, err)
+		pairs = append(pairs, [2]*PeerConnection{offerer, answerer})
+
+		track, err := shared.NewTrack("video", id)
+		assert.NoError(t, err)
+
+		_, err = offerer.AddTrack(track)
+		assert.NoError(t, err)
+
+		received.Add(1)
+		var once sync.Once
+		answerer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+			assert.Equal(t, id, trackRemote.StreamID())
+			once.Do(received.Done)
+		})
+
+		assert.NoError(t, signalPair(offerer, answerer))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		received.Wait()
+		close(done)
+	}()
+
+	func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(20 * time.Millisecond):
+				assert.NoError(t, shared.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Second}))
+			}
+		}
+	}()
+
+	// Samples are packetized once for all tracks
+	assert.Equal(t, int32(1), atomic.LoadInt32(&payloaders))
+
+	shared.mu.RLock()
+	assert.Len(t, shared.tracks, 2)
+	shared.mu.RUnlock()
+
+	for _, pair := range pairs {
+		closePairNow(t, pair[0], pair[1])
+	}
+
+	// Closed PeerConnections unbind their tracks
+	shared.mu.RLock()
+	assert.Empty(t, shared.tracks)
+	shared.mu.RUnlock()
+}
@@ -9,10 +9,12 @@ package webrtc
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pion/rtp"
 	"github.com/pion/webrtc/v4/internal/util"
 	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/pion/webrtc/v4/pkg/media/red"
 )
 
 // trackBinding is a single bind for a Track
@@ -22,7 +24,12 @@ type trackBinding struct {
 	id                          string
 	ssrc, ssrcRTX, ssrcFEC      SSRC
 	payloadType, payloadTypeRTX PayloadType
+	payloadTypeRED              PayloadType
 	writeStream                 TrackLocalWriter
+	frameDropper                *frameDropper
+	absCaptureTimeID            uint8
+	videoOrientationID          uint8
+	headerExtensionIDs          map[string]uint8
 }
 
 // TrackLocalStaticRTP  is a TrackLocal that has a pre-set codec and accepts RTP Packets.
@@ -32,7 +39,17 @@ type TrackLocalStaticRTP struct {
 	bindings          []trackBinding
 	codec             RTPCodecCapability
 	payloader         func(RTPCodecCapability) (rtp.Payloader, error)
+	sequencer         rtp.Sequencer
 	id, rid, streamID string
+
+	redMu      sync.Mutex
+	redEncoder *red.Encoder
+
+	frameDropThreshold uint64
+
+	// IDs of the header extensions of the written packets, see
+	// WithSourceHeaderExtensions
+	sourceExtensions map[uint8]string
 }
 
 // NewTrackLocalStaticRTP returns a TrackLocalStaticRTP.
@@ -69,6 +86,39 @@ func WithPayloader(h func(RTPCodecCapability) (rtp.Payloader, error)) func(*Trac
 	}
 }
 
+// WithSequencer sets the Sequencer numbering the RTP packets of the Samples
+// of a TrackLocalStaticSample, for instance to continue the sequence numbers
+// of a previous track. A random Sequencer is used by default. Together with
+// WithPayloader it allows sending the Samples of codecs Pion has no
+// Payloader for.
+func WithSequencer(sequencer rtp.Sequencer) func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.sequencer = sequencer
+	}
+}
+
+// WithRED makes the track send each payload again in the next distance packets
+// using RED (RFC 2198), if RED was negotiated for the codec of the track. See
+// MediaEngine.RegisterOpusRED.
+func WithRED(distance int) func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.redEncoder = red.NewEncoder(distance)
+	}
+}
+
+// WithFrameDropThreshold drops whole video frames instead of sending them while
+// a binding has more than threshold bytes waiting to be sent, see
+// TrackLocalBufferedWriter. Frames are told apart by their RTP timestamp. Once
+// a frame is dropped the following frames are dropped too, until a keyframe
+// is written after the congestion cleared. The dropped packets are seen as
+// lost by the remote, which requests a keyframe. It has no effect on audio
+// tracks.
+func WithFrameDropThreshold(threshold uint64) func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.frameDropThreshold = threshold
+	}
+}
+
 // Bind is called by the PeerConnection after negotiation is complete
 // This asserts that the code requested is supported by the remote peer.
 // If so it sets up all the state (SSRC and PayloadType) to have a call.
@@ -80,15 +130,30 @@ func (s *TrackLocalStaticRTP) Bind(trackContext TrackLocalContext) (RTPCodecPara
 	if codec, matchType := codecParametersFuzzySearch(
 		parameters,
 		trackContext.CodecParameters(),
-	); matchType != codecMatchNone {
+	); matchType != CodecMatchNone {
+		var payloadTypeRED PayloadType
+		if s.redEncoder != nil {
+			payloadTypeRED = findREDPayloadType(codec.PayloadType, trackContext.CodecParameters())
+		}
+
+		var dropper *frameDropper
+		if s.frameDropThreshold != 0 && s.Kind() == RTPCodecTypeVideo {
+			dropper = &frameDropper{threshold: s.frameDropThreshold, mimeType: s.codec.MimeType}
+		}
+
 		s.bindings = append(s.bindings, trackBinding{
-			ssrc:           trackContext.SSRC(),
-			ssrcRTX:        trackContext.SSRCRetransmission(),
-			ssrcFEC:        trackContext.SSRCForwardErrorCorrection(),
-			payloadType:    codec.PayloadType,
-			payloadTypeRTX: findRTXPayloadType(codec.PayloadType, trackContext.CodecParameters()),
-			writeStream:    trackContext.WriteStream(),
-			id:             trackContext.ID(),
+			ssrc:               trackContext.SSRC(),
+			ssrcRTX:            trackContext.SSRCRetransmission(),
+			ssrcFEC:            trackContext.SSRCForwardErrorCorrection(),
+			payloadType:        codec.PayloadType,
+			payloadTypeRTX:     findRTXPayloadType(codec.PayloadType, trackContext.CodecParameters()),
+			payloadTypeRED:     payloadTypeRED,
+			writeStream:        trackContext.WriteStream(),
+			frameDropper:       dropper,
+			absCaptureTimeID:   headerExtensionID(trackContext.HeaderExtensions(), AbsCaptureTimeURI),
+			videoOrientationID: headerExtensionID(trackContext.HeaderExtensions(), VideoOrientationURI),
+			headerExtensionIDs: headerExtensionIDs(trackContext.HeaderExtensions()),
+			id:                 trackContext.ID(),
 		})
 
 		return codec, nil
@@ -178,22 +243,121 @@ func (s *TrackLocalStaticRTP) WriteRTP(p *rtp.Packet) error {
 
 // writeRTP is like WriteRTP, except that it may modify the packet p.
 func (s *TrackLocalStaticRTP) writeRTP(packet *rtp.Packet) error {
+	return s.writeSampleRTP(packet, nil)
+}
+
+// writeSampleRTP is like writeRTP for a packet of sample. The SVC layer of
+// the sample is passed on to writers that support it, its capture time is
+// added to the packet of the bindings that negotiated abs-capture-time and
+// its orientation to the last packet of the bindings that negotiated CVO.
+func (s *TrackLocalStaticRTP) writeSampleRTP(packet *rtp.Packet, sample *media.Sample) error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	var spatialID, temporalID uint8
+	var captureTimePayload, orientationPayload []byte
+	if sample != nil {
+		spatialID, temporalID = sample.SpatialID, sample.TemporalID
+		if !sample.CaptureTime.IsZero() {
+			var err error
+			if captureTimePayload, err = absCaptureTimePayload(sample.CaptureTime); err != nil {
+				return err
+			}
+		}
+		if sample.Orientation != nil && packet.Marker {
+			orientationPayload = videoOrientationPayload(*sample.Orientation)
+		}
+	}
+
+	// The extensions are restored for every binding, as the extension IDs
+	// may differ between them
+	extension, extensionProfile, extensions := packet.Extension, packet.ExtensionProfile, packet.Extensions
+	defer func() {
+		packet.Extension, packet.ExtensionProfile, packet.Extensions = extension, extensionProfile, extensions
+	}()
+
+	// Padding only packets are not protected by RED
+	useRED := s.redEncoder != nil && len(packet.Payload) != 0
+	if useRED {
+		s.redMu.Lock()
+		defer s.redMu.Unlock()
+	}
+
 	writeErrs := []error{}
 
 	for _, b := range s.bindings {
 		packet.Header.SSRC = uint32(b.ssrc)
 		packet.Header.PayloadType = uint8(b.payloadType)
-		if _, err := b.writeStream.WriteRTP(&packet.Header, packet.Payload); err != nil {
+		packet.Header.Extension = extension
+		packet.Header.ExtensionProfile = extensionProfile
+		packet.Header.Extensions = extensions
+
+		if s.sourceExtensions != nil {
+			if err := b.mapHeaderExtensions(&packet.Header, s.sourceExtensions); err != nil {
+				writeErrs = append(writeErrs, err)
+
+				continue
+			}
+		}
+
+		if err := b.setSampleExtensions(&packet.Header, captureTimePayload, orientationPayload); err != nil {
+			writeErrs = append(writeErrs, err)
+
+			continue
+		}
+
+		if b.frameDropper != nil && b.frameDropper.drop(packet, b.writeStream) {
+			continue
+		}
+
+		payload := packet.Payload
+		if useRED && b.payloadTypeRED != 0 {
+			payload = s.redEncoder.Marshal(uint8(b.payloadType), packet.Payload, packet.Timestamp)
+			packet.Header.PayloadType = uint8(b.payloadTypeRED)
+		}
+
+		var err error
+		if layerWriter, ok := b.writeStream.(svcLayerWriter); ok {
+			_, err = layerWriter.writeRTPWithLayer(&packet.Header, payload, spatialID, temporalID)
+		} else {
+			_, err = b.writeStream.WriteRTP(&packet.Header, payload)
+		}
+		if err != nil {
 			writeErrs = append(writeErrs, err)
 		}
 	}
 
+	if useRED {
+		s.redEncoder.Push(packet.Payload, packet.Timestamp)
+	}
+
 	return util.FlattenErrs(writeErrs)
 }
 
+// setSampleExtensions adds the header extensions of a sample the binding
+// negotiated to header. Its extensions are copied first, as they are shared
+// by the bindings.
+func (b *trackBinding) setSampleExtensions(header *rtp.Header, captureTime, orientation []byte) error {
+	copied := false
+	for _, extension := range []struct {
+		id      uint8
+		payload []byte
+	}{{b.absCaptureTimeID, captureTime}, {b.videoOrientationID, orientation}} {
+		if extension.id == 0 || extension.payload == nil {
+			continue
+		}
+		if !copied {
+			header.Extensions = append([]rtp.Extension(nil), header.Extensions...)
+			copied = true
+		}
+		if err := header.SetExtension(extension.id, extension.payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Write writes a RTP Packet as a buffer to the TrackLocalStaticRTP
 // If one PeerConnection fails the packets will still be sent to
 // all PeerConnections. The error message will contain the ID of the failed
@@ -217,6 +381,24 @@ type TrackLocalStaticSample struct {
 	sequencer  rtp.Sequencer
 	rtpTrack   *TrackLocalStaticRTP
 	clockRate  float64
+
+	onFrameSentHandler func(FrameSendReport)
+
+	// stateMu guards the packetizing of the Samples and the state of the
+	// numbering of their packets
+	stateMu       sync.Mutex
+	state         SampleTrackState
+	hasState      bool
+	nextWriteTime time.Time
+}
+
+// SampleTrackState is the numbering of the RTP packets of a
+// TrackLocalStaticSample.
+type SampleTrackState struct {
+	// SequenceNumber is the sequence number of the last packet sent.
+	SequenceNumber uint16
+	// Timestamp is the RTP timestamp of the next Sample.
+	Timestamp uint32
 }
 
 // NewTrackLocalStaticSample returns a TrackLocalStaticSample.
@@ -271,28 +453,64 @@ func (s *TrackLocalStaticSample) Bind(t TrackLocalContext) (RTPCodecParameters,
 		return codec, nil
 	}
 
-	payloadHandler := s.rtpTrack.payloader
+	s.packetizer, s.sequencer, err = newSamplePacketizer(s.rtpTrack.payloader, s.rtpTrack.sequencer, codec)
+	if err != nil {
+		return codec, err
+	}
+	s.clockRate = float64(codec.RTPCodecCapability.ClockRate)
+
+	return codec, nil
+}
+
+// newSamplePacketizer returns the packetizer that turns the Samples of a track
+// bound with codec into RTP packets, numbered by sequencer if not nil.
+func newSamplePacketizer(
+	payloadHandler func(RTPCodecCapability) (rtp.Payloader, error),
+	sequencer rtp.Sequencer,
+	codec RTPCodecParameters,
+) (rtp.Packetizer, rtp.Sequencer, error) {
 	if payloadHandler == nil {
 		payloadHandler = payloaderForCodec
 	}
 
 	payloader, err := payloadHandler(codec.RTPCodecCapability)
 	if err != nil {
-		return codec, err
+		return nil, nil, err
+	}
+
+	if sequencer == nil {
+		sequencer = rtp.NewRandomSequencer()
 	}
 
-	s.sequencer = rtp.NewRandomSequencer()
-	s.packetizer = rtp.NewPacketizer(
+	return rtp.NewPacketizer(
 		rtpOutboundMTU,
 		0, // Value is handled when writing
 		0, // Value is handled when writing
 		payloader,
-		s.sequencer,
+		sequencer,
 		codec.ClockRate,
-	)
-	s.clockRate = float64(codec.RTPCodecCapability.ClockRate)
+	), sequencer, nil
+}
 
-	return codec, nil
+// packetizeSample splits sample into RTP packets, skipping the sequence
+// numbers and timestamps of the packets dropped before it.
+func packetizeSample(
+	packetizer rtp.Packetizer,
+	sequencer rtp.Sequencer,
+	clockRate float64,
+	sample media.Sample,
+) []*rtp.Packet {
+	// skip packets by the number of previously dropped packets
+	for i := uint16(0); i < sample.PrevDroppedPackets; i++ {
+		sequencer.NextSequenceNumber()
+	}
+
+	samples := uint32(sample.Duration.Seconds() * clockRate)
+	if sample.PrevDroppedPackets > 0 {
+		packetizer.SkipSamples(samples * uint32(sample.PrevDroppedPackets))
+	}
+
+	return packetizer.Packetize(sample.Data, samples)
 }
 
 // Unbind implements the teardown logic when the track is no longer needed. This happens
@@ -306,34 +524,111 @@ func (s *TrackLocalStaticSample) Unbind(t TrackLocalContext) error {
 // all PeerConnections. The error message will contain the ID of the failed
 // PeerConnections so you can remove them.
 func (s *TrackLocalStaticSample) WriteSample(sample media.Sample) error {
+	writeTime := time.Now()
+
 	s.rtpTrack.mu.RLock()
 	packetizer := s.packetizer
 	clockRate := s.clockRate
+	onFrameSent := s.onFrameSentHandler
 	s.rtpTrack.mu.RUnlock()
 
 	if packetizer == nil {
 		return nil
 	}
-
-	// skip packets by the number of previously dropped packets
-	for i := uint16(0); i < sample.PrevDroppedPackets; i++ {
-		s.sequencer.NextSequenceNumber()
+	if len(sample.CSRC) > maxCSRC {
+		return errSampleTooManyCSRC
 	}
 
-	samples := uint32(sample.Duration.Seconds() * clockRate)
-	if sample.PrevDroppedPackets > 0 {
-		packetizer.SkipSamples(samples * uint32(sample.PrevDroppedPackets))
+	s.stateMu.Lock()
+	packets := packetizeSample(packetizer, s.sequencer, clockRate, sample)
+	if len(packets) != 0 {
+		s.state = SampleTrackState{
+			SequenceNumber: packets[len(packets)-1].SequenceNumber,
+			Timestamp:      packets[0].Timestamp + uint32(sample.Duration.Seconds()*clockRate),
+		}
+		s.hasState = true
+	}
+	s.nextWriteTime = writeTime.Add(sample.Duration)
+	s.stateMu.Unlock()
+
+	report := FrameSendReport{Size: len(sample.Data), Packets: len(packets), WriteTime: writeTime}
+	if onFrameSent != nil {
+		report.BufferedAmount = s.rtpTrack.bufferedAmount()
+		for _, p := range packets {
+			report.PayloadBytes += len(p.Payload)
+		}
+		if len(packets) != 0 {
+			report.Timestamp = packets[0].Timestamp
+		}
 	}
-	packets := packetizer.Packetize(sample.Data, samples)
 
+	sendStart := time.Now()
 	writeErrs := []error{}
 	for _, p := range packets {
-		if err := s.rtpTrack.WriteRTP(p); err != nil {
+		p.CSRC = sample.CSRC
+		if err := s.rtpTrack.writeSampleRTP(p, &sample); err != nil {
 			writeErrs = append(writeErrs, err)
 		}
 	}
+	err := util.FlattenErrs(writeErrs)
 
-	return util.FlattenErrs(writeErrs)
+	if onFrameSent != nil {
+		report.PacingDelay = sendStart.Sub(writeTime)
+		report.SendDuration = time.Since(sendStart)
+		report.Err = err
+		onFrameSent(report)
+	}
+
+	return err
+}
+
+// State returns the numbering of the packets of the track, so an application
+// switching sources can check it stays continuous. It returns false until a
+// Sample was sent.
+func (s *TrackLocalStaticSample) State() (SampleTrackState, bool) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	return s.state, s.hasState
+}
+
+// SkipSamples advances the RTP timestamp of the next Sample by duration, as
+// if Samples of that duration were sent, without a jump in the sequence
+// numbers.
+func (s *TrackLocalStaticSample) SkipSamples(duration time.Duration) {
+	s.rtpTrack.mu.RLock()
+	packetizer := s.packetizer
+	clockRate := s.clockRate
+	s.rtpTrack.mu.RUnlock()
+
+	if packetizer == nil || duration <= 0 {
+		return
+	}
+
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	samples := uint32(duration.Seconds() * clockRate)
+	packetizer.SkipSamples(samples)
+	s.state.Timestamp += samples
+	if !s.nextWriteTime.IsZero() {
+		s.nextWriteTime = s.nextWriteTime.Add(duration)
+	}
+}
+
+// Resync advances the RTP timestamp of the next Sample by the time elapsed
+// since the previous Sample ended, so the timestamps keep following the wall
+// clock after the source of the Samples stalled or was switched. It does
+// nothing if the Samples are on time.
+func (s *TrackLocalStaticSample) Resync() {
+	s.stateMu.Lock()
+	nextWriteTime := s.nextWriteTime
+	s.stateMu.Unlock()
+
+	if nextWriteTime.IsZero() {
+		return
+	}
+	s.SkipSamples(time.Since(nextWriteTime))
 }
 
 // GeneratePadding writes padding-only samples to the TrackLocalStaticSample
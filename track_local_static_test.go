@@ -15,6 +15,8 @@ import (
 
 	"github.com/pion/rtp"
 	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/pion/webrtc/v4/pkg/media/red"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -425,3 +427,210 @@ func Test_TrackLocalStatic_Payloader(t *testing.T) {
 
 	closePairNow(t, offerer, answerer)
 }
+
+func Test_TrackLocalStatic_RED(t *testing.T) {
+	defer test.TimeOut(time.Second * 30).Stop()
+	defer test.CheckRoutines(t)()
+
+	newMediaEngine := func() *MediaEngine {
+		mediaEngine := &MediaEngine{}
+		assert.NoError(t, mediaEngine.RegisterDefaultCodecs())
+		assert.NoError(t, mediaEngine.RegisterOpusRED(63))
+
+		return mediaEngine
+	}
+
+	offerer, err := NewAPI(WithMediaEngine(newMediaEngine())).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	answerer, err := NewAPI(WithMediaEngine(newMediaEngine())).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion", WithRED(1))
+	assert.NoError(t, err)
+
+	_, err = offerer.AddTrack(track)
+	assert.NoError(t, err)
+
+	onRedundantPacket, onRedundantPacketFunc := context.WithCancel(context.Background())
+	answerer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		assert.Equal(t, MimeTypeRED, trackRemote.Codec().MimeType)
+
+		for {
+			packet, _, readErr := trackRemote.ReadRTP()
+			if readErr != nil {
+				return
+			}
+
+			blocks, unmarshalErr := red.Unmarshal(packet.Payload)
+			assert.NoError(t, unmarshalErr)
+			if len(blocks) == 2 {
+				assert.Equal(t, uint8(111), blocks[0].PayloadType)
+				onRedundantPacketFunc()
+
+				return
+			}
+		}
+	})
+
+	assert.NoError(t, signalPair(offerer, answerer))
+
+	// Redundant blocks must be within 16383 samples of the primary one
+	func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-onRedundantPacket.Done():
+				return
+			case <-ticker.C:
+				assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: 20 * time.Millisecond}))
+			}
+		}
+	}()
+
+	closePairNow(t, offerer, answerer)
+}
+
+func Test_TrackLocalStatic_OnFrameSent(t *testing.T) {
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8, ClockRate: 90000}, "video", "pion")
+	assert.NoError(t, err)
+
+	var reports []FrameSendReport
+	track.OnFrameSent(func(report FrameSendReport) {
+		reports = append(reports, report)
+	})
+
+	// Samples written before the track is bound aren't reported
+	assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Second}))
+	assert.Empty(t, reports)
+
+	writer := &bufferedTestWriter{bufferedAmount: 1500}
+	_, err = track.Bind(&baseTrackLocalContext{
+		id: "binding",
+		params: RTPParameters{Codecs: []RTPCodecParameters{
+			{RTPCodecCapability: track.Codec(), PayloadType: 96},
+		}},
+		ssrc:        1,
+		writeStream: writer,
+	})
+	assert.NoError(t, err)
+
+	// Larger than the MTU, so it is split in two packets
+	data := make([]byte, rtpOutboundMTU+100)
+	assert.NoError(t, track.WriteSample(media.Sample{Data: data, Duration: time.Second}))
+
+	require.Len(t, reports, 1)
+	report := reports[0]
+	assert.Equal(t, len(data), report.Size)
+	assert.Equal(t, 2, report.Packets)
+	assert.Len(t, writer.written, 2)
+	assert.GreaterOrEqual(t, report.PayloadBytes, len(data))
+	assert.Equal(t, uint64(1500), report.BufferedAmount)
+	assert.False(t, report.WriteTime.IsZero())
+	assert.GreaterOrEqual(t, report.PacingDelay, time.Duration(0))
+	assert.GreaterOrEqual(t, report.SendDuration, time.Duration(0))
+	assert.NoError(t, report.Err)
+}
+
+func Test_TrackLocalStatic_CSRC(t *testing.T) {
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus, ClockRate: 48000}, "audio", "pion")
+	require.NoError(t, err)
+
+	writer := &headerTestWriter{}
+	_, err = track.Bind(&baseTrackLocalContext{
+		params: RTPParameters{
+			Codecs: []RTPCodecParameters{{RTPCodecCapability: track.Codec(), PayloadType: 111}},
+		},
+		ssrc:        1,
+		writeStream: writer,
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: 20 * time.Millisecond}))
+	assert.NoError(t, track.WriteSample(media.Sample{
+		Data: []byte{0x00}, Duration: 20 * time.Millisecond, CSRC: []uint32{2, 3},
+	}))
+	assert.ErrorIs(t, track.WriteSample(media.Sample{
+		Data: []byte{0x00}, Duration: 20 * time.Millisecond, CSRC: make([]uint32, maxCSRC+1),
+	}), errSampleTooManyCSRC)
+
+	require.Len(t, writer.headers, 2)
+	assert.Empty(t, writer.headers[0].CSRC)
+	assert.Equal(t, []uint32{2, 3}, writer.headers[1].CSRC)
+}
+
+func Test_TrackLocalStatic_Sequencer(t *testing.T) {
+	customPayloader := &customCodecPayloader{}
+	track, err := NewTrackLocalStaticSample(
+		RTPCodecCapability{MimeType: "audio/L16", ClockRate: 48000, Channels: 2},
+		"audio",
+		"pion",
+		WithPayloader(func(RTPCodecCapability) (rtp.Payloader, error) {
+			return customPayloader, nil
+		}),
+		WithSequencer(rtp.NewFixedSequencer(1000)),
+	)
+	require.NoError(t, err)
+
+	writer := &headerTestWriter{}
+	_, err = track.Bind(&baseTrackLocalContext{
+		params: RTPParameters{
+			Codecs: []RTPCodecParameters{{RTPCodecCapability: track.Codec(), PayloadType: 97}},
+		},
+		ssrc:        1,
+		writeStream: writer,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00, 0x01}, Duration: 10 * time.Millisecond}))
+	}
+
+	require.Len(t, writer.headers, 2)
+	assert.Equal(t, uint16(1000), writer.headers[0].SequenceNumber)
+	assert.Equal(t, uint16(1001), writer.headers[1].SequenceNumber)
+	assert.Equal(t, int32(2), customPayloader.invokeCount.Load())
+}
+
+func Test_TrackLocalStatic_State(t *testing.T) {
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus, ClockRate: 48000}, "audio", "pion")
+	require.NoError(t, err)
+
+	writer := &headerTestWriter{}
+	_, err = track.Bind(&baseTrackLocalContext{
+		params: RTPParameters{
+			Codecs: []RTPCodecParameters{{RTPCodecCapability: track.Codec(), PayloadType: 111}},
+		},
+		ssrc:        1,
+		writeStream: writer,
+	})
+	require.NoError(t, err)
+
+	_, ok := track.State()
+	assert.False(t, ok)
+
+	sample := media.Sample{Data: []byte{0x00}, Duration: 20 * time.Millisecond}
+	assert.NoError(t, track.WriteSample(sample))
+	state, ok := track.State()
+	assert.True(t, ok)
+	assert.Equal(t, writer.headers[0].SequenceNumber, state.SequenceNumber)
+	assert.Equal(t, writer.headers[0].Timestamp+960, state.Timestamp)
+
+	// Skipping advances the timestamps but not the sequence numbers
+	track.SkipSamples(20 * time.Millisecond)
+	assert.NoError(t, track.WriteSample(sample))
+	assert.Equal(t, writer.headers[0].SequenceNumber+1, writer.headers[1].SequenceNumber)
+	assert.Equal(t, writer.headers[0].Timestamp+2*960, writer.headers[1].Timestamp)
+
+	// The source stalled for 100ms
+	track.stateMu.Lock()
+	track.nextWriteTime = time.Now().Add(-100 * time.Millisecond)
+	track.stateMu.Unlock()
+	track.Resync()
+	assert.NoError(t, track.WriteSample(sample))
+	assert.GreaterOrEqual(t, writer.headers[2].Timestamp-writer.headers[1].Timestamp, uint32(960+4800))
+	state, _ = track.State()
+	assert.Equal(t, writer.headers[2].SequenceNumber, state.SequenceNumber)
+}
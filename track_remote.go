@@ -7,19 +7,45 @@
 package webrtc
 
 import (
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/pion/webrtc/v4/pkg/media/samplebuilder"
 )
 
+const (
+	defaultJitterBufferMaxLateVideo = 256
+	defaultJitterBufferMaxLateAudio = 16
+)
+
+// JitterBufferConfig controls the reordering buffer used by TrackRemote.ReadSample.
+type JitterBufferConfig struct {
+	// MaxLate is how many packets to wait for a missing packet before the
+	// incomplete sample is dropped. Defaults to 256 for video and 16 for audio.
+	MaxLate uint16
+
+	// MaxLatency bounds the time a packet can wait in the buffer, see
+	// samplebuilder.WithMaxLatency. Zero disables the bound.
+	MaxLatency time.Duration
+
+	// MinPlayoutDelay and MaxPlayoutDelay bound the adaptive delay of the
+	// jitter buffer used by TrackRemote.ReadPlayout, they are read on its
+	// first call. They default to 20ms and 500ms.
+	MinPlayoutDelay time.Duration
+	MaxPlayoutDelay time.Duration
+}
+
 // TrackRemote represents a single inbound source of media.
 type TrackRemote struct {
 	mu sync.RWMutex
 
-	id       string
-	streamID string
+	id        string
+	streamID  string
+	streamIDs []string
 
 	payloadType PayloadType
 	kind        RTPCodecType
@@ -32,6 +58,27 @@ type TrackRemote struct {
 	receiver         *RTPReceiver
 	peeked           []byte
 	peekedAttributes interceptor.Attributes
+
+	jitterBufferConfig JitterBufferConfig
+	sampleBuilder      *samplebuilder.SampleBuilder
+	sampleBuilderCodec string
+
+	lossStats      lossStats
+	counters       rtpCounters
+	remoteOutbound remoteOutboundStats
+
+	playout        *trackPlayout
+	bitrateCapStop chan struct{}
+
+	sampleExtensions sampleExtensionsRing
+
+	onEndedHandler  func(TrackEndReason)
+	onMuteHandler   func()
+	onUnmuteHandler func()
+	watchdogStarted bool
+	muted           bool
+	endReason       TrackEndReason
+	endedCh         chan struct{}
 }
 
 func newTrackRemote(kind RTPCodecType, ssrc, rtxSsrc SSRC, rid string, receiver *RTPReceiver) *TrackRemote {
@@ -41,6 +88,7 @@ func newTrackRemote(kind RTPCodecType, ssrc, rtxSsrc SSRC, rid string, receiver
 		rtxSsrc:  rtxSsrc,
 		rid:      rid,
 		receiver: receiver,
+		endedCh:  make(chan struct{}),
 	}
 }
 
@@ -88,6 +136,16 @@ func (t *TrackRemote) StreamID() string {
 	return t.streamID
 }
 
+// StreamIDs returns the ids of the MediaStreams the track belongs to, one
+// for each a=msid line of its media section. It is empty if the track isn't
+// part of a stream.
+func (t *TrackRemote) StreamIDs() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return append([]string{}, t.streamIDs...)
+}
+
 // SSRC gets the SSRC of the track.
 func (t *TrackRemote) SSRC() SSRC {
 	t.mu.RLock()
@@ -140,6 +198,10 @@ func (t *TrackRemote) Read(b []byte) (n int, attributes interceptor.Attributes,
 		attributes = rtxPacketReceived.attributes
 		rtxPacketReceived.release()
 		err = nil
+		now := time.Now()
+		t.lossStats.add(b[:n], attributes)
+		t.counters.add(b[:n], now)
+		t.addContributingSources(receiver, b[:n], now)
 	} else {
 		// If there's no separate RTX track (or there's a separate RTX track but no RTX packet waiting), wait for and return
 		// a packet from the main track
@@ -148,8 +210,13 @@ func (t *TrackRemote) Read(b []byte) (n int, attributes interceptor.Attributes,
 			return n, attributes, err
 		}
 
+		now := time.Now()
+		t.lossStats.add(b[:n], attributes)
+		t.counters.add(b[:n], now)
+		t.addContributingSources(receiver, b[:n], now)
 		err = t.checkAndUpdateTrack(b)
 	}
+	receiver.api.settingEngine.performanceMetrics.observeReadLatency(attributes)
 
 	return n, attributes, err
 }
@@ -193,9 +260,30 @@ func (t *TrackRemote) ReadRTP() (*rtp.Packet, interceptor.Attributes, error) {
 		return nil, nil, err
 	}
 
+	if captureTime, ok := readAbsCaptureTime(&r.Header, t.headerExtensionID(AbsCaptureTimeURI)); ok {
+		if attributes == nil {
+			attributes = interceptor.Attributes{}
+		}
+		attributes.Set(AttributeAbsCaptureTime, captureTime)
+	}
+	if orientation, ok := readVideoOrientation(&r.Header, t.headerExtensionID(VideoOrientationURI)); ok {
+		if attributes == nil {
+			attributes = interceptor.Attributes{}
+		}
+		attributes.Set(AttributeVideoOrientation, orientation)
+	}
+
 	return r, attributes, nil
 }
 
+// headerExtensionID returns the negotiated ID of the header extension uri, or 0.
+func (t *TrackRemote) headerExtensionID(uri string) uint8 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return headerExtensionID(t.params.HeaderExtensions, uri)
+}
+
 // peek is like Read, but it doesn't discard the packet read.
 func (t *TrackRemote) peek(b []byte) (n int, a interceptor.Attributes, err error) {
 	n, a, err = t.Read(b)
@@ -242,3 +330,91 @@ func (t *TrackRemote) setRtxSSRC(ssrc SSRC) {
 	defer t.mu.Unlock()
 	t.rtxSsrc = ssrc
 }
+
+// SetJitterBufferConfig configures the jitter buffer used by ReadSample.
+// The buffer is rebuilt, so samples pending in it are discarded.
+func (t *TrackRemote) SetJitterBufferConfig(config JitterBufferConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.jitterBufferConfig = config
+	t.sampleBuilder = nil
+}
+
+// ReadSample reads RTP packets from the track, reorders them and returns the
+// next complete media.Sample. The depacketizer is chosen from the negotiated
+// codec, ErrNoDepacketizerForCodec is returned if the codec isn't supported.
+// ReadSample must not be mixed with Read or ReadRTP. The CaptureTime and
+// Orientation of the sample are set if the remote sent them with
+// abs-capture-time and CVO.
+func (t *TrackRemote) ReadSample() (*media.Sample, error) {
+	for {
+		t.mu.RLock()
+		builder := t.sampleBuilder
+		t.mu.RUnlock()
+
+		if builder != nil {
+			if sample := builder.Pop(); sample != nil {
+				extensions := t.sampleExtensions.get(sample.PacketTimestamp)
+				sample.CaptureTime = extensions.captureTime
+				sample.Orientation = extensions.orientation
+				sample.CSRC = extensions.csrc
+
+				return sample, nil
+			}
+		}
+
+		packet, attributes, err := t.ReadRTP()
+		if err != nil {
+			return nil, err
+		}
+		if captureTime, ok := attributes.Get(AttributeAbsCaptureTime).(time.Time); ok {
+			t.sampleExtensions.entry(packet.Timestamp).captureTime = captureTime
+		}
+		if orientation, ok := attributes.Get(AttributeVideoOrientation).(media.VideoOrientation); ok {
+			t.sampleExtensions.entry(packet.Timestamp).orientation = &orientation
+		}
+		if len(packet.CSRC) != 0 {
+			t.sampleExtensions.entry(packet.Timestamp).csrc = packet.CSRC
+		}
+
+		if builder, err = t.getSampleBuilder(); err != nil {
+			return nil, err
+		}
+		builder.Push(packet)
+	}
+}
+
+// getSampleBuilder returns the SampleBuilder of the current codec, creating
+// a new one if the codec changed.
+func (t *TrackRemote) getSampleBuilder() (*samplebuilder.SampleBuilder, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.sampleBuilder != nil && strings.EqualFold(t.sampleBuilderCodec, t.codec.MimeType) {
+		return t.sampleBuilder, nil
+	}
+
+	depacketizer, err := depacketizerForCodec(t.codec.RTPCodecCapability)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLate := t.jitterBufferConfig.MaxLate
+	if maxLate == 0 {
+		maxLate = defaultJitterBufferMaxLateVideo
+		if t.kind == RTPCodecTypeAudio {
+			maxLate = defaultJitterBufferMaxLateAudio
+		}
+	}
+
+	var opts []samplebuilder.Option
+	if t.jitterBufferConfig.MaxLatency != 0 {
+		opts = append(opts, samplebuilder.WithMaxLatency(t.jitterBufferConfig.MaxLatency))
+	}
+
+	t.sampleBuilder = samplebuilder.New(maxLate, depacketizer, t.codec.ClockRate, opts...)
+	t.sampleBuilderCodec = t.codec.MimeType
+
+	return t.sampleBuilder, nil
+}
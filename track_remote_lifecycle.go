@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// trackRemoteWatchdogChecks is how many times per timeout the watchdog
+// checks the arrivals of a track.
+const trackRemoteWatchdogChecks = 4
+
+// TrackEndReason tells why a TrackRemote ended.
+type TrackEndReason int
+
+const (
+	// TrackEndReasonUnknown is the enum's zero-value.
+	TrackEndReasonUnknown TrackEndReason = iota
+
+	// TrackEndReasonBye indicates the remote sender sent a RTCP BYE for the
+	// SSRC of the track.
+	TrackEndReasonBye
+
+	// TrackEndReasonStopped indicates the RTPReceiver of the track was
+	// stopped, by RTPTransceiver.Stop, a renegotiation removing the track
+	// or closing the PeerConnection.
+	TrackEndReasonStopped
+
+	// TrackEndReasonTimeout indicates no RTP arrived for the track for the
+	// timeout set with SettingEngine.SetTrackRemoteTimeouts.
+	TrackEndReasonTimeout
+)
+
+func (r TrackEndReason) String() string {
+	switch r {
+	case TrackEndReasonBye:
+		return "bye"
+	case TrackEndReasonStopped:
+		return "stopped"
+	case TrackEndReasonTimeout:
+		return "timeout"
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// OnEnded sets an event handler which is called once when the track ends,
+// after which no more media is read from it. The handler is called from its
+// own goroutine. It is only called for RTCP BYE if RTCP is read from the
+// RTPReceiver of the track.
+func (t *TrackRemote) OnEnded(f func(TrackEndReason)) {
+	t.mu.Lock()
+	t.onEndedHandler = f
+	t.mu.Unlock()
+
+	t.startWatchdog()
+}
+
+// OnMute sets an event handler which is called when no RTP arrived for the
+// track for the mute timeout of SettingEngine.SetTrackRemoteTimeouts. The
+// arrivals are tracked when the packets are received from the network, not
+// when they are read from the track.
+func (t *TrackRemote) OnMute(f func()) {
+	t.mu.Lock()
+	t.onMuteHandler = f
+	t.mu.Unlock()
+
+	t.startWatchdog()
+}
+
+// OnUnmute sets an event handler which is called when RTP arrives again for
+// a muted track.
+func (t *TrackRemote) OnUnmute(f func()) {
+	t.mu.Lock()
+	t.onUnmuteHandler = f
+	t.mu.Unlock()
+
+	t.startWatchdog()
+}
+
+// Muted returns whether no RTP arrived for the track for the mute timeout.
+// It is only tracked once OnMute, OnUnmute or OnEnded is set.
+func (t *TrackRemote) Muted() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.muted
+}
+
+// startWatchdog starts watching the arrivals of the track, the first time a
+// lifecycle handler is set.
+func (t *TrackRemote) startWatchdog() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.watchdogStarted || t.receiver == nil {
+		return
+	}
+	t.watchdogStarted = true
+
+	muteTimeout, endTimeout := t.receiver.api.settingEngine.getTrackRemoteTimeouts()
+	if muteTimeout == 0 && endTimeout == 0 {
+		return
+	}
+
+	go t.watch(muteTimeout, endTimeout)
+}
+
+func (t *TrackRemote) watch(muteTimeout, endTimeout time.Duration) {
+	interval := muteTimeout
+	if interval == 0 || (endTimeout != 0 && endTimeout < interval) {
+		interval = endTimeout
+	}
+	ticker := t.receiver.api.settingEngine.newTicker(interval / trackRemoteWatchdogChecks)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.Ch():
+		case <-t.endedCh:
+			return
+		case <-t.receiver.closed:
+			return
+		}
+
+		transport := t.receiver.Transport()
+		if transport == nil {
+			continue
+		}
+
+		lastArrival, ok := transport.arrivals.lastArrival(t.SSRC())
+		if !ok {
+			continue
+		}
+
+		idle := time.Since(lastArrival)
+		if endTimeout != 0 && idle >= endTimeout {
+			t.end(TrackEndReasonTimeout)
+
+			return
+		}
+		if muteTimeout != 0 {
+			t.setMuted(idle >= muteTimeout)
+		}
+	}
+}
+
+// setMuted calls the mute or unmute handler when the state changes.
+func (t *TrackRemote) setMuted(muted bool) {
+	t.mu.Lock()
+	if t.muted == muted {
+		t.mu.Unlock()
+
+		return
+	}
+	t.muted = muted
+	handler := t.onUnmuteHandler
+	if muted {
+		handler = t.onMuteHandler
+	}
+	t.mu.Unlock()
+
+	if handler != nil {
+		handler()
+	}
+}
+
+// handleGoodbye ends the track if pkts contain a RTCP BYE for ssrc.
+func (t *TrackRemote) handleGoodbye(pkts []rtcp.Packet, ssrc SSRC) {
+	for _, pkt := range pkts {
+		goodbye, ok := pkt.(*rtcp.Goodbye)
+		if !ok {
+			continue
+		}
+
+		for _, source := range goodbye.Sources {
+			if SSRC(source) == ssrc {
+				t.end(TrackEndReasonBye)
+
+				return
+			}
+		}
+	}
+}
+
+// end marks the track as ended and calls the OnEnded handler, only the
+// first call has an effect.
+func (t *TrackRemote) end(reason TrackEndReason) {
+	t.mu.Lock()
+	if t.endReason != TrackEndReasonUnknown {
+		t.mu.Unlock()
+
+		return
+	}
+	t.endReason = reason
+	if t.endedCh != nil {
+		close(t.endedCh)
+	}
+	handler := t.onEndedHandler
+	t.mu.Unlock()
+
+	if handler != nil {
+		go handler(reason)
+	}
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackEndReason_String(t *testing.T) {
+	testCases := []struct {
+		reason         TrackEndReason
+		expectedString string
+	}{
+		{TrackEndReasonUnknown, ErrUnknownType.Error()},
+		{TrackEndReasonBye, "bye"},
+		{TrackEndReasonStopped, "stopped"},
+		{TrackEndReasonTimeout, "timeout"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expectedString, testCase.reason.String(), "testCase: %d %v", i, testCase)
+	}
+}
+
+func TestTrackRemote_HandleGoodbye(t *testing.T) {
+	track := newTrackRemote(RTPCodecTypeVideo, 1234, 0, "", nil)
+	ended := make(chan TrackEndReason, 1)
+	track.OnEnded(func(reason TrackEndReason) { ended <- reason })
+
+	track.handleGoodbye([]rtcp.Packet{&rtcp.Goodbye{Sources: []uint32{5678}}}, 1234)
+	select {
+	case <-ended:
+		assert.Fail(t, "BYE of another SSRC ended the track")
+	default:
+	}
+
+	track.handleGoodbye([]rtcp.Packet{&rtcp.Goodbye{Sources: []uint32{5678, 1234}}}, 1234)
+	assert.Equal(t, TrackEndReasonBye, <-ended)
+
+	// Only the first end is reported
+	track.end(TrackEndReasonStopped)
+	select {
+	case <-ended:
+		assert.Fail(t, "OnEnded called twice")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestTrackRemote_Lifecycle(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetTrackRemoteTimeouts(200*time.Millisecond, 0)
+	api := NewAPI(WithSettingEngine(settingEngine))
+	assert.NoError(t, api.mediaEngine.RegisterDefaultCodecs())
+
+	pcOffer, pcAnswer, err := api.newPair(Configuration{})
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	_, err = pcOffer.AddTrack(track)
+	assert.NoError(t, err)
+
+	// Handlers don't block the watchdog once the test stopped waiting on them
+	muted, unmuted := make(chan bool, 1), make(chan struct{}, 1)
+	ended := make(chan TrackEndReason, 1)
+	trackReceived := make(chan struct{})
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		trackRemote.OnMute(func() {
+			select {
+			case muted <- trackRemote.Muted():
+			default:
+			}
+		})
+		trackRemote.OnUnmute(func() {
+			select {
+			case unmuted <- struct{}{}:
+			default:
+			}
+		})
+		trackRemote.OnEnded(func(reason TrackEndReason) { ended <- reason })
+		close(trackReceived)
+
+		for {
+			if _, _, readErr := trackRemote.ReadRTP(); readErr != nil {
+				return
+			}
+		}
+	})
+
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	var writing atomic.Bool
+	writing.Store(true)
+	done := make(chan struct{})
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if writing.Load() {
+					assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Second}))
+				}
+			}
+		}
+	}()
+
+	<-trackReceived
+
+	// The track is muted once the sender stops writing, and unmuted once it
+	// writes again
+	writing.Store(false)
+	assert.True(t, <-muted)
+	writing.Store(true)
+	<-unmuted
+
+	close(done)
+	<-writerDone
+
+	closePairNow(t, pcOffer, pcAnswer)
+	assert.Equal(t, TrackEndReasonStopped, <-ended)
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media/jitterbuffer"
+)
+
+// trackPlayout is the adaptive jitter buffer of TrackRemote.ReadPlayout, a
+// goroutine reads the track into it.
+type trackPlayout struct {
+	mu     sync.Mutex
+	buffer *jitterbuffer.JitterBuffer
+	err    error
+
+	pushed chan struct{}
+}
+
+// ReadPlayout reads RTP packets from the track through an adaptive jitter
+// buffer, and returns them in sequence at their playout time. The playout
+// delay follows the network jitter within the MinPlayoutDelay and
+// MaxPlayoutDelay of the JitterBufferConfig, packets that arrive after their
+// playout time are dropped and counted in PlayoutStats. It is meant for audio
+// tracks that are recorded or forwarded without being decoded.
+// ReadPlayout must not be mixed with Read, ReadRTP or ReadSample.
+func (t *TrackRemote) ReadPlayout() (*rtp.Packet, error) {
+	playout := t.getPlayout()
+
+	for {
+		playout.mu.Lock()
+		now := time.Now()
+		packet := playout.buffer.Pop(now)
+		next, hasNext := playout.buffer.NextPlayout()
+		err := playout.err
+		playout.mu.Unlock()
+
+		switch {
+		case packet != nil:
+			return packet, nil
+		case !hasNext && err != nil:
+			return nil, err
+		case !hasNext:
+			<-playout.pushed
+		default:
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-playout.pushed:
+			case <-timer.C:
+			}
+			timer.Stop()
+		}
+	}
+}
+
+// PlayoutStats returns the delay and packet counters of the jitter buffer of
+// ReadPlayout, it returns false if ReadPlayout wasn't called.
+func (t *TrackRemote) PlayoutStats() (jitterbuffer.Stats, bool) {
+	t.mu.RLock()
+	playout := t.playout
+	t.mu.RUnlock()
+
+	if playout == nil {
+		return jitterbuffer.Stats{}, false
+	}
+
+	playout.mu.Lock()
+	defer playout.mu.Unlock()
+
+	return playout.buffer.Stats(), true
+}
+
+// getPlayout returns the trackPlayout of the track, starting to read the
+// track into it on the first call.
+func (t *TrackRemote) getPlayout() *trackPlayout {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.playout != nil {
+		return t.playout
+	}
+
+	var opts []jitterbuffer.Option
+	if t.jitterBufferConfig.MinPlayoutDelay != 0 {
+		opts = append(opts, jitterbuffer.WithMinDelay(t.jitterBufferConfig.MinPlayoutDelay))
+	}
+	if t.jitterBufferConfig.MaxPlayoutDelay != 0 {
+		opts = append(opts, jitterbuffer.WithMaxDelay(t.jitterBufferConfig.MaxPlayoutDelay))
+	}
+
+	t.playout = &trackPlayout{
+		buffer: jitterbuffer.New(t.codec.ClockRate, opts...),
+		pushed: make(chan struct{}, 1),
+	}
+	go t.readPlayout(t.playout)
+
+	return t.playout
+}
+
+func (t *TrackRemote) readPlayout(playout *trackPlayout) {
+	b := make([]byte, t.receiver.api.settingEngine.getReceiveMTU())
+	for {
+		n, attributes, err := t.Read(b)
+
+		arrival, ok := attributes.Get(AttributeArrivalTime).(time.Time)
+		if !ok {
+			arrival = time.Now()
+		}
+
+		packet := &rtp.Packet{}
+		if err == nil {
+			if unmarshalErr := packet.Unmarshal(append([]byte{}, b[:n]...)); unmarshalErr != nil {
+				continue
+			}
+		}
+
+		playout.mu.Lock()
+		if err != nil {
+			playout.err = err
+		} else {
+			playout.buffer.Push(packet, arrival)
+		}
+		playout.mu.Unlock()
+
+		select {
+		case playout.pushed <- struct{}{}:
+		default:
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
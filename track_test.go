@@ -5,3 +5,133 @@
 // +build !js
 
 package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackRemote_ReadSample(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	sender, receiver, wan := createVNetPair(t, &interceptor.Registry{})
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	_, err = sender.AddTrack(track)
+	assert.NoError(t, err)
+
+	samplesRead, samplesReadCancel := context.WithCancel(context.Background())
+	receiver.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		trackRemote.SetJitterBufferConfig(JitterBufferConfig{MaxLate: 10})
+
+		for i := 0; i < 2; i++ {
+			sample, readErr := trackRemote.ReadSample()
+			assert.NoError(t, readErr)
+			assert.Equal(t, []byte{0xAA, 0xBB}, sample.Data)
+			// The RTP timestamps are truncated to the 90kHz clock
+			assert.InDelta(t, time.Second/30, sample.Duration, float64(time.Millisecond))
+		}
+
+		samplesReadCancel()
+	})
+
+	peerConnectionsConnected := untilConnectionState(PeerConnectionStateConnected, sender, receiver)
+
+	assert.NoError(t, signalPair(sender, receiver))
+
+	peerConnectionsConnected.Wait()
+
+	func() {
+		ticker := time.NewTicker(time.Second / 30)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-samplesRead.Done():
+				return
+			case <-ticker.C:
+				assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0xAA, 0xBB}, Duration: time.Second / 30}))
+			}
+		}
+	}()
+
+	assert.NoError(t, wan.Stop())
+	closePairNow(t, sender, receiver)
+}
+
+func TestTrackRemote_ReadPlayout(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	sender, receiver, wan := createVNetPair(t, &interceptor.Registry{})
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion")
+	assert.NoError(t, err)
+
+	_, err = sender.AddTrack(track)
+	assert.NoError(t, err)
+
+	packetsRead, packetsReadCancel := context.WithCancel(context.Background())
+	receiver.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		_, ok := trackRemote.PlayoutStats()
+		assert.False(t, ok)
+
+		trackRemote.SetJitterBufferConfig(JitterBufferConfig{MinPlayoutDelay: 40 * time.Millisecond})
+
+		var lastSequenceNumber uint16
+		for i := 0; i < 5; i++ {
+			packet, readErr := trackRemote.ReadPlayout()
+			assert.NoError(t, readErr)
+			assert.Equal(t, []byte{0xAA, 0xBB}, packet.Payload)
+			if i != 0 {
+				assert.Equal(t, lastSequenceNumber+1, packet.SequenceNumber)
+			}
+			lastSequenceNumber = packet.SequenceNumber
+		}
+
+		stats, ok := trackRemote.PlayoutStats()
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, stats.Delay, 40*time.Millisecond)
+		assert.Equal(t, uint64(5), stats.PacketsPlayed)
+
+		packetsReadCancel()
+	})
+
+	peerConnectionsConnected := untilConnectionState(PeerConnectionStateConnected, sender, receiver)
+
+	assert.NoError(t, signalPair(sender, receiver))
+
+	peerConnectionsConnected.Wait()
+
+	func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-packetsRead.Done():
+				return
+			case <-ticker.C:
+				assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0xAA, 0xBB}, Duration: 20 * time.Millisecond}))
+			}
+		}
+	}()
+
+	assert.NoError(t, wan.Stop())
+	closePairNow(t, sender, receiver)
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// transportFeedbackHistorySize is how many sent packets are remembered, it
+// covers the packets sent between two transport-wide feedback reports.
+const transportFeedbackHistorySize = 1024
+
+// transportFeedbackReferenceTimeUnit is the unit of the reference time of a
+// transport-wide congestion control feedback report.
+const transportFeedbackReferenceTimeUnit = 64 * time.Millisecond
+
+// transportFeedback computes the loss and queuing delay of the packets sent
+// on a DTLSTransport from the transport-wide congestion control feedback the
+// remote peer sends about them. The feedback covers the packets of every
+// encoding, so the results are passed to the handlers of their SSRCs.
+type transportFeedback struct {
+	mu   sync.Mutex
+	sent [transportFeedbackHistorySize]transportFeedbackPacket
+
+	// baseDelay is the lowest difference between the arrival and send time
+	// of a packet, the delay above it is the time spent in queues.
+	baseDelay    time.Duration
+	hasBaseDelay bool
+
+	handlers map[SSRC]func(fractionLost float64, queuingDelay time.Duration)
+}
+
+type transportFeedbackPacket struct {
+	ssrc           SSRC
+	sequenceNumber uint16
+	sent           time.Time
+}
+
+type transportFeedbackSummary struct {
+	handler        func(fractionLost float64, queuingDelay time.Duration)
+	received, lost int
+
+	// The delays of the packets are summed relative to the first one, the
+	// differences of unrelated clocks would overflow.
+	delays     int
+	firstDelay time.Duration
+	totalDelay time.Duration
+}
+
+// onFeedback sets the handler invoked with the results for the packets of
+// ssrc, nil removes it.
+func (f *transportFeedback) onFeedback(ssrc SSRC, handler func(fractionLost float64, queuingDelay time.Duration)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if handler == nil {
+		delete(f.handlers, ssrc)
+
+		return
+	}
+
+	if f.handlers == nil {
+		f.handlers = map[SSRC]func(float64, time.Duration){}
+	}
+	f.handlers[ssrc] = handler
+}
+
+// sentPacket records the send time of the packet of ssrc carrying the
+// transport-wide sequence number.
+func (f *transportFeedback) sentPacket(ssrc SSRC, sequenceNumber uint16, now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent[sequenceNumber%transportFeedbackHistorySize] = transportFeedbackPacket{
+		ssrc:           ssrc,
+		sequenceNumber: sequenceNumber,
+		sent:           now,
+	}
+}
+
+// handle invokes the handlers of the SSRCs whose packets feedback reports on.
+func (f *transportFeedback) handle(feedback *rtcp.TransportLayerCC) {
+	f.mu.Lock()
+
+	summaries := map[SSRC]*transportFeedbackSummary{}
+	arrival := time.Duration(feedback.ReferenceTime) * transportFeedbackReferenceTimeUnit
+	deltas := feedback.RecvDeltas
+	sequenceNumber := feedback.BaseSequenceNumber
+	for _, status := range transportFeedbackStatuses(feedback) {
+		current := sequenceNumber
+		sequenceNumber++
+
+		hasDelta := status == rtcp.TypeTCCPacketReceivedSmallDelta || status == rtcp.TypeTCCPacketReceivedLargeDelta
+		if hasDelta {
+			if len(deltas) == 0 {
+				break
+			}
+			arrival += time.Duration(deltas[0].Delta) * time.Microsecond
+			deltas = deltas[1:]
+		}
+
+		packet := f.sent[current%transportFeedbackHistorySize]
+		if packet.sent.IsZero() || packet.sequenceNumber != current {
+			continue
+		}
+
+		summary, ok := summaries[packet.ssrc]
+		if !ok {
+			handler, hasHandler := f.handlers[packet.ssrc]
+			if !hasHandler {
+				continue
+			}
+			summary = &transportFeedbackSummary{handler: handler}
+			summaries[packet.ssrc] = summary
+		}
+
+		if status == rtcp.TypeTCCPacketNotReceived {
+			summary.lost++
+
+			continue
+		}
+		summary.received++
+
+		if hasDelta {
+			// The clocks of both peers are unrelated, only the changes of
+			// the difference between them are meaningful.
+			delay := arrival - time.Duration(packet.sent.UnixNano())
+			if !f.hasBaseDelay || delay < f.baseDelay {
+				f.baseDelay = delay
+				f.hasBaseDelay = true
+			}
+			if summary.delays == 0 {
+				summary.firstDelay = delay
+			}
+			summary.delays++
+			summary.totalDelay += delay - summary.firstDelay
+		}
+	}
+
+	baseDelay := f.baseDelay
+	f.mu.Unlock()
+
+	for _, summary := range summaries {
+		var queuingDelay time.Duration
+		if summary.delays != 0 {
+			queuingDelay = summary.firstDelay + summary.totalDelay/time.Duration(summary.delays) - baseDelay
+		}
+		summary.handler(float64(summary.lost)/float64(summary.lost+summary.received), queuingDelay)
+	}
+}
+
+// transportFeedbackStatuses expands the packet status chunks of feedback to
+// one status per reported packet.
+func transportFeedbackStatuses(feedback *rtcp.TransportLayerCC) []uint16 {
+	statuses := make([]uint16, 0, feedback.PacketStatusCount)
+	for _, chunk := range feedback.PacketChunks {
+		switch chunk := chunk.(type) {
+		case *rtcp.RunLengthChunk:
+			for i := uint16(0); i < chunk.RunLength; i++ {
+				statuses = append(statuses, chunk.PacketStatusSymbol)
+			}
+		case *rtcp.StatusVectorChunk:
+			statuses = append(statuses, chunk.SymbolList...)
+		}
+	}
+
+	if len(statuses) > int(feedback.PacketStatusCount) {
+		statuses = statuses[:feedback.PacketStatusCount]
+	}
+
+	return statuses
+}
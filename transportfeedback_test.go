@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransportFeedback(t *testing.T) {
+	type result struct {
+		fractionLost float64
+		queuingDelay time.Duration
+	}
+	results := map[SSRC]result{}
+
+	var feedback transportFeedback
+	for _, ssrc := range []SSRC{1, 2} {
+		ssrc := ssrc
+		feedback.onFeedback(ssrc, func(fractionLost float64, queuingDelay time.Duration) {
+			results[ssrc] = result{fractionLost, queuingDelay}
+		})
+	}
+
+	// 65534 and 65535 belong to SSRC 1, 0 to 2 and 1 is unknown
+	now := time.Now()
+	feedback.sentPacket(1, 65534, now)
+	feedback.sentPacket(1, 65535, now.Add(10*time.Millisecond))
+	feedback.sentPacket(2, 0, now.Add(20*time.Millisecond))
+
+	feedback.handle(&rtcp.TransportLayerCC{
+		BaseSequenceNumber: 65534,
+		PacketStatusCount:  4,
+		PacketChunks: []rtcp.PacketStatusChunk{
+			&rtcp.StatusVectorChunk{
+				SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit,
+				SymbolList: []uint16{
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketReceivedLargeDelta,
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketNotReceived,
+				},
+			},
+		},
+		// 65534 is the fastest packet, 0 arrives 30ms later than it
+		RecvDeltas: []*rtcp.RecvDelta{
+			{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 0},
+			{Type: rtcp.TypeTCCPacketReceivedLargeDelta, Delta: 50_000},
+			{Type: rtcp.TypeTCCPacketReceivedSmallDelta, Delta: 1_000},
+		},
+	})
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, 0.5, results[1].fractionLost)
+	assert.Equal(t, time.Duration(0), results[1].queuingDelay)
+	assert.Equal(t, 0.0, results[2].fractionLost)
+	assert.Equal(t, 30*time.Millisecond, results[2].queuingDelay)
+
+	// Packets without a handler are ignored
+	feedback.onFeedback(2, nil)
+	delete(results, 2)
+	feedback.handle(&rtcp.TransportLayerCC{
+		BaseSequenceNumber: 0,
+		PacketStatusCount:  1,
+		PacketChunks: []rtcp.PacketStatusChunk{
+			&rtcp.RunLengthChunk{PacketStatusSymbol: rtcp.TypeTCCPacketNotReceived, RunLength: 1},
+		},
+	})
+	assert.NotContains(t, results, SSRC(2))
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+const (
+	// VideoOrientationURI is the URI of the coordination of video orientation
+	// (CVO) RTP header extension of 3GPP TS 26.114, which carries the
+	// rotation of a video frame. Register it with
+	// MediaEngine.RegisterHeaderExtension to negotiate it.
+	VideoOrientationURI = "urn:3gpp:video-orientation"
+
+	// AttributeVideoOrientation is the interceptor attribute holding the
+	// media.VideoOrientation of a RTP packet, it is set by
+	// TrackRemote.ReadRTP if the extension was negotiated and present.
+	AttributeVideoOrientation = "webrtc.videoOrientation"
+)
+
+const (
+	videoOrientationCameraBit = 0x08
+	videoOrientationFlipBit   = 0x04
+	videoOrientationRotation  = 0x03
+)
+
+// videoOrientationPayload returns the one byte extension payload of
+// orientation, the rotation is rounded down to a multiple of 90 degrees.
+func videoOrientationPayload(orientation media.VideoOrientation) []byte {
+	payload := byte(orientation.Rotation/90) & videoOrientationRotation //nolint:gosec // G115
+	if orientation.Flip {
+		payload |= videoOrientationFlipBit
+	}
+	if orientation.BackCamera {
+		payload |= videoOrientationCameraBit
+	}
+
+	return []byte{payload}
+}
+
+// readVideoOrientation returns the orientation of the packet if it carries
+// the extension with id.
+func readVideoOrientation(header *rtp.Header, id uint8) (media.VideoOrientation, bool) {
+	if id == 0 {
+		return media.VideoOrientation{}, false
+	}
+
+	payload := header.GetExtension(id)
+	if len(payload) == 0 {
+		return media.VideoOrientation{}, false
+	}
+
+	return media.VideoOrientation{
+		Rotation:   uint16(payload[0]&videoOrientationRotation) * 90,
+		Flip:       payload[0]&videoOrientationFlipBit != 0,
+		BackCamera: payload[0]&videoOrientationCameraBit != 0,
+	}, true
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoOrientation_Payload(t *testing.T) {
+	for _, orientation := range []media.VideoOrientation{
+		{},
+		{Rotation: 90},
+		{Rotation: 180, Flip: true},
+		{Rotation: 270, BackCamera: true},
+		{Rotation: 90, Flip: true, BackCamera: true},
+	} {
+		header := &rtp.Header{}
+		require.NoError(t, header.SetExtension(4, videoOrientationPayload(orientation)))
+
+		read, ok := readVideoOrientation(header, 4)
+		assert.True(t, ok)
+		assert.Equal(t, orientation, read)
+
+		_, ok = readVideoOrientation(header, 0)
+		assert.False(t, ok)
+		_, ok = readVideoOrientation(header, 5)
+		assert.False(t, ok)
+	}
+
+	payload := videoOrientationPayload(media.VideoOrientation{Rotation: 90, BackCamera: true, Flip: true})
+	assert.Equal(t, []byte{0x0D}, payload)
+}
+
+func TestVideoOrientation_WriteSample(t *testing.T) {
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8, ClockRate: 90000}, "video", "pion")
+	require.NoError(t, err)
+
+	writer := &headerTestWriter{}
+	_, err = track.Bind(&baseTrackLocalContext{
+		params: RTPParameters{
+			HeaderExtensions: []RTPHeaderExtensionParameter{
+				{URI: AbsCaptureTimeURI, ID: 3},
+				{URI: VideoOrientationURI, ID: 4},
+			},
+			Codecs: []RTPCodecParameters{{RTPCodecCapability: track.Codec(), PayloadType: 96}},
+		},
+		ssrc:        1,
+		writeStream: writer,
+	})
+	require.NoError(t, err)
+
+	orientation := &media.VideoOrientation{Rotation: 270}
+	assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Second}))
+	assert.NoError(t, track.WriteSample(media.Sample{
+		Data: []byte{0x00}, Duration: time.Second, Orientation: orientation, CaptureTime: time.Unix(1700000000, 0),
+	}))
+
+	require.Len(t, writer.headers, 2)
+	_, ok := readVideoOrientation(&writer.headers[0], 4)
+	assert.False(t, ok)
+
+	read, ok := readVideoOrientation(&writer.headers[1], 4)
+	assert.True(t, ok)
+	assert.Equal(t, *orientation, read)
+	_, ok = readAbsCaptureTime(&writer.headers[1], 3)
+	assert.True(t, ok)
+}